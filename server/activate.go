@@ -8,14 +8,11 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"runtime"
 
 	"github.com/mattermost/mattermost-plugin-calls/server/cluster"
 	"github.com/mattermost/mattermost-plugin-calls/server/enterprise"
 	"github.com/mattermost/mattermost-plugin-calls/server/license"
 
-	"github.com/mattermost/rtcd/service/rtc"
-
 	"github.com/mattermost/mattermost/server/public/model"
 	"github.com/mattermost/mattermost/server/public/shared/i18n"
 )
@@ -150,15 +147,28 @@ func (p *Plugin) OnActivate() (retErr error) {
 	// rtcServer and rtcdManager are mutually exclusive throughout the entire lifetime of the plugin.
 	// Which one is used is decided here, during activation.
 	// We first check if RTCD is configured and allowed by the license. If so
-	// we try to initialize its connection and fail to start the plugin if that errors.
+	// we try to initialize its connection and fail to start the plugin if that errors,
+	// unless falling back to the embedded RTC server is enabled.
+	var rtcdManager *rtcdClientManager
 	if rtcdURL := cfg.getRTCDURL(); rtcdURL != "" && p.licenseChecker.RTCDAllowed() {
-		rtcdManager, err := p.newRTCDClientManager(rtcdURL)
+		rtcdManager, err = p.newRTCDClientManager(rtcdURL)
 		if err != nil {
 			err = fmt.Errorf("failed to create rtcd manager: %w", err)
-			p.LogError(err.Error())
-			return err
+			if fallbackErr := p.canFallbackToEmbeddedRTC(cfg); fallbackErr != nil {
+				p.LogError(err.Error(), "fallbackErr", fallbackErr.Error())
+				return err
+			}
+			p.LogWarn("RTCD service is unreachable, falling back to the integrated RTC server. Calls will be hosted by this instance until the plugin is restarted with the RTCD service available.",
+				"err", err.Error())
+
+			// State cleanup was skipped earlier since RTCD is configured.
+			if err := p.cleanUpState(); err != nil {
+				p.LogError("failed to cleanup state", "err", err.Error())
+			}
 		}
+	}
 
+	if rtcdManager != nil {
 		p.LogDebug("rtcd client manager initialized successfully")
 
 		p.rtcdManager = rtcdManager
@@ -167,32 +177,14 @@ func (p *Plugin) OnActivate() (retErr error) {
 			p.LogError("failed to cleanup state", "err", err.Error())
 		}
 	} else {
-		rtcServerConfig := rtc.ServerConfig{
-			ICEAddressUDP:   rtc.ICEAddress(cfg.UDPServerAddress),
-			ICEAddressTCP:   rtc.ICEAddress(cfg.TCPServerAddress),
-			ICEPortUDP:      *cfg.UDPServerPort,
-			ICEPortTCP:      *cfg.TCPServerPort,
-			ICEHostOverride: cfg.ICEHostOverride,
-			ICEServers:      rtc.ICEServers(cfg.getICEServers(false)),
-			TURNConfig: rtc.TURNConfig{
-				CredentialsExpirationMinutes: *cfg.TURNCredentialsExpirationMinutes,
-			},
-			EnableIPv6:      *cfg.EnableIPv6,
-			UDPSocketsCount: runtime.NumCPU(),
-		}
-		if *cfg.ServerSideTURN {
-			rtcServerConfig.TURNConfig.StaticAuthSecret = cfg.TURNStaticAuthSecret
-		}
-		if cfg.ICEHostPortOverride != nil {
-			rtcServerConfig.ICEHostPortOverride = rtc.ICEHostPortOverride(fmt.Sprintf("%d", *cfg.ICEHostPortOverride))
-		}
-		rtcServer, err := rtc.NewServer(rtcServerConfig, newLogger(p), p.metrics.RTCMetrics())
+		rtcServerConfig, err := p.newRTCServerConfig(cfg)
 		if err != nil {
 			p.LogError(err.Error())
 			return err
 		}
 
-		if err := rtcServer.Start(); err != nil {
+		rtcServer, err := p.startRTCServer(rtcServerConfig)
+		if err != nil {
 			p.LogError(err.Error())
 			return err
 		}
@@ -203,30 +195,69 @@ func (p *Plugin) OnActivate() (retErr error) {
 		// Hence, in that case this field should be left empty.
 		p.nodeID = status.ClusterId
 
+		p.rtcServerMut.Lock()
 		p.rtcServer = rtcServer
+		p.rtcServerConfig = rtcServerConfig
+		p.rtcServerMut.Unlock()
 
 		// The wsWriter routine is only necessary when running the embedded RTC server since
 		// it's a listener on rtcServer.ReceiveCh used to forward RTC messages (e.g. signaling)
 		// back to the client through the WS connection. The RTCD handler has a separate way to
 		// do this (see clientReader method).
-		go p.wsWriter()
+		go p.wsWriter(rtcServer)
+
+		go p.cpuPressureMonitor()
 	}
 
 	// Cluster events need to be handled regardless of whether the embedded RTC service or RTCD are in use.
 	go p.clusterEventsHandler()
 
+	go p.participantWebhookSender()
+
+	go p.callTimelineWriter()
+
+	p.dbWriteBufferFlusherWg.Add(1)
+	go p.dbWriteBufferFlusher()
+
+	go p.metricsPusher()
+
+	go p.licenseMonitor()
+
+	go p.recoverRecordingUploads()
+
+	// Failing over the host role is only needed in HA deployments where nodes
+	// can go away while their sessions are still part of a call.
+	if status.ClusterId != "" {
+		go p.hostFailoverChecker(status.ClusterId)
+	}
+
 	p.LogDebug("activated", "ClusterID", status.ClusterId)
 
 	return nil
 }
 
+// canFallbackToEmbeddedRTC returns an error if calls can't be handled by the
+// integrated RTC server when the RTCD service is unreachable. Falling back is
+// not supported in High Availability mode since nodes could end up hosting
+// calls inconsistently, some through RTCD and others locally.
+func (p *Plugin) canFallbackToEmbeddedRTC(cfg *configuration) error {
+	if !cfg.rtcdFallbackEnabled() {
+		return fmt.Errorf("fallback to the integrated RTC server is disabled")
+	}
+
+	if p.isHA() {
+		return fmt.Errorf("fallback to the integrated RTC server is not supported in High Availability mode")
+	}
+
+	return nil
+}
+
 func (p *Plugin) OnDeactivate() error {
 	p.LogDebug("deactivate")
-	close(p.stopCh)
 
-	if err := p.store.Close(); err != nil {
-		p.LogError(err.Error())
-	}
+	// Recordings being finalized need the store and the file store so they
+	// are waited on before anything gets torn down.
+	p.stopRecordingFinalizations()
 
 	if p.rtcdManager != nil {
 		if err := p.rtcdManager.Close(); err != nil {
@@ -234,8 +265,8 @@ func (p *Plugin) OnDeactivate() error {
 		}
 	}
 
-	if p.rtcServer != nil {
-		if err := p.rtcServer.Stop(); err != nil {
+	if rtcServer := p.getRTCServer(); rtcServer != nil {
+		if err := rtcServer.Stop(); err != nil {
 			p.LogError(err.Error())
 		}
 	}
@@ -256,5 +287,12 @@ func (p *Plugin) OnDeactivate() error {
 		}
 	}
 
+	p.stopDBWriteBuffer()
+
+	// The store is closed last since cleaning up state depends on it.
+	if err := p.store.Close(); err != nil {
+		p.LogError(err.Error())
+	}
+
 	return nil
 }
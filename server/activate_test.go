@@ -0,0 +1,55 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"testing"
+
+	pluginMocks "github.com/mattermost/mattermost-plugin-calls/server/mocks/github.com/mattermost/mattermost/server/public/plugin"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanFallbackToEmbeddedRTC(t *testing.T) {
+	setup := func(t *testing.T, fallback, ha bool) (*Plugin, *configuration) {
+		t.Helper()
+
+		mockAPI := &pluginMocks.MockAPI{}
+		p := &Plugin{
+			MattermostPlugin: plugin.MattermostPlugin{
+				API: mockAPI,
+			},
+		}
+
+		mockAPI.On("GetConfig").Return(&model.Config{
+			ClusterSettings: model.ClusterSettings{
+				Enable: model.NewPointer(ha),
+			},
+		})
+
+		cfg := &configuration{}
+		cfg.SetDefaults()
+		cfg.RTCDFallbackToEmbedded = model.NewPointer(fallback)
+
+		return p, cfg
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		p, cfg := setup(t, false, false)
+		require.EqualError(t, p.canFallbackToEmbeddedRTC(cfg), "fallback to the integrated RTC server is disabled")
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		p, cfg := setup(t, true, false)
+		require.NoError(t, p.canFallbackToEmbeddedRTC(cfg))
+	})
+
+	t.Run("high availability", func(t *testing.T) {
+		p, cfg := setup(t, true, true)
+		require.EqualError(t, p.canFallbackToEmbeddedRTC(cfg), "fallback to the integrated RTC server is not supported in High Availability mode")
+	})
+}
@@ -0,0 +1,85 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/mattermost/mattermost-plugin-calls/server/db"
+	"github.com/mattermost/mattermost-plugin-calls/server/public"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+func newActiveRecording(job db.ActiveRecordingJob) public.ActiveRecording {
+	status := public.ActiveRecordingStatusRecording
+	if job.StartAt == 0 {
+		status = public.ActiveRecordingStatusInitializing
+	} else if job.Props.PausedAt > 0 {
+		status = public.ActiveRecordingStatusPaused
+	}
+
+	return public.ActiveRecording{
+		JobID:        job.ID,
+		CallID:       job.CallID,
+		ChannelID:    job.ChannelID,
+		CreatorID:    job.CreatorID,
+		InitAt:       job.InitAt,
+		StartAt:      job.StartAt,
+		PrimaryJobID: job.Props.PrimaryJobID,
+		Status:       status,
+	}
+}
+
+// getActiveRecordings returns the recordings in progress across the whole
+// cluster. Since jobs are tracked in the store it doesn't matter which node
+// is hosting the calls.
+func (p *Plugin) getActiveRecordings() ([]public.ActiveRecording, error) {
+	jobs, err := p.store.GetActiveRecordingJobs(db.GetCallJobOpts{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active recording jobs: %w", err)
+	}
+
+	recordings := make([]public.ActiveRecording, 0, len(jobs))
+	for _, job := range jobs {
+		recordings = append(recordings, newActiveRecording(job))
+	}
+
+	return recordings, nil
+}
+
+func (p *Plugin) writeActiveRecordings(w http.ResponseWriter) {
+	recordings, err := p.getActiveRecordings()
+	if err != nil {
+		p.LogError(err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(recordings); err != nil {
+		p.LogError(err.Error())
+	}
+}
+
+// handleInterPluginGetActiveRecordings lets other plugins (e.g. compliance
+// dashboards) list the recordings in progress.
+func (p *Plugin) handleInterPluginGetActiveRecordings(w http.ResponseWriter, _ *http.Request) {
+	p.writeActiveRecordings(w)
+}
+
+// handleGetActiveRecordings lists the recordings in progress. Only system
+// admins are allowed to do so as it spans all the channels.
+func (p *Plugin) handleGetActiveRecordings(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("Mattermost-User-Id")
+
+	if !p.API.HasPermissionTo(userID, model.PermissionManageSystem) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	p.writeActiveRecordings(w)
+}
@@ -0,0 +1,192 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-calls/server/db"
+	"github.com/mattermost/mattermost-plugin-calls/server/public"
+
+	pluginMocks "github.com/mattermost/mattermost-plugin-calls/server/mocks/github.com/mattermost/mattermost/server/public/plugin"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+)
+
+func TestNewActiveRecording(t *testing.T) {
+	job := db.ActiveRecordingJob{
+		CallJob: public.CallJob{
+			ID:        model.NewId(),
+			CallID:    model.NewId(),
+			Type:      public.JobTypeRecording,
+			CreatorID: model.NewId(),
+			InitAt:    time.Now().UnixMilli(),
+		},
+		ChannelID: model.NewId(),
+	}
+
+	t.Run("initializing", func(t *testing.T) {
+		require.Equal(t, public.ActiveRecording{
+			JobID:     job.ID,
+			CallID:    job.CallID,
+			ChannelID: job.ChannelID,
+			CreatorID: job.CreatorID,
+			InitAt:    job.InitAt,
+			Status:    public.ActiveRecordingStatusInitializing,
+		}, newActiveRecording(job))
+	})
+
+	t.Run("recording", func(t *testing.T) {
+		job.StartAt = time.Now().UnixMilli()
+		job.Props.PrimaryJobID = model.NewId()
+		recording := newActiveRecording(job)
+		require.Equal(t, public.ActiveRecordingStatusRecording, recording.Status)
+		require.Equal(t, job.StartAt, recording.StartAt)
+		require.Equal(t, job.Props.PrimaryJobID, recording.PrimaryJobID)
+	})
+
+	t.Run("paused", func(t *testing.T) {
+		job.Props.PausedAt = time.Now().UnixMilli()
+		require.Equal(t, public.ActiveRecordingStatusPaused, newActiveRecording(job).Status)
+	})
+}
+
+func TestHandleInterPluginGetActiveRecordingsAuth(t *testing.T) {
+	mockAPI := &pluginMocks.MockAPI{}
+	defer mockAPI.AssertExpectations(t)
+
+	p := Plugin{
+		MattermostPlugin: plugin.MattermostPlugin{
+			API: mockAPI,
+		},
+		apiLimiters: map[string]*rate.Limiter{},
+	}
+
+	apiRouter := p.newAPIRouter()
+
+	t.Run("no auth", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/interplugin/recordings/active", nil)
+
+		apiRouter.ServeHTTP(w, r)
+
+		resp := w.Result()
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("user session", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/interplugin/recordings/active", nil)
+		r.Header.Set("Mattermost-User-Id", model.NewId())
+
+		apiRouter.ServeHTTP(w, r)
+
+		resp := w.Result()
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+}
+
+func TestHandleGetActiveRecordings(t *testing.T) {
+	store, tearDown := NewTestStore(t)
+	t.Cleanup(tearDown)
+
+	mockAPI := &pluginMocks.MockAPI{}
+	defer mockAPI.AssertExpectations(t)
+
+	p := Plugin{
+		MattermostPlugin: plugin.MattermostPlugin{
+			API: mockAPI,
+		},
+		apiLimiters: map[string]*rate.Limiter{},
+		store:       store,
+	}
+
+	apiRouter := p.newAPIRouter()
+
+	call := &public.Call{
+		ID:        model.NewId(),
+		CreateAt:  time.Now().UnixMilli(),
+		ChannelID: model.NewId(),
+		StartAt:   time.Now().UnixMilli(),
+		PostID:    model.NewId(),
+		ThreadID:  model.NewId(),
+		OwnerID:   model.NewId(),
+	}
+	err := store.CreateCall(call)
+	require.NoError(t, err)
+
+	job := &public.CallJob{
+		ID:        model.NewId(),
+		CallID:    call.ID,
+		Type:      public.JobTypeRecording,
+		CreatorID: call.OwnerID,
+		InitAt:    time.Now().UnixMilli(),
+		StartAt:   time.Now().UnixMilli(),
+	}
+	err = store.CreateCallJob(job)
+	require.NoError(t, err)
+
+	expected := []public.ActiveRecording{
+		{
+			JobID:     job.ID,
+			CallID:    call.ID,
+			ChannelID: call.ChannelID,
+			CreatorID: job.CreatorID,
+			InitAt:    job.InitAt,
+			StartAt:   job.StartAt,
+			Status:    public.ActiveRecordingStatusRecording,
+		},
+	}
+
+	get := func(path string, headers map[string]string) (int, []public.ActiveRecording) {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", path, nil)
+		for k, v := range headers {
+			r.Header.Set(k, v)
+		}
+		apiRouter.ServeHTTP(w, r)
+
+		var recordings []public.ActiveRecording
+		if w.Result().StatusCode == http.StatusOK {
+			require.NoError(t, json.NewDecoder(w.Result().Body).Decode(&recordings))
+		}
+		return w.Result().StatusCode, recordings
+	}
+
+	userID := model.NewId()
+
+	t.Run("requires system admin", func(t *testing.T) {
+		mockAPI.On("HasPermissionTo", userID, model.PermissionManageSystem).Return(false).Once()
+		code, _ := get("/recordings/active", map[string]string{"Mattermost-User-Id": userID})
+		require.Equal(t, http.StatusForbidden, code)
+	})
+
+	t.Run("system admin", func(t *testing.T) {
+		mockAPI.On("HasPermissionTo", userID, model.PermissionManageSystem).Return(true).Once()
+		code, recordings := get("/recordings/active", map[string]string{"Mattermost-User-Id": userID})
+		require.Equal(t, http.StatusOK, code)
+		require.Equal(t, expected, recordings)
+	})
+
+	t.Run("inter-plugin", func(t *testing.T) {
+		code, recordings := get("/interplugin/recordings/active", map[string]string{"Mattermost-Plugin-ID": "com.mattermost.compliance"})
+		require.Equal(t, http.StatusOK, code)
+		require.Equal(t, expected, recordings)
+	})
+
+	t.Run("unauthorized", func(t *testing.T) {
+		code, _ := get("/interplugin/recordings/active", nil)
+		require.Equal(t, http.StatusUnauthorized, code)
+	})
+}
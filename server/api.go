@@ -62,10 +62,17 @@ func (p *Plugin) handleGetCallChannelState(w http.ResponseWriter, r *http.Reques
 	}
 
 	if channel == nil {
-		cfg := p.getConfiguration()
+		var teamID string
+		var channelType model.ChannelType
+		if ch, appErr := p.API.GetChannel(channelID); appErr == nil {
+			teamID = ch.TeamId
+			channelType = ch.Type
+		} else {
+			p.LogWarn("failed to get channel", "channelID", channelID, "err", appErr.Error())
+		}
 		channel = &public.CallsChannel{
 			ChannelID: channelID,
-			Enabled:   cfg.DefaultEnabled != nil && *cfg.DefaultEnabled,
+			Enabled:   p.getConfiguration().defaultEnabledForChannel(teamID, channelType),
 		}
 	}
 
@@ -97,7 +104,7 @@ func (p *Plugin) handleGetCallChannelState(w http.ResponseWriter, r *http.Reques
 	data := map[string]any{}
 	data["channel_id"] = channel.ChannelID
 	data["enabled"] = channel.Enabled
-	data["call"] = cs.getClientState(p.getBotID(), userID)
+	data["call"] = p.getCallClientState(cs, userID)
 
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(data); err != nil {
@@ -129,6 +136,66 @@ func (p *Plugin) handleGetCallActive(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// callsChannelEnabledState is the resolved availability of calls in a
+// channel for the requesting user.
+type callsChannelEnabledState struct {
+	ChannelID string `json:"channel_id"`
+	Enabled   bool   `json:"enabled"`
+	// Reason explains why calls are not enabled, if so.
+	Reason string `json:"reason,omitempty"`
+	// Source is where the channel's setting is resolved from (channel, team
+	// or default).
+	Source string `json:"source"`
+	// MaxParticipants is the participant limit in effect, zero meaning
+	// unlimited.
+	MaxParticipants int `json:"max_participants"`
+}
+
+// handleGetCallsChannelEnabled returns whether the user can start or join
+// calls in the channel, so that clients and integrations can check it without
+// attempting to join.
+func (p *Plugin) handleGetCallsChannelEnabled(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("Mattermost-User-Id")
+	channelID := mux.Vars(r)["channel_id"]
+
+	if !p.API.HasPermissionToChannel(userID, channelID, model.PermissionReadChannel) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	channel, appErr := p.API.GetChannel(channelID)
+	if appErr != nil {
+		http.Error(w, appErr.Error(), appErr.StatusCode)
+		return
+	}
+
+	callsChannel, err := p.store.GetCallsChannel(channelID, db.GetCallsChannelOpts{})
+	if err != nil && !errors.Is(err, db.ErrNotFound) {
+		p.LogError(err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	cfg := p.getConfiguration()
+	callsEnabled, source := cfg.resolveCallsEnabled(callsChannel, channel.TeamId)
+
+	state := callsChannelEnabledState{
+		ChannelID:       channelID,
+		Enabled:         true,
+		Source:          source,
+		MaxParticipants: cfg.maxParticipantsForChannelType(channel.Type),
+	}
+	if err := p.userCanStartOrJoin(userID, callsEnabled, channel.Type); err != nil {
+		state.Enabled = false
+		state.Reason = err.Error()
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(state); err != nil {
+		p.LogError(err.Error())
+	}
+}
+
 func (p *Plugin) hasPermissionToChannel(cm *model.ChannelMember, perm *model.Permission) bool {
 	if cm == nil {
 		return false
@@ -210,7 +277,7 @@ func (p *Plugin) handleGetAllCallChannelStates(w http.ResponseWriter, r *http.Re
 				http.Error(w, err.Error(), http.StatusInternalServerError)
 				return
 			}
-			channelData["call"] = cs.getClientState(p.getBotID(), userID)
+			channelData["call"] = p.getCallClientState(cs, userID)
 			delete(callsMap, ch.ChannelID)
 		}
 
@@ -231,7 +298,7 @@ func (p *Plugin) handleGetAllCallChannelStates(w http.ResponseWriter, r *http.Re
 
 		data = append(data, map[string]any{
 			"channel_id": call.ChannelID,
-			"call":       cs.getClientState(p.getBotID(), userID),
+			"call":       p.getCallClientState(cs, userID),
 		})
 	}
 
@@ -299,25 +366,26 @@ func (p *Plugin) handleServeStandalone(w http.ResponseWriter, r *http.Request) {
 func (p *Plugin) permissionToEnableDisableChannel(userID, channelID string) (bool, *model.AppError) {
 	// If TestMode (DefaultEnabled=false): only sysadmins can modify
 	// If LiveMode (DefaultEnabled=true): channel, team, sysadmin, DM/GM participants can modify
+	// The team setting, if any, overrides DefaultEnabled for channels in that team.
 
 	// Sysadmin has permission regardless
 	if p.API.HasPermissionTo(userID, model.PermissionManageSystem) {
 		return true, nil
 	}
 
-	// if DefaultEnabled=false, no-one else has permissions
-	cfg := p.getConfiguration()
-	if cfg.DefaultEnabled != nil && !*cfg.DefaultEnabled {
+	channel, appErr := p.API.GetChannel(channelID)
+	if appErr != nil {
+		return false, appErr
+	}
+
+	// if not enabled by default, no-one else has permissions
+	if !p.getConfiguration().defaultEnabledForChannel(channel.TeamId, channel.Type) {
 		return false, nil
 	}
 
 	// Must be live mode.
 
 	// Channel admin?
-	channel, appErr := p.API.GetChannel(channelID)
-	if appErr != nil {
-		return false, appErr
-	}
 	cm, appErr := p.API.GetChannelMember(channelID, userID)
 	if appErr != nil {
 		return false, appErr
@@ -385,7 +453,7 @@ func (p *Plugin) handlePostCallsChannel(w http.ResponseWriter, r *http.Request)
 		storedChannel = &public.CallsChannel{
 			ChannelID: channelID,
 			Enabled:   channel.Enabled,
-			Props:     channel.Props,
+			Props:     mergeCallsChannelProps(nil, channel.Props),
 		}
 		if err := p.store.CreateCallsChannel(storedChannel); err != nil {
 			res.Err = fmt.Errorf("failed to create calls channel: %w", err).Error()
@@ -395,7 +463,7 @@ func (p *Plugin) handlePostCallsChannel(w http.ResponseWriter, r *http.Request)
 	} else {
 		storedChannel.ChannelID = channelID
 		storedChannel.Enabled = channel.Enabled
-		storedChannel.Props = channel.Props
+		storedChannel.Props = mergeCallsChannelProps(storedChannel.Props, channel.Props)
 		if err := p.store.UpdateCallsChannel(storedChannel); err != nil {
 			res.Err = fmt.Errorf("failed to update calls channel: %w", err).Error()
 			res.Code = http.StatusInternalServerError
@@ -413,6 +481,29 @@ func (p *Plugin) handlePostCallsChannel(w http.ResponseWriter, r *http.Request)
 	p.publishWebSocketEvent(evType, nil, &WebSocketBroadcast{ChannelID: channelID, ReliableClusterSend: true})
 }
 
+// mergeCallsChannelProps applies the given props on top of the stored ones so
+// that clients only toggling calls in a channel don't reset its other
+// settings (e.g. join_muted, waiting_room). A null value removes the prop.
+func mergeCallsChannelProps(stored, props public.StringMap) public.StringMap {
+	merged := make(public.StringMap, len(stored)+len(props))
+	for k, v := range stored {
+		merged[k] = v
+	}
+	for k, v := range props {
+		if v == nil {
+			delete(merged, k)
+			continue
+		}
+		merged[k] = v
+	}
+
+	if len(merged) == 0 {
+		return nil
+	}
+
+	return merged
+}
+
 func (p *Plugin) handleGetTURNCredentials(w http.ResponseWriter, r *http.Request) {
 	var res httpResponse
 	defer p.httpAudit("handleGetTURNCredentials", &res, w, r)
@@ -424,7 +515,7 @@ func (p *Plugin) handleGetTURNCredentials(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	turnServers := cfg.ICEServersConfigs.getTURNConfigsForCredentials()
+	turnServers := cfg.getICEServers(false).getTURNConfigsForCredentials()
 	if len(turnServers) == 0 {
 		res.Err = "No TURN server was configured"
 		res.Code = http.StatusForbidden
@@ -451,6 +542,35 @@ func (p *Plugin) handleGetTURNCredentials(w http.ResponseWriter, r *http.Request
 	}
 }
 
+// handleGetChannelICEServers returns the ICE servers clients should use for
+// calls in the given channel, including short-lived credentials for the TURN
+// servers that need them.
+func (p *Plugin) handleGetChannelICEServers(w http.ResponseWriter, r *http.Request) {
+	var res httpResponse
+	defer p.httpAudit("handleGetChannelICEServers", &res, w, r)
+
+	userID := r.Header.Get("Mattermost-User-Id")
+	channelID := mux.Vars(r)["channel_id"]
+
+	if !p.API.HasPermissionToChannel(userID, channelID, model.PermissionReadChannel) {
+		res.Err = "Forbidden"
+		res.Code = http.StatusForbidden
+		return
+	}
+
+	iceServers, _, err := p.getUserICEServers(userID, channelID)
+	if err != nil {
+		res.Err = err.Error()
+		res.Code = http.StatusInternalServerError
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(iceServers); err != nil {
+		p.LogError(err.Error())
+	}
+}
+
 // handleConfig returns the client configuration, and cloud license information
 // that isn't exposed to clients yet on the webapp
 func (p *Plugin) handleConfig(w http.ResponseWriter, r *http.Request) error {
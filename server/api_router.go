@@ -27,6 +27,27 @@ func (p *Plugin) newAPIRouter() *mux.Router {
 	}
 	standaloneRoute := router.PathPrefix("/standalone/").HandlerFunc(p.handleServeStandalone).Methods("GET")
 
+	// Inter-plugin API endpoints (plugin ID required)
+
+	interPluginRouter := router.PathPrefix("/interplugin").Subrouter()
+	interPluginRouter.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// User sessions are let through by the auth middleware so we need
+			// to make sure only other plugins can reach these endpoints.
+			if r.Header.Get("Mattermost-Plugin-ID") == "" {
+				http.Error(w, "Forbidden", http.StatusForbidden)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	})
+	interPluginRouter.HandleFunc("/subscriptions/participants", p.handleInterPluginSubscribe).Methods("POST")
+	interPluginRouter.HandleFunc("/subscriptions/participants", p.handleInterPluginUnsubscribe).Methods("DELETE")
+	interPluginRouter.HandleFunc("/calls/{channel_id:[a-z0-9]{26}}/data/{name}", p.handleInterPluginPutCallData).Methods("PUT")
+	interPluginRouter.HandleFunc("/calls/{channel_id:[a-z0-9]{26}}/data/{name}", p.handleInterPluginDeleteCallData).Methods("DELETE")
+	interPluginRouter.HandleFunc("/recordings/active", p.handleInterPluginGetActiveRecordings).Methods("GET")
+
 	// Authenticated API handlers (user session required)
 
 	// Auth middleware
@@ -47,6 +68,13 @@ func (p *Plugin) newAPIRouter() *mux.Router {
 				return
 			}
 
+			// The Mattermost-Plugin-ID header is only set by the server on
+			// inter-plugin requests.
+			if pluginID := r.Header.Get("Mattermost-Plugin-ID"); pluginID != "" && interPluginRouter.Match(r, &mux.RouteMatch{}) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
 			if userID := r.Header.Get("Mattermost-User-Id"); userID != "" {
 				next.ServeHTTP(w, r)
 				return
@@ -105,7 +133,20 @@ func (p *Plugin) newAPIRouter() *mux.Router {
 	// Calls
 	router.HandleFunc("/calls/{channel_id:[a-z0-9]{26}}/dismiss-notification", p.handleDismissNotification).Methods("POST")
 	router.HandleFunc("/calls/{call_id:[a-z0-9]{26}}/recording/{action}", p.handleRecordingAction).Methods("POST")
+	router.HandleFunc("/calls/{call_id:[a-z0-9]{26}}/recording/progress", p.handleGetRecordingProgress).Methods("GET")
+	router.HandleFunc("/calls/{call_id:[a-z0-9]{26}}/recordings", p.handleGetRecordings).Methods("GET")
+	router.HandleFunc("/calls/{call_id:[a-z0-9]{26}}/recordings/{file_id:[a-z0-9]{26}}", p.handleGetRecordingFile).Methods("GET")
+	router.HandleFunc("/calls/recordings/{job_id:[a-z0-9]{26}}/cancel", p.handleCancelRecording).Methods("POST")
+	router.HandleFunc("/calls/history/{call_id:[a-z0-9]{26}}/pseudonyms", p.handleGetRecordingPseudonyms).Methods("GET")
+	router.HandleFunc("/calls/history/{call_id:[a-z0-9]{26}}/timeline", p.handleGetCallTimeline).Methods("GET")
+	router.HandleFunc("/calls/{call_id:[a-z0-9]{26}}/feedback", p.handlePostCallFeedback).Methods("POST")
+	router.HandleFunc("/recordings/active", p.handleGetActiveRecordings).Methods("GET")
+	router.HandleFunc("/recordings/uploads", p.handleGetRecordingUploads).Methods("GET")
+	router.HandleFunc("/recordings/uploads/{upload_id:[a-z0-9]{26}}/retry", p.handleRetryRecordingUpload).Methods("POST")
 	router.HandleFunc("/calls/{channel_id:[a-z0-9]{26}}/active", p.handleGetCallActive).Methods("GET")
+	router.HandleFunc("/calls/{channel_id:[a-z0-9]{26}}/data", p.handleGetCallData).Methods("GET")
+	router.HandleFunc("/calls/{channel_id:[a-z0-9]{26}}/state", p.handleGetCallSnapshot).Methods("GET")
+	router.HandleFunc("/calls/channels/{channel_id:[a-z0-9]{26}}/enabled", p.handleGetCallsChannelEnabled).Methods("GET")
 
 	// Deprecated for hostCtrlRounder /end, but needed for mobile backward compatibility (pre 2.18)
 	router.HandleFunc("/calls/{call_id:[a-z0-9]{26}}/end", p.handleEnd).Methods("POST")
@@ -119,6 +160,15 @@ func (p *Plugin) newAPIRouter() *mux.Router {
 	hostCtrlRouter.HandleFunc("/remove", p.handleRemoveSession).Methods("POST")
 	hostCtrlRouter.HandleFunc("/mute-others", p.handleMuteOthers).Methods("POST")
 	hostCtrlRouter.HandleFunc("/end", p.handleEnd).Methods("POST")
+	hostCtrlRouter.HandleFunc("/speaker-labels", p.handleSpeakerLabels).Methods("POST")
+	hostCtrlRouter.HandleFunc("/noise-suppression", p.handleNoiseSuppression).Methods("POST")
+	hostCtrlRouter.HandleFunc("/features", p.handleCallFeatures).Methods("POST")
+	hostCtrlRouter.HandleFunc("/noise-auto-mute", p.handleNoiseAutoMute).Methods("POST")
+	hostCtrlRouter.HandleFunc("/live-captions", p.handleLiveCaptions).Methods("POST")
+	hostCtrlRouter.HandleFunc("/waiting-room", p.handleWaitingRoom).Methods("POST")
+	hostCtrlRouter.HandleFunc("/admit", p.handleAdmit).Methods("POST")
+	hostCtrlRouter.HandleFunc("/deny", p.handleDeny).Methods("POST")
+	hostCtrlRouter.HandleFunc("/move", p.handleMoveCall).Methods("POST")
 
 	// Bot
 	botRouter := router.PathPrefix("/bot").Subrouter()
@@ -129,7 +179,7 @@ func (p *Plugin) newAPIRouter() *mux.Router {
 				return
 			}
 
-			if !p.licenseChecker.RecordingsAllowed() {
+			if !p.licenseChecker.RecordingsAllowed() && !p.jobsMayFinishAfterLicenseLapse() {
 				http.Error(w, "Forbidden", http.StatusForbidden)
 				return
 			}
@@ -140,6 +190,7 @@ func (p *Plugin) newAPIRouter() *mux.Router {
 	botRouter.HandleFunc("/channels/{channel_id:[a-z0-9]{26}}", p.handleBotGetChannel).Methods("GET")
 	botRouter.HandleFunc("/users/{user_id:[a-z0-9]{26}}/image", p.handleBotGetUserImage).Methods("GET")
 	botRouter.HandleFunc("/calls/{call_id:[a-z0-9]{26}}/sessions/{session_id:[a-z0-9]{26}}/profile", p.handleBotGetProfileForSession).Methods("GET")
+	botRouter.HandleFunc("/calls/{call_id:[a-z0-9]{26}}/profiles", p.handleBotGetProfilesForCall).Methods("POST")
 	botRouter.HandleFunc("/calls/{call_id:[a-z0-9]{26}}/filename", p.handleBotGetFilenameForCall).Methods("GET")
 	botRouter.HandleFunc("/uploads/{upload_id:[a-z0-9]{26}}", p.handleBotGetUpload).Methods("GET")
 	botRouter.HandleFunc("/uploads", p.handleBotCreateUpload).Methods("POST")
@@ -150,6 +201,24 @@ func (p *Plugin) newAPIRouter() *mux.Router {
 
 	// TURN
 	router.HandleFunc("/turn-credentials", p.handleGetTURNCredentials).Methods("GET")
+	router.HandleFunc("/calls/{channel_id:[a-z0-9]{26}}/ice-servers", p.handleGetChannelICEServers).Methods("GET")
+
+	// User preferences
+	router.HandleFunc("/notification-preferences", p.handleGetCallNotificationPreferences).Methods("GET")
+	router.HandleFunc("/notification-preferences", p.handlePostCallNotificationPreferences).Methods("POST")
+
+	// Blocked users (global and per channel)
+	router.HandleFunc("/blocked-users", p.handleGetBlockedUsers).Methods("GET")
+	router.HandleFunc("/blocked-users", p.handlePostBlockedUser).Methods("POST")
+	router.HandleFunc("/blocked-users/{user_id:[a-z0-9]{26}}", p.handleDeleteBlockedUser).Methods("DELETE")
+	router.HandleFunc("/calls/{channel_id:[a-z0-9]{26}}/blocked-users", p.handleGetBlockedUsers).Methods("GET")
+	router.HandleFunc("/calls/{channel_id:[a-z0-9]{26}}/blocked-users", p.handlePostBlockedUser).Methods("POST")
+	router.HandleFunc("/calls/{channel_id:[a-z0-9]{26}}/blocked-users/{user_id:[a-z0-9]{26}}", p.handleDeleteBlockedUser).Methods("DELETE")
+
+	// Nodes
+	router.HandleFunc("/calls/nodes/{node_id}/drain", p.handleGetNodeDrain).Methods("GET")
+	router.HandleFunc("/calls/nodes/{node_id}/drain", p.handlePostNodeDrain).Methods("POST")
+	router.HandleFunc("/calls/nodes/{node_id}/drain", p.handleDeleteNodeDrain).Methods("DELETE")
 
 	// Cloud
 	router.HandleFunc("/cloud-notify-admins", func(w http.ResponseWriter, r *http.Request) {
@@ -171,6 +240,40 @@ func (p *Plugin) newAPIRouter() *mux.Router {
 		}
 	}).Methods("GET")
 
+	// Calls history
+	router.HandleFunc("/calls/history", func(w http.ResponseWriter, r *http.Request) {
+		if userID := r.Header.Get("Mattermost-User-Id"); !p.API.HasPermissionTo(userID, model.PermissionManageSystem) {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+
+		if err := p.handleGetCallsHistory(w, r); err != nil {
+			p.handleError(w, err)
+		}
+	}).Methods("GET")
+	router.HandleFunc("/calls/history/export", func(w http.ResponseWriter, r *http.Request) {
+		if userID := r.Header.Get("Mattermost-User-Id"); !p.API.HasPermissionTo(userID, model.PermissionManageSystem) {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+
+		if err := p.handleExportCallsHistory(w, r); err != nil {
+			p.handleError(w, err)
+		}
+	}).Methods("GET")
+
+	// Recordings storage
+	router.HandleFunc("/calls/recordings/storage", func(w http.ResponseWriter, r *http.Request) {
+		if userID := r.Header.Get("Mattermost-User-Id"); !p.API.HasPermissionTo(userID, model.PermissionManageSystem) {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+
+		if err := p.handleGetRecordingsStorage(w, r); err != nil {
+			p.handleError(w, err)
+		}
+	}).Methods("GET")
+
 	// Rate limiting middleware
 	router.Use(func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -0,0 +1,44 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost-plugin-calls/server/public"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeCallsChannelProps(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		require.Nil(t, mergeCallsChannelProps(nil, nil))
+		require.Nil(t, mergeCallsChannelProps(nil, public.StringMap{"join_muted": nil}))
+	})
+
+	t.Run("new", func(t *testing.T) {
+		require.Equal(t, public.StringMap{"join_muted": true},
+			mergeCallsChannelProps(nil, public.StringMap{"join_muted": true}))
+	})
+
+	t.Run("toggling calls keeps settings", func(t *testing.T) {
+		stored := public.StringMap{"join_muted": true, "waiting_room": false}
+		require.Equal(t, stored, mergeCallsChannelProps(stored, nil))
+	})
+
+	t.Run("update", func(t *testing.T) {
+		stored := public.StringMap{"join_muted": true, "waiting_room": false}
+		require.Equal(t, public.StringMap{"join_muted": false, "waiting_room": false, "confirm_call_start": true},
+			mergeCallsChannelProps(stored, public.StringMap{"join_muted": false, "confirm_call_start": true}))
+		// Stored props are left untouched.
+		require.Equal(t, public.StringMap{"join_muted": true, "waiting_room": false}, stored)
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		stored := public.StringMap{"join_muted": true, "waiting_room": false}
+		require.Equal(t, public.StringMap{"waiting_room": false},
+			mergeCallsChannelProps(stored, public.StringMap{"join_muted": nil}))
+		require.Nil(t, mergeCallsChannelProps(public.StringMap{"join_muted": true}, public.StringMap{"join_muted": nil}))
+	})
+}
@@ -0,0 +1,313 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/mattermost/mattermost-plugin-calls/server/db"
+
+	"github.com/gorilla/mux"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+const (
+	blockedUsersKeyPrefix     = "blocked_users_"
+	blockedUsersGlobalKey     = blockedUsersKeyPrefix + "global"
+	maxBlockedUsers           = 1000
+	blockedUsersUpdateRetries = 5
+)
+
+var errUserBlocked = errors.New("user is blocked from joining calls in this channel")
+
+type blockedUsers struct {
+	UserIDs []string `json:"user_ids"`
+}
+
+// blockedUsersKey returns the KV key holding the users blocked from calls in
+// the given channel, or globally if channelID is empty.
+func blockedUsersKey(channelID string) string {
+	if channelID == "" {
+		return blockedUsersGlobalKey
+	}
+	return blockedUsersKeyPrefix + channelID
+}
+
+func (p *Plugin) getBlockedUsers(channelID string) ([]string, []byte, error) {
+	data, appErr := p.API.KVGet(blockedUsersKey(channelID))
+	if appErr != nil {
+		return nil, nil, fmt.Errorf("failed to get blocked users: %w", appErr)
+	}
+
+	userIDs := []string{}
+	if data == nil {
+		return userIDs, nil, nil
+	}
+
+	if err := json.Unmarshal(data, &userIDs); err != nil {
+		return nil, nil, fmt.Errorf("failed to unmarshal blocked users: %w", err)
+	}
+
+	return userIDs, data, nil
+}
+
+// updateBlockedUsers applies the given update to the users blocked from calls
+// in a channel (or globally). Concurrent updates from different nodes are
+// detected and retried so that no change is lost.
+func (p *Plugin) updateBlockedUsers(channelID string, update func(userIDs []string) ([]string, error)) error {
+	for i := 0; i < blockedUsersUpdateRetries; i++ {
+		userIDs, oldData, err := p.getBlockedUsers(channelID)
+		if err != nil {
+			return err
+		}
+
+		userIDs, err = update(userIDs)
+		if err != nil {
+			return err
+		}
+
+		data, err := json.Marshal(userIDs)
+		if err != nil {
+			return fmt.Errorf("failed to marshal blocked users: %w", err)
+		}
+
+		ok, appErr := p.API.KVSetWithOptions(blockedUsersKey(channelID), data, model.PluginKVSetOptions{
+			Atomic:   true,
+			OldValue: oldData,
+		})
+		if appErr != nil {
+			return fmt.Errorf("failed to set blocked users: %w", appErr)
+		}
+		if ok {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("failed to update blocked users: too many concurrent updates")
+}
+
+// checkUserBlockedFromCalls returns errUserBlocked if the user is blocked
+// from calls in the given channel, either in the channel itself or globally.
+func (p *Plugin) checkUserBlockedFromCalls(userID, channelID string) error {
+	if userID == p.getBotID() {
+		return nil
+	}
+
+	for _, id := range []string{"", channelID} {
+		userIDs, _, err := p.getBlockedUsers(id)
+		if err != nil {
+			return err
+		}
+		if slices.Contains(userIDs, userID) {
+			return errUserBlocked
+		}
+	}
+
+	return nil
+}
+
+// removeBlockedUserFromCalls removes the user from the ongoing call in the
+// given channel, or from all ongoing calls if channelID is empty.
+func (p *Plugin) removeBlockedUserFromCalls(userID, channelID string) error {
+	var channelIDs []string
+	if channelID != "" {
+		channelIDs = []string{channelID}
+	} else {
+		calls, err := p.store.GetAllActiveCalls(db.GetCallOpts{})
+		if err != nil {
+			return fmt.Errorf("failed to get active calls: %w", err)
+		}
+		for _, call := range calls {
+			inCall, err := p.store.IsUserInCall(userID, call.ID, db.GetCallSessionOpts{})
+			if err != nil {
+				return fmt.Errorf("failed to check whether user is in call: %w", err)
+			}
+			if inCall {
+				channelIDs = append(channelIDs, call.ChannelID)
+			}
+		}
+	}
+
+	for _, channelID := range channelIDs {
+		state, err := p.getCallState(channelID, false)
+		if err != nil {
+			return err
+		}
+		if state == nil {
+			continue
+		}
+
+		for _, sessionID := range state.getUserSessionIDs(userID) {
+			p.LogDebug("removing blocked user from call", "userID", userID, "channelID", channelID, "sessionID", sessionID)
+
+			p.publishWebSocketEvent(wsEventHostRemoved, map[string]interface{}{
+				"call_id":    state.Call.ID,
+				"channel_id": channelID,
+				"session_id": sessionID,
+				"user_id":    userID,
+			}, &WebSocketBroadcast{
+				ChannelID:           channelID,
+				ReliableClusterSend: true,
+				UserIDs:             getUserIDsFromSessions(state.sessions),
+			})
+
+			go p.closeSessionAfterGracePeriod(channelID, sessionID)
+		}
+	}
+
+	return nil
+}
+
+// permissionToManageBlockedUsers returns whether the user can manage the
+// users blocked from calls in the given channel (or globally if channelID is
+// empty). Global blocks are reserved to system admins while channel ones can
+// also be managed by team and channel admins.
+func (p *Plugin) permissionToManageBlockedUsers(userID, channelID string) (bool, *model.AppError) {
+	if p.API.HasPermissionTo(userID, model.PermissionManageSystem) {
+		return true, nil
+	}
+
+	if channelID == "" {
+		return false, nil
+	}
+
+	channel, appErr := p.API.GetChannel(channelID)
+	if appErr != nil {
+		return false, appErr
+	}
+
+	if p.API.HasPermissionToTeam(userID, channel.TeamId, model.PermissionManageTeam) {
+		return true, nil
+	}
+
+	cm, appErr := p.API.GetChannelMember(channelID, userID)
+	if appErr != nil {
+		return false, appErr
+	}
+
+	return cm.SchemeAdmin, nil
+}
+
+func (p *Plugin) handleGetBlockedUsers(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("Mattermost-User-Id")
+	channelID := mux.Vars(r)["channel_id"]
+
+	if permission, appErr := p.permissionToManageBlockedUsers(userID, channelID); appErr != nil || !permission {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	userIDs, _, err := p.getBlockedUsers(channelID)
+	if err != nil {
+		p.LogError(err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(blockedUsers{UserIDs: userIDs}); err != nil {
+		p.LogError(err.Error())
+	}
+}
+
+func (p *Plugin) handlePostBlockedUser(w http.ResponseWriter, r *http.Request) {
+	var res httpResponse
+	defer p.httpAudit("handlePostBlockedUser", &res, w, r)
+
+	userID := r.Header.Get("Mattermost-User-Id")
+	channelID := mux.Vars(r)["channel_id"]
+
+	if permission, appErr := p.permissionToManageBlockedUsers(userID, channelID); appErr != nil || !permission {
+		res.Err = "Forbidden"
+		res.Code = http.StatusForbidden
+		return
+	}
+
+	var data struct {
+		UserID string `json:"user_id"`
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, requestBodyMaxSizeBytes)).Decode(&data); err != nil {
+		res.Err = err.Error()
+		res.Code = http.StatusBadRequest
+		return
+	}
+
+	if !model.IsValidId(data.UserID) {
+		res.Err = "invalid user_id"
+		res.Code = http.StatusBadRequest
+		return
+	}
+
+	if data.UserID == userID || data.UserID == p.getBotID() {
+		res.Err = "user cannot be blocked"
+		res.Code = http.StatusBadRequest
+		return
+	}
+
+	if _, appErr := p.API.GetUser(data.UserID); appErr != nil {
+		res.Err = "user not found"
+		res.Code = http.StatusNotFound
+		return
+	}
+
+	err := p.updateBlockedUsers(channelID, func(userIDs []string) ([]string, error) {
+		if slices.Contains(userIDs, data.UserID) {
+			return userIDs, nil
+		}
+		if len(userIDs) >= maxBlockedUsers {
+			return nil, fmt.Errorf("too many blocked users: should be at most %d", maxBlockedUsers)
+		}
+		return append(userIDs, data.UserID), nil
+	})
+	if err != nil {
+		res.Err = err.Error()
+		res.Code = http.StatusInternalServerError
+		return
+	}
+
+	p.LogInfo("user blocked from calls", "userID", data.UserID, "channelID", channelID, "requesterID", userID)
+
+	if err := p.removeBlockedUserFromCalls(data.UserID, channelID); err != nil {
+		p.LogError("failed to remove blocked user from calls", "err", err.Error(), "userID", data.UserID)
+	}
+
+	res.Code = http.StatusOK
+	res.Msg = "success"
+}
+
+func (p *Plugin) handleDeleteBlockedUser(w http.ResponseWriter, r *http.Request) {
+	var res httpResponse
+	defer p.httpAudit("handleDeleteBlockedUser", &res, w, r)
+
+	userID := r.Header.Get("Mattermost-User-Id")
+	channelID := mux.Vars(r)["channel_id"]
+	blockedUserID := mux.Vars(r)["user_id"]
+
+	if permission, appErr := p.permissionToManageBlockedUsers(userID, channelID); appErr != nil || !permission {
+		res.Err = "Forbidden"
+		res.Code = http.StatusForbidden
+		return
+	}
+
+	err := p.updateBlockedUsers(channelID, func(userIDs []string) ([]string, error) {
+		return slices.DeleteFunc(userIDs, func(id string) bool {
+			return id == blockedUserID
+		}), nil
+	})
+	if err != nil {
+		res.Err = err.Error()
+		res.Code = http.StatusInternalServerError
+		return
+	}
+
+	p.LogInfo("user unblocked from calls", "userID", blockedUserID, "channelID", channelID, "requesterID", userID)
+
+	res.Code = http.StatusOK
+	res.Msg = "success"
+}
@@ -0,0 +1,169 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	pluginMocks "github.com/mattermost/mattermost-plugin-calls/server/mocks/github.com/mattermost/mattermost/server/public/plugin"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"golang.org/x/time/rate"
+)
+
+func TestCheckUserBlockedFromCalls(t *testing.T) {
+	mockAPI := &pluginMocks.MockAPI{}
+	defer mockAPI.AssertExpectations(t)
+
+	p := Plugin{
+		MattermostPlugin: plugin.MattermostPlugin{
+			API: mockAPI,
+		},
+		botSession: &model.Session{
+			UserId: "botID",
+		},
+	}
+
+	userID := model.NewId()
+	channelID := model.NewId()
+
+	t.Run("bot", func(t *testing.T) {
+		require.NoError(t, p.checkUserBlockedFromCalls("botID", channelID))
+	})
+
+	t.Run("not blocked", func(t *testing.T) {
+		mockAPI.On("KVGet", blockedUsersGlobalKey).Return(nil, nil).Once()
+		mockAPI.On("KVGet", blockedUsersKeyPrefix+channelID).Return([]byte(`["`+model.NewId()+`"]`), nil).Once()
+
+		require.NoError(t, p.checkUserBlockedFromCalls(userID, channelID))
+	})
+
+	t.Run("blocked globally", func(t *testing.T) {
+		mockAPI.On("KVGet", blockedUsersGlobalKey).Return([]byte(`["`+userID+`"]`), nil).Once()
+
+		require.Equal(t, errUserBlocked, p.checkUserBlockedFromCalls(userID, channelID))
+	})
+
+	t.Run("blocked in channel", func(t *testing.T) {
+		mockAPI.On("KVGet", blockedUsersGlobalKey).Return(nil, nil).Once()
+		mockAPI.On("KVGet", blockedUsersKeyPrefix+channelID).Return([]byte(`["`+userID+`"]`), nil).Once()
+
+		require.Equal(t, errUserBlocked, p.checkUserBlockedFromCalls(userID, channelID))
+	})
+}
+
+func TestUpdateBlockedUsers(t *testing.T) {
+	mockAPI := &pluginMocks.MockAPI{}
+	defer mockAPI.AssertExpectations(t)
+
+	p := Plugin{
+		MattermostPlugin: plugin.MattermostPlugin{
+			API: mockAPI,
+		},
+	}
+
+	channelID := model.NewId()
+	key := blockedUsersKeyPrefix + channelID
+
+	add := func(userID string) func([]string) ([]string, error) {
+		return func(userIDs []string) ([]string, error) {
+			return append(userIDs, userID), nil
+		}
+	}
+
+	t.Run("concurrent update", func(t *testing.T) {
+		mockAPI.On("KVGet", key).Return(nil, nil).Once()
+		mockAPI.On("KVSetWithOptions", key, []byte(`["userA"]`), model.PluginKVSetOptions{Atomic: true}).
+			Return(false, nil).Once()
+
+		mockAPI.On("KVGet", key).Return([]byte(`["userB"]`), nil).Once()
+		mockAPI.On("KVSetWithOptions", key, []byte(`["userB","userA"]`), model.PluginKVSetOptions{Atomic: true, OldValue: []byte(`["userB"]`)}).
+			Return(true, nil).Once()
+
+		require.NoError(t, p.updateBlockedUsers(channelID, add("userA")))
+	})
+
+	t.Run("too many concurrent updates", func(t *testing.T) {
+		mockAPI.On("KVGet", key).Return(nil, nil).Times(blockedUsersUpdateRetries)
+		mockAPI.On("KVSetWithOptions", key, []byte(`["userA"]`), model.PluginKVSetOptions{Atomic: true}).
+			Return(false, nil).Times(blockedUsersUpdateRetries)
+
+		require.EqualError(t, p.updateBlockedUsers(channelID, add("userA")),
+			"failed to update blocked users: too many concurrent updates")
+	})
+}
+
+func TestHandlePostBlockedUser(t *testing.T) {
+	mockAPI := &pluginMocks.MockAPI{}
+	defer mockAPI.AssertExpectations(t)
+
+	botID := model.NewId()
+
+	p := Plugin{
+		MattermostPlugin: plugin.MattermostPlugin{
+			API: mockAPI,
+		},
+		botSession: &model.Session{
+			UserId: botID,
+		},
+		apiLimiters: map[string]*rate.Limiter{},
+	}
+
+	apiRouter := p.newAPIRouter()
+
+	logArgs := []any{"handlePostBlockedUser"}
+	for i := 0; i < 18; i++ {
+		logArgs = append(logArgs, mock.Anything)
+	}
+	mockAPI.On("LogDebug", logArgs...)
+
+	userID := model.NewId()
+	channelID := model.NewId()
+
+	t.Run("global requires system admin", func(t *testing.T) {
+		mockAPI.On("HasPermissionTo", userID, model.PermissionManageSystem).Return(false).Once()
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("POST", "/blocked-users", strings.NewReader(`{"user_id":"`+model.NewId()+`"}`))
+		r.Header.Set("Mattermost-User-Id", userID)
+		apiRouter.ServeHTTP(w, r)
+
+		require.Equal(t, http.StatusForbidden, w.Result().StatusCode)
+	})
+
+	t.Run("channel requires channel admin", func(t *testing.T) {
+		mockAPI.On("HasPermissionTo", userID, model.PermissionManageSystem).Return(false).Once()
+		mockAPI.On("GetChannel", channelID).Return(&model.Channel{Id: channelID, TeamId: "teamID"}, nil).Once()
+		mockAPI.On("HasPermissionToTeam", userID, "teamID", model.PermissionManageTeam).Return(false).Once()
+		mockAPI.On("GetChannelMember", channelID, userID).Return(&model.ChannelMember{SchemeAdmin: false}, nil).Once()
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("POST", "/calls/"+channelID+"/blocked-users", strings.NewReader(`{"user_id":"`+model.NewId()+`"}`))
+		r.Header.Set("Mattermost-User-Id", userID)
+		apiRouter.ServeHTTP(w, r)
+
+		require.Equal(t, http.StatusForbidden, w.Result().StatusCode)
+	})
+
+	t.Run("invalid user", func(t *testing.T) {
+		mockAPI.On("HasPermissionTo", userID, model.PermissionManageSystem).Return(true).Times(3)
+
+		for _, blockedUserID := range []string{"invalid", userID, botID} {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("POST", "/blocked-users", strings.NewReader(`{"user_id":"`+blockedUserID+`"}`))
+			r.Header.Set("Mattermost-User-Id", userID)
+			apiRouter.ServeHTTP(w, r)
+
+			require.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+		}
+	})
+}
@@ -6,10 +6,13 @@ package main
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"time"
 
+	"github.com/mattermost/mattermost-plugin-calls/server/db"
 	"github.com/mattermost/mattermost-plugin-calls/server/public"
 
 	"github.com/mattermost/mattermost/server/public/model"
@@ -52,6 +55,13 @@ func (p *Plugin) handleBotGetChannel(w http.ResponseWriter, r *http.Request) {
 func (p *Plugin) handleBotGetUserImage(w http.ResponseWriter, r *http.Request) {
 	userID := mux.Vars(r)["user_id"]
 
+	// Anonymized participants are shown with the bot's avatar.
+	if ok, err := p.isRecordingPseudonymID(userID); err != nil {
+		p.LogError(err.Error())
+	} else if ok {
+		userID = p.getBotID()
+	}
+
 	data, appErr := p.API.GetProfileImage(userID)
 	if appErr != nil {
 		p.LogError(appErr.Error())
@@ -89,6 +99,9 @@ func (p *Plugin) handleBotUploadData(w http.ResponseWriter, r *http.Request) {
 	var res httpResponse
 	defer p.httpAudit("handleBotUploadData", &res, w, r)
 
+	done := p.startRecordingFinalization()
+	defer done()
+
 	uploadID := mux.Vars(r)["upload_id"]
 
 	us, err := p.API.GetUploadSession(uploadID)
@@ -113,11 +126,11 @@ func (p *Plugin) handleBotUploadData(w http.ResponseWriter, r *http.Request) {
 	// We use FileSettings.MaxFileSize to keep the request body size bounded to
 	// a sensible value to avoid abuse. This doesn't limit the amount of data we
 	// can upload overall.
-	fi, err := p.API.UploadData(us, http.MaxBytesReader(w, r.Body, *serverCfg.FileSettings.MaxFileSize))
+	fi, err := p.storeRecordingUploadData(us, io.LimitReader(r.Body, *serverCfg.FileSettings.MaxFileSize))
 	if err != nil {
 		res.Err = err.Error()
-		appErr, ok := err.(*model.AppError)
-		if ok {
+		var appErr *model.AppError
+		if errors.As(err, &appErr) {
 			res.Code = appErr.StatusCode
 		} else {
 			res.Code = http.StatusInternalServerError
@@ -174,6 +187,9 @@ func (p *Plugin) handleBotPostRecordings(w http.ResponseWriter, r *http.Request)
 	var res httpResponse
 	defer p.httpAudit("handleBotPostRecordings", &res, w, r)
 
+	done := p.startRecordingFinalization()
+	defer done()
+
 	callID := mux.Vars(r)["call_id"]
 
 	var info public.RecordingJobInfo
@@ -192,7 +208,7 @@ func (p *Plugin) handleBotPostRecordings(w http.ResponseWriter, r *http.Request)
 	// Here we need to lock since we'll be reading and updating the call
 	// post, potentially concurrently with other events (e.g. call ending,
 	// transcribing job completing).
-	_, err := p.lockCallReturnState(callID)
+	state, err := p.lockCallReturnState(callID)
 	if err != nil {
 		res.Err = fmt.Errorf("failed to lock call: %w", err).Error()
 		res.Code = http.StatusInternalServerError
@@ -226,6 +242,26 @@ func (p *Plugin) handleBotPostRecordings(w http.ResponseWriter, r *http.Request)
 	if title, _ := post.GetProp("title").(string); title != "" {
 		postMsg = fmt.Sprintf("%s of %s at %s UTC", postMsg, title, time.UnixMilli(startAt).Format("3:04PM"))
 	}
+
+	// The job has ended by the time its output gets posted.
+	recJob, err := p.store.GetCallJob(info.JobID, db.GetCallJobOpts{IncludeEnded: true})
+	if err != nil {
+		p.LogError("failed to get recording job", "recID", info.JobID, "err", err.Error())
+	}
+
+	if recJob != nil && recJob.Props.DiscardOutput {
+		p.LogInfo("recording was canceled, discarding output", "recID", info.JobID, "callID", callID)
+		res.Code = http.StatusOK
+		res.Msg = "success"
+		return
+	}
+
+	// When recording in multiple quality profiles each output gets its own
+	// post, labelled accordingly.
+	if recJob != nil && recJob.Props.Profile != "" {
+		postMsg = fmt.Sprintf("%s (%s quality)", postMsg, recJob.Props.Profile)
+	}
+
 	recPost := &model.Post{
 		UserId:    p.getBotID(),
 		ChannelId: callID,
@@ -237,6 +273,29 @@ func (p *Plugin) handleBotPostRecordings(w http.ResponseWriter, r *http.Request)
 	recPost.AddProp("recording_id", info.JobID)
 	recPost.AddProp("call_post_id", info.PostID)
 
+	if recJob != nil {
+		// Any interval during which the recording was paused is marked so that
+		// clients can tell the output isn't a continuous capture.
+		if len(recJob.Props.Pauses) > 0 {
+			recPost.AddProp("paused_intervals", recJob.Props.Pauses)
+		}
+		if recJob.Props.Profile != "" {
+			recPost.AddProp("recording_profile", recJob.Props.Profile)
+		}
+		// Markers set by the host are exposed as chapters, both as a post prop
+		// and as a WebVTT chapters file that players can load alongside the
+		// recording.
+		if chapters := getRecordingChapters(recJob); len(chapters) > 0 {
+			recPost.AddProp("chapters", chapters)
+			fi, appErr := p.API.UploadFile(generateChaptersVTT(recJob), callID, fmt.Sprintf("chapters-%s.vtt", recJob.ID))
+			if appErr != nil {
+				p.LogError("failed to upload chapters file", "recID", info.JobID, "err", appErr.Error())
+			} else {
+				recPost.AddProp("chapters_file_id", fi.Id)
+			}
+		}
+	}
+
 	recPost, appErr := p.API.CreatePost(recPost)
 	if appErr != nil {
 		res.Err = "failed to create post: " + appErr.Error()
@@ -264,6 +323,28 @@ func (p *Plugin) handleBotPostRecordings(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	if p.getConfiguration().recordingWebhookEnabled() {
+		payload := recordingWebhookPayload{
+			ChannelID:   callID,
+			CallPostID:  info.PostID,
+			RecordingID: info.JobID,
+			PostID:      recPost.Id,
+			FileID:      info.FileIDs[0],
+			Chapters:    getRecordingChapters(recJob),
+		}
+		if state != nil {
+			payload.CallID = state.Call.ID
+			payload.CallMetadata = state.Call.Props.Metadata
+		}
+		go p.sendRecordingWebhook(payload, threadID)
+	}
+
+	go p.enforceRecordingStorageQuota(callID, recPost.Id)
+
+	if recJob != nil && p.shouldNotifyParticipantsOfRecordings(callID) {
+		go p.sendRecordingNotifications(recJob.CallID, recPost.Id, getTranscriptionPostID(post, info.JobID))
+	}
+
 	res.Code = http.StatusOK
 	res.Msg = "success"
 }
@@ -287,6 +368,9 @@ func (p *Plugin) handleBotPostTranscriptions(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
+	// The job is done processing at this point.
+	p.releaseTranscriptionSlot(info.JobID)
+
 	// Here we need to lock since we'll be reading and updating the call
 	// post, potentially concurrently with other events (e.g. call ending,
 	// recording job completing).
@@ -298,6 +382,15 @@ func (p *Plugin) handleBotPostTranscriptions(w http.ResponseWriter, r *http.Requ
 	}
 	defer p.unlockCall(callID)
 
+	if trJob, err := p.store.GetCallJob(info.JobID, db.GetCallJobOpts{IncludeEnded: true}); err != nil {
+		p.LogError("failed to get transcription job", "trID", info.JobID, "err", err.Error())
+	} else if trJob.Props.DiscardOutput {
+		p.LogInfo("transcription was canceled, discarding output", "trID", info.JobID, "callID", callID)
+		res.Code = http.StatusOK
+		res.Msg = "success"
+		return
+	}
+
 	// Update call post
 	post, err := p.store.GetPost(info.PostID)
 	if err != nil {
@@ -439,6 +532,9 @@ func (p *Plugin) handleBotPostJobsStatus(w http.ResponseWriter, r *http.Request)
 	switch status.JobType {
 	case public.JobTypeRecording:
 		jb = state.Recording
+		if profileJob := state.getProfileRecording(jobID); profileJob != nil {
+			jb = profileJob
+		}
 	case public.JobTypeTranscribing:
 		jb = state.Transcription
 		if cfg := p.getConfiguration(); cfg != nil && cfg.liveCaptionsEnabled() {
@@ -476,7 +572,14 @@ func (p *Plugin) handleBotPostJobsStatus(w http.ResponseWriter, r *http.Request)
 		jb.EndAt = time.Now().UnixMilli()
 		jb.Props.Err = status.Error
 
-		if status.JobType == public.JobTypeRecording && state.Transcription != nil {
+		if status.JobType == public.JobTypeTranscribing {
+			p.releaseTranscriptionSlot(jobID)
+		}
+
+		// Additional recording jobs failing doesn't affect any other job.
+		if status.JobType == public.JobTypeRecording && jb.Props.PrimaryJobID != "" {
+			p.LogWarn("profile recording job has failed", "jobID", jobID, "profile", jb.Props.Profile, "err", status.Error)
+		} else if status.JobType == public.JobTypeRecording && state.Transcription != nil {
 			if err := p.stopTranscribingJob(state, callID); err != nil {
 				p.LogError("failed to stop transcribing job", "callID", callID, "err", err.Error())
 			}
@@ -517,7 +620,7 @@ func (p *Plugin) handleBotPostJobsStatus(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	if status.JobType == public.JobTypeRecording {
+	if status.JobType == public.JobTypeRecording && jb.Props.PrimaryJobID == "" {
 		p.publishWebSocketEvent(wsEventCallJobState, map[string]interface{}{
 			"callID":   callID,
 			"jobState": getClientStateFromCallJob(state.Recording).toMap(),
@@ -581,11 +684,23 @@ func (p *Plugin) handleBotGetProfileForSession(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	user, appErr := p.API.GetUser(ust.UserID)
-	if appErr != nil {
-		res.Code = http.StatusInternalServerError
-		res.Err = appErr.Error()
-		return
+	var user *model.User
+	if callJobsAnonymized(state) {
+		ps, err := p.getRecordingPseudonym(state.Call.ID, ust.UserID)
+		if err != nil {
+			res.Code = http.StatusInternalServerError
+			res.Err = err.Error()
+			return
+		}
+		user = ps.toUser()
+	} else {
+		var appErr *model.AppError
+		user, appErr = p.API.GetUser(ust.UserID)
+		if appErr != nil {
+			res.Code = http.StatusInternalServerError
+			res.Err = appErr.Error()
+			return
+		}
 	}
 
 	user.Sanitize(nil)
@@ -596,6 +711,80 @@ func (p *Plugin) handleBotGetProfileForSession(w http.ResponseWriter, r *http.Re
 	}
 }
 
+// handleBotGetProfilesForCall returns the profiles of the given call
+// participants, keyed by user ID. When the call is being recorded or
+// transcribed anonymously their pseudonyms are returned instead so that the
+// recording view never resolves the real profiles.
+func (p *Plugin) handleBotGetProfilesForCall(w http.ResponseWriter, r *http.Request) {
+	var res httpResponse
+	defer p.httpResponseHandler(&res, w)
+
+	callID := mux.Vars(r)["call_id"]
+
+	var userIDs []string
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, requestBodyMaxSizeBytes)).Decode(&userIDs); err != nil {
+		res.Code = http.StatusBadRequest
+		res.Err = err.Error()
+		return
+	}
+
+	state, err := p.lockCallReturnState(callID)
+	if err != nil {
+		p.LogError("handleBotGetProfilesForCall: failed to lock call", "err", err.Error())
+		res.Code = http.StatusInternalServerError
+		res.Err = err.Error()
+		return
+	}
+	defer p.unlockCall(callID)
+
+	if state == nil {
+		res.Code = http.StatusBadRequest
+		res.Err = "no call ongoing"
+		return
+	}
+
+	participants := make(map[string]bool, len(state.sessions))
+	for _, ust := range state.sessions {
+		participants[ust.UserID] = true
+	}
+
+	anonymized := callJobsAnonymized(state)
+	profiles := make(map[string]*model.User, len(userIDs))
+	for _, userID := range userIDs {
+		// Only participants can be resolved.
+		if !participants[userID] {
+			continue
+		}
+
+		var user *model.User
+		if anonymized {
+			ps, err := p.getRecordingPseudonym(state.Call.ID, userID)
+			if err != nil {
+				res.Code = http.StatusInternalServerError
+				res.Err = err.Error()
+				return
+			}
+			user = ps.toUser()
+		} else {
+			var appErr *model.AppError
+			user, appErr = p.API.GetUser(userID)
+			if appErr != nil {
+				res.Code = http.StatusInternalServerError
+				res.Err = appErr.Error()
+				return
+			}
+		}
+
+		user.Sanitize(nil)
+		profiles[userID] = user
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(profiles); err != nil {
+		p.LogError(err.Error())
+	}
+}
+
 func (p *Plugin) handleBotGetFilenameForCall(w http.ResponseWriter, r *http.Request) {
 	var res httpResponse
 	defer p.httpResponseHandler(&res, w)
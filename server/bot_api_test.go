@@ -4,6 +4,7 @@
 package main
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -216,3 +217,138 @@ func TestHandleBotGetProfileForSession(t *testing.T) {
 		require.Equal(t, user.Email, respUser.Email)
 	})
 }
+
+func TestHandleBotGetProfilesForCall(t *testing.T) {
+	mockAPI := &pluginMocks.MockAPI{}
+	mockMetrics := &serverMocks.MockMetrics{}
+
+	botUserID := model.NewId()
+
+	p := Plugin{
+		MattermostPlugin: plugin.MattermostPlugin{
+			API: mockAPI,
+		},
+		metrics: mockMetrics,
+		botSession: &model.Session{
+			UserId: botUserID,
+		},
+		callsClusterLocks: map[string]*cluster.Mutex{},
+	}
+
+	p.licenseChecker = enterprise.NewLicenseChecker(p.API)
+
+	store, tearDown := NewTestStore(t)
+	t.Cleanup(tearDown)
+	p.store = store
+
+	mockAPI.On("KVSetWithOptions", mock.Anything, mock.Anything, mock.Anything).Return(true, nil)
+	mockMetrics.On("ObserveClusterMutexGrabTime", "mutex_call", mock.AnythingOfType("float64"))
+	mockMetrics.On("ObserveAppHandlersTime", mock.AnythingOfType("string"), mock.AnythingOfType("float64"))
+	mockMetrics.On("ObserveClusterMutexLockedTime", "mutex_call", mock.AnythingOfType("float64"))
+	mockMetrics.On("Handler").Return(nil).Once()
+
+	mockAPI.On("GetConfig").Return(&model.Config{}, nil)
+	mockAPI.On("GetLicense").Return(&model.License{
+		SkuShortName: "enterprise",
+	}, nil)
+
+	apiRouter := p.newAPIRouter()
+
+	setupCall := func(t *testing.T) (string, string, string) {
+		t.Helper()
+
+		channelID := model.NewId()
+		userID := model.NewId()
+		call := &public.Call{
+			ID:        model.NewId(),
+			ChannelID: channelID,
+			CreateAt:  45,
+			StartAt:   45,
+			OwnerID:   userID,
+		}
+		err := store.CreateCall(call)
+		require.NoError(t, err)
+
+		err = store.CreateCallSession(&public.CallSession{
+			ID:     model.NewId(),
+			CallID: call.ID,
+			UserID: userID,
+			JoinAt: 45,
+		})
+		require.NoError(t, err)
+
+		mockAPI.On("LogDebug", "creating cluster mutex for call",
+			"origin", mock.AnythingOfType("string"), "channelID", channelID).Once()
+		mockAPI.On("KVDelete", "mutex_call_"+channelID).Return(nil).Once()
+
+		return channelID, call.ID, userID
+	}
+
+	getProfiles := func(t *testing.T, channelID string, userIDs []string) map[string]*model.User {
+		t.Helper()
+
+		body, err := json.Marshal(userIDs)
+		require.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("POST", "/bot/calls/"+channelID+"/profiles", bytes.NewReader(body))
+		r.Header.Set("Mattermost-User-Id", botUserID)
+		apiRouter.ServeHTTP(w, r)
+
+		resp := w.Result()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var profiles map[string]*model.User
+		err = json.NewDecoder(resp.Body).Decode(&profiles)
+		require.NoError(t, err)
+		return profiles
+	}
+
+	t.Run("success", func(t *testing.T) {
+		defer mockAPI.AssertExpectations(t)
+
+		channelID, _, userID := setupCall(t)
+
+		mockAPI.On("GetUser", userID).Return(&model.User{
+			Id:       userID,
+			Username: "testuser",
+		}, nil).Once()
+
+		// Non participants are not resolved.
+		profiles := getProfiles(t, channelID, []string{userID, model.NewId()})
+		require.Len(t, profiles, 1)
+		require.Equal(t, userID, profiles[userID].Id)
+		require.Equal(t, "testuser", profiles[userID].Username)
+	})
+
+	t.Run("anonymized", func(t *testing.T) {
+		defer mockAPI.AssertExpectations(t)
+
+		channelID, callID, userID := setupCall(t)
+
+		err := store.CreateCallJob(&public.CallJob{
+			ID:        model.NewId(),
+			CallID:    callID,
+			Type:      public.JobTypeRecording,
+			CreatorID: userID,
+			InitAt:    45,
+			StartAt:   45,
+			Props: public.CallJobProps{
+				Anonymized: true,
+			},
+		})
+		require.NoError(t, err)
+
+		mockAPI.On("KVGet", recordingPseudonymsKeyPrefix+callID).Return(nil, nil).Once()
+		mockAPI.On("KVSet", mock.AnythingOfType("string"), []byte(callID)).Return(nil).Once()
+
+		profiles := getProfiles(t, channelID, []string{userID})
+		require.Len(t, profiles, 1)
+		require.NotEqual(t, userID, profiles[userID].Id)
+		require.Equal(t, "participant1", profiles[userID].Username)
+		require.Equal(t, "Participant 1", profiles[userID].GetFullName())
+
+		// The real profile is never resolved.
+		mockAPI.AssertNotCalled(t, "GetUser", userID)
+	})
+}
@@ -0,0 +1,131 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mattermost/mattermost-plugin-calls/server/public"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+const (
+	maxCallChatMessageLen = 1024
+	// Participants can send a message per second on average, with short
+	// bursts allowed.
+	chatMsgRateLimit = 1
+	chatMsgBurst     = 5
+)
+
+type callChatMessageData struct {
+	Message string `json:"message"`
+}
+
+// handleCallChatMessage relays an in-call chat message to all the call
+// participants and, if configured, posts it to the call thread.
+func (p *Plugin) handleCallChatMessage(us *session, msg clientMessage) error {
+	cfg := p.getConfiguration()
+	if !cfg.callChatEnabled() {
+		return fmt.Errorf("call chat is not enabled")
+	}
+
+	if !us.chatMsgLimiter.Allow() {
+		return fmt.Errorf("chat message was dropped by rate limiter")
+	}
+
+	var data callChatMessageData
+	if err := json.Unmarshal(msg.Data, &data); err != nil {
+		return fmt.Errorf("failed to unmarshal chat data: %w", err)
+	}
+
+	message := strings.TrimSpace(data.Message)
+	if message == "" {
+		return fmt.Errorf("chat message should not be empty")
+	}
+	if utf8.RuneCountInString(message) > maxCallChatMessageLen {
+		return fmt.Errorf("chat message is too long: should be at most %d characters", maxCallChatMessageLen)
+	}
+
+	// Messages are handled while holding the call lock so that they are relayed
+	// and posted in the same order across the cluster.
+	channelID := us.getChannelID()
+	state, err := p.lockCallReturnState(channelID)
+	if err != nil {
+		return fmt.Errorf("failed to lock call: %w", err)
+	}
+	defer p.unlockCall(channelID)
+
+	if state == nil || state.Call.ID != us.callID {
+		return fmt.Errorf("no call ongoing")
+	}
+
+	if state.sessions[us.originalConnID] == nil {
+		return fmt.Errorf("user session is missing from call state")
+	}
+
+	if state.Call.IsFeatureDisabled(public.CallFeatureChat) {
+		return fmt.Errorf("chat is disabled for this call")
+	}
+
+	createAt := time.Now().UnixMilli()
+
+	// Messages from users who cannot post in the channel (read-only channels)
+	// are only relayed.
+	var postID string
+	if cfg.callChatPostToThread() && p.API.HasPermissionToChannel(us.userID, us.getChannelID(), model.PermissionCreatePost) {
+		postID, err = p.createCallChatPost(state, us.userID, message, createAt)
+		if err != nil {
+			p.LogError("failed to create chat post", "err", err.Error(), "callID", us.callID, "userID", us.userID)
+		}
+	}
+
+	p.publishWebSocketEvent(wsEventCallChatMessage, map[string]interface{}{
+		"channel_id": us.getChannelID(),
+		"call_id":    us.callID,
+		"user_id":    us.userID,
+		"session_id": us.originalConnID,
+		"message":    message,
+		"create_at":  createAt,
+		"post_id":    postID,
+	}, &WebSocketBroadcast{
+		ChannelID:           us.getChannelID(),
+		ReliableClusterSend: true,
+		UserIDs:             getUserIDsFromSessions(state.sessions),
+	})
+
+	return nil
+}
+
+// createCallChatPost posts the chat message to the call thread as the user who
+// sent it so that it's subject to the same rules as any of their posts.
+func (p *Plugin) createCallChatPost(state *callState, userID, message string, createAt int64) (string, error) {
+	post := &model.Post{
+		UserId:    userID,
+		ChannelId: state.Call.ChannelID,
+		RootId:    state.Call.ThreadID,
+		// Setting the creation time explicitly so that posts are ordered
+		// consistently with the relayed messages.
+		CreateAt: createAt,
+		Message:  message,
+	}
+	post.AddProp("call_id", state.Call.ID)
+
+	// Mirroring what the server does for posts made through the API, as
+	// plugins are otherwise free to notify the whole channel.
+	if !p.API.HasPermissionToChannel(userID, state.Call.ChannelID, model.PermissionUseChannelMentions) {
+		post.AddProp(model.PostPropsMentionHighlightDisabled, true)
+	}
+
+	createdPost, appErr := p.API.CreatePost(post)
+	if appErr != nil {
+		return "", fmt.Errorf("failed to create post: %w", appErr)
+	}
+
+	return createdPost.Id, nil
+}
@@ -0,0 +1,227 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-calls/server/cluster"
+	"github.com/mattermost/mattermost-plugin-calls/server/public"
+
+	serverMocks "github.com/mattermost/mattermost-plugin-calls/server/mocks/github.com/mattermost/mattermost-plugin-calls/server/interfaces"
+	pluginMocks "github.com/mattermost/mattermost-plugin-calls/server/mocks/github.com/mattermost/mattermost/server/public/plugin"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+)
+
+func newChatClientMessage(t *testing.T, message string) clientMessage {
+	t.Helper()
+	data, err := json.Marshal(callChatMessageData{Message: message})
+	require.NoError(t, err)
+	return clientMessage{Type: clientMessageTypeChat, Data: data}
+}
+
+func TestHandleCallChatMessageValidation(t *testing.T) {
+	p := &Plugin{}
+
+	cfg := &configuration{}
+	cfg.SetDefaults()
+	p.configuration = cfg
+
+	us := newUserSession(model.NewId(), model.NewId(), model.NewId(), model.NewId(), true)
+
+	t.Run("disabled", func(t *testing.T) {
+		err := p.handleCallChatMessage(us, newChatClientMessage(t, "hello"))
+		require.EqualError(t, err, "call chat is not enabled")
+	})
+
+	cfg.EnableCallChat = model.NewPointer(true)
+
+	t.Run("invalid data", func(t *testing.T) {
+		err := p.handleCallChatMessage(us, clientMessage{Type: clientMessageTypeChat, Data: []byte("{")})
+		require.ErrorContains(t, err, "failed to unmarshal chat data")
+	})
+
+	t.Run("empty message", func(t *testing.T) {
+		err := p.handleCallChatMessage(us, newChatClientMessage(t, "  \n "))
+		require.EqualError(t, err, "chat message should not be empty")
+	})
+
+	t.Run("too long", func(t *testing.T) {
+		err := p.handleCallChatMessage(us, newChatClientMessage(t, strings.Repeat("a", maxCallChatMessageLen+1)))
+		require.EqualError(t, err, "chat message is too long: should be at most 1024 characters")
+	})
+
+	t.Run("rate limited", func(t *testing.T) {
+		us := newUserSession(model.NewId(), model.NewId(), model.NewId(), model.NewId(), true)
+		us.chatMsgLimiter = rate.NewLimiter(0, 0)
+		err := p.handleCallChatMessage(us, newChatClientMessage(t, "hello"))
+		require.EqualError(t, err, "chat message was dropped by rate limiter")
+	})
+}
+
+func TestHandleCallChatMessage(t *testing.T) {
+	mockAPI := &pluginMocks.MockAPI{}
+	mockMetrics := &serverMocks.MockMetrics{}
+
+	defer mockAPI.AssertExpectations(t)
+	defer mockMetrics.AssertExpectations(t)
+
+	botID := model.NewId()
+	p := Plugin{
+		MattermostPlugin: plugin.MattermostPlugin{
+			API: mockAPI,
+		},
+		callsClusterLocks: map[string]*cluster.Mutex{},
+		metrics:           mockMetrics,
+		botSession:        &model.Session{UserId: botID},
+	}
+
+	cfg := &configuration{}
+	cfg.SetDefaults()
+	cfg.EnableCallChat = model.NewPointer(true)
+	p.configuration = cfg
+
+	store, tearDown := NewTestStore(t)
+	t.Cleanup(tearDown)
+	p.store = store
+
+	mockAPI.On("LogDebug", mock.Anything, mock.Anything, mock.Anything,
+		mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+		mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mockAPI.On("KVSetWithOptions", mock.Anything, mock.Anything, mock.Anything).Return(true, nil)
+	mockMetrics.On("ObserveClusterMutexGrabTime", "mutex_call", mock.AnythingOfType("float64"))
+	mockMetrics.On("ObserveClusterMutexLockedTime", "mutex_call", mock.AnythingOfType("float64"))
+	mockMetrics.On("ObserveAppHandlersTime", mock.AnythingOfType("string"), mock.AnythingOfType("float64"))
+
+	channelID := model.NewId()
+	userID := model.NewId()
+	connID := model.NewId()
+
+	call := &public.Call{
+		ID:        model.NewId(),
+		CreateAt:  time.Now().UnixMilli(),
+		ChannelID: channelID,
+		StartAt:   time.Now().UnixMilli(),
+		PostID:    model.NewId(),
+		ThreadID:  model.NewId(),
+		OwnerID:   userID,
+	}
+
+	us := newUserSession(userID, channelID, connID, call.ID, true)
+
+	t.Run("no call ongoing", func(t *testing.T) {
+		mockAPI.On("KVDelete", "mutex_call_"+channelID).Return(nil).Once()
+		err := p.handleCallChatMessage(us, newChatClientMessage(t, "hello"))
+		require.EqualError(t, err, "no call ongoing")
+	})
+
+	err := p.store.CreateCall(call)
+	require.NoError(t, err)
+
+	t.Run("session missing", func(t *testing.T) {
+		mockAPI.On("KVDelete", "mutex_call_"+channelID).Return(nil).Once()
+		err := p.handleCallChatMessage(us, newChatClientMessage(t, "hello"))
+		require.EqualError(t, err, "user session is missing from call state")
+	})
+
+	err = p.store.CreateCallSession(&public.CallSession{
+		ID:     connID,
+		CallID: call.ID,
+		UserID: userID,
+		JoinAt: time.Now().UnixMilli(),
+	})
+	require.NoError(t, err)
+
+	t.Run("relayed and posted", func(t *testing.T) {
+		mockAPI.On("KVDelete", "mutex_call_"+channelID).Return(nil).Once()
+		mockAPI.On("HasPermissionToChannel", userID, channelID, model.PermissionCreatePost).Return(true).Once()
+		mockAPI.On("HasPermissionToChannel", userID, channelID, model.PermissionUseChannelMentions).Return(true).Once()
+
+		var createAt int64
+		mockAPI.On("CreatePost", mock.MatchedBy(func(post *model.Post) bool {
+			createAt = post.CreateAt
+			return post.UserId == userID && post.ChannelId == channelID && post.RootId == call.ThreadID &&
+				post.Message == "hello" && post.GetProp("call_id") == call.ID &&
+				post.GetProp(model.PostPropsMentionHighlightDisabled) == nil
+		})).Return(&model.Post{Id: "postID"}, nil).Once()
+
+		mockMetrics.On("IncWebSocketEvent", "out", wsEventCallChatMessage).Twice()
+		mockAPI.On("PublishWebSocketEvent", wsEventCallChatMessage, mock.Anything, &model.WebsocketBroadcast{UserId: botID}).Once()
+		mockAPI.On("PublishWebSocketEvent", wsEventCallChatMessage, mock.MatchedBy(func(data map[string]any) bool {
+			return data["message"] == "hello" && data["post_id"] == "postID" && data["create_at"] == createAt &&
+				data["session_id"] == connID
+		}), mock.MatchedBy(func(broadcast *model.WebsocketBroadcast) bool {
+			return broadcast.UserId == userID
+		})).Once()
+
+		err := p.handleCallChatMessage(us, newChatClientMessage(t, " hello "))
+		require.NoError(t, err)
+	})
+
+	t.Run("no channel mentions permission", func(t *testing.T) {
+		mockAPI.On("KVDelete", "mutex_call_"+channelID).Return(nil).Once()
+		mockAPI.On("HasPermissionToChannel", userID, channelID, model.PermissionCreatePost).Return(true).Once()
+		mockAPI.On("HasPermissionToChannel", userID, channelID, model.PermissionUseChannelMentions).Return(false).Once()
+		mockAPI.On("CreatePost", mock.MatchedBy(func(post *model.Post) bool {
+			return post.UserId == userID && post.Message == "@channel hello" &&
+				post.GetProp(model.PostPropsMentionHighlightDisabled) == true
+		})).Return(&model.Post{Id: "postID"}, nil).Once()
+
+		mockMetrics.On("IncWebSocketEvent", "out", wsEventCallChatMessage).Twice()
+		mockAPI.On("PublishWebSocketEvent", wsEventCallChatMessage, mock.Anything, &model.WebsocketBroadcast{UserId: botID}).Once()
+		mockAPI.On("PublishWebSocketEvent", wsEventCallChatMessage, mock.MatchedBy(func(data map[string]any) bool {
+			return data["message"] == "@channel hello" && data["post_id"] == "postID"
+		}), mock.Anything).Once()
+
+		err := p.handleCallChatMessage(us, newChatClientMessage(t, "@channel hello"))
+		require.NoError(t, err)
+	})
+
+	t.Run("relayed only", func(t *testing.T) {
+		cfg.CallChatPostToThread = model.NewPointer(false)
+		defer func() { cfg.CallChatPostToThread = model.NewPointer(true) }()
+
+		mockAPI.On("KVDelete", "mutex_call_"+channelID).Return(nil).Once()
+		mockMetrics.On("IncWebSocketEvent", "out", wsEventCallChatMessage).Twice()
+		mockAPI.On("PublishWebSocketEvent", wsEventCallChatMessage, mock.Anything, &model.WebsocketBroadcast{UserId: botID}).Once()
+		mockAPI.On("PublishWebSocketEvent", wsEventCallChatMessage, mock.MatchedBy(func(data map[string]any) bool {
+			return data["message"] == "no post" && data["post_id"] == ""
+		}), mock.Anything).Once()
+
+		err := p.handleCallChatMessage(us, newChatClientMessage(t, "no post"))
+		require.NoError(t, err)
+	})
+
+	t.Run("read-only channel", func(t *testing.T) {
+		mockAPI.On("KVDelete", "mutex_call_"+channelID).Return(nil).Once()
+		mockAPI.On("HasPermissionToChannel", userID, channelID, model.PermissionCreatePost).Return(false).Once()
+		mockMetrics.On("IncWebSocketEvent", "out", wsEventCallChatMessage).Twice()
+		mockAPI.On("PublishWebSocketEvent", wsEventCallChatMessage, mock.Anything, &model.WebsocketBroadcast{UserId: botID}).Once()
+		mockAPI.On("PublishWebSocketEvent", wsEventCallChatMessage, mock.MatchedBy(func(data map[string]any) bool {
+			return data["message"] == "read only" && data["post_id"] == ""
+		}), mock.Anything).Once()
+
+		err := p.handleCallChatMessage(us, newChatClientMessage(t, "read only"))
+		require.NoError(t, err)
+	})
+
+	t.Run("disabled for the call", func(t *testing.T) {
+		call.Props.DisabledFeatures = []string{public.CallFeatureChat}
+		err := p.store.UpdateCall(call)
+		require.NoError(t, err)
+
+		mockAPI.On("KVDelete", "mutex_call_"+channelID).Return(nil).Once()
+		err = p.handleCallChatMessage(us, newChatClientMessage(t, "hello"))
+		require.EqualError(t, err, "chat is disabled for this call")
+	})
+}
@@ -0,0 +1,284 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-calls/server/public"
+
+	"github.com/gorilla/mux"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+const (
+	callDataKeyPrefix = "call_data_"
+	// Long enough to outlive any call, the data is keyed by call so it's
+	// never picked up by a later one.
+	callDataKeyTTL = 24 * time.Hour
+	// The maximum number of payloads that can be attached to a single call,
+	// across all plugins.
+	callDataMaxItems = 16
+)
+
+var (
+	errCallDataNotFound     = errors.New("call data not found")
+	errCallDataLimitReached = fmt.Errorf("too many data payloads attached to the call: should not be more than %d", callDataMaxItems)
+)
+
+func callDataItemKey(pluginID, name string) string {
+	return pluginID + "/" + name
+}
+
+func (p *Plugin) getCallData(callID string) (map[string]public.CallData, error) {
+	data, appErr := p.API.KVGet(callDataKeyPrefix + callID)
+	if appErr != nil {
+		return nil, fmt.Errorf("failed to get call data: %w", appErr)
+	}
+
+	items := map[string]public.CallData{}
+	if data == nil {
+		return items, nil
+	}
+
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal call data: %w", err)
+	}
+
+	return items, nil
+}
+
+func (p *Plugin) setCallData(callID string, items map[string]public.CallData) error {
+	if len(items) == 0 {
+		if appErr := p.API.KVDelete(callDataKeyPrefix + callID); appErr != nil {
+			return fmt.Errorf("failed to delete call data: %w", appErr)
+		}
+		return nil
+	}
+
+	data, err := json.Marshal(items)
+	if err != nil {
+		return fmt.Errorf("failed to marshal call data: %w", err)
+	}
+
+	if appErr := p.API.KVSetWithExpiry(callDataKeyPrefix+callID, data, int64(callDataKeyTTL.Seconds())); appErr != nil {
+		return fmt.Errorf("failed to set call data: %w", appErr)
+	}
+
+	return nil
+}
+
+// sortCallData returns the given payloads in a stable order so that clients
+// render them consistently.
+func sortCallData(items map[string]public.CallData) []public.CallData {
+	list := make([]public.CallData, 0, len(items))
+	for _, item := range items {
+		list = append(list, item)
+	}
+	slices.SortFunc(list, func(a, b public.CallData) int {
+		return cmp.Or(cmp.Compare(a.PluginID, b.PluginID), cmp.Compare(a.Name, b.Name))
+	})
+	return list
+}
+
+// putCallData attaches the given payload to the ongoing call in the channel,
+// replacing any previous payload with the same name from the same plugin,
+// and relays it to the participants.
+func (p *Plugin) putCallData(channelID string, item public.CallData) error {
+	state, err := p.lockCallReturnState(channelID)
+	if err != nil {
+		return fmt.Errorf("failed to lock call: %w", err)
+	}
+	defer p.unlockCall(channelID)
+
+	if state == nil {
+		return ErrNoCallOngoing
+	}
+
+	items, err := p.getCallData(state.Call.ID)
+	if err != nil {
+		return err
+	}
+
+	key := callDataItemKey(item.PluginID, item.Name)
+	if _, ok := items[key]; !ok && len(items) >= callDataMaxItems {
+		return errCallDataLimitReached
+	}
+
+	item.UpdateAt = time.Now().UnixMilli()
+	items[key] = item
+
+	if err := p.setCallData(state.Call.ID, items); err != nil {
+		return err
+	}
+
+	p.publishWebSocketEvent(wsEventCallData, map[string]interface{}{
+		"call_id":    state.Call.ID,
+		"channel_id": channelID,
+		"plugin_id":  item.PluginID,
+		"name":       item.Name,
+		"data":       string(item.Data),
+		"update_at":  item.UpdateAt,
+	}, &WebSocketBroadcast{
+		ChannelID:           channelID,
+		ReliableClusterSend: true,
+		UserIDs:             getUserIDsFromSessions(state.sessions),
+	})
+
+	return nil
+}
+
+// removeCallData detaches the named payload set by the given plugin from the
+// ongoing call in the channel.
+func (p *Plugin) removeCallData(channelID, pluginID, name string) error {
+	state, err := p.lockCallReturnState(channelID)
+	if err != nil {
+		return fmt.Errorf("failed to lock call: %w", err)
+	}
+	defer p.unlockCall(channelID)
+
+	if state == nil {
+		return ErrNoCallOngoing
+	}
+
+	items, err := p.getCallData(state.Call.ID)
+	if err != nil {
+		return err
+	}
+
+	key := callDataItemKey(pluginID, name)
+	if _, ok := items[key]; !ok {
+		return errCallDataNotFound
+	}
+	delete(items, key)
+
+	if err := p.setCallData(state.Call.ID, items); err != nil {
+		return err
+	}
+
+	p.publishWebSocketEvent(wsEventCallDataRemoved, map[string]interface{}{
+		"call_id":    state.Call.ID,
+		"channel_id": channelID,
+		"plugin_id":  pluginID,
+		"name":       name,
+	}, &WebSocketBroadcast{
+		ChannelID:           channelID,
+		ReliableClusterSend: true,
+		UserIDs:             getUserIDsFromSessions(state.sessions),
+	})
+
+	return nil
+}
+
+func (p *Plugin) handleInterPluginPutCallData(w http.ResponseWriter, r *http.Request) {
+	var res httpResponse
+	defer p.httpAudit("handleInterPluginPutCallData", &res, w, r)
+
+	channelID := mux.Vars(r)["channel_id"]
+
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, public.CallDataMaxSizeBytes))
+	if err != nil {
+		res.Err = "failed to read request body: " + err.Error()
+		res.Code = http.StatusBadRequest
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			res.Code = http.StatusRequestEntityTooLarge
+		}
+		return
+	}
+
+	item := public.CallData{
+		PluginID: r.Header.Get("Mattermost-Plugin-ID"),
+		Name:     mux.Vars(r)["name"],
+		Data:     data,
+	}
+	if err := item.IsValid(); err != nil {
+		res.Err = err.Error()
+		res.Code = http.StatusBadRequest
+		return
+	}
+
+	if err := p.putCallData(channelID, item); err != nil {
+		res.Err = err.Error()
+		switch {
+		case errors.Is(err, ErrNoCallOngoing):
+			res.Code = http.StatusBadRequest
+		case errors.Is(err, errCallDataLimitReached):
+			res.Code = http.StatusForbidden
+		default:
+			res.Code = http.StatusInternalServerError
+		}
+		return
+	}
+
+	res.Code = http.StatusOK
+	res.Msg = "success"
+}
+
+func (p *Plugin) handleInterPluginDeleteCallData(w http.ResponseWriter, r *http.Request) {
+	var res httpResponse
+	defer p.httpAudit("handleInterPluginDeleteCallData", &res, w, r)
+
+	channelID := mux.Vars(r)["channel_id"]
+	pluginID := r.Header.Get("Mattermost-Plugin-ID")
+
+	if err := p.removeCallData(channelID, pluginID, mux.Vars(r)["name"]); err != nil {
+		res.Err = err.Error()
+		switch {
+		case errors.Is(err, ErrNoCallOngoing):
+			res.Code = http.StatusBadRequest
+		case errors.Is(err, errCallDataNotFound):
+			res.Code = http.StatusNotFound
+		default:
+			res.Code = http.StatusInternalServerError
+		}
+		return
+	}
+
+	res.Code = http.StatusOK
+	res.Msg = "success"
+}
+
+// handleGetCallData returns the data payloads attached to the ongoing call in
+// the channel so that clients joining late can render them.
+func (p *Plugin) handleGetCallData(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("Mattermost-User-Id")
+	channelID := mux.Vars(r)["channel_id"]
+
+	if !p.API.HasPermissionToChannel(userID, channelID, model.PermissionReadChannel) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	state, err := p.getCallState(channelID, false)
+	if err != nil {
+		p.LogError(err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	items := []public.CallData{}
+	if state != nil {
+		data, err := p.getCallData(state.Call.ID)
+		if err != nil {
+			p.LogError(err.Error())
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		items = sortCallData(data)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(items); err != nil {
+		p.LogError(err.Error())
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/mattermost/mattermost-plugin-calls/server/public"
+
+	pluginMocks "github.com/mattermost/mattermost-plugin-calls/server/mocks/github.com/mattermost/mattermost/server/public/plugin"
+
+	"github.com/mattermost/mattermost/server/public/plugin"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCallDataStore(t *testing.T) {
+	mockAPI := &pluginMocks.MockAPI{}
+	defer mockAPI.AssertExpectations(t)
+
+	p := &Plugin{
+		MattermostPlugin: plugin.MattermostPlugin{
+			API: mockAPI,
+		},
+	}
+
+	key := callDataKeyPrefix + "callID"
+
+	t.Run("empty", func(t *testing.T) {
+		mockAPI.On("KVGet", key).Return(nil, nil).Once()
+		items, err := p.getCallData("callID")
+		require.NoError(t, err)
+		require.Empty(t, items)
+	})
+
+	t.Run("set and get", func(t *testing.T) {
+		items := map[string]public.CallData{
+			callDataItemKey("pluginB", "agenda"): {PluginID: "pluginB", Name: "agenda", Data: json.RawMessage(`{"items":["intro"]}`)},
+			callDataItemKey("pluginA", "doc"):    {PluginID: "pluginA", Name: "doc", Data: json.RawMessage(`"https://example.com"`)},
+			callDataItemKey("pluginA", "agenda"): {PluginID: "pluginA", Name: "agenda", Data: json.RawMessage(`[]`)},
+		}
+
+		var data []byte
+		mockAPI.On("KVSetWithExpiry", key, mock.Anything, int64(callDataKeyTTL.Seconds())).Run(func(args mock.Arguments) {
+			data = args.Get(1).([]byte)
+		}).Return(nil).Once()
+		require.NoError(t, p.setCallData("callID", items))
+
+		mockAPI.On("KVGet", key).Return(data, nil).Once()
+		stored, err := p.getCallData("callID")
+		require.NoError(t, err)
+		require.Equal(t, items, stored)
+
+		sorted := sortCallData(stored)
+		require.Len(t, sorted, 3)
+		require.Equal(t, "pluginA", sorted[0].PluginID)
+		require.Equal(t, "agenda", sorted[0].Name)
+		require.Equal(t, "pluginA", sorted[1].PluginID)
+		require.Equal(t, "doc", sorted[1].Name)
+		require.Equal(t, "pluginB", sorted[2].PluginID)
+	})
+
+	t.Run("removing the last item", func(t *testing.T) {
+		mockAPI.On("KVDelete", key).Return(nil).Once()
+		require.NoError(t, p.setCallData("callID", map[string]public.CallData{}))
+	})
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-calls/server/public"
+)
+
+// getCallEndWarningDelay returns how long to wait, from the start of a
+// timeout, before warning that the call is about to end. It returns false if
+// no warning should be sent because the timeout is too short for it.
+func getCallEndWarningDelay(timeout, warning time.Duration) (time.Duration, bool) {
+	if warning <= 0 || warning >= timeout {
+		return 0, false
+	}
+	return timeout - warning, true
+}
+
+// publishCallEndWarning lets users know the call is going to be ended
+// automatically at the given time unless there's some activity.
+func (p *Plugin) publishCallEndWarning(callID, channelID string, reason public.CallEndReason, endAt time.Time) {
+	p.LogDebug("call is about to end, warning users", "channelID", channelID, "callID", callID, "reason", string(reason))
+
+	p.publishWebSocketEvent(wsEventCallEndWarning, map[string]interface{}{
+		"call_id":    callID,
+		"channel_id": channelID,
+		"reason":     string(reason),
+		"end_at":     endAt.UnixMilli(),
+	}, &WebSocketBroadcast{ChannelID: channelID, ReliableClusterSend: true})
+}
+
+// publishCallEndWarningCanceled lets users know a previous warning no longer
+// applies since the call was kept going.
+func (p *Plugin) publishCallEndWarningCanceled(callID, channelID string) {
+	p.publishWebSocketEvent(wsEventCallEndWarningCanceled, map[string]interface{}{
+		"call_id":    callID,
+		"channel_id": channelID,
+	}, &WebSocketBroadcast{ChannelID: channelID, ReliableClusterSend: true})
+}
+
+// warnEmptyCallEnd waits until it's time to warn that the given empty call
+// is about to end, and does so if nobody rejoined it in the meantime. It
+// returns false if the plugin is stopping.
+func (p *Plugin) warnEmptyCallEnd(channelID, callID string, leftAt int64, gracePeriod time.Duration) bool {
+	delay, ok := getCallEndWarningDelay(gracePeriod, p.getConfiguration().getCallEndWarning())
+	if !ok {
+		return true
+	}
+
+	select {
+	case <-time.After(time.Until(time.UnixMilli(leftAt).Add(delay))):
+	case <-p.stopCh:
+		return false
+	}
+
+	state, err := p.lockCallReturnState(channelID)
+	if err != nil {
+		p.LogError("failed to lock call", "err", err.Error(), "channelID", channelID, "callID", callID)
+		return true
+	}
+	defer p.unlockCall(channelID)
+
+	if state.recordingGracePeriodActive(callID, leftAt, p.getBotID()) {
+		p.publishCallEndWarning(callID, channelID, public.CallEndReasonEmptyTimeout, time.UnixMilli(leftAt).Add(gracePeriod))
+	}
+
+	return true
+}
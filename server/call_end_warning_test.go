@@ -0,0 +1,115 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-calls/server/public"
+
+	serverMocks "github.com/mattermost/mattermost-plugin-calls/server/mocks/github.com/mattermost/mattermost-plugin-calls/server/interfaces"
+	pluginMocks "github.com/mattermost/mattermost-plugin-calls/server/mocks/github.com/mattermost/mattermost/server/public/plugin"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetCallEndWarningDelay(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		_, ok := getCallEndWarningDelay(time.Minute, 0)
+		require.False(t, ok)
+	})
+
+	t.Run("timeout too short", func(t *testing.T) {
+		_, ok := getCallEndWarningDelay(30*time.Second, time.Minute)
+		require.False(t, ok)
+
+		_, ok = getCallEndWarningDelay(time.Minute, time.Minute)
+		require.False(t, ok)
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		delay, ok := getCallEndWarningDelay(5*time.Minute, time.Minute)
+		require.True(t, ok)
+		require.Equal(t, 4*time.Minute, delay)
+	})
+}
+
+func TestCallEndWarningRestart(t *testing.T) {
+	newState := func(leftAt int64) *callState {
+		return &callState{
+			Call: public.Call{
+				ID: "callID",
+				Props: public.CallProps{
+					LastParticipantLeftAt: leftAt,
+				},
+			},
+			sessions: map[string]*public.CallSession{
+				"botConnID": {ID: "botConnID", UserID: "botID"},
+			},
+		}
+	}
+
+	t.Run("empty call", func(t *testing.T) {
+		require.True(t, newState(1000).recordingGracePeriodActive("callID", 1000, "botID"))
+	})
+
+	t.Run("rejoined", func(t *testing.T) {
+		// Someone rejoining cancels the pending warning.
+		cs := newState(1000)
+		cs.sessions["connA"] = &public.CallSession{ID: "connA", UserID: "userA"}
+		require.False(t, cs.recordingGracePeriodActive("callID", 1000, "botID"))
+	})
+
+	t.Run("left again", func(t *testing.T) {
+		// Leaving again restarts the timer, so only the new warning applies.
+		cs := newState(2000)
+		require.False(t, cs.recordingGracePeriodActive("callID", 1000, "botID"))
+		require.True(t, cs.recordingGracePeriodActive("callID", 2000, "botID"))
+	})
+}
+
+func TestPublishCallEndWarning(t *testing.T) {
+	mockAPI := &pluginMocks.MockAPI{}
+	mockMetrics := &serverMocks.MockMetrics{}
+	defer mockAPI.AssertExpectations(t)
+	defer mockMetrics.AssertExpectations(t)
+
+	p := &Plugin{
+		MattermostPlugin: plugin.MattermostPlugin{
+			API: mockAPI,
+		},
+		metrics: mockMetrics,
+	}
+
+	mockAPI.On("LogDebug", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+		mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+	t.Run("warning", func(t *testing.T) {
+		endAt := time.Now().Add(time.Minute)
+		mockMetrics.On("IncWebSocketEvent", "out", wsEventCallEndWarning).Once()
+		mockAPI.On("PublishWebSocketEvent", wsEventCallEndWarning, map[string]interface{}{
+			"call_id":    "callID",
+			"channel_id": "channelID",
+			"reason":     "empty-timeout",
+			"end_at":     endAt.UnixMilli(),
+		}, &model.WebsocketBroadcast{ChannelId: "channelID", ReliableClusterSend: true}).Once()
+
+		p.publishCallEndWarning("callID", "channelID", public.CallEndReasonEmptyTimeout, endAt)
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		mockMetrics.On("IncWebSocketEvent", "out", wsEventCallEndWarningCanceled).Once()
+		mockAPI.On("PublishWebSocketEvent", wsEventCallEndWarningCanceled, map[string]interface{}{
+			"call_id":    "callID",
+			"channel_id": "channelID",
+		}, &model.WebsocketBroadcast{ChannelId: "channelID", ReliableClusterSend: true}).Once()
+
+		p.publishCallEndWarningCanceled("callID", "channelID")
+	})
+}
@@ -0,0 +1,89 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/mattermost/mattermost-plugin-calls/server/public"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+// parseCallFeatures returns the sorted and deduplicated list of the given
+// features, failing if any isn't a known one.
+func parseCallFeatures(features []string) ([]string, error) {
+	parsed := []string{}
+	for _, feature := range features {
+		feature = strings.ToLower(strings.TrimSpace(feature))
+		if !slices.Contains(public.CallFeatures, feature) {
+			return nil, fmt.Errorf("%q is not a valid feature", feature)
+		}
+		if !slices.Contains(parsed, feature) {
+			parsed = append(parsed, feature)
+		}
+	}
+	slices.Sort(parsed)
+	return parsed, nil
+}
+
+// setCallFeature turns the given interactive feature on or off for the
+// current call. Clients are expected to hide the matching controls while
+// messages for a disabled feature get rejected.
+func (p *Plugin) setCallFeature(requesterID, channelID, feature string, enabled bool) error {
+	state, err := p.lockCallReturnState(channelID)
+	if err != nil {
+		return fmt.Errorf("failed to lock call: %w", err)
+	}
+	defer p.unlockCall(channelID)
+
+	if state == nil {
+		return ErrNoCallOngoing
+	}
+
+	if requesterID != state.Call.GetHostID() {
+		if isAdmin := p.API.HasPermissionTo(requesterID, model.PermissionManageSystem); !isAdmin {
+			return ErrNoPermissions
+		}
+	}
+
+	if state.Call.IsFeatureDisabled(feature) == !enabled {
+		return nil
+	}
+
+	if enabled {
+		state.Call.Props.DisabledFeatures = slices.DeleteFunc(state.Call.Props.DisabledFeatures, func(f string) bool {
+			return f == feature
+		})
+	} else {
+		state.Call.Props.DisabledFeatures = append(state.Call.Props.DisabledFeatures, feature)
+		slices.Sort(state.Call.Props.DisabledFeatures)
+	}
+
+	if err := p.store.UpdateCall(&state.Call); err != nil {
+		return fmt.Errorf("failed to update call: %w", err)
+	}
+
+	p.publishWebSocketEvent(wsEventCallFeatures, map[string]interface{}{
+		"call_id":           state.Call.ID,
+		"disabled_features": getDisabledCallFeatures(state.Call),
+	}, &WebSocketBroadcast{
+		ChannelID:           channelID,
+		ReliableClusterSend: true,
+		UserIDs:             getUserIDsFromSessions(state.sessions),
+	})
+
+	return nil
+}
+
+// getDisabledCallFeatures returns the features disabled for the given call,
+// never nil so that clients always get a list.
+func getDisabledCallFeatures(call public.Call) []string {
+	if call.Props.DisabledFeatures == nil {
+		return []string{}
+	}
+	return call.Props.DisabledFeatures
+}
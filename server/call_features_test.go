@@ -0,0 +1,230 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-calls/server/cluster"
+	"github.com/mattermost/mattermost-plugin-calls/server/db"
+	"github.com/mattermost/mattermost-plugin-calls/server/public"
+
+	serverMocks "github.com/mattermost/mattermost-plugin-calls/server/mocks/github.com/mattermost/mattermost-plugin-calls/server/interfaces"
+	pluginMocks "github.com/mattermost/mattermost-plugin-calls/server/mocks/github.com/mattermost/mattermost/server/public/plugin"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCallFeatures(t *testing.T) {
+	features, err := parseCallFeatures(nil)
+	require.NoError(t, err)
+	require.Empty(t, features)
+
+	features, err = parseCallFeatures([]string{" Reactions", "raise_hand", "reactions"})
+	require.NoError(t, err)
+	require.Equal(t, []string{public.CallFeatureRaiseHand, public.CallFeatureReactions}, features)
+
+	_, err = parseCallFeatures([]string{"chat", "screen"})
+	require.EqualError(t, err, `"screen" is not a valid feature`)
+}
+
+func TestGetDefaultDisabledCallFeatures(t *testing.T) {
+	var cfg configuration
+	cfg.SetDefaults()
+	require.Empty(t, cfg.getDefaultDisabledCallFeatures())
+
+	cfg.DefaultDisabledCallFeatures = "chat, Reactions,chat,"
+	require.Equal(t, []string{public.CallFeatureChat, public.CallFeatureReactions}, cfg.getDefaultDisabledCallFeatures())
+}
+
+func newCallFeaturesTestPlugin(t *testing.T, store *db.Store) (*Plugin, *pluginMocks.MockAPI, *serverMocks.MockMetrics) {
+	t.Helper()
+
+	mockAPI := &pluginMocks.MockAPI{}
+	mockMetrics := &serverMocks.MockMetrics{}
+
+	t.Cleanup(func() {
+		mockAPI.AssertExpectations(t)
+		mockMetrics.AssertExpectations(t)
+	})
+
+	p := &Plugin{
+		MattermostPlugin: plugin.MattermostPlugin{
+			API: mockAPI,
+		},
+		callsClusterLocks: map[string]*cluster.Mutex{},
+		metrics:           mockMetrics,
+		botSession:        &model.Session{UserId: model.NewId()},
+		store:             store,
+	}
+
+	cfg := &configuration{}
+	cfg.SetDefaults()
+	p.configuration = cfg
+
+	mockAPI.On("LogDebug", mock.Anything, mock.Anything, mock.Anything,
+		mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+		mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mockAPI.On("KVSetWithOptions", mock.Anything, mock.Anything, mock.Anything).Return(true, nil)
+	mockMetrics.On("ObserveClusterMutexGrabTime", "mutex_call", mock.AnythingOfType("float64"))
+	mockMetrics.On("ObserveClusterMutexLockedTime", "mutex_call", mock.AnythingOfType("float64"))
+	mockMetrics.On("ObserveAppHandlersTime", mock.AnythingOfType("string"), mock.AnythingOfType("float64"))
+
+	return p, mockAPI, mockMetrics
+}
+
+func TestSetCallFeature(t *testing.T) {
+	store, tearDown := NewTestStore(t)
+	t.Cleanup(tearDown)
+	p, mockAPI, mockMetrics := newCallFeaturesTestPlugin(t, store)
+
+	channelID := model.NewId()
+	hostID := model.NewId()
+	userID := model.NewId()
+
+	t.Run("no call ongoing", func(t *testing.T) {
+		mockAPI.On("KVDelete", "mutex_call_"+channelID).Return(nil).Once()
+		err := p.setCallFeature(hostID, channelID, public.CallFeatureChat, false)
+		require.ErrorIs(t, err, ErrNoCallOngoing)
+	})
+
+	call := &public.Call{
+		ID:        model.NewId(),
+		CreateAt:  time.Now().UnixMilli(),
+		ChannelID: channelID,
+		StartAt:   time.Now().UnixMilli(),
+		PostID:    model.NewId(),
+		ThreadID:  model.NewId(),
+		OwnerID:   hostID,
+		Props: public.CallProps{
+			Hosts: []string{hostID},
+		},
+	}
+	err := p.store.CreateCall(call)
+	require.NoError(t, err)
+
+	err = p.store.CreateCallSession(&public.CallSession{
+		ID:     model.NewId(),
+		CallID: call.ID,
+		UserID: hostID,
+		JoinAt: time.Now().UnixMilli(),
+	})
+	require.NoError(t, err)
+
+	t.Run("not host", func(t *testing.T) {
+		mockAPI.On("KVDelete", "mutex_call_"+channelID).Return(nil).Once()
+		mockAPI.On("HasPermissionTo", userID, model.PermissionManageSystem).Return(false).Once()
+		err := p.setCallFeature(userID, channelID, public.CallFeatureChat, false)
+		require.ErrorIs(t, err, ErrNoPermissions)
+	})
+
+	t.Run("disable", func(t *testing.T) {
+		mockAPI.On("KVDelete", "mutex_call_"+channelID).Return(nil).Once()
+		mockMetrics.On("IncWebSocketEvent", "out", wsEventCallFeatures).Twice()
+		mockAPI.On("PublishWebSocketEvent", wsEventCallFeatures, map[string]any{
+			"call_id":           call.ID,
+			"disabled_features": []string{public.CallFeatureReactions},
+		}, mock.Anything).Twice()
+
+		err := p.setCallFeature(hostID, channelID, public.CallFeatureReactions, false)
+		require.NoError(t, err)
+
+		dbCall, err := p.store.GetCall(call.ID, db.GetCallOpts{FromWriter: true})
+		require.NoError(t, err)
+		require.Equal(t, []string{public.CallFeatureReactions}, dbCall.Props.DisabledFeatures)
+	})
+
+	t.Run("already disabled", func(t *testing.T) {
+		mockAPI.On("KVDelete", "mutex_call_"+channelID).Return(nil).Once()
+		err := p.setCallFeature(hostID, channelID, public.CallFeatureReactions, false)
+		require.NoError(t, err)
+	})
+
+	t.Run("enable", func(t *testing.T) {
+		mockAPI.On("KVDelete", "mutex_call_"+channelID).Return(nil).Once()
+		mockMetrics.On("IncWebSocketEvent", "out", wsEventCallFeatures).Twice()
+		mockAPI.On("PublishWebSocketEvent", wsEventCallFeatures, map[string]any{
+			"call_id":           call.ID,
+			"disabled_features": []string{},
+		}, mock.Anything).Twice()
+
+		err := p.setCallFeature(hostID, channelID, public.CallFeatureReactions, true)
+		require.NoError(t, err)
+
+		dbCall, err := p.store.GetCall(call.ID, db.GetCallOpts{FromWriter: true})
+		require.NoError(t, err)
+		require.Empty(t, dbCall.Props.DisabledFeatures)
+	})
+}
+
+func TestCallFeaturesEnforcement(t *testing.T) {
+	store, tearDown := NewTestStore(t)
+	t.Cleanup(tearDown)
+	p, mockAPI, mockMetrics := newCallFeaturesTestPlugin(t, store)
+
+	channelID := model.NewId()
+	userID := model.NewId()
+	connID := model.NewId()
+
+	call := &public.Call{
+		ID:        model.NewId(),
+		CreateAt:  time.Now().UnixMilli(),
+		ChannelID: channelID,
+		StartAt:   time.Now().UnixMilli(),
+		PostID:    model.NewId(),
+		ThreadID:  model.NewId(),
+		OwnerID:   userID,
+		Props: public.CallProps{
+			DisabledFeatures: []string{public.CallFeatureRaiseHand, public.CallFeatureReactions},
+		},
+	}
+	err := p.store.CreateCall(call)
+	require.NoError(t, err)
+
+	err = p.store.CreateCallSession(&public.CallSession{
+		ID:     connID,
+		CallID: call.ID,
+		UserID: userID,
+		JoinAt: time.Now().UnixMilli(),
+	})
+	require.NoError(t, err)
+
+	us := newUserSession(userID, channelID, connID, call.ID, true)
+
+	t.Run("raise hand", func(t *testing.T) {
+		mockMetrics.On("IncWebSocketEvent", "in", clientMessageTypeRaiseHand).Once()
+		mockAPI.On("KVDelete", "mutex_call_"+channelID).Return(nil).Once()
+		err := p.handleClientMsg(us, clientMessage{Type: clientMessageTypeRaiseHand}, p.nodeID)
+		require.EqualError(t, err, "raise hand is disabled for this call")
+	})
+
+	t.Run("reactions", func(t *testing.T) {
+		mockMetrics.On("IncWebSocketEvent", "in", clientMessageTypeReact).Once()
+		err := p.handleClientMsg(us, clientMessage{
+			Type: clientMessageTypeReact,
+			Data: []byte(`{"name":"smile","unified":"1f604"}`),
+		}, p.nodeID)
+		require.EqualError(t, err, "reactions are disabled for this call")
+	})
+
+	call.Props.DisabledFeatures = nil
+	err = p.store.UpdateCall(call)
+	require.NoError(t, err)
+
+	t.Run("reactions enabled", func(t *testing.T) {
+		mockMetrics.On("IncWebSocketEvent", "in", clientMessageTypeReact).Once()
+		mockMetrics.On("IncWebSocketEvent", "out", wsEventUserReacted).Twice()
+		mockAPI.On("PublishWebSocketEvent", wsEventUserReacted, mock.Anything, mock.Anything).Twice()
+		err := p.handleClientMsg(us, clientMessage{
+			Type: clientMessageTypeReact,
+			Data: []byte(`{"name":"smile","unified":"1f604"}`),
+		}, p.nodeID)
+		require.NoError(t, err)
+	})
+}
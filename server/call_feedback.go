@@ -0,0 +1,197 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/mattermost/mattermost-plugin-calls/server/db"
+	"github.com/mattermost/mattermost-plugin-calls/server/public"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+const (
+	callFeedbackMinScore = 1
+	callFeedbackMaxScore = 5
+)
+
+// callFeedbackSampled returns whether participants of a call that just ended
+// should be asked for feedback.
+func (c *configuration) callFeedbackSampled() bool {
+	if !c.callFeedbackEnabled() || c.CallFeedbackSampleRate == nil {
+		return false
+	}
+	return rand.Intn(100) < *c.CallFeedbackSampleRate
+}
+
+func newCallFeedbackAttachment(callID, text string) *model.SlackAttachment {
+	attachment := &model.SlackAttachment{
+		Text: text,
+	}
+
+	for score := callFeedbackMinScore; score <= callFeedbackMaxScore; score++ {
+		attachment.Actions = append(attachment.Actions, &model.PostAction{
+			Id:   "score" + strconv.Itoa(score),
+			Name: strconv.Itoa(score),
+			Type: model.PostActionTypeButton,
+			Integration: &model.PostActionIntegration{
+				URL: fmt.Sprintf("/plugins/%s/calls/%s/feedback", manifest.Id, callID),
+				Context: map[string]any{
+					"score": score,
+				},
+			},
+		})
+	}
+
+	return attachment
+}
+
+// sendCallFeedbackPrompts asks the participants of the given call, through a
+// direct message from the bot, to rate its quality.
+func (p *Plugin) sendCallFeedbackPrompts(call *public.Call) {
+	botID := p.getBotID()
+
+	for _, userID := range call.Participants {
+		if userID == botID {
+			continue
+		}
+
+		user, appErr := p.API.GetUser(userID)
+		if appErr != nil {
+			p.LogError("failed to get user", "err", appErr.Error(), "userID", userID)
+			continue
+		}
+		if user.IsBot {
+			continue
+		}
+
+		dm, appErr := p.API.GetDirectChannel(userID, botID)
+		if appErr != nil {
+			p.LogError("failed to get dm between user and bot", "err", appErr.Error(), "userID", userID, "botID", botID)
+			continue
+		}
+
+		T := p.getTranslationFunc(user.Locale)
+
+		post := &model.Post{
+			UserId:    botID,
+			ChannelId: dm.Id,
+		}
+		model.ParseSlackAttachment(post, []*model.SlackAttachment{
+			newCallFeedbackAttachment(call.ID, T("app.call.feedback_prompt")),
+		})
+		if _, appErr := p.API.CreatePost(post); appErr != nil {
+			p.LogError("failed to create post", "err", appErr.Error(), "userID", userID)
+		}
+	}
+}
+
+// saveCallFeedback records the score given by a participant. Participants can
+// only rate a call once.
+func (p *Plugin) saveCallFeedback(call *public.Call, userID string, score int) (bool, error) {
+	if err := p.lockCall(call.ChannelID); err != nil {
+		return false, fmt.Errorf("failed to lock call: %w", err)
+	}
+	defer p.unlockCall(call.ChannelID)
+
+	if err := p.flushCallDBWrites(call.ID); err != nil {
+		return false, fmt.Errorf("failed to save buffered writes: %w", err)
+	}
+
+	// Fetching again now that we hold the lock to avoid overwriting
+	// concurrent responses.
+	call, err := p.store.GetCall(call.ID, db.GetCallOpts{FromWriter: true})
+	if err != nil {
+		return false, fmt.Errorf("failed to get call: %w", err)
+	}
+
+	if _, ok := call.Props.Feedback[userID]; ok {
+		return false, nil
+	}
+
+	if call.Props.Feedback == nil {
+		call.Props.Feedback = map[string]int{}
+	}
+	call.Props.Feedback[userID] = score
+
+	if err := p.store.UpdateCall(call); err != nil {
+		return false, fmt.Errorf("failed to update call: %w", err)
+	}
+
+	return true, nil
+}
+
+func (p *Plugin) handlePostCallFeedback(w http.ResponseWriter, r *http.Request) {
+	var res httpResponse
+	defer p.httpAudit("handlePostCallFeedback", &res, w, r)
+
+	userID := r.Header.Get("Mattermost-User-Id")
+	callID := mux.Vars(r)["call_id"]
+
+	var req model.PostActionIntegrationRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, requestBodyMaxSizeBytes)).Decode(&req); err != nil {
+		res.Err = "failed to decode request body: " + err.Error()
+		res.Code = http.StatusBadRequest
+		return
+	}
+
+	score, ok := req.Context["score"].(float64)
+	if !ok || score != float64(int(score)) || score < callFeedbackMinScore || score > callFeedbackMaxScore {
+		res.Err = fmt.Sprintf("invalid score: should be an integer in the range [%d, %d]", callFeedbackMinScore, callFeedbackMaxScore)
+		res.Code = http.StatusBadRequest
+		return
+	}
+
+	call, err := p.store.GetCall(callID, db.GetCallOpts{})
+	if errors.Is(err, db.ErrNotFound) {
+		res.Err = "call not found"
+		res.Code = http.StatusNotFound
+		return
+	} else if err != nil {
+		res.Err = err.Error()
+		res.Code = http.StatusInternalServerError
+		return
+	}
+
+	if call.EndAt == 0 || !slices.Contains(call.Participants, userID) {
+		res.Err = "Forbidden"
+		res.Code = http.StatusForbidden
+		return
+	}
+
+	saved, err := p.saveCallFeedback(call, userID, int(score))
+	if err != nil {
+		res.Err = err.Error()
+		res.Code = http.StatusInternalServerError
+		return
+	}
+	if saved {
+		p.metrics.ObserveCallFeedbackScore(int(score))
+	}
+
+	var locale string
+	if user, appErr := p.API.GetUser(userID); appErr == nil {
+		locale = user.Locale
+	}
+	T := p.getTranslationFunc(locale)
+
+	// Replacing the prompt so that it can't be answered again.
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(model.PostActionIntegrationResponse{
+		Update: &model.Post{
+			Message: T("app.call.feedback_thanks"),
+		},
+	}); err != nil {
+		p.LogError("failed to write response", "err", err.Error())
+	}
+}
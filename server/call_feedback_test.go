@@ -0,0 +1,49 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost-plugin-calls/server/public"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCallFeedbackAttachment(t *testing.T) {
+	callID := model.NewId()
+	attachment := newCallFeedbackAttachment(callID, "How was it?")
+
+	require.Equal(t, "How was it?", attachment.Text)
+	require.Len(t, attachment.Actions, callFeedbackMaxScore)
+	for i, action := range attachment.Actions {
+		require.Equal(t, i+1, action.Integration.Context["score"])
+		require.Equal(t, "/plugins/"+manifest.Id+"/calls/"+callID+"/feedback", action.Integration.URL)
+	}
+}
+
+func TestCallFeedbackSampled(t *testing.T) {
+	var cfg configuration
+	cfg.SetDefaults()
+	cfg.CallFeedbackSampleRate = model.NewPointer(100)
+	require.False(t, cfg.callFeedbackSampled())
+
+	cfg.EnableCallFeedback = model.NewPointer(true)
+	require.True(t, cfg.callFeedbackSampled())
+}
+
+func TestCallHistoryEntryQualityScore(t *testing.T) {
+	call := &public.Call{ID: model.NewId()}
+
+	entry := newCallHistoryEntry(call)
+	require.Zero(t, entry.QualityScore)
+	require.Zero(t, entry.FeedbackCount)
+
+	call.Props.Feedback = map[string]int{"userA": 5, "userB": 2, "userC": 4}
+	entry = newCallHistoryEntry(call)
+	require.Equal(t, 3, entry.FeedbackCount)
+	require.InDelta(t, 3.67, entry.QualityScore, 0.01)
+}
@@ -0,0 +1,239 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/mattermost/mattermost-plugin-calls/server/db"
+	"github.com/mattermost/mattermost-plugin-calls/server/public"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+const (
+	callsHistoryDefaultPerPage = 60
+	callsHistoryMaxPerPage     = 200
+	callsHistoryExportPerPage  = 1000
+)
+
+var callTagRE = regexp.MustCompile(`^[a-z0-9_-]+$`)
+
+type callHistoryEntry struct {
+	ID        string `json:"id"`
+	ChannelID string `json:"channel_id"`
+	Tag       string `json:"tag"`
+	OwnerID   string `json:"owner_id"`
+	StartAt   int64  `json:"start_at"`
+	EndAt     int64  `json:"end_at"`
+	// Duration of the call in seconds.
+	Duration     int64             `json:"duration"`
+	Participants int               `json:"participants"`
+	Metadata     map[string]string `json:"metadata,omitempty"`
+	// QualityScore is the average of the scores participants gave.
+	QualityScore  float64 `json:"quality_score,omitempty"`
+	FeedbackCount int     `json:"feedback_count,omitempty"`
+}
+
+func newCallHistoryEntry(call *public.Call) callHistoryEntry {
+	entry := callHistoryEntry{
+		ID:           call.ID,
+		ChannelID:    call.ChannelID,
+		Tag:          call.Props.Tag,
+		OwnerID:      call.OwnerID,
+		StartAt:      call.StartAt,
+		EndAt:        call.EndAt,
+		Duration:     (call.EndAt - call.StartAt) / 1000,
+		Participants: len(call.Participants),
+		Metadata:     call.Props.Metadata,
+	}
+
+	if len(call.Props.Feedback) > 0 {
+		var sum int
+		for _, score := range call.Props.Feedback {
+			sum += score
+		}
+		entry.FeedbackCount = len(call.Props.Feedback)
+		entry.QualityScore = float64(sum) / float64(entry.FeedbackCount)
+	}
+
+	return entry
+}
+
+func normalizeCallTag(tag string) string {
+	return strings.ToLower(strings.TrimSpace(tag))
+}
+
+func isValidCallTag(tag string) bool {
+	return len(tag) <= maxCallTagLen && callTagRE.MatchString(tag)
+}
+
+func (c *configuration) isAllowedCallTag(tag string) bool {
+	return slices.Contains(c.getAllowedCallTags(), normalizeCallTag(tag))
+}
+
+func parseCallsHistoryOpts(r *http.Request) (db.GetCallsHistoryOpts, error) {
+	query := r.URL.Query()
+
+	opts := db.GetCallsHistoryOpts{
+		ChannelID: query.Get("channel_id"),
+		Tag:       normalizeCallTag(query.Get("tag")),
+		PerPage:   callsHistoryDefaultPerPage,
+	}
+
+	if opts.ChannelID != "" && !model.IsValidId(opts.ChannelID) {
+		return opts, fmt.Errorf("invalid channel_id")
+	}
+
+	if opts.Tag != "" && !isValidCallTag(opts.Tag) {
+		return opts, fmt.Errorf("invalid tag")
+	}
+
+	for param, dst := range map[string]*int64{"since": &opts.Since, "until": &opts.Until} {
+		if val := query.Get(param); val != "" {
+			n, err := strconv.ParseInt(val, 10, 64)
+			if err != nil || n < 0 {
+				return opts, fmt.Errorf("invalid %s", param)
+			}
+			*dst = n
+		}
+	}
+
+	if val := query.Get("page"); val != "" {
+		n, err := strconv.Atoi(val)
+		if err != nil || n < 0 {
+			return opts, fmt.Errorf("invalid page")
+		}
+		opts.Page = n
+	}
+
+	if val := query.Get("per_page"); val != "" {
+		n, err := strconv.Atoi(val)
+		if err != nil || n <= 0 || n > callsHistoryMaxPerPage {
+			return opts, fmt.Errorf("invalid per_page: range should be [1, %d]", callsHistoryMaxPerPage)
+		}
+		opts.PerPage = n
+	}
+
+	return opts, nil
+}
+
+// handleGetCallsHistory returns the ended calls matching the given filters
+// (channel_id, tag, since, until) for reporting purposes.
+func (p *Plugin) handleGetCallsHistory(w http.ResponseWriter, r *http.Request) error {
+	opts, err := parseCallsHistoryOpts(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return nil
+	}
+
+	calls, err := p.store.GetCallsHistory(opts)
+	if err != nil {
+		return fmt.Errorf("failed to get calls history: %w", err)
+	}
+
+	entries := make([]callHistoryEntry, 0, len(calls))
+	for _, call := range calls {
+		entries = append(entries, newCallHistoryEntry(call))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(entries); err != nil {
+		return fmt.Errorf("error encoding calls history: %w", err)
+	}
+
+	return nil
+}
+
+// handleExportCallsHistory returns all the ended calls matching the given
+// filters as CSV. Pagination parameters are ignored.
+func (p *Plugin) handleExportCallsHistory(w http.ResponseWriter, r *http.Request) error {
+	opts, err := parseCallsHistoryOpts(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return nil
+	}
+	opts.Page = 0
+	opts.PerPage = callsHistoryExportPerPage
+
+	// Fetching the first page before writing anything so that we can still
+	// reply with a proper error.
+	calls, err := p.store.GetCallsHistory(opts)
+	if err != nil {
+		return fmt.Errorf("failed to get calls history: %w", err)
+	}
+
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", `attachment; filename="calls_history.csv"`)
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"id", "channel_id", "tag", "owner_id", "start_at", "end_at", "duration", "participants", "metadata", "quality_score", "feedback_count"}); err != nil {
+		p.LogError("failed to write calls history", "err", err.Error())
+		return nil
+	}
+
+	for {
+		for _, call := range calls {
+			entry := newCallHistoryEntry(call)
+
+			// Metadata is exported as a JSON object so that it fits a
+			// single column.
+			var metadata string
+			if len(entry.Metadata) > 0 {
+				data, err := json.Marshal(entry.Metadata)
+				if err != nil {
+					p.LogError("failed to marshal call metadata", "err", err.Error(), "callID", entry.ID)
+				}
+				metadata = string(data)
+			}
+
+			var qualityScore string
+			if entry.FeedbackCount > 0 {
+				qualityScore = strconv.FormatFloat(entry.QualityScore, 'f', 2, 64)
+			}
+
+			if err := cw.Write([]string{
+				entry.ID,
+				entry.ChannelID,
+				entry.Tag,
+				entry.OwnerID,
+				strconv.FormatInt(entry.StartAt, 10),
+				strconv.FormatInt(entry.EndAt, 10),
+				strconv.FormatInt(entry.Duration, 10),
+				strconv.Itoa(entry.Participants),
+				metadata,
+				qualityScore,
+				strconv.Itoa(entry.FeedbackCount),
+			}); err != nil {
+				p.LogError("failed to write calls history", "err", err.Error())
+				return nil
+			}
+		}
+
+		if len(calls) < opts.PerPage {
+			break
+		}
+
+		opts.Page++
+		calls, err = p.store.GetCallsHistory(opts)
+		if err != nil {
+			p.LogError("failed to get calls history", "err", err.Error())
+			break
+		}
+	}
+
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		p.LogError("failed to write calls history", "err", err.Error())
+	}
+
+	return nil
+}
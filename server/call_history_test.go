@@ -0,0 +1,91 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mattermost/mattermost-plugin-calls/server/db"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetAllowedCallTags(t *testing.T) {
+	var cfg configuration
+	cfg.SetDefaults()
+	require.Empty(t, cfg.getAllowedCallTags())
+	require.False(t, cfg.isAllowedCallTag("standup"))
+
+	cfg.AllowedCallTags = " Standup,interview,,standup , incident "
+	require.Equal(t, []string{"standup", "interview", "incident"}, cfg.getAllowedCallTags())
+	require.True(t, cfg.isAllowedCallTag("standup"))
+	require.True(t, cfg.isAllowedCallTag(" Interview"))
+	require.False(t, cfg.isAllowedCallTag("retro"))
+	require.NoError(t, cfg.IsValid())
+}
+
+func TestParseCallsHistoryOpts(t *testing.T) {
+	tcs := []struct {
+		name  string
+		query string
+		opts  db.GetCallsHistoryOpts
+		err   string
+	}{
+		{
+			name:  "defaults",
+			query: "",
+			opts:  db.GetCallsHistoryOpts{PerPage: callsHistoryDefaultPerPage},
+		},
+		{
+			name:  "all filters",
+			query: "?channel_id=abcdefghijklmnopqrstuvwxyz&tag=Interview&since=1000&until=2000&page=2&per_page=10",
+			opts: db.GetCallsHistoryOpts{
+				ChannelID: "abcdefghijklmnopqrstuvwxyz",
+				Tag:       "interview",
+				Since:     1000,
+				Until:     2000,
+				Page:      2,
+				PerPage:   10,
+			},
+		},
+		{
+			name:  "invalid channel_id",
+			query: "?channel_id=invalid",
+			err:   "invalid channel_id",
+		},
+		{
+			name:  "invalid tag",
+			query: "?tag=team%20sync",
+			err:   "invalid tag",
+		},
+		{
+			name:  "invalid since",
+			query: "?since=yesterday",
+			err:   "invalid since",
+		},
+		{
+			name:  "invalid page",
+			query: "?page=-1",
+			err:   "invalid page",
+		},
+		{
+			name:  "invalid per_page",
+			query: "?per_page=201",
+			err:   "invalid per_page: range should be [1, 200]",
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			opts, err := parseCallsHistoryOpts(httptest.NewRequest("GET", "/calls/history"+tc.query, nil))
+			if tc.err != "" {
+				require.EqualError(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.opts, opts)
+		})
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+const (
+	maxCallMetadataKeys     = 16
+	maxCallMetadataKeyLen   = 64
+	maxCallMetadataValueLen = 256
+	// maxCallMetadataSize is the maximum combined size (in bytes) of all the
+	// keys and values.
+	maxCallMetadataSize = 2048
+)
+
+var callMetadataKeyRE = regexp.MustCompile(`^[a-zA-Z0-9_.-]+$`)
+
+// parseCallMetadata validates the metadata integrations can attach to a call
+// when starting it (e.g. an external meeting ID) and returns it sanitized.
+// Values must be strings, control characters are stripped and surrounding
+// whitespace trimmed.
+func parseCallMetadata(data map[string]any) (map[string]string, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	if len(data) > maxCallMetadataKeys {
+		return nil, fmt.Errorf("too many keys: should be at most %d", maxCallMetadataKeys)
+	}
+
+	metadata := make(map[string]string, len(data))
+	var size int
+	for key, val := range data {
+		if len(key) > maxCallMetadataKeyLen || !callMetadataKeyRE.MatchString(key) {
+			return nil, fmt.Errorf("invalid key %q: should only contain letters, numbers, dots, dashes and underscores and be at most %d characters long",
+				key, maxCallMetadataKeyLen)
+		}
+
+		str, ok := val.(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid value for key %q: should be a string", key)
+		}
+
+		str = strings.TrimSpace(strings.Map(func(r rune) rune {
+			if unicode.IsControl(r) {
+				return -1
+			}
+			return r
+		}, str))
+		if len(str) > maxCallMetadataValueLen {
+			return nil, fmt.Errorf("invalid value for key %q: should be at most %d characters long", key, maxCallMetadataValueLen)
+		}
+
+		size += len(key) + len(str)
+		metadata[key] = str
+	}
+
+	if size > maxCallMetadataSize {
+		return nil, fmt.Errorf("too large: should be at most %d bytes", maxCallMetadataSize)
+	}
+
+	return metadata, nil
+}
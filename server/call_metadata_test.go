@@ -0,0 +1,98 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCallMetadata(t *testing.T) {
+	tooManyKeys := map[string]any{}
+	for i := 0; i <= maxCallMetadataKeys; i++ {
+		tooManyKeys[fmt.Sprintf("key%d", i)] = "value"
+	}
+
+	tooLarge := map[string]any{}
+	for i := 0; i < maxCallMetadataKeys; i++ {
+		tooLarge[fmt.Sprintf("key%d", i)] = strings.Repeat("a", maxCallMetadataValueLen)
+	}
+
+	tcs := []struct {
+		name     string
+		data     map[string]any
+		metadata map[string]string
+		err      string
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "valid",
+			data: map[string]any{
+				"meeting.id": "123-456",
+				"ticket_no":  "SUP-42",
+			},
+			metadata: map[string]string{
+				"meeting.id": "123-456",
+				"ticket_no":  "SUP-42",
+			},
+		},
+		{
+			name: "sanitized",
+			data: map[string]any{
+				"ticket": " SUP-42\n\x00",
+			},
+			metadata: map[string]string{
+				"ticket": "SUP-42",
+			},
+		},
+		{
+			name: "invalid key",
+			data: map[string]any{
+				"ticket number": "SUP-42",
+			},
+			err: `invalid key "ticket number": should only contain letters, numbers, dots, dashes and underscores and be at most 64 characters long`,
+		},
+		{
+			name: "non string value",
+			data: map[string]any{
+				"ticket": float64(42),
+			},
+			err: `invalid value for key "ticket": should be a string`,
+		},
+		{
+			name: "value too long",
+			data: map[string]any{
+				"ticket": strings.Repeat("a", maxCallMetadataValueLen+1),
+			},
+			err: `invalid value for key "ticket": should be at most 256 characters long`,
+		},
+		{
+			name: "too many keys",
+			data: tooManyKeys,
+			err:  "too many keys: should be at most 16",
+		},
+		{
+			name: "too large",
+			data: tooLarge,
+			err:  "too large: should be at most 2048 bytes",
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			metadata, err := parseCallMetadata(tc.data)
+			if tc.err == "" {
+				require.NoError(t, err)
+				require.Equal(t, tc.metadata, metadata)
+			} else {
+				require.EqualError(t, err, tc.err)
+			}
+		})
+	}
+}
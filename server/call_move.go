@@ -0,0 +1,248 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-calls/server/db"
+	"github.com/mattermost/mattermost-plugin-calls/server/public"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+var errMoveParticipantsWithoutAccess = errors.New("some participants cannot access the target channel")
+
+// moveCall moves the call ongoing in fromChannelID to toChannelID. The call
+// keeps going with the same ID so that media sessions, which are tied to it,
+// stay connected. Participants who cannot read the target channel are
+// removed from the call if removeWithoutAccess is set, otherwise the move
+// fails. Calls with running jobs cannot be moved as the jobs are bound to the
+// channel they were started in.
+func (p *Plugin) moveCall(requesterID, fromChannelID, toChannelID string, removeWithoutAccess bool) error {
+	if fromChannelID == toChannelID {
+		return fmt.Errorf("%w: the call is already in the target channel", ErrNotAllowed)
+	}
+
+	if !p.API.HasPermissionToChannel(requesterID, toChannelID, model.PermissionReadChannel) {
+		return ErrNoPermissions
+	}
+
+	toChannel, appErr := p.API.GetChannel(toChannelID)
+	if appErr != nil {
+		return fmt.Errorf("failed to get channel: %w", appErr)
+	}
+
+	callsChannel, err := p.store.GetCallsChannel(toChannelID, db.GetCallsChannelOpts{})
+	if err != nil && !errors.Is(err, db.ErrNotFound) {
+		return fmt.Errorf("failed to get calls channel: %w", err)
+	}
+	callsEnabled, _ := p.getConfiguration().resolveCallsEnabled(callsChannel, toChannel.TeamId)
+	if err := p.userCanStartOrJoin(requesterID, callsEnabled, toChannel.Type); err != nil {
+		return fmt.Errorf("%w: %s", ErrNotAllowed, err.Error())
+	}
+
+	// Both calls need to be locked: the source one as we are modifying it, the
+	// target one to make sure no call gets started there in the meantime. We
+	// always lock in the same order to avoid deadlocking with a concurrent
+	// move in the opposite direction.
+	lockIDs := []string{fromChannelID, toChannelID}
+	if toChannelID < fromChannelID {
+		lockIDs = []string{toChannelID, fromChannelID}
+	}
+	for i, channelID := range lockIDs {
+		if err := p.lockCall(channelID); err != nil {
+			if i > 0 {
+				p.unlockCall(lockIDs[0])
+			}
+			return fmt.Errorf("failed to lock call: %w", err)
+		}
+	}
+	defer func() {
+		for _, channelID := range lockIDs {
+			p.unlockCall(channelID)
+		}
+	}()
+
+	state, err := p.getLockedCallState(fromChannelID)
+	if err != nil {
+		return err
+	}
+	if state == nil {
+		return ErrNoCallOngoing
+	}
+
+	if requesterID != state.Call.GetHostID() {
+		if isAdmin := p.API.HasPermissionTo(requesterID, model.PermissionManageSystem); !isAdmin {
+			return ErrNoPermissions
+		}
+	}
+
+	if toState, err := p.getCallState(toChannelID, true); err != nil {
+		return err
+	} else if toState != nil {
+		return fmt.Errorf("%w: a call is already ongoing in the target channel", ErrNotAllowed)
+	}
+
+	if state.hasRunningJobs() {
+		return fmt.Errorf("%w: jobs are running for the call", ErrNotAllowed)
+	}
+
+	sessionsWithoutAccess := p.getSessionsWithoutChannelAccess(state, toChannelID)
+	if len(sessionsWithoutAccess) > 0 && !removeWithoutAccess {
+		return fmt.Errorf("%w: %w", ErrNotAllowed, errMoveParticipantsWithoutAccess)
+	}
+
+	oldPostID := state.Call.PostID
+	var title string
+	if post, err := p.store.GetPost(oldPostID); err == nil {
+		title, _ = post.GetProp("title").(string)
+	} else {
+		p.LogWarn("failed to get call post", "err", err.Error(), "postID", oldPostID)
+	}
+
+	postID, threadID, err := p.createCallStartedPost(state, state.Call.OwnerID, toChannelID, title, "", false)
+	if err != nil {
+		return fmt.Errorf("failed to create call post: %w", err)
+	}
+
+	state.Call.ChannelID = toChannelID
+	state.Call.PostID = postID
+	state.Call.ThreadID = threadID
+	if err := p.store.UpdateCall(&state.Call); err != nil {
+		return fmt.Errorf("failed to update call: %w", err)
+	}
+
+	if err := p.updateCallPostMoved(oldPostID, toChannel); err != nil {
+		p.LogError("failed to update call post", "err", err.Error(), "postID", oldPostID)
+	}
+
+	// Sessions keep track of the channel they are in, so we update those
+	// handled by this node and let the others do the same.
+	p.setSessionsChannel(state.Call.ID, toChannelID)
+	if err := p.sendClusterMessage(clusterMessage{
+		CallID:    state.Call.ID,
+		ChannelID: toChannelID,
+		SenderID:  p.nodeID,
+	}, clusterMessageTypeMove, ""); err != nil {
+		p.LogError("failed to send move message", "err", err.Error(), "callID", state.Call.ID)
+	}
+
+	p.LogInfo("call moved", "callID", state.Call.ID, "fromChannelID", fromChannelID, "toChannelID", toChannelID,
+		"requesterID", requesterID, "removedSessions", fmt.Sprintf("%d", len(sessionsWithoutAccess)))
+
+	removedSessionIDs := make([]string, 0, len(sessionsWithoutAccess))
+	for _, session := range sessionsWithoutAccess {
+		removedSessionIDs = append(removedSessionIDs, session.ID)
+	}
+
+	// Clients in the previous channel clear the call while participants
+	// switch over to the new channel.
+	p.publishWebSocketEvent(wsEventCallMoved, map[string]interface{}{
+		"call_id":             state.Call.ID,
+		"channel_id":          fromChannelID,
+		"new_channel_id":      toChannelID,
+		"new_post_id":         postID,
+		"new_thread_id":       threadID,
+		"removed_session_ids": removedSessionIDs,
+	}, &WebSocketBroadcast{
+		ChannelID:           fromChannelID,
+		ReliableClusterSend: true,
+		UserIDs:             getUserIDsFromSessions(state.sessions),
+	})
+
+	// The call is over as far as the previous channel is concerned. The reason
+	// is only sent to clients and not stored as the call keeps going.
+	p.publishWebSocketEvent(wsEventCallEnd, map[string]interface{}{
+		"call_id":  state.Call.ID,
+		"reason":   string(public.CallEndReasonMoved),
+		"metadata": state.Call.Props.Metadata,
+	}, &WebSocketBroadcast{ChannelID: fromChannelID, ReliableClusterSend: true})
+
+	p.publishCallStart(&state.Call)
+
+	userIDs := getUserIDsFromSessions(state.sessions)
+
+	// Participants get the full call state for the new channel since they
+	// aren't necessarily members of it yet.
+	for _, userID := range userIDs {
+		clientStateData, err := json.Marshal(p.getCallClientState(state, userID))
+		if err != nil {
+			p.LogError("failed to marshal client state", "err", err.Error())
+			continue
+		}
+		p.publishWebSocketEvent(wsEventCallState, map[string]interface{}{
+			"channel_id": toChannelID,
+			"call":       string(clientStateData),
+		}, &WebSocketBroadcast{UserID: userID, ReliableClusterSend: true})
+	}
+
+	for _, session := range sessionsWithoutAccess {
+		p.publishWebSocketEvent(wsEventHostRemoved, map[string]interface{}{
+			"call_id":    state.Call.ID,
+			"channel_id": toChannelID,
+			"session_id": session.ID,
+			"user_id":    session.UserID,
+			"reason":     hostRemovedReasonChannelAccessLost,
+		}, &WebSocketBroadcast{
+			ChannelID:           toChannelID,
+			ReliableClusterSend: true,
+			UserIDs:             userIDs,
+		})
+
+		go p.closeSessionAfterGracePeriod(toChannelID, session.ID)
+	}
+
+	return nil
+}
+
+// setSessionsChannel updates the channel of the sessions handled by this node
+// for the given call.
+func (p *Plugin) setSessionsChannel(callID, channelID string) {
+	p.mut.Lock()
+	defer p.mut.Unlock()
+
+	for _, us := range p.sessions {
+		if us.callID == callID {
+			us.setChannelID(channelID)
+		}
+	}
+}
+
+// updateCallPostMoved marks the call post in the previous channel as no
+// longer tracking the call, pointing users to where it moved.
+func (p *Plugin) updateCallPostMoved(postID string, toChannel *model.Channel) error {
+	if postID == "" {
+		return fmt.Errorf("postID should not be empty")
+	}
+
+	post, err := p.store.GetPost(postID)
+	if err != nil {
+		return err
+	}
+
+	T := p.getTranslationFunc("")
+
+	postMsg := T("app.call.moved_message", map[string]any{"ChannelName": toChannel.Name})
+	slackAttachment := model.SlackAttachment{
+		Fallback: postMsg,
+		Title:    postMsg,
+		Text:     postMsg,
+	}
+
+	post.Message = postMsg
+	post.DelProp("attachments")
+	post.AddProp("attachments", []*model.SlackAttachment{&slackAttachment})
+	post.AddProp("end_at", time.Now().UnixMilli())
+	post.AddProp("moved_to_channel_id", toChannel.Id)
+
+	if _, appErr := p.API.UpdatePost(post); appErr != nil {
+		return appErr
+	}
+
+	return nil
+}
@@ -0,0 +1,130 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"sync"
+	"testing"
+
+	pluginMocks "github.com/mattermost/mattermost-plugin-calls/server/mocks/github.com/mattermost/mattermost/server/public/plugin"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetSessionsChannel(t *testing.T) {
+	p := &Plugin{
+		sessions: map[string]*session{
+			"connA": newUserSession("userA", "channelA", "connA", "callA", false),
+			"connB": newUserSession("userB", "channelA", "connB", "callA", true),
+			"connC": newUserSession("userC", "channelC", "connC", "callC", false),
+		},
+	}
+
+	p.setSessionsChannel("callA", "channelB")
+
+	require.Equal(t, "channelB", p.sessions["connA"].getChannelID())
+	require.Equal(t, "channelB", p.sessions["connB"].getChannelID())
+	require.Equal(t, "channelC", p.sessions["connC"].getChannelID())
+}
+
+func TestSetSessionsChannelWhileHandlingMessages(t *testing.T) {
+	us := newUserSession("userA", "channelA", "connA", "callA", false)
+	p := &Plugin{
+		sessions: map[string]*session{
+			"connA": us,
+		},
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+
+	// Moving the call back and forth.
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1000; i++ {
+			if i%2 == 0 {
+				p.setSessionsChannel("callA", "channelB")
+			} else {
+				p.setSessionsChannel("callA", "channelA")
+			}
+		}
+	}()
+
+	// Handling client messages, which lock and unlock the call by channel.
+	go func() {
+		defer wg.Done()
+		locked := map[string]int{}
+		for i := 0; i < 1000; i++ {
+			channelID := us.getChannelID()
+			locked[channelID]++
+			locked[channelID]--
+		}
+		for channelID, n := range locked {
+			require.Zero(t, n, channelID)
+		}
+	}()
+
+	wg.Wait()
+}
+
+func TestMoveCall(t *testing.T) {
+	mockAPI := &pluginMocks.MockAPI{}
+	defer mockAPI.AssertExpectations(t)
+
+	p := &Plugin{
+		MattermostPlugin: plugin.MattermostPlugin{
+			API: mockAPI,
+		},
+	}
+
+	fromChannelID := model.NewId()
+	toChannelID := model.NewId()
+
+	t.Run("same channel", func(t *testing.T) {
+		err := p.moveCall("userID", fromChannelID, fromChannelID, false)
+		require.ErrorIs(t, err, ErrNotAllowed)
+	})
+
+	t.Run("no access to target channel", func(t *testing.T) {
+		mockAPI.On("HasPermissionToChannel", "userID", toChannelID, model.PermissionReadChannel).Return(false).Once()
+		err := p.moveCall("userID", fromChannelID, toChannelID, false)
+		require.ErrorIs(t, err, ErrNoPermissions)
+	})
+}
+
+func TestHandleMoveCommand(t *testing.T) {
+	mockAPI := &pluginMocks.MockAPI{}
+	defer mockAPI.AssertExpectations(t)
+
+	p := &Plugin{
+		MattermostPlugin: plugin.MattermostPlugin{
+			API: mockAPI,
+		},
+	}
+
+	args := &model.CommandArgs{
+		UserId:    "userID",
+		TeamId:    "teamID",
+		ChannelId: "channelID",
+	}
+
+	t.Run("missing channel", func(t *testing.T) {
+		_, err := p.handleMoveCommand(args, []string{"/call", "move"})
+		require.EqualError(t, err, "Invalid number of arguments provided")
+	})
+
+	t.Run("invalid argument", func(t *testing.T) {
+		_, err := p.handleMoveCommand(args, []string{"/call", "move", "~town-square", "now"})
+		require.EqualError(t, err, `Invalid argument "now"`)
+	})
+
+	t.Run("unknown channel", func(t *testing.T) {
+		mockAPI.On("GetChannelByName", "teamID", "unknown", false).Return(nil, &model.AppError{Message: "not found"}).Once()
+		_, err := p.handleMoveCommand(args, []string{"/call", "move", "~unknown"})
+		require.EqualError(t, err, "Could not find channel `unknown`")
+	})
+}
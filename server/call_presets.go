@@ -0,0 +1,127 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+const (
+	maxCallPresets       = 20
+	maxCallPresetNameLen = 64
+)
+
+// callPreset is a named set of call settings hosts can pick when starting a
+// call. Settings left unset fall back to the channel or global defaults.
+type callPreset struct {
+	Name string `json:"name"`
+	// JoinMuted is whether participants join muted (e.g. listener only).
+	JoinMuted *bool `json:"join_muted,omitempty"`
+	// WaitingRoom is whether participants need to be admitted by the host.
+	WaitingRoom *bool `json:"waiting_room,omitempty"`
+	// AudioOnly is whether camera video is disallowed in the call.
+	AudioOnly bool `json:"audio_only,omitempty"`
+	// Record is whether a recording starts automatically with the call.
+	Record bool `json:"record,omitempty"`
+}
+
+func (c *configuration) getCallPresets() ([]callPreset, error) {
+	if strings.TrimSpace(c.CallPresets) == "" {
+		return nil, nil
+	}
+
+	var presets []callPreset
+	if err := json.Unmarshal([]byte(c.CallPresets), &presets); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal: %w", err)
+	}
+
+	return presets, nil
+}
+
+func (c *configuration) callPresetsIsValid() error {
+	presets, err := c.getCallPresets()
+	if err != nil {
+		return err
+	}
+
+	if len(presets) > maxCallPresets {
+		return fmt.Errorf("should not contain more than %d presets", maxCallPresets)
+	}
+
+	names := make(map[string]bool, len(presets))
+	for _, preset := range presets {
+		name := normalizeCallPresetName(preset.Name)
+		if name == "" {
+			return fmt.Errorf("preset name should not be empty")
+		}
+		if len(name) > maxCallPresetNameLen {
+			return fmt.Errorf("preset name %q should be at most %d characters long", preset.Name, maxCallPresetNameLen)
+		}
+		if names[name] {
+			return fmt.Errorf("preset name %q is duplicated", preset.Name)
+		}
+		names[name] = true
+	}
+
+	return nil
+}
+
+func normalizeCallPresetName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// getCallPreset returns the preset matching the given name, ignoring case.
+func (c *configuration) getCallPreset(name string) (callPreset, bool) {
+	presets, err := c.getCallPresets()
+	if err != nil {
+		return callPreset{}, false
+	}
+
+	for _, preset := range presets {
+		if normalizeCallPresetName(preset.Name) == normalizeCallPresetName(name) {
+			return preset, true
+		}
+	}
+
+	return callPreset{}, false
+}
+
+// startRecordingFromPreset starts recording a call which was started with a
+// preset requiring it.
+func (p *Plugin) startRecordingFromPreset(channelID, presetName string) {
+	if !p.licenseChecker.RecordingsAllowed() {
+		p.LogDebug("preset recording ignored: recordings are not allowed by the license", "channelID", channelID)
+		return
+	}
+
+	if cfg := p.getConfiguration(); !cfg.recordingsEnabled() {
+		p.LogDebug("preset recording ignored: recordings are not enabled", "channelID", channelID)
+		return
+	}
+
+	if p.getJobService() == nil {
+		p.LogWarn("preset recording ignored: job service is not initialized", "channelID", channelID)
+		return
+	}
+
+	state, err := p.lockCallReturnState(channelID)
+	if err != nil {
+		p.LogError("failed to lock call", "err", err.Error(), "channelID", channelID)
+		return
+	}
+	defer p.unlockCall(channelID)
+	if state == nil {
+		return
+	}
+
+	p.LogInfo("starting recording from call preset", "callID", state.Call.ID, "channelID", channelID, "preset", presetName)
+
+	// The recording is started on behalf of the host, as if they had
+	// requested it, so that it's subject to the same rules.
+	if _, _, err := p.startRecordingJob(state, channelID, state.Call.GetHostID(), recordingStartRequest{}); err != nil {
+		p.LogError("failed to start recording from call preset", "err", err.Error(), "callID", state.Call.ID)
+	}
+}
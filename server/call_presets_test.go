@@ -0,0 +1,49 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetCallPreset(t *testing.T) {
+	var cfg configuration
+	cfg.SetDefaults()
+
+	_, ok := cfg.getCallPreset("webinar")
+	require.False(t, ok)
+
+	cfg.CallPresets = `[
+		{"name": "Webinar", "join_muted": true, "waiting_room": true, "record": true},
+		{"name": "Standup", "audio_only": true}
+	]`
+	require.NoError(t, cfg.IsValid())
+
+	preset, ok := cfg.getCallPreset(" webinar")
+	require.True(t, ok)
+	require.Equal(t, "Webinar", preset.Name)
+	require.True(t, *preset.JoinMuted)
+	require.True(t, *preset.WaitingRoom)
+	require.True(t, preset.Record)
+	require.False(t, preset.AudioOnly)
+
+	preset, ok = cfg.getCallPreset("Standup")
+	require.True(t, ok)
+	require.Nil(t, preset.JoinMuted)
+	require.Nil(t, preset.WaitingRoom)
+	require.True(t, preset.AudioOnly)
+
+	_, ok = cfg.getCallPreset("interview")
+	require.False(t, ok)
+
+	t.Run("invalid", func(t *testing.T) {
+		cfg.CallPresets = `{"name": "Webinar"}`
+		require.Error(t, cfg.callPresetsIsValid())
+
+		cfg.CallPresets = `[{"name": " "}]`
+		require.EqualError(t, cfg.callPresetsIsValid(), "preset name should not be empty")
+	})
+}
@@ -0,0 +1,72 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+// callSnapshotClient is the complete state of the call ongoing in a channel,
+// if any, as seen by the requesting user.
+type callSnapshotClient struct {
+	ChannelID string           `json:"channel_id"`
+	Call      *CallStateClient `json:"call"`
+}
+
+// getCallSnapshot returns the current state of the call ongoing in the given
+// channel, or nil if there's none.
+//
+// Locking is not ideal but it's the only way to guarantee a race free
+// sequence and a consistent state.
+// On the client we should make sure to make this request only when strictly
+// necessary (i.e first load, joining call, reconnecting).
+func (p *Plugin) getCallSnapshot(channelID, userID string) (*CallStateClient, error) {
+	state, err := p.lockCallReturnState(channelID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock call: %w", err)
+	}
+	defer p.unlockCall(channelID)
+
+	if state == nil {
+		return nil, nil
+	}
+
+	return p.getCallClientState(state, userID), nil
+}
+
+// handleGetCallSnapshot lets clients fetch the whole state of the call in a
+// channel at once (e.g. after reconnecting) instead of rebuilding it from
+// incremental events.
+func (p *Plugin) handleGetCallSnapshot(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("Mattermost-User-Id")
+	channelID := mux.Vars(r)["channel_id"]
+
+	// We should go through only if the user has permissions to the requested channel
+	// or if the user is the Calls bot.
+	if !(p.isBotSession(r) || p.API.HasPermissionToChannel(userID, channelID, model.PermissionReadChannel)) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	clientState, err := p.getCallSnapshot(channelID, userID)
+	if err != nil {
+		p.LogError(err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(callSnapshotClient{
+		ChannelID: channelID,
+		Call:      clientState,
+	}); err != nil {
+		p.LogError(err.Error())
+	}
+}
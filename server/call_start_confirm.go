@@ -0,0 +1,91 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-calls/server/public"
+)
+
+// The time a client has to confirm starting a call before the request is
+// discarded.
+var callStartConfirmTimeout = 15 * time.Second
+
+// pendingCallStart holds a join request that would start a new call until the
+// client confirms it.
+type pendingCallStart struct {
+	userID        string
+	authSessionID string
+	joinData      callsJoinData
+}
+
+// shouldConfirmCallStart returns whether starting a call in the given channel
+// requires a confirmation from the client.
+func (p *Plugin) shouldConfirmCallStart(callsChannel *public.CallsChannel) bool {
+	if callsChannel != nil {
+		if confirm, ok := callsChannel.Props["confirm_call_start"].(bool); ok {
+			return confirm
+		}
+	}
+	cfg := p.getConfiguration()
+	return cfg.ConfirmCallStart != nil && *cfg.ConfirmCallStart
+}
+
+// requestCallStartConfirmation holds the join request and asks the client to
+// confirm it. The call is only started once the confirmation is received.
+func (p *Plugin) requestCallStartConfirmation(userID, connID, authSessionID string, joinData callsJoinData) error {
+	pcs := &pendingCallStart{
+		userID:        userID,
+		authSessionID: authSessionID,
+		joinData:      joinData,
+	}
+
+	if err := addConnEntry(p, p.pendingCallStarts, connID, pcs, "waiting to start a call"); err != nil {
+		return err
+	}
+
+	p.LogDebug("call start requires confirmation", "userID", userID, "connID", connID, "channelID", joinData.ChannelID)
+
+	p.publishWebSocketEvent(wsEventCallStartConfirm, map[string]interface{}{
+		"connID":     connID,
+		"channel_id": joinData.ChannelID,
+		"timeout_ms": callStartConfirmTimeout.Milliseconds(),
+	}, &WebSocketBroadcast{ConnectionID: connID})
+
+	time.AfterFunc(callStartConfirmTimeout, func() {
+		if !removeConnEntry(p, p.pendingCallStarts, connID, pcs) {
+			return
+		}
+
+		p.publishWebSocketEvent(wsEventCallStartExpired, map[string]interface{}{
+			"connID":     connID,
+			"channel_id": joinData.ChannelID,
+		}, &WebSocketBroadcast{ConnectionID: connID})
+	})
+
+	return nil
+}
+
+// takePendingCallStart removes and returns the pending call start for the
+// given connection, if any.
+func (p *Plugin) takePendingCallStart(connID string) *pendingCallStart {
+	return takeConnEntry(p, p.pendingCallStarts, connID)
+}
+
+func (p *Plugin) handleCallStartConfirm(userID, connID string) error {
+	pcs := p.takePendingCallStart(connID)
+	if pcs == nil {
+		return fmt.Errorf("no call start to confirm")
+	}
+
+	if pcs.userID != userID {
+		return fmt.Errorf("forbidden")
+	}
+
+	pcs.joinData.startConfirmed = true
+
+	return p.handleJoin(userID, connID, pcs.authSessionID, pcs.joinData)
+}
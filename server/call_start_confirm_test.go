@@ -0,0 +1,158 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-calls/server/public"
+
+	serverMocks "github.com/mattermost/mattermost-plugin-calls/server/mocks/github.com/mattermost/mattermost-plugin-calls/server/interfaces"
+	pluginMocks "github.com/mattermost/mattermost-plugin-calls/server/mocks/github.com/mattermost/mattermost/server/public/plugin"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShouldConfirmCallStart(t *testing.T) {
+	p := &Plugin{}
+	cfg := &configuration{}
+	cfg.SetDefaults()
+	p.configuration = cfg
+
+	require.False(t, p.shouldConfirmCallStart(nil))
+
+	cfg.ConfirmCallStart = model.NewPointer(true)
+	require.True(t, p.shouldConfirmCallStart(nil))
+	require.True(t, p.shouldConfirmCallStart(&public.CallsChannel{}))
+
+	// Channel setting takes precedence.
+	require.False(t, p.shouldConfirmCallStart(&public.CallsChannel{
+		Props: map[string]any{"confirm_call_start": false},
+	}))
+
+	cfg.ConfirmCallStart = model.NewPointer(false)
+	require.True(t, p.shouldConfirmCallStart(&public.CallsChannel{
+		Props: map[string]any{"confirm_call_start": true},
+	}))
+}
+
+func TestCallStartConfirmation(t *testing.T) {
+	defaultTimeout := callStartConfirmTimeout
+	callStartConfirmTimeout = 50 * time.Millisecond
+	defer func() {
+		callStartConfirmTimeout = defaultTimeout
+	}()
+
+	mockAPI := &pluginMocks.MockAPI{}
+	mockMetrics := &serverMocks.MockMetrics{}
+
+	defer mockAPI.AssertExpectations(t)
+	defer mockMetrics.AssertExpectations(t)
+
+	p := &Plugin{
+		MattermostPlugin: plugin.MattermostPlugin{
+			API: mockAPI,
+		},
+		metrics:           mockMetrics,
+		sessions:          map[string]*session{},
+		pendingCallStarts: map[string]*pendingCallStart{},
+	}
+
+	userID := model.NewId()
+	channelID := model.NewId()
+	joinData := callsJoinData{
+		CallsClientJoinData: CallsClientJoinData{
+			ChannelID: channelID,
+		},
+	}
+
+	mockAPI.On("LogDebug", "call start requires confirmation", "origin", mock.Anything,
+		"userID", userID, "connID", mock.Anything, "channelID", channelID)
+
+	t.Run("confirm from another user", func(t *testing.T) {
+		connID := model.NewId()
+
+		mockMetrics.On("IncWebSocketEvent", "out", wsEventCallStartConfirm).Once()
+		mockAPI.On("PublishWebSocketEvent", wsEventCallStartConfirm, map[string]any{
+			"connID":     connID,
+			"channel_id": channelID,
+			"timeout_ms": callStartConfirmTimeout.Milliseconds(),
+		}, &model.WebsocketBroadcast{ConnectionId: connID}).Once()
+
+		err := p.requestCallStartConfirmation(userID, connID, "", joinData)
+		require.NoError(t, err)
+		require.NotNil(t, p.pendingCallStarts[connID])
+
+		err = p.handleCallStartConfirm(model.NewId(), connID)
+		require.EqualError(t, err, "forbidden")
+		require.Empty(t, p.pendingCallStarts)
+
+		err = p.handleCallStartConfirm(userID, connID)
+		require.EqualError(t, err, "no call start to confirm")
+	})
+
+	t.Run("already in call", func(t *testing.T) {
+		connID := model.NewId()
+		p.sessions[connID] = &session{}
+		defer delete(p.sessions, connID)
+
+		err := p.requestCallStartConfirmation(userID, connID, "", joinData)
+		require.EqualError(t, err, "connection is already in a call")
+		require.Empty(t, p.pendingCallStarts)
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		connID := model.NewId()
+
+		mockMetrics.On("IncWebSocketEvent", "out", wsEventCallStartConfirm).Once()
+		mockAPI.On("PublishWebSocketEvent", wsEventCallStartConfirm, mock.Anything,
+			&model.WebsocketBroadcast{ConnectionId: connID}).Once()
+
+		err := p.requestCallStartConfirmation(userID, connID, "", joinData)
+		require.NoError(t, err)
+
+		pcs := p.takePendingCallStart(connID)
+		require.NotNil(t, pcs)
+		require.Equal(t, userID, pcs.userID)
+		require.Equal(t, channelID, pcs.joinData.ChannelID)
+		require.False(t, pcs.joinData.startConfirmed)
+		require.Nil(t, p.takePendingCallStart(connID))
+
+		// Cancelled requests should not expire.
+		time.Sleep(2 * callStartConfirmTimeout)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		connID := model.NewId()
+
+		expiredCh := make(chan struct{})
+		mockMetrics.On("IncWebSocketEvent", "out", wsEventCallStartConfirm).Once()
+		mockMetrics.On("IncWebSocketEvent", "out", wsEventCallStartExpired).Once()
+		mockAPI.On("PublishWebSocketEvent", wsEventCallStartConfirm, mock.Anything,
+			&model.WebsocketBroadcast{ConnectionId: connID}).Once()
+		mockAPI.On("PublishWebSocketEvent", wsEventCallStartExpired, map[string]any{
+			"connID":     connID,
+			"channel_id": channelID,
+		}, &model.WebsocketBroadcast{ConnectionId: connID}).Run(func(_ mock.Arguments) {
+			close(expiredCh)
+		}).Once()
+
+		err := p.requestCallStartConfirmation(userID, connID, "", joinData)
+		require.NoError(t, err)
+
+		select {
+		case <-expiredCh:
+		case <-time.After(5 * time.Second):
+			require.FailNow(t, "timed out waiting for expiration")
+		}
+
+		err = p.handleCallStartConfirm(userID, connID)
+		require.EqualError(t, err, "no call start to confirm")
+	})
+}
@@ -0,0 +1,416 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/mattermost/mattermost-plugin-calls/server/db"
+	"github.com/mattermost/mattermost-plugin-calls/server/public"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+const (
+	callTimelineKeyPrefix     = "call_timeline_"
+	callTimelineQueueSize     = 4096
+	callTimelineMaxBatchSize  = 100
+	callTimelineMaxEvents     = 10000
+	callTimelineUpdateRetries = 5
+
+	callTimelineFormatJSON = "json"
+	callTimelineFormatText = "text"
+)
+
+// Events are persisted at most once per interval so that busy calls don't
+// turn every state change into a KV store write.
+var callTimelineFlushInterval = time.Second
+
+type callTimelineEventType string
+
+const (
+	callTimelineEventCallStart      callTimelineEventType = "call_start"
+	callTimelineEventCallEnd        callTimelineEventType = "call_end"
+	callTimelineEventJoin           callTimelineEventType = "join"
+	callTimelineEventLeave          callTimelineEventType = "leave"
+	callTimelineEventMute           callTimelineEventType = "mute"
+	callTimelineEventUnmute         callTimelineEventType = "unmute"
+	callTimelineEventRaiseHand      callTimelineEventType = "raise_hand"
+	callTimelineEventLowerHand      callTimelineEventType = "lower_hand"
+	callTimelineEventHostChange     callTimelineEventType = "host_change"
+	callTimelineEventRecordingStart callTimelineEventType = "recording_start"
+	callTimelineEventRecordingStop  callTimelineEventType = "recording_stop"
+	callTimelineEventMarker         callTimelineEventType = "marker"
+	callTimelineEventICEFailure     callTimelineEventType = "ice_failure"
+)
+
+type callTimelineEvent struct {
+	Type      callTimelineEventType `json:"type"`
+	CreateAt  int64                 `json:"create_at"`
+	UserID    string                `json:"user_id,omitempty"`
+	SessionID string                `json:"session_id,omitempty"`
+	// Data holds event specific details (e.g. the marker name).
+	Data string `json:"data,omitempty"`
+}
+
+// callTimeline holds the events of a call that can't be reconstructed from
+// the call and jobs records.
+type callTimeline struct {
+	Events []callTimelineEvent `json:"events"`
+	// Truncated is set when events were dropped because the limit was
+	// reached.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+type queuedCallTimelineEvent struct {
+	callID string
+	ev     callTimelineEvent
+}
+
+// recordCallTimelineEvent queues an event for persisting in the timeline of
+// the given call. It never blocks as it's called while holding the call lock.
+func (p *Plugin) recordCallTimelineEvent(callID string, evType callTimelineEventType, userID, sessionID, data string) {
+	if !p.getConfiguration().callTimelineEnabled() || userID == p.getBotID() {
+		return
+	}
+
+	select {
+	case p.callTimelineCh <- queuedCallTimelineEvent{
+		callID: callID,
+		ev: callTimelineEvent{
+			Type:      evType,
+			CreateAt:  time.Now().UnixMilli(),
+			UserID:    userID,
+			SessionID: sessionID,
+			Data:      data,
+		},
+	}:
+	default:
+		p.LogWarn("call timeline queue is full, dropping event", "type", string(evType), "callID", callID, "userID", userID)
+	}
+}
+
+func (p *Plugin) callTimelineWriter() {
+	ticker := time.NewTicker(callTimelineFlushInterval)
+	defer ticker.Stop()
+
+	var count int
+	events := map[string][]callTimelineEvent{}
+	for {
+		select {
+		case qev := <-p.callTimelineCh:
+			events[qev.callID] = append(events[qev.callID], qev.ev)
+			count++
+			if count < callTimelineMaxBatchSize {
+				continue
+			}
+		case <-ticker.C:
+			if count == 0 {
+				continue
+			}
+		case <-p.stopCh:
+			if count > 0 {
+				p.LogWarn("plugin stopping, dropping call timeline events", "count", fmt.Sprintf("%d", count))
+			}
+			return
+		}
+
+		for callID, evs := range events {
+			if err := p.appendCallTimelineEvents(callID, evs); err != nil {
+				p.LogError("failed to save call timeline events", "err", err.Error(), "callID", callID)
+			}
+		}
+		events = map[string][]callTimelineEvent{}
+		count = 0
+	}
+}
+
+func (p *Plugin) getCallTimeline(callID string) (*callTimeline, []byte, error) {
+	data, appErr := p.API.KVGet(callTimelineKeyPrefix + callID)
+	if appErr != nil {
+		return nil, nil, fmt.Errorf("failed to get call timeline: %w", appErr)
+	}
+
+	timeline := &callTimeline{}
+	if data != nil {
+		if err := json.Unmarshal(data, timeline); err != nil {
+			return nil, nil, fmt.Errorf("failed to unmarshal call timeline: %w", err)
+		}
+	}
+
+	return timeline, data, nil
+}
+
+// appendCallTimelineEvents persists the given events. Concurrent updates from
+// different nodes are detected and retried.
+func (p *Plugin) appendCallTimelineEvents(callID string, events []callTimelineEvent) error {
+	for i := 0; i < callTimelineUpdateRetries; i++ {
+		timeline, oldData, err := p.getCallTimeline(callID)
+		if err != nil {
+			return err
+		}
+
+		if timeline.Truncated {
+			return nil
+		}
+
+		if n := callTimelineMaxEvents - len(timeline.Events); len(events) > n {
+			timeline.Events = append(timeline.Events, events[:n]...)
+			timeline.Truncated = true
+		} else {
+			timeline.Events = append(timeline.Events, events...)
+		}
+
+		data, err := json.Marshal(timeline)
+		if err != nil {
+			return fmt.Errorf("failed to marshal call timeline: %w", err)
+		}
+
+		ok, appErr := p.API.KVSetWithOptions(callTimelineKeyPrefix+callID, data, model.PluginKVSetOptions{
+			Atomic:   true,
+			OldValue: oldData,
+		})
+		if appErr != nil {
+			return fmt.Errorf("failed to set call timeline: %w", appErr)
+		}
+		if ok {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("failed to append call timeline events: too many concurrent updates")
+}
+
+// buildCallTimeline returns the chronological list of events of the given
+// call. Events that are part of the call and jobs records (start, end,
+// recordings and markers) are reconstructed from those while the others come
+// from the persisted timeline.
+func buildCallTimeline(call *public.Call, jobs []*public.CallJob, timeline *callTimeline) []callTimelineEvent {
+	events := []callTimelineEvent{{
+		Type:     callTimelineEventCallStart,
+		CreateAt: call.StartAt,
+		UserID:   call.OwnerID,
+	}}
+
+	if timeline != nil {
+		events = append(events, timeline.Events...)
+	}
+
+	for _, job := range jobs {
+		// Additional recording jobs capture the same call.
+		if job.Type != public.JobTypeRecording || job.Props.PrimaryJobID != "" {
+			continue
+		}
+		if job.StartAt > 0 {
+			events = append(events, callTimelineEvent{
+				Type:     callTimelineEventRecordingStart,
+				CreateAt: job.StartAt,
+				UserID:   job.CreatorID,
+			})
+		}
+		for _, marker := range job.Props.Markers {
+			events = append(events, callTimelineEvent{
+				Type:     callTimelineEventMarker,
+				CreateAt: marker.CreateAt,
+				Data:     marker.Name,
+			})
+		}
+		if job.StartAt > 0 && job.EndAt > 0 {
+			events = append(events, callTimelineEvent{
+				Type:     callTimelineEventRecordingStop,
+				CreateAt: job.EndAt,
+				Data:     job.Props.EndReason,
+			})
+		}
+	}
+
+	if call.EndAt > 0 {
+		events = append(events, callTimelineEvent{
+			Type:     callTimelineEventCallEnd,
+			CreateAt: call.EndAt,
+			Data:     string(call.Props.EndReason),
+		})
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].CreateAt < events[j].CreateAt
+	})
+
+	return events
+}
+
+// canAccessCallTimeline returns whether the user is allowed to get the
+// timeline of the call: system admins, the call owner and anyone who has been
+// host.
+func (p *Plugin) canAccessCallTimeline(userID string, call *public.Call, events []callTimelineEvent) bool {
+	if userID == call.OwnerID || userID == call.GetHostID() {
+		return true
+	}
+
+	for _, ev := range events {
+		if ev.Type == callTimelineEventHostChange && ev.UserID == userID {
+			return true
+		}
+	}
+
+	return p.API.HasPermissionTo(userID, model.PermissionManageSystem)
+}
+
+// formatCallTimelineEvent returns a human readable line describing the event.
+func formatCallTimelineEvent(ev callTimelineEvent, startAt int64, username string) string {
+	offset := time.Duration(max(ev.CreateAt-startAt, 0)) * time.Millisecond
+	line := fmt.Sprintf("%s (+%02d:%02d:%02d) ",
+		time.UnixMilli(ev.CreateAt).UTC().Format(time.RFC3339),
+		int(offset.Hours()), int(offset.Minutes())%60, int(offset.Seconds())%60)
+
+	who := "@" + username
+	switch ev.Type {
+	case callTimelineEventCallStart:
+		line += who + " started the call"
+	case callTimelineEventCallEnd:
+		line += "Call ended"
+	case callTimelineEventJoin:
+		line += who + " joined"
+	case callTimelineEventLeave:
+		line += who + " left"
+	case callTimelineEventMute:
+		line += who + " muted"
+	case callTimelineEventUnmute:
+		line += who + " unmuted"
+	case callTimelineEventRaiseHand:
+		line += who + " raised their hand"
+	case callTimelineEventLowerHand:
+		line += who + " lowered their hand"
+	case callTimelineEventHostChange:
+		line += who + " became host"
+	case callTimelineEventRecordingStart:
+		line += who + " started recording"
+	case callTimelineEventRecordingStop:
+		line += "Recording stopped"
+	case callTimelineEventICEFailure:
+		line += who + " failed to connect"
+	case callTimelineEventMarker:
+		line += "Marker: " + ev.Data
+		return line
+	default:
+		line += string(ev.Type)
+	}
+
+	if ev.Data != "" {
+		line += " (" + ev.Data + ")"
+	}
+
+	return line
+}
+
+func (p *Plugin) handleGetCallTimeline(w http.ResponseWriter, r *http.Request) {
+	var res httpResponse
+	defer p.httpAudit("handleGetCallTimeline", &res, w, r)
+
+	userID := r.Header.Get("Mattermost-User-Id")
+	callID := mux.Vars(r)["call_id"]
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = callTimelineFormatJSON
+	}
+	if format != callTimelineFormatJSON && format != callTimelineFormatText {
+		res.Err = fmt.Sprintf("invalid format: should be either %q or %q", callTimelineFormatJSON, callTimelineFormatText)
+		res.Code = http.StatusBadRequest
+		return
+	}
+
+	call, err := p.store.GetCall(callID, db.GetCallOpts{})
+	if errors.Is(err, db.ErrNotFound) {
+		res.Err = "call not found"
+		res.Code = http.StatusNotFound
+		return
+	} else if err != nil {
+		res.Err = err.Error()
+		res.Code = http.StatusInternalServerError
+		return
+	}
+
+	timeline, _, err := p.getCallTimeline(callID)
+	if err != nil {
+		res.Err = err.Error()
+		res.Code = http.StatusInternalServerError
+		return
+	}
+
+	if !p.canAccessCallTimeline(userID, call, timeline.Events) {
+		res.Err = "Forbidden"
+		res.Code = http.StatusForbidden
+		return
+	}
+
+	jobs, err := p.store.GetCallJobs(callID, db.GetCallJobOpts{})
+	if err != nil {
+		res.Err = err.Error()
+		res.Code = http.StatusInternalServerError
+		return
+	}
+
+	events := buildCallTimeline(call, jobs, timeline)
+	if timeline.Truncated {
+		w.Header().Set("X-Calls-Timeline-Truncated", "true")
+	}
+
+	// Events are written one at a time so that long calls don't need to be
+	// rendered in memory.
+	bw := bufio.NewWriter(w)
+	if format == callTimelineFormatText {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		usernames := map[string]string{}
+		for _, ev := range events {
+			username, ok := usernames[ev.UserID]
+			if !ok && ev.UserID != "" {
+				username = ev.UserID
+				if user, appErr := p.API.GetUser(ev.UserID); appErr == nil {
+					username = user.Username
+				}
+				usernames[ev.UserID] = username
+			}
+			if _, err := bw.WriteString(formatCallTimelineEvent(ev, call.StartAt, username) + "\n"); err != nil {
+				p.LogError("failed to write call timeline", "err", err.Error())
+				return
+			}
+		}
+	} else {
+		w.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(bw)
+		if err := bw.WriteByte('['); err != nil {
+			p.LogError("failed to write call timeline", "err", err.Error())
+			return
+		}
+		for i, ev := range events {
+			if i > 0 {
+				if err := bw.WriteByte(','); err != nil {
+					p.LogError("failed to write call timeline", "err", err.Error())
+					return
+				}
+			}
+			if err := enc.Encode(ev); err != nil {
+				p.LogError("failed to write call timeline", "err", err.Error())
+				return
+			}
+		}
+		if err := bw.WriteByte(']'); err != nil {
+			p.LogError("failed to write call timeline", "err", err.Error())
+			return
+		}
+	}
+
+	if err := bw.Flush(); err != nil {
+		p.LogError("failed to write call timeline", "err", err.Error())
+	}
+}
@@ -0,0 +1,178 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-calls/server/public"
+
+	pluginMocks "github.com/mattermost/mattermost-plugin-calls/server/mocks/github.com/mattermost/mattermost/server/public/plugin"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildCallTimeline(t *testing.T) {
+	ownerID := model.NewId()
+	userID := model.NewId()
+
+	call := &public.Call{
+		ID:      model.NewId(),
+		OwnerID: ownerID,
+		StartAt: 1000,
+		EndAt:   9000,
+		Props: public.CallProps{
+			EndReason: public.CallEndReasonHostEnded,
+		},
+	}
+
+	jobs := []*public.CallJob{
+		{
+			Type:      public.JobTypeRecording,
+			CreatorID: ownerID,
+			StartAt:   3000,
+			EndAt:     7000,
+			Props: public.CallJobProps{
+				Markers: []public.CallJobMarker{{Name: "Q&A", CreateAt: 5000}},
+			},
+		},
+		// Additional recording jobs are skipped.
+		{
+			Type:      public.JobTypeRecording,
+			CreatorID: ownerID,
+			StartAt:   3000,
+			EndAt:     7000,
+			Props: public.CallJobProps{
+				PrimaryJobID: model.NewId(),
+			},
+		},
+		{
+			Type:      public.JobTypeTranscribing,
+			CreatorID: ownerID,
+			StartAt:   3000,
+			EndAt:     7000,
+		},
+	}
+
+	timeline := &callTimeline{
+		Events: []callTimelineEvent{
+			{Type: callTimelineEventJoin, CreateAt: 1000, UserID: ownerID},
+			{Type: callTimelineEventJoin, CreateAt: 2000, UserID: userID},
+			{Type: callTimelineEventRaiseHand, CreateAt: 6000, UserID: userID},
+			{Type: callTimelineEventLeave, CreateAt: 8000, UserID: userID},
+		},
+	}
+
+	events := buildCallTimeline(call, jobs, timeline)
+	require.Equal(t, []callTimelineEvent{
+		{Type: callTimelineEventCallStart, CreateAt: 1000, UserID: ownerID},
+		{Type: callTimelineEventJoin, CreateAt: 1000, UserID: ownerID},
+		{Type: callTimelineEventJoin, CreateAt: 2000, UserID: userID},
+		{Type: callTimelineEventRecordingStart, CreateAt: 3000, UserID: ownerID},
+		{Type: callTimelineEventMarker, CreateAt: 5000, Data: "Q&A"},
+		{Type: callTimelineEventRaiseHand, CreateAt: 6000, UserID: userID},
+		{Type: callTimelineEventRecordingStop, CreateAt: 7000},
+		{Type: callTimelineEventLeave, CreateAt: 8000, UserID: userID},
+		{Type: callTimelineEventCallEnd, CreateAt: 9000, Data: string(public.CallEndReasonHostEnded)},
+	}, events)
+}
+
+func TestFormatCallTimelineEvent(t *testing.T) {
+	startAt := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC).UnixMilli()
+
+	require.Equal(t, "2024-01-01T10:00:00Z (+00:00:00) @alice started the call",
+		formatCallTimelineEvent(callTimelineEvent{Type: callTimelineEventCallStart, CreateAt: startAt}, startAt, "alice"))
+	require.Equal(t, "2024-01-01T11:02:03Z (+01:02:03) @bob raised their hand",
+		formatCallTimelineEvent(callTimelineEvent{Type: callTimelineEventRaiseHand, CreateAt: startAt + 3723000}, startAt, "bob"))
+	require.Equal(t, "2024-01-01T10:05:00Z (+00:05:00) Marker: Q&A",
+		formatCallTimelineEvent(callTimelineEvent{Type: callTimelineEventMarker, CreateAt: startAt + 300000, Data: "Q&A"}, startAt, ""))
+	require.Equal(t, "2024-01-01T10:30:00Z (+00:30:00) Call ended (host-ended)",
+		formatCallTimelineEvent(callTimelineEvent{Type: callTimelineEventCallEnd, CreateAt: startAt + 1800000, Data: "host-ended"}, startAt, ""))
+}
+
+func TestAppendCallTimelineEvents(t *testing.T) {
+	mockAPI := &pluginMocks.MockAPI{}
+	defer mockAPI.AssertExpectations(t)
+
+	p := &Plugin{
+		MattermostPlugin: plugin.MattermostPlugin{
+			API: mockAPI,
+		},
+	}
+
+	callID := model.NewId()
+	key := callTimelineKeyPrefix + callID
+	ev := callTimelineEvent{Type: callTimelineEventJoin, CreateAt: 1000, UserID: model.NewId()}
+
+	t.Run("retries on concurrent update", func(t *testing.T) {
+		existing, err := json.Marshal(callTimeline{Events: []callTimelineEvent{ev}})
+		require.NoError(t, err)
+
+		mockAPI.On("KVGet", key).Return(nil, nil).Once()
+		mockAPI.On("KVSetWithOptions", key, mock.Anything, model.PluginKVSetOptions{Atomic: true}).Return(false, nil).Once()
+		mockAPI.On("KVGet", key).Return(existing, nil).Once()
+		mockAPI.On("KVSetWithOptions", key, mock.MatchedBy(func(data []byte) bool {
+			var timeline callTimeline
+			require.NoError(t, json.Unmarshal(data, &timeline))
+			return len(timeline.Events) == 2 && !timeline.Truncated
+		}), model.PluginKVSetOptions{Atomic: true, OldValue: existing}).Return(true, nil).Once()
+
+		require.NoError(t, p.appendCallTimelineEvents(callID, []callTimelineEvent{ev}))
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		events := make([]callTimelineEvent, callTimelineMaxEvents-1)
+		existing, err := json.Marshal(callTimeline{Events: events})
+		require.NoError(t, err)
+
+		mockAPI.On("KVGet", key).Return(existing, nil).Once()
+		mockAPI.On("KVSetWithOptions", key, mock.MatchedBy(func(data []byte) bool {
+			var timeline callTimeline
+			require.NoError(t, json.Unmarshal(data, &timeline))
+			return len(timeline.Events) == callTimelineMaxEvents && timeline.Truncated
+		}), model.PluginKVSetOptions{Atomic: true, OldValue: existing}).Return(true, nil).Once()
+
+		require.NoError(t, p.appendCallTimelineEvents(callID, []callTimelineEvent{ev, ev}))
+
+		// Nothing else is saved once truncated.
+		truncated, err := json.Marshal(callTimeline{Events: events, Truncated: true})
+		require.NoError(t, err)
+		mockAPI.On("KVGet", key).Return(truncated, nil).Once()
+		require.NoError(t, p.appendCallTimelineEvents(callID, []callTimelineEvent{ev}))
+	})
+}
+
+func TestCanAccessCallTimeline(t *testing.T) {
+	mockAPI := &pluginMocks.MockAPI{}
+	defer mockAPI.AssertExpectations(t)
+
+	p := &Plugin{
+		MattermostPlugin: plugin.MattermostPlugin{
+			API: mockAPI,
+		},
+	}
+
+	ownerID := model.NewId()
+	hostID := model.NewId()
+	adminID := model.NewId()
+	userID := model.NewId()
+
+	call := &public.Call{OwnerID: ownerID}
+	events := []callTimelineEvent{{Type: callTimelineEventHostChange, UserID: hostID}}
+
+	require.True(t, p.canAccessCallTimeline(ownerID, call, events))
+	require.True(t, p.canAccessCallTimeline(hostID, call, events))
+
+	mockAPI.On("HasPermissionTo", adminID, model.PermissionManageSystem).Return(true).Once()
+	require.True(t, p.canAccessCallTimeline(adminID, call, events))
+
+	mockAPI.On("HasPermissionTo", userID, model.PermissionManageSystem).Return(false).Once()
+	require.False(t, p.canAccessCallTimeline(userID, call, events))
+}
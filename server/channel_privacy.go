@@ -0,0 +1,82 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/mattermost/mattermost-plugin-calls/server/public"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+// hostRemovedReasonChannelAccessLost is sent along with the host removed
+// event when a participant is removed from a call because they can no longer
+// read the channel.
+const hostRemovedReasonChannelAccessLost = "channel_access_lost"
+
+// getSessionsWithoutChannelAccess returns the sessions of the participants
+// who can no longer read the call's channel.
+func (p *Plugin) getSessionsWithoutChannelAccess(state *callState, channelID string) []*public.CallSession {
+	var sessions []*public.CallSession
+	for _, session := range state.sessions {
+		if p.isBot(session.UserID) {
+			continue
+		}
+		if !p.API.HasPermissionToChannel(session.UserID, channelID, model.PermissionReadChannel) {
+			sessions = append(sessions, session)
+		}
+	}
+	return sessions
+}
+
+// handleChannelPrivacyChange applies the configured policy to the ongoing
+// call, if any, after its channel was converted between public and private.
+func (p *Plugin) handleChannelPrivacyChange(channelID string) error {
+	state, err := p.lockCallReturnState(channelID)
+	if err != nil {
+		return fmt.Errorf("failed to lock call: %w", err)
+	}
+	defer p.unlockCall(channelID)
+
+	if state == nil {
+		return nil
+	}
+
+	sessions := p.getSessionsWithoutChannelAccess(state, channelID)
+	if len(sessions) == 0 {
+		return nil
+	}
+
+	policy := p.getConfiguration().ChannelPrivacyChangePolicy
+
+	p.LogInfo("channel privacy changed during call, participants lost access",
+		"channelID", channelID, "callID", state.Call.ID, "sessions", fmt.Sprintf("%d", len(sessions)), "policy", policy)
+
+	if policy == channelPrivacyChangePolicyEnd {
+		return p.endCall(state, channelID, public.CallEndReasonPrivacyChanged)
+	}
+
+	userIDs := getUserIDsFromSessions(state.sessions)
+	for _, session := range sessions {
+		// We broadcast to all the participants, including the ones being
+		// removed since they can't receive channel events anymore, so
+		// that everyone is told why.
+		p.publishWebSocketEvent(wsEventHostRemoved, map[string]interface{}{
+			"call_id":    state.Call.ID,
+			"channel_id": channelID,
+			"session_id": session.ID,
+			"user_id":    session.UserID,
+			"reason":     hostRemovedReasonChannelAccessLost,
+		}, &WebSocketBroadcast{
+			ChannelID:           channelID,
+			ReliableClusterSend: true,
+			UserIDs:             userIDs,
+		})
+
+		go p.closeSessionAfterGracePeriod(channelID, session.ID)
+	}
+
+	return nil
+}
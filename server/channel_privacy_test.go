@@ -0,0 +1,60 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost-plugin-calls/server/public"
+
+	pluginMocks "github.com/mattermost/mattermost-plugin-calls/server/mocks/github.com/mattermost/mattermost/server/public/plugin"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetSessionsWithoutChannelAccess(t *testing.T) {
+	mockAPI := &pluginMocks.MockAPI{}
+	defer mockAPI.AssertExpectations(t)
+
+	p := &Plugin{
+		MattermostPlugin: plugin.MattermostPlugin{
+			API: mockAPI,
+		},
+		botSession: &model.Session{
+			UserId: "botID",
+		},
+	}
+
+	state := &callState{
+		Call: public.Call{
+			ID:        "callID",
+			ChannelID: "channelID",
+		},
+		sessions: map[string]*public.CallSession{
+			"connA": {ID: "connA", UserID: "userA"},
+			"connB": {ID: "connB", UserID: "userB"},
+			"connC": {ID: "connC", UserID: "botID"},
+		},
+	}
+
+	t.Run("public to private", func(t *testing.T) {
+		// userB joined the public channel's call without being a member.
+		mockAPI.On("HasPermissionToChannel", "userA", "channelID", model.PermissionReadChannel).Return(true).Once()
+		mockAPI.On("HasPermissionToChannel", "userB", "channelID", model.PermissionReadChannel).Return(false).Once()
+
+		sessions := p.getSessionsWithoutChannelAccess(state, "channelID")
+		require.Len(t, sessions, 1)
+		require.Equal(t, "connB", sessions[0].ID)
+	})
+
+	t.Run("private to public", func(t *testing.T) {
+		mockAPI.On("HasPermissionToChannel", "userA", "channelID", model.PermissionReadChannel).Return(true).Once()
+		mockAPI.On("HasPermissionToChannel", "userB", "channelID", model.PermissionReadChannel).Return(true).Once()
+
+		require.Empty(t, p.getSessionsWithoutChannelAccess(state, "channelID"))
+	})
+}
@@ -13,25 +13,48 @@ type clientMessage struct {
 }
 
 const (
-	clientMessageTypeJoin        = "join"
-	clientMessageTypeLeave       = "leave"
-	clientMessageTypeReconnect   = "reconnect"
-	clientMessageTypeSDP         = "sdp"
-	clientMessageTypeICE         = "ice"
-	clientMessageTypeMute        = "mute"
-	clientMessageTypeUnmute      = "unmute"
-	clientMessageTypeVoiceOn     = "voice_on"
-	clientMessageTypeVoiceOff    = "voice_off"
-	clientMessageTypeScreenOn    = "screen_on"
-	clientMessageTypeScreenOff   = "screen_off"
-	clientMessageTypeRaiseHand   = "raise_hand"
-	clientMessageTypeUnraiseHand = "unraise_hand"
-	clientMessageTypeReact       = "react"
-	clientMessageTypeCaption     = "caption"
-	clientMessageTypeMetric      = "metric"
-	clientMessageTypeCallState   = "call_state"
+	clientMessageTypeJoin         = "join"
+	clientMessageTypeLeave        = "leave"
+	clientMessageTypeReconnect    = "reconnect"
+	clientMessageTypeSDP          = "sdp"
+	clientMessageTypeICE          = "ice"
+	clientMessageTypeMute         = "mute"
+	clientMessageTypeUnmute       = "unmute"
+	clientMessageTypeVoiceOn      = "voice_on"
+	clientMessageTypeVoiceOff     = "voice_off"
+	clientMessageTypeScreenOn     = "screen_on"
+	clientMessageTypeScreenOff    = "screen_off"
+	clientMessageTypeVideoOn      = "video_on"
+	clientMessageTypeVideoOff     = "video_off"
+	clientMessageTypeRaiseHand    = "raise_hand"
+	clientMessageTypeUnraiseHand  = "unraise_hand"
+	clientMessageTypeReact        = "react"
+	clientMessageTypeCaption      = "caption"
+	clientMessageTypeMetric       = "metric"
+	clientMessageTypeCallState    = "call_state"
+	clientMessageTypeChat         = "chat"
+	clientMessageTypeLobbyEnter   = "lobby_enter"
+	clientMessageTypeLobbyLeave   = "lobby_leave"
+	clientMessageTypeLobbyPing    = "lobby_ping"
+	clientMessageTypeStartConfirm = "call_start_confirm"
+	clientMessageTypeStartCancel  = "call_start_cancel"
+	clientMessageTypeRecMarker    = "recording_marker"
 )
 
+// isThrottledClientMessage returns whether messages of the given type count
+// towards the combined per-session budget. Only chatty messages that don't
+// change the session's state are limited by it since dropping anything else
+// (e.g. an unmute or a lowered hand) would leave the call out of sync.
+func isThrottledClientMessage(msgType string) bool {
+	switch msgType {
+	case clientMessageTypeReact, clientMessageTypeChat,
+		clientMessageTypeRecMarker:
+		return true
+	default:
+		return false
+	}
+}
+
 func (m *clientMessage) ToJSON() ([]byte, error) {
 	return json.Marshal(m)
 }
@@ -7,6 +7,8 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"github.com/mattermost/mattermost-plugin-calls/server/public"
+
 	"github.com/mattermost/rtcd/service/rtc"
 
 	"github.com/mattermost/mattermost/server/public/model"
@@ -23,17 +25,28 @@ type clusterMessage struct {
 	SenderID      string           `json:"sender_id,omitempty"`
 	SessionProps  rtc.SessionProps `json:"session_props,omitempty"`
 	ClientMessage clientMessage    `json:"client_message,omitempty"`
+	// NoiseAutoMute is used by clusterMessageTypeNoiseAutoMute to inform other
+	// nodes about the call override, nil meaning the configured one applies.
+	NoiseAutoMute *public.CallNoiseAutoMute `json:"noise_auto_mute,omitempty"`
+	// LiveCaptionsOff is used by clusterMessageTypeLiveCaptions to inform
+	// other nodes about the host toggling live captions.
+	LiveCaptionsOff bool `json:"live_captions_off,omitempty"`
 }
 
 type clusterMessageType string
 
 const (
-	clusterMessageTypeConnect    clusterMessageType = "connect"
-	clusterMessageTypeDisconnect clusterMessageType = "disconnect"
-	clusterMessageTypeLeave      clusterMessageType = "leave"
-	clusterMessageTypeReconnect  clusterMessageType = "reconnect"
-	clusterMessageTypeSignaling  clusterMessageType = "signaling"
-	clusterMessageTypeUserState  clusterMessageType = "user_state"
+	clusterMessageTypeConnect       clusterMessageType = "connect"
+	clusterMessageTypeDisconnect    clusterMessageType = "disconnect"
+	clusterMessageTypeLeave         clusterMessageType = "leave"
+	clusterMessageTypeReconnect     clusterMessageType = "reconnect"
+	clusterMessageTypeSignaling     clusterMessageType = "signaling"
+	clusterMessageTypeUserState     clusterMessageType = "user_state"
+	clusterMessageTypeAdmit         clusterMessageType = "admit"
+	clusterMessageTypeDeny          clusterMessageType = "deny"
+	clusterMessageTypeMove          clusterMessageType = "move"
+	clusterMessageTypeNoiseAutoMute clusterMessageType = "noise_auto_mute"
+	clusterMessageTypeLiveCaptions  clusterMessageType = "live_captions"
 )
 
 func (m *clusterMessage) ToJSON() ([]byte, error) {
@@ -9,13 +9,21 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"net/url"
 	"os"
+	"path/filepath"
 	"reflect"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
+
+	"golang.org/x/time/rate"
 
 	"github.com/mattermost/mattermost-plugin-calls/server/license"
+	"github.com/mattermost/mattermost-plugin-calls/server/public"
 
+	recorder "github.com/mattermost/calls-recorder/cmd/recorder/config"
 	transcriber "github.com/mattermost/calls-transcriber/cmd/transcriber/config"
 	"github.com/mattermost/rtcd/service/rtc"
 
@@ -45,6 +53,11 @@ type configuration struct {
 	// The local IP address used by the RTC server to listen on for TCP
 	// connections.
 	TCPServerAddress string
+	// The network interface name (e.g. eth1) or local IP address the RTC
+	// server should bind media sockets to. When set, ICE candidates are only
+	// gathered from its addresses. It cannot be combined with
+	// UDPServerAddress or TCPServerAddress.
+	ICEInterface string
 	// UDP port used by the RTC server to listen to.
 	UDPServerPort *int
 	// TCP port used by the RTC server to listen to.
@@ -52,23 +65,254 @@ type configuration struct {
 	// The URL to a running RTCD service instance that should host the calls.
 	// When set (non empty) all calls will be handled by the external service.
 	RTCDServiceURL string
+	// When set to true the plugin will fall back to the embedded RTC server in case
+	// the connection to the RTCD service cannot be established during activation.
+	// Not supported in High Availability mode.
+	RTCDFallbackToEmbedded *bool
+	// The number of times a message that failed to be sent to the RTCD service
+	// is retried.
+	RTCDSendMaxRetries *int
+	// The maximum time, in milliseconds, spent trying to deliver a message to
+	// the RTCD service, including retries.
+	RTCDSendTimeoutMs *int
+	// The interval, in seconds, at which connections to the RTCD service are
+	// checked and kept warm when idle. The zero value disables it.
+	RTCDKeepaliveIntervalSeconds *int
 	// The secret key used to generate TURN short-lived authentication credentials
 	TURNStaticAuthSecret string
 	// The number of minutes that the generated TURN credentials will be valid for.
 	TURNCredentialsExpirationMinutes *int
+	// A JSON object mapping channel IDs to the ICE servers calls in those
+	// channels should use instead of ICEServersConfigs. Each entry can carry
+	// its own TURNStaticAuthSecret to generate credentials with.
+	ChannelICEServersConfigs string
 	// When set to true it will pass and use configured TURN candidates to server
 	// initiated connections.
 	ServerSideTURN *bool
+	// When set to true TURN servers are only ever used with short-lived
+	// credentials generated from TURNStaticAuthSecret, both by clients and by
+	// the RTC server, so that relay usage can be traced back to a user (or
+	// session) and credentials expire on their own. Static credentials
+	// configured for TURN servers are ignored.
+	EnforceShortLivedTURNCredentials *bool
+	// A comma separated list of ICE candidate types (host, srflx, relay)
+	// clients are allowed to signal. Candidates of other types are
+	// dropped. Leaving it empty allows all types.
+	AllowedICECandidateTypes string
+	// The maximum number of ICE candidates advertised to each session. Zero
+	// means no limit.
+	MaxICECandidatesPerSession *int
+	// A comma separated list of ICE candidate types, in order of priority,
+	// that get advertised first when MaxICECandidatesPerSession is set.
+	ICECandidatesPriority string
+	// The maximum size, in KB, of the session descriptions (SDP offers and
+	// answers) clients can signal. Larger ones are rejected.
+	MaxSDPSizeKB *int
+	// The number of seconds a joining session has to establish its ICE
+	// connection before the join is aborted. Only clients reporting when they
+	// are connected are subject to it. The zero value means no timeout.
+	ICEConnectionTimeoutSeconds *int
+	// The number of seconds a session's media connection can go without
+	// activity, while its WebSocket connection is alive, before the session is
+	// considered dead and disconnected. Only applies to clients reporting media
+	// keepalives. The zero value disables the check.
+	MediaInactivityTimeoutSeconds *int
+	// The number of seconds a session's media connection can go without
+	// activity before the server asks the client to restart ICE, repeating the
+	// request at the same interval until media resumes. Only applies to
+	// clients reporting media keepalives. The zero value disables
+	// server-initiated restarts.
+	ICERestartTimeoutSeconds *int
+	// The number of chatty messages (i.e. reactions, chat, recording markers)
+	// per second a session can send on average, across all features. Messages
+	// above the budget are dropped. The zero value means no limit.
+	SessionMessageRateLimit *int
+	// The number of chatty messages a session can send in a burst.
+	SessionMessageBurst *int
+	// When enabled, participants whose audio is continuously detected as voice
+	// activity, without the pauses speech has, for longer than
+	// NoiseAutoMuteThresholdSeconds are considered noisy and automatically
+	// muted. They are notified and can unmute when ready. The threshold
+	// defaults to two minutes as someone lecturing can talk for a while
+	// without pausing long enough for voice activity to end; lowering it
+	// risks muting them.
+	EnableNoiseAutoMute           *bool
+	NoiseAutoMuteThresholdSeconds *int
+	// The maximum number of calls that can be ongoing at the same time. The
+	// zero value means no limit. On Cloud this is set by the license tier.
+	MaxConcurrentCalls *int
+	// What happens when a user joins a call they are already connected to
+	// from another device: "allow" keeps both sessions while "replace" ends
+	// the older one.
+	MultiDeviceJoinPolicy string
+	// Who becomes host when a call starts: "initiator" (the user starting
+	// it), "channel_admin" (the first channel admin to join, falling back to
+	// the initiator) or "none".
+	HostAssignmentPolicy string
+	// What happens to an ongoing call when its channel is converted between
+	// public and private: "remove" removes the participants who lost access
+	// to the channel while "end" ends the call for everyone if any did.
+	ChannelPrivacyChangePolicy string
+	// The time (in seconds) after which a raised hand is automatically
+	// lowered. The timer restarts whenever the host uses host controls. 0
+	// (default) means hands are never lowered automatically.
+	RaiseHandAutoLowerTimeoutSeconds *int
+	// The time (in seconds) participants removed from a call by the host
+	// have to wait before they can join it again.
+	KickedRejoinCooldownSeconds *int
+	// The time (in seconds) participants dropped from a call (e.g. their
+	// connection was lost) have to wait before they can join it again. 0
+	// (default) lets them rejoin immediately.
+	DroppedRejoinCooldownSeconds *int
+	// When set to true users who can read but not post in a channel (e.g.
+	// channels moderated to prevent members from posting) can start and join
+	// calls in it.
+	AllowCallsInReadOnlyChannels *bool
+	// The URL to an external service (e.g. attendance tracking) to be notified
+	// when participants join or leave calls.
+	ParticipantWebhookURL string
+	// The secret used to sign participant webhook requests (HMAC-SHA256).
+	ParticipantWebhookSecret string
+	// When set to true (default) the participant webhook is notified of joins.
+	EnableJoinWebhook *bool
+	// When set to true (default) the participant webhook is notified of leaves.
+	EnableLeaveWebhook *bool
 	// The URL to a running calls-offloader job service instance.
 	JobServiceURL string
+	// An optional HTTP(S) proxy URL used for outbound connections to the
+	// rtcd service, the job service and the recording webhook. It takes
+	// precedence over the standard HTTP_PROXY/HTTPS_PROXY environment variables.
+	OutboundProxyURL string
+	// The URL to a Prometheus Pushgateway compatible endpoint key call metrics
+	// (e.g. active calls, participants, recording jobs) are periodically
+	// pushed to. It's meant for deployments that cannot scrape the plugin.
+	MetricsPushURL string
+	// The interval (in seconds) at which metrics are pushed.
+	MetricsPushIntervalSeconds *int
+	// The number of times connecting to the database is retried on
+	// activation before giving up.
+	DBConnectRetries *int
+	// The number of calls whose stale state is cleaned up concurrently on
+	// activation. Raising it speeds up startup on instances with many calls
+	// left over.
+	CleanUpStateWorkers *int
+	// The time (in seconds) to wait before the first database connection
+	// retry. It doubles after every failed attempt.
+	DBConnectRetryDelaySeconds *int
+	// When set to true call and job records that could not be saved because the
+	// database is unavailable are kept in memory and saved again once it
+	// recovers.
+	EnableDBWriteBuffer *bool
 	// The audio and video quality of call recordings.
 	RecordingQuality string
+	// The resolution of call recordings (e.g. "720p"). When set, it overrides
+	// the resolution implied by RecordingQuality.
+	RecordingResolution string
+	// The framerate of call recordings. When greater than zero, it overrides
+	// the framerate implied by RecordingQuality.
+	RecordingFrameRate *int
+	// The container format of call recordings. Only "mp4" (H.264/AAC) is
+	// currently supported by the recorder.
+	RecordingOutputFormat string
+	// A comma separated list of additional quality profiles (e.g. "low") to
+	// record calls with, alongside RecordingQuality. Each profile runs as a
+	// separate recording job and is uploaded as a separate file. Leaving it
+	// empty records a single file.
+	RecordingAdditionalQualities string
+	// A template for the text to burn onto call recordings (e.g.
+	// "CONFIDENTIAL - {channel_name} - {date} {time}"). Compositing the
+	// overlay adds to the CPU cost of the recording job. Leaving it empty
+	// disables the watermark.
+	RecordingWatermarkTemplate string
+	// The default layout of call recordings: "grid" tiles all the
+	// participants while "active_speaker" follows whoever is speaking. Hosts
+	// can pick a different one when starting a recording.
+	RecordingLayout string
+	// When set to true participants are shown with pseudonyms (e.g.
+	// "Participant 1") in place of their names and avatars in recordings and
+	// transcripts. The mapping is kept for system admins.
+	AnonymizeRecordings *bool
+	// When set to true participants get a direct message from the bot with a
+	// link to the recording of a call they took part in once it's available.
+	// It can be overridden on a per channel basis.
+	NotifyParticipantsOfRecordings *bool
+	// The maximum total size (in MB) of the call recordings stored in a
+	// single channel. Zero means no limit.
+	MaxRecordingStoragePerChannelMB *int
+	// The maximum total size (in MB) of the call recordings stored across
+	// the channels of a team. Zero means no limit.
+	MaxRecordingStoragePerTeamMB *int
+	// What to do when a recording storage quota is exceeded: "block" prevents
+	// new recordings from starting while "delete_oldest" deletes the oldest
+	// recordings to make room for new ones.
+	RecordingStorageQuotaPolicy string
+	// The URL to an external service (e.g. AI summarization) to be notified
+	// when a call recording is available.
+	RecordingWebhookURL string
+	// An optional token sent as Bearer authorization to the recording webhook.
+	RecordingWebhookAuthToken string
+	// The maximum time (in seconds) to wait for the recording webhook to respond.
+	RecordingWebhookTimeoutSeconds *int
+	// The number of times a failed recording webhook request is retried.
+	RecordingWebhookMaxRetries *int
+	// The number of times a failed upload of a recording to the file store is
+	// retried before giving up and reporting it as failed.
+	RecordingUploadMaxRetries *int
+	// The local directory where recording uploads are spooled until they are
+	// successfully stored. Defaults to a directory in the system's temporary
+	// path when empty.
+	RecordingUploadSpoolDirectory string
+	// A comma separated list of keywords that automatically start a recording
+	// when posted in a channel with an ongoing call.
+	RecordingKeywordTriggers string
+	// A comma separated list of channel IDs in which RecordingKeywordTriggers
+	// apply. Keyword triggers are disabled when empty.
+	RecordingKeywordTriggerChannels string
+	// The number of seconds an ongoing recording keeps going after the last
+	// participant has left the call. Once elapsed, the recording is stopped
+	// and the call ends. The zero value stops the recording right away.
+	RecordingEmptyCallGracePeriodSeconds *int
+	// The number of seconds before a call is automatically ended for
+	// inactivity that participants are warned, giving them a chance to keep
+	// it going. The zero value disables the warning.
+	CallEndWarningSeconds *int
 	// When set to true the RTC service will work in dual-stack mode, listening for IPv6
 	// connections and generating candidates in addition to IPv4 ones.
 	EnableIPv6 *bool
+	// When set to true the quality of calls hosted by the embedded RTC server
+	// is reduced while the node is under CPU pressure.
+	EnableCPUQualityDowngrade *bool
+	// The CPU usage, in percent, past which the quality of calls is reduced.
+	CPUQualityDowngradeThreshold *int
+	// The CPU usage, in percent, under which the quality of calls is restored.
+	// It must be lower than CPUQualityDowngradeThreshold.
+	CPUQualityRestoreThreshold *int
+	// When set to true hosts are alerted of participants whose connection
+	// quality stays poor for a while.
+	EnablePoorConnectionAlerts *bool
+	// The round trip time, in milliseconds, past which a participant's
+	// connection is considered poor.
+	PoorConnectionRTTThresholdMs *int
+	// The packet loss, in percent, past which a participant's connection is
+	// considered poor.
+	PoorConnectionLossThreshold *int
+	// How long, in seconds, a participant's connection needs to stay poor
+	// before alerting the host, so that momentary blips are ignored.
+	PoorConnectionAlertDelaySeconds *int
 	// Ringing is default off (for now -- 8.0), allow sysadmins to turn it on.
 	// When set to true it enables ringing for DM/GM channels.
 	EnableRinging *bool
+	// When set to true (default) offline users are sent push notifications
+	// when a call starts. This is independent from chat message notifications.
+	EnableCallPushNotifications *bool
+	// When set to true (default) participant events (e.g. joins, mutes, host
+	// changes) are persisted so that the timeline of a call can be exported.
+	EnableCallTimeline *bool
+	// When set to true participants are sent a direct message after some calls
+	// asking them to rate the call quality.
+	EnableCallFeedback *bool
+	// The percentage of calls participants are asked for feedback after.
+	CallFeedbackSampleRate *int
 	// The speech-to-text model size to use to transcribe calls.
 	TranscriberModelSize transcriber.ModelSize
 	// The speech-to-text API to use to transcribe calls.
@@ -79,6 +323,10 @@ type configuration struct {
 	TranscribeAPIAzureSpeechRegion string
 	// The number of threads to use to transcriber calls.
 	TranscriberNumThreads *int
+	// The maximum number of transcription jobs running at the same time across
+	// the cluster. Transcriptions requested past this limit wait in queue
+	// until a job finishes. The zero value means unlimited.
+	MaxConcurrentTranscriptionJobs *int
 	// When set to true live captions will be enabled when starting transcription jobs.
 	EnableLiveCaptions *bool
 	// The speech-to-text model size to use to transcribe live captions.
@@ -108,11 +356,33 @@ type ClientConfig struct {
 	// TestMode="on" -> DefaultEnabled=false
 	// When TestMode is set to off (DefaultEnabled=true), calls will be possible in all channels where they are not explicitly disabled.
 	DefaultEnabled *bool
+	// A comma separated list of team IDs in which calls are enabled by default,
+	// overriding DefaultEnabled. Channel settings still take precedence.
+	EnabledTeams string
+	// A comma separated list of team IDs in which calls are disabled by default,
+	// overriding DefaultEnabled. Channel settings still take precedence.
+	DisabledTeams string
+	// Whether calls are enabled by default in direct message channels. Either
+	// "inherit" (default), following DefaultEnabled, "enabled" or "disabled".
+	// Channel settings still take precedence.
+	DMCallsDefault string
+	// Whether calls are enabled by default in group message channels. Either
+	// "inherit" (default), following DefaultEnabled, "enabled" or "disabled".
+	// Channel settings still take precedence.
+	GMCallsDefault string
 	// The maximum number of participants that can join a call. The zero value
 	// means unlimited.
 	MaxCallParticipants *int
+	// The maximum number of participants that can join a call in a group
+	// message channel. The zero value means MaxCallParticipants applies. It
+	// can only lower the global limit.
+	MaxGMCallParticipants *int
 	// Used to signal the client whether or not to generate TURN credentials. This is a client only option, generated server side.
 	NeedsTURNCredentials *bool
+	// The ICE transport policy (all, relay) clients should gather candidates
+	// with, derived from AllowedICECandidateTypes. This is a client only
+	// option, generated server side.
+	ICETransportPolicy string
 	// When set to true it allows call participants to share their screen.
 	AllowScreenSharing *bool
 	// When set to true it enables the call recordings functionality
@@ -127,6 +397,12 @@ type ClientConfig struct {
 	EnableSimulcast *bool
 	// When set to true it enables ringing for DM/GM channels.
 	EnableRinging *bool
+	// When set to true (default) starting a call in a direct message channel
+	// rings the other participant. Otherwise the call only posts a message.
+	EnableDMRinging *bool
+	// When set to true (default) starting a call in a group message channel
+	// rings the other members. Otherwise the call only posts a message.
+	EnableGMRinging *bool
 	// (Cloud) License information that isn't exposed to clients yet on the webapp
 	SkuShortName string `json:"sku_short_name"`
 	// Let the server determine whether or not host controls are allowed (through license checks or otherwise)
@@ -137,6 +413,52 @@ type ClientConfig struct {
 	GroupCallsAllowed bool
 	// When set to true it enables experimental support for using the data channel for signaling.
 	EnableDCSignaling *bool
+	// When set to true participants join calls muted and their audio is not
+	// forwarded until they explicitly unmute. It can be overridden on a per
+	// channel basis.
+	JoinMuted *bool
+	// When set to true (default) clients are recommended to apply noise
+	// suppression to their audio in new calls. Hosts can change it for an
+	// ongoing call. Audio is not processed server side, so this only
+	// standardizes the client-side setting across clients.
+	NoiseSuppression *bool
+	// When set to true starting a new call requires a confirmation from the
+	// client before the call is created and the channel notified. It can be
+	// overridden on a per channel basis.
+	ConfirmCallStart *bool
+	// When set to true participants joining an ongoing call wait until the
+	// host admits them. It can be overridden on a per channel basis and
+	// toggled by the host during the call.
+	WaitingRoom *bool
+	// When set to true the host also gets a push notification when someone
+	// starts waiting to be admitted.
+	WaitingRoomPushNotifications *bool
+	// When set to true clients are not allowed to turn on their camera in
+	// calls. Screen sharing is controlled separately by AllowScreenSharing.
+	DisableVideo *bool
+	// The maximum number of participants that can have their camera on at the
+	// same time in a call. Listeners and audio only participants don't count
+	// towards the limit. If set to 0 (default) there's no limit.
+	MaxVideoPublishers *int
+	// The maximum number of participants that can share their screen at the
+	// same time in a call. Defaults to 1.
+	MaxScreenShares *int
+	// A comma separated list of tags (e.g. "standup,interview,incident") calls
+	// can be categorized with when started. Leaving it empty disables tagging.
+	AllowedCallTags string
+	// A comma separated list of interactive features (reactions, chat,
+	// raise_hand) disabled by default in calls. Hosts can turn them back on
+	// when starting the call or during it.
+	DefaultDisabledCallFeatures string
+	// A JSON array of named call presets (e.g. "Webinar", "Standup") hosts can
+	// pick when starting a call. Each preset can set whether participants join
+	// muted, the waiting room, audio only and automatic recording.
+	CallPresets string
+	// When set to true participants can exchange text messages during a call.
+	EnableCallChat *bool
+	// When set to true (default) in-call chat messages are also posted by the
+	// bot to the call thread so that they persist after the call ends.
+	CallChatPostToThread *bool
 }
 
 const (
@@ -145,6 +467,111 @@ const (
 	maxRecDurationMinutes     = 180
 	minAllowedPort            = 80
 	maxAllowedPort            = 49151
+
+	defaultRecWebhookTimeoutSeconds = 30
+	maxRecWebhookTimeoutSeconds     = 300
+	defaultRecWebhookMaxRetries     = 3
+	maxRecWebhookMaxRetries         = 10
+	defaultRecUploadMaxRetries      = 3
+	maxRecUploadMaxRetries          = 10
+
+	maxConcurrentTranscriptionJobs = 1000
+
+	maxRecWatermarkTemplateLen = 256
+
+	maxRecStorageQuotaMB = 10 * 1024 * 1024
+
+	maxICEConnectionTimeoutSeconds = 300
+
+	maxMaxICECandidatesPerSession = 64
+	defaultICECandidatesPriority  = "relay,srflx"
+
+	defaultMaxSDPSizeKB = 64
+	minMaxSDPSizeKB     = 4
+	maxMaxSDPSizeKB     = 1024
+
+	defaultMediaInactivityTimeoutSeconds = 60
+	minMediaInactivityTimeoutSeconds     = 15
+	maxMediaInactivityTimeoutSeconds     = 600
+
+	defaultICERestartTimeoutSeconds = 10
+	minICERestartTimeoutSeconds     = 2
+	maxICERestartTimeoutSeconds     = 120
+
+	defaultRTCDSendMaxRetries = 3
+	maxRTCDSendMaxRetries     = 10
+	defaultRTCDSendTimeoutMs  = 5000
+	minRTCDSendTimeoutMs      = 100
+	maxRTCDSendTimeoutMs      = 30000
+
+	defaultRTCDKeepaliveIntervalSeconds = 30
+	maxRTCDKeepaliveIntervalSeconds     = 3600
+
+	defaultMetricsPushIntervalSeconds = 60
+	minMetricsPushIntervalSeconds     = 10
+	maxMetricsPushIntervalSeconds     = 3600
+
+	maxRaiseHandAutoLowerTimeoutSeconds = 3600
+
+	defaultKickedRejoinCooldownSeconds = 60
+	maxRejoinCooldownSeconds           = 3600
+
+	defaultCPUQualityDowngradeThreshold = 85
+	defaultCPUQualityRestoreThreshold   = 70
+
+	defaultPoorConnectionRTTThresholdMs    = 500
+	minPoorConnectionRTTThresholdMs        = 50
+	maxPoorConnectionRTTThresholdMs        = 10000
+	defaultPoorConnectionLossThreshold     = 10
+	defaultPoorConnectionAlertDelaySeconds = 15
+	maxPoorConnectionAlertDelaySeconds     = 300
+
+	defaultCleanUpStateWorkers = 4
+	maxCleanUpStateWorkers     = 32
+
+	defaultDBConnectRetries           = 3
+	maxDBConnectRetries               = 100
+	defaultDBConnectRetryDelaySeconds = 5
+	maxDBConnectRetryDelaySeconds     = 300
+
+	defaultSessionMessageRateLimit = 5
+	maxSessionMessageRateLimit     = 1000
+	defaultSessionMessageBurst     = 25
+	maxSessionMessageBurst         = 10000
+
+	defaultNoiseAutoMuteThresholdSeconds = 120
+
+	maxRecEmptyCallGracePeriodSeconds = 3600
+
+	defaultCallEndWarningSeconds = 60
+	maxCallEndWarningSeconds     = 600
+
+	defaultCallFeedbackSampleRate = 10
+
+	maxScreenShares = 4
+
+	maxCallTags   = 50
+	maxCallTagLen = 32
+
+	channelTypeCallsDefaultInherit  = "inherit"
+	channelTypeCallsDefaultEnabled  = "enabled"
+	channelTypeCallsDefaultDisabled = "disabled"
+
+	multiDeviceJoinPolicyAllow   = "allow"
+	multiDeviceJoinPolicyReplace = "replace"
+
+	hostAssignmentPolicyInitiator    = "initiator"
+	hostAssignmentPolicyChannelAdmin = "channel_admin"
+	hostAssignmentPolicyNone         = "none"
+
+	channelPrivacyChangePolicyRemove = "remove"
+	channelPrivacyChangePolicyEnd    = "end"
+
+	recordingLayoutGrid          = "grid"
+	recordingLayoutActiveSpeaker = "active_speaker"
+
+	recordingStorageQuotaPolicyBlock        = "block"
+	recordingStorageQuotaPolicyDeleteOldest = "delete_oldest"
 )
 
 type (
@@ -203,12 +630,45 @@ func (c *configuration) SetDefaults() {
 	if c.MaxCallParticipants == nil {
 		c.MaxCallParticipants = model.NewPointer(0) // unlimited
 	}
+	if c.DMCallsDefault == "" {
+		c.DMCallsDefault = channelTypeCallsDefaultInherit
+	}
+	if c.GMCallsDefault == "" {
+		c.GMCallsDefault = channelTypeCallsDefaultInherit
+	}
+	if c.MaxGMCallParticipants == nil {
+		c.MaxGMCallParticipants = model.NewPointer(0)
+	}
+	if c.MultiDeviceJoinPolicy == "" {
+		c.MultiDeviceJoinPolicy = multiDeviceJoinPolicyAllow
+	}
+	if c.HostAssignmentPolicy == "" {
+		c.HostAssignmentPolicy = hostAssignmentPolicyInitiator
+	}
+	if c.ChannelPrivacyChangePolicy == "" {
+		c.ChannelPrivacyChangePolicy = channelPrivacyChangePolicyRemove
+	}
+	if c.RaiseHandAutoLowerTimeoutSeconds == nil {
+		c.RaiseHandAutoLowerTimeoutSeconds = model.NewPointer(0) // never
+	}
+	if c.KickedRejoinCooldownSeconds == nil {
+		c.KickedRejoinCooldownSeconds = model.NewPointer(defaultKickedRejoinCooldownSeconds)
+	}
+	if c.DroppedRejoinCooldownSeconds == nil {
+		c.DroppedRejoinCooldownSeconds = model.NewPointer(0)
+	}
+	if c.MaxConcurrentCalls == nil {
+		c.MaxConcurrentCalls = model.NewPointer(0) // unlimited
+	}
 	if c.TURNCredentialsExpirationMinutes == nil {
 		c.TURNCredentialsExpirationMinutes = model.NewPointer(1440)
 	}
 	if c.ServerSideTURN == nil {
 		c.ServerSideTURN = model.NewPointer(false)
 	}
+	if c.EnforceShortLivedTURNCredentials == nil {
+		c.EnforceShortLivedTURNCredentials = model.NewPointer(false)
+	}
 	if c.AllowScreenSharing == nil {
 		c.AllowScreenSharing = model.NewPointer(true)
 	}
@@ -221,21 +681,66 @@ func (c *configuration) SetDefaults() {
 	if c.TranscriberNumThreads == nil {
 		c.TranscriberNumThreads = model.NewPointer(transcriber.NumThreadsDefault)
 	}
+	if c.MaxConcurrentTranscriptionJobs == nil {
+		c.MaxConcurrentTranscriptionJobs = model.NewPointer(0)
+	}
 	if c.MaxRecordingDuration == nil {
 		c.MaxRecordingDuration = model.NewPointer(defaultRecDurationMinutes)
 	}
 	if c.RecordingQuality == "" {
 		c.RecordingQuality = "medium"
 	}
+	if c.RecordingFrameRate == nil {
+		c.RecordingFrameRate = model.NewPointer(0)
+	}
+	if c.RecordingOutputFormat == "" {
+		c.RecordingOutputFormat = string(recorder.AVFormatMP4)
+	}
+	if c.RecordingLayout == "" {
+		c.RecordingLayout = recordingLayoutGrid
+	}
+	if c.RecordingStorageQuotaPolicy == "" {
+		c.RecordingStorageQuotaPolicy = recordingStorageQuotaPolicyBlock
+	}
 	if c.EnableSimulcast == nil {
 		c.EnableSimulcast = model.NewPointer(false)
 	}
 	if c.EnableIPv6 == nil {
 		c.EnableIPv6 = model.NewPointer(false)
 	}
+	if c.EnableCPUQualityDowngrade == nil {
+		c.EnableCPUQualityDowngrade = model.NewPointer(false)
+	}
+	if c.CPUQualityDowngradeThreshold == nil {
+		c.CPUQualityDowngradeThreshold = model.NewPointer(defaultCPUQualityDowngradeThreshold)
+	}
+	if c.CPUQualityRestoreThreshold == nil {
+		c.CPUQualityRestoreThreshold = model.NewPointer(defaultCPUQualityRestoreThreshold)
+	}
+	if c.EnablePoorConnectionAlerts == nil {
+		c.EnablePoorConnectionAlerts = model.NewPointer(false)
+	}
+	if c.PoorConnectionRTTThresholdMs == nil {
+		c.PoorConnectionRTTThresholdMs = model.NewPointer(defaultPoorConnectionRTTThresholdMs)
+	}
+	if c.PoorConnectionLossThreshold == nil {
+		c.PoorConnectionLossThreshold = model.NewPointer(defaultPoorConnectionLossThreshold)
+	}
+	if c.PoorConnectionAlertDelaySeconds == nil {
+		c.PoorConnectionAlertDelaySeconds = model.NewPointer(defaultPoorConnectionAlertDelaySeconds)
+	}
 	if c.EnableRinging == nil {
 		c.EnableRinging = model.NewPointer(false)
 	}
+	if c.EnableDMRinging == nil {
+		c.EnableDMRinging = model.NewPointer(true)
+	}
+	if c.EnableGMRinging == nil {
+		c.EnableGMRinging = model.NewPointer(true)
+	}
+	if c.EnableCallPushNotifications == nil {
+		c.EnableCallPushNotifications = model.NewPointer(true)
+	}
 	if c.TranscriberModelSize == "" {
 		c.TranscriberModelSize = transcriber.ModelSizeDefault
 	}
@@ -263,6 +768,138 @@ func (c *configuration) SetDefaults() {
 	if c.EnableDCSignaling == nil {
 		c.EnableDCSignaling = model.NewPointer(false)
 	}
+	if c.RTCDFallbackToEmbedded == nil {
+		c.RTCDFallbackToEmbedded = model.NewPointer(false)
+	}
+	if c.RTCDSendMaxRetries == nil {
+		c.RTCDSendMaxRetries = model.NewPointer(defaultRTCDSendMaxRetries)
+	}
+	if c.RTCDSendTimeoutMs == nil {
+		c.RTCDSendTimeoutMs = model.NewPointer(defaultRTCDSendTimeoutMs)
+	}
+	if c.RTCDKeepaliveIntervalSeconds == nil {
+		c.RTCDKeepaliveIntervalSeconds = model.NewPointer(defaultRTCDKeepaliveIntervalSeconds)
+	}
+	if c.AllowCallsInReadOnlyChannels == nil {
+		c.AllowCallsInReadOnlyChannels = model.NewPointer(false)
+	}
+	if c.EnableJoinWebhook == nil {
+		c.EnableJoinWebhook = model.NewPointer(true)
+	}
+	if c.EnableLeaveWebhook == nil {
+		c.EnableLeaveWebhook = model.NewPointer(true)
+	}
+	if c.JoinMuted == nil {
+		c.JoinMuted = model.NewPointer(false)
+	}
+	if c.NoiseSuppression == nil {
+		c.NoiseSuppression = model.NewPointer(true)
+	}
+	if c.ConfirmCallStart == nil {
+		c.ConfirmCallStart = model.NewPointer(false)
+	}
+	if c.WaitingRoom == nil {
+		c.WaitingRoom = model.NewPointer(false)
+	}
+	if c.WaitingRoomPushNotifications == nil {
+		c.WaitingRoomPushNotifications = model.NewPointer(false)
+	}
+	if c.DisableVideo == nil {
+		c.DisableVideo = model.NewPointer(false)
+	}
+	if c.MaxVideoPublishers == nil {
+		c.MaxVideoPublishers = model.NewPointer(0) // unlimited
+	}
+	if c.MaxScreenShares == nil {
+		c.MaxScreenShares = model.NewPointer(1)
+	}
+	if c.EnableCallChat == nil {
+		c.EnableCallChat = model.NewPointer(false)
+	}
+	if c.EnableCallTimeline == nil {
+		c.EnableCallTimeline = model.NewPointer(true)
+	}
+	if c.EnableCallFeedback == nil {
+		c.EnableCallFeedback = model.NewPointer(false)
+	}
+	if c.CallFeedbackSampleRate == nil {
+		c.CallFeedbackSampleRate = model.NewPointer(defaultCallFeedbackSampleRate)
+	}
+	if c.CallChatPostToThread == nil {
+		c.CallChatPostToThread = model.NewPointer(true)
+	}
+	if c.ICEConnectionTimeoutSeconds == nil {
+		c.ICEConnectionTimeoutSeconds = model.NewPointer(0)
+	}
+	if c.MaxICECandidatesPerSession == nil {
+		c.MaxICECandidatesPerSession = model.NewPointer(0)
+	}
+	if c.ICECandidatesPriority == "" {
+		c.ICECandidatesPriority = defaultICECandidatesPriority
+	}
+	if c.MaxSDPSizeKB == nil {
+		c.MaxSDPSizeKB = model.NewPointer(defaultMaxSDPSizeKB)
+	}
+	if c.MediaInactivityTimeoutSeconds == nil {
+		c.MediaInactivityTimeoutSeconds = model.NewPointer(defaultMediaInactivityTimeoutSeconds)
+	}
+	if c.ICERestartTimeoutSeconds == nil {
+		c.ICERestartTimeoutSeconds = model.NewPointer(defaultICERestartTimeoutSeconds)
+	}
+	if c.MetricsPushIntervalSeconds == nil {
+		c.MetricsPushIntervalSeconds = model.NewPointer(defaultMetricsPushIntervalSeconds)
+	}
+	if c.DBConnectRetries == nil {
+		c.DBConnectRetries = model.NewPointer(defaultDBConnectRetries)
+	}
+	if c.CleanUpStateWorkers == nil {
+		c.CleanUpStateWorkers = model.NewPointer(defaultCleanUpStateWorkers)
+	}
+	if c.DBConnectRetryDelaySeconds == nil {
+		c.DBConnectRetryDelaySeconds = model.NewPointer(defaultDBConnectRetryDelaySeconds)
+	}
+	if c.EnableDBWriteBuffer == nil {
+		c.EnableDBWriteBuffer = model.NewPointer(false)
+	}
+	if c.SessionMessageRateLimit == nil {
+		c.SessionMessageRateLimit = model.NewPointer(defaultSessionMessageRateLimit)
+	}
+	if c.SessionMessageBurst == nil {
+		c.SessionMessageBurst = model.NewPointer(defaultSessionMessageBurst)
+	}
+	if c.EnableNoiseAutoMute == nil {
+		c.EnableNoiseAutoMute = model.NewPointer(false)
+	}
+	if c.NoiseAutoMuteThresholdSeconds == nil {
+		c.NoiseAutoMuteThresholdSeconds = model.NewPointer(defaultNoiseAutoMuteThresholdSeconds)
+	}
+	if c.RecordingWebhookTimeoutSeconds == nil {
+		c.RecordingWebhookTimeoutSeconds = model.NewPointer(defaultRecWebhookTimeoutSeconds)
+	}
+	if c.RecordingEmptyCallGracePeriodSeconds == nil {
+		c.RecordingEmptyCallGracePeriodSeconds = model.NewPointer(0)
+	}
+	if c.CallEndWarningSeconds == nil {
+		c.CallEndWarningSeconds = model.NewPointer(defaultCallEndWarningSeconds)
+	}
+	if c.RecordingWebhookMaxRetries == nil {
+		c.RecordingWebhookMaxRetries = model.NewPointer(defaultRecWebhookMaxRetries)
+	}
+	if c.RecordingUploadMaxRetries == nil {
+		c.RecordingUploadMaxRetries = model.NewPointer(defaultRecUploadMaxRetries)
+	}
+	if c.NotifyParticipantsOfRecordings == nil {
+		c.NotifyParticipantsOfRecordings = model.NewPointer(false)
+	}
+	if c.MaxRecordingStoragePerChannelMB == nil {
+		c.MaxRecordingStoragePerChannelMB = model.NewPointer(0)
+	}
+	if c.MaxRecordingStoragePerTeamMB == nil {
+		c.MaxRecordingStoragePerTeamMB = model.NewPointer(0)
+	}
+	if c.AnonymizeRecordings == nil {
+		c.AnonymizeRecordings = model.NewPointer(false)
+	}
 }
 
 func (c *configuration) IsValid() error {
@@ -274,6 +911,23 @@ func (c *configuration) IsValid() error {
 		return fmt.Errorf("TCPServerAddress parsing failed")
 	}
 
+	if c.ICEInterface != "" && (c.UDPServerAddress != "" || c.TCPServerAddress != "") {
+		return fmt.Errorf("ICEInterface is not valid: cannot be combined with UDPServerAddress or TCPServerAddress")
+	}
+
+	if allowedTypes := c.getAllowedICECandidateTypes(); len(allowedTypes) > 0 {
+		for _, typ := range allowedTypes {
+			if !slices.Contains(iceCandidateTypes, typ) {
+				return fmt.Errorf("AllowedICECandidateTypes is not valid: %q is not a valid candidate type", typ)
+			}
+		}
+
+		if !slices.Contains(allowedTypes, iceCandidateTypeHost) && !slices.Contains(allowedTypes, iceCandidateTypeSrflx) &&
+			!slices.ContainsFunc(c.ICEServersConfigs, func(cfg rtc.ICEServerConfig) bool { return cfg.IsTURN() }) {
+			return fmt.Errorf("AllowedICECandidateTypes is not valid: allowing only relay candidates requires a TURN server to be configured")
+		}
+	}
+
 	if c.UDPServerPort == nil {
 		return fmt.Errorf("UDPServerPort should not be nil")
 	}
@@ -290,14 +944,101 @@ func (c *configuration) IsValid() error {
 		return fmt.Errorf("TCPServerPort is not valid: %d is not in allowed range [%d, %d]", *c.TCPServerPort, minAllowedPort, maxAllowedPort)
 	}
 
+	if c.CPUQualityDowngradeThreshold != nil && (*c.CPUQualityDowngradeThreshold < 1 || *c.CPUQualityDowngradeThreshold > 100) {
+		return fmt.Errorf("CPUQualityDowngradeThreshold is not valid: range should be [1, 100]")
+	}
+
+	if c.CPUQualityRestoreThreshold != nil && c.CPUQualityDowngradeThreshold != nil &&
+		(*c.CPUQualityRestoreThreshold < 0 || *c.CPUQualityRestoreThreshold >= *c.CPUQualityDowngradeThreshold) {
+		return fmt.Errorf("CPUQualityRestoreThreshold is not valid: should be lower than CPUQualityDowngradeThreshold")
+	}
+
+	if c.PoorConnectionRTTThresholdMs != nil && (*c.PoorConnectionRTTThresholdMs < minPoorConnectionRTTThresholdMs || *c.PoorConnectionRTTThresholdMs > maxPoorConnectionRTTThresholdMs) {
+		return fmt.Errorf("PoorConnectionRTTThresholdMs is not valid: range should be [%d, %d]", minPoorConnectionRTTThresholdMs, maxPoorConnectionRTTThresholdMs)
+	}
+
+	if c.PoorConnectionLossThreshold != nil && (*c.PoorConnectionLossThreshold < 1 || *c.PoorConnectionLossThreshold > 100) {
+		return fmt.Errorf("PoorConnectionLossThreshold is not valid: range should be [1, 100]")
+	}
+
+	if c.PoorConnectionAlertDelaySeconds != nil && (*c.PoorConnectionAlertDelaySeconds < 1 || *c.PoorConnectionAlertDelaySeconds > maxPoorConnectionAlertDelaySeconds) {
+		return fmt.Errorf("PoorConnectionAlertDelaySeconds is not valid: range should be [1, %d]", maxPoorConnectionAlertDelaySeconds)
+	}
+
 	if c.MaxCallParticipants == nil || *c.MaxCallParticipants < 0 {
 		return fmt.Errorf("MaxCallParticipants is not valid")
 	}
 
+	if c.MaxGMCallParticipants != nil && *c.MaxGMCallParticipants < 0 {
+		return fmt.Errorf("MaxGMCallParticipants is not valid")
+	}
+
+	if c.MaxConcurrentCalls == nil || *c.MaxConcurrentCalls < 0 {
+		return fmt.Errorf("MaxConcurrentCalls is not valid")
+	}
+
+	if !isValidChannelTypeCallsDefault(c.DMCallsDefault) {
+		return fmt.Errorf("DMCallsDefault is not valid: should be one of %q, %q or %q",
+			channelTypeCallsDefaultInherit, channelTypeCallsDefaultEnabled, channelTypeCallsDefaultDisabled)
+	}
+
+	if !isValidChannelTypeCallsDefault(c.GMCallsDefault) {
+		return fmt.Errorf("GMCallsDefault is not valid: should be one of %q, %q or %q",
+			channelTypeCallsDefaultInherit, channelTypeCallsDefaultEnabled, channelTypeCallsDefaultDisabled)
+	}
+
+	if c.MaxVideoPublishers != nil && *c.MaxVideoPublishers < 0 {
+		return fmt.Errorf("MaxVideoPublishers is not valid")
+	}
+
+	if c.MaxScreenShares == nil || *c.MaxScreenShares < 1 || *c.MaxScreenShares > maxScreenShares {
+		return fmt.Errorf("MaxScreenShares is not valid: range should be [1, %d]", maxScreenShares)
+	}
+
+	if c.MultiDeviceJoinPolicy != multiDeviceJoinPolicyAllow && c.MultiDeviceJoinPolicy != multiDeviceJoinPolicyReplace {
+		return fmt.Errorf("MultiDeviceJoinPolicy is not valid: should be either %q or %q", multiDeviceJoinPolicyAllow, multiDeviceJoinPolicyReplace)
+	}
+
+	switch c.HostAssignmentPolicy {
+	case hostAssignmentPolicyInitiator, hostAssignmentPolicyChannelAdmin, hostAssignmentPolicyNone:
+	default:
+		return fmt.Errorf("HostAssignmentPolicy is not valid: should be one of %q, %q or %q",
+			hostAssignmentPolicyInitiator, hostAssignmentPolicyChannelAdmin, hostAssignmentPolicyNone)
+	}
+
+	if c.ChannelPrivacyChangePolicy != channelPrivacyChangePolicyRemove && c.ChannelPrivacyChangePolicy != channelPrivacyChangePolicyEnd {
+		return fmt.Errorf("ChannelPrivacyChangePolicy is not valid: should be either %q or %q", channelPrivacyChangePolicyRemove, channelPrivacyChangePolicyEnd)
+	}
+
+	if c.RaiseHandAutoLowerTimeoutSeconds != nil && (*c.RaiseHandAutoLowerTimeoutSeconds < 0 || *c.RaiseHandAutoLowerTimeoutSeconds > maxRaiseHandAutoLowerTimeoutSeconds) {
+		return fmt.Errorf("RaiseHandAutoLowerTimeoutSeconds is not valid: range should be [0, %d]", maxRaiseHandAutoLowerTimeoutSeconds)
+	}
+
+	if c.KickedRejoinCooldownSeconds != nil && (*c.KickedRejoinCooldownSeconds < 0 || *c.KickedRejoinCooldownSeconds > maxRejoinCooldownSeconds) {
+		return fmt.Errorf("KickedRejoinCooldownSeconds is not valid: range should be [0, %d]", maxRejoinCooldownSeconds)
+	}
+
+	if c.DroppedRejoinCooldownSeconds != nil && (*c.DroppedRejoinCooldownSeconds < 0 || *c.DroppedRejoinCooldownSeconds > maxRejoinCooldownSeconds) {
+		return fmt.Errorf("DroppedRejoinCooldownSeconds is not valid: range should be [0, %d]", maxRejoinCooldownSeconds)
+	}
+
 	if c.TURNCredentialsExpirationMinutes != nil && *c.TURNCredentialsExpirationMinutes < 0 {
 		return fmt.Errorf("TURNCredentialsExpirationMinutes is not valid")
 	}
 
+	if c.shortLivedTURNCredentialsEnforced() {
+		if c.TURNStaticAuthSecret == "" {
+			return fmt.Errorf("EnforceShortLivedTURNCredentials is not valid: TURNStaticAuthSecret should be set")
+		}
+		if c.TURNCredentialsExpirationMinutes == nil || *c.TURNCredentialsExpirationMinutes < 1 || *c.TURNCredentialsExpirationMinutes >= rtc.MaxTURNCredentialsExpiration {
+			return fmt.Errorf("EnforceShortLivedTURNCredentials is not valid: TURNCredentialsExpirationMinutes should be in range [1, %d)", rtc.MaxTURNCredentialsExpiration)
+		}
+	}
+
+	if err := c.channelICEServersConfigsIsValid(); err != nil {
+		return fmt.Errorf("ChannelICEServersConfigs is not valid: %w", err)
+	}
+
 	if c.MaxRecordingDuration == nil || *c.MaxRecordingDuration < minRecDurationMinutes || *c.MaxRecordingDuration > maxRecDurationMinutes {
 		return fmt.Errorf("MaxRecordingDuration is not valid: range should be [%d, %d]", minRecDurationMinutes, maxRecDurationMinutes)
 	}
@@ -306,6 +1047,47 @@ func (c *configuration) IsValid() error {
 		return fmt.Errorf("RecordingQuality is not valid")
 	}
 
+	if _, ok := recorderResolutions[c.RecordingResolution]; c.RecordingResolution != "" && !ok {
+		return fmt.Errorf("RecordingResolution is not valid")
+	}
+
+	if c.RecordingFrameRate == nil || (*c.RecordingFrameRate != 0 && (*c.RecordingFrameRate < recorder.FrameRateMin || *c.RecordingFrameRate > recorder.FrameRateMax)) {
+		return fmt.Errorf("RecordingFrameRate is not valid: range should be [%d, %d]", recorder.FrameRateMin, recorder.FrameRateMax)
+	}
+
+	if c.RecordingOutputFormat != string(recorder.AVFormatMP4) {
+		return fmt.Errorf("RecordingOutputFormat is not valid")
+	}
+
+	if len(c.RecordingWatermarkTemplate) > maxRecWatermarkTemplateLen {
+		return fmt.Errorf("RecordingWatermarkTemplate is not valid: length should be at most %d", maxRecWatermarkTemplateLen)
+	}
+
+	if !isValidRecordingLayout(c.RecordingLayout) {
+		return fmt.Errorf("RecordingLayout is not valid: should be either %q or %q", recordingLayoutGrid, recordingLayoutActiveSpeaker)
+	}
+
+	if c.MaxRecordingStoragePerChannelMB != nil && (*c.MaxRecordingStoragePerChannelMB < 0 || *c.MaxRecordingStoragePerChannelMB > maxRecStorageQuotaMB) {
+		return fmt.Errorf("MaxRecordingStoragePerChannelMB is not valid: range should be [0, %d]", maxRecStorageQuotaMB)
+	}
+
+	if c.MaxRecordingStoragePerTeamMB != nil && (*c.MaxRecordingStoragePerTeamMB < 0 || *c.MaxRecordingStoragePerTeamMB > maxRecStorageQuotaMB) {
+		return fmt.Errorf("MaxRecordingStoragePerTeamMB is not valid: range should be [0, %d]", maxRecStorageQuotaMB)
+	}
+
+	if c.RecordingStorageQuotaPolicy != recordingStorageQuotaPolicyBlock && c.RecordingStorageQuotaPolicy != recordingStorageQuotaPolicyDeleteOldest {
+		return fmt.Errorf("RecordingStorageQuotaPolicy is not valid: should be either %q or %q", recordingStorageQuotaPolicyBlock, recordingStorageQuotaPolicyDeleteOldest)
+	}
+
+	for _, quality := range c.getRecordingAdditionalQualities() {
+		if _, ok := recorderBaseConfigs[quality]; !ok {
+			return fmt.Errorf("RecordingAdditionalQualities is not valid: %q is not a valid quality", quality)
+		}
+		if quality == c.RecordingQuality {
+			return fmt.Errorf("RecordingAdditionalQualities is not valid: %q is already the RecordingQuality", quality)
+		}
+	}
+
 	if c.transcriptionsEnabled() {
 		if ok := c.TranscriberModelSize.IsValid(); !ok {
 			return fmt.Errorf("TranscriberModelSize is not valid")
@@ -320,6 +1102,179 @@ func (c *configuration) IsValid() error {
 		}
 	}
 
+	if c.MaxConcurrentTranscriptionJobs == nil || *c.MaxConcurrentTranscriptionJobs < 0 || *c.MaxConcurrentTranscriptionJobs > maxConcurrentTranscriptionJobs {
+		return fmt.Errorf("MaxConcurrentTranscriptionJobs is not valid: range should be [0, %d]", maxConcurrentTranscriptionJobs)
+	}
+
+	if c.OutboundProxyURL != "" {
+		if u, err := url.Parse(c.OutboundProxyURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("OutboundProxyURL is not valid: should be an absolute http(s) URL")
+		}
+	}
+
+	if c.RecordingWebhookURL != "" {
+		if u, err := url.Parse(c.RecordingWebhookURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("RecordingWebhookURL is not valid: should be an absolute http(s) URL")
+		}
+	}
+
+	if c.ParticipantWebhookURL != "" {
+		if u, err := url.Parse(c.ParticipantWebhookURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("ParticipantWebhookURL is not valid: should be an absolute http(s) URL")
+		}
+		if c.ParticipantWebhookSecret == "" {
+			return fmt.Errorf("ParticipantWebhookSecret should not be empty when ParticipantWebhookURL is set")
+		}
+	}
+
+	if c.RecordingWebhookTimeoutSeconds == nil || *c.RecordingWebhookTimeoutSeconds <= 0 || *c.RecordingWebhookTimeoutSeconds > maxRecWebhookTimeoutSeconds {
+		return fmt.Errorf("RecordingWebhookTimeoutSeconds is not valid: range should be [1, %d]", maxRecWebhookTimeoutSeconds)
+	}
+
+	if c.RecordingWebhookMaxRetries == nil || *c.RecordingWebhookMaxRetries < 0 || *c.RecordingWebhookMaxRetries > maxRecWebhookMaxRetries {
+		return fmt.Errorf("RecordingWebhookMaxRetries is not valid: range should be [0, %d]", maxRecWebhookMaxRetries)
+	}
+
+	if c.RecordingUploadMaxRetries == nil || *c.RecordingUploadMaxRetries < 0 || *c.RecordingUploadMaxRetries > maxRecUploadMaxRetries {
+		return fmt.Errorf("RecordingUploadMaxRetries is not valid: range should be [0, %d]", maxRecUploadMaxRetries)
+	}
+
+	if c.RecordingUploadSpoolDirectory != "" && !filepath.IsAbs(c.RecordingUploadSpoolDirectory) {
+		return fmt.Errorf("RecordingUploadSpoolDirectory is not valid: should be an absolute path")
+	}
+
+	for _, channelID := range c.getRecordingKeywordTriggerChannels() {
+		if !model.IsValidId(channelID) {
+			return fmt.Errorf("RecordingKeywordTriggerChannels is not valid: %q is not a valid channel ID", channelID)
+		}
+	}
+
+	if c.RecordingEmptyCallGracePeriodSeconds == nil || *c.RecordingEmptyCallGracePeriodSeconds < 0 || *c.RecordingEmptyCallGracePeriodSeconds > maxRecEmptyCallGracePeriodSeconds {
+		return fmt.Errorf("RecordingEmptyCallGracePeriodSeconds is not valid: range should be [0, %d]", maxRecEmptyCallGracePeriodSeconds)
+	}
+
+	if c.CallEndWarningSeconds != nil && (*c.CallEndWarningSeconds < 0 || *c.CallEndWarningSeconds > maxCallEndWarningSeconds) {
+		return fmt.Errorf("CallEndWarningSeconds is not valid: range should be [0, %d]", maxCallEndWarningSeconds)
+	}
+
+	if c.CallFeedbackSampleRate == nil || *c.CallFeedbackSampleRate < 1 || *c.CallFeedbackSampleRate > 100 {
+		return fmt.Errorf("CallFeedbackSampleRate is not valid: range should be [1, 100]")
+	}
+
+	if c.ICEConnectionTimeoutSeconds != nil && (*c.ICEConnectionTimeoutSeconds < 0 || *c.ICEConnectionTimeoutSeconds > maxICEConnectionTimeoutSeconds) {
+		return fmt.Errorf("ICEConnectionTimeoutSeconds is not valid: range should be [0, %d]", maxICEConnectionTimeoutSeconds)
+	}
+
+	if c.MaxICECandidatesPerSession != nil && (*c.MaxICECandidatesPerSession < 0 || *c.MaxICECandidatesPerSession > maxMaxICECandidatesPerSession) {
+		return fmt.Errorf("MaxICECandidatesPerSession is not valid: range should be [0, %d]", maxMaxICECandidatesPerSession)
+	}
+
+	for _, typ := range c.getICECandidatesPriority() {
+		if !slices.Contains(iceCandidateTypes, typ) {
+			return fmt.Errorf("ICECandidatesPriority is not valid: %q is not a valid candidate type", typ)
+		}
+	}
+
+	if c.MaxSDPSizeKB == nil || *c.MaxSDPSizeKB < minMaxSDPSizeKB || *c.MaxSDPSizeKB > maxMaxSDPSizeKB {
+		return fmt.Errorf("MaxSDPSizeKB is not valid: range should be [%d, %d]", minMaxSDPSizeKB, maxMaxSDPSizeKB)
+	}
+
+	if c.MediaInactivityTimeoutSeconds != nil && *c.MediaInactivityTimeoutSeconds != 0 &&
+		(*c.MediaInactivityTimeoutSeconds < minMediaInactivityTimeoutSeconds || *c.MediaInactivityTimeoutSeconds > maxMediaInactivityTimeoutSeconds) {
+		return fmt.Errorf("MediaInactivityTimeoutSeconds is not valid: should be 0 or in range [%d, %d]", minMediaInactivityTimeoutSeconds, maxMediaInactivityTimeoutSeconds)
+	}
+
+	if c.ICERestartTimeoutSeconds != nil && *c.ICERestartTimeoutSeconds != 0 {
+		if *c.ICERestartTimeoutSeconds < minICERestartTimeoutSeconds || *c.ICERestartTimeoutSeconds > maxICERestartTimeoutSeconds {
+			return fmt.Errorf("ICERestartTimeoutSeconds is not valid: should be 0 or in range [%d, %d]", minICERestartTimeoutSeconds, maxICERestartTimeoutSeconds)
+		}
+		// Restarting only makes sense before the session gets disconnected.
+		if timeout := c.getMediaInactivityTimeout(); timeout > 0 && c.getICERestartTimeout() >= timeout {
+			return fmt.Errorf("ICERestartTimeoutSeconds is not valid: should be lower than MediaInactivityTimeoutSeconds")
+		}
+	}
+
+	if c.MetricsPushURL != "" {
+		if u, err := url.Parse(c.MetricsPushURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("MetricsPushURL is not valid: should be an absolute http(s) URL")
+		}
+	}
+
+	if c.RTCDSendMaxRetries != nil && (*c.RTCDSendMaxRetries < 0 || *c.RTCDSendMaxRetries > maxRTCDSendMaxRetries) {
+		return fmt.Errorf("RTCDSendMaxRetries is not valid: range should be [0, %d]", maxRTCDSendMaxRetries)
+	}
+
+	if c.RTCDSendTimeoutMs != nil && (*c.RTCDSendTimeoutMs < minRTCDSendTimeoutMs || *c.RTCDSendTimeoutMs > maxRTCDSendTimeoutMs) {
+		return fmt.Errorf("RTCDSendTimeoutMs is not valid: range should be [%d, %d]", minRTCDSendTimeoutMs, maxRTCDSendTimeoutMs)
+	}
+
+	if c.RTCDKeepaliveIntervalSeconds != nil && (*c.RTCDKeepaliveIntervalSeconds < 0 || *c.RTCDKeepaliveIntervalSeconds > maxRTCDKeepaliveIntervalSeconds) {
+		return fmt.Errorf("RTCDKeepaliveIntervalSeconds is not valid: range should be [0, %d]", maxRTCDKeepaliveIntervalSeconds)
+	}
+
+	if c.MetricsPushIntervalSeconds != nil && (*c.MetricsPushIntervalSeconds < minMetricsPushIntervalSeconds || *c.MetricsPushIntervalSeconds > maxMetricsPushIntervalSeconds) {
+		return fmt.Errorf("MetricsPushIntervalSeconds is not valid: range should be [%d, %d]", minMetricsPushIntervalSeconds, maxMetricsPushIntervalSeconds)
+	}
+
+	if c.DBConnectRetries != nil && (*c.DBConnectRetries < 0 || *c.DBConnectRetries > maxDBConnectRetries) {
+		return fmt.Errorf("DBConnectRetries is not valid: range should be [0, %d]", maxDBConnectRetries)
+	}
+
+	if c.CleanUpStateWorkers != nil && (*c.CleanUpStateWorkers < 1 || *c.CleanUpStateWorkers > maxCleanUpStateWorkers) {
+		return fmt.Errorf("CleanUpStateWorkers is not valid: range should be [1, %d]", maxCleanUpStateWorkers)
+	}
+
+	if c.DBConnectRetryDelaySeconds != nil && (*c.DBConnectRetryDelaySeconds < 1 || *c.DBConnectRetryDelaySeconds > maxDBConnectRetryDelaySeconds) {
+		return fmt.Errorf("DBConnectRetryDelaySeconds is not valid: range should be [1, %d]", maxDBConnectRetryDelaySeconds)
+	}
+
+	if c.SessionMessageRateLimit != nil && (*c.SessionMessageRateLimit < 0 || *c.SessionMessageRateLimit > maxSessionMessageRateLimit) {
+		return fmt.Errorf("SessionMessageRateLimit is not valid: range should be [0, %d]", maxSessionMessageRateLimit)
+	}
+
+	if c.SessionMessageBurst != nil && (*c.SessionMessageBurst < 1 || *c.SessionMessageBurst > maxSessionMessageBurst) {
+		return fmt.Errorf("SessionMessageBurst is not valid: range should be [1, %d]", maxSessionMessageBurst)
+	}
+
+	if c.NoiseAutoMuteThresholdSeconds == nil || *c.NoiseAutoMuteThresholdSeconds < public.MinNoiseAutoMuteThresholdSeconds || *c.NoiseAutoMuteThresholdSeconds > public.MaxNoiseAutoMuteThresholdSeconds {
+		return fmt.Errorf("NoiseAutoMuteThresholdSeconds is not valid: range should be [%d, %d]", public.MinNoiseAutoMuteThresholdSeconds, public.MaxNoiseAutoMuteThresholdSeconds)
+	}
+
+	enabledTeams := parseIDs(c.EnabledTeams)
+	for _, teamID := range enabledTeams {
+		if !model.IsValidId(teamID) {
+			return fmt.Errorf("EnabledTeams is not valid: %q is not a valid team ID", teamID)
+		}
+	}
+	for _, teamID := range parseIDs(c.DisabledTeams) {
+		if !model.IsValidId(teamID) {
+			return fmt.Errorf("DisabledTeams is not valid: %q is not a valid team ID", teamID)
+		}
+		if slices.Contains(enabledTeams, teamID) {
+			return fmt.Errorf("DisabledTeams is not valid: team %q is also in EnabledTeams", teamID)
+		}
+	}
+
+	allowedCallTags := c.getAllowedCallTags()
+	if len(allowedCallTags) > maxCallTags {
+		return fmt.Errorf("AllowedCallTags is not valid: should not contain more than %d tags", maxCallTags)
+	}
+	for _, tag := range allowedCallTags {
+		if !isValidCallTag(tag) {
+			return fmt.Errorf("AllowedCallTags is not valid: %q should only contain letters, numbers, dashes and underscores and be at most %d characters long", tag, maxCallTagLen)
+		}
+	}
+
+	for _, feature := range c.getDefaultDisabledCallFeatures() {
+		if !slices.Contains(public.CallFeatures, feature) {
+			return fmt.Errorf("DefaultDisabledCallFeatures is not valid: %q is not a valid feature", feature)
+		}
+	}
+
+	if err := c.callPresetsIsValid(); err != nil {
+		return fmt.Errorf("CallPresets is not valid: %w", err)
+	}
+
 	if c.ICEHostPortOverride != nil && *c.ICEHostPortOverride != 0 && (*c.ICEHostPortOverride < minAllowedPort || *c.ICEHostPortOverride > maxAllowedPort) {
 		return fmt.Errorf("ICEHostPortOverride is not valid: %d is not in allowed range [%d, %d]", *c.ICEHostPortOverride, minAllowedPort, maxAllowedPort)
 	}
@@ -350,17 +1305,46 @@ func (c *configuration) Clone() *configuration {
 
 	cfg.UDPServerAddress = c.UDPServerAddress
 	cfg.TCPServerAddress = c.TCPServerAddress
+	cfg.ICEInterface = c.ICEInterface
+	cfg.AllowedICECandidateTypes = c.AllowedICECandidateTypes
+	cfg.ICECandidatesPriority = c.ICECandidatesPriority
 	cfg.ICEHostOverride = c.ICEHostOverride
 	cfg.RTCDServiceURL = c.RTCDServiceURL
 	cfg.JobServiceURL = c.JobServiceURL
+	cfg.OutboundProxyURL = c.OutboundProxyURL
+	cfg.MetricsPushURL = c.MetricsPushURL
 	cfg.TURNStaticAuthSecret = c.TURNStaticAuthSecret
+	cfg.ChannelICEServersConfigs = c.ChannelICEServersConfigs
 	cfg.RecordingQuality = c.RecordingQuality
+	cfg.RecordingResolution = c.RecordingResolution
+	cfg.RecordingOutputFormat = c.RecordingOutputFormat
+	cfg.RecordingAdditionalQualities = c.RecordingAdditionalQualities
+	cfg.RecordingWatermarkTemplate = c.RecordingWatermarkTemplate
+	cfg.RecordingLayout = c.RecordingLayout
+	cfg.RecordingStorageQuotaPolicy = c.RecordingStorageQuotaPolicy
+	cfg.RecordingWebhookURL = c.RecordingWebhookURL
+	cfg.RecordingWebhookAuthToken = c.RecordingWebhookAuthToken
+	cfg.RecordingUploadSpoolDirectory = c.RecordingUploadSpoolDirectory
+	cfg.RecordingKeywordTriggers = c.RecordingKeywordTriggers
+	cfg.RecordingKeywordTriggerChannels = c.RecordingKeywordTriggerChannels
+	cfg.ParticipantWebhookURL = c.ParticipantWebhookURL
+	cfg.ParticipantWebhookSecret = c.ParticipantWebhookSecret
 	cfg.TranscriberModelSize = c.TranscriberModelSize
 	cfg.TranscribeAPI = c.TranscribeAPI
 	cfg.TranscribeAPIAzureSpeechKey = c.TranscribeAPIAzureSpeechKey
 	cfg.TranscribeAPIAzureSpeechRegion = c.TranscribeAPIAzureSpeechRegion
 	cfg.LiveCaptionsModelSize = c.LiveCaptionsModelSize
 	cfg.LiveCaptionsLanguage = c.LiveCaptionsLanguage
+	cfg.MultiDeviceJoinPolicy = c.MultiDeviceJoinPolicy
+	cfg.HostAssignmentPolicy = c.HostAssignmentPolicy
+	cfg.ChannelPrivacyChangePolicy = c.ChannelPrivacyChangePolicy
+	cfg.AllowedCallTags = c.AllowedCallTags
+	cfg.DefaultDisabledCallFeatures = c.DefaultDisabledCallFeatures
+	cfg.CallPresets = c.CallPresets
+	cfg.EnabledTeams = c.EnabledTeams
+	cfg.DisabledTeams = c.DisabledTeams
+	cfg.DMCallsDefault = c.DMCallsDefault
+	cfg.GMCallsDefault = c.GMCallsDefault
 
 	if c.UDPServerPort != nil {
 		cfg.UDPServerPort = model.NewPointer(*c.UDPServerPort)
@@ -391,6 +1375,10 @@ func (c *configuration) Clone() *configuration {
 		cfg.MaxCallParticipants = model.NewPointer(*c.MaxCallParticipants)
 	}
 
+	if c.MaxConcurrentCalls != nil {
+		cfg.MaxConcurrentCalls = model.NewPointer(*c.MaxConcurrentCalls)
+	}
+
 	if c.TURNCredentialsExpirationMinutes != nil {
 		cfg.TURNCredentialsExpirationMinutes = model.NewPointer(*c.TURNCredentialsExpirationMinutes)
 	}
@@ -399,6 +1387,10 @@ func (c *configuration) Clone() *configuration {
 		cfg.ServerSideTURN = model.NewPointer(*c.ServerSideTURN)
 	}
 
+	if c.EnforceShortLivedTURNCredentials != nil {
+		cfg.EnforceShortLivedTURNCredentials = model.NewPointer(*c.EnforceShortLivedTURNCredentials)
+	}
+
 	if c.AllowScreenSharing != nil {
 		cfg.AllowScreenSharing = model.NewPointer(*c.AllowScreenSharing)
 	}
@@ -415,7 +1407,11 @@ func (c *configuration) Clone() *configuration {
 		cfg.TranscriberNumThreads = model.NewPointer(*c.TranscriberNumThreads)
 	}
 
-	if c.EnableLiveCaptions != nil {
+	if c.MaxConcurrentTranscriptionJobs != nil {
+		cfg.MaxConcurrentTranscriptionJobs = model.NewPointer(*c.MaxConcurrentTranscriptionJobs)
+	}
+
+	if c.EnableLiveCaptions != nil {
 		cfg.EnableLiveCaptions = model.NewPointer(*c.EnableLiveCaptions)
 	}
 
@@ -431,10 +1427,42 @@ func (c *configuration) Clone() *configuration {
 		cfg.EnableIPv6 = model.NewPointer(*c.EnableIPv6)
 	}
 
+	if c.EnableCPUQualityDowngrade != nil {
+		cfg.EnableCPUQualityDowngrade = model.NewPointer(*c.EnableCPUQualityDowngrade)
+	}
+
+	if c.CPUQualityDowngradeThreshold != nil {
+		cfg.CPUQualityDowngradeThreshold = model.NewPointer(*c.CPUQualityDowngradeThreshold)
+	}
+
+	if c.CPUQualityRestoreThreshold != nil {
+		cfg.CPUQualityRestoreThreshold = model.NewPointer(*c.CPUQualityRestoreThreshold)
+	}
+
+	if c.EnablePoorConnectionAlerts != nil {
+		cfg.EnablePoorConnectionAlerts = model.NewPointer(*c.EnablePoorConnectionAlerts)
+	}
+
+	if c.PoorConnectionRTTThresholdMs != nil {
+		cfg.PoorConnectionRTTThresholdMs = model.NewPointer(*c.PoorConnectionRTTThresholdMs)
+	}
+
+	if c.PoorConnectionLossThreshold != nil {
+		cfg.PoorConnectionLossThreshold = model.NewPointer(*c.PoorConnectionLossThreshold)
+	}
+
+	if c.PoorConnectionAlertDelaySeconds != nil {
+		cfg.PoorConnectionAlertDelaySeconds = model.NewPointer(*c.PoorConnectionAlertDelaySeconds)
+	}
+
 	if c.EnableRinging != nil {
 		cfg.EnableRinging = model.NewPointer(*c.EnableRinging)
 	}
 
+	if c.EnableCallPushNotifications != nil {
+		cfg.EnableCallPushNotifications = model.NewPointer(*c.EnableCallPushNotifications)
+	}
+
 	if c.ICEHostPortOverride != nil {
 		cfg.ICEHostPortOverride = model.NewPointer(*c.ICEHostPortOverride)
 	}
@@ -455,6 +1483,206 @@ func (c *configuration) Clone() *configuration {
 		cfg.EnableDCSignaling = model.NewPointer(*c.EnableDCSignaling)
 	}
 
+	if c.RTCDFallbackToEmbedded != nil {
+		cfg.RTCDFallbackToEmbedded = model.NewPointer(*c.RTCDFallbackToEmbedded)
+	}
+
+	if c.RTCDSendMaxRetries != nil {
+		cfg.RTCDSendMaxRetries = model.NewPointer(*c.RTCDSendMaxRetries)
+	}
+
+	if c.RTCDSendTimeoutMs != nil {
+		cfg.RTCDSendTimeoutMs = model.NewPointer(*c.RTCDSendTimeoutMs)
+	}
+
+	if c.RTCDKeepaliveIntervalSeconds != nil {
+		cfg.RTCDKeepaliveIntervalSeconds = model.NewPointer(*c.RTCDKeepaliveIntervalSeconds)
+	}
+
+	if c.AllowCallsInReadOnlyChannels != nil {
+		cfg.AllowCallsInReadOnlyChannels = model.NewPointer(*c.AllowCallsInReadOnlyChannels)
+	}
+
+	if c.EnableJoinWebhook != nil {
+		cfg.EnableJoinWebhook = model.NewPointer(*c.EnableJoinWebhook)
+	}
+
+	if c.EnableLeaveWebhook != nil {
+		cfg.EnableLeaveWebhook = model.NewPointer(*c.EnableLeaveWebhook)
+	}
+
+	if c.JoinMuted != nil {
+		cfg.JoinMuted = model.NewPointer(*c.JoinMuted)
+	}
+
+	if c.NoiseSuppression != nil {
+		cfg.NoiseSuppression = model.NewPointer(*c.NoiseSuppression)
+	}
+
+	if c.ConfirmCallStart != nil {
+		cfg.ConfirmCallStart = model.NewPointer(*c.ConfirmCallStart)
+	}
+
+	if c.WaitingRoom != nil {
+		cfg.WaitingRoom = model.NewPointer(*c.WaitingRoom)
+	}
+
+	if c.WaitingRoomPushNotifications != nil {
+		cfg.WaitingRoomPushNotifications = model.NewPointer(*c.WaitingRoomPushNotifications)
+	}
+
+	if c.DisableVideo != nil {
+		cfg.DisableVideo = model.NewPointer(*c.DisableVideo)
+	}
+
+	if c.MaxVideoPublishers != nil {
+		cfg.MaxVideoPublishers = model.NewPointer(*c.MaxVideoPublishers)
+	}
+
+	if c.MaxScreenShares != nil {
+		cfg.MaxScreenShares = model.NewPointer(*c.MaxScreenShares)
+	}
+
+	if c.EnableCallChat != nil {
+		cfg.EnableCallChat = model.NewPointer(*c.EnableCallChat)
+	}
+
+	if c.EnableCallTimeline != nil {
+		cfg.EnableCallTimeline = model.NewPointer(*c.EnableCallTimeline)
+	}
+
+	if c.EnableCallFeedback != nil {
+		cfg.EnableCallFeedback = model.NewPointer(*c.EnableCallFeedback)
+	}
+
+	if c.CallFeedbackSampleRate != nil {
+		cfg.CallFeedbackSampleRate = model.NewPointer(*c.CallFeedbackSampleRate)
+	}
+
+	if c.CallChatPostToThread != nil {
+		cfg.CallChatPostToThread = model.NewPointer(*c.CallChatPostToThread)
+	}
+
+	if c.MaxGMCallParticipants != nil {
+		cfg.MaxGMCallParticipants = model.NewPointer(*c.MaxGMCallParticipants)
+	}
+
+	if c.EnableDMRinging != nil {
+		cfg.EnableDMRinging = model.NewPointer(*c.EnableDMRinging)
+	}
+
+	if c.EnableGMRinging != nil {
+		cfg.EnableGMRinging = model.NewPointer(*c.EnableGMRinging)
+	}
+
+	if c.ICEConnectionTimeoutSeconds != nil {
+		cfg.ICEConnectionTimeoutSeconds = model.NewPointer(*c.ICEConnectionTimeoutSeconds)
+	}
+
+	if c.MaxICECandidatesPerSession != nil {
+		cfg.MaxICECandidatesPerSession = model.NewPointer(*c.MaxICECandidatesPerSession)
+	}
+
+	if c.MaxSDPSizeKB != nil {
+		cfg.MaxSDPSizeKB = model.NewPointer(*c.MaxSDPSizeKB)
+	}
+
+	if c.MediaInactivityTimeoutSeconds != nil {
+		cfg.MediaInactivityTimeoutSeconds = model.NewPointer(*c.MediaInactivityTimeoutSeconds)
+	}
+
+	if c.ICERestartTimeoutSeconds != nil {
+		cfg.ICERestartTimeoutSeconds = model.NewPointer(*c.ICERestartTimeoutSeconds)
+	}
+
+	if c.MetricsPushIntervalSeconds != nil {
+		cfg.MetricsPushIntervalSeconds = model.NewPointer(*c.MetricsPushIntervalSeconds)
+	}
+
+	if c.RaiseHandAutoLowerTimeoutSeconds != nil {
+		cfg.RaiseHandAutoLowerTimeoutSeconds = model.NewPointer(*c.RaiseHandAutoLowerTimeoutSeconds)
+	}
+
+	if c.KickedRejoinCooldownSeconds != nil {
+		cfg.KickedRejoinCooldownSeconds = model.NewPointer(*c.KickedRejoinCooldownSeconds)
+	}
+
+	if c.DroppedRejoinCooldownSeconds != nil {
+		cfg.DroppedRejoinCooldownSeconds = model.NewPointer(*c.DroppedRejoinCooldownSeconds)
+	}
+
+	if c.DBConnectRetries != nil {
+		cfg.DBConnectRetries = model.NewPointer(*c.DBConnectRetries)
+	}
+
+	if c.CleanUpStateWorkers != nil {
+		cfg.CleanUpStateWorkers = model.NewPointer(*c.CleanUpStateWorkers)
+	}
+
+	if c.DBConnectRetryDelaySeconds != nil {
+		cfg.DBConnectRetryDelaySeconds = model.NewPointer(*c.DBConnectRetryDelaySeconds)
+	}
+
+	if c.EnableDBWriteBuffer != nil {
+		cfg.EnableDBWriteBuffer = model.NewPointer(*c.EnableDBWriteBuffer)
+	}
+
+	if c.SessionMessageRateLimit != nil {
+		cfg.SessionMessageRateLimit = model.NewPointer(*c.SessionMessageRateLimit)
+	}
+
+	if c.SessionMessageBurst != nil {
+		cfg.SessionMessageBurst = model.NewPointer(*c.SessionMessageBurst)
+	}
+
+	if c.EnableNoiseAutoMute != nil {
+		cfg.EnableNoiseAutoMute = model.NewPointer(*c.EnableNoiseAutoMute)
+	}
+
+	if c.NoiseAutoMuteThresholdSeconds != nil {
+		cfg.NoiseAutoMuteThresholdSeconds = model.NewPointer(*c.NoiseAutoMuteThresholdSeconds)
+	}
+
+	if c.RecordingFrameRate != nil {
+		cfg.RecordingFrameRate = model.NewPointer(*c.RecordingFrameRate)
+	}
+
+	if c.RecordingEmptyCallGracePeriodSeconds != nil {
+		cfg.RecordingEmptyCallGracePeriodSeconds = model.NewPointer(*c.RecordingEmptyCallGracePeriodSeconds)
+	}
+
+	if c.CallEndWarningSeconds != nil {
+		cfg.CallEndWarningSeconds = model.NewPointer(*c.CallEndWarningSeconds)
+	}
+
+	if c.RecordingWebhookTimeoutSeconds != nil {
+		cfg.RecordingWebhookTimeoutSeconds = model.NewPointer(*c.RecordingWebhookTimeoutSeconds)
+	}
+
+	if c.RecordingWebhookMaxRetries != nil {
+		cfg.RecordingWebhookMaxRetries = model.NewPointer(*c.RecordingWebhookMaxRetries)
+	}
+
+	if c.RecordingUploadMaxRetries != nil {
+		cfg.RecordingUploadMaxRetries = model.NewPointer(*c.RecordingUploadMaxRetries)
+	}
+
+	if c.NotifyParticipantsOfRecordings != nil {
+		cfg.NotifyParticipantsOfRecordings = model.NewPointer(*c.NotifyParticipantsOfRecordings)
+	}
+
+	if c.MaxRecordingStoragePerChannelMB != nil {
+		cfg.MaxRecordingStoragePerChannelMB = model.NewPointer(*c.MaxRecordingStoragePerChannelMB)
+	}
+
+	if c.MaxRecordingStoragePerTeamMB != nil {
+		cfg.MaxRecordingStoragePerTeamMB = model.NewPointer(*c.MaxRecordingStoragePerTeamMB)
+	}
+
+	if c.AnonymizeRecordings != nil {
+		cfg.AnonymizeRecordings = model.NewPointer(*c.AnonymizeRecordings)
+	}
+
 	return &cfg
 }
 
@@ -470,6 +1698,31 @@ func (c *configuration) getRTCDURL() string {
 	return c.RTCDServiceURL
 }
 
+func (c *configuration) rtcdFallbackEnabled() bool {
+	return c.RTCDFallbackToEmbedded != nil && *c.RTCDFallbackToEmbedded
+}
+
+func (c *configuration) getRTCDSendMaxRetries() int {
+	if c.RTCDSendMaxRetries == nil || *c.RTCDSendMaxRetries < 0 {
+		return defaultRTCDSendMaxRetries
+	}
+	return *c.RTCDSendMaxRetries
+}
+
+func (c *configuration) getRTCDSendTimeout() time.Duration {
+	if c.RTCDSendTimeoutMs == nil || *c.RTCDSendTimeoutMs <= 0 {
+		return defaultRTCDSendTimeoutMs * time.Millisecond
+	}
+	return time.Duration(*c.RTCDSendTimeoutMs) * time.Millisecond
+}
+
+func (c *configuration) getRTCDKeepaliveInterval() time.Duration {
+	if c.RTCDKeepaliveIntervalSeconds == nil || *c.RTCDKeepaliveIntervalSeconds < 0 {
+		return defaultRTCDKeepaliveIntervalSeconds * time.Second
+	}
+	return time.Duration(*c.RTCDKeepaliveIntervalSeconds) * time.Second
+}
+
 func (c *configuration) getJobServiceURL() string {
 	if url := os.Getenv("MM_CALLS_JOB_SERVICE_URL"); url != "" {
 		return url
@@ -491,6 +1744,465 @@ func (c *configuration) transcriptionsEnabled() bool {
 	return false
 }
 
+func (c *configuration) recordingWebhookEnabled() bool {
+	return c.recordingsEnabled() && c.RecordingWebhookURL != ""
+}
+
+func (c *configuration) recordingUploadSpoolDirectory() string {
+	if c.RecordingUploadSpoolDirectory != "" {
+		return c.RecordingUploadSpoolDirectory
+	}
+	return filepath.Join(os.TempDir(), "calls-recordings-spool")
+}
+
+func (c *configuration) participantWebhookEnabled(evType public.ParticipantEventType) bool {
+	if c.ParticipantWebhookURL == "" {
+		return false
+	}
+
+	switch evType {
+	case public.ParticipantEventTypeJoin:
+		return c.EnableJoinWebhook != nil && *c.EnableJoinWebhook
+	case public.ParticipantEventTypeLeave:
+		return c.EnableLeaveWebhook != nil && *c.EnableLeaveWebhook
+	default:
+		return false
+	}
+}
+
+// getRecordingAdditionalQualities returns the deduplicated list of quality
+// profiles calls are recorded with in addition to RecordingQuality.
+func (c *configuration) getRecordingAdditionalQualities() []string {
+	var qualities []string
+	for _, quality := range strings.Split(c.RecordingAdditionalQualities, ",") {
+		if quality = strings.ToLower(strings.TrimSpace(quality)); quality != "" && !slices.Contains(qualities, quality) {
+			qualities = append(qualities, quality)
+		}
+	}
+	return qualities
+}
+
+// getDefaultDisabledCallFeatures returns the deduplicated list of features
+// disabled by default in calls.
+func (c *configuration) getDefaultDisabledCallFeatures() []string {
+	var features []string
+	for _, feature := range strings.Split(c.DefaultDisabledCallFeatures, ",") {
+		if feature = strings.ToLower(strings.TrimSpace(feature)); feature != "" && !slices.Contains(features, feature) {
+			features = append(features, feature)
+		}
+	}
+	return features
+}
+
+// getAllowedCallTags returns the normalized list of tags calls can be
+// categorized with.
+func (c *configuration) getAllowedCallTags() []string {
+	var tags []string
+	for _, tag := range strings.Split(c.AllowedCallTags, ",") {
+		if tag = normalizeCallTag(tag); tag != "" && !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// getRecordingKeywordTriggers returns the normalized (lower cased) list of
+// keywords starting a recording.
+func (c *configuration) getRecordingKeywordTriggers() []string {
+	var keywords []string
+	for _, keyword := range strings.Split(c.RecordingKeywordTriggers, ",") {
+		if keyword = strings.ToLower(strings.TrimSpace(keyword)); keyword != "" && !slices.Contains(keywords, keyword) {
+			keywords = append(keywords, keyword)
+		}
+	}
+	return keywords
+}
+
+// getRecordingKeywordTriggerChannels returns the IDs of the channels keyword
+// triggers apply to.
+func (c *configuration) getRecordingKeywordTriggerChannels() []string {
+	return parseIDs(c.RecordingKeywordTriggerChannels)
+}
+
+// parseIDs returns the IDs found in the given comma separated list.
+func parseIDs(s string) []string {
+	var ids []string
+	for _, id := range strings.Split(s, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// getTeamCallsEnabled returns whether calls are explicitly enabled or disabled
+// by default in the given team, or nil if the team has no setting.
+func (c *configuration) getTeamCallsEnabled(teamID string) *bool {
+	if teamID == "" {
+		return nil
+	}
+	if slices.Contains(parseIDs(c.EnabledTeams), teamID) {
+		return model.NewPointer(true)
+	}
+	if slices.Contains(parseIDs(c.DisabledTeams), teamID) {
+		return model.NewPointer(false)
+	}
+	return nil
+}
+
+const (
+	callsEnabledSourceChannel = "channel"
+	callsEnabledSourceTeam    = "team"
+	callsEnabledSourceDefault = "default"
+)
+
+// resolveCallsEnabled returns whether calls are explicitly enabled or disabled
+// in a channel along with where the setting comes from. The channel setting
+// takes precedence over the team one. A nil value means neither is set, in
+// which case the global default applies (see userCanStartOrJoin).
+func (c *configuration) resolveCallsEnabled(callsChannel *public.CallsChannel, teamID string) (*bool, string) {
+	if callsChannel != nil {
+		return model.NewPointer(callsChannel.Enabled), callsEnabledSourceChannel
+	}
+	if enabled := c.getTeamCallsEnabled(teamID); enabled != nil {
+		return enabled, callsEnabledSourceTeam
+	}
+	return nil, callsEnabledSourceDefault
+}
+
+// defaultEnabledForTeam returns whether calls are enabled in channels of the
+// given team that don't have an explicit setting. The team setting, if any,
+// takes precedence over the global DefaultEnabled one.
+func (c *configuration) defaultEnabledForTeam(teamID string) bool {
+	if enabled := c.getTeamCallsEnabled(teamID); enabled != nil {
+		return *enabled
+	}
+	return c.DefaultEnabled != nil && *c.DefaultEnabled
+}
+
+func isValidChannelTypeCallsDefault(policy string) bool {
+	switch policy {
+	case channelTypeCallsDefaultInherit, channelTypeCallsDefaultEnabled, channelTypeCallsDefaultDisabled:
+		return true
+	}
+	return false
+}
+
+// getChannelTypeCallsEnabled returns whether calls are explicitly enabled or
+// disabled by default in DM/GM channels of the given type, or nil if the
+// global default applies.
+func (c *configuration) getChannelTypeCallsEnabled(channelType model.ChannelType) *bool {
+	var policy string
+	switch channelType {
+	case model.ChannelTypeDirect:
+		policy = c.DMCallsDefault
+	case model.ChannelTypeGroup:
+		policy = c.GMCallsDefault
+	}
+
+	switch policy {
+	case channelTypeCallsDefaultEnabled:
+		return model.NewPointer(true)
+	case channelTypeCallsDefaultDisabled:
+		return model.NewPointer(false)
+	}
+	return nil
+}
+
+// defaultEnabledForChannel returns whether calls are enabled in a channel of
+// the given team and type that doesn't have an explicit setting. DM/GM
+// channels don't belong to a team so their own default, if any, applies
+// instead.
+func (c *configuration) defaultEnabledForChannel(teamID string, channelType model.ChannelType) bool {
+	if enabled := c.getChannelTypeCallsEnabled(channelType); enabled != nil {
+		return *enabled
+	}
+	return c.defaultEnabledForTeam(teamID)
+}
+
+// ringingEnabledForChannelType returns whether starting a call in a channel
+// of the given type should ring its members. Only DM/GM calls ring.
+func (c *configuration) ringingEnabledForChannelType(channelType model.ChannelType) bool {
+	if c.EnableRinging == nil || !*c.EnableRinging {
+		return false
+	}
+	return c.ringOnStart(channelType)
+}
+
+// ringOnStart returns whether calls started in a channel of the given type
+// should notify members as incoming calls rather than only posting a message.
+func (c *configuration) ringOnStart(channelType model.ChannelType) bool {
+	switch channelType {
+	case model.ChannelTypeDirect:
+		return c.EnableDMRinging == nil || *c.EnableDMRinging
+	case model.ChannelTypeGroup:
+		return c.EnableGMRinging == nil || *c.EnableGMRinging
+	}
+	return false
+}
+
+// maxParticipantsForChannelType returns the participant limit for calls
+// started in a channel of the given type, zero meaning unlimited.
+func (c *configuration) maxParticipantsForChannelType(channelType model.ChannelType) int {
+	limit := c.getCallLimits().MaxParticipants
+	if channelType == model.ChannelTypeGroup && c.MaxGMCallParticipants != nil && *c.MaxGMCallParticipants > 0 {
+		if limit == 0 || *c.MaxGMCallParticipants < limit {
+			limit = *c.MaxGMCallParticipants
+		}
+	}
+	return limit
+}
+
+// replaceSessionsOnJoin returns whether joining a call from another device
+// should end the user's existing sessions.
+func (c *configuration) replaceSessionsOnJoin() bool {
+	return c.MultiDeviceJoinPolicy == multiDeviceJoinPolicyReplace
+}
+
+func (c *configuration) callsInReadOnlyChannelsAllowed() bool {
+	return c.AllowCallsInReadOnlyChannels != nil && *c.AllowCallsInReadOnlyChannels
+}
+
+func (c *configuration) noiseSuppressionRecommended() bool {
+	return c.NoiseSuppression != nil && *c.NoiseSuppression
+}
+
+func (c *configuration) videoDisabled() bool {
+	return c.DisableVideo != nil && *c.DisableVideo
+}
+
+func (c *configuration) getMaxVideoPublishers() int {
+	if c.MaxVideoPublishers == nil {
+		return 0
+	}
+	return *c.MaxVideoPublishers
+}
+
+func (c *configuration) getMaxScreenShares() int {
+	if c.MaxScreenShares == nil || *c.MaxScreenShares < 1 {
+		return 1
+	}
+	return *c.MaxScreenShares
+}
+
+func (c *configuration) callChatEnabled() bool {
+	return c.EnableCallChat != nil && *c.EnableCallChat
+}
+
+func (c *configuration) callTimelineEnabled() bool {
+	return c.EnableCallTimeline != nil && *c.EnableCallTimeline
+}
+
+func (c *configuration) callFeedbackEnabled() bool {
+	return c.EnableCallFeedback != nil && *c.EnableCallFeedback
+}
+
+func (c *configuration) callChatPostToThread() bool {
+	return c.callChatEnabled() && c.CallChatPostToThread != nil && *c.CallChatPostToThread
+}
+
+// getMaxSDPSize returns the maximum size, in bytes, of the session
+// descriptions clients can signal.
+func (c *configuration) getMaxSDPSize() int {
+	if c.MaxSDPSizeKB == nil {
+		return defaultMaxSDPSizeKB * 1024
+	}
+	return *c.MaxSDPSizeKB * 1024
+}
+
+// getMediaInactivityTimeout returns the time after which a session with no
+// media activity is disconnected. Zero means the check is disabled.
+func (c *configuration) getMediaInactivityTimeout() time.Duration {
+	if c.MediaInactivityTimeoutSeconds == nil {
+		return 0
+	}
+	return time.Duration(*c.MediaInactivityTimeoutSeconds) * time.Second
+}
+
+// getICERestartTimeout returns the time after which the server asks a
+// session with no media activity to restart ICE. Zero means the server never
+// does.
+func (c *configuration) getICERestartTimeout() time.Duration {
+	if c.ICERestartTimeoutSeconds == nil {
+		return 0
+	}
+	return time.Duration(*c.ICERestartTimeoutSeconds) * time.Second
+}
+
+func isValidRecordingLayout(layout string) bool {
+	return layout == recordingLayoutGrid || layout == recordingLayoutActiveSpeaker
+}
+
+// getRecordingLayout returns the layout to record with, falling back to the
+// configured default if none was requested.
+func (c *configuration) getRecordingLayout(requested string) string {
+	if requested != "" {
+		return requested
+	}
+	if c.RecordingLayout == "" {
+		return recordingLayoutGrid
+	}
+	return c.RecordingLayout
+}
+
+// getRecordingStorageQuotas returns the maximum total size (in bytes) of the
+// recordings stored in a channel and in a team. Zero means no limit.
+func (c *configuration) getRecordingStorageQuotas() (int64, int64) {
+	var channelQuota, teamQuota int64
+	if c.MaxRecordingStoragePerChannelMB != nil && *c.MaxRecordingStoragePerChannelMB > 0 {
+		channelQuota = int64(*c.MaxRecordingStoragePerChannelMB) * 1024 * 1024
+	}
+	if c.MaxRecordingStoragePerTeamMB != nil && *c.MaxRecordingStoragePerTeamMB > 0 {
+		teamQuota = int64(*c.MaxRecordingStoragePerTeamMB) * 1024 * 1024
+	}
+	return channelQuota, teamQuota
+}
+
+// getRaiseHandAutoLowerTimeout returns the time after which raised hands are
+// lowered automatically, or zero if they should never be.
+func (c *configuration) getRaiseHandAutoLowerTimeout() time.Duration {
+	if c.RaiseHandAutoLowerTimeoutSeconds == nil || *c.RaiseHandAutoLowerTimeoutSeconds <= 0 {
+		return 0
+	}
+	return time.Duration(*c.RaiseHandAutoLowerTimeoutSeconds) * time.Second
+}
+
+// getRejoinCooldown returns the time participants removed from a call for the
+// given reason have to wait before joining it again.
+func (c *configuration) getRejoinCooldown(reason rejoinCooldownReason) time.Duration {
+	seconds := c.DroppedRejoinCooldownSeconds
+	if reason == rejoinCooldownReasonKicked {
+		seconds = c.KickedRejoinCooldownSeconds
+	}
+	if seconds == nil || *seconds <= 0 {
+		return 0
+	}
+	return time.Duration(*seconds) * time.Second
+}
+
+func (c *configuration) getMetricsPushInterval() time.Duration {
+	if c.MetricsPushIntervalSeconds == nil || *c.MetricsPushIntervalSeconds <= 0 {
+		return defaultMetricsPushIntervalSeconds * time.Second
+	}
+	return time.Duration(*c.MetricsPushIntervalSeconds) * time.Second
+}
+
+func (c *configuration) cpuQualityDowngradeEnabled() bool {
+	return c.EnableCPUQualityDowngrade != nil && *c.EnableCPUQualityDowngrade
+}
+
+// getCPUQualityThresholds returns the CPU usage past which the quality of
+// calls is reduced and the one under which it's restored.
+func (c *configuration) getCPUQualityThresholds() (float64, float64) {
+	downgrade, restore := defaultCPUQualityDowngradeThreshold, defaultCPUQualityRestoreThreshold
+	if c.CPUQualityDowngradeThreshold != nil {
+		downgrade = *c.CPUQualityDowngradeThreshold
+	}
+	if c.CPUQualityRestoreThreshold != nil {
+		restore = *c.CPUQualityRestoreThreshold
+	}
+	return float64(downgrade), float64(restore)
+}
+
+func (c *configuration) poorConnectionAlertsEnabled() bool {
+	return c.EnablePoorConnectionAlerts != nil && *c.EnablePoorConnectionAlerts
+}
+
+// getPoorConnectionThresholds returns the round trip time and packet loss
+// rate (as a fraction) past which a connection is considered poor, along with
+// how long it needs to stay so before alerting the host.
+func (c *configuration) getPoorConnectionThresholds() (float64, float64, time.Duration) {
+	rttMs, loss, delay := defaultPoorConnectionRTTThresholdMs, defaultPoorConnectionLossThreshold, defaultPoorConnectionAlertDelaySeconds
+	if c.PoorConnectionRTTThresholdMs != nil {
+		rttMs = *c.PoorConnectionRTTThresholdMs
+	}
+	if c.PoorConnectionLossThreshold != nil {
+		loss = *c.PoorConnectionLossThreshold
+	}
+	if c.PoorConnectionAlertDelaySeconds != nil {
+		delay = *c.PoorConnectionAlertDelaySeconds
+	}
+	return float64(rttMs), float64(loss) / 100, time.Duration(delay) * time.Second
+}
+
+func (c *configuration) getCleanUpStateWorkers() int {
+	if c.CleanUpStateWorkers == nil {
+		return defaultCleanUpStateWorkers
+	}
+	return *c.CleanUpStateWorkers
+}
+
+func (c *configuration) getDBConnectRetries() int {
+	if c.DBConnectRetries == nil {
+		return 0
+	}
+	return *c.DBConnectRetries
+}
+
+func (c *configuration) getDBConnectRetryDelay() time.Duration {
+	if c.DBConnectRetryDelaySeconds == nil || *c.DBConnectRetryDelaySeconds <= 0 {
+		return defaultDBConnectRetryDelaySeconds * time.Second
+	}
+	return time.Duration(*c.DBConnectRetryDelaySeconds) * time.Second
+}
+
+func (c *configuration) dbWriteBufferEnabled() bool {
+	return c.EnableDBWriteBuffer != nil && *c.EnableDBWriteBuffer
+}
+
+func (c *configuration) callPushNotificationsEnabled() bool {
+	return c.EnableCallPushNotifications == nil || *c.EnableCallPushNotifications
+}
+
+// newSessionMessageLimiter returns the rate limiter enforcing the combined
+// budget of chatty messages a session can send.
+func (c *configuration) newSessionMessageLimiter() *rate.Limiter {
+	limit, burst := defaultSessionMessageRateLimit, defaultSessionMessageBurst
+	if c.SessionMessageRateLimit != nil {
+		limit = *c.SessionMessageRateLimit
+	}
+	if c.SessionMessageBurst != nil {
+		burst = *c.SessionMessageBurst
+	}
+
+	if limit == 0 {
+		return rate.NewLimiter(rate.Inf, burst)
+	}
+
+	return rate.NewLimiter(rate.Limit(limit), burst)
+}
+
+// getNoiseAutoMute returns the configured automatic muting of noisy
+// participants, which calls use unless the host overrides it.
+func (c *configuration) getNoiseAutoMute() public.CallNoiseAutoMute {
+	noiseAutoMute := public.CallNoiseAutoMute{
+		ThresholdSeconds: defaultNoiseAutoMuteThresholdSeconds,
+	}
+	if c.EnableNoiseAutoMute != nil {
+		noiseAutoMute.Enabled = *c.EnableNoiseAutoMute
+	}
+	if c.NoiseAutoMuteThresholdSeconds != nil {
+		noiseAutoMute.ThresholdSeconds = *c.NoiseAutoMuteThresholdSeconds
+	}
+	return noiseAutoMute
+}
+
+func (c *configuration) recordingEmptyCallGracePeriod() time.Duration {
+	if c.RecordingEmptyCallGracePeriodSeconds == nil {
+		return 0
+	}
+	return time.Duration(*c.RecordingEmptyCallGracePeriodSeconds) * time.Second
+}
+
+// getCallEndWarning returns how long before a call is automatically ended
+// participants should be warned, or zero if they shouldn't be.
+func (c *configuration) getCallEndWarning() time.Duration {
+	if c.CallEndWarningSeconds == nil || *c.CallEndWarningSeconds <= 0 {
+		return 0
+	}
+	return time.Duration(*c.CallEndWarningSeconds) * time.Second
+}
+
 func (c *configuration) liveCaptionsEnabled() bool {
 	if c.recordingsEnabled() && c.transcriptionsEnabled() &&
 		c.EnableLiveCaptions != nil && *c.EnableLiveCaptions {
@@ -507,24 +2219,42 @@ func (p *Plugin) getClientConfig(c *configuration) ClientConfig {
 	}
 
 	return ClientConfig{
-		AllowEnableCalls:     model.NewPointer(true), // always true
-		DefaultEnabled:       c.DefaultEnabled,
-		ICEServers:           c.ICEServers,
-		ICEServersConfigs:    c.getICEServers(true),
-		MaxCallParticipants:  c.MaxCallParticipants,
-		NeedsTURNCredentials: model.NewPointer(c.TURNStaticAuthSecret != "" && len(c.ICEServersConfigs.getTURNConfigsForCredentials()) > 0),
-		AllowScreenSharing:   c.AllowScreenSharing,
-		EnableRecordings:     c.EnableRecordings,
-		EnableTranscriptions: c.EnableTranscriptions,
-		EnableLiveCaptions:   c.EnableLiveCaptions,
-		MaxRecordingDuration: c.MaxRecordingDuration,
-		EnableSimulcast:      c.EnableSimulcast,
-		EnableRinging:        c.EnableRinging,
-		SkuShortName:         skuShortName,
-		HostControlsAllowed:  p.licenseChecker.HostControlsAllowed(),
-		EnableAV1:            c.EnableAV1,
-		GroupCallsAllowed:    p.licenseChecker.GroupCallsAllowed(),
-		EnableDCSignaling:    c.EnableDCSignaling,
+		AllowEnableCalls:      model.NewPointer(true), // always true
+		DefaultEnabled:        c.DefaultEnabled,
+		ICEServers:            c.ICEServers,
+		ICEServersConfigs:     c.getICEServers(true),
+		MaxCallParticipants:   c.MaxCallParticipants,
+		NeedsTURNCredentials:  model.NewPointer(c.TURNStaticAuthSecret != "" && len(c.getICEServers(false).getTURNConfigsForCredentials()) > 0),
+		ICETransportPolicy:    c.getICETransportPolicy(),
+		AllowScreenSharing:    c.AllowScreenSharing,
+		EnableRecordings:      c.EnableRecordings,
+		EnableTranscriptions:  c.EnableTranscriptions,
+		EnableLiveCaptions:    c.EnableLiveCaptions,
+		MaxRecordingDuration:  c.MaxRecordingDuration,
+		EnableSimulcast:       c.EnableSimulcast,
+		EnableRinging:         c.EnableRinging,
+		EnableDMRinging:       c.EnableDMRinging,
+		EnableGMRinging:       c.EnableGMRinging,
+		SkuShortName:          skuShortName,
+		HostControlsAllowed:   p.licenseChecker.HostControlsAllowed(),
+		EnableAV1:             c.EnableAV1,
+		GroupCallsAllowed:     p.licenseChecker.GroupCallsAllowed(),
+		EnableDCSignaling:     c.EnableDCSignaling,
+		JoinMuted:             c.JoinMuted,
+		NoiseSuppression:      c.NoiseSuppression,
+		ConfirmCallStart:      c.ConfirmCallStart,
+		DisableVideo:          c.DisableVideo,
+		MaxVideoPublishers:    c.MaxVideoPublishers,
+		MaxScreenShares:       c.MaxScreenShares,
+		AllowedCallTags:       c.AllowedCallTags,
+		CallPresets:           c.CallPresets,
+		EnabledTeams:          c.EnabledTeams,
+		DisabledTeams:         c.DisabledTeams,
+		DMCallsDefault:        c.DMCallsDefault,
+		GMCallsDefault:        c.GMCallsDefault,
+		MaxGMCallParticipants: c.MaxGMCallParticipants,
+		EnableCallChat:        c.EnableCallChat,
+		CallChatPostToThread:  c.CallChatPostToThread,
 	}
 }
 
@@ -601,6 +2331,10 @@ func (p *Plugin) OnConfigurationChange() error {
 		return fmt.Errorf("OnConfigurationChange: failed to load config: %w", err)
 	}
 
+	// RTC settings changes are applied to the embedded server without
+	// requiring a restart.
+	p.scheduleRTCServerReload()
+
 	return nil
 }
 
@@ -667,7 +2401,8 @@ func (p *Plugin) setOverrides(cfg *configuration) {
 
 	cfg.AllowEnableCalls = model.NewPointer(true)
 
-	if l := p.API.GetLicense(); l != nil && license.IsCloud(l) {
+	l := p.API.GetLicense()
+	if l != nil && license.IsCloud(l) {
 		// On Cloud installations we want calls enabled in all channels so we
 		// override it since the plugin's default is now false.
 		*cfg.DefaultEnabled = true
@@ -684,20 +2419,34 @@ func (p *Plugin) setOverrides(cfg *configuration) {
 		} else {
 			p.LogError("setOverrides", "failed to parse MM_CALLS_MAX_PARTICIPANTS", err.Error())
 		}
-	} else if l := p.API.GetLicense(); l != nil && license.IsCloud(l) {
+	} else if l != nil && license.IsCloud(l) {
 		// otherwise, if this is a cloud installation, set it at the default
-		if license.IsCloudStarter(l) {
-			*cfg.MaxCallParticipants = cloudStarterMaxParticipantsDefault
-		} else {
-			*cfg.MaxCallParticipants = cloudPaidMaxParticipantsDefault
-		}
+		*cfg.MaxCallParticipants = license.GetLimits(l).MaxParticipants
+	}
+
+	// On Cloud the concurrent calls limit is set by the license tier unless
+	// explicitly overridden through the MM_CALLS_MAX_CONCURRENT_CALLS env variable.
+	if _, ok := p.configEnvOverrides["MaxConcurrentCalls"]; !ok && l != nil && license.IsCloud(l) {
+		*cfg.MaxConcurrentCalls = license.GetLimits(l).MaxConcurrentCalls
 	}
 
 	cfg.ICEHostOverride = strings.TrimSpace(cfg.ICEHostOverride)
 	cfg.UDPServerAddress = strings.TrimSpace(cfg.UDPServerAddress)
 	cfg.TCPServerAddress = strings.TrimSpace(cfg.TCPServerAddress)
+	cfg.ICEInterface = strings.TrimSpace(cfg.ICEInterface)
+	cfg.AllowedICECandidateTypes = strings.TrimSpace(cfg.AllowedICECandidateTypes)
+	cfg.ICECandidatesPriority = strings.TrimSpace(cfg.ICECandidatesPriority)
 	cfg.RTCDServiceURL = strings.TrimSpace(cfg.RTCDServiceURL)
 	cfg.JobServiceURL = strings.TrimSpace(cfg.JobServiceURL)
+	cfg.OutboundProxyURL = strings.TrimSpace(cfg.OutboundProxyURL)
+	cfg.ParticipantWebhookURL = strings.TrimSpace(cfg.ParticipantWebhookURL)
+	cfg.MetricsPushURL = strings.TrimSpace(cfg.MetricsPushURL)
+	cfg.RecordingAdditionalQualities = strings.TrimSpace(cfg.RecordingAdditionalQualities)
+	cfg.DefaultDisabledCallFeatures = strings.TrimSpace(cfg.DefaultDisabledCallFeatures)
+	cfg.EnabledTeams = strings.TrimSpace(cfg.EnabledTeams)
+	cfg.DisabledTeams = strings.TrimSpace(cfg.DisabledTeams)
+	cfg.RecordingKeywordTriggers = strings.TrimSpace(cfg.RecordingKeywordTriggers)
+	cfg.RecordingKeywordTriggerChannels = strings.TrimSpace(cfg.RecordingKeywordTriggerChannels)
 }
 
 func (p *Plugin) isSingleHandler() bool {
@@ -708,8 +2457,10 @@ func (p *Plugin) isSingleHandler() bool {
 		return false
 	}
 
+	// If the embedded RTC server is running the RTCD service is not in use,
+	// even if configured (i.e. RTCDFallbackToEmbedded).
 	rtcdURL := pluginCfg.getRTCDURL()
-	hasRTCD := rtcdURL != "" && p.licenseChecker.RTCDAllowed()
+	hasRTCD := rtcdURL != "" && p.licenseChecker.RTCDAllowed() && p.getRTCServer() == nil
 
 	if hasRTCD {
 		return false
@@ -728,10 +2479,34 @@ func (p *Plugin) isHA() bool {
 	return cfg.ClusterSettings.Enable != nil && *cfg.ClusterSettings.Enable
 }
 
+func (c *configuration) shortLivedTURNCredentialsEnforced() bool {
+	return c.EnforceShortLivedTURNCredentials != nil && *c.EnforceShortLivedTURNCredentials
+}
+
+// withoutStaticTURNCredentials returns the given ICE servers with any static
+// credentials stripped from the TURN ones, so that short-lived credentials get
+// generated for them instead.
+func withoutStaticTURNCredentials(cfgs ICEServersConfigs) ICEServersConfigs {
+	stripped := make(ICEServersConfigs, 0, len(cfgs))
+	for _, cfg := range cfgs {
+		if cfg.IsTURN() {
+			cfg.Username = ""
+			cfg.Credential = ""
+		}
+		stripped = append(stripped, cfg)
+	}
+	return stripped
+}
+
 func (c *configuration) getICEServers(forClient bool) ICEServersConfigs {
 	var iceServers ICEServersConfigs
 
-	for _, cfg := range c.ICEServersConfigs {
+	configs := c.ICEServersConfigs
+	if c.shortLivedTURNCredentialsEnforced() {
+		configs = withoutStaticTURNCredentials(configs)
+	}
+
+	for _, cfg := range configs {
 		if forClient && cfg.IsTURN() && cfg.Username == "" && cfg.Credential == "" {
 			continue
 		}
@@ -747,6 +2522,80 @@ func (c *configuration) getICEServers(forClient bool) ICEServersConfigs {
 	return iceServers
 }
 
+// channelICEServersConfig holds the ICE servers overriding the global ones
+// for calls in a given channel.
+type channelICEServersConfig struct {
+	ICEServersConfigs    rtc.ICEServers `json:"ice_servers_configs"`
+	TURNStaticAuthSecret string         `json:"turn_static_auth_secret"`
+}
+
+func (c *configuration) getChannelICEServersConfigs() (map[string]channelICEServersConfig, error) {
+	if c.ChannelICEServersConfigs == "" {
+		return nil, nil
+	}
+
+	var configs map[string]channelICEServersConfig
+	if err := json.Unmarshal([]byte(c.ChannelICEServersConfigs), &configs); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal: %w", err)
+	}
+
+	return configs, nil
+}
+
+func (c *configuration) channelICEServersConfigsIsValid() error {
+	configs, err := c.getChannelICEServersConfigs()
+	if err != nil {
+		return err
+	}
+
+	for channelID, cfg := range configs {
+		if !model.IsValidId(channelID) {
+			return fmt.Errorf("%q is not a valid channel ID", channelID)
+		}
+		if len(cfg.ICEServersConfigs) == 0 {
+			return fmt.Errorf("channel %q should have at least one ICE server", channelID)
+		}
+		if err := cfg.ICEServersConfigs.IsValid(); err != nil {
+			return fmt.Errorf("channel %q has an invalid ICE server: %w", channelID, err)
+		}
+		// Credentials for these servers can't be generated with the global
+		// secret since they belong to a different infrastructure.
+		turnServers := ICEServersConfigs(cfg.ICEServersConfigs)
+		if c.shortLivedTURNCredentialsEnforced() {
+			turnServers = withoutStaticTURNCredentials(turnServers)
+		}
+		if len(turnServers.getTURNConfigsForCredentials()) > 0 && cfg.TURNStaticAuthSecret == "" {
+			return fmt.Errorf("channel %q has TURN servers without credentials and no TURN static auth secret", channelID)
+		}
+	}
+
+	return nil
+}
+
+// getChannelICEServers returns the ICE servers calls in the given channel
+// should use, along with the secret to generate TURN credentials with. It falls
+// back to the global configuration when the channel has no override.
+func (c *configuration) getChannelICEServers(channelID string, forClient bool) (ICEServersConfigs, string) {
+	configs, err := c.getChannelICEServersConfigs()
+	if cfg, ok := configs[channelID]; err == nil && ok {
+		channelConfigs := ICEServersConfigs(cfg.ICEServersConfigs)
+		if c.shortLivedTURNCredentialsEnforced() {
+			channelConfigs = withoutStaticTURNCredentials(channelConfigs)
+		}
+
+		var iceServers ICEServersConfigs
+		for _, iceCfg := range channelConfigs {
+			if forClient && iceCfg.IsTURN() && iceCfg.Username == "" && iceCfg.Credential == "" {
+				continue
+			}
+			iceServers = append(iceServers, iceCfg)
+		}
+		return iceServers, cfg.TURNStaticAuthSecret
+	}
+
+	return c.getICEServers(forClient), c.TURNStaticAuthSecret
+}
+
 func (cfgs ICEServersConfigs) getTURNConfigsForCredentials() []rtc.ICEServerConfig {
 	var configs []rtc.ICEServerConfig
 	for _, cfg := range cfgs {
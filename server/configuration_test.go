@@ -4,14 +4,17 @@
 package main
 
 import (
+	"strings"
 	"testing"
 
 	transcriber "github.com/mattermost/calls-transcriber/cmd/transcriber/config"
 	"github.com/mattermost/mattermost-plugin-calls/server/enterprise"
 	pluginMocks "github.com/mattermost/mattermost-plugin-calls/server/mocks/github.com/mattermost/mattermost/server/public/plugin"
+	"github.com/mattermost/mattermost-plugin-calls/server/public"
 	"github.com/mattermost/mattermost/server/public/plugin"
 
 	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/rtcd/service/rtc"
 	"github.com/stretchr/testify/require"
 )
 
@@ -108,6 +111,640 @@ func TestConfigurationIsValid(t *testing.T) {
 			}(),
 			err: "RecordingQuality is not valid",
 		},
+		{
+			name: "invalid MaxScreenShares",
+			input: func() configuration {
+				var cfg configuration
+				cfg.SetDefaults()
+				cfg.MaxScreenShares = model.NewPointer(5)
+				return cfg
+			}(),
+			err: "MaxScreenShares is not valid: range should be [1, 4]",
+		},
+		{
+			name: "invalid CallFeedbackSampleRate",
+			input: func() configuration {
+				var cfg configuration
+				cfg.SetDefaults()
+				cfg.CallFeedbackSampleRate = model.NewPointer(0)
+				return cfg
+			}(),
+			err: "CallFeedbackSampleRate is not valid: range should be [1, 100]",
+		},
+		{
+			name: "invalid RecordingResolution",
+			input: func() configuration {
+				var cfg configuration
+				cfg.SetDefaults()
+				cfg.RecordingResolution = "480p"
+				return cfg
+			}(),
+			err: "RecordingResolution is not valid",
+		},
+		{
+			name: "invalid RecordingFrameRate",
+			input: func() configuration {
+				var cfg configuration
+				cfg.SetDefaults()
+				cfg.RecordingFrameRate = model.NewPointer(120)
+				return cfg
+			}(),
+			err: "RecordingFrameRate is not valid: range should be [10, 60]",
+		},
+		{
+			name: "invalid RecordingOutputFormat",
+			input: func() configuration {
+				var cfg configuration
+				cfg.SetDefaults()
+				cfg.RecordingOutputFormat = "webm"
+				return cfg
+			}(),
+			err: "RecordingOutputFormat is not valid",
+		},
+		{
+			name: "invalid RecordingAdditionalQualities",
+			input: func() configuration {
+				var cfg configuration
+				cfg.SetDefaults()
+				cfg.RecordingAdditionalQualities = "low,ultra"
+				return cfg
+			}(),
+			err: `RecordingAdditionalQualities is not valid: "ultra" is not a valid quality`,
+		},
+		{
+			name: "RecordingAdditionalQualities including RecordingQuality",
+			input: func() configuration {
+				var cfg configuration
+				cfg.SetDefaults()
+				cfg.RecordingAdditionalQualities = "low, Medium"
+				return cfg
+			}(),
+			err: `RecordingAdditionalQualities is not valid: "medium" is already the RecordingQuality`,
+		},
+		{
+			name: "invalid RecordingEmptyCallGracePeriodSeconds",
+			input: func() configuration {
+				var cfg configuration
+				cfg.SetDefaults()
+				cfg.RecordingEmptyCallGracePeriodSeconds = model.NewPointer(-1)
+				return cfg
+			}(),
+			err: "RecordingEmptyCallGracePeriodSeconds is not valid: range should be [0, 3600]",
+		},
+		{
+			name: "invalid CallEndWarningSeconds",
+			input: func() configuration {
+				var cfg configuration
+				cfg.SetDefaults()
+				cfg.CallEndWarningSeconds = model.NewPointer(601)
+				return cfg
+			}(),
+			err: "CallEndWarningSeconds is not valid: range should be [0, 600]",
+		},
+		{
+			name: "invalid AllowedCallTags",
+			input: func() configuration {
+				var cfg configuration
+				cfg.SetDefaults()
+				cfg.AllowedCallTags = "standup, team sync"
+				return cfg
+			}(),
+			err: `AllowedCallTags is not valid: "team sync" should only contain letters, numbers, dashes and underscores and be at most 32 characters long`,
+		},
+		{
+			name: "invalid DefaultDisabledCallFeatures",
+			input: func() configuration {
+				var cfg configuration
+				cfg.SetDefaults()
+				cfg.DefaultDisabledCallFeatures = "reactions, polls"
+				return cfg
+			}(),
+			err: `DefaultDisabledCallFeatures is not valid: "polls" is not a valid feature`,
+		},
+		{
+			name: "invalid CallPresets",
+			input: func() configuration {
+				var cfg configuration
+				cfg.SetDefaults()
+				cfg.CallPresets = `[{"name": "Webinar"}, {"name": "webinar "}]`
+				return cfg
+			}(),
+			err: `CallPresets is not valid: preset name "webinar " is duplicated`,
+		},
+		{
+			name: "invalid EnabledTeams",
+			input: func() configuration {
+				var cfg configuration
+				cfg.SetDefaults()
+				cfg.EnabledTeams = "teamA"
+				return cfg
+			}(),
+			err: `EnabledTeams is not valid: "teamA" is not a valid team ID`,
+		},
+		{
+			name: "team both enabled and disabled",
+			input: func() configuration {
+				var cfg configuration
+				cfg.SetDefaults()
+				cfg.EnabledTeams = "hqj3x4g4hfnh3eqjwu3mrpfnrw, 5ms1ebuzq3gp7ne89xjyjnm5xe"
+				cfg.DisabledTeams = "5ms1ebuzq3gp7ne89xjyjnm5xe"
+				return cfg
+			}(),
+			err: `DisabledTeams is not valid: team "5ms1ebuzq3gp7ne89xjyjnm5xe" is also in EnabledTeams`,
+		},
+		{
+			name: "invalid AllowedICECandidateTypes",
+			input: func() configuration {
+				var cfg configuration
+				cfg.SetDefaults()
+				cfg.AllowedICECandidateTypes = "srflx,mdns"
+				return cfg
+			}(),
+			err: `AllowedICECandidateTypes is not valid: "mdns" is not a valid candidate type`,
+		},
+		{
+			name: "prflx ICE candidates allowed",
+			input: func() configuration {
+				var cfg configuration
+				cfg.SetDefaults()
+				cfg.AllowedICECandidateTypes = "srflx,prflx"
+				return cfg
+			}(),
+			err: `AllowedICECandidateTypes is not valid: "prflx" is not a valid candidate type`,
+		},
+		{
+			name: "only relay ICE candidates allowed without TURN",
+			input: func() configuration {
+				var cfg configuration
+				cfg.SetDefaults()
+				cfg.AllowedICECandidateTypes = "relay"
+				return cfg
+			}(),
+			err: "AllowedICECandidateTypes is not valid: allowing only relay candidates requires a TURN server to be configured",
+		},
+		{
+			name: "MaxICECandidatesPerSession not in range",
+			input: func() configuration {
+				var cfg configuration
+				cfg.SetDefaults()
+				cfg.MaxICECandidatesPerSession = model.NewPointer(-1)
+				return cfg
+			}(),
+			err: "MaxICECandidatesPerSession is not valid: range should be [0, 64]",
+		},
+		{
+			name: "invalid ICECandidatesPriority",
+			input: func() configuration {
+				var cfg configuration
+				cfg.SetDefaults()
+				cfg.ICECandidatesPriority = "relay,mdns"
+				return cfg
+			}(),
+			err: "ICECandidatesPriority is not valid: \"mdns\" is not a valid candidate type",
+		},
+		{
+			name: "MaxSDPSizeKB not in range",
+			input: func() configuration {
+				var cfg configuration
+				cfg.SetDefaults()
+				cfg.MaxSDPSizeKB = model.NewPointer(2)
+				return cfg
+			}(),
+			err: "MaxSDPSizeKB is not valid: range should be [4, 1024]",
+		},
+		{
+			name: "invalid MultiDeviceJoinPolicy",
+			input: func() configuration {
+				var cfg configuration
+				cfg.SetDefaults()
+				cfg.MultiDeviceJoinPolicy = "kick"
+				return cfg
+			}(),
+			err: `MultiDeviceJoinPolicy is not valid: should be either "allow" or "replace"`,
+		},
+		{
+			name: "invalid HostAssignmentPolicy",
+			input: func() configuration {
+				var cfg configuration
+				cfg.SetDefaults()
+				cfg.HostAssignmentPolicy = "owner"
+				return cfg
+			}(),
+			err: `HostAssignmentPolicy is not valid: should be one of "initiator", "channel_admin" or "none"`,
+		},
+		{
+			name: "invalid ChannelPrivacyChangePolicy",
+			input: func() configuration {
+				var cfg configuration
+				cfg.SetDefaults()
+				cfg.ChannelPrivacyChangePolicy = "keep"
+				return cfg
+			}(),
+			err: `ChannelPrivacyChangePolicy is not valid: should be either "remove" or "end"`,
+		},
+		{
+			name: "invalid RecordingLayout",
+			input: func() configuration {
+				var cfg configuration
+				cfg.SetDefaults()
+				cfg.RecordingLayout = "spotlight"
+				return cfg
+			}(),
+			err: `RecordingLayout is not valid: should be either "grid" or "active_speaker"`,
+		},
+		{
+			name: "invalid MaxRecordingStoragePerChannelMB",
+			input: func() configuration {
+				var cfg configuration
+				cfg.SetDefaults()
+				cfg.MaxRecordingStoragePerChannelMB = model.NewPointer(-1)
+				return cfg
+			}(),
+			err: "MaxRecordingStoragePerChannelMB is not valid: range should be [0, 10485760]",
+		},
+		{
+			name: "invalid MaxRecordingStoragePerTeamMB",
+			input: func() configuration {
+				var cfg configuration
+				cfg.SetDefaults()
+				cfg.MaxRecordingStoragePerTeamMB = model.NewPointer(-1)
+				return cfg
+			}(),
+			err: "MaxRecordingStoragePerTeamMB is not valid: range should be [0, 10485760]",
+		},
+		{
+			name: "invalid RecordingStorageQuotaPolicy",
+			input: func() configuration {
+				var cfg configuration
+				cfg.SetDefaults()
+				cfg.RecordingStorageQuotaPolicy = "ignore"
+				return cfg
+			}(),
+			err: `RecordingStorageQuotaPolicy is not valid: should be either "block" or "delete_oldest"`,
+		},
+		{
+			name: "invalid RaiseHandAutoLowerTimeoutSeconds",
+			input: func() configuration {
+				var cfg configuration
+				cfg.SetDefaults()
+				cfg.RaiseHandAutoLowerTimeoutSeconds = model.NewPointer(-1)
+				return cfg
+			}(),
+			err: "RaiseHandAutoLowerTimeoutSeconds is not valid: range should be [0, 3600]",
+		},
+		{
+			name: "invalid KickedRejoinCooldownSeconds",
+			input: func() configuration {
+				var cfg configuration
+				cfg.SetDefaults()
+				cfg.KickedRejoinCooldownSeconds = model.NewPointer(3601)
+				return cfg
+			}(),
+			err: "KickedRejoinCooldownSeconds is not valid: range should be [0, 3600]",
+		},
+		{
+			name: "invalid DroppedRejoinCooldownSeconds",
+			input: func() configuration {
+				var cfg configuration
+				cfg.SetDefaults()
+				cfg.DroppedRejoinCooldownSeconds = model.NewPointer(-1)
+				return cfg
+			}(),
+			err: "DroppedRejoinCooldownSeconds is not valid: range should be [0, 3600]",
+		},
+		{
+			name: "invalid MaxConcurrentCalls",
+			input: func() configuration {
+				var cfg configuration
+				cfg.SetDefaults()
+				cfg.MaxConcurrentCalls = model.NewPointer(-1)
+				return cfg
+			}(),
+			err: "MaxConcurrentCalls is not valid",
+		},
+		{
+			name: "invalid DMCallsDefault",
+			input: func() configuration {
+				var cfg configuration
+				cfg.SetDefaults()
+				cfg.DMCallsDefault = "on"
+				return cfg
+			}(),
+			err: `DMCallsDefault is not valid: should be one of "inherit", "enabled" or "disabled"`,
+		},
+		{
+			name: "invalid MaxGMCallParticipants",
+			input: func() configuration {
+				var cfg configuration
+				cfg.SetDefaults()
+				cfg.MaxGMCallParticipants = model.NewPointer(-1)
+				return cfg
+			}(),
+			err: "MaxGMCallParticipants is not valid",
+		},
+		{
+			name: "invalid MaxVideoPublishers",
+			input: func() configuration {
+				var cfg configuration
+				cfg.SetDefaults()
+				cfg.MaxVideoPublishers = model.NewPointer(-1)
+				return cfg
+			}(),
+			err: "MaxVideoPublishers is not valid",
+		},
+		{
+			name: "ICEInterface combined with server address",
+			input: func() configuration {
+				var cfg configuration
+				cfg.SetDefaults()
+				cfg.ICEInterface = "eth1"
+				cfg.UDPServerAddress = "10.0.0.1"
+				return cfg
+			}(),
+			err: "ICEInterface is not valid: cannot be combined with UDPServerAddress or TCPServerAddress",
+		},
+		{
+			name: "invalid MetricsPushURL",
+			input: func() configuration {
+				var cfg configuration
+				cfg.SetDefaults()
+				cfg.MetricsPushURL = "pushgateway:9091"
+				return cfg
+			}(),
+			err: "MetricsPushURL is not valid: should be an absolute http(s) URL",
+		},
+		{
+			name: "MetricsPushIntervalSeconds not in range",
+			input: func() configuration {
+				var cfg configuration
+				cfg.SetDefaults()
+				cfg.MetricsPushIntervalSeconds = model.NewPointer(5)
+				return cfg
+			}(),
+			err: "MetricsPushIntervalSeconds is not valid: range should be [10, 3600]",
+		},
+		{
+			name: "CPUQualityDowngradeThreshold not in range",
+			input: func() configuration {
+				var cfg configuration
+				cfg.SetDefaults()
+				cfg.CPUQualityDowngradeThreshold = model.NewPointer(101)
+				return cfg
+			}(),
+			err: "CPUQualityDowngradeThreshold is not valid: range should be [1, 100]",
+		},
+		{
+			name: "CPUQualityRestoreThreshold not lower than downgrade threshold",
+			input: func() configuration {
+				var cfg configuration
+				cfg.SetDefaults()
+				cfg.CPUQualityRestoreThreshold = model.NewPointer(85)
+				return cfg
+			}(),
+			err: "CPUQualityRestoreThreshold is not valid: should be lower than CPUQualityDowngradeThreshold",
+		},
+		{
+			name: "PoorConnectionRTTThresholdMs not in range",
+			input: func() configuration {
+				var cfg configuration
+				cfg.SetDefaults()
+				cfg.PoorConnectionRTTThresholdMs = model.NewPointer(10)
+				return cfg
+			}(),
+			err: "PoorConnectionRTTThresholdMs is not valid: range should be [50, 10000]",
+		},
+		{
+			name: "PoorConnectionLossThreshold not in range",
+			input: func() configuration {
+				var cfg configuration
+				cfg.SetDefaults()
+				cfg.PoorConnectionLossThreshold = model.NewPointer(0)
+				return cfg
+			}(),
+			err: "PoorConnectionLossThreshold is not valid: range should be [1, 100]",
+		},
+		{
+			name: "PoorConnectionAlertDelaySeconds not in range",
+			input: func() configuration {
+				var cfg configuration
+				cfg.SetDefaults()
+				cfg.PoorConnectionAlertDelaySeconds = model.NewPointer(301)
+				return cfg
+			}(),
+			err: "PoorConnectionAlertDelaySeconds is not valid: range should be [1, 300]",
+		},
+		{
+			name: "NoiseAutoMuteThresholdSeconds not in range",
+			input: func() configuration {
+				var cfg configuration
+				cfg.SetDefaults()
+				cfg.NoiseAutoMuteThresholdSeconds = model.NewPointer(5)
+				return cfg
+			}(),
+			err: "NoiseAutoMuteThresholdSeconds is not valid: range should be [10, 300]",
+		},
+		{
+			name: "ICERestartTimeoutSeconds not in range",
+			input: func() configuration {
+				var cfg configuration
+				cfg.SetDefaults()
+				cfg.ICERestartTimeoutSeconds = model.NewPointer(1)
+				return cfg
+			}(),
+			err: "ICERestartTimeoutSeconds is not valid: should be 0 or in range [2, 120]",
+		},
+		{
+			name: "ICERestartTimeoutSeconds not lower than MediaInactivityTimeoutSeconds",
+			input: func() configuration {
+				var cfg configuration
+				cfg.SetDefaults()
+				cfg.ICERestartTimeoutSeconds = model.NewPointer(60)
+				return cfg
+			}(),
+			err: "ICERestartTimeoutSeconds is not valid: should be lower than MediaInactivityTimeoutSeconds",
+		},
+		{
+			name: "CleanUpStateWorkers not in range",
+			input: func() configuration {
+				var cfg configuration
+				cfg.SetDefaults()
+				cfg.CleanUpStateWorkers = model.NewPointer(0)
+				return cfg
+			}(),
+			err: "CleanUpStateWorkers is not valid: range should be [1, 32]",
+		},
+		{
+			name: "DBConnectRetries not in range",
+			input: func() configuration {
+				var cfg configuration
+				cfg.SetDefaults()
+				cfg.DBConnectRetries = model.NewPointer(-1)
+				return cfg
+			}(),
+			err: "DBConnectRetries is not valid: range should be [0, 100]",
+		},
+		{
+			name: "DBConnectRetryDelaySeconds not in range",
+			input: func() configuration {
+				var cfg configuration
+				cfg.SetDefaults()
+				cfg.DBConnectRetryDelaySeconds = model.NewPointer(0)
+				return cfg
+			}(),
+			err: "DBConnectRetryDelaySeconds is not valid: range should be [1, 300]",
+		},
+		{
+			name: "invalid SessionMessageRateLimit",
+			input: func() configuration {
+				var cfg configuration
+				cfg.SetDefaults()
+				cfg.SessionMessageRateLimit = model.NewPointer(-1)
+				return cfg
+			}(),
+			err: "SessionMessageRateLimit is not valid: range should be [0, 1000]",
+		},
+		{
+			name: "invalid SessionMessageBurst",
+			input: func() configuration {
+				var cfg configuration
+				cfg.SetDefaults()
+				cfg.SessionMessageBurst = model.NewPointer(0)
+				return cfg
+			}(),
+			err: "SessionMessageBurst is not valid: range should be [1, 10000]",
+		},
+		{
+			name: "invalid RecordingWatermarkTemplate",
+			input: func() configuration {
+				var cfg configuration
+				cfg.SetDefaults()
+				cfg.RecordingWatermarkTemplate = strings.Repeat("a", 257)
+				return cfg
+			}(),
+			err: "RecordingWatermarkTemplate is not valid: length should be at most 256",
+		},
+		{
+			name: "invalid OutboundProxyURL",
+			input: func() configuration {
+				var cfg configuration
+				cfg.SetDefaults()
+				cfg.OutboundProxyURL = "socks5://proxy.example.com:1080"
+				return cfg
+			}(),
+			err: "OutboundProxyURL is not valid: should be an absolute http(s) URL",
+		},
+		{
+			name: "invalid RecordingWebhookURL",
+			input: func() configuration {
+				var cfg configuration
+				cfg.SetDefaults()
+				cfg.RecordingWebhookURL = "summarizer.example.com"
+				return cfg
+			}(),
+			err: "RecordingWebhookURL is not valid: should be an absolute http(s) URL",
+		},
+		{
+			name: "invalid ParticipantWebhookURL",
+			input: func() configuration {
+				var cfg configuration
+				cfg.SetDefaults()
+				cfg.ParticipantWebhookURL = "attendance.example.com"
+				cfg.ParticipantWebhookSecret = "secret"
+				return cfg
+			}(),
+			err: "ParticipantWebhookURL is not valid: should be an absolute http(s) URL",
+		},
+		{
+			name: "missing ParticipantWebhookSecret",
+			input: func() configuration {
+				var cfg configuration
+				cfg.SetDefaults()
+				cfg.ParticipantWebhookURL = "https://attendance.example.com/webhook"
+				return cfg
+			}(),
+			err: "ParticipantWebhookSecret should not be empty when ParticipantWebhookURL is set",
+		},
+		{
+			name: "RecordingWebhookMaxRetries not in range",
+			input: func() configuration {
+				var cfg configuration
+				cfg.SetDefaults()
+				cfg.RecordingWebhookMaxRetries = model.NewPointer(-1)
+				return cfg
+			}(),
+			err: "RecordingWebhookMaxRetries is not valid: range should be [0, 10]",
+		},
+		{
+			name: "RecordingUploadMaxRetries not in range",
+			input: func() configuration {
+				var cfg configuration
+				cfg.SetDefaults()
+				cfg.RecordingUploadMaxRetries = model.NewPointer(11)
+				return cfg
+			}(),
+			err: "RecordingUploadMaxRetries is not valid: range should be [0, 10]",
+		},
+		{
+			name: "invalid ChannelICEServersConfigs",
+			input: func() configuration {
+				var cfg configuration
+				cfg.SetDefaults()
+				cfg.ChannelICEServersConfigs = `{"channelA":{"ice_servers_configs":[{"urls":["stun:stun.example.org"]}]}}`
+				return cfg
+			}(),
+			err: `ChannelICEServersConfigs is not valid: "channelA" is not a valid channel ID`,
+		},
+		{
+			name: "ChannelICEServersConfigs missing TURN secret",
+			input: func() configuration {
+				var cfg configuration
+				cfg.SetDefaults()
+				cfg.TURNStaticAuthSecret = "secret"
+				cfg.ChannelICEServersConfigs = `{"hqj3x4g4hfnh3eqjwu3mrpfnrw":{"ice_servers_configs":[{"urls":["turn:turn.example.org"]}]}}`
+				return cfg
+			}(),
+			err: `ChannelICEServersConfigs is not valid: channel "hqj3x4g4hfnh3eqjwu3mrpfnrw" has TURN servers without credentials and no TURN static auth secret`,
+		},
+		{
+			name: "RTCDKeepaliveIntervalSeconds not in range",
+			input: func() configuration {
+				var cfg configuration
+				cfg.SetDefaults()
+				cfg.RTCDKeepaliveIntervalSeconds = model.NewPointer(3601)
+				return cfg
+			}(),
+			err: "RTCDKeepaliveIntervalSeconds is not valid: range should be [0, 3600]",
+		},
+		{
+			name: "MaxConcurrentTranscriptionJobs not in range",
+			input: func() configuration {
+				var cfg configuration
+				cfg.SetDefaults()
+				cfg.MaxConcurrentTranscriptionJobs = model.NewPointer(-1)
+				return cfg
+			}(),
+			err: "MaxConcurrentTranscriptionJobs is not valid: range should be [0, 1000]",
+		},
+		{
+			name: "relative RecordingUploadSpoolDirectory",
+			input: func() configuration {
+				var cfg configuration
+				cfg.SetDefaults()
+				cfg.RecordingUploadSpoolDirectory = "spool"
+				return cfg
+			}(),
+			err: "RecordingUploadSpoolDirectory is not valid: should be an absolute path",
+		},
+		{
+			name: "invalid RecordingKeywordTriggerChannels",
+			input: func() configuration {
+				var cfg configuration
+				cfg.SetDefaults()
+				cfg.RecordingKeywordTriggerChannels = "hqj3x4g4hfnh3eqjwu3mrpfnrw, channelA"
+				return cfg
+			}(),
+			err: `RecordingKeywordTriggerChannels is not valid: "channelA" is not a valid channel ID`,
+		},
 		{
 			name: "invalid ICEHostPortOverride",
 			input: func() configuration {
@@ -212,6 +849,216 @@ func TestConfigurationIsValid(t *testing.T) {
 	}
 }
 
+func TestDefaultEnabledForTeam(t *testing.T) {
+	teamA := model.NewId()
+	teamB := model.NewId()
+	teamC := model.NewId()
+
+	var cfg configuration
+	cfg.SetDefaults()
+	cfg.EnabledTeams = teamA
+	cfg.DisabledTeams = teamB + ", " + model.NewId()
+	require.NoError(t, cfg.IsValid())
+
+	t.Run("test mode", func(t *testing.T) {
+		cfg.DefaultEnabled = model.NewPointer(false)
+
+		require.Equal(t, model.NewPointer(true), cfg.getTeamCallsEnabled(teamA))
+		require.True(t, cfg.defaultEnabledForTeam(teamA))
+		require.Equal(t, model.NewPointer(false), cfg.getTeamCallsEnabled(teamB))
+		require.False(t, cfg.defaultEnabledForTeam(teamB))
+		require.Nil(t, cfg.getTeamCallsEnabled(teamC))
+		require.False(t, cfg.defaultEnabledForTeam(teamC))
+		require.False(t, cfg.defaultEnabledForTeam(""))
+	})
+
+	t.Run("live mode", func(t *testing.T) {
+		cfg.DefaultEnabled = model.NewPointer(true)
+
+		require.True(t, cfg.defaultEnabledForTeam(teamA))
+		require.False(t, cfg.defaultEnabledForTeam(teamB))
+		require.True(t, cfg.defaultEnabledForTeam(teamC))
+		require.True(t, cfg.defaultEnabledForTeam(""))
+	})
+}
+
+func TestGetRecordingLayout(t *testing.T) {
+	var cfg configuration
+	require.Equal(t, "grid", cfg.getRecordingLayout(""))
+
+	cfg.SetDefaults()
+	require.Equal(t, "grid", cfg.getRecordingLayout(""))
+	require.Equal(t, "active_speaker", cfg.getRecordingLayout("active_speaker"))
+
+	cfg.RecordingLayout = "active_speaker"
+	require.NoError(t, cfg.IsValid())
+	require.Equal(t, "active_speaker", cfg.getRecordingLayout(""))
+	require.Equal(t, "grid", cfg.getRecordingLayout("grid"))
+}
+
+func TestChannelTypeCallsConfig(t *testing.T) {
+	teamID := model.NewId()
+
+	var cfg configuration
+	cfg.SetDefaults()
+	cfg.DefaultEnabled = model.NewPointer(false)
+	cfg.DMCallsDefault = channelTypeCallsDefaultEnabled
+	cfg.MaxCallParticipants = model.NewPointer(20)
+	cfg.MaxGMCallParticipants = model.NewPointer(5)
+	cfg.EnableRinging = model.NewPointer(true)
+	cfg.EnableGMRinging = model.NewPointer(false)
+	require.NoError(t, cfg.IsValid())
+
+	t.Run("DM", func(t *testing.T) {
+		require.Equal(t, model.NewPointer(true), cfg.getChannelTypeCallsEnabled(model.ChannelTypeDirect))
+		require.True(t, cfg.defaultEnabledForChannel("", model.ChannelTypeDirect))
+		require.True(t, cfg.ringingEnabledForChannelType(model.ChannelTypeDirect))
+		require.Equal(t, 20, cfg.maxParticipantsForChannelType(model.ChannelTypeDirect))
+	})
+
+	t.Run("GM", func(t *testing.T) {
+		require.Nil(t, cfg.getChannelTypeCallsEnabled(model.ChannelTypeGroup))
+		require.False(t, cfg.defaultEnabledForChannel("", model.ChannelTypeGroup))
+		require.False(t, cfg.ringingEnabledForChannelType(model.ChannelTypeGroup))
+		require.Equal(t, 5, cfg.maxParticipantsForChannelType(model.ChannelTypeGroup))
+
+		// The group message limit can only lower the global one.
+		cfg.MaxCallParticipants = model.NewPointer(3)
+		defer func() { cfg.MaxCallParticipants = model.NewPointer(20) }()
+		require.Equal(t, 3, cfg.maxParticipantsForChannelType(model.ChannelTypeGroup))
+	})
+
+	t.Run("channel", func(t *testing.T) {
+		require.Nil(t, cfg.getChannelTypeCallsEnabled(model.ChannelTypeOpen))
+		require.False(t, cfg.defaultEnabledForChannel(teamID, model.ChannelTypeOpen))
+		require.False(t, cfg.ringingEnabledForChannelType(model.ChannelTypeOpen))
+		require.Equal(t, 20, cfg.maxParticipantsForChannelType(model.ChannelTypeOpen))
+
+		cfg.EnabledTeams = teamID
+		defer func() { cfg.EnabledTeams = "" }()
+		require.True(t, cfg.defaultEnabledForChannel(teamID, model.ChannelTypeOpen))
+	})
+
+	t.Run("ringing disabled", func(t *testing.T) {
+		cfg.EnableRinging = model.NewPointer(false)
+		defer func() { cfg.EnableRinging = model.NewPointer(true) }()
+		require.False(t, cfg.ringingEnabledForChannelType(model.ChannelTypeDirect))
+		require.True(t, cfg.ringOnStart(model.ChannelTypeDirect))
+	})
+}
+
+func TestResolveCallsEnabled(t *testing.T) {
+	teamA := model.NewId()
+	teamB := model.NewId()
+
+	var cfg configuration
+	cfg.SetDefaults()
+	cfg.EnabledTeams = teamA
+
+	t.Run("channel setting", func(t *testing.T) {
+		enabled, source := cfg.resolveCallsEnabled(&public.CallsChannel{Enabled: false}, teamA)
+		require.Equal(t, model.NewPointer(false), enabled)
+		require.Equal(t, callsEnabledSourceChannel, source)
+	})
+
+	t.Run("team setting", func(t *testing.T) {
+		enabled, source := cfg.resolveCallsEnabled(nil, teamA)
+		require.Equal(t, model.NewPointer(true), enabled)
+		require.Equal(t, callsEnabledSourceTeam, source)
+	})
+
+	t.Run("default", func(t *testing.T) {
+		enabled, source := cfg.resolveCallsEnabled(nil, teamB)
+		require.Nil(t, enabled)
+		require.Equal(t, callsEnabledSourceDefault, source)
+	})
+}
+
+func TestGetChannelICEServers(t *testing.T) {
+	channelID := model.NewId()
+
+	var cfg configuration
+	cfg.SetDefaults()
+	cfg.TURNStaticAuthSecret = "globalSecret"
+	cfg.ICEServersConfigs = ICEServersConfigs{{URLs: []string{"turn:turn.internal.example.org"}}}
+	cfg.ChannelICEServersConfigs = `{"` + channelID + `":{"ice_servers_configs":[` +
+		`{"urls":["stun:stun.external.example.org"]},{"urls":["turn:turn.external.example.org"]}],` +
+		`"turn_static_auth_secret":"externalSecret"}}`
+	require.NoError(t, cfg.IsValid())
+
+	t.Run("override", func(t *testing.T) {
+		iceServers, secret := cfg.getChannelICEServers(channelID, true)
+		require.Equal(t, ICEServersConfigs{{URLs: []string{"stun:stun.external.example.org"}}}, iceServers)
+		require.Equal(t, "externalSecret", secret)
+
+		iceServers, _ = cfg.getChannelICEServers(channelID, false)
+		require.Equal(t, []rtc.ICEServerConfig{{URLs: []string{"turn:turn.external.example.org"}}}, iceServers.getTURNConfigsForCredentials())
+	})
+
+	t.Run("fallback", func(t *testing.T) {
+		iceServers, secret := cfg.getChannelICEServers(model.NewId(), false)
+		require.Equal(t, cfg.getICEServers(false), iceServers)
+		require.Equal(t, "globalSecret", secret)
+	})
+}
+
+func TestEnforceShortLivedTURNCredentials(t *testing.T) {
+	channelID := model.NewId()
+
+	var cfg configuration
+	cfg.SetDefaults()
+	cfg.ICEServersConfigs = ICEServersConfigs{
+		{URLs: []string{"stun:stun.example.org"}},
+		{URLs: []string{"turn:turn.example.org"}, Username: "static", Credential: "password"},
+	}
+	cfg.ChannelICEServersConfigs = `{"` + channelID + `":{"ice_servers_configs":[` +
+		`{"urls":["turn:turn.external.example.org"],"username":"static","credential":"password"}]}}`
+
+	t.Run("disabled", func(t *testing.T) {
+		require.NoError(t, cfg.IsValid())
+		require.Equal(t, cfg.ICEServersConfigs, cfg.getICEServers(true))
+		require.Empty(t, cfg.getICEServers(false).getTURNConfigsForCredentials())
+	})
+
+	t.Run("missing secret", func(t *testing.T) {
+		cfg := *cfg.Clone()
+		cfg.EnforceShortLivedTURNCredentials = model.NewPointer(true)
+		require.EqualError(t, cfg.IsValid(), "EnforceShortLivedTURNCredentials is not valid: TURNStaticAuthSecret should be set")
+	})
+
+	t.Run("no expiration", func(t *testing.T) {
+		cfg := *cfg.Clone()
+		cfg.EnforceShortLivedTURNCredentials = model.NewPointer(true)
+		cfg.TURNStaticAuthSecret = "secret"
+		cfg.TURNCredentialsExpirationMinutes = model.NewPointer(0)
+		require.EqualError(t, cfg.IsValid(), "EnforceShortLivedTURNCredentials is not valid: TURNCredentialsExpirationMinutes should be in range [1, 10080)")
+	})
+
+	t.Run("channel override without secret", func(t *testing.T) {
+		cfg := *cfg.Clone()
+		cfg.EnforceShortLivedTURNCredentials = model.NewPointer(true)
+		cfg.TURNStaticAuthSecret = "secret"
+		require.EqualError(t, cfg.IsValid(), `ChannelICEServersConfigs is not valid: channel "`+channelID+`" has TURN servers without credentials and no TURN static auth secret`)
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		cfg := *cfg.Clone()
+		cfg.EnforceShortLivedTURNCredentials = model.NewPointer(true)
+		cfg.TURNStaticAuthSecret = "secret"
+		cfg.ChannelICEServersConfigs = ""
+		require.NoError(t, cfg.IsValid())
+
+		// Static credentials are never handed out to clients.
+		require.Equal(t, ICEServersConfigs{{URLs: []string{"stun:stun.example.org"}}}, cfg.getICEServers(true))
+
+		// Credentials get generated in their place.
+		require.Equal(t, []rtc.ICEServerConfig{{URLs: []string{"turn:turn.example.org"}}}, cfg.getICEServers(false).getTURNConfigsForCredentials())
+
+		// The configured servers are left untouched.
+		require.Equal(t, "static", cfg.ICEServersConfigs[1].Username)
+	})
+}
+
 func TestGetClientConfig(t *testing.T) {
 	mockAPI := &pluginMocks.MockAPI{}
 
@@ -243,6 +1090,27 @@ func TestGetClientConfig(t *testing.T) {
 	require.Equal(t, true, *clientCfg.AllowEnableCalls)
 	require.Equal(t, p.getConfiguration().DefaultEnabled, clientCfg.DefaultEnabled)
 
+	// Video
+	require.Equal(t, model.NewPointer(false), clientCfg.DisableVideo)
+	require.False(t, p.getConfiguration().videoDisabled())
+	*p.configuration.DisableVideo = true
+	clientCfg = p.getClientConfig(p.getConfiguration())
+	require.Equal(t, model.NewPointer(true), clientCfg.DisableVideo)
+	require.True(t, p.getConfiguration().videoDisabled())
+	require.Equal(t, model.NewPointer(0), clientCfg.MaxVideoPublishers)
+	require.Equal(t, model.NewPointer(true), clientCfg.NoiseSuppression)
+	require.True(t, p.getConfiguration().noiseSuppressionRecommended())
+	*p.configuration.MaxVideoPublishers = 4
+	clientCfg = p.getClientConfig(p.getConfiguration())
+	require.Equal(t, model.NewPointer(4), clientCfg.MaxVideoPublishers)
+	require.Equal(t, 4, p.getConfiguration().getMaxVideoPublishers())
+
+	// ICE
+	require.Equal(t, "all", clientCfg.ICETransportPolicy)
+	p.configuration.AllowedICECandidateTypes = "relay"
+	clientCfg = p.getClientConfig(p.getConfiguration())
+	require.Equal(t, "relay", clientCfg.ICETransportPolicy)
+
 	// Host controls
 	require.Equal(t, false, clientCfg.HostControlsAllowed)
 	mockAPI.On("GetLicense").Unset()
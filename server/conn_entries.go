@@ -0,0 +1,58 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"fmt"
+)
+
+// The helpers below manage the per-connection maps (e.g. lobby sessions or
+// join requests on hold) that are guarded by p.mut.
+
+// addConnEntry stores the entry for the given connection. It fails if the
+// connection has already joined a call or already has an entry, in which case
+// desc describes the state the connection is in.
+func addConnEntry[T any](p *Plugin, entries map[string]*T, connID string, entry *T, desc string) error {
+	p.mut.Lock()
+	defer p.mut.Unlock()
+
+	if p.sessions[connID] != nil {
+		return fmt.Errorf("connection is already in a call")
+	}
+	if entries[connID] != nil {
+		return fmt.Errorf("connection is already %s", desc)
+	}
+	entries[connID] = entry
+
+	return nil
+}
+
+// removeConnEntry removes the entry for the given connection as long as it's
+// still the given one. It returns whether the entry was removed.
+func removeConnEntry[T any](p *Plugin, entries map[string]*T, connID string, entry *T) bool {
+	p.mut.Lock()
+	defer p.mut.Unlock()
+
+	if entries[connID] != entry {
+		return false
+	}
+	delete(entries, connID)
+
+	return true
+}
+
+// takeConnEntry removes and returns the entry for the given connection, if
+// any.
+func takeConnEntry[T any](p *Plugin, entries map[string]*T, connID string) *T {
+	// This is called on every WebSocket disconnection so we first check
+	// under a read lock to avoid contention in the common case.
+	p.mut.RLock()
+	entry := entries[connID]
+	p.mut.RUnlock()
+	if entry == nil || !removeConnEntry(p, entries, connID, entry) {
+		return nil
+	}
+
+	return entry
+}
@@ -0,0 +1,53 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConnEntries(t *testing.T) {
+	p := &Plugin{
+		sessions: map[string]*session{},
+	}
+	entries := map[string]*pendingCallStart{}
+
+	connID := model.NewId()
+	entry := &pendingCallStart{userID: model.NewId()}
+
+	t.Run("add", func(t *testing.T) {
+		require.NoError(t, addConnEntry(p, entries, connID, entry, "waiting"))
+		require.Equal(t, entry, entries[connID])
+
+		err := addConnEntry(p, entries, connID, &pendingCallStart{}, "waiting")
+		require.EqualError(t, err, "connection is already waiting")
+		require.Equal(t, entry, entries[connID])
+	})
+
+	t.Run("add while in call", func(t *testing.T) {
+		otherConnID := model.NewId()
+		p.sessions[otherConnID] = &session{}
+		defer delete(p.sessions, otherConnID)
+
+		err := addConnEntry(p, entries, otherConnID, &pendingCallStart{}, "waiting")
+		require.EqualError(t, err, "connection is already in a call")
+		require.Nil(t, entries[otherConnID])
+	})
+
+	t.Run("remove other entry", func(t *testing.T) {
+		require.False(t, removeConnEntry(p, entries, connID, &pendingCallStart{}))
+		require.Equal(t, entry, entries[connID])
+	})
+
+	t.Run("take", func(t *testing.T) {
+		require.Nil(t, takeConnEntry(p, entries, model.NewId()))
+		require.Equal(t, entry, takeConnEntry(p, entries, connID))
+		require.Empty(t, entries)
+		require.Nil(t, takeConnEntry(p, entries, connID))
+	})
+}
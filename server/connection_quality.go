@@ -0,0 +1,179 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"slices"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-calls/server/public"
+)
+
+// poorConnectionAlertInterval is the minimum time between two alerts sent to
+// the host of a call so that it doesn't get spammed while several
+// participants are struggling.
+var poorConnectionAlertInterval = time.Minute
+
+// connectionQualityState tracks the connection quality reported by a
+// session's client.
+type connectionQualityState struct {
+	// the time the quality became poor. Zero while it's fine.
+	poorSince time.Time
+	// whether the host was alerted of the ongoing poor quality.
+	reported bool
+}
+
+// update returns whether the connection has been poor for long enough that
+// the host should be alerted. It only returns true once until the quality
+// recovers.
+func (s *connectionQualityState) update(poor bool, now time.Time, delay time.Duration) bool {
+	if !poor {
+		*s = connectionQualityState{}
+		return false
+	}
+
+	if s.poorSince.IsZero() {
+		s.poorSince = now
+	}
+
+	if s.reported || now.Sub(s.poorSince) < delay {
+		return false
+	}
+
+	s.reported = true
+
+	return true
+}
+
+// pendingPoorConnections holds the sessions of a call to name in the next
+// alert sent to the host.
+type pendingPoorConnections struct {
+	// A map of sessionID -> userID.
+	sessions map[string]string
+	sentAt   time.Time
+}
+
+func (p *Plugin) queuePoorConnection(callID, sessionID, userID string) {
+	p.poorConnectionsMut.Lock()
+	defer p.poorConnectionsMut.Unlock()
+
+	pending := p.poorConnections[callID]
+	if pending == nil {
+		pending = &pendingPoorConnections{sessions: map[string]string{}}
+		p.poorConnections[callID] = pending
+	}
+	pending.sessions[sessionID] = userID
+}
+
+func (p *Plugin) dequeuePoorConnection(callID, sessionID string) {
+	p.poorConnectionsMut.Lock()
+	defer p.poorConnectionsMut.Unlock()
+
+	pending := p.poorConnections[callID]
+	if pending == nil {
+		return
+	}
+	delete(pending.sessions, sessionID)
+
+	if len(pending.sessions) == 0 && time.Since(pending.sentAt) >= poorConnectionAlertInterval {
+		delete(p.poorConnections, callID)
+	}
+}
+
+// flushPoorConnections returns the sessions to alert the host of, if any and
+// enough time has passed since the previous alert.
+func (p *Plugin) flushPoorConnections(callID string, now time.Time) map[string]string {
+	p.poorConnectionsMut.Lock()
+	defer p.poorConnectionsMut.Unlock()
+
+	pending := p.poorConnections[callID]
+	if pending == nil || now.Sub(pending.sentAt) < poorConnectionAlertInterval {
+		return nil
+	}
+
+	if len(pending.sessions) == 0 {
+		delete(p.poorConnections, callID)
+		return nil
+	}
+
+	sessions := pending.sessions
+	pending.sessions = map[string]string{}
+	pending.sentAt = now
+
+	return sessions
+}
+
+// handleConnectionQualityReport keeps track of the connection quality
+// reported by the session's client, alerting the host of the call once it has
+// stayed poor for a while.
+func (p *Plugin) handleConnectionQualityReport(us *session, payload public.ClientConnectionQualityMetricPayload) {
+	cfg := p.getConfiguration()
+	if !cfg.poorConnectionAlertsEnabled() {
+		return
+	}
+
+	rttThreshold, lossThreshold, delay := cfg.getPoorConnectionThresholds()
+	poor := payload.RTTMs >= rttThreshold || payload.LossRate >= lossThreshold
+
+	now := time.Now()
+	// Queuing happens while holding the lock so that concurrent reports can't
+	// dequeue the session ahead of it being queued.
+	us.connQualityMut.Lock()
+	wasReported := us.connQuality.reported
+	if us.connQuality.update(poor, now, delay) {
+		p.LogDebug("session has a poor connection", "userID", us.userID, "connID", us.connID, "callID", us.callID)
+		p.queuePoorConnection(us.callID, us.originalConnID, us.userID)
+	} else if wasReported && !poor {
+		p.dequeuePoorConnection(us.callID, us.originalConnID)
+	}
+	us.connQualityMut.Unlock()
+
+	if sessions := p.flushPoorConnections(us.callID, now); len(sessions) > 0 {
+		p.alertHostOfPoorConnections(us.getChannelID(), us.callID, sessions)
+	}
+}
+
+// alertHostOfPoorConnections lets the host of the call know which
+// participants have been having a poor connection.
+func (p *Plugin) alertHostOfPoorConnections(channelID, callID string, sessions map[string]string) {
+	state, err := p.getCallState(channelID, false)
+	if err != nil {
+		p.LogError("failed to get call state", "err", err.Error(), "channelID", channelID)
+		return
+	}
+	if state == nil || state.Call.ID != callID {
+		return
+	}
+
+	hostID := state.Call.GetHostID()
+	if hostID == "" {
+		return
+	}
+
+	sessionIDs := make([]string, 0, len(sessions))
+	for sessionID := range sessions {
+		// The host doesn't need to be told about their own connection and
+		// sessions may have left in the meantime.
+		if _, ok := state.sessions[sessionID]; !ok || sessions[sessionID] == hostID {
+			continue
+		}
+		sessionIDs = append(sessionIDs, sessionID)
+	}
+	if len(sessionIDs) == 0 {
+		return
+	}
+	slices.Sort(sessionIDs)
+
+	userIDs := make([]string, 0, len(sessionIDs))
+	for _, sessionID := range sessionIDs {
+		userIDs = append(userIDs, sessions[sessionID])
+	}
+
+	p.publishWebSocketEvent(wsEventCallPoorConnections, map[string]interface{}{
+		"call_id":     callID,
+		"channel_id":  channelID,
+		"session_ids": sessionIDs,
+		"user_ids":    userIDs,
+	}, &WebSocketBroadcast{UserID: hostID, ReliableClusterSend: true})
+}
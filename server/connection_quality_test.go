@@ -0,0 +1,172 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-calls/server/public"
+
+	serverMocks "github.com/mattermost/mattermost-plugin-calls/server/mocks/github.com/mattermost/mattermost-plugin-calls/server/interfaces"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConnectionQualityStateUpdate(t *testing.T) {
+	now := time.Now()
+	delay := 10 * time.Second
+
+	t.Run("good connection", func(t *testing.T) {
+		var s connectionQualityState
+		require.False(t, s.update(false, now, delay))
+		require.True(t, s.poorSince.IsZero())
+	})
+
+	t.Run("momentary blip", func(t *testing.T) {
+		var s connectionQualityState
+		require.False(t, s.update(true, now, delay))
+		require.False(t, s.update(true, now.Add(5*time.Second), delay))
+		require.False(t, s.update(false, now.Add(6*time.Second), delay))
+		// The delay starts over once the connection recovers.
+		require.False(t, s.update(true, now.Add(12*time.Second), delay))
+		require.False(t, s.update(true, now.Add(15*time.Second), delay))
+	})
+
+	t.Run("sustained", func(t *testing.T) {
+		var s connectionQualityState
+		require.False(t, s.update(true, now, delay))
+		require.True(t, s.update(true, now.Add(delay), delay))
+		// Only reported once.
+		require.False(t, s.update(true, now.Add(2*delay), delay))
+
+		// Reported again if it becomes poor after recovering.
+		require.False(t, s.update(false, now.Add(3*delay), delay))
+		require.False(t, s.update(true, now.Add(4*delay), delay))
+		require.True(t, s.update(true, now.Add(5*delay), delay))
+	})
+}
+
+func TestFlushPoorConnections(t *testing.T) {
+	p := &Plugin{
+		poorConnections: map[string]*pendingPoorConnections{},
+	}
+
+	now := time.Now()
+
+	t.Run("nothing pending", func(t *testing.T) {
+		require.Nil(t, p.flushPoorConnections("callID", now))
+	})
+
+	t.Run("throttled", func(t *testing.T) {
+		p.queuePoorConnection("callID", "sessionA", "userA")
+		require.Equal(t, map[string]string{"sessionA": "userA"}, p.flushPoorConnections("callID", now))
+
+		// Sessions having issues soon after an alert are held back.
+		p.queuePoorConnection("callID", "sessionB", "userB")
+		p.queuePoorConnection("callID", "sessionC", "userC")
+		require.Nil(t, p.flushPoorConnections("callID", now.Add(poorConnectionAlertInterval/2)))
+
+		// Recovered sessions are dropped.
+		p.dequeuePoorConnection("callID", "sessionC")
+		require.Equal(t, map[string]string{"sessionB": "userB"}, p.flushPoorConnections("callID", now.Add(poorConnectionAlertInterval)))
+	})
+
+	t.Run("per call", func(t *testing.T) {
+		p.queuePoorConnection("callID2", "sessionD", "userD")
+		require.Equal(t, map[string]string{"sessionD": "userD"}, p.flushPoorConnections("callID2", now))
+	})
+
+	t.Run("cleanup", func(t *testing.T) {
+		require.Nil(t, p.flushPoorConnections("callID", now.Add(3*poorConnectionAlertInterval)))
+		require.NotContains(t, p.poorConnections, "callID")
+	})
+}
+
+func TestHandleConnectionQualityReportDisabled(t *testing.T) {
+	var cfg configuration
+	cfg.SetDefaults()
+	p := &Plugin{
+		configuration:   &cfg,
+		poorConnections: map[string]*pendingPoorConnections{},
+	}
+
+	us := newUserSession("userID", "channelID", "connID", "callID", false)
+	p.handleConnectionQualityReport(us, public.ClientConnectionQualityMetricPayload{RTTMs: 5000, LossRate: 0.5})
+	require.True(t, us.connQuality.poorSince.IsZero())
+	require.Empty(t, p.poorConnections)
+}
+
+func TestHandleConnectionQualityReportConcurrent(t *testing.T) {
+	var cfg configuration
+	cfg.SetDefaults()
+	cfg.EnablePoorConnectionAlerts = model.NewPointer(true)
+	p := &Plugin{
+		configuration:   &cfg,
+		poorConnections: map[string]*pendingPoorConnections{},
+	}
+
+	us := newUserSession("userID", "channelID", "connID", "callID", false)
+
+	// Reports can come in both from the WebSocket reader and the plugin hook.
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(poor bool) {
+			defer wg.Done()
+			payload := public.ClientConnectionQualityMetricPayload{RTTMs: 100}
+			if poor {
+				payload.RTTMs = 5000
+			}
+			p.handleConnectionQualityReport(us, payload)
+		}(i%2 == 0)
+	}
+	wg.Wait()
+
+	// The alert delay hasn't passed so nothing should be queued.
+	us.connQualityMut.Lock()
+	require.False(t, us.connQuality.reported)
+	us.connQualityMut.Unlock()
+	require.Empty(t, p.poorConnections)
+}
+
+func TestHandleConnectionQualityMetric(t *testing.T) {
+	mockMetrics := &serverMocks.MockMetrics{}
+	defer mockMetrics.AssertExpectations(t)
+
+	var cfg configuration
+	cfg.SetDefaults()
+	p := &Plugin{
+		configuration:   &cfg,
+		metrics:         mockMetrics,
+		poorConnections: map[string]*pendingPoorConnections{},
+	}
+
+	us := newUserSession("userID", "channelID", "connID", "callID", false)
+
+	t.Run("invalid", func(t *testing.T) {
+		err := p.handleMetricMessage(us, public.MetricClientConnectionQuality, `{"rtt_ms":100,"loss_rate":1.5}`)
+		require.EqualError(t, err, "failed to validate payload: invalid loss rate 1.5: range should be [0, 1]")
+	})
+
+	t.Run("embedded", func(t *testing.T) {
+		mockMetrics.On("ObserveClientPacketLoss", rtcServerTypeEmbedded, 0.05).Once()
+		err := p.handleMetricMessage(us, public.MetricClientConnectionQuality, `{"rtt_ms":100,"loss_rate":0.05}`)
+		require.NoError(t, err)
+	})
+
+	t.Run("rtcd", func(t *testing.T) {
+		p.rtcdManager = &rtcdClientManager{}
+		defer func() {
+			p.rtcdManager = nil
+		}()
+
+		mockMetrics.On("ObserveClientPacketLoss", rtcServerTypeRTCD, 0.2).Once()
+		err := p.handleMetricMessage(us, public.MetricClientConnectionQuality, `{"rtt_ms":100,"loss_rate":0.2}`)
+		require.NoError(t, err)
+	})
+}
@@ -0,0 +1,179 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-calls/server/db"
+)
+
+// cpuPressureSamples is the number of consecutive samples past the thresholds
+// needed to change state, so that short spikes don't make quality flap.
+const cpuPressureSamples = 3
+
+var cpuPressureSampleInterval = 5 * time.Second
+
+// cpuSampler computes the CPU usage of the node from /proc/stat.
+type cpuSampler struct {
+	prevIdle  uint64
+	prevTotal uint64
+}
+
+// parseCPUTimes returns the idle and total CPU times from the aggregate line
+// of /proc/stat.
+func parseCPUTimes(data []byte) (uint64, uint64, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 5 || fields[0] != "cpu" {
+			continue
+		}
+
+		var idle, total uint64
+		for i, field := range fields[1:] {
+			val, err := strconv.ParseUint(field, 10, 64)
+			if err != nil {
+				return 0, 0, fmt.Errorf("failed to parse cpu time: %w", err)
+			}
+			// idle and iowait
+			if i == 3 || i == 4 {
+				idle += val
+			}
+			total += val
+		}
+
+		return idle, total, nil
+	}
+
+	return 0, 0, fmt.Errorf("cpu times not found")
+}
+
+// update returns the CPU usage, in percent, since the previous sample. It
+// returns false on the first sample.
+func (s *cpuSampler) update(data []byte) (float64, bool, error) {
+	idle, total, err := parseCPUTimes(data)
+	if err != nil {
+		return 0, false, err
+	}
+
+	defer func() {
+		s.prevIdle = idle
+		s.prevTotal = total
+	}()
+
+	if s.prevTotal == 0 || total <= s.prevTotal {
+		return 0, false, nil
+	}
+
+	idleDiff := float64(idle - min(idle, s.prevIdle))
+	totalDiff := float64(total - s.prevTotal)
+
+	return 100 * (1 - idleDiff/totalDiff), true, nil
+}
+
+// cpuPressureState tracks whether the node is under CPU pressure.
+type cpuPressureState struct {
+	degraded bool
+	count    int
+}
+
+// update returns whether the state changed given the latest CPU usage.
+func (s *cpuPressureState) update(usage, downgradeThreshold, restoreThreshold float64) bool {
+	if (!s.degraded && usage >= downgradeThreshold) || (s.degraded && usage < restoreThreshold) {
+		s.count++
+	} else {
+		s.count = 0
+	}
+
+	if s.count < cpuPressureSamples {
+		return false
+	}
+
+	s.degraded = !s.degraded
+	s.count = 0
+
+	return true
+}
+
+// cpuPressureMonitor samples the node CPU usage and reduces the quality of
+// the calls hosted by the embedded RTC server while it's too high.
+func (p *Plugin) cpuPressureMonitor() {
+	ticker := time.NewTicker(cpuPressureSampleInterval)
+	defer ticker.Stop()
+
+	var sampler cpuSampler
+	var state cpuPressureState
+
+	for {
+		select {
+		case <-ticker.C:
+			cfg := p.getConfiguration()
+			if !cfg.cpuQualityDowngradeEnabled() {
+				if state.degraded {
+					state = cpuPressureState{}
+					p.setCallsQualityDegraded(false)
+				}
+				continue
+			}
+
+			data, err := os.ReadFile("/proc/stat")
+			if err != nil {
+				p.LogError("failed to read cpu stats, quality downgrade is not available", "err", err.Error())
+				return
+			}
+
+			usage, ok, err := sampler.update(data)
+			if err != nil {
+				p.LogError("failed to sample cpu usage", "err", err.Error())
+				continue
+			}
+			if !ok {
+				continue
+			}
+
+			downgradeThreshold, restoreThreshold := cfg.getCPUQualityThresholds()
+			if state.update(usage, downgradeThreshold, restoreThreshold) {
+				if state.degraded {
+					p.LogWarn("node is under cpu pressure, reducing calls quality", "usage", fmt.Sprintf("%.2f", usage))
+				} else {
+					p.LogInfo("node is no longer under cpu pressure, restoring calls quality", "usage", fmt.Sprintf("%.2f", usage))
+				}
+				p.setCallsQualityDegraded(state.degraded)
+			}
+		case <-p.stopCh:
+			return
+		}
+	}
+}
+
+// setCallsQualityDegraded lets participants of the calls hosted by this node
+// know whether they should reduce quality.
+func (p *Plugin) setCallsQualityDegraded(degraded bool) {
+	p.callsQualityDegraded.Store(degraded)
+
+	calls, err := p.store.GetAllActiveCalls(db.GetCallOpts{})
+	if err != nil {
+		p.LogError("failed to get active calls", "err", err.Error())
+		return
+	}
+
+	for _, call := range calls {
+		if call.Props.NodeID != p.nodeID || call.Props.RTCDHost != "" {
+			continue
+		}
+
+		p.publishWebSocketEvent(wsEventCallQualityDegraded, map[string]interface{}{
+			"call_id":    call.ID,
+			"channel_id": call.ChannelID,
+			"degraded":   degraded,
+		}, &WebSocketBroadcast{ChannelID: call.ChannelID, ReliableClusterSend: true})
+	}
+}
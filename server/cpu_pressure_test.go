@@ -0,0 +1,60 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCPUSampler(t *testing.T) {
+	t.Run("invalid", func(t *testing.T) {
+		_, _, err := parseCPUTimes([]byte("intr 1 2 3\n"))
+		require.EqualError(t, err, "cpu times not found")
+
+		_, _, err = parseCPUTimes([]byte("cpu  10 0 10 x 0 0 0 0 0 0\n"))
+		require.Error(t, err)
+	})
+
+	t.Run("usage", func(t *testing.T) {
+		var s cpuSampler
+
+		// user nice system idle iowait irq softirq steal guest guest_nice
+		_, ok, err := s.update([]byte("cpu  100 0 100 700 100 0 0 0 0 0\ncpu0 100 0 100 700 100 0 0 0 0 0\n"))
+		require.NoError(t, err)
+		require.False(t, ok)
+
+		usage, ok, err := s.update([]byte("cpu  160 0 160 760 120 0 0 0 0 0\ncpu0 160 0 160 760 120 0 0 0 0 0\n"))
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.InDelta(t, 60, usage, 0.001)
+	})
+}
+
+func TestCPUPressureState(t *testing.T) {
+	var s cpuPressureState
+
+	// Short spikes are ignored.
+	require.False(t, s.update(90, 85, 70))
+	require.False(t, s.update(90, 85, 70))
+	require.False(t, s.update(50, 85, 70))
+	require.False(t, s.update(90, 85, 70))
+	require.False(t, s.update(90, 85, 70))
+	require.False(t, s.degraded)
+
+	require.True(t, s.update(95, 85, 70))
+	require.True(t, s.degraded)
+
+	// Quality is only restored under the lower threshold.
+	for i := 0; i < cpuPressureSamples*2; i++ {
+		require.False(t, s.update(80, 85, 70))
+	}
+	require.True(t, s.degraded)
+
+	require.False(t, s.update(60, 85, 70))
+	require.False(t, s.update(60, 85, 70))
+	require.True(t, s.update(60, 85, 70))
+	require.False(t, s.degraded)
+}
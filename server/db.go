@@ -5,6 +5,7 @@ package main
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/mattermost/mattermost-plugin-calls/server/db"
 	"github.com/mattermost/mattermost/server/public/shared/driver"
@@ -18,10 +19,32 @@ func (p *Plugin) initDB() error {
 		return fmt.Errorf("server config should not be nil")
 	}
 
-	store, err := db.NewStore(serverCfg.SqlSettings, driver.NewConnector(p.Driver, false), newLogger(p), p.metrics)
-	if err != nil {
+	cfg := p.getConfiguration()
+
+	// The database may be briefly unavailable (e.g. restarting along with the
+	// server) so we give it some time before failing activation.
+	var store *db.Store
+	var err error
+	retries := cfg.getDBConnectRetries()
+	delay := cfg.getDBConnectRetryDelay()
+	for i := 0; ; i++ {
+		store, err = db.NewStore(serverCfg.SqlSettings, driver.NewConnector(p.Driver, false), newLogger(p), p.metrics)
+		if err == nil {
+			break
+		}
 		p.LogError(err.Error())
-		return fmt.Errorf("failed to create db store: %w", err)
+
+		if i >= retries {
+			return fmt.Errorf("failed to create db store: %w", err)
+		}
+
+		p.LogWarn("failed to create db store, retrying", "attempt", fmt.Sprintf("%d", i+1), "delay", delay.String())
+		select {
+		case <-time.After(delay):
+		case <-p.stopCh:
+			return fmt.Errorf("failed to create db store: %w", err)
+		}
+		delay = min(delay*2, maxDBConnectRetryDelaySeconds*time.Second)
 	}
 
 	if err := store.Migrate(models.Up, false); err != nil {
@@ -36,10 +36,7 @@ func (s *Store) CreateCallJob(job *public.CallJob) error {
 		return fmt.Errorf("failed to prepare query: %w", err)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(*s.settings.QueryTimeout)*time.Second)
-	defer cancel()
-	_, err = s.wDB.ExecContext(ctx, q, args...)
-	if err != nil {
+	if err := s.execWrite("CreateCallJob", q, args...); err != nil {
 		return fmt.Errorf("failed to run query: %w", err)
 	}
 
@@ -68,10 +65,7 @@ func (s *Store) UpdateCallJob(job *public.CallJob) error {
 		return fmt.Errorf("failed to prepare query: %w", err)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(*s.settings.QueryTimeout)*time.Second)
-	defer cancel()
-	_, err = s.wDB.ExecContext(ctx, q, args...)
-	if err != nil {
+	if err := s.execWrite("UpdateCallJob", q, args...); err != nil {
 		return fmt.Errorf("failed to run query: %w", err)
 	}
 
@@ -101,7 +95,7 @@ func (s *Store) GetCallJob(id string, opts GetCallJobOpts) (*public.CallJob, err
 	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(*s.settings.QueryTimeout)*time.Second)
 	defer cancel()
 	if err := s.dbXFromGetOpts(opts).GetContext(ctx, &job, q, args...); err == sql.ErrNoRows {
-		return nil, fmt.Errorf("call job not found")
+		return nil, fmt.Errorf("call job %w", ErrNotFound)
 	} else if err != nil {
 		return nil, fmt.Errorf("failed to get call job: %w", err)
 	}
@@ -115,6 +109,54 @@ func (s *Store) GetActiveCallJobs(callID string, opts GetCallJobOpts) (map[publi
 		s.metrics.ObserveStoreMethodsTime("GetActiveCallJobs", time.Since(start).Seconds())
 	}(time.Now())
 
+	jobs, err := s.getActiveCallJobs(callID, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return GetCallJobsByType(jobs), nil
+}
+
+// GetAllActiveCallJobs returns all the active jobs for the given call,
+// including any additional recording jobs.
+func (s *Store) GetAllActiveCallJobs(callID string, opts GetCallJobOpts) ([]*public.CallJob, error) {
+	s.metrics.IncStoreOp("GetAllActiveCallJobs")
+	defer func(start time.Time) {
+		s.metrics.ObserveStoreMethodsTime("GetAllActiveCallJobs", time.Since(start).Seconds())
+	}(time.Now())
+
+	return s.getActiveCallJobs(callID, opts)
+}
+
+// GetCallJobs returns all the jobs for the given call, including the ended
+// ones, ordered by InitAt.
+func (s *Store) GetCallJobs(callID string, opts GetCallJobOpts) ([]*public.CallJob, error) {
+	s.metrics.IncStoreOp("GetCallJobs")
+	defer func(start time.Time) {
+		s.metrics.ObserveStoreMethodsTime("GetCallJobs", time.Since(start).Seconds())
+	}(time.Now())
+
+	qb := getQueryBuilder(s.driverName).Select(callsJobsColumns...).
+		From("calls_jobs").
+		Where(sq.Eq{"CallID": callID}).
+		OrderBy("InitAt ASC, ID")
+
+	q, args, err := qb.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	jobs := []*public.CallJob{}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(*s.settings.QueryTimeout)*time.Second)
+	defer cancel()
+	if err := s.dbXFromGetOpts(opts).SelectContext(ctx, &jobs, q, args...); err != nil {
+		return nil, fmt.Errorf("failed to get call jobs: %w", err)
+	}
+
+	return jobs, nil
+}
+
+func (s *Store) getActiveCallJobs(callID string, opts GetCallJobOpts) ([]*public.CallJob, error) {
 	qb := getQueryBuilder(s.driverName).Select(callsJobsColumns...).
 		From("calls_jobs").
 		Where(sq.And{
@@ -134,17 +176,72 @@ func (s *Store) GetActiveCallJobs(callID string, opts GetCallJobOpts) (map[publi
 		return nil, fmt.Errorf("failed to get call jobs: %w", err)
 	}
 
+	return jobs, nil
+}
+
+// GetCallJobsByType returns one job per type out of the given list, which
+// is expected to be sorted by StartAt (DESC). Additional recording jobs are
+// skipped.
+func GetCallJobsByType(jobs []*public.CallJob) map[public.JobType]*public.CallJob {
 	// We want to return only one job per type. Since we are sorting by
 	// StartAt (DESC) we select the first one we find. It should generally be one
 	// but we want to avoid consistencies issues (e.g. two running jobs for same
 	// type).
 	jobsMap := make(map[public.JobType]*public.CallJob, len(jobs))
 	for _, job := range jobs {
+		if job.Props.PrimaryJobID != "" {
+			continue
+		}
 		_, ok := jobsMap[job.Type]
 		if !ok {
 			jobsMap[job.Type] = job
 		}
 	}
 
-	return jobsMap, nil
+	return jobsMap
+}
+
+// ActiveRecordingJob is a recording job running in an ongoing call.
+type ActiveRecordingJob struct {
+	public.CallJob
+	ChannelID string
+}
+
+// GetActiveRecordingJobs returns all the recording jobs running in ongoing
+// calls, including any additional recording jobs, ordered by InitAt.
+func (s *Store) GetActiveRecordingJobs(opts GetCallJobOpts) ([]ActiveRecordingJob, error) {
+	s.metrics.IncStoreOp("GetActiveRecordingJobs")
+	defer func(start time.Time) {
+		s.metrics.ObserveStoreMethodsTime("GetActiveRecordingJobs", time.Since(start).Seconds())
+	}(time.Now())
+
+	columns := make([]string, 0, len(callsJobsColumns)+1)
+	for _, column := range callsJobsColumns {
+		columns = append(columns, "calls_jobs."+column)
+	}
+	columns = append(columns, "calls.ChannelID")
+
+	qb := getQueryBuilder(s.driverName).Select(columns...).
+		From("calls_jobs").
+		Join("calls ON calls_jobs.CallID = calls.ID").
+		Where(sq.And{
+			sq.Eq{"calls_jobs.Type": public.JobTypeRecording},
+			sq.Eq{"calls_jobs.EndAt": 0},
+			sq.Eq{"calls.EndAt": 0},
+			sq.Eq{"calls.DeleteAt": 0},
+		}).OrderBy("calls_jobs.InitAt ASC, calls_jobs.ID")
+
+	q, args, err := qb.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	jobs := []ActiveRecordingJob{}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(*s.settings.QueryTimeout)*time.Second)
+	defer cancel()
+	if err := s.dbXFromGetOpts(opts).SelectContext(ctx, &jobs, q, args...); err != nil {
+		return nil, fmt.Errorf("failed to get active recording jobs: %w", err)
+	}
+
+	return jobs, nil
 }
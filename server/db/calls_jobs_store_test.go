@@ -20,6 +20,8 @@ func TestCallsJobsStore(t *testing.T) {
 		"TestUpdateCallJob":                testUpdateCallJob,
 		"TestGetCallJob":                   testGetCallJob,
 		"TestGetActiveCallJobs":            testGetActiveCallJobs,
+		"TestGetActiveRecordingJobs":       testGetActiveRecordingJobs,
+		"TestGetCallJobs":                  testGetCallJobs,
 		"TestCallsJobsTableColumnAddition": testCallsJobsTableColumnAddition,
 	})
 }
@@ -228,6 +230,37 @@ func testGetActiveCallJobs(t *testing.T, store *Store) {
 		require.Equal(t, jobs[len(jobs)-1], gotJobs[public.JobTypeRecording])
 	})
 
+	t.Run("additional recording jobs", func(t *testing.T) {
+		callID := model.NewId()
+		recJob := &public.CallJob{
+			ID:        model.NewId(),
+			CallID:    callID,
+			Type:      public.JobTypeRecording,
+			CreatorID: model.NewId(),
+			InitAt:    time.Now().UnixMilli(),
+		}
+		err := store.CreateCallJob(recJob)
+		require.NoError(t, err)
+
+		profileJob := *recJob
+		profileJob.ID = model.NewId()
+		profileJob.StartAt = time.Now().UnixMilli()
+		profileJob.Props.PrimaryJobID = recJob.ID
+		profileJob.Props.Profile = "low"
+		err = store.CreateCallJob(&profileJob)
+		require.NoError(t, err)
+
+		jobs, err := store.GetActiveCallJobs(callID, GetCallJobOpts{})
+		require.NoError(t, err)
+		require.Equal(t, map[public.JobType]*public.CallJob{
+			public.JobTypeRecording: recJob,
+		}, jobs)
+
+		allJobs, err := store.GetAllActiveCallJobs(callID, GetCallJobOpts{})
+		require.NoError(t, err)
+		require.ElementsMatch(t, []*public.CallJob{recJob, &profileJob}, allJobs)
+	})
+
 	t.Run("should never include ended", func(t *testing.T) {
 		callID := model.NewId()
 		job := &public.CallJob{
@@ -261,6 +294,45 @@ func testGetActiveCallJobs(t *testing.T, store *Store) {
 	})
 }
 
+func testGetCallJobs(t *testing.T, store *Store) {
+	t.Run("no jobs", func(t *testing.T) {
+		jobs, err := store.GetCallJobs(model.NewId(), GetCallJobOpts{})
+		require.NoError(t, err)
+		require.Empty(t, jobs)
+	})
+
+	t.Run("includes ended", func(t *testing.T) {
+		callID := model.NewId()
+		initAt := time.Now().UnixMilli()
+
+		recJob := &public.CallJob{
+			ID:        model.NewId(),
+			CallID:    callID,
+			Type:      public.JobTypeRecording,
+			CreatorID: model.NewId(),
+			InitAt:    initAt,
+			StartAt:   initAt + 1000,
+			EndAt:     initAt + 2000,
+		}
+		err := store.CreateCallJob(recJob)
+		require.NoError(t, err)
+
+		trJob := &public.CallJob{
+			ID:        model.NewId(),
+			CallID:    callID,
+			Type:      public.JobTypeTranscribing,
+			CreatorID: model.NewId(),
+			InitAt:    initAt + 3000,
+		}
+		err = store.CreateCallJob(trJob)
+		require.NoError(t, err)
+
+		jobs, err := store.GetCallJobs(callID, GetCallJobOpts{})
+		require.NoError(t, err)
+		require.Equal(t, []*public.CallJob{recJob, trJob}, jobs)
+	})
+}
+
 func testCallsJobsTableColumnAddition(t *testing.T, store *Store) {
 	// This test simulates adding a new column to the calls_jobs table
 	// and verifies that existing code can still fetch data correctly
@@ -320,3 +392,56 @@ func testCallsJobsTableColumnAddition(t *testing.T, store *Store) {
 	_, err = store.wDB.Exec(dropColumnSQL)
 	require.NoError(t, err)
 }
+
+func testGetActiveRecordingJobs(t *testing.T, store *Store) {
+	activeCall := &public.Call{
+		ID:        model.NewId(),
+		CreateAt:  time.Now().UnixMilli(),
+		ChannelID: model.NewId(),
+		StartAt:   time.Now().UnixMilli(),
+		PostID:    model.NewId(),
+		ThreadID:  model.NewId(),
+		OwnerID:   model.NewId(),
+	}
+	err := store.CreateCall(activeCall)
+	require.NoError(t, err)
+
+	endedCall := *activeCall
+	endedCall.ID = model.NewId()
+	endedCall.ChannelID = model.NewId()
+	endedCall.EndAt = time.Now().UnixMilli()
+	err = store.CreateCall(&endedCall)
+	require.NoError(t, err)
+
+	var expected []ActiveRecordingJob
+	for i, job := range []*public.CallJob{
+		{Type: public.JobTypeRecording, CallID: activeCall.ID},
+		{Type: public.JobTypeTranscribing, CallID: activeCall.ID},
+		{Type: public.JobTypeRecording, CallID: activeCall.ID, EndAt: time.Now().UnixMilli()},
+		{Type: public.JobTypeRecording, CallID: activeCall.ID, Props: public.CallJobProps{Profile: "high"}},
+		// Stale job from a call that has ended.
+		{Type: public.JobTypeRecording, CallID: endedCall.ID},
+	} {
+		job.ID = model.NewId()
+		job.CreatorID = model.NewId()
+		job.InitAt = time.Now().UnixMilli() + int64(i)
+		err := store.CreateCallJob(job)
+		require.NoError(t, err)
+
+		if job.Type == public.JobTypeRecording && job.EndAt == 0 && job.CallID == activeCall.ID {
+			expected = append(expected, ActiveRecordingJob{CallJob: *job, ChannelID: activeCall.ChannelID})
+		}
+	}
+
+	jobs, err := store.GetActiveRecordingJobs(GetCallJobOpts{})
+	require.NoError(t, err)
+
+	// Other tests share the store so only the jobs created here are checked.
+	var got []ActiveRecordingJob
+	for _, job := range jobs {
+		if job.CallID == activeCall.ID || job.CallID == endedCall.ID {
+			got = append(got, job)
+		}
+	}
+	require.Equal(t, expected, got)
+}
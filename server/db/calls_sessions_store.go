@@ -36,10 +36,7 @@ func (s *Store) CreateCallSession(session *public.CallSession) error {
 		return fmt.Errorf("failed to prepare query: %w", err)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(*s.settings.QueryTimeout)*time.Second)
-	defer cancel()
-	_, err = s.wDB.ExecContext(ctx, q, args...)
-	if err != nil {
+	if err := s.execWrite("CreateCallSession", q, args...); err != nil {
 		return fmt.Errorf("failed to run query: %w", err)
 	}
 
@@ -67,10 +64,7 @@ func (s *Store) UpdateCallSession(session *public.CallSession) error {
 		return fmt.Errorf("failed to prepare query: %w", err)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(*s.settings.QueryTimeout)*time.Second)
-	defer cancel()
-	_, err = s.wDB.ExecContext(ctx, q, args...)
-	if err != nil {
+	if err := s.execWrite("UpdateCallSession", q, args...); err != nil {
 		return fmt.Errorf("failed to run query: %w", err)
 	}
 
@@ -92,10 +86,7 @@ func (s *Store) DeleteCallSession(id string) error {
 		return fmt.Errorf("failed to prepare query: %w", err)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(*s.settings.QueryTimeout)*time.Second)
-	defer cancel()
-	_, err = s.wDB.ExecContext(ctx, q, args...)
-	if err != nil {
+	if err := s.execWrite("DeleteCallSession", q, args...); err != nil {
 		return fmt.Errorf("failed to run query: %w", err)
 	}
 
@@ -183,10 +174,7 @@ func (s *Store) DeleteCallsSessions(callID string) error {
 		return fmt.Errorf("failed to prepare query: %w", err)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(*s.settings.QueryTimeout)*time.Second)
-	defer cancel()
-	_, err = s.wDB.ExecContext(ctx, q, args...)
-	if err != nil {
+	if err := s.execWrite("DeleteCallsSessions", q, args...); err != nil {
 		return fmt.Errorf("failed to run query: %w", err)
 	}
 
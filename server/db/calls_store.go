@@ -54,10 +54,7 @@ func (s *Store) CreateCall(call *public.Call) error {
 		return fmt.Errorf("failed to prepare query: %w", err)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(*s.settings.QueryTimeout)*time.Second)
-	defer cancel()
-	_, err = s.wDB.ExecContext(ctx, q, args...)
-	if err != nil {
+	if err := s.execWrite("CreateCall", q, args...); err != nil {
 		return fmt.Errorf("failed to run query: %w", err)
 	}
 
@@ -76,6 +73,7 @@ func (s *Store) UpdateCall(call *public.Call) error {
 
 	qb := getQueryBuilder(s.driverName).
 		Update("calls").
+		Set("ChannelID", call.ChannelID).
 		Set("EndAt", call.EndAt).
 		Set("DeleteAt", call.DeleteAt).
 		Set("ThreadID", call.ThreadID).
@@ -90,10 +88,7 @@ func (s *Store) UpdateCall(call *public.Call) error {
 		return fmt.Errorf("failed to prepare query: %w", err)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(*s.settings.QueryTimeout)*time.Second)
-	defer cancel()
-	_, err = s.wDB.ExecContext(ctx, q, args...)
-	if err != nil {
+	if err := s.execWrite("UpdateCall", q, args...); err != nil {
 		return fmt.Errorf("failed to run query: %w", err)
 	}
 
@@ -116,10 +111,7 @@ func (s *Store) DeleteCall(callID string) error {
 		return fmt.Errorf("failed to prepare query: %w", err)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(*s.settings.QueryTimeout)*time.Second)
-	defer cancel()
-	_, err = s.wDB.ExecContext(ctx, q, args...)
-	if err != nil {
+	if err := s.execWrite("DeleteCall", q, args...); err != nil {
 		return fmt.Errorf("failed to run query: %w", err)
 	}
 
@@ -142,10 +134,7 @@ func (s *Store) DeleteCallByChannelID(channelID string) error {
 		return fmt.Errorf("failed to prepare query: %w", err)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(*s.settings.QueryTimeout)*time.Second)
-	defer cancel()
-	_, err = s.wDB.ExecContext(ctx, q, args...)
-	if err != nil {
+	if err := s.execWrite("DeleteCallByChannelID", q, args...); err != nil {
 		return fmt.Errorf("failed to run query: %w", err)
 	}
 
@@ -308,3 +297,60 @@ func (s *Store) GetRTCDHostForCall(callID string, opts GetCallOpts) (string, err
 
 	return rtcdHost, nil
 }
+
+// GetCallsHistory returns the calls that have ended, most recent first,
+// matching the given filters.
+func (s *Store) GetCallsHistory(opts GetCallsHistoryOpts) ([]*public.Call, error) {
+	s.metrics.IncStoreOp("GetCallsHistory")
+	defer func(start time.Time) {
+		s.metrics.ObserveStoreMethodsTime("GetCallsHistory", time.Since(start).Seconds())
+	}(time.Now())
+
+	conds := sq.And{
+		sq.Expr("EndAt > StartAt"),
+		sq.Eq{"DeleteAt": 0},
+	}
+
+	if opts.ChannelID != "" {
+		conds = append(conds, sq.Eq{"ChannelID": opts.ChannelID})
+	}
+
+	if opts.Tag != "" {
+		tagProp := "COALESCE(props->>'tag', '')"
+		if s.driverName == model.DatabaseDriverMysql {
+			tagProp = `COALESCE(Props->>"$.tag", '')`
+		}
+		conds = append(conds, sq.Expr(tagProp+" = ?", opts.Tag))
+	}
+
+	if opts.Since > 0 {
+		conds = append(conds, sq.GtOrEq{"StartAt": opts.Since})
+	}
+
+	if opts.Until > 0 {
+		conds = append(conds, sq.Lt{"StartAt": opts.Until})
+	}
+
+	qb := getQueryBuilder(s.driverName).Select(callsColumns...).
+		From("calls").
+		Where(conds).
+		OrderBy("StartAt DESC, ID")
+
+	if opts.PerPage > 0 {
+		qb = qb.Limit(uint64(opts.PerPage)).Offset(uint64(opts.Page * opts.PerPage))
+	}
+
+	q, args, err := qb.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	calls := []*public.Call{}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(*s.settings.QueryTimeout)*time.Second)
+	defer cancel()
+	if err := s.rDBx.SelectContext(ctx, &calls, q, args...); err != nil {
+		return nil, fmt.Errorf("failed to get calls history: %w", err)
+	}
+
+	return calls, nil
+}
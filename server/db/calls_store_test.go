@@ -26,6 +26,7 @@ func TestCallsStore(t *testing.T) {
 		"TestGetAllActiveCalls":        testGetAllActiveCalls,
 		"TestGetCallActive":            testGetCallActive,
 		"TestCallsTableColumnAddition": testCallsTableColumnAddition,
+		"TestGetCallsHistory":          testGetCallsHistory,
 	})
 }
 
@@ -577,3 +578,63 @@ func testGetAllActiveCalls(t *testing.T, store *Store) {
 		require.ElementsMatch(t, calls, gotCalls)
 	})
 }
+
+func testGetCallsHistory(t *testing.T, store *Store) {
+	t.Run("no calls", func(t *testing.T) {
+		calls, err := store.GetCallsHistory(GetCallsHistoryOpts{})
+		require.NoError(t, err)
+		require.Empty(t, calls)
+	})
+
+	t.Run("filters", func(t *testing.T) {
+		channelID := model.NewId()
+		now := time.Now().UnixMilli()
+
+		newCall := func(startAt int64, tag string, ended bool) *public.Call {
+			call := &public.Call{
+				ID:           model.NewId(),
+				CreateAt:     startAt,
+				ChannelID:    channelID,
+				StartAt:      startAt,
+				PostID:       model.NewId(),
+				ThreadID:     model.NewId(),
+				OwnerID:      model.NewId(),
+				Participants: []string{model.NewId()},
+				Props: public.CallProps{
+					Tag: tag,
+				},
+			}
+			if ended {
+				call.EndAt = startAt + 60000
+			}
+			require.NoError(t, store.CreateCall(call))
+			return call
+		}
+
+		standup := newCall(now-3000, "standup", true)
+		interview := newCall(now-2000, "interview", true)
+		untagged := newCall(now-1000, "", true)
+		// Ongoing calls are not part of the history.
+		newCall(now, "interview", false)
+
+		calls, err := store.GetCallsHistory(GetCallsHistoryOpts{ChannelID: channelID})
+		require.NoError(t, err)
+		require.Equal(t, []*public.Call{untagged, interview, standup}, calls)
+
+		calls, err = store.GetCallsHistory(GetCallsHistoryOpts{ChannelID: channelID, Tag: "interview"})
+		require.NoError(t, err)
+		require.Equal(t, []*public.Call{interview}, calls)
+
+		calls, err = store.GetCallsHistory(GetCallsHistoryOpts{ChannelID: channelID, Tag: "incident"})
+		require.NoError(t, err)
+		require.Empty(t, calls)
+
+		calls, err = store.GetCallsHistory(GetCallsHistoryOpts{ChannelID: channelID, Since: now - 2000, Until: now})
+		require.NoError(t, err)
+		require.Equal(t, []*public.Call{untagged, interview}, calls)
+
+		calls, err = store.GetCallsHistory(GetCallsHistoryOpts{ChannelID: channelID, Page: 1, PerPage: 2})
+		require.NoError(t, err)
+		require.Equal(t, []*public.Call{standup}, calls)
+	})
+}
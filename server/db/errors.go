@@ -0,0 +1,88 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+)
+
+const (
+	ErrorTypeTransient = "transient"
+	ErrorTypePermanent = "permanent"
+)
+
+// IsTransientError returns whether the given error is likely caused by a
+// temporary database failure (e.g. a connection loss or a deadlock) so that
+// the operation can be attempted again.
+func IsTransientError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, sql.ErrConnDone) || errors.Is(err, mysql.ErrInvalidConn) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code.Class() {
+		// connection_exception, transaction_rollback, insufficient_resources, operator_intervention
+		case "08", "40", "53", "57":
+			return true
+		}
+		return false
+	}
+
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		switch mysqlErr.Number {
+		// ER_CON_COUNT_ERROR, ER_LOCK_WAIT_TIMEOUT, ER_LOCK_DEADLOCK
+		case 1040, 1205, 1213:
+			return true
+		}
+		return false
+	}
+
+	// Query timeouts and connection errors not wrapped by the driver.
+	return errors.Is(err, context.DeadlineExceeded) ||
+		strings.Contains(err.Error(), "connection refused") ||
+		strings.Contains(err.Error(), "connection reset by peer")
+}
+
+// incWriteErrors tracks a failed write by the type of error.
+func (s *Store) incWriteErrors(method string, err error) {
+	if IsTransientError(err) {
+		s.metrics.IncStoreErrors(method, ErrorTypeTransient)
+		return
+	}
+	s.metrics.IncStoreErrors(method, ErrorTypePermanent)
+}
+
+// execWrite runs the given query against the writer DB. Writes failing with
+// transient errors are not retried here as they are mostly made while holding
+// the call lock, which we don't want to hold any longer than necessary.
+// Callers can buffer them to be saved later instead.
+func (s *Store) execWrite(method, q string, args ...any) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(*s.settings.QueryTimeout)*time.Second)
+	defer cancel()
+	_, err := s.wDB.ExecContext(ctx, q, args...)
+	if err != nil {
+		s.incWriteErrors(method, err)
+	}
+	return err
+}
@@ -0,0 +1,45 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package db
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+
+	"github.com/stretchr/testify/require"
+
+	serverMocks "github.com/mattermost/mattermost-plugin-calls/server/mocks/github.com/mattermost/mattermost-plugin-calls/server/interfaces"
+)
+
+func TestIsTransientError(t *testing.T) {
+	require.False(t, IsTransientError(nil))
+	require.False(t, IsTransientError(fmt.Errorf("invalid call")))
+	require.False(t, IsTransientError(&pq.Error{Code: "23505"}))
+	require.False(t, IsTransientError(&mysql.MySQLError{Number: 1062}))
+
+	require.True(t, IsTransientError(fmt.Errorf("failed: %w", driver.ErrBadConn)))
+	require.True(t, IsTransientError(fmt.Errorf("failed: %w", &pq.Error{Code: "08006"})))
+	require.True(t, IsTransientError(&pq.Error{Code: "40P01"}))
+	require.True(t, IsTransientError(&mysql.MySQLError{Number: 1213}))
+	require.True(t, IsTransientError(fmt.Errorf("dial tcp 127.0.0.1:5432: connect: connection refused")))
+}
+
+func TestIncWriteErrors(t *testing.T) {
+	mockMetrics := &serverMocks.MockMetrics{}
+	defer mockMetrics.AssertExpectations(t)
+
+	s := &Store{
+		metrics: mockMetrics,
+	}
+
+	mockMetrics.On("IncStoreErrors", "UpdateCall", ErrorTypePermanent).Once()
+	s.incWriteErrors("UpdateCall", fmt.Errorf("invalid"))
+
+	mockMetrics.On("IncStoreErrors", "UpdateCall", ErrorTypeTransient).Once()
+	s.incWriteErrors("UpdateCall", fmt.Errorf("failed to run query: %w", driver.ErrBadConn))
+}
@@ -141,6 +141,7 @@ func newPostgresStore(t *testing.T, binaryParams bool) (*Store, func()) {
 	})
 	mockMetrics.On("IncStoreOp", mock.AnythingOfType("string"))
 	mockMetrics.On("ObserveStoreMethodsTime", mock.AnythingOfType("string"), mock.AnythingOfType("float64"))
+	mockMetrics.On("IncStoreErrors", mock.AnythingOfType("string"), mock.AnythingOfType("string"))
 
 	mockLogger.On("Debug", "db opened", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Once()
 
@@ -183,6 +184,7 @@ func newMySQLStore(t *testing.T) (*Store, func()) {
 	})
 	mockMetrics.On("IncStoreOp", mock.AnythingOfType("string"))
 	mockMetrics.On("ObserveStoreMethodsTime", mock.AnythingOfType("string"), mock.AnythingOfType("float64"))
+	mockMetrics.On("IncStoreErrors", mock.AnythingOfType("string"), mock.AnythingOfType("string"))
 
 	mockLogger.On("Debug", "db opened", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Times(5)
 
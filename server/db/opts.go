@@ -42,6 +42,18 @@ func (o GetCallJobOpts) UseWriter() bool {
 	return o.FromWriter
 }
 
+// GetCallsHistoryOpts are the filters applied when fetching ended calls.
+// Zero values mean no filtering.
+type GetCallsHistoryOpts struct {
+	ChannelID string
+	Tag       string
+	// Since and Until bound the start time of the calls (in milliseconds).
+	Since   int64
+	Until   int64
+	Page    int
+	PerPage int
+}
+
 type getOpts interface {
 	UseWriter() bool
 }
@@ -59,3 +71,12 @@ func (s *Store) dbFromGetOpts(opts getOpts) *sql.DB {
 	}
 	return s.rDB
 }
+
+// RecordingsStorageOpts are the filters applied when computing the storage
+// used by call recordings. Zero values mean no filtering.
+type RecordingsStorageOpts struct {
+	ChannelID string
+	TeamID    string
+	// Limit caps the number of recordings returned by GetOldestRecordings.
+	Limit int
+}
@@ -0,0 +1,106 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	sq "github.com/mattermost/squirrel"
+)
+
+// callRecordingPostType is the type of the posts the recording files are
+// attached to.
+const callRecordingPostType = "custom_calls_recording"
+
+// RecordingFile is a call recording file along with the post it's attached
+// to.
+type RecordingFile struct {
+	FileID    string
+	PostID    string
+	ChannelID string
+	Size      int64
+	CreateAt  int64
+}
+
+func (s *Store) recordingFilesQuery(columns []string, opts RecordingsStorageOpts) sq.SelectBuilder {
+	qb := getQueryBuilder(s.driverName).Select(columns...).
+		From("FileInfo").
+		Join("Posts ON Posts.Id = FileInfo.PostId").
+		Where(sq.Eq{
+			"Posts.Type":        callRecordingPostType,
+			"Posts.DeleteAt":    0,
+			"FileInfo.DeleteAt": 0,
+		})
+
+	if opts.ChannelID != "" {
+		qb = qb.Where(sq.Eq{"Posts.ChannelId": opts.ChannelID})
+	}
+
+	if opts.TeamID != "" {
+		qb = qb.Join("Channels ON Channels.Id = Posts.ChannelId").
+			Where(sq.Eq{"Channels.TeamId": opts.TeamID})
+	}
+
+	return qb
+}
+
+// GetRecordingsStorageSize returns the total size (in bytes) of the call
+// recordings matching the given filters.
+func (s *Store) GetRecordingsStorageSize(opts RecordingsStorageOpts) (int64, error) {
+	s.metrics.IncStoreOp("GetRecordingsStorageSize")
+	defer func(start time.Time) {
+		s.metrics.ObserveStoreMethodsTime("GetRecordingsStorageSize", time.Since(start).Seconds())
+	}(time.Now())
+
+	q, args, err := s.recordingFilesQuery([]string{"COALESCE(SUM(FileInfo.Size), 0)"}, opts).ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	var size int64
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(*s.settings.QueryTimeout)*time.Second)
+	defer cancel()
+	if err := s.rDBx.GetContext(ctx, &size, q, args...); err != nil {
+		return 0, fmt.Errorf("failed to get recordings storage size: %w", err)
+	}
+
+	return size, nil
+}
+
+// GetOldestRecordings returns the call recordings matching the given
+// filters, oldest first.
+func (s *Store) GetOldestRecordings(opts RecordingsStorageOpts) ([]RecordingFile, error) {
+	s.metrics.IncStoreOp("GetOldestRecordings")
+	defer func(start time.Time) {
+		s.metrics.ObserveStoreMethodsTime("GetOldestRecordings", time.Since(start).Seconds())
+	}(time.Now())
+
+	qb := s.recordingFilesQuery([]string{
+		"FileInfo.Id AS FileID",
+		"FileInfo.PostId AS PostID",
+		"Posts.ChannelId AS ChannelID",
+		"FileInfo.Size AS Size",
+		"FileInfo.CreateAt AS CreateAt",
+	}, opts).OrderBy("FileInfo.CreateAt ASC, FileInfo.Id")
+
+	if opts.Limit > 0 {
+		qb = qb.Limit(uint64(opts.Limit))
+	}
+
+	q, args, err := qb.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	recordings := []RecordingFile{}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(*s.settings.QueryTimeout)*time.Second)
+	defer cancel()
+	if err := s.rDBx.SelectContext(ctx, &recordings, q, args...); err != nil {
+		return nil, fmt.Errorf("failed to get oldest recordings: %w", err)
+	}
+
+	return recordings, nil
+}
@@ -149,6 +149,46 @@ func (s *Store) GetTotalCalls(active bool) (int64, error) {
 	return count, nil
 }
 
+// GetTotalActiveCallJobs returns the number of jobs (e.g. recordings)
+// currently running in active calls, by job type.
+func (s *Store) GetTotalActiveCallJobs() (map[string]int64, error) {
+	s.metrics.IncStoreOp("GetTotalActiveCallJobs")
+	defer func(start time.Time) {
+		s.metrics.ObserveStoreMethodsTime("GetTotalActiveCallJobs", time.Since(start).Seconds())
+	}(time.Now())
+
+	qb := getQueryBuilder(s.driverName).Select("COUNT(*) AS Count, calls_jobs.Type AS Type").
+		From("calls_jobs").
+		Join("calls ON calls_jobs.CallID = calls.ID").
+		Where(sq.And{
+			sq.Eq{"calls_jobs.EndAt": 0},
+			sq.Eq{"calls.EndAt": 0},
+			sq.Eq{"calls.DeleteAt": 0},
+		}).GroupBy("calls_jobs.Type")
+
+	q, args, err := qb.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare query: %w", err)
+	}
+
+	var rows []struct {
+		Type  string
+		Count int64
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(*s.settings.QueryTimeout)*time.Second)
+	defer cancel()
+	if err := s.rDBx.SelectContext(ctx, &rows, q, args...); err != nil {
+		return nil, fmt.Errorf("failed to run query: %w", err)
+	}
+
+	m := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		m[row.Type] = row.Count
+	}
+
+	return m, nil
+}
+
 func (s *Store) GetCallsByChannelType() (map[string]int64, error) {
 	s.metrics.IncStoreOp("GetCallsByChannelType")
 	defer func(start time.Time) {
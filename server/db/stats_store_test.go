@@ -16,7 +16,60 @@ import (
 
 func TestStatsStore(t *testing.T) {
 	testStore(t, map[string]func(t *testing.T, store *Store){
-		"TestGetStats": testGetStats,
+		"TestGetStats":               testGetStats,
+		"TestGetTotalActiveCallJobs": testGetTotalActiveCallJobs,
+	})
+}
+
+func testGetTotalActiveCallJobs(t *testing.T, store *Store) {
+	t.Run("empty", func(t *testing.T) {
+		jobs, err := store.GetTotalActiveCallJobs()
+		require.NoError(t, err)
+		require.Empty(t, jobs)
+	})
+
+	t.Run("active jobs", func(t *testing.T) {
+		defer resetStore(t, store)
+
+		activeCall := &public.Call{
+			ID:        model.NewId(),
+			CreateAt:  time.Now().UnixMilli(),
+			ChannelID: model.NewId(),
+			StartAt:   time.Now().UnixMilli(),
+			PostID:    model.NewId(),
+			ThreadID:  model.NewId(),
+			OwnerID:   model.NewId(),
+		}
+		err := store.CreateCall(activeCall)
+		require.NoError(t, err)
+
+		endedCall := *activeCall
+		endedCall.ID = model.NewId()
+		endedCall.ChannelID = model.NewId()
+		endedCall.EndAt = time.Now().UnixMilli()
+		err = store.CreateCall(&endedCall)
+		require.NoError(t, err)
+
+		for _, job := range []*public.CallJob{
+			{Type: public.JobTypeRecording, CallID: activeCall.ID},
+			{Type: public.JobTypeTranscribing, CallID: activeCall.ID},
+			{Type: public.JobTypeRecording, CallID: activeCall.ID, EndAt: time.Now().UnixMilli()},
+			// Stale job from a call that has ended.
+			{Type: public.JobTypeRecording, CallID: endedCall.ID},
+		} {
+			job.ID = model.NewId()
+			job.CreatorID = model.NewId()
+			job.InitAt = time.Now().UnixMilli()
+			err := store.CreateCallJob(job)
+			require.NoError(t, err)
+		}
+
+		jobs, err := store.GetTotalActiveCallJobs()
+		require.NoError(t, err)
+		require.Equal(t, map[string]int64{
+			string(public.JobTypeRecording):    1,
+			string(public.JobTypeTranscribing): 1,
+		}, jobs)
 	})
 }
 
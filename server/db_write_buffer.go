@@ -0,0 +1,244 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-calls/server/db"
+	"github.com/mattermost/mattermost-plugin-calls/server/public"
+)
+
+const dbWriteBufferMaxSize = 1000
+
+var dbWriteBufferFlushInterval = 10 * time.Second
+
+// bufferedDBWrite is a store write that failed because the database was
+// unavailable, waiting to be attempted again.
+type bufferedDBWrite struct {
+	method string
+	// callID is the call the record belongs to, so that its pending writes can
+	// be saved before its state is read again.
+	callID string
+	write  func() error
+	// seq tells buffered writes apart so that the flusher can detect a newer
+	// write for the same record being buffered while it was saving.
+	seq uint64
+}
+
+// updateCallBuffered saves the given call. If the database is unavailable
+// the update is buffered to be saved once it recovers, so that the call
+// history isn't lost.
+func (p *Plugin) updateCallBuffered(call *public.Call) error {
+	return bufferedStoreWrite(p, call.ID, "call_"+call.ID, "UpdateCall", call, p.store.UpdateCall)
+}
+
+// updateCallJobBuffered saves the given job. If the database is unavailable
+// the update is buffered to be saved once it recovers, so that recording
+// records aren't lost.
+func (p *Plugin) updateCallJobBuffered(job *public.CallJob) error {
+	return bufferedStoreWrite(p, job.CallID, "job_"+job.ID, "UpdateCallJob", job, p.store.UpdateCallJob)
+}
+
+// bufferedStoreWrite saves v through write, buffering it if the database is
+// unavailable. Writes for the same record are expected to be serialized by the
+// caller (i.e. made while holding the call lock).
+func bufferedStoreWrite[T any](p *Plugin, callID, key, method string, v *T, write func(*T) error) error {
+	if !p.getConfiguration().dbWriteBufferEnabled() {
+		return write(v)
+	}
+
+	// An older write for the same record is still pending, or being saved by
+	// the flusher. Writing directly could have it land after this one so the
+	// newer value replaces it in the buffer instead.
+	if p.hasBufferedDBWrite(key) {
+		w, err := newBufferedDBWrite(callID, method, v, write)
+		if err != nil {
+			return err
+		}
+		// Falling back to a direct write if the buffer was flushed in the
+		// meantime and is now full.
+		if p.bufferDBWrite(key, w) {
+			return nil
+		}
+	}
+
+	err := write(v)
+	if err == nil || !db.IsTransientError(err) {
+		return err
+	}
+
+	w, bufErr := newBufferedDBWrite(callID, method, v, write)
+	if bufErr != nil {
+		return bufErr
+	}
+	if !p.bufferDBWrite(key, w) {
+		return err
+	}
+
+	p.LogWarn("database unavailable, write buffered", "method", method, "key", key, "err", err.Error())
+
+	return nil
+}
+
+func newBufferedDBWrite[T any](callID, method string, v *T, write func(*T) error) (bufferedDBWrite, error) {
+	// Callers may keep modifying the value so we need a copy of its current
+	// state.
+	data, err := json.Marshal(v)
+	if err != nil {
+		return bufferedDBWrite{}, fmt.Errorf("failed to marshal value: %w", err)
+	}
+	var cp T
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return bufferedDBWrite{}, fmt.Errorf("failed to unmarshal value: %w", err)
+	}
+
+	return bufferedDBWrite{
+		method: method,
+		callID: callID,
+		write:  func() error { return write(&cp) },
+	}, nil
+}
+
+func (p *Plugin) hasBufferedDBWrite(key string) bool {
+	p.dbWriteBufferMut.Lock()
+	defer p.dbWriteBufferMut.Unlock()
+	_, ok := p.dbWriteBuffer[key]
+	return ok
+}
+
+func (p *Plugin) bufferDBWrite(key string, w bufferedDBWrite) bool {
+	p.dbWriteBufferMut.Lock()
+	defer p.dbWriteBufferMut.Unlock()
+
+	if _, ok := p.dbWriteBuffer[key]; !ok && len(p.dbWriteBuffer) >= dbWriteBufferMaxSize {
+		p.LogError("db write buffer is full, dropping write", "method", w.method, "key", key)
+		return false
+	}
+
+	// Only the latest write for a given record needs saving.
+	p.dbWriteBufferSeq++
+	w.seq = p.dbWriteBufferSeq
+	p.dbWriteBuffer[key] = w
+	p.metrics.SetStoreBufferedWrites(len(p.dbWriteBuffer))
+
+	return true
+}
+
+// flushDBWriteBuffer attempts the buffered writes again. The lock isn't held
+// while writing so that callers are never blocked by a slow database. Instead,
+// writes stay in the buffer until saved so that any newer write for the same
+// record gets buffered rather than racing with them.
+func (p *Plugin) flushDBWriteBuffer() {
+	p.dbWriteBufferMut.Lock()
+	keys := mapKeys(p.dbWriteBuffer)
+	p.dbWriteBufferMut.Unlock()
+
+	for _, key := range keys {
+		if err := p.flushDBWrite(key); err != nil && db.IsTransientError(err) {
+			// Still unavailable, no point in trying the rest.
+			return
+		}
+	}
+}
+
+// flushCallDBWrites saves the writes buffered for the given call. It's meant
+// to be called under lock (on the call's channel) before reading the call's
+// state so that a pending write doesn't later replace newer ones made
+// directly.
+func (p *Plugin) flushCallDBWrites(callID string) error {
+	p.dbWriteBufferMut.Lock()
+	var keys []string
+	for key, w := range p.dbWriteBuffer {
+		if w.callID == callID {
+			keys = append(keys, key)
+		}
+	}
+	p.dbWriteBufferMut.Unlock()
+
+	for _, key := range keys {
+		if err := p.flushDBWrite(key); err != nil && db.IsTransientError(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// hasCallDBWrites returns whether any write is buffered for the given call.
+func (p *Plugin) hasCallDBWrites(callID string) bool {
+	p.dbWriteBufferMut.Lock()
+	defer p.dbWriteBufferMut.Unlock()
+	for _, w := range p.dbWriteBuffer {
+		if w.callID == callID {
+			return true
+		}
+	}
+	return false
+}
+
+// flushDBWrite attempts the buffered write for the given key again. Saving is
+// serialized so that a write being saved by the flusher can't land after a
+// newer one made once flushCallDBWrites returns.
+func (p *Plugin) flushDBWrite(key string) error {
+	p.dbWriteFlushMut.Lock()
+	defer p.dbWriteFlushMut.Unlock()
+
+	p.dbWriteBufferMut.Lock()
+	w, ok := p.dbWriteBuffer[key]
+	p.dbWriteBufferMut.Unlock()
+	if !ok {
+		return nil
+	}
+
+	if err := w.write(); err != nil {
+		if db.IsTransientError(err) {
+			return err
+		}
+		p.LogError("failed to save buffered write", "method", w.method, "key", key, "err", err.Error())
+	}
+
+	p.dbWriteBufferMut.Lock()
+	// A newer write buffered in the meantime is left for the next flush.
+	if cur, ok := p.dbWriteBuffer[key]; ok && cur.seq == w.seq {
+		delete(p.dbWriteBuffer, key)
+		p.metrics.SetStoreBufferedWrites(len(p.dbWriteBuffer))
+	}
+	p.dbWriteBufferMut.Unlock()
+
+	return nil
+}
+
+func (p *Plugin) dbWriteBufferFlusher() {
+	defer p.dbWriteBufferFlusherWg.Done()
+
+	ticker := time.NewTicker(dbWriteBufferFlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			p.flushDBWriteBuffer()
+		case <-p.stopCh:
+			return
+		}
+	}
+}
+
+// stopDBWriteBuffer waits for the flusher to exit and attempts the buffered
+// writes one last time. It's meant to be called right before closing the
+// store, once nothing else can buffer writes.
+func (p *Plugin) stopDBWriteBuffer() {
+	p.dbWriteBufferFlusherWg.Wait()
+
+	p.flushDBWriteBuffer()
+
+	p.dbWriteBufferMut.Lock()
+	defer p.dbWriteBufferMut.Unlock()
+	if n := len(p.dbWriteBuffer); n > 0 {
+		p.LogError("plugin stopping, dropping buffered db writes", "count", fmt.Sprintf("%d", n))
+	}
+}
@@ -0,0 +1,213 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"testing"
+
+	"github.com/mattermost/mattermost-plugin-calls/server/public"
+
+	serverMocks "github.com/mattermost/mattermost-plugin-calls/server/mocks/github.com/mattermost/mattermost-plugin-calls/server/interfaces"
+	pluginMocks "github.com/mattermost/mattermost-plugin-calls/server/mocks/github.com/mattermost/mattermost/server/public/plugin"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBufferedStoreWrite(t *testing.T) {
+	mockAPI := &pluginMocks.MockAPI{}
+	mockMetrics := &serverMocks.MockMetrics{}
+
+	defer mockAPI.AssertExpectations(t)
+	defer mockMetrics.AssertExpectations(t)
+
+	cfg := &configuration{}
+	cfg.SetDefaults()
+	cfg.EnableDBWriteBuffer = model.NewPointer(true)
+
+	p := &Plugin{
+		MattermostPlugin: plugin.MattermostPlugin{
+			API: mockAPI,
+		},
+		metrics:       mockMetrics,
+		configuration: cfg,
+		dbWriteBuffer: map[string]bufferedDBWrite{},
+	}
+
+	call := &public.Call{ID: model.NewId()}
+	key := "call_" + call.ID
+
+	var saved []public.Call
+	var onWrite func()
+	dbErr := driver.ErrBadConn
+	write := func(c *public.Call) error {
+		if dbErr != nil {
+			return dbErr
+		}
+		if onWrite != nil {
+			onWrite()
+		}
+		saved = append(saved, *c)
+		return nil
+	}
+
+	t.Run("permanent error", func(t *testing.T) {
+		err := bufferedStoreWrite(p, call.ID, key, "UpdateCall", call, func(_ *public.Call) error {
+			return fmt.Errorf("invalid call")
+		})
+		require.EqualError(t, err, "invalid call")
+		require.Empty(t, p.dbWriteBuffer)
+	})
+
+	t.Run("buffered", func(t *testing.T) {
+		mockMetrics.On("SetStoreBufferedWrites", 1).Twice()
+		mockAPI.On("LogWarn", "database unavailable, write buffered",
+			"origin", mock.Anything, "method", "UpdateCall", "key", key, "err", dbErr.Error()).Once()
+
+		call.EndAt = 100
+		require.NoError(t, bufferedStoreWrite(p, call.ID, key, "UpdateCall", call, write))
+		// Buffered straight away as an older write is pending.
+		call.EndAt = 200
+		require.NoError(t, bufferedStoreWrite(p, call.ID, key, "UpdateCall", call, write))
+		require.Len(t, p.dbWriteBuffer, 1)
+
+		// Changes after buffering are not saved.
+		call.EndAt = 300
+
+		// Still unavailable.
+		p.flushDBWriteBuffer()
+		require.Len(t, p.dbWriteBuffer, 1)
+		require.Empty(t, saved)
+
+		mockMetrics.On("SetStoreBufferedWrites", 0).Once()
+		dbErr = nil
+		p.flushDBWriteBuffer()
+		require.Empty(t, p.dbWriteBuffer)
+		require.Len(t, saved, 1)
+		require.Equal(t, int64(200), saved[0].EndAt)
+	})
+
+	t.Run("direct write", func(t *testing.T) {
+		saved = nil
+		call.EndAt = 300
+		require.NoError(t, bufferedStoreWrite(p, call.ID, key, "UpdateCall", call, write))
+		require.Empty(t, p.dbWriteBuffer)
+		require.Len(t, saved, 1)
+		require.Equal(t, int64(300), saved[0].EndAt)
+	})
+
+	t.Run("newer write while flushing", func(t *testing.T) {
+		saved = nil
+		dbErr = driver.ErrBadConn
+
+		mockMetrics.On("SetStoreBufferedWrites", 1).Twice()
+		mockAPI.On("LogWarn", "database unavailable, write buffered",
+			"origin", mock.Anything, "method", "UpdateCall", "key", key, "err", dbErr.Error()).Once()
+		call.EndAt = 400
+		require.NoError(t, bufferedStoreWrite(p, call.ID, key, "UpdateCall", call, write))
+
+		// The database recovers and a newer write comes in while the older
+		// one is being saved.
+		dbErr = nil
+		onWrite = func() {
+			onWrite = nil
+			call.EndAt = 500
+			require.NoError(t, bufferedStoreWrite(p, call.ID, key, "UpdateCall", call, write))
+		}
+		p.flushDBWriteBuffer()
+		require.Len(t, p.dbWriteBuffer, 1)
+		require.Len(t, saved, 1)
+		require.Equal(t, int64(400), saved[0].EndAt)
+
+		mockMetrics.On("SetStoreBufferedWrites", 0).Once()
+		p.flushDBWriteBuffer()
+		require.Empty(t, p.dbWriteBuffer)
+		require.Len(t, saved, 2)
+		require.Equal(t, int64(500), saved[1].EndAt)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		cfg.EnableDBWriteBuffer = model.NewPointer(false)
+		defer func() {
+			cfg.EnableDBWriteBuffer = model.NewPointer(true)
+		}()
+
+		dbErr = driver.ErrBadConn
+		err := bufferedStoreWrite(p, call.ID, key, "UpdateCall", call, write)
+		require.ErrorIs(t, err, driver.ErrBadConn)
+		require.Empty(t, p.dbWriteBuffer)
+	})
+}
+
+func TestFlushCallDBWrites(t *testing.T) {
+	mockAPI := &pluginMocks.MockAPI{}
+	mockMetrics := &serverMocks.MockMetrics{}
+
+	defer mockAPI.AssertExpectations(t)
+	defer mockMetrics.AssertExpectations(t)
+
+	cfg := &configuration{}
+	cfg.SetDefaults()
+	cfg.EnableDBWriteBuffer = model.NewPointer(true)
+
+	p := &Plugin{
+		MattermostPlugin: plugin.MattermostPlugin{
+			API: mockAPI,
+		},
+		metrics:       mockMetrics,
+		configuration: cfg,
+		dbWriteBuffer: map[string]bufferedDBWrite{},
+		stopCh:        make(chan struct{}),
+	}
+
+	callA := &public.Call{ID: model.NewId()}
+	callB := &public.Call{ID: model.NewId()}
+
+	var saved []string
+	dbErr := driver.ErrBadConn
+	write := func(c *public.Call) error {
+		if dbErr != nil {
+			return dbErr
+		}
+		saved = append(saved, c.ID)
+		return nil
+	}
+
+	mockMetrics.On("SetStoreBufferedWrites", mock.AnythingOfType("int"))
+	mockAPI.On("LogWarn", "database unavailable, write buffered",
+		"origin", mock.Anything, "method", "UpdateCall", "key", mock.Anything, "err", dbErr.Error()).Twice()
+
+	require.NoError(t, bufferedStoreWrite(p, callA.ID, "call_"+callA.ID, "UpdateCall", callA, write))
+	require.NoError(t, bufferedStoreWrite(p, callB.ID, "call_"+callB.ID, "UpdateCall", callB, write))
+	require.True(t, p.hasCallDBWrites(callA.ID))
+	require.False(t, p.hasCallDBWrites(model.NewId()))
+
+	t.Run("still unavailable", func(t *testing.T) {
+		require.ErrorIs(t, p.flushCallDBWrites(callA.ID), driver.ErrBadConn)
+		require.Len(t, p.dbWriteBuffer, 2)
+	})
+
+	t.Run("only the given call", func(t *testing.T) {
+		dbErr = nil
+		require.NoError(t, p.flushCallDBWrites(callA.ID))
+		require.Equal(t, []string{callA.ID}, saved)
+		require.False(t, p.hasCallDBWrites(callA.ID))
+		require.True(t, p.hasCallDBWrites(callB.ID))
+	})
+
+	t.Run("stop", func(t *testing.T) {
+		p.dbWriteBufferFlusherWg.Add(1)
+		go p.dbWriteBufferFlusher()
+		close(p.stopCh)
+
+		p.stopDBWriteBuffer()
+		require.Equal(t, []string{callA.ID, callB.ID}, saved)
+		require.Empty(t, p.dbWriteBuffer)
+	})
+}
@@ -47,6 +47,12 @@ func (e *LicenseChecker) RecordingsAllowed() bool {
 	return e.isAtLeastEnterpriseLicensed()
 }
 
+// RecordingWatermarkAllowed returns true if the license allows burning
+// a watermark onto call recordings.
+func (e *LicenseChecker) RecordingWatermarkAllowed() bool {
+	return e.isAtLeastEnterpriseLicensed()
+}
+
 // RecordingsAllowed returns true if the license allows use of
 // the call transcriptions functionality.
 func (e *LicenseChecker) TranscriptionsAllowed() bool {
@@ -0,0 +1,157 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/mattermost/mattermost-plugin-calls/server/db"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+const focusModeKeyPrefix = "focus_mode_"
+
+// focusModeState is stored while focus mode is active for a user so that
+// their previous status can be restored once they leave the call, even if
+// that happens on a different node or after a restart.
+type focusModeState struct {
+	CallID     string `json:"call_id"`
+	PrevStatus string `json:"prev_status"`
+}
+
+func (p *Plugin) getFocusModeState(userID string) (*focusModeState, error) {
+	data, appErr := p.API.KVGet(focusModeKeyPrefix + userID)
+	if appErr != nil {
+		return nil, fmt.Errorf("failed to get focus mode state: %w", appErr)
+	}
+	if data == nil {
+		return nil, nil
+	}
+
+	var state focusModeState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal focus mode state: %w", err)
+	}
+
+	return &state, nil
+}
+
+// enableFocusMode switches the user's status to Do Not Disturb when joining
+// a call, if they opted in. Users already in Do Not Disturb are left alone.
+func (p *Plugin) enableFocusMode(userID, callID string) error {
+	prefs, err := p.getCallNotificationPreferences(userID)
+	if err != nil {
+		return err
+	}
+	if !prefs.FocusMode {
+		return nil
+	}
+
+	state, err := p.getFocusModeState(userID)
+	if err != nil {
+		return err
+	}
+
+	// Already active (e.g. joined from another device or moved to a
+	// different call before the previous leave was processed).
+	if state != nil {
+		if state.CallID == callID {
+			return nil
+		}
+		state.CallID = callID
+		data, err := json.Marshal(state)
+		if err != nil {
+			return fmt.Errorf("failed to marshal focus mode state: %w", err)
+		}
+		if appErr := p.API.KVSet(focusModeKeyPrefix+userID, data); appErr != nil {
+			return fmt.Errorf("failed to set focus mode state: %w", appErr)
+		}
+		return nil
+	}
+
+	status, appErr := p.API.GetUserStatus(userID)
+	if appErr != nil {
+		return fmt.Errorf("failed to get user status: %w", appErr)
+	}
+	if status.Status == model.StatusDnd {
+		return nil
+	}
+
+	data, err := json.Marshal(focusModeState{
+		CallID:     callID,
+		PrevStatus: status.Status,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal focus mode state: %w", err)
+	}
+
+	// Only one node gets to store the previous status.
+	ok, appErr := p.API.KVSetWithOptions(focusModeKeyPrefix+userID, data, model.PluginKVSetOptions{
+		Atomic:   true,
+		OldValue: nil,
+	})
+	if appErr != nil {
+		return fmt.Errorf("failed to set focus mode state: %w", appErr)
+	}
+	if !ok {
+		return nil
+	}
+
+	if _, appErr := p.API.UpdateUserStatus(userID, model.StatusDnd); appErr != nil {
+		return fmt.Errorf("failed to update user status: %w", appErr)
+	}
+
+	p.LogDebug("focus mode enabled", "userID", userID, "callID", callID)
+
+	// The user may have left while this was in progress.
+	return p.disableFocusMode(userID, callID)
+}
+
+// disableFocusMode restores the user's previous status once they are no
+// longer in the call focus mode was enabled for. An empty callID matches any
+// call, which is used to reconcile the state after missed leave events. The
+// status is only restored if the user didn't change it in the meantime.
+func (p *Plugin) disableFocusMode(userID, callID string) error {
+	state, err := p.getFocusModeState(userID)
+	if err != nil {
+		return err
+	}
+	if state == nil || (callID != "" && state.CallID != callID) {
+		return nil
+	}
+
+	inCall, err := p.store.IsUserInCall(userID, state.CallID, db.GetCallSessionOpts{})
+	if err != nil {
+		return fmt.Errorf("failed to check whether user is in call: %w", err)
+	}
+	if inCall {
+		return nil
+	}
+
+	if appErr := p.API.KVDelete(focusModeKeyPrefix + userID); appErr != nil {
+		return fmt.Errorf("failed to delete focus mode state: %w", appErr)
+	}
+
+	status, appErr := p.API.GetUserStatus(userID)
+	if appErr != nil {
+		return fmt.Errorf("failed to get user status: %w", appErr)
+	}
+	if status.Status != model.StatusDnd {
+		return nil
+	}
+
+	prevStatus := state.PrevStatus
+	if prevStatus == "" || prevStatus == model.StatusOffline {
+		prevStatus = model.StatusOnline
+	}
+	if _, appErr := p.API.UpdateUserStatus(userID, prevStatus); appErr != nil {
+		return fmt.Errorf("failed to update user status: %w", appErr)
+	}
+
+	p.LogDebug("focus mode disabled", "userID", userID, "callID", state.CallID, "status", prevStatus)
+
+	return nil
+}
@@ -0,0 +1,156 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-calls/server/public"
+
+	serverMocks "github.com/mattermost/mattermost-plugin-calls/server/mocks/github.com/mattermost/mattermost-plugin-calls/server/interfaces"
+	pluginMocks "github.com/mattermost/mattermost-plugin-calls/server/mocks/github.com/mattermost/mattermost/server/public/plugin"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnableFocusMode(t *testing.T) {
+	mockAPI := &pluginMocks.MockAPI{}
+	defer mockAPI.AssertExpectations(t)
+
+	p := Plugin{
+		MattermostPlugin: plugin.MattermostPlugin{
+			API: mockAPI,
+		},
+	}
+
+	userID := model.NewId()
+	callID := model.NewId()
+
+	mockPrefs := func(focusMode bool) {
+		data, err := json.Marshal(CallNotificationPreferences{
+			Mode:            CallNotificationModeRing,
+			Channels:        CallNotificationChannelsAll,
+			MutedChannelIDs: []string{},
+			FocusMode:       focusMode,
+		})
+		require.NoError(t, err)
+		mockAPI.On("GetPreferenceForUser", userID, callNotificationPreferencesCategory, callNotificationPreferencesName).
+			Return(model.Preference{Value: string(data)}, nil).Once()
+	}
+
+	t.Run("not opted in", func(t *testing.T) {
+		mockAPI.On("GetPreferenceForUser", userID, callNotificationPreferencesCategory, callNotificationPreferencesName).
+			Return(model.Preference{}, &model.AppError{StatusCode: http.StatusNotFound}).Once()
+
+		require.NoError(t, p.enableFocusMode(userID, callID))
+	})
+
+	t.Run("already in dnd", func(t *testing.T) {
+		mockPrefs(true)
+		mockAPI.On("KVGet", focusModeKeyPrefix+userID).Return(nil, nil).Once()
+		mockAPI.On("GetUserStatus", userID).Return(&model.Status{Status: model.StatusDnd}, nil).Once()
+
+		require.NoError(t, p.enableFocusMode(userID, callID))
+	})
+
+	t.Run("set by another node", func(t *testing.T) {
+		mockPrefs(true)
+		mockAPI.On("KVGet", focusModeKeyPrefix+userID).Return(nil, nil).Once()
+		mockAPI.On("GetUserStatus", userID).Return(&model.Status{Status: model.StatusAway}, nil).Once()
+		mockAPI.On("KVSetWithOptions", focusModeKeyPrefix+userID, mock.Anything, mock.AnythingOfType("model.PluginKVSetOptions")).
+			Return(false, nil).Once()
+
+		require.NoError(t, p.enableFocusMode(userID, callID))
+	})
+
+	t.Run("moved to another call", func(t *testing.T) {
+		newCallID := model.NewId()
+		state, err := json.Marshal(focusModeState{CallID: callID, PrevStatus: model.StatusAway})
+		require.NoError(t, err)
+		newState, err := json.Marshal(focusModeState{CallID: newCallID, PrevStatus: model.StatusAway})
+		require.NoError(t, err)
+
+		mockPrefs(true)
+		mockAPI.On("KVGet", focusModeKeyPrefix+userID).Return(state, nil).Once()
+		mockAPI.On("KVSet", focusModeKeyPrefix+userID, newState).Return(nil).Once()
+
+		require.NoError(t, p.enableFocusMode(userID, newCallID))
+	})
+}
+
+func TestDisableFocusMode(t *testing.T) {
+	mockAPI := &pluginMocks.MockAPI{}
+	mockMetrics := &serverMocks.MockMetrics{}
+	defer mockAPI.AssertExpectations(t)
+
+	p := Plugin{
+		MattermostPlugin: plugin.MattermostPlugin{
+			API: mockAPI,
+		},
+		metrics: mockMetrics,
+	}
+
+	store, tearDown := NewTestStore(t)
+	t.Cleanup(tearDown)
+	p.store = store
+
+	mockAPI.On("LogDebug", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+		mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+	userID := model.NewId()
+	callID := model.NewId()
+
+	state, err := json.Marshal(focusModeState{CallID: callID, PrevStatus: model.StatusAway})
+	require.NoError(t, err)
+
+	t.Run("not active", func(t *testing.T) {
+		mockAPI.On("KVGet", focusModeKeyPrefix+userID).Return(nil, nil).Once()
+		require.NoError(t, p.disableFocusMode(userID, callID))
+	})
+
+	t.Run("different call", func(t *testing.T) {
+		mockAPI.On("KVGet", focusModeKeyPrefix+userID).Return(state, nil).Once()
+		require.NoError(t, p.disableFocusMode(userID, model.NewId()))
+	})
+
+	t.Run("still in call", func(t *testing.T) {
+		defer ResetTestStore(t, p.store)
+
+		err := p.store.CreateCallSession(&public.CallSession{
+			ID:     model.NewId(),
+			CallID: callID,
+			UserID: userID,
+			JoinAt: time.Now().UnixMilli(),
+		})
+		require.NoError(t, err)
+
+		mockAPI.On("KVGet", focusModeKeyPrefix+userID).Return(state, nil).Once()
+		require.NoError(t, p.disableFocusMode(userID, ""))
+	})
+
+	t.Run("status changed by user", func(t *testing.T) {
+		mockAPI.On("KVGet", focusModeKeyPrefix+userID).Return(state, nil).Once()
+		mockAPI.On("KVDelete", focusModeKeyPrefix+userID).Return(nil).Once()
+		mockAPI.On("GetUserStatus", userID).Return(&model.Status{Status: model.StatusOnline}, nil).Once()
+
+		require.NoError(t, p.disableFocusMode(userID, callID))
+	})
+
+	t.Run("restored", func(t *testing.T) {
+		mockAPI.On("KVGet", focusModeKeyPrefix+userID).Return(state, nil).Once()
+		mockAPI.On("KVDelete", focusModeKeyPrefix+userID).Return(nil).Once()
+		mockAPI.On("GetUserStatus", userID).Return(&model.Status{Status: model.StatusDnd}, nil).Once()
+		mockAPI.On("UpdateUserStatus", userID, model.StatusAway).Return(&model.Status{Status: model.StatusAway}, nil).Once()
+
+		// Reconciling after a missed leave matches any call.
+		require.NoError(t, p.disableFocusMode(userID, ""))
+	})
+}
@@ -5,9 +5,11 @@ package main
 
 import (
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/mattermost/mattermost-plugin-calls/server/db"
+	"github.com/mattermost/mattermost-plugin-calls/server/public"
 
 	"github.com/mattermost/mattermost/server/public/model"
 	"github.com/pkg/errors"
@@ -71,6 +73,7 @@ func (p *Plugin) changeHost(requesterID, channelID, newHostID string) error {
 		ReliableClusterSend: true,
 		UserIDs:             getUserIDsFromSessions(state.sessions),
 	})
+	p.recordCallTimelineEvent(state.Call.ID, callTimelineEventHostChange, newHostID, "", "")
 
 	return nil
 }
@@ -91,6 +94,8 @@ func (p *Plugin) muteSession(requesterID, channelID, sessionID string) error {
 		}
 	}
 
+	p.recordHostActivity(state.Call.ID)
+
 	ust, ok := state.sessions[sessionID]
 	if !ok {
 		return ErrNotInCall
@@ -101,6 +106,7 @@ func (p *Plugin) muteSession(requesterID, channelID, sessionID string) error {
 	}
 
 	p.publishWebSocketEvent(wsEventHostMute, map[string]interface{}{
+		"call_id":    state.Call.ID,
 		"channel_id": channelID,
 		"session_id": sessionID,
 	}, &WebSocketBroadcast{UserID: ust.UserID, ReliableClusterSend: true})
@@ -124,11 +130,14 @@ func (p *Plugin) muteOthers(requesterID, channelID string) error {
 		}
 	}
 
+	p.recordHostActivity(state.Call.ID)
+
 	// Unmute anyone muted (who is not the host/requester).
 	// If there are no unmuted sessions, return without doing anything.
 	for id, s := range state.sessions {
 		if s.Unmuted && s.UserID != requesterID {
 			p.publishWebSocketEvent(wsEventHostMute, map[string]interface{}{
+				"call_id":    state.Call.ID,
 				"channel_id": channelID,
 				"session_id": id,
 			}, &WebSocketBroadcast{UserID: s.UserID, ReliableClusterSend: true})
@@ -154,7 +163,9 @@ func (p *Plugin) screenOff(requesterID, channelID, sessionID string) error {
 		}
 	}
 
-	if state.Props.ScreenSharingSessionID != sessionID {
+	p.recordHostActivity(state.Call.ID)
+
+	if !slices.Contains(getScreenSharingSessionIDs(state.Props), sessionID) {
 		return nil
 	}
 
@@ -164,6 +175,7 @@ func (p *Plugin) screenOff(requesterID, channelID, sessionID string) error {
 	}
 
 	p.publishWebSocketEvent(wsEventHostScreenOff, map[string]interface{}{
+		"call_id":    state.Call.ID,
 		"channel_id": channelID,
 		"session_id": sessionID,
 	}, &WebSocketBroadcast{UserID: ust.UserID, ReliableClusterSend: true})
@@ -187,6 +199,8 @@ func (p *Plugin) lowerHand(requesterID, channelID, sessionID string) error {
 		}
 	}
 
+	p.recordHostActivity(state.Call.ID)
+
 	ust, ok := state.sessions[sessionID]
 	if !ok {
 		return ErrNotInCall
@@ -222,6 +236,8 @@ func (p *Plugin) hostRemoveSession(requesterID, channelID, sessionID string) err
 		}
 	}
 
+	p.recordHostActivity(state.Call.ID)
+
 	ust, ok := state.sessions[sessionID]
 	if !ok {
 		return ErrNotInCall
@@ -240,29 +256,92 @@ func (p *Plugin) hostRemoveSession(requesterID, channelID, sessionID string) err
 		UserIDs:             getUserIDsFromSessions(state.sessions),
 	})
 
-	go func() {
-		// Wait a few seconds for the client to end their session cleanly. If they don't (like for an
-		// older mobile client) then forcibly end it.
-		time.Sleep(3 * time.Second)
+	p.setRejoinCooldown(channelID, ust.UserID, rejoinCooldownReasonKicked)
 
-		state, err := p.getCallState(channelID, false)
-		if err != nil {
-			p.LogError("hostRemoveSession: failed to get call state", "err", err.Error())
-		}
+	go p.closeSessionAfterGracePeriod(channelID, sessionID)
 
-		if state == nil {
-			return
-		}
+	return nil
+}
 
-		ust, ok := state.sessions[sessionID]
-		if !ok {
-			return
+// setSpeakerLabels toggles the name labels rendered for the active speaker
+// in the call recording. The recorder is a participant of the call, so it
+// picks up the change live through the websocket event.
+func (p *Plugin) setSpeakerLabels(requesterID, channelID string, enabled bool) error {
+	state, err := p.lockCallReturnState(channelID)
+	if err != nil {
+		return fmt.Errorf("failed to lock call: %w", err)
+	}
+	defer p.unlockCall(channelID)
+
+	if state == nil {
+		return ErrNoCallOngoing
+	}
+
+	if requesterID != state.Call.GetHostID() {
+		if isAdmin := p.API.HasPermissionTo(requesterID, model.PermissionManageSystem); !isAdmin {
+			return ErrNoPermissions
 		}
+	}
 
-		if err := p.closeRTCSession(ust.UserID, sessionID, channelID, state.Call.Props.NodeID, state.Call.ID); err != nil {
-			p.LogError("hostRemoveSession: failed to close RTC session", "err", err.Error())
+	if state.Call.Props.SpeakerLabels == enabled {
+		return nil
+	}
+
+	state.Call.Props.SpeakerLabels = enabled
+	if err := p.store.UpdateCall(&state.Call); err != nil {
+		return fmt.Errorf("failed to update call: %w", err)
+	}
+
+	p.publishWebSocketEvent(wsEventCallSpeakerLabels, map[string]interface{}{
+		"call_id":    state.Call.ID,
+		"channel_id": channelID,
+		"enabled":    enabled,
+	}, &WebSocketBroadcast{
+		ChannelID:           channelID,
+		ReliableClusterSend: true,
+		UserIDs:             getUserIDsFromSessions(state.sessions),
+	})
+
+	return nil
+}
+
+// setNoiseSuppression toggles whether participants should apply noise
+// suppression to their audio for the current call. Audio isn't processed
+// server side, so clients apply the change locally.
+func (p *Plugin) setNoiseSuppression(requesterID, channelID string, enabled bool) error {
+	state, err := p.lockCallReturnState(channelID)
+	if err != nil {
+		return fmt.Errorf("failed to lock call: %w", err)
+	}
+	defer p.unlockCall(channelID)
+
+	if state == nil {
+		return ErrNoCallOngoing
+	}
+
+	if requesterID != state.Call.GetHostID() {
+		if isAdmin := p.API.HasPermissionTo(requesterID, model.PermissionManageSystem); !isAdmin {
+			return ErrNoPermissions
 		}
-	}()
+	}
+
+	if state.Call.Props.NoiseSuppression == enabled {
+		return nil
+	}
+
+	state.Call.Props.NoiseSuppression = enabled
+	if err := p.store.UpdateCall(&state.Call); err != nil {
+		return fmt.Errorf("failed to update call: %w", err)
+	}
+
+	p.publishWebSocketEvent(wsEventCallNoiseSuppression, map[string]interface{}{
+		"call_id": state.Call.ID,
+		"enabled": enabled,
+	}, &WebSocketBroadcast{
+		ChannelID:           channelID,
+		ReliableClusterSend: true,
+		UserIDs:             getUserIDsFromSessions(state.sessions),
+	})
 
 	return nil
 }
@@ -278,14 +357,29 @@ func (p *Plugin) hostEnd(requesterID, channelID string) error {
 		return ErrNoCallOngoing
 	}
 
+	reason := public.CallEndReasonHostEnded
 	if requesterID != state.Call.GetHostID() {
 		if isAdmin := p.API.HasPermissionTo(requesterID, model.PermissionManageSystem); !isAdmin {
 			return ErrNoPermissions
 		}
+		reason = public.CallEndReasonAdminEnded
+	}
+
+	return p.endCall(state, channelID, reason)
+}
+
+// endCall asks participants to leave and force ends the call if they don't
+// in a few seconds.
+// NOTE: this is meant to be called under lock (on channelID).
+func (p *Plugin) endCall(state *callState, channelID string, reason public.CallEndReason) error {
+	// The reason is stored so that it's kept once the call actually ends.
+	state.Call.Props.EndReason = reason
+	if err := p.store.UpdateCall(&state.Call); err != nil {
+		return fmt.Errorf("failed to update call: %w", err)
 	}
 
 	// Ask clients to disconnect themselves. The last to disconnect will cause the call to end, as usual.
-	p.publishWebSocketEvent(wsEventCallEnd, map[string]interface{}{}, &WebSocketBroadcast{ChannelID: channelID, ReliableClusterSend: true})
+	p.publishCallEnd(&state.Call)
 
 	callID := state.Call.ID
 	nodeID := state.Call.Props.NodeID
@@ -297,12 +391,12 @@ func (p *Plugin) hostEnd(requesterID, channelID string) error {
 
 		call, err := p.store.GetCall(callID, db.GetCallOpts{})
 		if err != nil {
-			p.LogError("failed to get call", "err", err.Error())
+			p.LogError("failed to get call", "err", err.Error(), "callID", callID)
 		}
 
 		sessions, err := p.store.GetCallSessions(callID, db.GetCallSessionOpts{})
 		if err != nil {
-			p.LogError("failed to get call sessions", "err", err.Error())
+			p.LogError("failed to get call sessions", "err", err.Error(), "callID", callID)
 		}
 
 		for _, session := range sessions {
@@ -311,10 +405,78 @@ func (p *Plugin) hostEnd(requesterID, channelID string) error {
 			}
 		}
 
-		if err := p.cleanCallState(call); err != nil {
+		if err := p.cleanCallState(call, reason); err != nil {
 			p.LogError(err.Error())
 		}
 	}()
 
 	return nil
 }
+
+// setLiveCaptions turns live captions on or off for the given call. When off,
+// captions produced by the transcriber are not forwarded to participants.
+func (p *Plugin) setLiveCaptions(requesterID, channelID string, enabled bool) error {
+	if !p.licenseChecker.TranscriptionsAllowed() || !p.getConfiguration().liveCaptionsEnabled() {
+		return ErrNotAllowed
+	}
+
+	state, err := p.lockCallReturnState(channelID)
+	if err != nil {
+		return fmt.Errorf("failed to lock call: %w", err)
+	}
+	defer p.unlockCall(channelID)
+
+	if state == nil {
+		return ErrNoCallOngoing
+	}
+
+	if requesterID != state.Call.GetHostID() {
+		if isAdmin := p.API.HasPermissionTo(requesterID, model.PermissionManageSystem); !isAdmin {
+			return ErrNoPermissions
+		}
+	}
+
+	if state.Call.Props.LiveCaptionsOff == !enabled {
+		return nil
+	}
+
+	state.Call.Props.LiveCaptionsOff = !enabled
+	if err := p.store.UpdateCall(&state.Call); err != nil {
+		return fmt.Errorf("failed to update call: %w", err)
+	}
+
+	// Sessions keep the toggle in memory, so we update those handled by
+	// this node and let the others do the same.
+	p.setSessionsLiveCaptionsOff(state.Call.ID, !enabled)
+	if err := p.sendClusterMessage(clusterMessage{
+		CallID:          state.Call.ID,
+		SenderID:        p.nodeID,
+		LiveCaptionsOff: !enabled,
+	}, clusterMessageTypeLiveCaptions, ""); err != nil {
+		p.LogError("failed to send live captions message", "err", err.Error(), "callID", state.Call.ID)
+	}
+
+	p.publishWebSocketEvent(wsEventCallLiveCaptions, map[string]interface{}{
+		"call_id": state.Call.ID,
+		"enabled": enabled,
+	}, &WebSocketBroadcast{
+		ChannelID:           channelID,
+		ReliableClusterSend: true,
+		UserIDs:             getUserIDsFromSessions(state.sessions),
+	})
+
+	return nil
+}
+
+// setSessionsLiveCaptionsOff updates the live captions toggle of the sessions
+// handled by this node for the given call.
+func (p *Plugin) setSessionsLiveCaptionsOff(callID string, off bool) {
+	p.mut.RLock()
+	defer p.mut.RUnlock()
+
+	for _, us := range p.sessions {
+		if us.callID == callID {
+			us.liveCaptionsOff.Store(off)
+		}
+	}
+}
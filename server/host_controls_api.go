@@ -6,9 +6,16 @@ package main
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/mattermost/mattermost-plugin-calls/server/public"
 
 	"github.com/gorilla/mux"
+
+	"github.com/mattermost/mattermost/server/public/model"
 )
 
 func (p *Plugin) handleMakeHost(w http.ResponseWriter, r *http.Request) {
@@ -168,6 +175,156 @@ func (p *Plugin) handleEnd(w http.ResponseWriter, r *http.Request) {
 	res.Msg = "success"
 }
 
+func (p *Plugin) handleSpeakerLabels(w http.ResponseWriter, r *http.Request) {
+	var res httpResponse
+	defer p.httpAudit("handleSpeakerLabels", &res, w, r)
+
+	userID := r.Header.Get("Mattermost-User-Id")
+	callID := mux.Vars(r)["call_id"]
+
+	var payload struct {
+		Enabled bool `json:"enabled"`
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, requestBodyMaxSizeBytes)).Decode(&payload); err != nil {
+		res.Err = err.Error()
+		res.Code = http.StatusBadRequest
+		return
+	}
+
+	if err := p.setSpeakerLabels(userID, callID, payload.Enabled); err != nil {
+		p.handleHostControlsError(err, &res, "handleSpeakerLabels")
+		return
+	}
+
+	res.Code = http.StatusOK
+	res.Msg = "success"
+}
+
+func (p *Plugin) handleNoiseSuppression(w http.ResponseWriter, r *http.Request) {
+	var res httpResponse
+	defer p.httpAudit("handleNoiseSuppression", &res, w, r)
+
+	userID := r.Header.Get("Mattermost-User-Id")
+	callID := mux.Vars(r)["call_id"]
+
+	var payload struct {
+		Enabled bool `json:"enabled"`
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, requestBodyMaxSizeBytes)).Decode(&payload); err != nil {
+		res.Err = err.Error()
+		res.Code = http.StatusBadRequest
+		return
+	}
+
+	if err := p.setNoiseSuppression(userID, callID, payload.Enabled); err != nil {
+		p.handleHostControlsError(err, &res, "handleNoiseSuppression")
+		return
+	}
+
+	res.Code = http.StatusOK
+	res.Msg = "success"
+}
+
+// handleCallFeatures turns an interactive feature (e.g. reactions) on or off
+// for the call.
+func (p *Plugin) handleCallFeatures(w http.ResponseWriter, r *http.Request) {
+	var res httpResponse
+	defer p.httpAudit("handleCallFeatures", &res, w, r)
+
+	userID := r.Header.Get("Mattermost-User-Id")
+	callID := mux.Vars(r)["call_id"]
+
+	var payload struct {
+		Feature string `json:"feature"`
+		Enabled bool   `json:"enabled"`
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, requestBodyMaxSizeBytes)).Decode(&payload); err != nil {
+		res.Err = err.Error()
+		res.Code = http.StatusBadRequest
+		return
+	}
+
+	if !slices.Contains(public.CallFeatures, payload.Feature) {
+		res.Err = fmt.Sprintf("invalid feature: should be one of %s", strings.Join(public.CallFeatures, ", "))
+		res.Code = http.StatusBadRequest
+		return
+	}
+
+	if err := p.setCallFeature(userID, callID, payload.Feature, payload.Enabled); err != nil {
+		p.handleHostControlsError(err, &res, "handleCallFeatures")
+		return
+	}
+
+	res.Code = http.StatusOK
+	res.Msg = "success"
+}
+
+// handleNoiseAutoMute overrides the automatic muting of noisy participants for
+// the call. A null payload restores the configured one.
+func (p *Plugin) handleNoiseAutoMute(w http.ResponseWriter, r *http.Request) {
+	var res httpResponse
+	defer p.httpAudit("handleNoiseAutoMute", &res, w, r)
+
+	userID := r.Header.Get("Mattermost-User-Id")
+	callID := mux.Vars(r)["call_id"]
+
+	var payload *public.CallNoiseAutoMute
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, requestBodyMaxSizeBytes)).Decode(&payload); err != nil {
+		res.Err = err.Error()
+		res.Code = http.StatusBadRequest
+		return
+	}
+
+	if payload != nil {
+		if err := payload.IsValid(); err != nil {
+			res.Err = err.Error()
+			res.Code = http.StatusBadRequest
+			return
+		}
+	}
+
+	if err := p.setCallNoiseAutoMute(userID, callID, payload); err != nil {
+		p.handleHostControlsError(err, &res, "handleNoiseAutoMute")
+		return
+	}
+
+	res.Code = http.StatusOK
+	res.Msg = "success"
+}
+
+// handleMoveCall moves the call to another channel the requester can access.
+func (p *Plugin) handleMoveCall(w http.ResponseWriter, r *http.Request) {
+	var res httpResponse
+	defer p.httpAudit("handleMoveCall", &res, w, r)
+
+	userID := r.Header.Get("Mattermost-User-Id")
+	callID := mux.Vars(r)["call_id"]
+
+	var payload struct {
+		ChannelID           string `json:"channel_id"`
+		RemoveWithoutAccess bool   `json:"remove_without_access"`
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, requestBodyMaxSizeBytes)).Decode(&payload); err != nil {
+		res.Err = err.Error()
+		res.Code = http.StatusBadRequest
+		return
+	}
+
+	if !model.IsValidId(payload.ChannelID) {
+		res.Err = "invalid channel_id"
+		res.Code = http.StatusBadRequest
+		return
+	}
+
+	if err := p.moveCall(userID, callID, payload.ChannelID, payload.RemoveWithoutAccess); err != nil {
+		p.handleHostControlsError(err, &res, "handleMoveCall")
+		return
+	}
+
+	res.Code = http.StatusOK
+	res.Msg = "success"
+}
+
 func (p *Plugin) handleHostControlsError(err error, res *httpResponse, handlerName string) {
 	p.LogError(handlerName, "err", err.Error())
 
@@ -181,3 +338,104 @@ func (p *Plugin) handleHostControlsError(err error, res *httpResponse, handlerNa
 
 	res.Err = err.Error()
 }
+
+func (p *Plugin) handleLiveCaptions(w http.ResponseWriter, r *http.Request) {
+	var res httpResponse
+	defer p.httpAudit("handleLiveCaptions", &res, w, r)
+
+	userID := r.Header.Get("Mattermost-User-Id")
+	callID := mux.Vars(r)["call_id"]
+
+	var payload struct {
+		Enabled bool `json:"enabled"`
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, requestBodyMaxSizeBytes)).Decode(&payload); err != nil {
+		res.Err = err.Error()
+		res.Code = http.StatusBadRequest
+		return
+	}
+
+	if err := p.setLiveCaptions(userID, callID, payload.Enabled); err != nil {
+		p.handleHostControlsError(err, &res, "handleLiveCaptions")
+		return
+	}
+
+	res.Code = http.StatusOK
+	res.Msg = "success"
+}
+
+func (p *Plugin) handleWaitingRoom(w http.ResponseWriter, r *http.Request) {
+	var res httpResponse
+	defer p.httpAudit("handleWaitingRoom", &res, w, r)
+
+	userID := r.Header.Get("Mattermost-User-Id")
+	callID := mux.Vars(r)["call_id"]
+
+	var payload struct {
+		Enabled bool `json:"enabled"`
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, requestBodyMaxSizeBytes)).Decode(&payload); err != nil {
+		res.Err = err.Error()
+		res.Code = http.StatusBadRequest
+		return
+	}
+
+	if err := p.setWaitingRoom(userID, callID, payload.Enabled); err != nil {
+		p.handleHostControlsError(err, &res, "handleWaitingRoom")
+		return
+	}
+
+	res.Code = http.StatusOK
+	res.Msg = "success"
+}
+
+func (p *Plugin) handleAdmit(w http.ResponseWriter, r *http.Request) {
+	var res httpResponse
+	defer p.httpAudit("handleAdmit", &res, w, r)
+
+	userID := r.Header.Get("Mattermost-User-Id")
+	callID := mux.Vars(r)["call_id"]
+
+	var payload struct {
+		SessionID string `json:"session_id"`
+		All       bool   `json:"all"`
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, requestBodyMaxSizeBytes)).Decode(&payload); err != nil {
+		res.Err = err.Error()
+		res.Code = http.StatusBadRequest
+		return
+	}
+
+	if err := p.admitWaitingSessions(userID, callID, payload.SessionID, payload.All, true); err != nil {
+		p.handleHostControlsError(err, &res, "handleAdmit")
+		return
+	}
+
+	res.Code = http.StatusOK
+	res.Msg = "success"
+}
+
+func (p *Plugin) handleDeny(w http.ResponseWriter, r *http.Request) {
+	var res httpResponse
+	defer p.httpAudit("handleDeny", &res, w, r)
+
+	userID := r.Header.Get("Mattermost-User-Id")
+	callID := mux.Vars(r)["call_id"]
+
+	var payload struct {
+		SessionID string `json:"session_id"`
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, requestBodyMaxSizeBytes)).Decode(&payload); err != nil {
+		res.Err = err.Error()
+		res.Code = http.StatusBadRequest
+		return
+	}
+
+	if err := p.admitWaitingSessions(userID, callID, payload.SessionID, false, false); err != nil {
+		p.handleHostControlsError(err, &res, "handleDeny")
+		return
+	}
+
+	res.Code = http.StatusOK
+	res.Msg = "success"
+}
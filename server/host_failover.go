@@ -0,0 +1,147 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	hostFailoverCheckInterval = 30 * time.Second
+	nodeHeartbeatKeyPrefix    = "node_heartbeat_"
+)
+
+// A node is considered gone if it hasn't refreshed its heartbeat in this long.
+var nodeHeartbeatTTL = 3 * hostFailoverCheckInterval
+
+// hostFailoverChecker periodically refreshes this node's heartbeat and checks
+// the calls this node has sessions in, reassigning the host role if the node
+// the host is connected through is gone. Nodes going down don't trigger any
+// leave event for the sessions they were handling, so without this a call
+// could be left with an unreachable host.
+func (p *Plugin) hostFailoverChecker(nodeID string) {
+	ticker := time.NewTicker(hostFailoverCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		p.hostFailoverCheck(nodeID)
+
+		select {
+		case <-ticker.C:
+		case <-p.stopCh:
+			return
+		}
+	}
+}
+
+func (p *Plugin) hostFailoverCheck(nodeID string) {
+	if appErr := p.API.KVSetWithExpiry(nodeHeartbeatKeyPrefix+nodeID, []byte{1}, int64(nodeHeartbeatTTL.Seconds())); appErr != nil {
+		p.LogError("failed to set node heartbeat", "nodeID", nodeID, "err", appErr.Error())
+	}
+
+	localSessions := map[string][]string{}
+	p.mut.RLock()
+	for _, us := range p.sessions {
+		localSessions[us.getChannelID()] = append(localSessions[us.getChannelID()], us.originalConnID)
+	}
+	p.mut.RUnlock()
+
+	for channelID, sessionIDs := range localSessions {
+		if err := p.checkCallHostNode(nodeID, channelID, sessionIDs); err != nil {
+			p.LogError("failed to check call host node", "channelID", channelID, "err", err.Error())
+		}
+	}
+}
+
+// checkCallHostNode keeps track of the node the call host is connected
+// through and fails over to a new host if that node is gone.
+func (p *Plugin) checkCallHostNode(nodeID, channelID string, localSessionIDs []string) error {
+	state, err := p.lockCallReturnState(channelID)
+	if err != nil {
+		return fmt.Errorf("failed to lock call: %w", err)
+	}
+	defer p.unlockCall(channelID)
+
+	if state == nil {
+		return nil
+	}
+
+	hostID := state.Call.GetHostID()
+	if hostID == "" {
+		return nil
+	}
+
+	// The host is connected through this node.
+	for _, sessionID := range localSessionIDs {
+		if session := state.sessions[sessionID]; session != nil && session.UserID == hostID {
+			if state.Call.Props.HostNodeID == nodeID {
+				return nil
+			}
+			state.Call.Props.HostNodeID = nodeID
+			return p.store.UpdateCall(&state.Call)
+		}
+	}
+
+	hostNodeID := state.Call.Props.HostNodeID
+
+	// The host is no longer connected through this node (e.g. reconnected
+	// elsewhere). We clear it so that the right node can claim it.
+	if hostNodeID == nodeID {
+		state.Call.Props.HostNodeID = ""
+		return p.store.UpdateCall(&state.Call)
+	}
+
+	if hostNodeID == "" {
+		return nil
+	}
+
+	data, appErr := p.API.KVGet(nodeHeartbeatKeyPrefix + hostNodeID)
+	if appErr != nil {
+		return fmt.Errorf("failed to get node heartbeat: %w", appErr)
+	}
+	if data != nil {
+		return nil
+	}
+
+	// Sessions connected through the gone node are left behind since no leave
+	// event fires for them, so the new host is picked among the sessions
+	// connected through this node, which are known to be alive.
+	newHostID := state.getNewHostIDAmongSessions(p.getBotID(), hostID, localSessionIDs)
+
+	p.LogInfo("host's node is gone, reassigning host", "channelID", channelID, "callID", state.Call.ID,
+		"hostNodeID", hostNodeID, "hostID", hostID, "newHostID", newHostID)
+
+	if state.Call.Props.HostLockedUserID == hostID {
+		state.Call.Props.HostLockedUserID = ""
+	}
+	p.updateCallHost(state, channelID, newHostID)
+
+	return p.store.UpdateCall(&state.Call)
+}
+
+// updateCallHost sets the new host for the call and lets participants know.
+// NOTE: this is meant to be called under lock (on channelID).
+func (p *Plugin) updateCallHost(state *callState, channelID, newHostID string) {
+	if newHostID == "" {
+		state.Call.Props.Hosts = nil
+	} else {
+		state.Call.Props.Hosts = []string{newHostID}
+	}
+
+	// The node the new host is connected through will claim it.
+	state.Call.Props.HostNodeID = ""
+
+	p.publishWebSocketEvent(wsEventCallHostChanged, map[string]interface{}{
+		"hostID":  newHostID,
+		"call_id": state.Call.ID,
+	}, &WebSocketBroadcast{
+		ChannelID:           channelID,
+		ReliableClusterSend: true,
+		UserIDs:             getUserIDsFromSessions(state.sessions),
+	})
+	if newHostID != "" {
+		p.recordCallTimelineEvent(state.Call.ID, callTimelineEventHostChange, newHostID, "", "")
+	}
+}
@@ -0,0 +1,273 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/mattermost/rtcd/service/rtc"
+)
+
+// ICE candidate types as defined in RFC 8445. Peer reflexive candidates are
+// left out as they are only discovered during connectivity checks and never
+// signaled.
+const (
+	iceCandidateTypeHost  = "host"
+	iceCandidateTypeSrflx = "srflx"
+	iceCandidateTypeRelay = "relay"
+)
+
+var iceCandidateTypes = []string{
+	iceCandidateTypeHost,
+	iceCandidateTypeSrflx,
+	iceCandidateTypeRelay,
+}
+
+// ICE transport policies as defined by the WebRTC RTCConfiguration.
+const (
+	iceTransportPolicyAll   = "all"
+	iceTransportPolicyRelay = "relay"
+)
+
+const sdpCandidateAttrPrefix = "a=candidate:"
+
+// getICECandidateType returns the type of the given candidate attribute
+// (e.g. "candidate:1 1 udp 2130706431 10.0.0.1 8443 typ host") or an empty
+// string if it cannot be found.
+func getICECandidateType(candidate string) string {
+	fields := strings.Fields(candidate)
+	for i := 0; i < len(fields)-1; i++ {
+		if fields[i] == "typ" {
+			return strings.ToLower(fields[i+1])
+		}
+	}
+	return ""
+}
+
+func parseICECandidateTypes(value string) []string {
+	var types []string
+	for _, typ := range strings.Split(value, ",") {
+		if typ = strings.ToLower(strings.TrimSpace(typ)); typ != "" && !slices.Contains(types, typ) {
+			types = append(types, typ)
+		}
+	}
+	return types
+}
+
+// getAllowedICECandidateTypes returns the normalized list of candidate types
+// clients are allowed to signal. An empty list means all types are allowed.
+func (c *configuration) getAllowedICECandidateTypes() []string {
+	return parseICECandidateTypes(c.AllowedICECandidateTypes)
+}
+
+// getICETransportPolicy returns the ICE transport policy clients should apply.
+// When only relay candidates are allowed clients are told to gather those
+// alone, so that they don't fail to connect waiting on candidates that would
+// be dropped anyway.
+func (c *configuration) getICETransportPolicy() string {
+	if allowedTypes := c.getAllowedICECandidateTypes(); len(allowedTypes) == 1 && allowedTypes[0] == iceCandidateTypeRelay {
+		return iceTransportPolicyRelay
+	}
+	return iceTransportPolicyAll
+}
+
+// getICECandidatesPriority returns the normalized list of candidate types, in
+// order of priority, to advertise first when the number of candidates per
+// session is capped.
+func (c *configuration) getICECandidatesPriority() []string {
+	return parseICECandidateTypes(c.ICECandidatesPriority)
+}
+
+func (c *configuration) getMaxICECandidatesPerSession() int {
+	if c.MaxICECandidatesPerSession == nil {
+		return 0
+	}
+	return *c.MaxICECandidatesPerSession
+}
+
+func (c *configuration) isICECandidateAllowed(candidate string) bool {
+	allowedTypes := c.getAllowedICECandidateTypes()
+	if len(allowedTypes) == 0 {
+		return true
+	}
+
+	// An empty candidate signals the end of gathering.
+	if strings.TrimSpace(candidate) == "" {
+		return true
+	}
+
+	return slices.Contains(allowedTypes, getICECandidateType(candidate))
+}
+
+// isICEMessageAllowed returns whether the candidate carried by the given ICE
+// signaling message is of an allowed type.
+func (c *configuration) isICEMessageAllowed(data []byte) (bool, error) {
+	if c.AllowedICECandidateTypes == "" {
+		return true, nil
+	}
+
+	var msg struct {
+		Candidate string `json:"candidate"`
+	}
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return false, fmt.Errorf("failed to unmarshal ICE message: %w", err)
+	}
+
+	return c.isICECandidateAllowed(msg.Candidate), nil
+}
+
+// filterSDPMessageCandidates removes the candidates of disallowed types
+// embedded in the session description carried by the given SDP signaling
+// message.
+func (c *configuration) filterSDPMessageCandidates(data []byte) ([]byte, error) {
+	if c.AllowedICECandidateTypes == "" {
+		return data, nil
+	}
+
+	var msg map[string]any
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal SDP message: %w", err)
+	}
+
+	sdp, ok := msg["sdp"].(string)
+	if !ok {
+		return data, nil
+	}
+
+	filtered, ok := filterSDPCandidates(sdp, c.isICECandidateAllowed)
+	if !ok {
+		return data, nil
+	}
+
+	msg["sdp"] = filtered
+
+	return json.Marshal(msg)
+}
+
+// filterSDPCandidates removes the candidate lines for which keep returns false
+// from the given session description. It returns whether any was removed.
+func filterSDPCandidates(sdp string, keep func(candidate string) bool) (string, bool) {
+	lines := strings.Split(sdp, "\r\n")
+	filtered := lines[:0]
+	for _, line := range lines {
+		if strings.HasPrefix(line, sdpCandidateAttrPrefix) && !keep(strings.TrimPrefix(line, "a=")) {
+			continue
+		}
+		filtered = append(filtered, line)
+	}
+
+	if len(filtered) == len(lines) {
+		return sdp, false
+	}
+
+	return strings.Join(filtered, "\r\n"), true
+}
+
+// iceCandidateLimiter caps the number of ICE candidates advertised to a
+// session. Since candidates are trickled in the order they are gathered
+// (usually host first), a slot is reserved for each of the prioritized types
+// that wasn't advertised yet so that they don't get crowded out.
+type iceCandidateLimiter struct {
+	mut        sync.Mutex
+	count      int
+	advertised map[string]bool
+}
+
+func newICECandidateLimiter() *iceCandidateLimiter {
+	return &iceCandidateLimiter{
+		advertised: make(map[string]bool),
+	}
+}
+
+// allow returns whether a candidate of the given type can be advertised
+// without going over maxCandidates, and counts it if so.
+func (l *iceCandidateLimiter) allow(typ string, maxCandidates int, priority []string) bool {
+	if maxCandidates <= 0 {
+		return true
+	}
+
+	l.mut.Lock()
+	defer l.mut.Unlock()
+
+	limit := maxCandidates
+	for _, prioritizedType := range priority {
+		if prioritizedType == typ {
+			break
+		}
+		if !l.advertised[prioritizedType] {
+			limit--
+		}
+	}
+
+	if l.count >= limit {
+		return false
+	}
+
+	l.count++
+	l.advertised[typ] = true
+
+	return true
+}
+
+// allowCandidate is like allow but takes the candidate attribute. An empty
+// candidate signals the end of gathering and is always allowed.
+func (l *iceCandidateLimiter) allowCandidate(candidate string, maxCandidates int, priority []string) bool {
+	if strings.TrimSpace(candidate) == "" {
+		return true
+	}
+	return l.allow(getICECandidateType(candidate), maxCandidates, priority)
+}
+
+// filterSignalCandidates applies the configured cap on advertised candidates
+// to the given signaling message sent by the SFU to the session. It returns
+// a nil message if it should be dropped altogether.
+func (c *configuration) filterSignalCandidates(l *iceCandidateLimiter, msgType rtc.MessageType, data []byte) ([]byte, error) {
+	maxCandidates := c.getMaxICECandidatesPerSession()
+	if maxCandidates == 0 || l == nil {
+		return data, nil
+	}
+	priority := c.getICECandidatesPriority()
+
+	switch msgType {
+	case rtc.ICEMessage:
+		var msg struct {
+			Candidate struct {
+				Candidate string `json:"candidate"`
+			} `json:"candidate"`
+		}
+		if err := json.Unmarshal(data, &msg); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal ICE message: %w", err)
+		}
+		if !l.allowCandidate(msg.Candidate.Candidate, maxCandidates, priority) {
+			return nil, nil
+		}
+	case rtc.SDPMessage:
+		var msg map[string]any
+		if err := json.Unmarshal(data, &msg); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal SDP message: %w", err)
+		}
+
+		sdp, ok := msg["sdp"].(string)
+		if !ok {
+			return data, nil
+		}
+
+		filtered, ok := filterSDPCandidates(sdp, func(candidate string) bool {
+			return l.allowCandidate(candidate, maxCandidates, priority)
+		})
+		if !ok {
+			return data, nil
+		}
+
+		msg["sdp"] = filtered
+
+		return json.Marshal(msg)
+	}
+
+	return data, nil
+}
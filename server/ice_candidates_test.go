@@ -0,0 +1,201 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/rtcd/service/rtc"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetICECandidateType(t *testing.T) {
+	require.Equal(t, "host", getICECandidateType("candidate:1 1 udp 2130706431 10.0.0.1 8443 typ host"))
+	require.Equal(t, "srflx", getICECandidateType("candidate:2 1 udp 1694498815 1.1.1.1 8443 typ srflx raddr 10.0.0.1 rport 8443"))
+	require.Equal(t, "relay", getICECandidateType("candidate:3 1 udp 16777215 2.2.2.2 3478 typ relay raddr 1.1.1.1 rport 8443"))
+	require.Empty(t, getICECandidateType("candidate:1 1 udp 2130706431 10.0.0.1 8443"))
+	require.Empty(t, getICECandidateType(""))
+}
+
+func TestGetICETransportPolicy(t *testing.T) {
+	var cfg configuration
+	cfg.SetDefaults()
+	require.Equal(t, iceTransportPolicyAll, cfg.getICETransportPolicy())
+
+	cfg.AllowedICECandidateTypes = "srflx,relay"
+	require.Equal(t, iceTransportPolicyAll, cfg.getICETransportPolicy())
+
+	cfg.AllowedICECandidateTypes = " relay "
+	require.Equal(t, iceTransportPolicyRelay, cfg.getICETransportPolicy())
+}
+
+func TestIsICEMessageAllowed(t *testing.T) {
+	var cfg configuration
+	cfg.SetDefaults()
+
+	hostMsg := []byte(`{"candidate":"candidate:1 1 udp 2130706431 10.0.0.1 8443 typ host","sdpMid":"0","sdpMLineIndex":0}`)
+	srflxMsg := []byte(`{"candidate":"candidate:2 1 udp 1694498815 1.1.1.1 8443 typ srflx raddr 10.0.0.1 rport 8443","sdpMid":"0","sdpMLineIndex":0}`)
+
+	t.Run("all allowed", func(t *testing.T) {
+		allowed, err := cfg.isICEMessageAllowed(hostMsg)
+		require.NoError(t, err)
+		require.True(t, allowed)
+	})
+
+	t.Run("restricted", func(t *testing.T) {
+		cfg.AllowedICECandidateTypes = "srflx, relay"
+
+		allowed, err := cfg.isICEMessageAllowed(hostMsg)
+		require.NoError(t, err)
+		require.False(t, allowed)
+
+		allowed, err = cfg.isICEMessageAllowed(srflxMsg)
+		require.NoError(t, err)
+		require.True(t, allowed)
+
+		allowed, err = cfg.isICEMessageAllowed([]byte(`{"candidate":""}`))
+		require.NoError(t, err)
+		require.True(t, allowed)
+
+		_, err = cfg.isICEMessageAllowed([]byte(`not json`))
+		require.Error(t, err)
+	})
+}
+
+func TestFilterSDPMessageCandidates(t *testing.T) {
+	var cfg configuration
+	cfg.SetDefaults()
+
+	sdp := "v=0\r\n" +
+		"m=audio 9 UDP/TLS/RTP/SAVPF 111\r\n" +
+		"a=candidate:1 1 udp 2130706431 10.0.0.1 8443 typ host\r\n" +
+		"a=candidate:2 1 udp 1694498815 1.1.1.1 8443 typ srflx raddr 10.0.0.1 rport 8443\r\n" +
+		"a=end-of-candidates\r\n"
+	data, err := json.Marshal(map[string]string{"type": "offer", "sdp": sdp})
+	require.NoError(t, err)
+
+	t.Run("all allowed", func(t *testing.T) {
+		filtered, err := cfg.filterSDPMessageCandidates(data)
+		require.NoError(t, err)
+		require.Equal(t, data, filtered)
+	})
+
+	t.Run("restricted", func(t *testing.T) {
+		cfg.AllowedICECandidateTypes = "srflx,relay"
+
+		filtered, err := cfg.filterSDPMessageCandidates(data)
+		require.NoError(t, err)
+
+		var msg map[string]string
+		require.NoError(t, json.Unmarshal(filtered, &msg))
+		require.Equal(t, "offer", msg["type"])
+		require.Equal(t, "v=0\r\n"+
+			"m=audio 9 UDP/TLS/RTP/SAVPF 111\r\n"+
+			"a=candidate:2 1 udp 1694498815 1.1.1.1 8443 typ srflx raddr 10.0.0.1 rport 8443\r\n"+
+			"a=end-of-candidates\r\n", msg["sdp"])
+	})
+}
+
+func TestICECandidateLimiter(t *testing.T) {
+	priority := []string{"relay", "srflx"}
+
+	t.Run("no limit", func(t *testing.T) {
+		l := newICECandidateLimiter()
+		for i := 0; i < 10; i++ {
+			require.True(t, l.allow("host", 0, priority))
+		}
+	})
+
+	t.Run("prioritized types get reserved slots", func(t *testing.T) {
+		l := newICECandidateLimiter()
+		require.True(t, l.allow("host", 4, priority))
+		require.True(t, l.allow("host", 4, priority))
+		require.False(t, l.allow("host", 4, priority))
+		require.True(t, l.allow("srflx", 4, priority))
+		require.False(t, l.allow("srflx", 4, priority))
+		require.True(t, l.allow("relay", 4, priority))
+		require.False(t, l.allow("relay", 4, priority))
+	})
+
+	t.Run("unused reserved slots", func(t *testing.T) {
+		l := newICECandidateLimiter()
+		require.True(t, l.allow("relay", 4, priority))
+		require.True(t, l.allow("relay", 4, priority))
+		require.True(t, l.allow("host", 4, priority))
+		require.False(t, l.allow("host", 4, priority))
+		require.True(t, l.allow("srflx", 4, priority))
+		require.False(t, l.allow("srflx", 4, priority))
+	})
+}
+
+func TestFilterSignalCandidates(t *testing.T) {
+	var cfg configuration
+	cfg.SetDefaults()
+
+	iceMsg := func(typ string) []byte {
+		data, err := json.Marshal(map[string]any{
+			"type": "candidate",
+			"candidate": map[string]any{
+				"candidate":     "candidate:1 1 udp 2130706431 10.0.0.1 8443 typ " + typ,
+				"sdpMid":        "0",
+				"sdpMLineIndex": 0,
+			},
+		})
+		require.NoError(t, err)
+		return data
+	}
+
+	t.Run("no limit", func(t *testing.T) {
+		l := newICECandidateLimiter()
+		data, err := cfg.filterSignalCandidates(l, rtc.ICEMessage, iceMsg("host"))
+		require.NoError(t, err)
+		require.Equal(t, iceMsg("host"), data)
+	})
+
+	t.Run("ice", func(t *testing.T) {
+		cfg.MaxICECandidatesPerSession = model.NewPointer(2)
+		l := newICECandidateLimiter()
+
+		data, err := cfg.filterSignalCandidates(l, rtc.ICEMessage, iceMsg("host"))
+		require.NoError(t, err)
+		require.Nil(t, data)
+
+		data, err = cfg.filterSignalCandidates(l, rtc.ICEMessage, iceMsg("srflx"))
+		require.NoError(t, err)
+		require.Equal(t, iceMsg("srflx"), data)
+
+		data, err = cfg.filterSignalCandidates(l, rtc.ICEMessage, iceMsg("relay"))
+		require.NoError(t, err)
+		require.Equal(t, iceMsg("relay"), data)
+
+		data, err = cfg.filterSignalCandidates(l, rtc.ICEMessage, iceMsg("relay"))
+		require.NoError(t, err)
+		require.Nil(t, data)
+
+		_, err = cfg.filterSignalCandidates(l, rtc.ICEMessage, []byte(`not json`))
+		require.Error(t, err)
+	})
+
+	t.Run("sdp", func(t *testing.T) {
+		cfg.MaxICECandidatesPerSession = model.NewPointer(1)
+		cfg.ICECandidatesPriority = "srflx"
+		l := newICECandidateLimiter()
+
+		sdp := "v=0\r\n" +
+			"a=candidate:1 1 udp 2130706431 10.0.0.1 8443 typ host\r\n" +
+			"a=candidate:2 1 udp 1694498815 1.1.1.1 8443 typ srflx raddr 10.0.0.1 rport 8443\r\n"
+		data, err := json.Marshal(map[string]string{"type": "offer", "sdp": sdp})
+		require.NoError(t, err)
+
+		filtered, err := cfg.filterSignalCandidates(l, rtc.SDPMessage, data)
+		require.NoError(t, err)
+
+		var msg map[string]string
+		require.NoError(t, json.Unmarshal(filtered, &msg))
+		require.Equal(t, "v=0\r\na=candidate:2 1 udp 1694498815 1.1.1.1 8443 typ srflx raddr 10.0.0.1 rport 8443\r\n", msg["sdp"])
+	})
+}
@@ -0,0 +1,60 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"encoding/json"
+)
+
+// Initiators of an ICE restart, used as metric labels.
+const (
+	iceRestartInitiatorClient = "client"
+	iceRestartInitiatorServer = "server"
+)
+
+// handleClientICERestart detects whether the given client SDP message is
+// restarting ICE, which happens when the client's network changes, and if so
+// sends the client fresh TURN credentials to gather candidates with. The
+// restart itself is then negotiated with the RTC server as any other offer.
+func (p *Plugin) handleClientICERestart(us *session, data []byte) {
+	ufrag := getSDPOfferICEUfrag(data)
+	if ufrag == "" {
+		return
+	}
+
+	prevUfrag := us.iceUfrag.Swap(&ufrag)
+	if prevUfrag == nil || *prevUfrag == ufrag {
+		return
+	}
+
+	p.LogDebug("client restarting ICE", "userID", us.userID, "connID", us.connID, "channelID", us.getChannelID(), "callID", us.callID)
+	p.metrics.IncICERestarts(iceRestartInitiatorClient)
+
+	go p.publishICEServers(us)
+}
+
+// requestICERestart asks the client to restart ICE, including fresh TURN
+// credentials if any, as its media connection appears to have been lost.
+func (p *Plugin) requestICERestart(us *session) {
+	p.LogDebug("requesting ICE restart", "userID", us.userID, "connID", us.connID, "channelID", us.getChannelID(), "callID", us.callID)
+	p.metrics.IncICERestarts(iceRestartInitiatorServer)
+
+	data := map[string]interface{}{
+		"call_id": us.callID,
+	}
+
+	iceServers, hasCredentials, err := p.getUserICEServers(us.userID, us.getChannelID())
+	if err != nil {
+		p.LogError("failed to get ICE servers", "err", err.Error(), "userID", us.userID, "connID", us.connID)
+	} else if hasCredentials {
+		iceServersData, err := json.Marshal(iceServers)
+		if err != nil {
+			p.LogError("failed to marshal ICE servers", "err", err.Error())
+		} else {
+			data["ice_servers"] = string(iceServersData)
+		}
+	}
+
+	p.publishWebSocketEvent(wsEventICERestart, data, &WebSocketBroadcast{ConnectionID: us.connID, ReliableClusterSend: true})
+}
@@ -0,0 +1,58 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	serverMocks "github.com/mattermost/mattermost-plugin-calls/server/mocks/github.com/mattermost/mattermost-plugin-calls/server/interfaces"
+	pluginMocks "github.com/mattermost/mattermost-plugin-calls/server/mocks/github.com/mattermost/mattermost/server/public/plugin"
+
+	"github.com/mattermost/mattermost/server/public/plugin"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleClientICERestart(t *testing.T) {
+	mockAPI := &pluginMocks.MockAPI{}
+	mockMetrics := &serverMocks.MockMetrics{}
+
+	defer mockAPI.AssertExpectations(t)
+	defer mockMetrics.AssertExpectations(t)
+
+	p := Plugin{
+		MattermostPlugin: plugin.MattermostPlugin{
+			API: mockAPI,
+		},
+		metrics: mockMetrics,
+	}
+
+	newOffer := func(ufrag string) []byte {
+		data, err := json.Marshal(map[string]string{
+			"type": "offer",
+			"sdp":  "v=0\r\nm=audio 9 UDP/TLS/RTP/SAVPF 111\r\na=ice-ufrag:" + ufrag + "\r\n",
+		})
+		require.NoError(t, err)
+		return data
+	}
+
+	us := newUserSession("userID", "channelID", "connID", "callID", true)
+
+	// The initial offer isn't a restart.
+	p.handleClientICERestart(us, newOffer("ufragA"))
+	require.Equal(t, "ufragA", *us.iceUfrag.Load())
+
+	// Renegotiating with the same credentials isn't a restart either.
+	p.handleClientICERestart(us, newOffer("ufragA"))
+
+	mockAPI.On("LogDebug", "client restarting ICE",
+		"origin", mock.Anything, "userID", "userID", "connID", "connID", "channelID", "channelID",
+		"callID", "callID").Once()
+	mockMetrics.On("IncICERestarts", iceRestartInitiatorClient).Once()
+
+	p.handleClientICERestart(us, newOffer("ufragB"))
+	require.Equal(t, "ufragB", *us.iceUfrag.Load())
+}
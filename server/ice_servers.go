@@ -0,0 +1,71 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/mattermost/rtcd/service/rtc"
+)
+
+// getUserICEServers returns the ICE servers the given user should use for
+// calls in the channel, including credentials for the TURN servers that need
+// them. Credentials are generated on each call, never cached, so that they
+// are valid for the whole expiration period from the time they are requested.
+// The returned bool is whether any credentials were generated.
+func (p *Plugin) getUserICEServers(userID, channelID string) (ICEServersConfigs, bool, error) {
+	cfg := p.getConfiguration()
+	iceServers, turnSecret := cfg.getChannelICEServers(channelID, true)
+	allServers, _ := cfg.getChannelICEServers(channelID, false)
+
+	var hasCredentials bool
+	if turnServers := allServers.getTURNConfigsForCredentials(); turnSecret != "" && len(turnServers) > 0 {
+		user, appErr := p.API.GetUser(userID)
+		if appErr != nil {
+			return nil, false, fmt.Errorf("failed to get user: %w", appErr)
+		}
+
+		configs, err := rtc.GenTURNConfigs(turnServers, user.Username, turnSecret, *cfg.TURNCredentialsExpirationMinutes)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to generate TURN credentials: %w", err)
+		}
+		iceServers = append(iceServers, configs...)
+		hasCredentials = true
+	}
+
+	if iceServers == nil {
+		iceServers = ICEServersConfigs{}
+	}
+
+	return iceServers, hasCredentials, nil
+}
+
+// publishICEServers sends freshly generated ICE servers credentials to the
+// session. It's needed on reconnect as the credentials the client got when
+// joining may have expired in the meantime, causing the ICE restart to fail.
+func (p *Plugin) publishICEServers(us *session) {
+	iceServers, hasCredentials, err := p.getUserICEServers(us.userID, us.getChannelID())
+	if err != nil {
+		p.LogError("failed to get ICE servers", "err", err.Error(), "userID", us.userID, "connID", us.connID)
+		return
+	}
+
+	// Clients keep using the configured servers as they are unless there are
+	// credentials to refresh.
+	if !hasCredentials {
+		return
+	}
+
+	iceServersData, err := json.Marshal(iceServers)
+	if err != nil {
+		p.LogError("failed to marshal ICE servers", "err", err.Error())
+		return
+	}
+
+	p.publishWebSocketEvent(wsEventICEServers, map[string]interface{}{
+		"call_id":     us.callID,
+		"ice_servers": string(iceServersData),
+	}, &WebSocketBroadcast{ConnectionID: us.connID, ReliableClusterSend: true})
+}
@@ -0,0 +1,125 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	serverMocks "github.com/mattermost/mattermost-plugin-calls/server/mocks/github.com/mattermost/mattermost-plugin-calls/server/interfaces"
+	pluginMocks "github.com/mattermost/mattermost-plugin-calls/server/mocks/github.com/mattermost/mattermost/server/public/plugin"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin"
+
+	"github.com/mattermost/rtcd/service/rtc"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// getTURNCredentialsExpiration returns the expiration time encoded in the
+// username of TURN credentials.
+func getTURNCredentialsExpiration(t *testing.T, username string) time.Time {
+	t.Helper()
+	ts, _, ok := strings.Cut(username, ":")
+	require.True(t, ok)
+	expiration, err := strconv.ParseInt(ts, 10, 64)
+	require.NoError(t, err)
+	return time.Unix(expiration, 0)
+}
+
+func TestGetUserICEServers(t *testing.T) {
+	mockAPI := &pluginMocks.MockAPI{}
+	defer mockAPI.AssertExpectations(t)
+
+	var cfg configuration
+	cfg.SetDefaults()
+	p := &Plugin{
+		MattermostPlugin: plugin.MattermostPlugin{
+			API: mockAPI,
+		},
+		configuration: &cfg,
+	}
+
+	t.Run("no credentials needed", func(t *testing.T) {
+		iceServers, hasCredentials, err := p.getUserICEServers("userID", "channelID")
+		require.NoError(t, err)
+		require.False(t, hasCredentials)
+		require.Empty(t, iceServers)
+	})
+
+	t.Run("fresh credentials", func(t *testing.T) {
+		cfg.TURNStaticAuthSecret = "secret"
+		cfg.ICEServersConfigs = ICEServersConfigs{
+			{URLs: []string{"stun:stun.example.org"}},
+			{URLs: []string{"turn:turn.example.org"}},
+		}
+		defer func() {
+			cfg.TURNStaticAuthSecret = ""
+			cfg.ICEServersConfigs = nil
+		}()
+
+		mockAPI.On("GetUser", "userID").Return(&model.User{Id: "userID", Username: "username"}, nil).Once()
+
+		iceServers, hasCredentials, err := p.getUserICEServers("userID", "channelID")
+		require.NoError(t, err)
+		require.True(t, hasCredentials)
+		require.Len(t, iceServers, 2)
+		require.Equal(t, []string{"stun:stun.example.org"}, iceServers[0].URLs)
+		require.Equal(t, []string{"turn:turn.example.org"}, iceServers[1].URLs)
+		require.NotEmpty(t, iceServers[1].Credential)
+		require.True(t, getTURNCredentialsExpiration(t, iceServers[1].Username).After(time.Now()))
+	})
+}
+
+func TestPublishICEServersAfterCredentialsExpiry(t *testing.T) {
+	mockAPI := &pluginMocks.MockAPI{}
+	mockMetrics := &serverMocks.MockMetrics{}
+	defer mockAPI.AssertExpectations(t)
+	defer mockMetrics.AssertExpectations(t)
+
+	var cfg configuration
+	cfg.SetDefaults()
+	cfg.TURNStaticAuthSecret = "secret"
+	cfg.TURNCredentialsExpirationMinutes = model.NewPointer(1)
+	cfg.ICEServersConfigs = ICEServersConfigs{{URLs: []string{"turn:turn.example.org"}}}
+	p := &Plugin{
+		MattermostPlugin: plugin.MattermostPlugin{
+			API: mockAPI,
+		},
+		metrics:       mockMetrics,
+		configuration: &cfg,
+	}
+
+	// Credentials the client got when joining, which have since expired.
+	joinConfigs, err := rtc.GenTURNConfigs(cfg.ICEServersConfigs.getTURNConfigsForCredentials(), "username", cfg.TURNStaticAuthSecret, -5)
+	require.NoError(t, err)
+	require.Len(t, joinConfigs, 1)
+	require.True(t, getTURNCredentialsExpiration(t, joinConfigs[0].Username).Before(time.Now()))
+
+	us := newUserSession("userID", "channelID", "connB", "callID", false)
+	us.originalConnID = "connA"
+
+	mockAPI.On("GetUser", "userID").Return(&model.User{Id: "userID", Username: "username"}, nil).Once()
+	mockMetrics.On("IncWebSocketEvent", "out", wsEventICEServers).Once()
+
+	var iceServers []rtc.ICEServerConfig
+	mockAPI.On("PublishWebSocketEvent", wsEventICEServers, mock.AnythingOfType("map[string]interface {}"),
+		&model.WebsocketBroadcast{ConnectionId: "connB", ReliableClusterSend: true}).Run(func(args mock.Arguments) {
+		data := args.Get(1).(map[string]any)
+		require.Equal(t, "callID", data["call_id"])
+		require.NoError(t, json.Unmarshal([]byte(data["ice_servers"].(string)), &iceServers))
+	}).Once()
+
+	p.publishICEServers(us)
+
+	require.Len(t, iceServers, 1)
+	require.NotEqual(t, joinConfigs[0].Username, iceServers[0].Username)
+	require.NotEqual(t, joinConfigs[0].Credential, iceServers[0].Credential)
+	require.True(t, getTURNCredentialsExpiration(t, iceServers[0].Username).After(time.Now()))
+}
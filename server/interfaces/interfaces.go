@@ -32,15 +32,43 @@ type Metrics interface {
 	IncLiveCaptionsWindowDropped()
 	IncLiveCaptionsTranscriberBufFull()
 	IncLiveCaptionsPktPayloadChBufFull()
+	SetTranscriptionQueueDepth(depth int)
+	ObserveTranscriptionQueueWaitTime(elapsed float64)
 	ObserveAppHandlersTime(handler string, elapsed float64)
 	ObserveStoreMethodsTime(method string, elapsed float64)
+	IncStoreErrors(method, errType string)
+	SetStoreBufferedWrites(count int)
 	RegisterDBMetrics(db *sql.DB, name string)
 	IncClientICECandidatePairs(p public.ClientICECandidatePairMetricPayload)
+	IncICEConnectionTimeouts()
+	IncICEConnectionFailures(candidateType, platform string)
+	IncZombieSessions()
+	IncICEConnections(state string)
+	IncICERestarts(initiator string)
+	IncThrottledSessions()
+	IncNoiseAutoMutes()
+	IncRTCDMessageRetries(msgType string)
+	IncRTCDMessagesDropped(msgType string)
+	SetRTCDConnectionAge(host string, age float64)
+	SetRTCDLastPingTime(host string, ts float64)
+	DeleteRTCDHostMetrics(host string)
+	IncRejectedSDPs(reason string)
+	AddKeyFrameRequests(reqType string, count int)
+	ObserveClientJitterBufferDelay(delayMs float64)
+	ObserveClientPacketLoss(serverType string, lossRate float64)
+	ObserveCallFeedbackScore(score int)
+	ObserveWebSocketWriterMessage(msgType string, size int)
+	SetWebSocketWriterQueueDepth(depth int)
+	IncRecordingStorageQuotaExceeded(scope, policy string)
+	SetRecordingStorageBytes(bytes int64)
+	ObserveCleanUpStateTime(elapsed float64)
+	AddCleanUpStateReapedCalls(count int)
 }
 
 type StoreMetrics interface {
 	IncStoreOp(op string)
 	ObserveStoreMethodsTime(method string, elapsed float64)
+	IncStoreErrors(method, errType string)
 }
 
 type Store interface {
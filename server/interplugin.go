@@ -0,0 +1,178 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-calls/server/public"
+)
+
+const (
+	participantEventsQueueSize       = 256
+	participantEventsDeliveryTimeout = 5 * time.Second
+)
+
+type participantEventsSubscriber struct {
+	pluginID string
+	path     string
+	eventsCh chan public.ParticipantEvent
+	stopCh   chan struct{}
+}
+
+// handleInterPluginSubscribe lets other plugins subscribe to participant
+// join/leave events. Subscriptions are kept in memory by the plugin instance
+// that received the request so plugins should subscribe upon activation.
+func (p *Plugin) handleInterPluginSubscribe(w http.ResponseWriter, r *http.Request) {
+	var res httpResponse
+	defer p.httpAudit("handleInterPluginSubscribe", &res, w, r)
+
+	pluginID := r.Header.Get("Mattermost-Plugin-ID")
+
+	var sub public.ParticipantEventsSubscription
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, requestBodyMaxSizeBytes)).Decode(&sub); err != nil {
+		res.Err = "failed to decode request body: " + err.Error()
+		res.Code = http.StatusBadRequest
+		return
+	}
+
+	if err := sub.IsValid(); err != nil {
+		res.Err = err.Error()
+		res.Code = http.StatusBadRequest
+		return
+	}
+
+	subscriber := &participantEventsSubscriber{
+		pluginID: pluginID,
+		path:     sub.Path,
+		eventsCh: make(chan public.ParticipantEvent, participantEventsQueueSize),
+		stopCh:   make(chan struct{}),
+	}
+
+	p.participantEventsSubsMut.Lock()
+	// A plugin can only have a single active subscription. Subscribing again
+	// (e.g. after a restart) replaces the previous one.
+	if prev := p.participantEventsSubs[pluginID]; prev != nil {
+		close(prev.stopCh)
+	}
+	p.participantEventsSubs[pluginID] = subscriber
+	p.participantEventsSubsMut.Unlock()
+
+	go p.participantEventsSender(subscriber)
+
+	p.LogDebug("plugin subscribed to participant events", "pluginID", pluginID, "path", sub.Path)
+
+	res.Code = http.StatusOK
+	res.Msg = "success"
+}
+
+func (p *Plugin) handleInterPluginUnsubscribe(w http.ResponseWriter, r *http.Request) {
+	var res httpResponse
+	defer p.httpAudit("handleInterPluginUnsubscribe", &res, w, r)
+
+	pluginID := r.Header.Get("Mattermost-Plugin-ID")
+
+	p.participantEventsSubsMut.Lock()
+	defer p.participantEventsSubsMut.Unlock()
+
+	subscriber := p.participantEventsSubs[pluginID]
+	if subscriber == nil {
+		res.Err = "subscription not found"
+		res.Code = http.StatusNotFound
+		return
+	}
+
+	close(subscriber.stopCh)
+	delete(p.participantEventsSubs, pluginID)
+
+	p.LogDebug("plugin unsubscribed from participant events", "pluginID", pluginID)
+
+	res.Code = http.StatusOK
+	res.Msg = "success"
+}
+
+// publishParticipantEvent queues the event for delivery to all the subscribed
+// plugins. It never blocks: if a subscriber's queue is full the event is
+// dropped for that subscriber.
+func (p *Plugin) publishParticipantEvent(evType public.ParticipantEventType, channelID, callID, userID, sessionID string, callMetadata map[string]string) {
+	if userID == p.getBotID() {
+		return
+	}
+
+	ev := public.ParticipantEvent{
+		Type:      evType,
+		ChannelID: channelID,
+		CallID:    callID,
+		UserID:    userID,
+		SessionID: sessionID,
+		CreateAt:  time.Now().UnixMilli(),
+
+		CallMetadata: callMetadata,
+	}
+
+	p.queueParticipantWebhookEvent(ev)
+
+	p.participantEventsSubsMut.RLock()
+	defer p.participantEventsSubsMut.RUnlock()
+
+	for _, subscriber := range p.participantEventsSubs {
+		select {
+		case subscriber.eventsCh <- ev:
+		default:
+			p.LogWarn("participant events queue is full, dropping event",
+				"pluginID", subscriber.pluginID, "type", string(evType), "callID", callID)
+		}
+	}
+}
+
+func (p *Plugin) participantEventsSender(subscriber *participantEventsSubscriber) {
+	for {
+		select {
+		case ev := <-subscriber.eventsCh:
+			if err := p.sendParticipantEvent(subscriber, ev); err != nil {
+				p.LogWarn("failed to deliver participant event", "pluginID", subscriber.pluginID,
+					"type", string(ev.Type), "callID", ev.CallID, "err", err.Error())
+			}
+		case <-subscriber.stopCh:
+			return
+		case <-p.stopCh:
+			return
+		}
+	}
+}
+
+func (p *Plugin) sendParticipantEvent(subscriber *participantEventsSubscriber, ev public.ParticipantEvent) error {
+	data, err := json.Marshal(ev)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), participantEventsDeliveryTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "/"+subscriber.pluginID+subscriber.path, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp := p.API.PluginHTTP(req)
+	if resp == nil {
+		return fmt.Errorf("empty response")
+	}
+	if resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	return nil
+}
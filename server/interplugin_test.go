@@ -0,0 +1,166 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-calls/server/public"
+
+	serverMocks "github.com/mattermost/mattermost-plugin-calls/server/mocks/github.com/mattermost/mattermost-plugin-calls/server/interfaces"
+	pluginMocks "github.com/mattermost/mattermost-plugin-calls/server/mocks/github.com/mattermost/mattermost/server/public/plugin"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+)
+
+func TestParticipantEvents(t *testing.T) {
+	mockAPI := &pluginMocks.MockAPI{}
+	mockMetrics := &serverMocks.MockMetrics{}
+
+	p := Plugin{
+		MattermostPlugin: plugin.MattermostPlugin{
+			API: mockAPI,
+		},
+		metrics:               mockMetrics,
+		apiLimiters:           map[string]*rate.Limiter{},
+		stopCh:                make(chan struct{}),
+		participantEventsSubs: map[string]*participantEventsSubscriber{},
+	}
+	defer close(p.stopCh)
+
+	mockMetrics.On("Handler").Return(nil).Once()
+	apiRouter := p.newAPIRouter()
+
+	for _, handler := range []string{"handleInterPluginSubscribe", "handleInterPluginUnsubscribe"} {
+		for _, n := range []int{16, 18} {
+			logArgs := []any{handler}
+			for i := 0; i < n; i++ {
+				logArgs = append(logArgs, mock.Anything)
+			}
+			mockAPI.On("LogDebug", logArgs...)
+		}
+	}
+
+	t.Run("missing plugin ID", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("POST", "/interplugin/subscriptions/participants", strings.NewReader(`{"path": "/events"}`))
+
+		apiRouter.ServeHTTP(w, r)
+
+		resp := w.Result()
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("user session", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("POST", "/interplugin/subscriptions/participants", strings.NewReader(`{"path": "/events"}`))
+		r.Header.Set("Mattermost-User-Id", model.NewId())
+
+		apiRouter.ServeHTTP(w, r)
+
+		resp := w.Result()
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusForbidden, resp.StatusCode)
+		require.Empty(t, p.participantEventsSubs)
+
+		w = httptest.NewRecorder()
+		r = httptest.NewRequest("DELETE", "/interplugin/subscriptions/participants", nil)
+		r.Header.Set("Mattermost-User-Id", model.NewId())
+
+		apiRouter.ServeHTTP(w, r)
+
+		resp = w.Result()
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("invalid subscription", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("POST", "/interplugin/subscriptions/participants", strings.NewReader(`{"path": "events"}`))
+		r.Header.Set("Mattermost-Plugin-ID", "com.example.plugin")
+
+		apiRouter.ServeHTTP(w, r)
+
+		resp := w.Result()
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("subscribe and receive events", func(t *testing.T) {
+		mockAPI.On("LogDebug", "plugin subscribed to participant events",
+			"origin", mock.Anything, "pluginID", "com.example.plugin", "path", "/events").Once()
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("POST", "/interplugin/subscriptions/participants", strings.NewReader(`{"path": "/events"}`))
+		r.Header.Set("Mattermost-Plugin-ID", "com.example.plugin")
+
+		apiRouter.ServeHTTP(w, r)
+
+		resp := w.Result()
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		evCh := make(chan public.ParticipantEvent, 1)
+		mockAPI.On("PluginHTTP", mock.MatchedBy(func(req *http.Request) bool {
+			return req.URL.Path == "/com.example.plugin/events"
+		})).Run(func(args mock.Arguments) {
+			req := args.Get(0).(*http.Request)
+			var ev public.ParticipantEvent
+			require.NoError(t, json.NewDecoder(req.Body).Decode(&ev))
+			evCh <- ev
+		}).Return(&http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(""))}).Once()
+
+		userID := model.NewId()
+		p.publishParticipantEvent(public.ParticipantEventTypeJoin, "channelID", "callID", userID, "sessionID", nil)
+
+		select {
+		case ev := <-evCh:
+			require.Equal(t, public.ParticipantEventTypeJoin, ev.Type)
+			require.Equal(t, "channelID", ev.ChannelID)
+			require.Equal(t, "callID", ev.CallID)
+			require.Equal(t, userID, ev.UserID)
+			require.Equal(t, "sessionID", ev.SessionID)
+		case <-time.After(5 * time.Second):
+			require.Fail(t, "timed out waiting for event")
+		}
+	})
+
+	t.Run("unsubscribe", func(t *testing.T) {
+		mockAPI.On("LogDebug", "plugin unsubscribed from participant events",
+			"origin", mock.Anything, "pluginID", "com.example.plugin").Once()
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("DELETE", "/interplugin/subscriptions/participants", nil)
+		r.Header.Set("Mattermost-Plugin-ID", "com.example.plugin")
+
+		apiRouter.ServeHTTP(w, r)
+
+		resp := w.Result()
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Empty(t, p.participantEventsSubs)
+
+		w = httptest.NewRecorder()
+		r = httptest.NewRequest("DELETE", "/interplugin/subscriptions/participants", nil)
+		r.Header.Set("Mattermost-Plugin-ID", "com.example.plugin")
+
+		apiRouter.ServeHTTP(w, r)
+
+		resp = w.Result()
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/url"
 	"os"
 	"strings"
 	"time"
@@ -35,6 +36,21 @@ var (
 	transcriberJobRunner = ""
 )
 
+// recorderLayoutKey is the job input key the recorder reads the layout to
+// composite the recording with from.
+const recorderLayoutKey = "layout"
+
+// jobOptions holds per-job settings that aren't derived from the plugin's
+// configuration alone.
+type jobOptions struct {
+	// The quality profile to record with in place of RecordingQuality. Only
+	// applies to recording jobs.
+	RecordingQuality string
+	// The layout to composite the recording with. Only applies to recording
+	// jobs.
+	RecordingLayout string
+}
+
 var recorderBaseConfigs = map[string]recorder.RecorderConfig{
 	"low": {
 		Width:        1280,
@@ -65,6 +81,50 @@ var recorderBaseConfigs = map[string]recorder.RecorderConfig{
 	},
 }
 
+// recorderResolutions holds the supported values for the RecordingResolution
+// setting.
+var recorderResolutions = map[string]struct {
+	Width  int
+	Height int
+}{
+	"720p":  {Width: 1280, Height: 720},
+	"1080p": {Width: 1920, Height: 1080},
+	"1440p": {Width: 2560, Height: 1440},
+	"2160p": {Width: 3840, Height: 2160},
+}
+
+// getRecorderConfig returns the recorder output config for the configured
+// quality with any resolution, framerate or format override applied on top.
+func getRecorderConfig(cfg *configuration) recorder.RecorderConfig {
+	return getRecorderConfigForQuality(cfg, cfg.RecordingQuality)
+}
+
+// getRecorderConfigForQuality returns the recorder output config for the given
+// quality profile. Resolution and framerate overrides only apply to the main
+// RecordingQuality so that additional profiles keep their own.
+func getRecorderConfigForQuality(cfg *configuration, quality string) recorder.RecorderConfig {
+	recCfg := recorderBaseConfigs[quality]
+
+	if cfg.RecordingOutputFormat != "" {
+		recCfg.OutputFormat = recorder.AVFormat(cfg.RecordingOutputFormat)
+	}
+
+	if quality != cfg.RecordingQuality {
+		return recCfg
+	}
+
+	if res, ok := recorderResolutions[cfg.RecordingResolution]; ok {
+		recCfg.Width = res.Width
+		recCfg.Height = res.Height
+	}
+
+	if cfg.RecordingFrameRate != nil && *cfg.RecordingFrameRate > 0 {
+		recCfg.FrameRate = *cfg.RecordingFrameRate
+	}
+
+	return recCfg
+}
+
 type jobService struct {
 	ctx    *Plugin
 	client *offloader.Client
@@ -188,7 +248,16 @@ func (p *Plugin) newJobService(serviceURL string) (*jobService, error) {
 		return nil, fmt.Errorf("failed to get job service client config: %w", err)
 	}
 
-	client, err := offloader.NewClient(cfg)
+	var opts []offloader.ClientOption
+	if proxyURL := p.getConfiguration().getOutboundProxyURL(); proxyURL != nil {
+		u, err := url.Parse(cfg.URL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse job service URL: %w", err)
+		}
+		opts = append(opts, offloader.WithDialFunc(getProxyDialFn(proxyURL, getURLHostPort(u))))
+	}
+
+	client, err := offloader.NewClient(cfg, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create client: %w", err)
 	}
@@ -234,6 +303,41 @@ func (s *jobService) StopJob(channelID, jobID, botUserID, botConnID string) erro
 	return nil
 }
 
+// PauseJob tells the job to stop capturing until ResumeJob is called. The
+// recording view blanks the video and silences the audio in the meantime so
+// that the recorder keeps writing to the same output.
+func (s *jobService) PauseJob(jobID, botUserID string) error {
+	if jobID == "" {
+		return fmt.Errorf("jobID should not be empty")
+	}
+
+	if botUserID == "" {
+		return fmt.Errorf("botUserID should not be empty")
+	}
+
+	s.ctx.publishWebSocketEvent(wsEventJobPause, map[string]interface{}{
+		"job_id": jobID,
+	}, &WebSocketBroadcast{UserID: botUserID, ReliableClusterSend: true})
+
+	return nil
+}
+
+func (s *jobService) ResumeJob(jobID, botUserID string) error {
+	if jobID == "" {
+		return fmt.Errorf("jobID should not be empty")
+	}
+
+	if botUserID == "" {
+		return fmt.Errorf("botUserID should not be empty")
+	}
+
+	s.ctx.publishWebSocketEvent(wsEventJobResume, map[string]interface{}{
+		"job_id": jobID,
+	}, &WebSocketBroadcast{UserID: botUserID, ReliableClusterSend: true})
+
+	return nil
+}
+
 func (s *jobService) Init(runners []string) error {
 	if len(runners) == 0 {
 		return fmt.Errorf("unexpected empty runners")
@@ -258,7 +362,7 @@ func (s *jobService) Init(runners []string) error {
 	})
 }
 
-func (s *jobService) RunJob(jobType job.Type, callID, postID, jobID, authToken string) (string, error) {
+func (s *jobService) RunJob(jobType job.Type, callID, postID, jobID, authToken string, opts jobOptions) (string, error) {
 	cfg := s.ctx.getConfiguration()
 	if cfg == nil {
 		return "", fmt.Errorf("failed to get plugin configuration")
@@ -283,7 +387,10 @@ func (s *jobService) RunJob(jobType job.Type, callID, postID, jobID, authToken s
 
 	switch jobType {
 	case job.TypeRecording:
-		baseRecorderCfg := recorderBaseConfigs[cfg.RecordingQuality]
+		baseRecorderCfg := getRecorderConfig(cfg)
+		if opts.RecordingQuality != "" {
+			baseRecorderCfg = getRecorderConfigForQuality(cfg, opts.RecordingQuality)
+		}
 		baseRecorderCfg.SiteURL = siteURL
 		if siteURLOverride := os.Getenv("MM_CALLS_RECORDER_SITE_URL"); siteURLOverride != "" {
 			s.ctx.LogInfo("using SiteURL override for recorder job", "siteURL", siteURL, "siteURLOverride", siteURLOverride)
@@ -301,6 +408,9 @@ func (s *jobService) RunJob(jobType job.Type, callID, postID, jobID, authToken s
 		jobCfg.Runner = recorderJobRunner
 		jobCfg.MaxDurationSec = int64(*cfg.MaxRecordingDuration * 60)
 		jobCfg.InputData = baseRecorderCfg.ToMap()
+		if opts.RecordingLayout != "" {
+			jobCfg.InputData[recorderLayoutKey] = opts.RecordingLayout
+		}
 	case job.TypeTranscribing:
 		var transcriberConfig transcriber.CallTranscriberConfig
 		transcriberConfig.SetDefaults()
@@ -85,3 +85,55 @@ func TestJobServiceStopJob(t *testing.T) {
 		require.NoError(t, err)
 	})
 }
+
+func TestGetRecorderConfig(t *testing.T) {
+	t.Run("quality defaults", func(t *testing.T) {
+		var cfg configuration
+		cfg.SetDefaults()
+		cfg.RecordingQuality = "high"
+
+		recCfg := getRecorderConfig(&cfg)
+		require.Equal(t, recorderBaseConfigs["high"], recCfg)
+	})
+
+	t.Run("overrides", func(t *testing.T) {
+		var cfg configuration
+		cfg.SetDefaults()
+		cfg.RecordingQuality = "low"
+		cfg.RecordingResolution = "1080p"
+		cfg.RecordingFrameRate = model.NewPointer(30)
+		cfg.RecordingOutputFormat = "mp4"
+
+		recCfg := getRecorderConfig(&cfg)
+		recCfg.SiteURL = "http://localhost:8065"
+		recCfg.CallID = model.NewId()
+		recCfg.PostID = model.NewId()
+		recCfg.RecordingID = model.NewId()
+		recCfg.AuthToken = model.NewId()
+		require.NoError(t, recCfg.IsValid())
+
+		// This is what gets passed to the job as input data.
+		inputData := recCfg.ToMap()
+		require.Equal(t, 1920, inputData["width"])
+		require.Equal(t, 1080, inputData["height"])
+		require.Equal(t, 30, inputData["frame_rate"])
+		require.EqualValues(t, "mp4", inputData["output_format"])
+		require.Equal(t, recorderBaseConfigs["low"].VideoRate, inputData["video_rate"])
+	})
+
+	t.Run("additional quality", func(t *testing.T) {
+		var cfg configuration
+		cfg.SetDefaults()
+		cfg.RecordingQuality = "high"
+		cfg.RecordingResolution = "1080p"
+		cfg.RecordingFrameRate = model.NewPointer(30)
+		cfg.RecordingAdditionalQualities = "low"
+
+		// Overrides only apply to the main quality.
+		recCfg := getRecorderConfigForQuality(&cfg, "low")
+		require.Equal(t, recorderBaseConfigs["low"].Width, recCfg.Width)
+		require.Equal(t, recorderBaseConfigs["low"].Height, recCfg.Height)
+		require.Equal(t, recorderBaseConfigs["low"].FrameRate, recCfg.FrameRate)
+		require.Equal(t, []string{"low"}, cfg.getRecordingAdditionalQualities())
+	})
+}
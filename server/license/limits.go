@@ -0,0 +1,53 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package license
+
+import (
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+const (
+	CloudStarterMaxParticipants    = 8
+	CloudStarterMaxConcurrentCalls = 5
+	CloudPaidMaxParticipants       = 200
+)
+
+// Limits holds the call limits that apply to a license tier. The zero value
+// for a limit means there's no limit.
+type Limits struct {
+	// The maximum number of participants in a single call.
+	MaxParticipants int
+	// The maximum number of calls that can be ongoing at the same time.
+	MaxConcurrentCalls int
+}
+
+// GetLimits returns the call limits for the given license.
+func GetLimits(license *model.License) Limits {
+	if !IsCloud(license) {
+		return Limits{}
+	}
+
+	if IsCloudStarter(license) {
+		return Limits{
+			MaxParticipants:    CloudStarterMaxParticipants,
+			MaxConcurrentCalls: CloudStarterMaxConcurrentCalls,
+		}
+	}
+
+	return Limits{
+		MaxParticipants: CloudPaidMaxParticipants,
+	}
+}
+
+// ParticipantsAllowed returns true if a call with the given number of
+// participants can accept a new one.
+func (l Limits) ParticipantsAllowed(count int) bool {
+	return l.MaxParticipants == 0 || count < l.MaxParticipants
+}
+
+// CallsAllowed returns true if a new call can be started given the number of
+// ongoing calls.
+func (l Limits) CallsAllowed(count int) bool {
+	return l.MaxConcurrentCalls == 0 || count < l.MaxConcurrentCalls
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package license
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetLimits(t *testing.T) {
+	cloudLicense := func(sku string) *model.License {
+		return &model.License{
+			SkuShortName: sku,
+			Features: &model.Features{
+				Cloud: model.NewPointer(true),
+			},
+		}
+	}
+
+	t.Run("unlicensed", func(t *testing.T) {
+		require.Equal(t, Limits{}, GetLimits(nil))
+	})
+
+	t.Run("self-hosted", func(t *testing.T) {
+		require.Equal(t, Limits{}, GetLimits(&model.License{SkuShortName: enterprise}))
+	})
+
+	t.Run("cloud starter", func(t *testing.T) {
+		require.Equal(t, Limits{
+			MaxParticipants:    CloudStarterMaxParticipants,
+			MaxConcurrentCalls: CloudStarterMaxConcurrentCalls,
+		}, GetLimits(cloudLicense("starter")))
+	})
+
+	t.Run("cloud paid", func(t *testing.T) {
+		require.Equal(t, Limits{
+			MaxParticipants: CloudPaidMaxParticipants,
+		}, GetLimits(cloudLicense(professional)))
+	})
+}
+
+func TestLimitsBoundaries(t *testing.T) {
+	t.Run("no limits", func(t *testing.T) {
+		var l Limits
+		require.True(t, l.ParticipantsAllowed(1000))
+		require.True(t, l.CallsAllowed(1000))
+	})
+
+	t.Run("participants", func(t *testing.T) {
+		l := Limits{MaxParticipants: 8}
+		require.True(t, l.ParticipantsAllowed(7))
+		require.False(t, l.ParticipantsAllowed(8))
+		require.False(t, l.ParticipantsAllowed(9))
+	})
+
+	t.Run("concurrent calls", func(t *testing.T) {
+		l := Limits{MaxConcurrentCalls: 5}
+		require.True(t, l.CallsAllowed(4))
+		require.False(t, l.CallsAllowed(5))
+		require.False(t, l.CallsAllowed(6))
+	})
+}
@@ -0,0 +1,176 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+const (
+	// The time, on top of MaxRecordingDuration, jobs started before the
+	// license lapsed are given to complete (e.g. upload their output).
+	licenseLapseJobsGracePeriod = time.Hour
+	licenseLapseWarningBackoff  = time.Hour
+)
+
+// How often the license entitlements are checked again after activation.
+var licenseCheckInterval = 5 * time.Minute
+
+// licenseEntitlements holds the license gated features the plugin relies on
+// while running.
+type licenseEntitlements struct {
+	rtcd           bool
+	recordings     bool
+	transcriptions bool
+	groupCalls     bool
+}
+
+func (p *Plugin) getLicenseEntitlements() licenseEntitlements {
+	return licenseEntitlements{
+		rtcd:           p.licenseChecker.RTCDAllowed(),
+		recordings:     p.licenseChecker.RecordingsAllowed(),
+		transcriptions: p.licenseChecker.TranscriptionsAllowed(),
+		groupCalls:     p.licenseChecker.GroupCallsAllowed(),
+	}
+}
+
+// licenseMonitor periodically checks the license so that features lapsing
+// while the plugin is running are handled gracefully.
+func (p *Plugin) licenseMonitor() {
+	ticker := time.NewTicker(licenseCheckInterval)
+	defer ticker.Stop()
+
+	entitlements := p.getLicenseEntitlements()
+
+	for {
+		select {
+		case <-ticker.C:
+			entitlements = p.checkLicenseEntitlements(entitlements)
+		case <-p.stopCh:
+			return
+		}
+	}
+}
+
+// checkLicenseEntitlements compares the current license entitlements with the
+// previous ones. Features checked on use (e.g. starting a recording) are
+// already refused once lapsed, ongoing calls and jobs are allowed to finish.
+// Admins get notified about any feature in use that lapsed.
+func (p *Plugin) checkLicenseEntitlements(prev licenseEntitlements) licenseEntitlements {
+	cur := p.getLicenseEntitlements()
+	cfg := p.getConfiguration()
+
+	var lapsed []string
+	if prev.rtcd && !cur.rtcd && p.rtcdManager != nil {
+		lapsed = append(lapsed, "rtcd")
+	}
+	if prev.recordings && !cur.recordings {
+		p.recordingsLicenseLapsedAt.Store(time.Now().UnixMilli())
+		if cfg.recordingsEnabled() {
+			lapsed = append(lapsed, "recordings")
+		}
+	} else if !prev.recordings && cur.recordings {
+		p.recordingsLicenseLapsedAt.Store(0)
+	}
+	if prev.transcriptions && !cur.transcriptions && cfg.transcriptionsEnabled() {
+		lapsed = append(lapsed, "transcriptions")
+	}
+	if prev.groupCalls && !cur.groupCalls {
+		lapsed = append(lapsed, "group_calls")
+	}
+
+	if len(lapsed) > 0 {
+		p.LogWarn("license no longer allows some of the features in use, ongoing calls will be allowed to finish",
+			"features", strings.Join(lapsed, ","))
+
+		ok, appErr := p.API.KVSetWithOptions("license_lapsed_warning", []byte{1}, model.PluginKVSetOptions{
+			Atomic:          true,
+			OldValue:        nil,
+			ExpireInSeconds: int64(licenseLapseWarningBackoff.Seconds()),
+		})
+		if appErr != nil {
+			p.LogError("failed to set kv", "err", appErr.Error())
+		} else if ok {
+			// Only one node notifies admins.
+			if err := p.sendLicenseLapsedWarning(lapsed); err != nil {
+				p.LogError("failed to send license lapsed warning", "err", err.Error())
+			}
+		}
+	}
+
+	if cur != prev && len(lapsed) == 0 {
+		p.LogInfo("license entitlements have changed", "rtcd", cur.rtcd, "recordings", cur.recordings,
+			"transcriptions", cur.transcriptions, "groupCalls", cur.groupCalls)
+	}
+
+	return cur
+}
+
+func (p *Plugin) sendLicenseLapsedWarning(features []string) error {
+	admins, appErr := p.API.GetUsers(&model.UserGetOptions{
+		Role:    model.SystemAdminRoleId,
+		Page:    0,
+		PerPage: maxAdminsToQueryForNotification,
+	})
+	if appErr != nil {
+		return fmt.Errorf("failed to get admin users: %w", appErr)
+	} else if len(admins) == 0 {
+		return fmt.Errorf("no admin user found")
+	}
+
+	botID := p.getBotID()
+
+	for _, admin := range admins {
+		dm, appErr := p.API.GetDirectChannel(admin.Id, botID)
+		if appErr != nil {
+			p.LogError("failed to get dm between admin and bot",
+				"userID", admin.Id, "botID", botID, "err", appErr.Error())
+			continue
+		}
+
+		T := p.getTranslationFunc(admin.Locale)
+
+		msg := T("app.admin.license_lapsed_warning.intro")
+		msg += "\r\n"
+		for _, feature := range features {
+			msg += "\r\n- " + T("app.admin.license_lapsed_warning."+feature)
+		}
+		msg += "\r\n\r\n"
+		msg += T("app.admin.license_lapsed_warning.outro")
+
+		post := &model.Post{
+			Message:   ":warning: " + msg,
+			UserId:    botID,
+			ChannelId: dm.Id,
+		}
+
+		if _, appErr := p.API.CreatePost(post); appErr != nil {
+			p.LogError("failed to create warning post",
+				"userID", admin.Id, "botID", botID, "err", appErr.Error())
+		}
+	}
+
+	return nil
+}
+
+// jobsMayFinishAfterLicenseLapse returns whether jobs started before the
+// recordings entitlement lapsed may still be running, in which case they are
+// allowed to complete.
+func (p *Plugin) jobsMayFinishAfterLicenseLapse() bool {
+	lapsedAt := p.recordingsLicenseLapsedAt.Load()
+	if lapsedAt == 0 {
+		return false
+	}
+
+	window := licenseLapseJobsGracePeriod
+	if cfg := p.getConfiguration(); cfg.MaxRecordingDuration != nil {
+		window += time.Duration(*cfg.MaxRecordingDuration) * time.Minute
+	}
+
+	return time.Since(time.UnixMilli(lapsedAt)) < window
+}
@@ -0,0 +1,143 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-calls/server/enterprise"
+
+	pluginMocks "github.com/mattermost/mattermost-plugin-calls/server/mocks/github.com/mattermost/mattermost/server/public/plugin"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckLicenseEntitlements(t *testing.T) {
+	cfg := &configuration{}
+	cfg.SetDefaults()
+	cfg.EnableRecordings = model.NewPointer(true)
+
+	p := Plugin{
+		configuration: cfg,
+		botSession: &model.Session{
+			UserId: "botID",
+		},
+	}
+
+	// The license can be fetched multiple times per check.
+	setupMockAPI := func(t *testing.T, license *model.License) *pluginMocks.MockAPI {
+		t.Helper()
+		mockAPI := &pluginMocks.MockAPI{}
+		t.Cleanup(func() { mockAPI.AssertExpectations(t) })
+		p.API = mockAPI
+		p.licenseChecker = enterprise.NewLicenseChecker(mockAPI)
+		mockAPI.On("GetConfig").Return(&model.Config{})
+		mockAPI.On("GetLicense").Return(license)
+		return mockAPI
+	}
+
+	licensed := licenseEntitlements{
+		rtcd:           true,
+		recordings:     true,
+		transcriptions: true,
+		groupCalls:     true,
+	}
+
+	t.Run("license expired", func(t *testing.T) {
+		mockAPI := setupMockAPI(t, nil)
+
+		mockAPI.On("LogWarn", "license no longer allows some of the features in use, ongoing calls will be allowed to finish",
+			"origin", mock.Anything, "features", "recordings,group_calls").Once()
+
+		mockAPI.On("KVSetWithOptions", "license_lapsed_warning", []byte{1}, mock.AnythingOfType("model.PluginKVSetOptions")).Return(true, nil).Once()
+
+		mockAPI.On("GetUsers", mock.AnythingOfType("*model.UserGetOptions")).Return([]*model.User{
+			{
+				Id:     "adminID",
+				Locale: "en",
+			},
+		}, nil).Once()
+
+		mockAPI.On("GetDirectChannel", "adminID", "botID").Return(&model.Channel{
+			Id: "channelID",
+		}, nil).Once()
+
+		mockAPI.On("CreatePost", &model.Post{
+			UserId:    "botID",
+			ChannelId: "channelID",
+			Message: ":warning: app.admin.license_lapsed_warning.intro\r\n" +
+				"\r\n- app.admin.license_lapsed_warning.recordings" +
+				"\r\n- app.admin.license_lapsed_warning.group_calls" +
+				"\r\n\r\napp.admin.license_lapsed_warning.outro",
+		}).Return(&model.Post{Id: "postID"}, nil).Once()
+
+		cur := p.checkLicenseEntitlements(licensed)
+		require.Equal(t, licenseEntitlements{}, cur)
+		require.NotZero(t, p.recordingsLicenseLapsedAt.Load())
+		require.True(t, p.jobsMayFinishAfterLicenseLapse())
+	})
+
+	t.Run("already notified", func(t *testing.T) {
+		mockAPI := setupMockAPI(t, nil)
+
+		mockAPI.On("LogWarn", "license no longer allows some of the features in use, ongoing calls will be allowed to finish",
+			"origin", mock.Anything, "features", "recordings,group_calls").Once()
+
+		mockAPI.On("KVSetWithOptions", "license_lapsed_warning", []byte{1}, mock.AnythingOfType("model.PluginKVSetOptions")).Return(false, nil).Once()
+
+		cur := p.checkLicenseEntitlements(licensed)
+		require.Equal(t, licenseEntitlements{}, cur)
+	})
+
+	t.Run("grace period elapsed", func(t *testing.T) {
+		p.recordingsLicenseLapsedAt.Store(time.Now().Add(-licenseLapseJobsGracePeriod - time.Duration(*cfg.MaxRecordingDuration)*time.Minute).UnixMilli())
+		require.False(t, p.jobsMayFinishAfterLicenseLapse())
+	})
+
+	t.Run("license renewed", func(t *testing.T) {
+		mockAPI := setupMockAPI(t, &model.License{SkuShortName: "enterprise"})
+
+		mockAPI.On("LogInfo", "license entitlements have changed", "origin", mock.Anything,
+			"rtcd", true, "recordings", true, "transcriptions", true, "groupCalls", true).Once()
+
+		cur := p.checkLicenseEntitlements(licenseEntitlements{})
+		require.Equal(t, licensed, cur)
+		require.Zero(t, p.recordingsLicenseLapsedAt.Load())
+		require.False(t, p.jobsMayFinishAfterLicenseLapse())
+	})
+}
+
+func TestNewCallAllowedLicenseLapsed(t *testing.T) {
+	mockAPI := &pluginMocks.MockAPI{}
+	defer mockAPI.AssertExpectations(t)
+
+	cfg := &configuration{}
+	cfg.SetDefaults()
+
+	p := Plugin{
+		MattermostPlugin: plugin.MattermostPlugin{
+			API: mockAPI,
+		},
+		licenseChecker: enterprise.NewLicenseChecker(mockAPI),
+		configuration:  cfg,
+		rtcdManager:    &rtcdClientManager{},
+	}
+
+	mockAPI.On("GetConfig").Return(&model.Config{})
+
+	t.Run("rtcd allowed", func(t *testing.T) {
+		mockAPI.On("GetLicense").Return(&model.License{SkuShortName: "enterprise"}).Once()
+		require.NoError(t, p.newCallAllowed())
+	})
+
+	t.Run("rtcd no longer allowed", func(t *testing.T) {
+		mockAPI.On("GetLicense").Return(nil).Twice()
+		require.Equal(t, errRTCDNotAllowed, p.newCallAllowed())
+	})
+}
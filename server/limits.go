@@ -17,13 +17,8 @@ import (
 	"github.com/mattermost/mattermost/server/public/model"
 )
 
-// cloudStarterMaxParticipantsDefault is set to 8.
-// The value used can be overridden by setting the MM_CALLS_MAX_PARTICIPANTS env variable.
-
 const (
-	cloudStarterMaxParticipantsDefault = 8
-	cloudPaidMaxParticipantsDefault    = 200
-	maxAdminsToQueryForNotification    = 25
+	maxAdminsToQueryForNotification = 25
 
 	// The value of concurrent sessions (globally) that will trigger a warning if the plugin is not using
 	// a dedicated rtcd service.
@@ -31,6 +26,77 @@ const (
 	concurrentSessionsWarningBackoffTimeDefault = 24 * 7 * time.Hour // 1 week
 )
 
+// getCallLimits returns the call limits in effect. On Cloud these are set
+// from the license tier (see setOverrides) while on self-hosted installations
+// they can be customized.
+func (c *configuration) getCallLimits() license.Limits {
+	var limits license.Limits
+	if c.MaxCallParticipants != nil {
+		limits.MaxParticipants = *c.MaxCallParticipants
+	}
+	if c.MaxConcurrentCalls != nil {
+		limits.MaxConcurrentCalls = *c.MaxConcurrentCalls
+	}
+	return limits
+}
+
+// getCallCapacity returns the participant limit in effect for the given call
+// (zero meaning unlimited) and whether the call is currently at capacity.
+// The limit can depend on the channel type so clients should rely on this
+// rather than resolving it themselves so that the check matches joinAllowed.
+func (p *Plugin) getCallCapacity(state *callState) (int, bool) {
+	limits := p.getConfiguration().getCallLimits()
+	if limit := state.Call.Props.MaxParticipants; limit > 0 && (limits.MaxParticipants == 0 || limit < limits.MaxParticipants) {
+		limits.MaxParticipants = limit
+	}
+	return limits.MaxParticipants, !limits.ParticipantsAllowed(len(state.sessions))
+}
+
+// getCallClientState returns the call state as sent to clients, including
+// the server resolved capacity.
+func (p *Plugin) getCallClientState(state *callState, userID string) *CallStateClient {
+	clientState := state.getClientState(p.getBotID(), userID)
+	clientState.MaxParticipants, clientState.AtCapacity = p.getCallCapacity(state)
+	return clientState
+}
+
+// joinAllowed returns an error if the user cannot join the call because of
+// license or configuration limits.
+func (p *Plugin) joinAllowed(state *callState) error {
+	if _, atCapacity := p.getCallCapacity(state); atCapacity {
+		return errMaxParticipantsReached
+	}
+	return nil
+}
+
+// newCallAllowed returns an error if a new call cannot be started because of
+// license or configuration limits. The check is best effort as calls in
+// different channels can be started concurrently.
+func (p *Plugin) newCallAllowed() error {
+	// The RTCD service is picked at activation so if the license lapses
+	// afterwards, ongoing calls are allowed to finish but no new ones can be
+	// started until the plugin is restarted with the integrated RTC server.
+	if p.rtcdManager != nil && !p.licenseChecker.RTCDAllowed() {
+		return errRTCDNotAllowed
+	}
+
+	limits := p.getConfiguration().getCallLimits()
+	if limits.MaxConcurrentCalls == 0 {
+		return nil
+	}
+
+	count, err := p.store.GetTotalCalls(true)
+	if err != nil {
+		return fmt.Errorf("failed to get total active calls: %w", err)
+	}
+
+	if !limits.CallsAllowed(int(count)) {
+		return errMaxConcurrentCallsReached
+	}
+
+	return nil
+}
+
 func getConcurrentSessionsThreshold() int64 {
 	val, err := strconv.Atoi(os.Getenv("MM_CALLS_CONCURRENT_SESSIONS_THRESHOLD"))
 	if err != nil {
@@ -75,7 +141,7 @@ func (p *Plugin) handleCloudNotifyAdmins(w http.ResponseWriter, r *http.Request)
 		return fmt.Errorf("no admins found")
 	}
 
-	maxParticipants := cloudStarterMaxParticipantsDefault
+	maxParticipants := license.CloudStarterMaxParticipants
 	cfg := p.getConfiguration()
 	if cfg != nil && cfg.MaxCallParticipants != nil {
 		maxParticipants = *cfg.MaxCallParticipants
@@ -360,3 +360,155 @@ func TestSendConcurrentSessionsWarning(t *testing.T) {
 		require.NoError(t, err)
 	})
 }
+
+func TestJoinAllowed(t *testing.T) {
+	p := Plugin{}
+
+	newState := func(n int) *callState {
+		state := &callState{
+			sessions: map[string]*public.CallSession{},
+		}
+		for i := 0; i < n; i++ {
+			state.sessions[model.NewId()] = &public.CallSession{}
+		}
+		return state
+	}
+
+	t.Run("unlimited", func(t *testing.T) {
+		cfg := &configuration{}
+		cfg.SetDefaults()
+		p.configuration = cfg
+
+		require.NoError(t, p.joinAllowed(newState(1000)))
+	})
+
+	t.Run("below limit", func(t *testing.T) {
+		cfg := &configuration{}
+		cfg.SetDefaults()
+		cfg.MaxCallParticipants = model.NewPointer(8)
+		p.configuration = cfg
+
+		require.NoError(t, p.joinAllowed(newState(7)))
+	})
+
+	t.Run("at limit", func(t *testing.T) {
+		cfg := &configuration{}
+		cfg.SetDefaults()
+		cfg.MaxCallParticipants = model.NewPointer(8)
+		p.configuration = cfg
+
+		require.Equal(t, errMaxParticipantsReached, p.joinAllowed(newState(8)))
+	})
+
+	t.Run("group message limit", func(t *testing.T) {
+		cfg := &configuration{}
+		cfg.SetDefaults()
+		cfg.MaxCallParticipants = model.NewPointer(8)
+		p.configuration = cfg
+
+		state := newState(4)
+		state.Call.Props.MaxParticipants = 4
+		require.Equal(t, errMaxParticipantsReached, p.joinAllowed(state))
+
+		// A lower global limit still applies.
+		state = newState(2)
+		state.Call.Props.MaxParticipants = 4
+		cfg.MaxCallParticipants = model.NewPointer(2)
+		require.Equal(t, errMaxParticipantsReached, p.joinAllowed(state))
+	})
+}
+
+func TestGetCallClientStateCapacity(t *testing.T) {
+	p := Plugin{
+		botSession: &model.Session{
+			UserId: "botID",
+		},
+	}
+
+	newState := func(n int) *callState {
+		state := &callState{
+			sessions: map[string]*public.CallSession{},
+		}
+		for i := 0; i < n; i++ {
+			id := model.NewId()
+			state.sessions[id] = &public.CallSession{ID: id, UserID: model.NewId()}
+		}
+		return state
+	}
+
+	t.Run("unlimited", func(t *testing.T) {
+		cfg := &configuration{}
+		cfg.SetDefaults()
+		p.configuration = cfg
+
+		cs := p.getCallClientState(newState(1000), "userID")
+		require.Zero(t, cs.MaxParticipants)
+		require.False(t, cs.AtCapacity)
+	})
+
+	t.Run("below limit", func(t *testing.T) {
+		cfg := &configuration{}
+		cfg.SetDefaults()
+		cfg.MaxCallParticipants = model.NewPointer(8)
+		p.configuration = cfg
+
+		cs := p.getCallClientState(newState(7), "userID")
+		require.Equal(t, 8, cs.MaxParticipants)
+		require.False(t, cs.AtCapacity)
+	})
+
+	t.Run("at limit", func(t *testing.T) {
+		cfg := &configuration{}
+		cfg.SetDefaults()
+		cfg.MaxCallParticipants = model.NewPointer(8)
+		p.configuration = cfg
+
+		state := newState(8)
+		cs := p.getCallClientState(state, "userID")
+		require.Equal(t, 8, cs.MaxParticipants)
+		require.True(t, cs.AtCapacity)
+		require.Equal(t, errMaxParticipantsReached, p.joinAllowed(state))
+	})
+}
+
+func TestNewCallAllowed(t *testing.T) {
+	p := Plugin{}
+
+	store, tearDown := NewTestStore(t)
+	t.Cleanup(tearDown)
+	p.store = store
+
+	cfg := &configuration{}
+	cfg.SetDefaults()
+	p.configuration = cfg
+
+	t.Run("unlimited", func(t *testing.T) {
+		require.NoError(t, p.newCallAllowed())
+	})
+
+	cfg.MaxConcurrentCalls = model.NewPointer(2)
+
+	createCall := func(t *testing.T) {
+		t.Helper()
+		err := p.store.CreateCall(&public.Call{
+			ID:        model.NewId(),
+			ChannelID: model.NewId(),
+			StartAt:   time.Now().UnixMilli(),
+			CreateAt:  time.Now().UnixMilli(),
+			PostID:    model.NewId(),
+			ThreadID:  model.NewId(),
+			OwnerID:   model.NewId(),
+		})
+		require.NoError(t, err)
+	}
+
+	t.Run("below limit", func(t *testing.T) {
+		createCall(t)
+		require.NoError(t, p.newCallAllowed())
+	})
+
+	t.Run("at limit", func(t *testing.T) {
+		createCall(t)
+		require.Equal(t, errMaxConcurrentCallsReached, p.newCallAllowed())
+	})
+}
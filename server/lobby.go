@@ -0,0 +1,138 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+const (
+	// The maximum time a user can spend in the lobby before being let out.
+	lobbyMaxDuration = 5 * time.Minute
+	// Clients are expected to send a probe per second while testing their
+	// connection.
+	lobbyPingRateLimit = 2
+	lobbyPingBurst     = 10
+)
+
+// lobbySession tracks a user testing their connection before joining a call in
+// the given channel. Lobby sessions are not part of the call state so they are
+// never visible to call participants.
+type lobbySession struct {
+	userID    string
+	channelID string
+	connID    string
+	enterAt   int64
+
+	pingLimiter *rate.Limiter
+
+	leaveCh chan struct{}
+	left    int32
+}
+
+func (p *Plugin) handleLobbyEnter(userID, connID, channelID string) error {
+	if _, err := p.checkCallChannelAccess(userID, channelID); err != nil {
+		return err
+	}
+
+	channel, appErr := p.API.GetChannel(channelID)
+	if appErr != nil {
+		return appErr
+	}
+	if channel.DeleteAt > 0 {
+		return errArchivedChannel
+	}
+
+	ls := &lobbySession{
+		userID:      userID,
+		channelID:   channelID,
+		connID:      connID,
+		enterAt:     time.Now().UnixMilli(),
+		pingLimiter: rate.NewLimiter(lobbyPingRateLimit, lobbyPingBurst),
+		leaveCh:     make(chan struct{}),
+	}
+
+	if err := addConnEntry(p, p.lobbySessions, connID, ls, "in the lobby"); err != nil {
+		return err
+	}
+
+	p.LogDebug("user entered lobby", "userID", userID, "connID", connID, "channelID", channelID)
+
+	p.publishWebSocketEvent(wsEventLobbyReady, map[string]interface{}{
+		"connID":          connID,
+		"channel_id":      channelID,
+		"max_duration_ms": lobbyMaxDuration.Milliseconds(),
+	}, &WebSocketBroadcast{ConnectionID: connID})
+
+	go p.lobbyTimeoutWatcher(ls, lobbyMaxDuration)
+
+	return nil
+}
+
+// handleLobbyPing replies to a connection probe so that the client can measure
+// round trip time and jitter towards the server.
+func (p *Plugin) handleLobbyPing(connID string, seq, ts float64) error {
+	p.mut.RLock()
+	ls := p.lobbySessions[connID]
+	p.mut.RUnlock()
+	if ls == nil {
+		return fmt.Errorf("connection is not in the lobby")
+	}
+
+	if !ls.pingLimiter.Allow() {
+		return fmt.Errorf("lobby ping was dropped by rate limiter")
+	}
+
+	p.publishWebSocketEvent(wsEventLobbyPong, map[string]interface{}{
+		"connID":    connID,
+		"seq":       seq,
+		"ts":        ts,
+		"server_ts": time.Now().UnixMilli(),
+	}, &WebSocketBroadcast{ConnectionID: connID})
+
+	return nil
+}
+
+// leaveLobby removes the lobby session for the given connection, if any. It
+// returns whether a session was removed.
+func (p *Plugin) leaveLobby(connID string) bool {
+	ls := takeConnEntry(p, p.lobbySessions, connID)
+	if ls == nil {
+		return false
+	}
+
+	if atomic.CompareAndSwapInt32(&ls.left, 0, 1) {
+		close(ls.leaveCh)
+	}
+
+	p.LogDebug("user left lobby", "userID", ls.userID, "connID", connID, "channelID", ls.channelID)
+
+	return true
+}
+
+func (p *Plugin) lobbyTimeoutWatcher(ls *lobbySession, timeout time.Duration) {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+	case <-ls.leaveCh:
+		return
+	case <-p.stopCh:
+		return
+	}
+
+	if !p.leaveLobby(ls.connID) {
+		return
+	}
+
+	p.publishWebSocketEvent(wsEventLobbyLeft, map[string]interface{}{
+		"connID": ls.connID,
+		"reason": "timeout",
+	}, &WebSocketBroadcast{ConnectionID: ls.connID})
+}
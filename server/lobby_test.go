@@ -0,0 +1,142 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"testing"
+	"time"
+
+	serverMocks "github.com/mattermost/mattermost-plugin-calls/server/mocks/github.com/mattermost/mattermost-plugin-calls/server/interfaces"
+	pluginMocks "github.com/mattermost/mattermost-plugin-calls/server/mocks/github.com/mattermost/mattermost/server/public/plugin"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+)
+
+func TestLobby(t *testing.T) {
+	mockAPI := &pluginMocks.MockAPI{}
+	mockMetrics := &serverMocks.MockMetrics{}
+
+	defer mockAPI.AssertExpectations(t)
+	defer mockMetrics.AssertExpectations(t)
+
+	p := Plugin{
+		MattermostPlugin: plugin.MattermostPlugin{
+			API: mockAPI,
+		},
+		metrics:       mockMetrics,
+		stopCh:        make(chan struct{}),
+		sessions:      map[string]*session{},
+		lobbySessions: map[string]*lobbySession{},
+	}
+
+	cfg := &configuration{}
+	cfg.SetDefaults()
+	p.configuration = cfg
+
+	mockAPI.On("LogDebug", mock.Anything, mock.Anything, mock.Anything,
+		mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+		mock.Anything, mock.Anything)
+
+	userID := model.NewId()
+	channelID := model.NewId()
+
+	enter := func(t *testing.T, connID string) {
+		t.Helper()
+		mockAPI.On("HasPermissionToChannel", userID, channelID, model.PermissionCreatePost).Return(true).Once()
+		mockAPI.On("GetChannel", channelID).Return(&model.Channel{Id: channelID, Type: model.ChannelTypeOpen}, nil).Once()
+		mockMetrics.On("IncWebSocketEvent", "out", wsEventLobbyReady).Once()
+		mockAPI.On("PublishWebSocketEvent", wsEventLobbyReady, map[string]any{
+			"connID":          connID,
+			"channel_id":      channelID,
+			"max_duration_ms": lobbyMaxDuration.Milliseconds(),
+		}, &model.WebsocketBroadcast{ConnectionId: connID}).Once()
+
+		err := p.handleLobbyEnter(userID, connID, channelID)
+		require.NoError(t, err)
+	}
+
+	t.Run("enter and leave", func(t *testing.T) {
+		connID := model.NewId()
+		enter(t, connID)
+		require.NotNil(t, p.lobbySessions[connID])
+
+		t.Run("already in lobby", func(t *testing.T) {
+			mockAPI.On("HasPermissionToChannel", userID, channelID, model.PermissionCreatePost).Return(true).Once()
+			mockAPI.On("GetChannel", channelID).Return(&model.Channel{Id: channelID, Type: model.ChannelTypeOpen}, nil).Once()
+			err := p.handleLobbyEnter(userID, connID, channelID)
+			require.EqualError(t, err, "connection is already in the lobby")
+		})
+
+		require.True(t, p.leaveLobby(connID))
+		require.Nil(t, p.lobbySessions[connID])
+		require.False(t, p.leaveLobby(connID))
+	})
+
+	t.Run("archived channel", func(t *testing.T) {
+		mockAPI.On("HasPermissionToChannel", userID, channelID, model.PermissionCreatePost).Return(true).Once()
+		mockAPI.On("GetChannel", channelID).Return(&model.Channel{
+			Id:       channelID,
+			Type:     model.ChannelTypeOpen,
+			DeleteAt: time.Now().UnixMilli(),
+		}, nil).Once()
+
+		err := p.handleLobbyEnter(userID, model.NewId(), channelID)
+		require.Equal(t, errArchivedChannel, err)
+	})
+
+	t.Run("already in call", func(t *testing.T) {
+		connID := model.NewId()
+		p.sessions[connID] = newUserSession(userID, channelID, connID, model.NewId(), true)
+		defer delete(p.sessions, connID)
+
+		mockAPI.On("HasPermissionToChannel", userID, channelID, model.PermissionCreatePost).Return(true).Once()
+		mockAPI.On("GetChannel", channelID).Return(&model.Channel{Id: channelID, Type: model.ChannelTypeOpen}, nil).Once()
+
+		err := p.handleLobbyEnter(userID, connID, channelID)
+		require.EqualError(t, err, "connection is already in a call")
+	})
+
+	t.Run("ping", func(t *testing.T) {
+		connID := model.NewId()
+
+		err := p.handleLobbyPing(connID, 1, 45)
+		require.EqualError(t, err, "connection is not in the lobby")
+
+		enter(t, connID)
+		defer p.leaveLobby(connID)
+
+		mockMetrics.On("IncWebSocketEvent", "out", wsEventLobbyPong).Once()
+		mockAPI.On("PublishWebSocketEvent", wsEventLobbyPong, mock.MatchedBy(func(data map[string]any) bool {
+			return data["connID"] == connID && data["seq"] == float64(1) && data["ts"] == float64(45) &&
+				data["server_ts"].(int64) > 0
+		}), &model.WebsocketBroadcast{ConnectionId: connID}).Once()
+
+		err = p.handleLobbyPing(connID, 1, 45)
+		require.NoError(t, err)
+
+		p.lobbySessions[connID].pingLimiter = rate.NewLimiter(0, 0)
+		err = p.handleLobbyPing(connID, 2, 46)
+		require.EqualError(t, err, "lobby ping was dropped by rate limiter")
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		connID := model.NewId()
+		enter(t, connID)
+		ls := p.lobbySessions[connID]
+
+		mockMetrics.On("IncWebSocketEvent", "out", wsEventLobbyLeft).Once()
+		mockAPI.On("PublishWebSocketEvent", wsEventLobbyLeft, map[string]any{
+			"connID": connID,
+			"reason": "timeout",
+		}, &model.WebsocketBroadcast{ConnectionId: connID}).Once()
+
+		p.lobbyTimeoutWatcher(ls, time.Millisecond)
+		require.Nil(t, p.lobbySessions[connID])
+	})
+}
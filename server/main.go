@@ -4,11 +4,14 @@
 package main
 
 import (
+	"time"
+
 	"golang.org/x/time/rate"
 
 	"github.com/mattermost/mattermost-plugin-calls/server/batching"
 	"github.com/mattermost/mattermost-plugin-calls/server/cluster"
 	"github.com/mattermost/mattermost-plugin-calls/server/performance"
+	"github.com/mattermost/mattermost-plugin-calls/server/public"
 
 	"github.com/mattermost/mattermost/server/public/model"
 	"github.com/mattermost/mattermost/server/public/plugin"
@@ -29,11 +32,20 @@ func main() {
 		stopCh:                 make(chan struct{}),
 		clusterEvCh:            make(chan model.PluginClusterEvent, clusterEventQueueSize),
 		sessions:               map[string]*session{},
+		lobbySessions:          map[string]*lobbySession{},
+		pendingCallStarts:      map[string]*pendingCallStart{},
+		pendingAdmissions:      map[string]*pendingAdmission{},
 		metrics:                performance.NewMetrics(),
 		apiLimiters:            map[string]*rate.Limiter{},
 		callsClusterLocks:      map[string]*cluster.Mutex{},
 		addSessionsBatchers:    map[string]*batching.Batcher{},
 		removeSessionsBatchers: map[string]*batching.Batcher{},
+		participantEventsSubs:  map[string]*participantEventsSubscriber{},
+		participantWebhookCh:   make(chan public.ParticipantEvent, participantWebhookQueueSize),
+		callTimelineCh:         make(chan queuedCallTimelineEvent, callTimelineQueueSize),
+		dbWriteBuffer:          map[string]bufferedDBWrite{},
+		poorConnections:        map[string]*pendingPoorConnections{},
+		noisySessions:          map[string]*time.Timer{},
 	}
 	p.apiRouter = p.newAPIRouter()
 	plugin.ClientMain(p)
@@ -0,0 +1,130 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/push"
+)
+
+const (
+	metricsPushJobName = "mattermost_plugin_calls"
+	metricsPushTimeout = 10 * time.Second
+)
+
+// callsPushMetrics holds the key call metrics periodically pushed to the
+// configured endpoint. Values are read from the database so they account for
+// all the nodes in the cluster.
+type callsPushMetrics struct {
+	registry       *prometheus.Registry
+	activeCalls    prometheus.Gauge
+	activeSessions prometheus.Gauge
+	activeJobs     *prometheus.GaugeVec
+}
+
+func newCallsPushMetrics() *callsPushMetrics {
+	m := &callsPushMetrics{
+		registry: prometheus.NewRegistry(),
+		activeCalls: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: metricsPushJobName,
+			Name:      "active_calls",
+			Help:      "Number of ongoing calls",
+		}),
+		activeSessions: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: metricsPushJobName,
+			Name:      "active_sessions",
+			Help:      "Number of participant sessions in ongoing calls",
+		}),
+		activeJobs: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: metricsPushJobName,
+			Name:      "active_jobs",
+			Help:      "Number of jobs (e.g. recordings) running in ongoing calls",
+		}, []string{"type"}),
+	}
+
+	m.registry.MustRegister(m.activeCalls, m.activeSessions, m.activeJobs)
+
+	return m
+}
+
+// metricsPusher periodically sends the key call metrics to the configured
+// push endpoint (e.g. a Prometheus Pushgateway). Failures are logged and
+// retried at the next interval.
+func (p *Plugin) metricsPusher() {
+	interval := p.getConfiguration().getMetricsPushInterval()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	m := newCallsPushMetrics()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-p.stopCh:
+			return
+		}
+
+		cfg := p.getConfiguration()
+
+		// The interval can change at runtime through the configuration.
+		if newInterval := cfg.getMetricsPushInterval(); newInterval != interval {
+			interval = newInterval
+			ticker.Reset(interval)
+		}
+
+		if cfg.MetricsPushURL == "" {
+			continue
+		}
+
+		if err := p.pushMetrics(cfg, m); err != nil {
+			p.LogWarn("failed to push metrics", "err", err.Error())
+		}
+	}
+}
+
+func (p *Plugin) pushMetrics(cfg *configuration, m *callsPushMetrics) error {
+	activeCalls, err := p.store.GetTotalCalls(true)
+	if err != nil {
+		return fmt.Errorf("failed to get active calls: %w", err)
+	}
+
+	activeSessions, err := p.store.GetTotalActiveSessions()
+	if err != nil {
+		return fmt.Errorf("failed to get active sessions: %w", err)
+	}
+
+	activeJobs, err := p.store.GetTotalActiveCallJobs()
+	if err != nil {
+		return fmt.Errorf("failed to get active jobs: %w", err)
+	}
+
+	m.activeCalls.Set(float64(activeCalls))
+	m.activeSessions.Set(float64(activeSessions))
+	m.activeJobs.Reset()
+	for jobType, count := range activeJobs {
+		m.activeJobs.WithLabelValues(jobType).Set(float64(count))
+	}
+
+	instance, err := os.Hostname()
+	if err != nil {
+		instance = p.nodeID
+	}
+
+	return pushCallsMetrics(cfg.newOutboundHTTPClient(metricsPushTimeout), cfg.MetricsPushURL, instance, m.registry)
+}
+
+// pushCallsMetrics replaces all the metrics previously pushed for the same
+// instance so that stale job types don't linger.
+func pushCallsMetrics(client *http.Client, pushURL, instance string, g prometheus.Gatherer) error {
+	return push.New(pushURL, metricsPushJobName).
+		Client(client).
+		Grouping("instance", instance).
+		Gatherer(g).
+		Push()
+}
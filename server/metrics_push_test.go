@@ -0,0 +1,61 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPushCallsMetrics(t *testing.T) {
+	m := newCallsPushMetrics()
+	m.activeCalls.Set(2)
+	m.activeSessions.Set(7)
+	m.activeJobs.WithLabelValues("recording").Set(1)
+
+	t.Run("success", func(t *testing.T) {
+		var body string
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, http.MethodPut, r.Method)
+			require.Equal(t, "/metrics/job/mattermost_plugin_calls/instance/nodeA", r.URL.Path)
+			data, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			body = string(data)
+			w.WriteHeader(http.StatusAccepted)
+		}))
+		defer ts.Close()
+
+		err := pushCallsMetrics(ts.Client(), ts.URL, "nodeA", m.registry)
+		require.NoError(t, err)
+		require.NotEmpty(t, body)
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer ts.Close()
+
+		err := pushCallsMetrics(ts.Client(), ts.URL, "nodeA", m.registry)
+		require.Error(t, err)
+	})
+}
+
+func TestGetMetricsPushInterval(t *testing.T) {
+	cfg := &configuration{}
+	require.Equal(t, defaultMetricsPushIntervalSeconds*time.Second, cfg.getMetricsPushInterval())
+
+	cfg.SetDefaults()
+	require.Equal(t, 60*time.Second, cfg.getMetricsPushInterval())
+
+	cfg.MetricsPushIntervalSeconds = model.NewPointer(15)
+	require.Equal(t, 15*time.Second, cfg.getMetricsPushInterval())
+}
@@ -27,6 +27,73 @@ func (_m *MockMetrics) EXPECT() *MockMetrics_Expecter {
 	return &MockMetrics_Expecter{mock: &_m.Mock}
 }
 
+// AddCleanUpStateReapedCalls provides a mock function with given fields: count
+func (_m *MockMetrics) AddCleanUpStateReapedCalls(count int) {
+	_m.Called(count)
+}
+
+// MockMetrics_AddCleanUpStateReapedCalls_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddCleanUpStateReapedCalls'
+type MockMetrics_AddCleanUpStateReapedCalls_Call struct {
+	*mock.Call
+}
+
+// AddCleanUpStateReapedCalls is a helper method to define mock.On call
+//   - count int
+func (_e *MockMetrics_Expecter) AddCleanUpStateReapedCalls(count interface{}) *MockMetrics_AddCleanUpStateReapedCalls_Call {
+	return &MockMetrics_AddCleanUpStateReapedCalls_Call{Call: _e.mock.On("AddCleanUpStateReapedCalls", count)}
+}
+
+func (_c *MockMetrics_AddCleanUpStateReapedCalls_Call) Run(run func(count int)) *MockMetrics_AddCleanUpStateReapedCalls_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(int))
+	})
+	return _c
+}
+
+func (_c *MockMetrics_AddCleanUpStateReapedCalls_Call) Return() *MockMetrics_AddCleanUpStateReapedCalls_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockMetrics_AddCleanUpStateReapedCalls_Call) RunAndReturn(run func(int)) *MockMetrics_AddCleanUpStateReapedCalls_Call {
+	_c.Run(run)
+	return _c
+}
+
+// AddKeyFrameRequests provides a mock function with given fields: reqType, count
+func (_m *MockMetrics) AddKeyFrameRequests(reqType string, count int) {
+	_m.Called(reqType, count)
+}
+
+// MockMetrics_AddKeyFrameRequests_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddKeyFrameRequests'
+type MockMetrics_AddKeyFrameRequests_Call struct {
+	*mock.Call
+}
+
+// AddKeyFrameRequests is a helper method to define mock.On call
+//   - reqType string
+//   - count int
+func (_e *MockMetrics_Expecter) AddKeyFrameRequests(reqType interface{}, count interface{}) *MockMetrics_AddKeyFrameRequests_Call {
+	return &MockMetrics_AddKeyFrameRequests_Call{Call: _e.mock.On("AddKeyFrameRequests", reqType, count)}
+}
+
+func (_c *MockMetrics_AddKeyFrameRequests_Call) Run(run func(reqType string, count int)) *MockMetrics_AddKeyFrameRequests_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockMetrics_AddKeyFrameRequests_Call) Return() *MockMetrics_AddKeyFrameRequests_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockMetrics_AddKeyFrameRequests_Call) RunAndReturn(run func(string, int)) *MockMetrics_AddKeyFrameRequests_Call {
+	_c.Run(run)
+	return _c
+}
+
 // DecWebSocketConn provides a mock function with no fields
 func (_m *MockMetrics) DecWebSocketConn() {
 	_m.Called()
@@ -59,6 +126,39 @@ func (_c *MockMetrics_DecWebSocketConn_Call) RunAndReturn(run func()) *MockMetri
 	return _c
 }
 
+// DeleteRTCDHostMetrics provides a mock function with given fields: host
+func (_m *MockMetrics) DeleteRTCDHostMetrics(host string) {
+	_m.Called(host)
+}
+
+// MockMetrics_DeleteRTCDHostMetrics_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteRTCDHostMetrics'
+type MockMetrics_DeleteRTCDHostMetrics_Call struct {
+	*mock.Call
+}
+
+// DeleteRTCDHostMetrics is a helper method to define mock.On call
+//   - host string
+func (_e *MockMetrics_Expecter) DeleteRTCDHostMetrics(host interface{}) *MockMetrics_DeleteRTCDHostMetrics_Call {
+	return &MockMetrics_DeleteRTCDHostMetrics_Call{Call: _e.mock.On("DeleteRTCDHostMetrics", host)}
+}
+
+func (_c *MockMetrics_DeleteRTCDHostMetrics_Call) Run(run func(host string)) *MockMetrics_DeleteRTCDHostMetrics_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *MockMetrics_DeleteRTCDHostMetrics_Call) Return() *MockMetrics_DeleteRTCDHostMetrics_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockMetrics_DeleteRTCDHostMetrics_Call) RunAndReturn(run func(string)) *MockMetrics_DeleteRTCDHostMetrics_Call {
+	_c.Run(run)
+	return _c
+}
+
 // Handler provides a mock function with no fields
 func (_m *MockMetrics) Handler() http.Handler {
 	ret := _m.Called()
@@ -205,383 +305,979 @@ func (_c *MockMetrics_IncClusterMutexLockRetries_Call) RunAndReturn(run func(str
 	return _c
 }
 
-// IncLiveCaptionsPktPayloadChBufFull provides a mock function with no fields
-func (_m *MockMetrics) IncLiveCaptionsPktPayloadChBufFull() {
-	_m.Called()
+// IncICEConnectionFailures provides a mock function with given fields: candidateType, platform
+func (_m *MockMetrics) IncICEConnectionFailures(candidateType string, platform string) {
+	_m.Called(candidateType, platform)
 }
 
-// MockMetrics_IncLiveCaptionsPktPayloadChBufFull_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IncLiveCaptionsPktPayloadChBufFull'
-type MockMetrics_IncLiveCaptionsPktPayloadChBufFull_Call struct {
+// MockMetrics_IncICEConnectionFailures_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IncICEConnectionFailures'
+type MockMetrics_IncICEConnectionFailures_Call struct {
 	*mock.Call
 }
 
-// IncLiveCaptionsPktPayloadChBufFull is a helper method to define mock.On call
-func (_e *MockMetrics_Expecter) IncLiveCaptionsPktPayloadChBufFull() *MockMetrics_IncLiveCaptionsPktPayloadChBufFull_Call {
-	return &MockMetrics_IncLiveCaptionsPktPayloadChBufFull_Call{Call: _e.mock.On("IncLiveCaptionsPktPayloadChBufFull")}
+// IncICEConnectionFailures is a helper method to define mock.On call
+//   - candidateType string
+//   - platform string
+func (_e *MockMetrics_Expecter) IncICEConnectionFailures(candidateType interface{}, platform interface{}) *MockMetrics_IncICEConnectionFailures_Call {
+	return &MockMetrics_IncICEConnectionFailures_Call{Call: _e.mock.On("IncICEConnectionFailures", candidateType, platform)}
 }
 
-func (_c *MockMetrics_IncLiveCaptionsPktPayloadChBufFull_Call) Run(run func()) *MockMetrics_IncLiveCaptionsPktPayloadChBufFull_Call {
+func (_c *MockMetrics_IncICEConnectionFailures_Call) Run(run func(candidateType string, platform string)) *MockMetrics_IncICEConnectionFailures_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run()
+		run(args[0].(string), args[1].(string))
 	})
 	return _c
 }
 
-func (_c *MockMetrics_IncLiveCaptionsPktPayloadChBufFull_Call) Return() *MockMetrics_IncLiveCaptionsPktPayloadChBufFull_Call {
+func (_c *MockMetrics_IncICEConnectionFailures_Call) Return() *MockMetrics_IncICEConnectionFailures_Call {
 	_c.Call.Return()
 	return _c
 }
 
-func (_c *MockMetrics_IncLiveCaptionsPktPayloadChBufFull_Call) RunAndReturn(run func()) *MockMetrics_IncLiveCaptionsPktPayloadChBufFull_Call {
+func (_c *MockMetrics_IncICEConnectionFailures_Call) RunAndReturn(run func(string, string)) *MockMetrics_IncICEConnectionFailures_Call {
 	_c.Run(run)
 	return _c
 }
 
-// IncLiveCaptionsTranscriberBufFull provides a mock function with no fields
-func (_m *MockMetrics) IncLiveCaptionsTranscriberBufFull() {
+// IncLiveCaptionsPktPayloadChBufFull provides a mock function with no fields
+// IncICEConnectionTimeouts provides a mock function with no fields
+func (_m *MockMetrics) IncICEConnectionTimeouts() {
 	_m.Called()
 }
 
-// MockMetrics_IncLiveCaptionsTranscriberBufFull_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IncLiveCaptionsTranscriberBufFull'
-type MockMetrics_IncLiveCaptionsTranscriberBufFull_Call struct {
+// MockMetrics_IncICEConnectionTimeouts_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IncICEConnectionTimeouts'
+type MockMetrics_IncICEConnectionTimeouts_Call struct {
 	*mock.Call
 }
 
-// IncLiveCaptionsTranscriberBufFull is a helper method to define mock.On call
-func (_e *MockMetrics_Expecter) IncLiveCaptionsTranscriberBufFull() *MockMetrics_IncLiveCaptionsTranscriberBufFull_Call {
-	return &MockMetrics_IncLiveCaptionsTranscriberBufFull_Call{Call: _e.mock.On("IncLiveCaptionsTranscriberBufFull")}
+// IncICEConnectionTimeouts is a helper method to define mock.On call
+func (_e *MockMetrics_Expecter) IncICEConnectionTimeouts() *MockMetrics_IncICEConnectionTimeouts_Call {
+	return &MockMetrics_IncICEConnectionTimeouts_Call{Call: _e.mock.On("IncICEConnectionTimeouts")}
 }
 
-func (_c *MockMetrics_IncLiveCaptionsTranscriberBufFull_Call) Run(run func()) *MockMetrics_IncLiveCaptionsTranscriberBufFull_Call {
+func (_c *MockMetrics_IncICEConnectionTimeouts_Call) Run(run func()) *MockMetrics_IncICEConnectionTimeouts_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		run()
 	})
 	return _c
 }
 
-func (_c *MockMetrics_IncLiveCaptionsTranscriberBufFull_Call) Return() *MockMetrics_IncLiveCaptionsTranscriberBufFull_Call {
+func (_c *MockMetrics_IncICEConnectionTimeouts_Call) Return() *MockMetrics_IncICEConnectionTimeouts_Call {
 	_c.Call.Return()
 	return _c
 }
 
-func (_c *MockMetrics_IncLiveCaptionsTranscriberBufFull_Call) RunAndReturn(run func()) *MockMetrics_IncLiveCaptionsTranscriberBufFull_Call {
+func (_c *MockMetrics_IncICEConnectionTimeouts_Call) RunAndReturn(run func()) *MockMetrics_IncICEConnectionTimeouts_Call {
 	_c.Run(run)
 	return _c
 }
 
-// IncLiveCaptionsWindowDropped provides a mock function with no fields
-func (_m *MockMetrics) IncLiveCaptionsWindowDropped() {
-	_m.Called()
+// IncICEConnections provides a mock function with given fields: state
+func (_m *MockMetrics) IncICEConnections(state string) {
+	_m.Called(state)
 }
 
-// MockMetrics_IncLiveCaptionsWindowDropped_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IncLiveCaptionsWindowDropped'
-type MockMetrics_IncLiveCaptionsWindowDropped_Call struct {
+// MockMetrics_IncICEConnections_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IncICEConnections'
+type MockMetrics_IncICEConnections_Call struct {
 	*mock.Call
 }
 
-// IncLiveCaptionsWindowDropped is a helper method to define mock.On call
-func (_e *MockMetrics_Expecter) IncLiveCaptionsWindowDropped() *MockMetrics_IncLiveCaptionsWindowDropped_Call {
-	return &MockMetrics_IncLiveCaptionsWindowDropped_Call{Call: _e.mock.On("IncLiveCaptionsWindowDropped")}
+// IncICEConnections is a helper method to define mock.On call
+//   - state string
+func (_e *MockMetrics_Expecter) IncICEConnections(state interface{}) *MockMetrics_IncICEConnections_Call {
+	return &MockMetrics_IncICEConnections_Call{Call: _e.mock.On("IncICEConnections", state)}
 }
 
-func (_c *MockMetrics_IncLiveCaptionsWindowDropped_Call) Run(run func()) *MockMetrics_IncLiveCaptionsWindowDropped_Call {
+func (_c *MockMetrics_IncICEConnections_Call) Run(run func(state string)) *MockMetrics_IncICEConnections_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run()
+		run(args[0].(string))
 	})
 	return _c
 }
 
-func (_c *MockMetrics_IncLiveCaptionsWindowDropped_Call) Return() *MockMetrics_IncLiveCaptionsWindowDropped_Call {
+func (_c *MockMetrics_IncICEConnections_Call) Return() *MockMetrics_IncICEConnections_Call {
 	_c.Call.Return()
 	return _c
 }
 
-func (_c *MockMetrics_IncLiveCaptionsWindowDropped_Call) RunAndReturn(run func()) *MockMetrics_IncLiveCaptionsWindowDropped_Call {
+func (_c *MockMetrics_IncICEConnections_Call) RunAndReturn(run func(string)) *MockMetrics_IncICEConnections_Call {
 	_c.Run(run)
 	return _c
 }
 
-// IncStoreOp provides a mock function with given fields: op
-func (_m *MockMetrics) IncStoreOp(op string) {
-	_m.Called(op)
+// IncICERestarts provides a mock function with given fields: initiator
+func (_m *MockMetrics) IncICERestarts(initiator string) {
+	_m.Called(initiator)
 }
 
-// MockMetrics_IncStoreOp_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IncStoreOp'
-type MockMetrics_IncStoreOp_Call struct {
+// MockMetrics_IncICERestarts_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IncICERestarts'
+type MockMetrics_IncICERestarts_Call struct {
 	*mock.Call
 }
 
-// IncStoreOp is a helper method to define mock.On call
-//   - op string
-func (_e *MockMetrics_Expecter) IncStoreOp(op interface{}) *MockMetrics_IncStoreOp_Call {
-	return &MockMetrics_IncStoreOp_Call{Call: _e.mock.On("IncStoreOp", op)}
+// IncICERestarts is a helper method to define mock.On call
+//   - initiator string
+func (_e *MockMetrics_Expecter) IncICERestarts(initiator interface{}) *MockMetrics_IncICERestarts_Call {
+	return &MockMetrics_IncICERestarts_Call{Call: _e.mock.On("IncICERestarts", initiator)}
 }
 
-func (_c *MockMetrics_IncStoreOp_Call) Run(run func(op string)) *MockMetrics_IncStoreOp_Call {
+func (_c *MockMetrics_IncICERestarts_Call) Run(run func(initiator string)) *MockMetrics_IncICERestarts_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		run(args[0].(string))
 	})
 	return _c
 }
 
-func (_c *MockMetrics_IncStoreOp_Call) Return() *MockMetrics_IncStoreOp_Call {
+func (_c *MockMetrics_IncICERestarts_Call) Return() *MockMetrics_IncICERestarts_Call {
 	_c.Call.Return()
 	return _c
 }
 
-func (_c *MockMetrics_IncStoreOp_Call) RunAndReturn(run func(string)) *MockMetrics_IncStoreOp_Call {
+func (_c *MockMetrics_IncICERestarts_Call) RunAndReturn(run func(string)) *MockMetrics_IncICERestarts_Call {
 	_c.Run(run)
 	return _c
 }
 
-// IncWebSocketConn provides a mock function with no fields
-func (_m *MockMetrics) IncWebSocketConn() {
+// IncLiveCaptionsPktPayloadChBufFull provides a mock function with no fields
+func (_m *MockMetrics) IncLiveCaptionsPktPayloadChBufFull() {
 	_m.Called()
 }
 
-// MockMetrics_IncWebSocketConn_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IncWebSocketConn'
-type MockMetrics_IncWebSocketConn_Call struct {
+// MockMetrics_IncLiveCaptionsPktPayloadChBufFull_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IncLiveCaptionsPktPayloadChBufFull'
+type MockMetrics_IncLiveCaptionsPktPayloadChBufFull_Call struct {
 	*mock.Call
 }
 
-// IncWebSocketConn is a helper method to define mock.On call
-func (_e *MockMetrics_Expecter) IncWebSocketConn() *MockMetrics_IncWebSocketConn_Call {
-	return &MockMetrics_IncWebSocketConn_Call{Call: _e.mock.On("IncWebSocketConn")}
+// IncLiveCaptionsPktPayloadChBufFull is a helper method to define mock.On call
+func (_e *MockMetrics_Expecter) IncLiveCaptionsPktPayloadChBufFull() *MockMetrics_IncLiveCaptionsPktPayloadChBufFull_Call {
+	return &MockMetrics_IncLiveCaptionsPktPayloadChBufFull_Call{Call: _e.mock.On("IncLiveCaptionsPktPayloadChBufFull")}
 }
 
-func (_c *MockMetrics_IncWebSocketConn_Call) Run(run func()) *MockMetrics_IncWebSocketConn_Call {
+func (_c *MockMetrics_IncLiveCaptionsPktPayloadChBufFull_Call) Run(run func()) *MockMetrics_IncLiveCaptionsPktPayloadChBufFull_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		run()
 	})
 	return _c
 }
 
-func (_c *MockMetrics_IncWebSocketConn_Call) Return() *MockMetrics_IncWebSocketConn_Call {
+func (_c *MockMetrics_IncLiveCaptionsPktPayloadChBufFull_Call) Return() *MockMetrics_IncLiveCaptionsPktPayloadChBufFull_Call {
 	_c.Call.Return()
 	return _c
 }
 
-func (_c *MockMetrics_IncWebSocketConn_Call) RunAndReturn(run func()) *MockMetrics_IncWebSocketConn_Call {
+func (_c *MockMetrics_IncLiveCaptionsPktPayloadChBufFull_Call) RunAndReturn(run func()) *MockMetrics_IncLiveCaptionsPktPayloadChBufFull_Call {
 	_c.Run(run)
 	return _c
 }
 
-// IncWebSocketEvent provides a mock function with given fields: direction, evType
-func (_m *MockMetrics) IncWebSocketEvent(direction string, evType string) {
-	_m.Called(direction, evType)
+// IncLiveCaptionsTranscriberBufFull provides a mock function with no fields
+func (_m *MockMetrics) IncLiveCaptionsTranscriberBufFull() {
+	_m.Called()
 }
 
-// MockMetrics_IncWebSocketEvent_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IncWebSocketEvent'
-type MockMetrics_IncWebSocketEvent_Call struct {
+// MockMetrics_IncLiveCaptionsTranscriberBufFull_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IncLiveCaptionsTranscriberBufFull'
+type MockMetrics_IncLiveCaptionsTranscriberBufFull_Call struct {
 	*mock.Call
 }
 
-// IncWebSocketEvent is a helper method to define mock.On call
-//   - direction string
-//   - evType string
-func (_e *MockMetrics_Expecter) IncWebSocketEvent(direction interface{}, evType interface{}) *MockMetrics_IncWebSocketEvent_Call {
-	return &MockMetrics_IncWebSocketEvent_Call{Call: _e.mock.On("IncWebSocketEvent", direction, evType)}
+// IncLiveCaptionsTranscriberBufFull is a helper method to define mock.On call
+func (_e *MockMetrics_Expecter) IncLiveCaptionsTranscriberBufFull() *MockMetrics_IncLiveCaptionsTranscriberBufFull_Call {
+	return &MockMetrics_IncLiveCaptionsTranscriberBufFull_Call{Call: _e.mock.On("IncLiveCaptionsTranscriberBufFull")}
 }
 
-func (_c *MockMetrics_IncWebSocketEvent_Call) Run(run func(direction string, evType string)) *MockMetrics_IncWebSocketEvent_Call {
+func (_c *MockMetrics_IncLiveCaptionsTranscriberBufFull_Call) Run(run func()) *MockMetrics_IncLiveCaptionsTranscriberBufFull_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(string), args[1].(string))
+		run()
 	})
 	return _c
 }
 
-func (_c *MockMetrics_IncWebSocketEvent_Call) Return() *MockMetrics_IncWebSocketEvent_Call {
+func (_c *MockMetrics_IncLiveCaptionsTranscriberBufFull_Call) Return() *MockMetrics_IncLiveCaptionsTranscriberBufFull_Call {
 	_c.Call.Return()
 	return _c
 }
 
-func (_c *MockMetrics_IncWebSocketEvent_Call) RunAndReturn(run func(string, string)) *MockMetrics_IncWebSocketEvent_Call {
+func (_c *MockMetrics_IncLiveCaptionsTranscriberBufFull_Call) RunAndReturn(run func()) *MockMetrics_IncLiveCaptionsTranscriberBufFull_Call {
 	_c.Run(run)
 	return _c
 }
 
-// ObserveAppHandlersTime provides a mock function with given fields: handler, elapsed
-func (_m *MockMetrics) ObserveAppHandlersTime(handler string, elapsed float64) {
-	_m.Called(handler, elapsed)
+// IncLiveCaptionsWindowDropped provides a mock function with no fields
+func (_m *MockMetrics) IncLiveCaptionsWindowDropped() {
+	_m.Called()
 }
 
-// MockMetrics_ObserveAppHandlersTime_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ObserveAppHandlersTime'
-type MockMetrics_ObserveAppHandlersTime_Call struct {
+// MockMetrics_IncLiveCaptionsWindowDropped_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IncLiveCaptionsWindowDropped'
+type MockMetrics_IncLiveCaptionsWindowDropped_Call struct {
 	*mock.Call
 }
 
-// ObserveAppHandlersTime is a helper method to define mock.On call
-//   - handler string
-//   - elapsed float64
-func (_e *MockMetrics_Expecter) ObserveAppHandlersTime(handler interface{}, elapsed interface{}) *MockMetrics_ObserveAppHandlersTime_Call {
-	return &MockMetrics_ObserveAppHandlersTime_Call{Call: _e.mock.On("ObserveAppHandlersTime", handler, elapsed)}
+// IncLiveCaptionsWindowDropped is a helper method to define mock.On call
+func (_e *MockMetrics_Expecter) IncLiveCaptionsWindowDropped() *MockMetrics_IncLiveCaptionsWindowDropped_Call {
+	return &MockMetrics_IncLiveCaptionsWindowDropped_Call{Call: _e.mock.On("IncLiveCaptionsWindowDropped")}
 }
 
-func (_c *MockMetrics_ObserveAppHandlersTime_Call) Run(run func(handler string, elapsed float64)) *MockMetrics_ObserveAppHandlersTime_Call {
+func (_c *MockMetrics_IncLiveCaptionsWindowDropped_Call) Run(run func()) *MockMetrics_IncLiveCaptionsWindowDropped_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(string), args[1].(float64))
+		run()
 	})
 	return _c
 }
 
-func (_c *MockMetrics_ObserveAppHandlersTime_Call) Return() *MockMetrics_ObserveAppHandlersTime_Call {
+func (_c *MockMetrics_IncLiveCaptionsWindowDropped_Call) Return() *MockMetrics_IncLiveCaptionsWindowDropped_Call {
 	_c.Call.Return()
 	return _c
 }
 
-func (_c *MockMetrics_ObserveAppHandlersTime_Call) RunAndReturn(run func(string, float64)) *MockMetrics_ObserveAppHandlersTime_Call {
+func (_c *MockMetrics_IncLiveCaptionsWindowDropped_Call) RunAndReturn(run func()) *MockMetrics_IncLiveCaptionsWindowDropped_Call {
 	_c.Run(run)
 	return _c
 }
 
-// ObserveClusterMutexGrabTime provides a mock function with given fields: group, elapsed
-func (_m *MockMetrics) ObserveClusterMutexGrabTime(group string, elapsed float64) {
-	_m.Called(group, elapsed)
+// IncNoiseAutoMutes provides a mock function with no fields
+func (_m *MockMetrics) IncNoiseAutoMutes() {
+	_m.Called()
 }
 
-// MockMetrics_ObserveClusterMutexGrabTime_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ObserveClusterMutexGrabTime'
-type MockMetrics_ObserveClusterMutexGrabTime_Call struct {
+// MockMetrics_IncNoiseAutoMutes_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IncNoiseAutoMutes'
+type MockMetrics_IncNoiseAutoMutes_Call struct {
 	*mock.Call
 }
 
-// ObserveClusterMutexGrabTime is a helper method to define mock.On call
-//   - group string
-//   - elapsed float64
-func (_e *MockMetrics_Expecter) ObserveClusterMutexGrabTime(group interface{}, elapsed interface{}) *MockMetrics_ObserveClusterMutexGrabTime_Call {
-	return &MockMetrics_ObserveClusterMutexGrabTime_Call{Call: _e.mock.On("ObserveClusterMutexGrabTime", group, elapsed)}
+// IncNoiseAutoMutes is a helper method to define mock.On call
+func (_e *MockMetrics_Expecter) IncNoiseAutoMutes() *MockMetrics_IncNoiseAutoMutes_Call {
+	return &MockMetrics_IncNoiseAutoMutes_Call{Call: _e.mock.On("IncNoiseAutoMutes")}
 }
 
-func (_c *MockMetrics_ObserveClusterMutexGrabTime_Call) Run(run func(group string, elapsed float64)) *MockMetrics_ObserveClusterMutexGrabTime_Call {
+func (_c *MockMetrics_IncNoiseAutoMutes_Call) Run(run func()) *MockMetrics_IncNoiseAutoMutes_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(string), args[1].(float64))
+		run()
 	})
 	return _c
 }
 
-func (_c *MockMetrics_ObserveClusterMutexGrabTime_Call) Return() *MockMetrics_ObserveClusterMutexGrabTime_Call {
+func (_c *MockMetrics_IncNoiseAutoMutes_Call) Return() *MockMetrics_IncNoiseAutoMutes_Call {
 	_c.Call.Return()
 	return _c
 }
 
-func (_c *MockMetrics_ObserveClusterMutexGrabTime_Call) RunAndReturn(run func(string, float64)) *MockMetrics_ObserveClusterMutexGrabTime_Call {
+func (_c *MockMetrics_IncNoiseAutoMutes_Call) RunAndReturn(run func()) *MockMetrics_IncNoiseAutoMutes_Call {
 	_c.Run(run)
 	return _c
 }
 
-// ObserveClusterMutexLockedTime provides a mock function with given fields: group, elapsed
-func (_m *MockMetrics) ObserveClusterMutexLockedTime(group string, elapsed float64) {
-	_m.Called(group, elapsed)
+// IncRTCDMessageRetries provides a mock function with given fields: msgType
+func (_m *MockMetrics) IncRTCDMessageRetries(msgType string) {
+	_m.Called(msgType)
 }
 
-// MockMetrics_ObserveClusterMutexLockedTime_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ObserveClusterMutexLockedTime'
-type MockMetrics_ObserveClusterMutexLockedTime_Call struct {
+// MockMetrics_IncRTCDMessageRetries_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IncRTCDMessageRetries'
+type MockMetrics_IncRTCDMessageRetries_Call struct {
 	*mock.Call
 }
 
-// ObserveClusterMutexLockedTime is a helper method to define mock.On call
-//   - group string
-//   - elapsed float64
-func (_e *MockMetrics_Expecter) ObserveClusterMutexLockedTime(group interface{}, elapsed interface{}) *MockMetrics_ObserveClusterMutexLockedTime_Call {
-	return &MockMetrics_ObserveClusterMutexLockedTime_Call{Call: _e.mock.On("ObserveClusterMutexLockedTime", group, elapsed)}
+// IncRTCDMessageRetries is a helper method to define mock.On call
+//   - msgType string
+func (_e *MockMetrics_Expecter) IncRTCDMessageRetries(msgType interface{}) *MockMetrics_IncRTCDMessageRetries_Call {
+	return &MockMetrics_IncRTCDMessageRetries_Call{Call: _e.mock.On("IncRTCDMessageRetries", msgType)}
 }
 
-func (_c *MockMetrics_ObserveClusterMutexLockedTime_Call) Run(run func(group string, elapsed float64)) *MockMetrics_ObserveClusterMutexLockedTime_Call {
+func (_c *MockMetrics_IncRTCDMessageRetries_Call) Run(run func(msgType string)) *MockMetrics_IncRTCDMessageRetries_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(string), args[1].(float64))
+		run(args[0].(string))
 	})
 	return _c
 }
 
-func (_c *MockMetrics_ObserveClusterMutexLockedTime_Call) Return() *MockMetrics_ObserveClusterMutexLockedTime_Call {
+func (_c *MockMetrics_IncRTCDMessageRetries_Call) Return() *MockMetrics_IncRTCDMessageRetries_Call {
 	_c.Call.Return()
 	return _c
 }
 
-func (_c *MockMetrics_ObserveClusterMutexLockedTime_Call) RunAndReturn(run func(string, float64)) *MockMetrics_ObserveClusterMutexLockedTime_Call {
+func (_c *MockMetrics_IncRTCDMessageRetries_Call) RunAndReturn(run func(string)) *MockMetrics_IncRTCDMessageRetries_Call {
 	_c.Run(run)
 	return _c
 }
 
-// ObserveLiveCaptionsAudioLen provides a mock function with given fields: elapsed
-func (_m *MockMetrics) ObserveLiveCaptionsAudioLen(elapsed float64) {
-	_m.Called(elapsed)
+// IncRTCDMessagesDropped provides a mock function with given fields: msgType
+func (_m *MockMetrics) IncRTCDMessagesDropped(msgType string) {
+	_m.Called(msgType)
 }
 
-// MockMetrics_ObserveLiveCaptionsAudioLen_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ObserveLiveCaptionsAudioLen'
-type MockMetrics_ObserveLiveCaptionsAudioLen_Call struct {
+// MockMetrics_IncRTCDMessagesDropped_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IncRTCDMessagesDropped'
+type MockMetrics_IncRTCDMessagesDropped_Call struct {
 	*mock.Call
 }
 
-// ObserveLiveCaptionsAudioLen is a helper method to define mock.On call
-//   - elapsed float64
-func (_e *MockMetrics_Expecter) ObserveLiveCaptionsAudioLen(elapsed interface{}) *MockMetrics_ObserveLiveCaptionsAudioLen_Call {
-	return &MockMetrics_ObserveLiveCaptionsAudioLen_Call{Call: _e.mock.On("ObserveLiveCaptionsAudioLen", elapsed)}
+// IncRTCDMessagesDropped is a helper method to define mock.On call
+//   - msgType string
+func (_e *MockMetrics_Expecter) IncRTCDMessagesDropped(msgType interface{}) *MockMetrics_IncRTCDMessagesDropped_Call {
+	return &MockMetrics_IncRTCDMessagesDropped_Call{Call: _e.mock.On("IncRTCDMessagesDropped", msgType)}
 }
 
-func (_c *MockMetrics_ObserveLiveCaptionsAudioLen_Call) Run(run func(elapsed float64)) *MockMetrics_ObserveLiveCaptionsAudioLen_Call {
+func (_c *MockMetrics_IncRTCDMessagesDropped_Call) Run(run func(msgType string)) *MockMetrics_IncRTCDMessagesDropped_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(float64))
+		run(args[0].(string))
 	})
 	return _c
 }
 
-func (_c *MockMetrics_ObserveLiveCaptionsAudioLen_Call) Return() *MockMetrics_ObserveLiveCaptionsAudioLen_Call {
+func (_c *MockMetrics_IncRTCDMessagesDropped_Call) Return() *MockMetrics_IncRTCDMessagesDropped_Call {
 	_c.Call.Return()
 	return _c
 }
 
-func (_c *MockMetrics_ObserveLiveCaptionsAudioLen_Call) RunAndReturn(run func(float64)) *MockMetrics_ObserveLiveCaptionsAudioLen_Call {
+func (_c *MockMetrics_IncRTCDMessagesDropped_Call) RunAndReturn(run func(string)) *MockMetrics_IncRTCDMessagesDropped_Call {
 	_c.Run(run)
 	return _c
 }
 
-// ObserveStoreMethodsTime provides a mock function with given fields: method, elapsed
-func (_m *MockMetrics) ObserveStoreMethodsTime(method string, elapsed float64) {
-	_m.Called(method, elapsed)
+// IncRecordingStorageQuotaExceeded provides a mock function with given fields: scope, policy
+func (_m *MockMetrics) IncRecordingStorageQuotaExceeded(scope string, policy string) {
+	_m.Called(scope, policy)
 }
 
-// MockMetrics_ObserveStoreMethodsTime_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ObserveStoreMethodsTime'
-type MockMetrics_ObserveStoreMethodsTime_Call struct {
+// MockMetrics_IncRecordingStorageQuotaExceeded_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IncRecordingStorageQuotaExceeded'
+type MockMetrics_IncRecordingStorageQuotaExceeded_Call struct {
 	*mock.Call
 }
 
-// ObserveStoreMethodsTime is a helper method to define mock.On call
-//   - method string
-//   - elapsed float64
-func (_e *MockMetrics_Expecter) ObserveStoreMethodsTime(method interface{}, elapsed interface{}) *MockMetrics_ObserveStoreMethodsTime_Call {
-	return &MockMetrics_ObserveStoreMethodsTime_Call{Call: _e.mock.On("ObserveStoreMethodsTime", method, elapsed)}
+// IncRecordingStorageQuotaExceeded is a helper method to define mock.On call
+//   - scope string
+//   - policy string
+func (_e *MockMetrics_Expecter) IncRecordingStorageQuotaExceeded(scope interface{}, policy interface{}) *MockMetrics_IncRecordingStorageQuotaExceeded_Call {
+	return &MockMetrics_IncRecordingStorageQuotaExceeded_Call{Call: _e.mock.On("IncRecordingStorageQuotaExceeded", scope, policy)}
 }
 
-func (_c *MockMetrics_ObserveStoreMethodsTime_Call) Run(run func(method string, elapsed float64)) *MockMetrics_ObserveStoreMethodsTime_Call {
+func (_c *MockMetrics_IncRecordingStorageQuotaExceeded_Call) Run(run func(scope string, policy string)) *MockMetrics_IncRecordingStorageQuotaExceeded_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(string), args[1].(float64))
+		run(args[0].(string), args[1].(string))
 	})
 	return _c
 }
 
-func (_c *MockMetrics_ObserveStoreMethodsTime_Call) Return() *MockMetrics_ObserveStoreMethodsTime_Call {
+func (_c *MockMetrics_IncRecordingStorageQuotaExceeded_Call) Return() *MockMetrics_IncRecordingStorageQuotaExceeded_Call {
 	_c.Call.Return()
 	return _c
 }
 
-func (_c *MockMetrics_ObserveStoreMethodsTime_Call) RunAndReturn(run func(string, float64)) *MockMetrics_ObserveStoreMethodsTime_Call {
+func (_c *MockMetrics_IncRecordingStorageQuotaExceeded_Call) RunAndReturn(run func(string, string)) *MockMetrics_IncRecordingStorageQuotaExceeded_Call {
 	_c.Run(run)
 	return _c
 }
 
-// RTCMetrics provides a mock function with no fields
-func (_m *MockMetrics) RTCMetrics() rtc.Metrics {
-	ret := _m.Called()
+// IncRejectedSDPs provides a mock function with given fields: reason
+func (_m *MockMetrics) IncRejectedSDPs(reason string) {
+	_m.Called(reason)
+}
 
-	if len(ret) == 0 {
-		panic("no return value specified for RTCMetrics")
-	}
+// MockMetrics_IncRejectedSDPs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IncRejectedSDPs'
+type MockMetrics_IncRejectedSDPs_Call struct {
+	*mock.Call
+}
 
-	var r0 rtc.Metrics
-	if rf, ok := ret.Get(0).(func() rtc.Metrics); ok {
-		r0 = rf()
-	} else {
-		if ret.Get(0) != nil {
+// IncRejectedSDPs is a helper method to define mock.On call
+//   - reason string
+func (_e *MockMetrics_Expecter) IncRejectedSDPs(reason interface{}) *MockMetrics_IncRejectedSDPs_Call {
+	return &MockMetrics_IncRejectedSDPs_Call{Call: _e.mock.On("IncRejectedSDPs", reason)}
+}
+
+func (_c *MockMetrics_IncRejectedSDPs_Call) Run(run func(reason string)) *MockMetrics_IncRejectedSDPs_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *MockMetrics_IncRejectedSDPs_Call) Return() *MockMetrics_IncRejectedSDPs_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockMetrics_IncRejectedSDPs_Call) RunAndReturn(run func(string)) *MockMetrics_IncRejectedSDPs_Call {
+	_c.Run(run)
+	return _c
+}
+
+// IncStoreErrors provides a mock function with given fields: method, errType
+func (_m *MockMetrics) IncStoreErrors(method string, errType string) {
+	_m.Called(method, errType)
+}
+
+// MockMetrics_IncStoreErrors_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IncStoreErrors'
+type MockMetrics_IncStoreErrors_Call struct {
+	*mock.Call
+}
+
+// IncStoreErrors is a helper method to define mock.On call
+//   - method string
+//   - errType string
+func (_e *MockMetrics_Expecter) IncStoreErrors(method interface{}, errType interface{}) *MockMetrics_IncStoreErrors_Call {
+	return &MockMetrics_IncStoreErrors_Call{Call: _e.mock.On("IncStoreErrors", method, errType)}
+}
+
+func (_c *MockMetrics_IncStoreErrors_Call) Run(run func(method string, errType string)) *MockMetrics_IncStoreErrors_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockMetrics_IncStoreErrors_Call) Return() *MockMetrics_IncStoreErrors_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockMetrics_IncStoreErrors_Call) RunAndReturn(run func(string, string)) *MockMetrics_IncStoreErrors_Call {
+	_c.Run(run)
+	return _c
+}
+
+// IncStoreOp provides a mock function with given fields: op
+func (_m *MockMetrics) IncStoreOp(op string) {
+	_m.Called(op)
+}
+
+// MockMetrics_IncStoreOp_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IncStoreOp'
+type MockMetrics_IncStoreOp_Call struct {
+	*mock.Call
+}
+
+// IncStoreOp is a helper method to define mock.On call
+//   - op string
+func (_e *MockMetrics_Expecter) IncStoreOp(op interface{}) *MockMetrics_IncStoreOp_Call {
+	return &MockMetrics_IncStoreOp_Call{Call: _e.mock.On("IncStoreOp", op)}
+}
+
+func (_c *MockMetrics_IncStoreOp_Call) Run(run func(op string)) *MockMetrics_IncStoreOp_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *MockMetrics_IncStoreOp_Call) Return() *MockMetrics_IncStoreOp_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockMetrics_IncStoreOp_Call) RunAndReturn(run func(string)) *MockMetrics_IncStoreOp_Call {
+	_c.Run(run)
+	return _c
+}
+
+// IncThrottledSessions provides a mock function with no fields
+func (_m *MockMetrics) IncThrottledSessions() {
+	_m.Called()
+}
+
+// MockMetrics_IncThrottledSessions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IncThrottledSessions'
+type MockMetrics_IncThrottledSessions_Call struct {
+	*mock.Call
+}
+
+// IncThrottledSessions is a helper method to define mock.On call
+func (_e *MockMetrics_Expecter) IncThrottledSessions() *MockMetrics_IncThrottledSessions_Call {
+	return &MockMetrics_IncThrottledSessions_Call{Call: _e.mock.On("IncThrottledSessions")}
+}
+
+func (_c *MockMetrics_IncThrottledSessions_Call) Run(run func()) *MockMetrics_IncThrottledSessions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockMetrics_IncThrottledSessions_Call) Return() *MockMetrics_IncThrottledSessions_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockMetrics_IncThrottledSessions_Call) RunAndReturn(run func()) *MockMetrics_IncThrottledSessions_Call {
+	_c.Run(run)
+	return _c
+}
+
+// IncWebSocketConn provides a mock function with no fields
+func (_m *MockMetrics) IncWebSocketConn() {
+	_m.Called()
+}
+
+// MockMetrics_IncWebSocketConn_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IncWebSocketConn'
+type MockMetrics_IncWebSocketConn_Call struct {
+	*mock.Call
+}
+
+// IncWebSocketConn is a helper method to define mock.On call
+func (_e *MockMetrics_Expecter) IncWebSocketConn() *MockMetrics_IncWebSocketConn_Call {
+	return &MockMetrics_IncWebSocketConn_Call{Call: _e.mock.On("IncWebSocketConn")}
+}
+
+func (_c *MockMetrics_IncWebSocketConn_Call) Run(run func()) *MockMetrics_IncWebSocketConn_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockMetrics_IncWebSocketConn_Call) Return() *MockMetrics_IncWebSocketConn_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockMetrics_IncWebSocketConn_Call) RunAndReturn(run func()) *MockMetrics_IncWebSocketConn_Call {
+	_c.Run(run)
+	return _c
+}
+
+// IncWebSocketEvent provides a mock function with given fields: direction, evType
+func (_m *MockMetrics) IncWebSocketEvent(direction string, evType string) {
+	_m.Called(direction, evType)
+}
+
+// MockMetrics_IncWebSocketEvent_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IncWebSocketEvent'
+type MockMetrics_IncWebSocketEvent_Call struct {
+	*mock.Call
+}
+
+// IncWebSocketEvent is a helper method to define mock.On call
+//   - direction string
+//   - evType string
+func (_e *MockMetrics_Expecter) IncWebSocketEvent(direction interface{}, evType interface{}) *MockMetrics_IncWebSocketEvent_Call {
+	return &MockMetrics_IncWebSocketEvent_Call{Call: _e.mock.On("IncWebSocketEvent", direction, evType)}
+}
+
+func (_c *MockMetrics_IncWebSocketEvent_Call) Run(run func(direction string, evType string)) *MockMetrics_IncWebSocketEvent_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockMetrics_IncWebSocketEvent_Call) Return() *MockMetrics_IncWebSocketEvent_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockMetrics_IncWebSocketEvent_Call) RunAndReturn(run func(string, string)) *MockMetrics_IncWebSocketEvent_Call {
+	_c.Run(run)
+	return _c
+}
+
+// IncZombieSessions provides a mock function with no fields
+func (_m *MockMetrics) IncZombieSessions() {
+	_m.Called()
+}
+
+// MockMetrics_IncZombieSessions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IncZombieSessions'
+type MockMetrics_IncZombieSessions_Call struct {
+	*mock.Call
+}
+
+// IncZombieSessions is a helper method to define mock.On call
+func (_e *MockMetrics_Expecter) IncZombieSessions() *MockMetrics_IncZombieSessions_Call {
+	return &MockMetrics_IncZombieSessions_Call{Call: _e.mock.On("IncZombieSessions")}
+}
+
+func (_c *MockMetrics_IncZombieSessions_Call) Run(run func()) *MockMetrics_IncZombieSessions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockMetrics_IncZombieSessions_Call) Return() *MockMetrics_IncZombieSessions_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockMetrics_IncZombieSessions_Call) RunAndReturn(run func()) *MockMetrics_IncZombieSessions_Call {
+	_c.Run(run)
+	return _c
+}
+
+// ObserveAppHandlersTime provides a mock function with given fields: handler, elapsed
+func (_m *MockMetrics) ObserveAppHandlersTime(handler string, elapsed float64) {
+	_m.Called(handler, elapsed)
+}
+
+// MockMetrics_ObserveAppHandlersTime_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ObserveAppHandlersTime'
+type MockMetrics_ObserveAppHandlersTime_Call struct {
+	*mock.Call
+}
+
+// ObserveAppHandlersTime is a helper method to define mock.On call
+//   - handler string
+//   - elapsed float64
+func (_e *MockMetrics_Expecter) ObserveAppHandlersTime(handler interface{}, elapsed interface{}) *MockMetrics_ObserveAppHandlersTime_Call {
+	return &MockMetrics_ObserveAppHandlersTime_Call{Call: _e.mock.On("ObserveAppHandlersTime", handler, elapsed)}
+}
+
+func (_c *MockMetrics_ObserveAppHandlersTime_Call) Run(run func(handler string, elapsed float64)) *MockMetrics_ObserveAppHandlersTime_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(float64))
+	})
+	return _c
+}
+
+func (_c *MockMetrics_ObserveAppHandlersTime_Call) Return() *MockMetrics_ObserveAppHandlersTime_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockMetrics_ObserveAppHandlersTime_Call) RunAndReturn(run func(string, float64)) *MockMetrics_ObserveAppHandlersTime_Call {
+	_c.Run(run)
+	return _c
+}
+
+// ObserveCallFeedbackScore provides a mock function with given fields: score
+func (_m *MockMetrics) ObserveCallFeedbackScore(score int) {
+	_m.Called(score)
+}
+
+// MockMetrics_ObserveCallFeedbackScore_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ObserveCallFeedbackScore'
+type MockMetrics_ObserveCallFeedbackScore_Call struct {
+	*mock.Call
+}
+
+// ObserveCallFeedbackScore is a helper method to define mock.On call
+//   - score int
+func (_e *MockMetrics_Expecter) ObserveCallFeedbackScore(score interface{}) *MockMetrics_ObserveCallFeedbackScore_Call {
+	return &MockMetrics_ObserveCallFeedbackScore_Call{Call: _e.mock.On("ObserveCallFeedbackScore", score)}
+}
+
+func (_c *MockMetrics_ObserveCallFeedbackScore_Call) Run(run func(score int)) *MockMetrics_ObserveCallFeedbackScore_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(int))
+	})
+	return _c
+}
+
+func (_c *MockMetrics_ObserveCallFeedbackScore_Call) Return() *MockMetrics_ObserveCallFeedbackScore_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockMetrics_ObserveCallFeedbackScore_Call) RunAndReturn(run func(int)) *MockMetrics_ObserveCallFeedbackScore_Call {
+	_c.Run(run)
+	return _c
+}
+
+// ObserveCleanUpStateTime provides a mock function with given fields: elapsed
+func (_m *MockMetrics) ObserveCleanUpStateTime(elapsed float64) {
+	_m.Called(elapsed)
+}
+
+// MockMetrics_ObserveCleanUpStateTime_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ObserveCleanUpStateTime'
+type MockMetrics_ObserveCleanUpStateTime_Call struct {
+	*mock.Call
+}
+
+// ObserveCleanUpStateTime is a helper method to define mock.On call
+//   - elapsed float64
+func (_e *MockMetrics_Expecter) ObserveCleanUpStateTime(elapsed interface{}) *MockMetrics_ObserveCleanUpStateTime_Call {
+	return &MockMetrics_ObserveCleanUpStateTime_Call{Call: _e.mock.On("ObserveCleanUpStateTime", elapsed)}
+}
+
+func (_c *MockMetrics_ObserveCleanUpStateTime_Call) Run(run func(elapsed float64)) *MockMetrics_ObserveCleanUpStateTime_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(float64))
+	})
+	return _c
+}
+
+func (_c *MockMetrics_ObserveCleanUpStateTime_Call) Return() *MockMetrics_ObserveCleanUpStateTime_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockMetrics_ObserveCleanUpStateTime_Call) RunAndReturn(run func(float64)) *MockMetrics_ObserveCleanUpStateTime_Call {
+	_c.Run(run)
+	return _c
+}
+
+// ObserveClientJitterBufferDelay provides a mock function with given fields: delayMs
+func (_m *MockMetrics) ObserveClientJitterBufferDelay(delayMs float64) {
+	_m.Called(delayMs)
+}
+
+// MockMetrics_ObserveClientJitterBufferDelay_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ObserveClientJitterBufferDelay'
+type MockMetrics_ObserveClientJitterBufferDelay_Call struct {
+	*mock.Call
+}
+
+// ObserveClientJitterBufferDelay is a helper method to define mock.On call
+//   - delayMs float64
+func (_e *MockMetrics_Expecter) ObserveClientJitterBufferDelay(delayMs interface{}) *MockMetrics_ObserveClientJitterBufferDelay_Call {
+	return &MockMetrics_ObserveClientJitterBufferDelay_Call{Call: _e.mock.On("ObserveClientJitterBufferDelay", delayMs)}
+}
+
+func (_c *MockMetrics_ObserveClientJitterBufferDelay_Call) Run(run func(delayMs float64)) *MockMetrics_ObserveClientJitterBufferDelay_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(float64))
+	})
+	return _c
+}
+
+func (_c *MockMetrics_ObserveClientJitterBufferDelay_Call) Return() *MockMetrics_ObserveClientJitterBufferDelay_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockMetrics_ObserveClientJitterBufferDelay_Call) RunAndReturn(run func(float64)) *MockMetrics_ObserveClientJitterBufferDelay_Call {
+	_c.Run(run)
+	return _c
+}
+
+// ObserveClientPacketLoss provides a mock function with given fields: serverType, lossRate
+func (_m *MockMetrics) ObserveClientPacketLoss(serverType string, lossRate float64) {
+	_m.Called(serverType, lossRate)
+}
+
+// MockMetrics_ObserveClientPacketLoss_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ObserveClientPacketLoss'
+type MockMetrics_ObserveClientPacketLoss_Call struct {
+	*mock.Call
+}
+
+// ObserveClientPacketLoss is a helper method to define mock.On call
+//   - serverType string
+//   - lossRate float64
+func (_e *MockMetrics_Expecter) ObserveClientPacketLoss(serverType interface{}, lossRate interface{}) *MockMetrics_ObserveClientPacketLoss_Call {
+	return &MockMetrics_ObserveClientPacketLoss_Call{Call: _e.mock.On("ObserveClientPacketLoss", serverType, lossRate)}
+}
+
+func (_c *MockMetrics_ObserveClientPacketLoss_Call) Run(run func(serverType string, lossRate float64)) *MockMetrics_ObserveClientPacketLoss_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(float64))
+	})
+	return _c
+}
+
+func (_c *MockMetrics_ObserveClientPacketLoss_Call) Return() *MockMetrics_ObserveClientPacketLoss_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockMetrics_ObserveClientPacketLoss_Call) RunAndReturn(run func(string, float64)) *MockMetrics_ObserveClientPacketLoss_Call {
+	_c.Run(run)
+	return _c
+}
+
+// ObserveClusterMutexGrabTime provides a mock function with given fields: group, elapsed
+func (_m *MockMetrics) ObserveClusterMutexGrabTime(group string, elapsed float64) {
+	_m.Called(group, elapsed)
+}
+
+// MockMetrics_ObserveClusterMutexGrabTime_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ObserveClusterMutexGrabTime'
+type MockMetrics_ObserveClusterMutexGrabTime_Call struct {
+	*mock.Call
+}
+
+// ObserveClusterMutexGrabTime is a helper method to define mock.On call
+//   - group string
+//   - elapsed float64
+func (_e *MockMetrics_Expecter) ObserveClusterMutexGrabTime(group interface{}, elapsed interface{}) *MockMetrics_ObserveClusterMutexGrabTime_Call {
+	return &MockMetrics_ObserveClusterMutexGrabTime_Call{Call: _e.mock.On("ObserveClusterMutexGrabTime", group, elapsed)}
+}
+
+func (_c *MockMetrics_ObserveClusterMutexGrabTime_Call) Run(run func(group string, elapsed float64)) *MockMetrics_ObserveClusterMutexGrabTime_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(float64))
+	})
+	return _c
+}
+
+func (_c *MockMetrics_ObserveClusterMutexGrabTime_Call) Return() *MockMetrics_ObserveClusterMutexGrabTime_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockMetrics_ObserveClusterMutexGrabTime_Call) RunAndReturn(run func(string, float64)) *MockMetrics_ObserveClusterMutexGrabTime_Call {
+	_c.Run(run)
+	return _c
+}
+
+// ObserveClusterMutexLockedTime provides a mock function with given fields: group, elapsed
+func (_m *MockMetrics) ObserveClusterMutexLockedTime(group string, elapsed float64) {
+	_m.Called(group, elapsed)
+}
+
+// MockMetrics_ObserveClusterMutexLockedTime_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ObserveClusterMutexLockedTime'
+type MockMetrics_ObserveClusterMutexLockedTime_Call struct {
+	*mock.Call
+}
+
+// ObserveClusterMutexLockedTime is a helper method to define mock.On call
+//   - group string
+//   - elapsed float64
+func (_e *MockMetrics_Expecter) ObserveClusterMutexLockedTime(group interface{}, elapsed interface{}) *MockMetrics_ObserveClusterMutexLockedTime_Call {
+	return &MockMetrics_ObserveClusterMutexLockedTime_Call{Call: _e.mock.On("ObserveClusterMutexLockedTime", group, elapsed)}
+}
+
+func (_c *MockMetrics_ObserveClusterMutexLockedTime_Call) Run(run func(group string, elapsed float64)) *MockMetrics_ObserveClusterMutexLockedTime_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(float64))
+	})
+	return _c
+}
+
+func (_c *MockMetrics_ObserveClusterMutexLockedTime_Call) Return() *MockMetrics_ObserveClusterMutexLockedTime_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockMetrics_ObserveClusterMutexLockedTime_Call) RunAndReturn(run func(string, float64)) *MockMetrics_ObserveClusterMutexLockedTime_Call {
+	_c.Run(run)
+	return _c
+}
+
+// ObserveLiveCaptionsAudioLen provides a mock function with given fields: elapsed
+func (_m *MockMetrics) ObserveLiveCaptionsAudioLen(elapsed float64) {
+	_m.Called(elapsed)
+}
+
+// MockMetrics_ObserveLiveCaptionsAudioLen_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ObserveLiveCaptionsAudioLen'
+type MockMetrics_ObserveLiveCaptionsAudioLen_Call struct {
+	*mock.Call
+}
+
+// ObserveLiveCaptionsAudioLen is a helper method to define mock.On call
+//   - elapsed float64
+func (_e *MockMetrics_Expecter) ObserveLiveCaptionsAudioLen(elapsed interface{}) *MockMetrics_ObserveLiveCaptionsAudioLen_Call {
+	return &MockMetrics_ObserveLiveCaptionsAudioLen_Call{Call: _e.mock.On("ObserveLiveCaptionsAudioLen", elapsed)}
+}
+
+func (_c *MockMetrics_ObserveLiveCaptionsAudioLen_Call) Run(run func(elapsed float64)) *MockMetrics_ObserveLiveCaptionsAudioLen_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(float64))
+	})
+	return _c
+}
+
+func (_c *MockMetrics_ObserveLiveCaptionsAudioLen_Call) Return() *MockMetrics_ObserveLiveCaptionsAudioLen_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockMetrics_ObserveLiveCaptionsAudioLen_Call) RunAndReturn(run func(float64)) *MockMetrics_ObserveLiveCaptionsAudioLen_Call {
+	_c.Run(run)
+	return _c
+}
+
+// ObserveStoreMethodsTime provides a mock function with given fields: method, elapsed
+func (_m *MockMetrics) ObserveStoreMethodsTime(method string, elapsed float64) {
+	_m.Called(method, elapsed)
+}
+
+// MockMetrics_ObserveStoreMethodsTime_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ObserveStoreMethodsTime'
+type MockMetrics_ObserveStoreMethodsTime_Call struct {
+	*mock.Call
+}
+
+// ObserveStoreMethodsTime is a helper method to define mock.On call
+//   - method string
+//   - elapsed float64
+func (_e *MockMetrics_Expecter) ObserveStoreMethodsTime(method interface{}, elapsed interface{}) *MockMetrics_ObserveStoreMethodsTime_Call {
+	return &MockMetrics_ObserveStoreMethodsTime_Call{Call: _e.mock.On("ObserveStoreMethodsTime", method, elapsed)}
+}
+
+func (_c *MockMetrics_ObserveStoreMethodsTime_Call) Run(run func(method string, elapsed float64)) *MockMetrics_ObserveStoreMethodsTime_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(float64))
+	})
+	return _c
+}
+
+func (_c *MockMetrics_ObserveStoreMethodsTime_Call) Return() *MockMetrics_ObserveStoreMethodsTime_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockMetrics_ObserveStoreMethodsTime_Call) RunAndReturn(run func(string, float64)) *MockMetrics_ObserveStoreMethodsTime_Call {
+	_c.Run(run)
+	return _c
+}
+
+// ObserveTranscriptionQueueWaitTime provides a mock function with given fields: elapsed
+func (_m *MockMetrics) ObserveTranscriptionQueueWaitTime(elapsed float64) {
+	_m.Called(elapsed)
+}
+
+// MockMetrics_ObserveTranscriptionQueueWaitTime_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ObserveTranscriptionQueueWaitTime'
+type MockMetrics_ObserveTranscriptionQueueWaitTime_Call struct {
+	*mock.Call
+}
+
+// ObserveTranscriptionQueueWaitTime is a helper method to define mock.On call
+//   - elapsed float64
+func (_e *MockMetrics_Expecter) ObserveTranscriptionQueueWaitTime(elapsed interface{}) *MockMetrics_ObserveTranscriptionQueueWaitTime_Call {
+	return &MockMetrics_ObserveTranscriptionQueueWaitTime_Call{Call: _e.mock.On("ObserveTranscriptionQueueWaitTime", elapsed)}
+}
+
+func (_c *MockMetrics_ObserveTranscriptionQueueWaitTime_Call) Run(run func(elapsed float64)) *MockMetrics_ObserveTranscriptionQueueWaitTime_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(float64))
+	})
+	return _c
+}
+
+func (_c *MockMetrics_ObserveTranscriptionQueueWaitTime_Call) Return() *MockMetrics_ObserveTranscriptionQueueWaitTime_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockMetrics_ObserveTranscriptionQueueWaitTime_Call) RunAndReturn(run func(float64)) *MockMetrics_ObserveTranscriptionQueueWaitTime_Call {
+	_c.Run(run)
+	return _c
+}
+
+// ObserveWebSocketWriterMessage provides a mock function with given fields: msgType, size
+func (_m *MockMetrics) ObserveWebSocketWriterMessage(msgType string, size int) {
+	_m.Called(msgType, size)
+}
+
+// MockMetrics_ObserveWebSocketWriterMessage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ObserveWebSocketWriterMessage'
+type MockMetrics_ObserveWebSocketWriterMessage_Call struct {
+	*mock.Call
+}
+
+// ObserveWebSocketWriterMessage is a helper method to define mock.On call
+//   - msgType string
+//   - size int
+func (_e *MockMetrics_Expecter) ObserveWebSocketWriterMessage(msgType interface{}, size interface{}) *MockMetrics_ObserveWebSocketWriterMessage_Call {
+	return &MockMetrics_ObserveWebSocketWriterMessage_Call{Call: _e.mock.On("ObserveWebSocketWriterMessage", msgType, size)}
+}
+
+func (_c *MockMetrics_ObserveWebSocketWriterMessage_Call) Run(run func(msgType string, size int)) *MockMetrics_ObserveWebSocketWriterMessage_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockMetrics_ObserveWebSocketWriterMessage_Call) Return() *MockMetrics_ObserveWebSocketWriterMessage_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockMetrics_ObserveWebSocketWriterMessage_Call) RunAndReturn(run func(string, int)) *MockMetrics_ObserveWebSocketWriterMessage_Call {
+	_c.Run(run)
+	return _c
+}
+
+// RTCMetrics provides a mock function with no fields
+func (_m *MockMetrics) RTCMetrics() rtc.Metrics {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for RTCMetrics")
+	}
+
+	var r0 rtc.Metrics
+	if rf, ok := ret.Get(0).(func() rtc.Metrics); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(rtc.Metrics)
 		}
 	}
@@ -650,6 +1346,206 @@ func (_c *MockMetrics_RegisterDBMetrics_Call) RunAndReturn(run func(*sql.DB, str
 	return _c
 }
 
+// SetRTCDConnectionAge provides a mock function with given fields: host, age
+func (_m *MockMetrics) SetRTCDConnectionAge(host string, age float64) {
+	_m.Called(host, age)
+}
+
+// MockMetrics_SetRTCDConnectionAge_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetRTCDConnectionAge'
+type MockMetrics_SetRTCDConnectionAge_Call struct {
+	*mock.Call
+}
+
+// SetRTCDConnectionAge is a helper method to define mock.On call
+//   - host string
+//   - age float64
+func (_e *MockMetrics_Expecter) SetRTCDConnectionAge(host interface{}, age interface{}) *MockMetrics_SetRTCDConnectionAge_Call {
+	return &MockMetrics_SetRTCDConnectionAge_Call{Call: _e.mock.On("SetRTCDConnectionAge", host, age)}
+}
+
+func (_c *MockMetrics_SetRTCDConnectionAge_Call) Run(run func(host string, age float64)) *MockMetrics_SetRTCDConnectionAge_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(float64))
+	})
+	return _c
+}
+
+func (_c *MockMetrics_SetRTCDConnectionAge_Call) Return() *MockMetrics_SetRTCDConnectionAge_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockMetrics_SetRTCDConnectionAge_Call) RunAndReturn(run func(string, float64)) *MockMetrics_SetRTCDConnectionAge_Call {
+	_c.Run(run)
+	return _c
+}
+
+// SetRTCDLastPingTime provides a mock function with given fields: host, ts
+func (_m *MockMetrics) SetRTCDLastPingTime(host string, ts float64) {
+	_m.Called(host, ts)
+}
+
+// MockMetrics_SetRTCDLastPingTime_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetRTCDLastPingTime'
+type MockMetrics_SetRTCDLastPingTime_Call struct {
+	*mock.Call
+}
+
+// SetRTCDLastPingTime is a helper method to define mock.On call
+//   - host string
+//   - ts float64
+func (_e *MockMetrics_Expecter) SetRTCDLastPingTime(host interface{}, ts interface{}) *MockMetrics_SetRTCDLastPingTime_Call {
+	return &MockMetrics_SetRTCDLastPingTime_Call{Call: _e.mock.On("SetRTCDLastPingTime", host, ts)}
+}
+
+func (_c *MockMetrics_SetRTCDLastPingTime_Call) Run(run func(host string, ts float64)) *MockMetrics_SetRTCDLastPingTime_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(float64))
+	})
+	return _c
+}
+
+func (_c *MockMetrics_SetRTCDLastPingTime_Call) Return() *MockMetrics_SetRTCDLastPingTime_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockMetrics_SetRTCDLastPingTime_Call) RunAndReturn(run func(string, float64)) *MockMetrics_SetRTCDLastPingTime_Call {
+	_c.Run(run)
+	return _c
+}
+
+// SetRecordingStorageBytes provides a mock function with given fields: bytes
+func (_m *MockMetrics) SetRecordingStorageBytes(bytes int64) {
+	_m.Called(bytes)
+}
+
+// MockMetrics_SetRecordingStorageBytes_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetRecordingStorageBytes'
+type MockMetrics_SetRecordingStorageBytes_Call struct {
+	*mock.Call
+}
+
+// SetRecordingStorageBytes is a helper method to define mock.On call
+//   - bytes int64
+func (_e *MockMetrics_Expecter) SetRecordingStorageBytes(bytes interface{}) *MockMetrics_SetRecordingStorageBytes_Call {
+	return &MockMetrics_SetRecordingStorageBytes_Call{Call: _e.mock.On("SetRecordingStorageBytes", bytes)}
+}
+
+func (_c *MockMetrics_SetRecordingStorageBytes_Call) Run(run func(bytes int64)) *MockMetrics_SetRecordingStorageBytes_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(int64))
+	})
+	return _c
+}
+
+func (_c *MockMetrics_SetRecordingStorageBytes_Call) Return() *MockMetrics_SetRecordingStorageBytes_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockMetrics_SetRecordingStorageBytes_Call) RunAndReturn(run func(int64)) *MockMetrics_SetRecordingStorageBytes_Call {
+	_c.Run(run)
+	return _c
+}
+
+// SetStoreBufferedWrites provides a mock function with given fields: count
+func (_m *MockMetrics) SetStoreBufferedWrites(count int) {
+	_m.Called(count)
+}
+
+// MockMetrics_SetStoreBufferedWrites_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetStoreBufferedWrites'
+type MockMetrics_SetStoreBufferedWrites_Call struct {
+	*mock.Call
+}
+
+// SetStoreBufferedWrites is a helper method to define mock.On call
+//   - count int
+func (_e *MockMetrics_Expecter) SetStoreBufferedWrites(count interface{}) *MockMetrics_SetStoreBufferedWrites_Call {
+	return &MockMetrics_SetStoreBufferedWrites_Call{Call: _e.mock.On("SetStoreBufferedWrites", count)}
+}
+
+func (_c *MockMetrics_SetStoreBufferedWrites_Call) Run(run func(count int)) *MockMetrics_SetStoreBufferedWrites_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(int))
+	})
+	return _c
+}
+
+func (_c *MockMetrics_SetStoreBufferedWrites_Call) Return() *MockMetrics_SetStoreBufferedWrites_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockMetrics_SetStoreBufferedWrites_Call) RunAndReturn(run func(int)) *MockMetrics_SetStoreBufferedWrites_Call {
+	_c.Run(run)
+	return _c
+}
+
+// SetTranscriptionQueueDepth provides a mock function with given fields: depth
+func (_m *MockMetrics) SetTranscriptionQueueDepth(depth int) {
+	_m.Called(depth)
+}
+
+// MockMetrics_SetTranscriptionQueueDepth_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetTranscriptionQueueDepth'
+type MockMetrics_SetTranscriptionQueueDepth_Call struct {
+	*mock.Call
+}
+
+// SetTranscriptionQueueDepth is a helper method to define mock.On call
+//   - depth int
+func (_e *MockMetrics_Expecter) SetTranscriptionQueueDepth(depth interface{}) *MockMetrics_SetTranscriptionQueueDepth_Call {
+	return &MockMetrics_SetTranscriptionQueueDepth_Call{Call: _e.mock.On("SetTranscriptionQueueDepth", depth)}
+}
+
+func (_c *MockMetrics_SetTranscriptionQueueDepth_Call) Run(run func(depth int)) *MockMetrics_SetTranscriptionQueueDepth_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(int))
+	})
+	return _c
+}
+
+func (_c *MockMetrics_SetTranscriptionQueueDepth_Call) Return() *MockMetrics_SetTranscriptionQueueDepth_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockMetrics_SetTranscriptionQueueDepth_Call) RunAndReturn(run func(int)) *MockMetrics_SetTranscriptionQueueDepth_Call {
+	_c.Run(run)
+	return _c
+}
+
+// SetWebSocketWriterQueueDepth provides a mock function with given fields: depth
+func (_m *MockMetrics) SetWebSocketWriterQueueDepth(depth int) {
+	_m.Called(depth)
+}
+
+// MockMetrics_SetWebSocketWriterQueueDepth_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetWebSocketWriterQueueDepth'
+type MockMetrics_SetWebSocketWriterQueueDepth_Call struct {
+	*mock.Call
+}
+
+// SetWebSocketWriterQueueDepth is a helper method to define mock.On call
+//   - depth int
+func (_e *MockMetrics_Expecter) SetWebSocketWriterQueueDepth(depth interface{}) *MockMetrics_SetWebSocketWriterQueueDepth_Call {
+	return &MockMetrics_SetWebSocketWriterQueueDepth_Call{Call: _e.mock.On("SetWebSocketWriterQueueDepth", depth)}
+}
+
+func (_c *MockMetrics_SetWebSocketWriterQueueDepth_Call) Run(run func(depth int)) *MockMetrics_SetWebSocketWriterQueueDepth_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(int))
+	})
+	return _c
+}
+
+func (_c *MockMetrics_SetWebSocketWriterQueueDepth_Call) Return() *MockMetrics_SetWebSocketWriterQueueDepth_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockMetrics_SetWebSocketWriterQueueDepth_Call) RunAndReturn(run func(int)) *MockMetrics_SetWebSocketWriterQueueDepth_Call {
+	_c.Run(run)
+	return _c
+}
+
 // NewMockMetrics creates a new instance of MockMetrics. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMockMetrics(t interface {
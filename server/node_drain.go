@@ -0,0 +1,378 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/mattermost/mattermost-plugin-calls/server/db"
+	"github.com/mattermost/mattermost-plugin-calls/server/public"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+const (
+	nodeDrainKeyPrefix           = "node_drain_"
+	defaultNodeDrainGracePeriod  = 5 * time.Minute
+	maxNodeDrainGracePeriodInSec = 24 * 60 * 60
+)
+
+var (
+	errNodeDraining  = errors.New("node is draining")
+	nodeDrainIDRegex = regexp.MustCompile(`^[a-zA-Z0-9.:_-]{1,64}$`)
+)
+
+// nodeDrain holds the state of a drain requested by an admin. The node ID
+// is either the address of an rtcd host or, when using the embedded RTC
+// server, the ID of a cluster node.
+type nodeDrain struct {
+	NodeID      string `json:"node_id"`
+	RequesterID string `json:"requester_id"`
+	StartAt     int64  `json:"start_at"`
+	// EndCallsAt is the time ongoing calls on the node are ended at. It's
+	// zero if calls are left to finish on their own.
+	EndCallsAt int64 `json:"end_calls_at,omitempty"`
+}
+
+type nodeDrainRequest struct {
+	// EndCalls is whether ongoing calls should be ended once the grace
+	// period expires.
+	EndCalls bool `json:"end_calls"`
+	// GracePeriod is the time, in seconds, participants are given before
+	// their calls are ended.
+	GracePeriod *int `json:"grace_period,omitempty"`
+}
+
+type nodeDrainStatus struct {
+	nodeDrain
+	// CallIDs are the ongoing calls still hosted by the node.
+	CallIDs []string `json:"call_ids"`
+	// Done is whether the node no longer hosts any call.
+	Done bool `json:"done"`
+}
+
+func (p *Plugin) getNodeDrain(nodeID string) (*nodeDrain, error) {
+	data, appErr := p.API.KVGet(nodeDrainKeyPrefix + nodeID)
+	if appErr != nil {
+		return nil, fmt.Errorf("failed to get node drain: %w", appErr)
+	}
+	if data == nil {
+		return nil, nil
+	}
+
+	var drain nodeDrain
+	if err := json.Unmarshal(data, &drain); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal node drain: %w", err)
+	}
+
+	return &drain, nil
+}
+
+// isNodeDraining returns whether new calls should not be assigned to the
+// given node. Errors are logged and considered as not draining so that a
+// failure to read the store doesn't prevent calls from starting.
+func (p *Plugin) isNodeDraining(nodeID string) bool {
+	drain, err := p.getNodeDrain(nodeID)
+	if err != nil {
+		p.LogError(err.Error(), "nodeID", nodeID)
+		return false
+	}
+	return drain != nil
+}
+
+// getCallsOnNode returns the ongoing calls hosted by the given node.
+func (p *Plugin) getCallsOnNode(nodeID string) ([]*public.Call, error) {
+	calls, err := p.store.GetAllActiveCalls(db.GetCallOpts{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active calls: %w", err)
+	}
+
+	var nodeCalls []*public.Call
+	for _, call := range calls {
+		if call.Props.RTCDHost == nodeID || (call.Props.RTCDHost == "" && call.Props.NodeID == nodeID) {
+			nodeCalls = append(nodeCalls, call)
+		}
+	}
+
+	return nodeCalls, nil
+}
+
+func (p *Plugin) getNodeDrainStatus(drain *nodeDrain) (*nodeDrainStatus, error) {
+	calls, err := p.getCallsOnNode(drain.NodeID)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &nodeDrainStatus{
+		nodeDrain: *drain,
+		CallIDs:   make([]string, 0, len(calls)),
+	}
+	for _, call := range calls {
+		status.CallIDs = append(status.CallIDs, call.ID)
+	}
+	status.Done = len(status.CallIDs) == 0
+
+	return status, nil
+}
+
+// drainNode stops new calls from being assigned to the given node and lets
+// participants of the calls it hosts know. If requested, these calls are
+// ended once the grace period expires.
+func (p *Plugin) drainNode(requesterID, nodeID string, req nodeDrainRequest) (*nodeDrain, error) {
+	drain := &nodeDrain{
+		NodeID:      nodeID,
+		RequesterID: requesterID,
+		StartAt:     time.Now().UnixMilli(),
+	}
+
+	gracePeriod := defaultNodeDrainGracePeriod
+	if req.GracePeriod != nil {
+		gracePeriod = time.Duration(*req.GracePeriod) * time.Second
+	}
+	if req.EndCalls {
+		drain.EndCallsAt = time.Now().Add(gracePeriod).UnixMilli()
+	}
+
+	data, err := json.Marshal(drain)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal node drain: %w", err)
+	}
+	if appErr := p.API.KVSet(nodeDrainKeyPrefix+nodeID, data); appErr != nil {
+		return nil, fmt.Errorf("failed to save node drain: %w", appErr)
+	}
+
+	// Other nodes will pick this up on their next hosts check.
+	if p.rtcdManager != nil {
+		p.rtcdManager.setHostDraining(nodeID, true)
+	}
+
+	calls, err := p.getCallsOnNode(nodeID)
+	if err != nil {
+		return nil, err
+	}
+
+	p.LogInfo("draining node", "nodeID", nodeID, "requesterID", requesterID, "calls", len(calls), "endCallsAt", drain.EndCallsAt)
+
+	for _, call := range calls {
+		p.notifyNodeDraining(call, drain, gracePeriod)
+	}
+
+	if req.EndCalls {
+		go p.endDrainedCalls(nodeID, drain.StartAt, gracePeriod)
+	}
+
+	return drain, nil
+}
+
+// notifyNodeDraining lets the participants of a call hosted by a draining
+// node know, through an ephemeral message, what is going to happen.
+func (p *Plugin) notifyNodeDraining(call *public.Call, drain *nodeDrain, gracePeriod time.Duration) {
+	p.publishWebSocketEvent(wsEventCallNodeDraining, map[string]interface{}{
+		"call_id":      call.ID,
+		"channel_id":   call.ChannelID,
+		"end_calls_at": drain.EndCallsAt,
+	}, &WebSocketBroadcast{ChannelID: call.ChannelID, ReliableClusterSend: true})
+
+	sessions, err := p.store.GetCallSessions(call.ID, db.GetCallSessionOpts{})
+	if err != nil {
+		p.LogError("failed to get call sessions", "err", err.Error(), "callID", call.ID)
+		return
+	}
+
+	T := p.getTranslationFunc("")
+	msg := T("app.call.node_draining")
+	if drain.EndCallsAt > 0 {
+		msg = T("app.call.node_draining_end", map[string]any{
+			"Minutes": max(1, int(math.Ceil(gracePeriod.Minutes()))),
+		})
+	}
+
+	botID := p.getBotID()
+	for _, userID := range getUserIDsFromSessions(sessions) {
+		if userID == botID {
+			continue
+		}
+		p.API.SendEphemeralPost(userID, &model.Post{
+			UserId:    botID,
+			ChannelId: call.ChannelID,
+			Message:   msg,
+		})
+	}
+}
+
+// endDrainedCalls ends the calls still hosted by the given node once the
+// grace period expires. Nothing is done if the drain was cancelled or
+// restarted in the meantime.
+func (p *Plugin) endDrainedCalls(nodeID string, startAt int64, gracePeriod time.Duration) {
+	timer := time.NewTimer(gracePeriod)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+	case <-p.stopCh:
+		return
+	}
+
+	drain, err := p.getNodeDrain(nodeID)
+	if err != nil {
+		p.LogError(err.Error(), "nodeID", nodeID)
+		return
+	}
+	if drain == nil || drain.StartAt != startAt {
+		return
+	}
+
+	calls, err := p.getCallsOnNode(nodeID)
+	if err != nil {
+		p.LogError(err.Error(), "nodeID", nodeID)
+		return
+	}
+
+	for _, call := range calls {
+		p.LogInfo("ending call on drained node", "nodeID", nodeID, "callID", call.ID, "channelID", call.ChannelID)
+		if err := p.endDrainedCall(call.ChannelID, call.ID); err != nil {
+			p.LogError("failed to end call", "err", err.Error(), "nodeID", nodeID, "callID", call.ID)
+		}
+	}
+}
+
+func (p *Plugin) endDrainedCall(channelID, callID string) error {
+	state, err := p.lockCallReturnState(channelID)
+	if err != nil {
+		return fmt.Errorf("failed to lock call: %w", err)
+	}
+	defer p.unlockCall(channelID)
+
+	// The call may have ended, and a new one started elsewhere, in the
+	// meantime.
+	if state == nil || state.Call.ID != callID {
+		return nil
+	}
+
+	return p.endCall(state, channelID, public.CallEndReasonNodeDrained)
+}
+
+func (p *Plugin) undrainNode(nodeID string) error {
+	if appErr := p.API.KVDelete(nodeDrainKeyPrefix + nodeID); appErr != nil {
+		return fmt.Errorf("failed to delete node drain: %w", appErr)
+	}
+
+	if p.rtcdManager != nil {
+		p.rtcdManager.setHostDraining(nodeID, false)
+	}
+
+	p.LogInfo("node drain cancelled", "nodeID", nodeID)
+
+	return nil
+}
+
+func (p *Plugin) writeNodeDrainStatus(w http.ResponseWriter, res *httpResponse, drain *nodeDrain) {
+	status, err := p.getNodeDrainStatus(drain)
+	if err != nil {
+		res.Err = err.Error()
+		res.Code = http.StatusInternalServerError
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(status); err != nil {
+		p.LogError("failed to write response", "err", err.Error())
+	}
+}
+
+func (p *Plugin) handleGetNodeDrain(w http.ResponseWriter, r *http.Request) {
+	var res httpResponse
+	defer p.httpAudit("handleGetNodeDrain", &res, w, r)
+
+	if !p.API.HasPermissionTo(r.Header.Get("Mattermost-User-Id"), model.PermissionManageSystem) {
+		res.Err = "Forbidden"
+		res.Code = http.StatusForbidden
+		return
+	}
+
+	drain, err := p.getNodeDrain(mux.Vars(r)["node_id"])
+	if err != nil {
+		res.Err = err.Error()
+		res.Code = http.StatusInternalServerError
+		return
+	}
+	if drain == nil {
+		res.Err = "node is not draining"
+		res.Code = http.StatusNotFound
+		return
+	}
+
+	p.writeNodeDrainStatus(w, &res, drain)
+}
+
+func (p *Plugin) handlePostNodeDrain(w http.ResponseWriter, r *http.Request) {
+	var res httpResponse
+	defer p.httpAudit("handlePostNodeDrain", &res, w, r)
+
+	userID := r.Header.Get("Mattermost-User-Id")
+	if !p.API.HasPermissionTo(userID, model.PermissionManageSystem) {
+		res.Err = "Forbidden"
+		res.Code = http.StatusForbidden
+		return
+	}
+
+	nodeID := mux.Vars(r)["node_id"]
+	if !nodeDrainIDRegex.MatchString(nodeID) {
+		res.Err = "invalid node id"
+		res.Code = http.StatusBadRequest
+		return
+	}
+
+	var req nodeDrainRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, requestBodyMaxSizeBytes)).Decode(&req); err != nil {
+			res.Err = "failed to decode request body: " + err.Error()
+			res.Code = http.StatusBadRequest
+			return
+		}
+	}
+
+	if req.GracePeriod != nil && (*req.GracePeriod < 0 || *req.GracePeriod > maxNodeDrainGracePeriodInSec) {
+		res.Err = fmt.Sprintf("invalid grace_period: should be in the range [0, %d]", maxNodeDrainGracePeriodInSec)
+		res.Code = http.StatusBadRequest
+		return
+	}
+
+	drain, err := p.drainNode(userID, nodeID, req)
+	if err != nil {
+		res.Err = err.Error()
+		res.Code = http.StatusInternalServerError
+		return
+	}
+
+	p.writeNodeDrainStatus(w, &res, drain)
+}
+
+func (p *Plugin) handleDeleteNodeDrain(w http.ResponseWriter, r *http.Request) {
+	var res httpResponse
+	defer p.httpAudit("handleDeleteNodeDrain", &res, w, r)
+
+	if !p.API.HasPermissionTo(r.Header.Get("Mattermost-User-Id"), model.PermissionManageSystem) {
+		res.Err = "Forbidden"
+		res.Code = http.StatusForbidden
+		return
+	}
+
+	if err := p.undrainNode(mux.Vars(r)["node_id"]); err != nil {
+		res.Err = err.Error()
+		res.Code = http.StatusInternalServerError
+		return
+	}
+
+	res.Code = http.StatusOK
+	res.Msg = "success"
+}
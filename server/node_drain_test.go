@@ -0,0 +1,77 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/mattermost/mattermost/server/public/plugin"
+
+	rtcd "github.com/mattermost/rtcd/service"
+
+	rtcdMocks "github.com/mattermost/mattermost-plugin-calls/server/mocks/github.com/mattermost/mattermost-plugin-calls/server/interfaces"
+	pluginMocks "github.com/mattermost/mattermost-plugin-calls/server/mocks/github.com/mattermost/mattermost/server/public/plugin"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNodeDrainHosts(t *testing.T) {
+	mockAPI := &pluginMocks.MockAPI{}
+	mockClientA := &rtcdMocks.MockRTCDClient{}
+	mockClientB := &rtcdMocks.MockRTCDClient{}
+
+	defer mockAPI.AssertExpectations(t)
+	defer mockClientA.AssertExpectations(t)
+	defer mockClientB.AssertExpectations(t)
+
+	m := &rtcdClientManager{
+		ctx: &Plugin{
+			MattermostPlugin: plugin.MattermostPlugin{
+				API: mockAPI,
+			},
+		},
+		hosts: map[string]*rtcdHost{
+			"127.0.0.1": {
+				ip:     "127.0.0.1",
+				client: mockClientA,
+			},
+			"127.0.0.2": {
+				ip:     "127.0.0.2",
+				client: mockClientB,
+			},
+		},
+	}
+
+	mockAPI.On("LogDebug", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+		mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mockAPI.On("LogDebug", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+		mock.Anything, mock.Anything)
+
+	drain, err := json.Marshal(nodeDrain{NodeID: "127.0.0.1"})
+	require.NoError(t, err)
+	mockAPI.On("KVGet", nodeDrainKeyPrefix+"127.0.0.1").Return(drain, nil).Once()
+	mockAPI.On("KVGet", nodeDrainKeyPrefix+"127.0.0.2").Return(nil, nil).Once()
+
+	m.updateDrainingHosts()
+	require.True(t, m.getHost("127.0.0.1").draining)
+	require.False(t, m.getHost("127.0.0.2").draining)
+
+	mockClientA.On("Connected").Return(true).Once()
+	mockClientB.On("Connected").Return(true).Once()
+	mockClientB.On("GetSystemInfo").Return(rtcd.SystemInfo{CPULoad: 1}, nil).Once()
+
+	host, err := m.GetHostForNewCall()
+	require.NoError(t, err)
+	require.Equal(t, "127.0.0.2", host)
+
+	m.setHostDraining("127.0.0.2", true)
+
+	mockClientA.On("Connected").Return(true).Once()
+	mockClientB.On("Connected").Return(true).Once()
+
+	_, err = m.GetHostForNewCall()
+	require.EqualError(t, err, "no host available")
+}
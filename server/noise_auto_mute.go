@@ -0,0 +1,183 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-calls/server/public"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/rtcd/service/rtc"
+)
+
+// Noisy participants are detected from the voice activity events the SFU
+// sends, as it's the only component analyzing audio levels. Speech naturally
+// pauses, which ends voice activity, while continuous noise (e.g. traffic,
+// a fan or music) keeps it going: a session whose voice activity lasts longer
+// than the configured threshold without a single pause is considered noisy.
+
+// getCallNoiseAutoMute returns the automatic muting of noisy participants in
+// effect for the given call.
+func (p *Plugin) getCallNoiseAutoMute(call *public.Call) public.CallNoiseAutoMute {
+	return p.getEffectiveNoiseAutoMute(call.Props.NoiseAutoMute)
+}
+
+// getSessionNoiseAutoMute is like getCallNoiseAutoMute but relies on the call
+// props kept in memory by the session.
+func (p *Plugin) getSessionNoiseAutoMute(us *session) public.CallNoiseAutoMute {
+	return p.getEffectiveNoiseAutoMute(us.noiseAutoMute.Load())
+}
+
+func (p *Plugin) getEffectiveNoiseAutoMute(override *public.CallNoiseAutoMute) public.CallNoiseAutoMute {
+	if override != nil {
+		return *override
+	}
+	return p.getConfiguration().getNoiseAutoMute()
+}
+
+// setCallNoiseAutoMute overrides the automatic muting of noisy participants
+// for the current call. Passing nil restores the configured one.
+func (p *Plugin) setCallNoiseAutoMute(requesterID, channelID string, noiseAutoMute *public.CallNoiseAutoMute) error {
+	state, err := p.lockCallReturnState(channelID)
+	if err != nil {
+		return fmt.Errorf("failed to lock call: %w", err)
+	}
+	defer p.unlockCall(channelID)
+
+	if state == nil {
+		return ErrNoCallOngoing
+	}
+
+	if requesterID != state.Call.GetHostID() {
+		if isAdmin := p.API.HasPermissionTo(requesterID, model.PermissionManageSystem); !isAdmin {
+			return ErrNoPermissions
+		}
+	}
+
+	state.Call.Props.NoiseAutoMute = noiseAutoMute
+	if err := p.store.UpdateCall(&state.Call); err != nil {
+		return fmt.Errorf("failed to update call: %w", err)
+	}
+
+	// Sessions keep the override in memory, so we update those handled by
+	// this node and let the others do the same.
+	p.setSessionsNoiseAutoMute(state.Call.ID, noiseAutoMute)
+	if err := p.sendClusterMessage(clusterMessage{
+		CallID:        state.Call.ID,
+		SenderID:      p.nodeID,
+		NoiseAutoMute: noiseAutoMute,
+	}, clusterMessageTypeNoiseAutoMute, ""); err != nil {
+		p.LogError("failed to send noise auto mute message", "err", err.Error(), "callID", state.Call.ID)
+	}
+
+	effective := p.getCallNoiseAutoMute(&state.Call)
+	p.publishWebSocketEvent(wsEventCallNoiseAutoMute, map[string]interface{}{
+		"call_id":           state.Call.ID,
+		"enabled":           effective.Enabled,
+		"threshold_seconds": effective.ThresholdSeconds,
+		"overridden":        noiseAutoMute != nil,
+	}, &WebSocketBroadcast{
+		ChannelID:           channelID,
+		ReliableClusterSend: true,
+		UserIDs:             getUserIDsFromSessions(state.sessions),
+	})
+
+	return nil
+}
+
+// setSessionsNoiseAutoMute updates the noise auto mute override of the
+// sessions handled by this node for the given call.
+func (p *Plugin) setSessionsNoiseAutoMute(callID string, noiseAutoMute *public.CallNoiseAutoMute) {
+	p.mut.RLock()
+	defer p.mut.RUnlock()
+
+	for _, us := range p.sessions {
+		if us.callID == callID {
+			us.noiseAutoMute.Store(noiseAutoMute)
+		}
+	}
+}
+
+// startNoiseDetection starts timing the voice activity of the given session
+// if automatic muting of noisy participants is enabled for the call.
+func (p *Plugin) startNoiseDetection(channelID, callID, sessionID string, noiseAutoMute public.CallNoiseAutoMute) {
+	if !noiseAutoMute.Enabled {
+		return
+	}
+
+	p.noisySessionsMut.Lock()
+	defer p.noisySessionsMut.Unlock()
+
+	// Voice activity is already being timed.
+	if _, ok := p.noisySessions[sessionID]; ok {
+		return
+	}
+
+	var timer *time.Timer
+	timer = time.AfterFunc(time.Duration(noiseAutoMute.ThresholdSeconds)*time.Second, func() {
+		p.noisySessionsMut.Lock()
+		if p.noisySessions[sessionID] != timer {
+			p.noisySessionsMut.Unlock()
+			return
+		}
+		delete(p.noisySessions, sessionID)
+		p.noisySessionsMut.Unlock()
+
+		p.muteNoisySession(channelID, callID, sessionID)
+	})
+	p.noisySessions[sessionID] = timer
+}
+
+// stopNoiseDetection stops timing the voice activity of the given session,
+// which paused.
+func (p *Plugin) stopNoiseDetection(sessionID string) {
+	p.noisySessionsMut.Lock()
+	defer p.noisySessionsMut.Unlock()
+
+	if timer, ok := p.noisySessions[sessionID]; ok {
+		timer.Stop()
+		delete(p.noisySessions, sessionID)
+	}
+}
+
+// muteNoisySession mutes the given session, detected as noisy, at the SFU and
+// asks its client to mute, letting the participant know why. They are free
+// to unmute when ready.
+func (p *Plugin) muteNoisySession(channelID, callID, sessionID string) {
+	state, err := p.getCallState(channelID, false)
+	if err != nil {
+		p.LogError("failed to get call state", "err", err.Error(), "channelID", channelID)
+		return
+	}
+
+	// The call could have ended or the setting been turned off in the meantime.
+	if state == nil || state.Call.ID != callID || !p.getCallNoiseAutoMute(&state.Call).Enabled {
+		return
+	}
+
+	// Presenters are expected to talk at length so they are never muted.
+	session := state.sessions[sessionID]
+	if session == nil || !session.Unmuted || slices.Contains(state.Call.Props.ScreenSharingSessionIDs, sessionID) {
+		return
+	}
+
+	p.LogDebug("muting noisy session", "sessionID", sessionID, "userID", session.UserID, "callID", callID)
+	p.metrics.IncNoiseAutoMutes()
+
+	if err := p.sendRTCMessage(rtc.Message{
+		SessionID: sessionID,
+		Type:      rtc.MuteMessage,
+	}, callID); err != nil {
+		p.LogError("failed to send RTC message", "err", err.Error(), "sessionID", sessionID, "callID", callID)
+	}
+
+	p.publishWebSocketEvent(wsEventNoiseMute, map[string]interface{}{
+		"call_id":    callID,
+		"channel_id": channelID,
+		"session_id": sessionID,
+	}, &WebSocketBroadcast{UserID: session.UserID, ReliableClusterSend: true})
+}
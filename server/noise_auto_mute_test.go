@@ -0,0 +1,231 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-calls/server/cluster"
+	"github.com/mattermost/mattermost-plugin-calls/server/public"
+
+	serverMocks "github.com/mattermost/mattermost-plugin-calls/server/mocks/github.com/mattermost/mattermost-plugin-calls/server/interfaces"
+	pluginMocks "github.com/mattermost/mattermost-plugin-calls/server/mocks/github.com/mattermost/mattermost/server/public/plugin"
+	rtcMocks "github.com/mattermost/mattermost-plugin-calls/server/mocks/github.com/mattermost/rtcd/service/rtc"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin"
+	"github.com/mattermost/rtcd/service/rtc"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetCallNoiseAutoMute(t *testing.T) {
+	var cfg configuration
+	cfg.SetDefaults()
+	p := &Plugin{
+		configuration: &cfg,
+	}
+
+	t.Run("defaults", func(t *testing.T) {
+		require.Equal(t, public.CallNoiseAutoMute{
+			ThresholdSeconds: 120,
+		}, p.getCallNoiseAutoMute(&public.Call{}))
+	})
+
+	t.Run("configured", func(t *testing.T) {
+		cfg.EnableNoiseAutoMute = model.NewPointer(true)
+		cfg.NoiseAutoMuteThresholdSeconds = model.NewPointer(60)
+
+		require.Equal(t, public.CallNoiseAutoMute{
+			Enabled:          true,
+			ThresholdSeconds: 60,
+		}, p.getCallNoiseAutoMute(&public.Call{}))
+	})
+
+	t.Run("call override", func(t *testing.T) {
+		noiseAutoMute := &public.CallNoiseAutoMute{
+			ThresholdSeconds: 15,
+		}
+		require.Equal(t, *noiseAutoMute, p.getCallNoiseAutoMute(&public.Call{
+			Props: public.CallProps{
+				NoiseAutoMute: noiseAutoMute,
+			},
+		}))
+	})
+}
+
+func TestSessionNoiseAutoMute(t *testing.T) {
+	var cfg configuration
+	cfg.SetDefaults()
+	p := &Plugin{
+		configuration: &cfg,
+		sessions:      map[string]*session{},
+	}
+
+	usA := newUserSession("userA", "channelA", "connA", "callA", true)
+	usB := newUserSession("userB", "channelB", "connB", "callB", true)
+	p.sessions[usA.connID] = usA
+	p.sessions[usB.connID] = usB
+
+	usA.setCallProps(public.CallProps{JoinMuted: true})
+	require.True(t, usA.joinMuted.Load())
+	require.Equal(t, cfg.getNoiseAutoMute(), p.getSessionNoiseAutoMute(usA))
+
+	noiseAutoMute := &public.CallNoiseAutoMute{
+		Enabled:          true,
+		ThresholdSeconds: 15,
+	}
+	p.setSessionsNoiseAutoMute("callA", noiseAutoMute)
+	require.Equal(t, *noiseAutoMute, p.getSessionNoiseAutoMute(usA))
+	require.Equal(t, cfg.getNoiseAutoMute(), p.getSessionNoiseAutoMute(usB))
+
+	p.setSessionsNoiseAutoMute("callA", nil)
+	require.Equal(t, cfg.getNoiseAutoMute(), p.getSessionNoiseAutoMute(usA))
+}
+
+func TestNoiseDetection(t *testing.T) {
+	var cfg configuration
+	cfg.SetDefaults()
+	p := &Plugin{
+		configuration: &cfg,
+		noisySessions: map[string]*time.Timer{},
+	}
+
+	call := &public.Call{
+		ID:        model.NewId(),
+		ChannelID: model.NewId(),
+	}
+	sessionID := model.NewId()
+
+	t.Run("disabled", func(t *testing.T) {
+		p.startNoiseDetection(call.ChannelID, call.ID, sessionID, p.getCallNoiseAutoMute(call))
+		require.Empty(t, p.noisySessions)
+	})
+
+	call.Props.NoiseAutoMute = &public.CallNoiseAutoMute{
+		Enabled:          true,
+		ThresholdSeconds: 60,
+	}
+
+	t.Run("voice on", func(t *testing.T) {
+		p.startNoiseDetection(call.ChannelID, call.ID, sessionID, p.getCallNoiseAutoMute(call))
+		require.Len(t, p.noisySessions, 1)
+		timer := p.noisySessions[sessionID]
+		require.NotNil(t, timer)
+
+		// Voice activity is timed from when it started.
+		p.startNoiseDetection(call.ChannelID, call.ID, sessionID, p.getCallNoiseAutoMute(call))
+		require.Same(t, timer, p.noisySessions[sessionID])
+	})
+
+	t.Run("voice off", func(t *testing.T) {
+		p.stopNoiseDetection(sessionID)
+		require.Empty(t, p.noisySessions)
+
+		p.stopNoiseDetection(sessionID)
+		require.Empty(t, p.noisySessions)
+	})
+}
+
+func TestMuteNoisySession(t *testing.T) {
+	store, tearDown := NewTestStore(t)
+	t.Cleanup(tearDown)
+
+	mockAPI := &pluginMocks.MockAPI{}
+	mockMetrics := &serverMocks.MockMetrics{}
+	mockRTCMetrics := &rtcMocks.MockMetrics{}
+
+	defer mockAPI.AssertExpectations(t)
+	defer mockMetrics.AssertExpectations(t)
+
+	p := &Plugin{
+		MattermostPlugin: plugin.MattermostPlugin{
+			API: mockAPI,
+		},
+		callsClusterLocks: map[string]*cluster.Mutex{},
+		metrics:           mockMetrics,
+		store:             store,
+	}
+
+	var cfg configuration
+	cfg.SetDefaults()
+	p.configuration = &cfg
+
+	mockMetrics.On("ObserveAppHandlersTime", "getCallState", mock.AnythingOfType("float64"))
+
+	// The server isn't started as messages only need to be queued.
+	rtcServer, err := rtc.NewServer(rtc.ServerConfig{
+		ICEPortUDP: 33443,
+		ICEPortTCP: 33443,
+	}, newLogger(p), mockRTCMetrics)
+	require.NoError(t, err)
+	p.rtcServer = rtcServer
+
+	userID := model.NewId()
+	presenterID := model.NewId()
+	sessionID := model.NewId()
+	presenterSessionID := model.NewId()
+
+	call := &public.Call{
+		ID:        model.NewId(),
+		CreateAt:  time.Now().UnixMilli(),
+		ChannelID: model.NewId(),
+		StartAt:   time.Now().UnixMilli(),
+		PostID:    model.NewId(),
+		ThreadID:  model.NewId(),
+		OwnerID:   userID,
+		Props: public.CallProps{
+			ScreenSharingSessionIDs: []string{presenterSessionID},
+			NoiseAutoMute: &public.CallNoiseAutoMute{
+				Enabled:          true,
+				ThresholdSeconds: 30,
+			},
+		},
+	}
+	err = p.store.CreateCall(call)
+	require.NoError(t, err)
+
+	for id, uid := range map[string]string{sessionID: userID, presenterSessionID: presenterID} {
+		err = p.store.CreateCallSession(&public.CallSession{
+			ID:      id,
+			CallID:  call.ID,
+			UserID:  uid,
+			JoinAt:  time.Now().UnixMilli(),
+			Unmuted: true,
+		})
+		require.NoError(t, err)
+	}
+
+	t.Run("presenter", func(t *testing.T) {
+		p.muteNoisySession(call.ChannelID, call.ID, presenterSessionID)
+	})
+
+	t.Run("different call", func(t *testing.T) {
+		p.muteNoisySession(call.ChannelID, model.NewId(), sessionID)
+	})
+
+	t.Run("noisy", func(t *testing.T) {
+		mockAPI.On("LogDebug", "muting noisy session", "origin", mock.Anything,
+			"sessionID", sessionID, "userID", userID, "callID", call.ID).Once()
+		mockMetrics.On("IncNoiseAutoMutes").Once()
+		mockMetrics.On("IncWebSocketEvent", "out", wsEventNoiseMute).Once()
+		mockAPI.On("PublishWebSocketEvent", wsEventNoiseMute, map[string]any{
+			"call_id":    call.ID,
+			"channel_id": call.ChannelID,
+			"session_id": sessionID,
+		}, &model.WebsocketBroadcast{UserId: userID, ReliableClusterSend: true}).Once()
+
+		p.muteNoisySession(call.ChannelID, call.ID, sessionID)
+	})
+
+	call.Props.NoiseAutoMute.Enabled = false
+	err = p.store.UpdateCall(call)
+	require.NoError(t, err)
+
+	t.Run("disabled", func(t *testing.T) {
+		p.muteNoisySession(call.ChannelID, call.ID, sessionID)
+	})
+}
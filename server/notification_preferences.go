@@ -0,0 +1,225 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+const (
+	callNotificationPreferencesName  = "call_notifications"
+	maxCallNotificationMutedChannels = 256
+)
+
+// Plugin preferences categories are conventionally prefixed with "pp_".
+var callNotificationPreferencesCategory = "pp_" + manifest.Id
+
+// CallNotificationMode controls how users are alerted when a call starts.
+type CallNotificationMode string
+
+const (
+	// Notify and ring (DMs and GMs only, if ringing is enabled).
+	CallNotificationModeRing CallNotificationMode = "ring"
+	// Notify without ringing.
+	CallNotificationModeSilent CallNotificationMode = "silent"
+)
+
+// CallNotificationChannels controls which channels users are notified for
+// when a call starts.
+type CallNotificationChannels string
+
+const (
+	CallNotificationChannelsAll    CallNotificationChannels = "all"
+	CallNotificationChannelsDirect CallNotificationChannels = "direct"
+	CallNotificationChannelsNone   CallNotificationChannels = "none"
+)
+
+// CallNotificationPreferences holds a user's preferences for call start
+// notifications. These are applied on top of the channel notification
+// settings.
+type CallNotificationPreferences struct {
+	Mode     CallNotificationMode     `json:"mode"`
+	Channels CallNotificationChannels `json:"channels"`
+	// The channels the user doesn't want to be notified for, regardless of
+	// Channels.
+	MutedChannelIDs []string `json:"muted_channel_ids"`
+	// When set the user's status is switched to Do Not Disturb while they
+	// are in a call (see focus_mode.go).
+	FocusMode bool `json:"focus_mode"`
+	// When set the user doesn't get a direct message when the recording of
+	// a call they took part in is available (see recording_notifications.go).
+	MuteRecordingNotifications bool `json:"mute_recording_notifications"`
+}
+
+func newCallNotificationPreferences() CallNotificationPreferences {
+	return CallNotificationPreferences{
+		Mode:            CallNotificationModeRing,
+		Channels:        CallNotificationChannelsAll,
+		MutedChannelIDs: []string{},
+	}
+}
+
+func (p CallNotificationPreferences) IsValid() error {
+	switch p.Mode {
+	case CallNotificationModeRing, CallNotificationModeSilent:
+	default:
+		return fmt.Errorf("invalid mode %q", p.Mode)
+	}
+
+	switch p.Channels {
+	case CallNotificationChannelsAll, CallNotificationChannelsDirect, CallNotificationChannelsNone:
+	default:
+		return fmt.Errorf("invalid channels %q", p.Channels)
+	}
+
+	if len(p.MutedChannelIDs) > maxCallNotificationMutedChannels {
+		return fmt.Errorf("too many muted channels: should not be more than %d", maxCallNotificationMutedChannels)
+	}
+
+	for _, channelID := range p.MutedChannelIDs {
+		if !model.IsValidId(channelID) {
+			return fmt.Errorf("invalid muted channel ID %q", channelID)
+		}
+	}
+
+	return nil
+}
+
+// shouldNotify returns whether the user should be notified of a call
+// starting in the given channel.
+func (p CallNotificationPreferences) shouldNotify(channelID string, channelType model.ChannelType) bool {
+	if slices.Contains(p.MutedChannelIDs, channelID) {
+		return false
+	}
+
+	switch p.Channels {
+	case CallNotificationChannelsNone:
+		return false
+	case CallNotificationChannelsDirect:
+		return channelType == model.ChannelTypeDirect || channelType == model.ChannelTypeGroup
+	default:
+		return true
+	}
+}
+
+// getCallNotificationPreferences returns the user's call notification
+// preferences, or the defaults if they were never set.
+func (p *Plugin) getCallNotificationPreferences(userID string) (CallNotificationPreferences, error) {
+	prefs := newCallNotificationPreferences()
+
+	pref, appErr := p.API.GetPreferenceForUser(userID, callNotificationPreferencesCategory, callNotificationPreferencesName)
+	if appErr != nil {
+		if appErr.StatusCode == http.StatusNotFound {
+			return prefs, nil
+		}
+		return prefs, fmt.Errorf("failed to get preference: %w", appErr)
+	}
+
+	if err := json.Unmarshal([]byte(pref.Value), &prefs); err != nil {
+		return newCallNotificationPreferences(), fmt.Errorf("failed to unmarshal preference: %w", err)
+	}
+
+	// Stored preferences may have been set by an older version, any
+	// invalid values are reset to the defaults.
+	if err := prefs.IsValid(); err != nil {
+		return newCallNotificationPreferences(), nil
+	}
+
+	return prefs, nil
+}
+
+func (p *Plugin) setCallNotificationPreferences(userID string, prefs CallNotificationPreferences) error {
+	data, err := json.Marshal(prefs)
+	if err != nil {
+		return fmt.Errorf("failed to marshal preference: %w", err)
+	}
+
+	if appErr := p.API.UpdatePreferencesForUser(userID, []model.Preference{
+		{
+			UserId:   userID,
+			Category: callNotificationPreferencesCategory,
+			Name:     callNotificationPreferencesName,
+			Value:    string(data),
+		},
+	}); appErr != nil {
+		return fmt.Errorf("failed to update preference: %w", appErr)
+	}
+
+	return nil
+}
+
+// shouldNotifyUserForCall returns whether the user should be notified of a
+// call starting in the given channel and whether it should be done silently.
+// Errors fetching preferences fall back to notifying.
+func (p *Plugin) shouldNotifyUserForCall(userID, channelID string, channelType model.ChannelType) (notify bool, silent bool) {
+	prefs, err := p.getCallNotificationPreferences(userID)
+	if err != nil {
+		p.LogError("failed to get call notification preferences", "err", err.Error(), "userID", userID)
+	}
+
+	return prefs.shouldNotify(channelID, channelType), prefs.Mode == CallNotificationModeSilent
+}
+
+func (p *Plugin) handleGetCallNotificationPreferences(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("Mattermost-User-Id")
+
+	prefs, err := p.getCallNotificationPreferences(userID)
+	if err != nil {
+		p.LogError(err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(prefs); err != nil {
+		p.LogError(err.Error())
+	}
+}
+
+func (p *Plugin) handlePostCallNotificationPreferences(w http.ResponseWriter, r *http.Request) {
+	var res httpResponse
+	defer p.httpAudit("handlePostCallNotificationPreferences", &res, w, r)
+
+	userID := r.Header.Get("Mattermost-User-Id")
+
+	var prefs CallNotificationPreferences
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, requestBodyMaxSizeBytes)).Decode(&prefs); err != nil {
+		res.Err = err.Error()
+		res.Code = http.StatusBadRequest
+		return
+	}
+
+	if prefs.MutedChannelIDs == nil {
+		prefs.MutedChannelIDs = []string{}
+	}
+
+	if err := prefs.IsValid(); err != nil {
+		res.Err = fmt.Errorf("invalid preferences: %w", err).Error()
+		res.Code = http.StatusBadRequest
+		return
+	}
+
+	if err := p.setCallNotificationPreferences(userID, prefs); err != nil {
+		res.Err = err.Error()
+		res.Code = http.StatusInternalServerError
+		return
+	}
+
+	// Keep the user's other clients in sync.
+	p.publishWebSocketEvent(wsEventCallNotificationPreferences, map[string]interface{}{
+		"mode":                         prefs.Mode,
+		"channels":                     prefs.Channels,
+		"muted_channel_ids":            prefs.MutedChannelIDs,
+		"focus_mode":                   prefs.FocusMode,
+		"mute_recording_notifications": prefs.MuteRecordingNotifications,
+	}, &WebSocketBroadcast{UserID: userID, ReliableClusterSend: true})
+
+	res.Code = http.StatusOK
+	res.Msg = "success"
+}
@@ -0,0 +1,116 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"net/http"
+	"testing"
+
+	pluginMocks "github.com/mattermost/mattermost-plugin-calls/server/mocks/github.com/mattermost/mattermost/server/public/plugin"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCallNotificationPreferencesIsValid(t *testing.T) {
+	prefs := newCallNotificationPreferences()
+	require.NoError(t, prefs.IsValid())
+
+	prefs.Mode = "loud"
+	require.EqualError(t, prefs.IsValid(), `invalid mode "loud"`)
+
+	prefs = newCallNotificationPreferences()
+	prefs.Channels = "some"
+	require.EqualError(t, prefs.IsValid(), `invalid channels "some"`)
+
+	prefs = newCallNotificationPreferences()
+	prefs.MutedChannelIDs = []string{model.NewId(), "invalid"}
+	require.EqualError(t, prefs.IsValid(), `invalid muted channel ID "invalid"`)
+
+	prefs.MutedChannelIDs = make([]string, maxCallNotificationMutedChannels+1)
+	require.EqualError(t, prefs.IsValid(), "too many muted channels: should not be more than 256")
+}
+
+func TestCallNotificationPreferencesShouldNotify(t *testing.T) {
+	channelID := model.NewId()
+	prefs := newCallNotificationPreferences()
+
+	require.True(t, prefs.shouldNotify(channelID, model.ChannelTypeOpen))
+	require.True(t, prefs.shouldNotify(channelID, model.ChannelTypeDirect))
+
+	prefs.Channels = CallNotificationChannelsDirect
+	require.False(t, prefs.shouldNotify(channelID, model.ChannelTypeOpen))
+	require.False(t, prefs.shouldNotify(channelID, model.ChannelTypePrivate))
+	require.True(t, prefs.shouldNotify(channelID, model.ChannelTypeDirect))
+	require.True(t, prefs.shouldNotify(channelID, model.ChannelTypeGroup))
+
+	prefs.MutedChannelIDs = []string{channelID}
+	require.False(t, prefs.shouldNotify(channelID, model.ChannelTypeDirect))
+	require.True(t, prefs.shouldNotify(model.NewId(), model.ChannelTypeDirect))
+
+	prefs = newCallNotificationPreferences()
+	prefs.Channels = CallNotificationChannelsNone
+	require.False(t, prefs.shouldNotify(channelID, model.ChannelTypeDirect))
+}
+
+func TestGetCallNotificationPreferences(t *testing.T) {
+	mockAPI := &pluginMocks.MockAPI{}
+	defer mockAPI.AssertExpectations(t)
+
+	p := &Plugin{
+		MattermostPlugin: plugin.MattermostPlugin{
+			API: mockAPI,
+		},
+	}
+
+	userID := model.NewId()
+
+	t.Run("defaults", func(t *testing.T) {
+		mockAPI.On("GetPreferenceForUser", userID, callNotificationPreferencesCategory, callNotificationPreferencesName).
+			Return(model.Preference{}, &model.AppError{StatusCode: http.StatusNotFound}).Once()
+
+		prefs, err := p.getCallNotificationPreferences(userID)
+		require.NoError(t, err)
+		require.Equal(t, newCallNotificationPreferences(), prefs)
+	})
+
+	t.Run("stored", func(t *testing.T) {
+		mockAPI.On("GetPreferenceForUser", userID, callNotificationPreferencesCategory, callNotificationPreferencesName).
+			Return(model.Preference{Value: `{"mode":"silent","channels":"direct","muted_channel_ids":[]}`}, nil).Once()
+
+		prefs, err := p.getCallNotificationPreferences(userID)
+		require.NoError(t, err)
+		require.Equal(t, CallNotificationPreferences{
+			Mode:            CallNotificationModeSilent,
+			Channels:        CallNotificationChannelsDirect,
+			MutedChannelIDs: []string{},
+		}, prefs)
+	})
+
+	t.Run("invalid stored value", func(t *testing.T) {
+		mockAPI.On("GetPreferenceForUser", userID, callNotificationPreferencesCategory, callNotificationPreferencesName).
+			Return(model.Preference{Value: `{"mode":"loud"}`}, nil).Once()
+
+		prefs, err := p.getCallNotificationPreferences(userID)
+		require.NoError(t, err)
+		require.Equal(t, newCallNotificationPreferences(), prefs)
+	})
+
+	t.Run("set", func(t *testing.T) {
+		mockAPI.On("UpdatePreferencesForUser", userID, mock.MatchedBy(func(prefs []model.Preference) bool {
+			return len(prefs) == 1 && prefs[0].UserId == userID &&
+				prefs[0].Category == callNotificationPreferencesCategory &&
+				prefs[0].Name == callNotificationPreferencesName &&
+				prefs[0].Value == `{"mode":"silent","channels":"all","muted_channel_ids":[],"focus_mode":true,"mute_recording_notifications":false}`
+		})).Return(nil).Once()
+
+		prefs := newCallNotificationPreferences()
+		prefs.Mode = CallNotificationModeSilent
+		prefs.FocusMode = true
+		require.NoError(t, p.setCallNotificationPreferences(userID, prefs))
+	})
+}
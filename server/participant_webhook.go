@@ -0,0 +1,179 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-calls/server/public"
+)
+
+const (
+	// This value should be high enough to absorb the join/leave bursts of
+	// large calls while a batch is being delivered.
+	participantWebhookQueueSize    = 4096
+	participantWebhookMaxBatchSize = 100
+	participantWebhookTimeout      = 10 * time.Second
+	participantWebhookMaxRetries   = 3
+
+	webhookSignatureHeader = "X-Calls-Signature"
+	webhookTimestampHeader = "X-Calls-Timestamp"
+)
+
+// Events are sent at most once per interval, or earlier if a batch fills up,
+// so that large calls don't flood the receiving service.
+var (
+	participantWebhookBatchInterval  = time.Second
+	participantWebhookRetryBaseDelay = 2 * time.Second
+)
+
+type participantWebhookEvent struct {
+	public.ParticipantEvent
+	Username string `json:"username,omitempty"`
+}
+
+type participantWebhookPayload struct {
+	Events []participantWebhookEvent `json:"events"`
+}
+
+// signWebhookPayload returns the HMAC-SHA256 signature of the given request
+// body. The timestamp is part of the signed content so that receivers can
+// reject replayed requests.
+func signWebhookPayload(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// queueParticipantWebhookEvent queues a participant event for delivery to the
+// configured webhook. It never blocks as it's called while holding the call
+// lock.
+func (p *Plugin) queueParticipantWebhookEvent(ev public.ParticipantEvent) {
+	if !p.getConfiguration().participantWebhookEnabled(ev.Type) {
+		return
+	}
+
+	select {
+	case p.participantWebhookCh <- ev:
+	default:
+		p.LogWarn("participant webhook queue is full, dropping event",
+			"type", string(ev.Type), "callID", ev.CallID, "userID", ev.UserID)
+	}
+}
+
+func (p *Plugin) participantWebhookSender() {
+	ticker := time.NewTicker(participantWebhookBatchInterval)
+	defer ticker.Stop()
+
+	var events []public.ParticipantEvent
+	for {
+		select {
+		case ev := <-p.participantWebhookCh:
+			events = append(events, ev)
+			if len(events) < participantWebhookMaxBatchSize {
+				continue
+			}
+		case <-ticker.C:
+			if len(events) == 0 {
+				continue
+			}
+		case <-p.stopCh:
+			if len(events) > 0 {
+				p.LogWarn("plugin stopping, dropping participant webhook events", "count", fmt.Sprintf("%d", len(events)))
+			}
+			return
+		}
+
+		p.sendParticipantWebhook(events)
+		events = nil
+	}
+}
+
+func (p *Plugin) sendParticipantWebhook(events []public.ParticipantEvent) {
+	cfg := p.getConfiguration()
+	if cfg.ParticipantWebhookURL == "" {
+		return
+	}
+
+	payload := participantWebhookPayload{
+		Events: make([]participantWebhookEvent, 0, len(events)),
+	}
+	usernames := map[string]string{}
+	for _, ev := range events {
+		username, ok := usernames[ev.UserID]
+		if !ok {
+			if user, appErr := p.API.GetUser(ev.UserID); appErr != nil {
+				p.LogWarn("failed to get user for participant webhook", "userID", ev.UserID, "err", appErr.Error())
+			} else {
+				username = user.Username
+			}
+			usernames[ev.UserID] = username
+		}
+		payload.Events = append(payload.Events, participantWebhookEvent{
+			ParticipantEvent: ev,
+			Username:         username,
+		})
+	}
+
+	client := cfg.newOutboundHTTPClient(participantWebhookTimeout)
+
+	var err error
+	for attempt := 0; attempt <= participantWebhookMaxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-time.After(time.Duration(attempt) * participantWebhookRetryBaseDelay):
+			case <-p.stopCh:
+				p.LogWarn("plugin stopping, aborting participant webhook")
+				return
+			}
+		}
+
+		err = postParticipantWebhook(client, cfg.ParticipantWebhookURL, cfg.ParticipantWebhookSecret, payload)
+		if err == nil {
+			return
+		}
+
+		p.LogWarn("participant webhook request failed", "attempt", fmt.Sprintf("%d", attempt+1), "err", err.Error())
+	}
+
+	p.LogError("failed to send participant webhook", "count", fmt.Sprintf("%d", len(events)), "err", err.Error())
+}
+
+func postParticipantWebhook(client *http.Client, webhookURL, secret string, payload participantWebhookPayload) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, webhookURL, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(webhookTimestampHeader, timestamp)
+	req.Header.Set(webhookSignatureHeader, signWebhookPayload(secret, timestamp, data))
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,188 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-calls/server/public"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin"
+
+	pluginMocks "github.com/mattermost/mattermost-plugin-calls/server/mocks/github.com/mattermost/mattermost/server/public/plugin"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignWebhookPayload(t *testing.T) {
+	sig := signWebhookPayload("secret", "1700000000", []byte(`{"events":[]}`))
+	require.Equal(t, "sha256=3947de27ec923573170fccda604ddfb25583ff98dc51e96cca2e11c59545026a", sig)
+	require.NotEqual(t, sig, signWebhookPayload("secret", "1700000001", []byte(`{"events":[]}`)))
+	require.NotEqual(t, sig, signWebhookPayload("other", "1700000000", []byte(`{"events":[]}`)))
+}
+
+func TestParticipantWebhookEnabled(t *testing.T) {
+	cfg := &configuration{}
+	cfg.SetDefaults()
+	require.False(t, cfg.participantWebhookEnabled(public.ParticipantEventTypeJoin))
+	require.False(t, cfg.participantWebhookEnabled(public.ParticipantEventTypeLeave))
+
+	cfg.ParticipantWebhookURL = "https://attendance.example.com/webhook"
+	require.True(t, cfg.participantWebhookEnabled(public.ParticipantEventTypeJoin))
+	require.True(t, cfg.participantWebhookEnabled(public.ParticipantEventTypeLeave))
+
+	cfg.EnableJoinWebhook = model.NewPointer(false)
+	require.False(t, cfg.participantWebhookEnabled(public.ParticipantEventTypeJoin))
+	require.True(t, cfg.participantWebhookEnabled(public.ParticipantEventTypeLeave))
+
+	cfg.EnableLeaveWebhook = model.NewPointer(false)
+	require.False(t, cfg.participantWebhookEnabled(public.ParticipantEventTypeLeave))
+}
+
+func TestParticipantWebhook(t *testing.T) {
+	defaultInterval := participantWebhookBatchInterval
+	defaultDelay := participantWebhookRetryBaseDelay
+	participantWebhookBatchInterval = 10 * time.Millisecond
+	participantWebhookRetryBaseDelay = time.Millisecond
+	defer func() {
+		participantWebhookBatchInterval = defaultInterval
+		participantWebhookRetryBaseDelay = defaultDelay
+	}()
+
+	userID := model.NewId()
+	callID := model.NewId()
+	channelID := model.NewId()
+
+	setup := func(t *testing.T, webhookURL string) (*Plugin, *pluginMocks.MockAPI) {
+		t.Helper()
+
+		mockAPI := &pluginMocks.MockAPI{}
+		t.Cleanup(func() { mockAPI.AssertExpectations(t) })
+
+		p := &Plugin{
+			MattermostPlugin: plugin.MattermostPlugin{
+				API: mockAPI,
+			},
+			stopCh:               make(chan struct{}),
+			participantWebhookCh: make(chan public.ParticipantEvent, participantWebhookQueueSize),
+		}
+
+		cfg := &configuration{}
+		cfg.SetDefaults()
+		cfg.ParticipantWebhookURL = webhookURL
+		cfg.ParticipantWebhookSecret = "secret"
+		p.configuration = cfg
+
+		return p, mockAPI
+	}
+
+	t.Run("batched and signed", func(t *testing.T) {
+		receivedCh := make(chan participantWebhookPayload, 1)
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, http.MethodPost, r.Method)
+
+			body, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+
+			timestamp := r.Header.Get(webhookTimestampHeader)
+			require.NotEmpty(t, timestamp)
+			require.Equal(t, signWebhookPayload("secret", timestamp, body), r.Header.Get(webhookSignatureHeader))
+
+			var payload participantWebhookPayload
+			require.NoError(t, json.Unmarshal(body, &payload))
+			receivedCh <- payload
+		}))
+		defer ts.Close()
+
+		p, mockAPI := setup(t, ts.URL)
+		mockAPI.On("GetUser", userID).Return(&model.User{Id: userID, Username: "alice"}, nil).Once()
+
+		// Events are queued before starting the sender so that they end up
+		// in the same batch.
+		metadata := map[string]string{"meeting_id": "123-456"}
+		p.publishParticipantEvent(public.ParticipantEventTypeJoin, channelID, callID, userID, "sessionA", metadata)
+		p.publishParticipantEvent(public.ParticipantEventTypeLeave, channelID, callID, userID, "sessionA", metadata)
+
+		go p.participantWebhookSender()
+		defer close(p.stopCh)
+
+		select {
+		case payload := <-receivedCh:
+			require.Len(t, payload.Events, 2)
+			require.Equal(t, public.ParticipantEventTypeJoin, payload.Events[0].Type)
+			require.Equal(t, public.ParticipantEventTypeLeave, payload.Events[1].Type)
+			for _, ev := range payload.Events {
+				require.Equal(t, userID, ev.UserID)
+				require.Equal(t, "alice", ev.Username)
+				require.Equal(t, callID, ev.CallID)
+				require.Equal(t, channelID, ev.ChannelID)
+				require.NotZero(t, ev.CreateAt)
+				require.Equal(t, metadata, ev.CallMetadata)
+			}
+		case <-time.After(5 * time.Second):
+			require.FailNow(t, "timed out waiting for webhook")
+		}
+	})
+
+	t.Run("disabled event type", func(t *testing.T) {
+		p, _ := setup(t, "https://attendance.example.com/webhook")
+		p.configuration.EnableLeaveWebhook = model.NewPointer(false)
+
+		p.queueParticipantWebhookEvent(public.ParticipantEvent{Type: public.ParticipantEventTypeLeave})
+		require.Empty(t, p.participantWebhookCh)
+
+		p.queueParticipantWebhookEvent(public.ParticipantEvent{Type: public.ParticipantEventTypeJoin})
+		require.Len(t, p.participantWebhookCh, 1)
+	})
+
+	t.Run("retries", func(t *testing.T) {
+		var attempts atomic.Int32
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			if attempts.Add(1) < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}
+		}))
+		defer ts.Close()
+
+		p, mockAPI := setup(t, ts.URL)
+		mockAPI.On("GetUser", userID).Return(&model.User{Id: userID, Username: "alice"}, nil).Once()
+		mockAPI.On("LogWarn", "participant webhook request failed", "origin", mock.Anything,
+			"attempt", mock.Anything, "err", mock.Anything).Twice()
+
+		p.sendParticipantWebhook([]public.ParticipantEvent{{
+			Type:   public.ParticipantEventTypeJoin,
+			UserID: userID,
+			CallID: callID,
+		}})
+		require.Equal(t, int32(3), attempts.Load())
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer ts.Close()
+
+		p, mockAPI := setup(t, ts.URL)
+		mockAPI.On("GetUser", userID).Return(&model.User{Id: userID, Username: "alice"}, nil).Once()
+		mockAPI.On("LogWarn", "participant webhook request failed", "origin", mock.Anything,
+			"attempt", mock.Anything, "err", mock.Anything).Times(participantWebhookMaxRetries + 1)
+		mockAPI.On("LogError", "failed to send participant webhook", "origin", mock.Anything,
+			"count", "1", "err", "unexpected status code 500").Once()
+
+		p.sendParticipantWebhook([]public.ParticipantEvent{{
+			Type:   public.ParticipantEventTypeJoin,
+			UserID: userID,
+			CallID: callID,
+		}})
+	})
+}
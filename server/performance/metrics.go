@@ -37,6 +37,9 @@ type Metrics struct {
 
 	WebSocketConnections             prometheus.Gauge
 	WebSocketEventCounters           *prometheus.CounterVec
+	WebSocketWriterMessagesCounters  *prometheus.CounterVec
+	WebSocketWriterBytesCounters     *prometheus.CounterVec
+	WebSocketWriterQueueDepth        prometheus.Gauge
 	ClusterEventCounters             *prometheus.CounterVec
 	ClusterMutexGrabTimeHistograms   *prometheus.HistogramVec
 	ClusterMutexLockedTimeHistograms *prometheus.HistogramVec
@@ -46,13 +49,42 @@ type Metrics struct {
 
 	StoreOpCounters            *prometheus.CounterVec
 	StoreMethodsTimeHistograms *prometheus.HistogramVec
+	StoreErrorsCounters        *prometheus.CounterVec
+	StoreBufferedWrites        prometheus.Gauge
 
 	LiveCaptionsNewAudioLenHistogram       prometheus.Histogram
 	LiveCaptionsWindowDroppedCounter       prometheus.Counter
 	LiveCaptionsTranscriberBufFullCounter  prometheus.Counter
 	LiveCaptionsPktPayloadChBufFullCounter prometheus.Counter
 
+	TranscriptionQueueDepth         prometheus.Gauge
+	TranscriptionQueueWaitHistogram prometheus.Histogram
+
 	ClientICECandidatePairsCounter *prometheus.CounterVec
+	ICEConnectionTimeoutsCounter   prometheus.Counter
+	ICEConnectionFailuresCounters  *prometheus.CounterVec
+	ICEConnectionsCounters         *prometheus.CounterVec
+	ICERestartsCounters            *prometheus.CounterVec
+	ThrottledSessionsCounter       prometheus.Counter
+	ZombieSessionsCounter          prometheus.Counter
+	NoiseAutoMutesCounter          prometheus.Counter
+
+	RTCDMessageRetriesCounters  *prometheus.CounterVec
+	RTCDMessagesDroppedCounters *prometheus.CounterVec
+	RTCDConnectionAgeGauges     *prometheus.GaugeVec
+	RTCDLastPingTimeGauges      *prometheus.GaugeVec
+	RejectedSDPsCounters        *prometheus.CounterVec
+	KeyFrameRequestsCounters    *prometheus.CounterVec
+
+	ClientJitterBufferDelayHistogram prometheus.Histogram
+	ClientPacketLossHistograms       *prometheus.HistogramVec
+	CallFeedbackScoresHistogram      prometheus.Histogram
+
+	RecordingStorageQuotaExceededCounters *prometheus.CounterVec
+	RecordingStorageBytes                 prometheus.Gauge
+
+	CleanUpStateTimeHistogram prometheus.Histogram
+	CleanUpStateReapedCounter prometheus.Counter
 }
 
 func NewMetrics() *Metrics {
@@ -83,6 +115,36 @@ func NewMetrics() *Metrics {
 	)
 	m.registry.MustRegister(m.WebSocketEventCounters)
 
+	m.WebSocketWriterMessagesCounters = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubSystemWS,
+			Name:      "writer_messages_total",
+			Help:      "Total number of RTC messages forwarded to WebSocket clients",
+		},
+		[]string{"type"},
+	)
+	m.registry.MustRegister(m.WebSocketWriterMessagesCounters)
+
+	m.WebSocketWriterBytesCounters = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubSystemWS,
+			Name:      "writer_bytes_total",
+			Help:      "Total number of bytes of RTC messages forwarded to WebSocket clients",
+		},
+		[]string{"type"},
+	)
+	m.registry.MustRegister(m.WebSocketWriterBytesCounters)
+
+	m.WebSocketWriterQueueDepth = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubSystemWS,
+		Name:      "writer_queue_depth",
+		Help:      "The number of RTC messages waiting to be forwarded to WebSocket clients by the embedded RTC server.",
+	})
+	m.registry.MustRegister(m.WebSocketWriterQueueDepth)
+
 	m.ClusterEventCounters = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
 			Namespace: metricsNamespace,
@@ -176,6 +238,25 @@ func NewMetrics() *Metrics {
 		})
 	m.registry.MustRegister(m.LiveCaptionsPktPayloadChBufFullCounter)
 
+	m.TranscriptionQueueDepth = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubSystemJobs,
+		Name:      "transcription_queue_depth",
+		Help:      "The number of transcription jobs waiting for a slot to start.",
+	})
+	m.registry.MustRegister(m.TranscriptionQueueDepth)
+
+	m.TranscriptionQueueWaitHistogram = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubSystemJobs,
+			Name:      "transcription_queue_wait_time",
+			Help:      "Time (in seconds) transcription jobs waited in queue before starting",
+			Buckets:   []float64{1, 10, 30, 60, 300, 600, 1800, 3600},
+		},
+	)
+	m.registry.MustRegister(m.TranscriptionQueueWaitHistogram)
+
 	m.AppHandlersTimeHistograms = prometheus.NewHistogramVec(
 		prometheus.HistogramOpts{
 			Namespace: metricsNamespace,
@@ -198,6 +279,25 @@ func NewMetrics() *Metrics {
 	)
 	m.registry.MustRegister(m.StoreMethodsTimeHistograms)
 
+	m.StoreErrorsCounters = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubSystemStore,
+			Name:      "errors_total",
+			Help:      "Total number of failed store methods executions",
+		},
+		[]string{"method", "type"},
+	)
+	m.registry.MustRegister(m.StoreErrorsCounters)
+
+	m.StoreBufferedWrites = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubSystemStore,
+		Name:      "buffered_writes",
+		Help:      "The number of store writes waiting to be retried while the database is unavailable.",
+	})
+	m.registry.MustRegister(m.StoreBufferedWrites)
+
 	m.ClientICECandidatePairsCounter = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
 			Namespace: metricsNamespace,
@@ -209,6 +309,214 @@ func NewMetrics() *Metrics {
 	)
 	m.registry.MustRegister(m.ClientICECandidatePairsCounter)
 
+	m.ICEConnectionTimeoutsCounter = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubSystemApp,
+			Name:      "ice_connection_timeouts_total",
+			Help:      "Total number of joins aborted because the ICE connection could not be established in time",
+		})
+	m.registry.MustRegister(m.ICEConnectionTimeoutsCounter)
+
+	m.ICEConnectionFailuresCounters = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubSystemClient,
+			Name:      "ice_connection_failures_total",
+			Help:      "Total number of client ICE connection failures",
+		},
+		[]string{"candidate_type", "platform"},
+	)
+	m.registry.MustRegister(m.ICEConnectionFailuresCounters)
+
+	m.ICEConnectionsCounters = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubSystemApp,
+			Name:      "ice_connections_total",
+			Help:      "Total number of ICE connections by state (new or connected)",
+		},
+		[]string{"state"},
+	)
+	m.registry.MustRegister(m.ICEConnectionsCounters)
+
+	m.ICERestartsCounters = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubSystemApp,
+			Name:      "ice_restarts_total",
+			Help:      "Total number of ICE restarts by initiator (client or server)",
+		},
+		[]string{"initiator"},
+	)
+	m.registry.MustRegister(m.ICERestartsCounters)
+
+	m.ThrottledSessionsCounter = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubSystemWS,
+			Name:      "throttled_sessions_total",
+			Help:      "Total number of times sessions got throttled for exceeding their messages budget",
+		})
+	m.registry.MustRegister(m.ThrottledSessionsCounter)
+
+	m.ZombieSessionsCounter = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubSystemWS,
+			Name:      "zombie_sessions_total",
+			Help:      "Total number of sessions disconnected for having no media activity while connected",
+		})
+	m.registry.MustRegister(m.ZombieSessionsCounter)
+
+	m.NoiseAutoMutesCounter = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubSystemApp,
+			Name:      "noise_auto_mutes_total",
+			Help:      "Total number of participants automatically muted for sending continuous noise",
+		})
+	m.registry.MustRegister(m.NoiseAutoMutesCounter)
+
+	m.RTCDMessageRetriesCounters = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubSystemApp,
+			Name:      "rtcd_message_retries_total",
+			Help:      "Total number of retries to send messages to the RTCD service",
+		},
+		[]string{"type"},
+	)
+	m.registry.MustRegister(m.RTCDMessageRetriesCounters)
+
+	m.RTCDMessagesDroppedCounters = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubSystemApp,
+			Name:      "rtcd_messages_dropped_total",
+			Help:      "Total number of messages that could not be sent to the RTCD service",
+		},
+		[]string{"type"},
+	)
+	m.registry.MustRegister(m.RTCDMessagesDroppedCounters)
+
+	m.RTCDConnectionAgeGauges = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubSystemApp,
+			Name:      "rtcd_connection_age_seconds",
+			Help:      "Time (in seconds) since the connection to the RTCD host was established",
+		},
+		[]string{"host"},
+	)
+	m.registry.MustRegister(m.RTCDConnectionAgeGauges)
+
+	m.RTCDLastPingTimeGauges = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubSystemApp,
+			Name:      "rtcd_last_ping_timestamp_seconds",
+			Help:      "Unix time (in seconds) of the last successful keepalive ping to the RTCD host",
+		},
+		[]string{"host"},
+	)
+	m.registry.MustRegister(m.RTCDLastPingTimeGauges)
+
+	m.RejectedSDPsCounters = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubSystemApp,
+			Name:      "rejected_sdps_total",
+			Help:      "Total number of client session descriptions rejected for being oversized or malformed",
+		},
+		[]string{"reason"},
+	)
+	m.registry.MustRegister(m.RejectedSDPsCounters)
+
+	m.KeyFrameRequestsCounters = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubSystemApp,
+			Name:      "key_frame_requests_total",
+			Help:      "Total number of key frame requests (PLI/FIR) sent by viewers and received by publishers, as reported by clients",
+		},
+		[]string{"type"},
+	)
+	m.registry.MustRegister(m.KeyFrameRequestsCounters)
+
+	m.ClientJitterBufferDelayHistogram = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubSystemApp,
+			Name:      "client_jitter_buffer_delay_ms",
+			Help:      "Average delay (in ms) introduced by the clients' audio jitter buffer",
+			Buckets:   []float64{20, 40, 60, 80, 100, 150, 200, 300, 500, 1000, 2000},
+		},
+	)
+	m.registry.MustRegister(m.ClientJitterBufferDelayHistogram)
+
+	m.ClientPacketLossHistograms = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubSystemApp,
+			Name:      "client_packet_loss_rate",
+			Help:      "Packet loss rates (0 to 1) periodically reported by the clients' sessions",
+			Buckets:   []float64{0, 0.005, 0.01, 0.02, 0.03, 0.05, 0.1, 0.2, 0.3, 0.5, 1},
+		},
+		[]string{"server_type"},
+	)
+	m.registry.MustRegister(m.ClientPacketLossHistograms)
+
+	m.CallFeedbackScoresHistogram = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubSystemApp,
+			Name:      "call_feedback_scores",
+			Help:      "Call quality scores (1-5) given by participants",
+			Buckets:   []float64{1, 2, 3, 4, 5},
+		},
+	)
+	m.registry.MustRegister(m.CallFeedbackScoresHistogram)
+
+	m.RecordingStorageQuotaExceededCounters = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubSystemApp,
+			Name:      "recording_storage_quota_exceeded_total",
+			Help:      "Total number of times a recording storage quota was found to be exceeded",
+		},
+		[]string{"scope", "policy"},
+	)
+	m.registry.MustRegister(m.RecordingStorageQuotaExceededCounters)
+
+	m.RecordingStorageBytes = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubSystemApp,
+		Name:      "recording_storage_bytes",
+		Help:      "Total size (in bytes) of the call recordings currently stored",
+	})
+	m.registry.MustRegister(m.RecordingStorageBytes)
+
+	m.CleanUpStateTimeHistogram = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubSystemApp,
+			Name:      "clean_up_state_time",
+			Help:      "Time (in seconds) taken to clean up the state of calls left over on activation",
+			Buckets:   []float64{0.1, 0.5, 1, 5, 10, 30, 60, 300},
+		},
+	)
+	m.registry.MustRegister(m.CleanUpStateTimeHistogram)
+
+	m.CleanUpStateReapedCounter = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubSystemApp,
+			Name:      "clean_up_state_reaped_calls_total",
+			Help:      "Total number of calls left over that were ended while cleaning up state",
+		})
+	m.registry.MustRegister(m.CleanUpStateReapedCounter)
+
 	m.rtcMetrics = perf.NewMetrics(metricsNamespace, m.registry)
 
 	return &m
@@ -274,6 +582,14 @@ func (m *Metrics) IncLiveCaptionsPktPayloadChBufFull() {
 	m.LiveCaptionsPktPayloadChBufFullCounter.Inc()
 }
 
+func (m *Metrics) SetTranscriptionQueueDepth(depth int) {
+	m.TranscriptionQueueDepth.Set(float64(depth))
+}
+
+func (m *Metrics) ObserveTranscriptionQueueWaitTime(elapsed float64) {
+	m.TranscriptionQueueWaitHistogram.Observe(elapsed)
+}
+
 func (m *Metrics) ObserveAppHandlersTime(handler string, elapsed float64) {
 	m.AppHandlersTimeHistograms.With(prometheus.Labels{"handler": handler}).Observe(elapsed)
 }
@@ -282,6 +598,14 @@ func (m *Metrics) ObserveStoreMethodsTime(method string, elapsed float64) {
 	m.StoreMethodsTimeHistograms.With(prometheus.Labels{"method": method}).Observe(elapsed)
 }
 
+func (m *Metrics) IncStoreErrors(method, errType string) {
+	m.StoreErrorsCounters.With(prometheus.Labels{"method": method, "type": errType}).Inc()
+}
+
+func (m *Metrics) SetStoreBufferedWrites(count int) {
+	m.StoreBufferedWrites.Set(float64(count))
+}
+
 func (m *Metrics) IncClientICECandidatePairs(p public.ClientICECandidatePairMetricPayload) {
 	m.ClientICECandidatePairsCounter.With(prometheus.Labels{
 		"state":           p.State,
@@ -291,3 +615,97 @@ func (m *Metrics) IncClientICECandidatePairs(p public.ClientICECandidatePairMetr
 		"remote_protocol": p.Remote.Protocol,
 	}).Inc()
 }
+
+func (m *Metrics) IncICEConnectionTimeouts() {
+	m.ICEConnectionTimeoutsCounter.Inc()
+}
+
+func (m *Metrics) IncICEConnectionFailures(candidateType, platform string) {
+	m.ICEConnectionFailuresCounters.With(prometheus.Labels{"candidate_type": candidateType, "platform": platform}).Inc()
+}
+
+func (m *Metrics) IncThrottledSessions() {
+	m.ThrottledSessionsCounter.Inc()
+}
+
+func (m *Metrics) IncZombieSessions() {
+	m.ZombieSessionsCounter.Inc()
+}
+
+func (m *Metrics) IncNoiseAutoMutes() {
+	m.NoiseAutoMutesCounter.Inc()
+}
+
+func (m *Metrics) IncRTCDMessageRetries(msgType string) {
+	m.RTCDMessageRetriesCounters.With(prometheus.Labels{"type": msgType}).Inc()
+}
+
+func (m *Metrics) IncRTCDMessagesDropped(msgType string) {
+	m.RTCDMessagesDroppedCounters.With(prometheus.Labels{"type": msgType}).Inc()
+}
+
+func (m *Metrics) SetRTCDConnectionAge(host string, age float64) {
+	m.RTCDConnectionAgeGauges.With(prometheus.Labels{"host": host}).Set(age)
+}
+
+func (m *Metrics) SetRTCDLastPingTime(host string, ts float64) {
+	m.RTCDLastPingTimeGauges.With(prometheus.Labels{"host": host}).Set(ts)
+}
+
+func (m *Metrics) DeleteRTCDHostMetrics(host string) {
+	m.RTCDConnectionAgeGauges.Delete(prometheus.Labels{"host": host})
+	m.RTCDLastPingTimeGauges.Delete(prometheus.Labels{"host": host})
+}
+
+func (m *Metrics) IncRejectedSDPs(reason string) {
+	m.RejectedSDPsCounters.With(prometheus.Labels{"reason": reason}).Inc()
+}
+
+func (m *Metrics) IncICEConnections(state string) {
+	m.ICEConnectionsCounters.With(prometheus.Labels{"state": state}).Inc()
+}
+
+func (m *Metrics) IncICERestarts(initiator string) {
+	m.ICERestartsCounters.With(prometheus.Labels{"initiator": initiator}).Inc()
+}
+
+func (m *Metrics) AddKeyFrameRequests(reqType string, count int) {
+	m.KeyFrameRequestsCounters.With(prometheus.Labels{"type": reqType}).Add(float64(count))
+}
+
+func (m *Metrics) ObserveClientJitterBufferDelay(delayMs float64) {
+	m.ClientJitterBufferDelayHistogram.Observe(delayMs)
+}
+
+func (m *Metrics) ObserveClientPacketLoss(serverType string, lossRate float64) {
+	m.ClientPacketLossHistograms.With(prometheus.Labels{"server_type": serverType}).Observe(lossRate)
+}
+
+func (m *Metrics) ObserveCallFeedbackScore(score int) {
+	m.CallFeedbackScoresHistogram.Observe(float64(score))
+}
+
+func (m *Metrics) ObserveWebSocketWriterMessage(msgType string, size int) {
+	m.WebSocketWriterMessagesCounters.With(prometheus.Labels{"type": msgType}).Inc()
+	m.WebSocketWriterBytesCounters.With(prometheus.Labels{"type": msgType}).Add(float64(size))
+}
+
+func (m *Metrics) SetWebSocketWriterQueueDepth(depth int) {
+	m.WebSocketWriterQueueDepth.Set(float64(depth))
+}
+
+func (m *Metrics) IncRecordingStorageQuotaExceeded(scope, policy string) {
+	m.RecordingStorageQuotaExceededCounters.With(prometheus.Labels{"scope": scope, "policy": policy}).Inc()
+}
+
+func (m *Metrics) SetRecordingStorageBytes(bytes int64) {
+	m.RecordingStorageBytes.Set(float64(bytes))
+}
+
+func (m *Metrics) ObserveCleanUpStateTime(elapsed float64) {
+	m.CleanUpStateTimeHistogram.Observe(elapsed)
+}
+
+func (m *Metrics) AddCleanUpStateReapedCalls(count int) {
+	m.CleanUpStateReapedCounter.Add(float64(count))
+}
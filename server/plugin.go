@@ -17,6 +17,7 @@ import (
 	"github.com/mattermost/mattermost-plugin-calls/server/db"
 	"github.com/mattermost/mattermost-plugin-calls/server/enterprise"
 	"github.com/mattermost/mattermost-plugin-calls/server/interfaces"
+	"github.com/mattermost/mattermost-plugin-calls/server/public"
 
 	rtcd "github.com/mattermost/rtcd/service"
 	"github.com/mattermost/rtcd/service/rtc"
@@ -54,12 +55,32 @@ type Plugin struct {
 	stopCh      chan struct{}
 	clusterEvCh chan model.PluginClusterEvent
 	sessions    map[string]*session
-
-	rtcServer       *rtc.Server
-	rtcdManager     *rtcdClientManager
-	rtcdVersionInfo rtcd.VersionInfo
+	// A map of connID -> *lobbySession for users testing their connection
+	// before joining a call.
+	lobbySessions map[string]*lobbySession
+	// A map of connID -> *pendingCallStart for call starts waiting on a
+	// confirmation from the client.
+	pendingCallStarts map[string]*pendingCallStart
+	// A map of connID -> *pendingAdmission for join requests waiting on the
+	// host to admit them.
+	pendingAdmissions map[string]*pendingAdmission
+
+	rtcServerMut sync.RWMutex
+	rtcServer    *rtc.Server
+	// The configuration the embedded RTC server was started with, used to
+	// detect changes that require a reload.
+	rtcServerConfig        rtc.ServerConfig
+	rtcServerReloadPending atomic.Bool
+	rtcdManager            *rtcdClientManager
+	rtcdVersionInfo        rtcd.VersionInfo
 
 	jobService *jobService
+	// Set when the recordings entitlement lapses while the plugin is running
+	// so that ongoing jobs can still complete.
+	recordingsLicenseLapsedAt atomic.Int64
+	// The number of recording outputs (uploads, posts) currently being
+	// finalized, waited on when deactivating.
+	recordingFinalizations atomic.Int32
 
 	// A map of userID -> limiter to implement basic, user based API rate-limiting.
 	// TODO: consider moving this to a dedicated API object.
@@ -79,6 +100,40 @@ type Plugin struct {
 	// Batchers
 	addSessionsBatchers    map[string]*batching.Batcher
 	removeSessionsBatchers map[string]*batching.Batcher
+
+	// A map of pluginID -> *participantEventsSubscriber for plugins
+	// interested in participant join/leave events.
+	participantEventsSubs    map[string]*participantEventsSubscriber
+	participantEventsSubsMut sync.RWMutex
+
+	// Participant events queued for delivery to the participant webhook.
+	participantWebhookCh chan public.ParticipantEvent
+
+	// Call timeline events queued for persisting.
+	callTimelineCh chan queuedCallTimelineEvent
+
+	// Store writes waiting for the database to become available again,
+	// keyed by record.
+	dbWriteBuffer    map[string]bufferedDBWrite
+	dbWriteBufferSeq uint64
+	dbWriteBufferMut sync.Mutex
+	// Serializes saving buffered writes.
+	dbWriteFlushMut        sync.Mutex
+	dbWriteBufferFlusherWg sync.WaitGroup
+
+	// Whether calls hosted by this node should reduce quality due to CPU
+	// pressure.
+	callsQualityDegraded atomic.Bool
+
+	// A map of callID -> *pendingPoorConnections for participants to alert
+	// hosts of.
+	poorConnections    map[string]*pendingPoorConnections
+	poorConnectionsMut sync.Mutex
+
+	// A map of sessionID -> *time.Timer timing the ongoing voice activity of
+	// sessions to detect noisy ones.
+	noisySessions    map[string]*time.Timer
+	noisySessionsMut sync.Mutex
 }
 
 func (p *Plugin) startSession(us *session, senderID string, props rtc.SessionProps) {
@@ -89,7 +144,8 @@ func (p *Plugin) startSession(us *session, senderID string, props rtc.SessionPro
 		SessionID: us.connID,
 		Props:     props,
 	}
-	if err := p.rtcServer.InitSession(cfg, func() error {
+	rtcServer := p.getRTCServer()
+	if err := rtcServer.InitSession(cfg, func() error {
 		p.LogDebug("rtc session close cb", "sessionID", us.connID)
 		if atomic.CompareAndSwapInt32(&us.rtcClosed, 0, 1) {
 			close(us.rtcCloseCh)
@@ -102,7 +158,7 @@ func (p *Plugin) startSession(us *session, senderID string, props rtc.SessionPro
 
 	defer func() {
 		p.LogDebug("closing rtc session", "sessionID", us.connID)
-		if err := p.rtcServer.CloseSession(us.connID); err != nil {
+		if err := rtcServer.CloseSession(us.connID); err != nil {
 			p.LogError("failed to close session", "error", err.Error())
 		}
 	}()
@@ -116,7 +172,7 @@ func (p *Plugin) startSession(us *session, senderID string, props rtc.SessionPro
 			clusterMsg := clusterMessage{
 				ConnID:    us.connID,
 				UserID:    us.userID,
-				ChannelID: us.channelID,
+				ChannelID: us.getChannelID(),
 				CallID:    us.callID,
 				SenderID:  p.nodeID,
 				ClientMessage: clientMessage{
@@ -152,14 +208,19 @@ func (p *Plugin) handleEvent(ev model.PluginClusterEvent) error {
 	switch clusterMessageType(ev.Id) {
 	case clusterMessageTypeConnect:
 		p.LogDebug("connect event", "ChannelID", msg.ChannelID, "UserID", msg.UserID, "ConnID", msg.ConnID)
+		call, err := p.store.GetCall(msg.CallID, db.GetCallOpts{})
+		if err != nil {
+			return fmt.Errorf("failed to get call: %w", err)
+		}
 		p.mut.Lock()
 		defer p.mut.Unlock()
 		us := p.sessions[msg.ConnID]
 		if us != nil {
 			return fmt.Errorf("session already exists, userID=%q, connID=%q, channelID=%q",
-				us.userID, msg.ConnID, us.channelID)
+				us.userID, msg.ConnID, us.getChannelID())
 		}
 		us = newUserSession(msg.UserID, msg.ChannelID, msg.ConnID, msg.CallID, true)
+		us.setCallProps(call.Props)
 		p.sessions[msg.ConnID] = us
 		go p.startSession(us, msg.SenderID, msg.SessionProps)
 		return nil
@@ -274,6 +335,18 @@ func (p *Plugin) handleEvent(ev model.PluginClusterEvent) error {
 		if err := p.sendRTCMessage(rtcMsg, us.callID); err != nil {
 			return fmt.Errorf("failed to send RTC message: %w", err)
 		}
+	case clusterMessageTypeAdmit, clusterMessageTypeDeny:
+		p.LogDebug("admission event", "UserID", msg.UserID, "ConnID", msg.ConnID, "type", ev.Id)
+		go p.resolveAdmission(msg.ConnID, clusterMessageType(ev.Id) == clusterMessageTypeAdmit)
+	case clusterMessageTypeMove:
+		p.LogDebug("move event", "CallID", msg.CallID, "ChannelID", msg.ChannelID)
+		p.setSessionsChannel(msg.CallID, msg.ChannelID)
+	case clusterMessageTypeNoiseAutoMute:
+		p.LogDebug("noise auto mute event", "CallID", msg.CallID)
+		p.setSessionsNoiseAutoMute(msg.CallID, msg.NoiseAutoMute)
+	case clusterMessageTypeLiveCaptions:
+		p.LogDebug("live captions event", "CallID", msg.CallID)
+		p.setSessionsLiveCaptionsOff(msg.CallID, msg.LiveCaptionsOff)
 	default:
 		return fmt.Errorf("unexpected event type %q", ev.Id)
 	}
@@ -294,7 +367,7 @@ func (p *Plugin) clusterEventsHandler() {
 	}
 }
 
-func (p *Plugin) createCallStartedPost(state *callState, userID, channelID, title, threadID string) (string, string, error) {
+func (p *Plugin) createCallStartedPost(state *callState, userID, channelID, title, threadID string, postAsBot bool) (string, string, error) {
 	user, appErr := p.API.GetUser(userID)
 	if appErr != nil {
 		return "", "", appErr
@@ -322,8 +395,13 @@ func (p *Plugin) createCallStartedPost(state *callState, userID, channelID, titl
 		Text:     postMsg,
 	}
 
+	postUserID := userID
+	if postAsBot {
+		postUserID = p.getBotID()
+	}
+
 	post := &model.Post{
-		UserId:    userID,
+		UserId:    postUserID,
 		ChannelId: channelID,
 		RootId:    threadID,
 		Message:   postMsg,
@@ -332,6 +410,9 @@ func (p *Plugin) createCallStartedPost(state *callState, userID, channelID, titl
 			"attachments": []*model.SlackAttachment{&slackAttachment},
 			"start_at":    state.Call.StartAt,
 			"title":       title,
+			// Lets clients (e.g. mobile opening a push notification) go
+			// straight into the call.
+			"call_id": state.Call.ID,
 		},
 	}
 
@@ -404,6 +485,22 @@ func (p *Plugin) MessageWillBeUpdated(c *plugin.Context, newPost, oldPost *model
 	return newPost, ""
 }
 
+// MessageHasBeenPosted catches channels being converted between public and
+// private, since there's no dedicated hook for it, and posts that could
+// trigger a recording.
+func (p *Plugin) MessageHasBeenPosted(_ *plugin.Context, post *model.Post) {
+	if post.Type == model.PostTypeChangeChannelPrivacy {
+		go func() {
+			if err := p.handleChannelPrivacyChange(post.ChannelId); err != nil {
+				p.LogError("failed to handle channel privacy change", "err", err.Error(), "channelID", post.ChannelId)
+			}
+		}()
+		return
+	}
+
+	p.handleRecordingKeywordPost(post)
+}
+
 func (p *Plugin) UserHasLeftChannel(_ *plugin.Context, cm *model.ChannelMember, _ *model.User) {
 	if cm == nil {
 		p.LogWarn("UserHasLeftChannel: unexpected nil channel member")
@@ -436,6 +533,7 @@ func (p *Plugin) UserHasLeftChannel(_ *plugin.Context, cm *model.ChannelMember,
 				"user_id":    session.UserID,
 				"session_id": connID,
 				"channelID":  cm.ChannelId,
+				"call_id":    state.Call.ID,
 			}, &WebSocketBroadcast{UserID: cm.UserId, ReliableClusterSend: true})
 		}
 	}
@@ -8,8 +8,18 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-calls/server/public"
+
+	pluginMocks "github.com/mattermost/mattermost-plugin-calls/server/mocks/github.com/mattermost/mattermost/server/public/plugin"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func TestServeHTTP(t *testing.T) {
@@ -31,3 +41,78 @@ func TestServeHTTP(t *testing.T) {
 	assert.Equal("404 page not found\n", bodyString)
 	assert.Equal(http.StatusNotFound, result.StatusCode)
 }
+
+func TestCreateCallStartedPost(t *testing.T) {
+	botID := model.NewId()
+	userID := model.NewId()
+	channelID := model.NewId()
+
+	state := &callState{
+		Call: public.Call{
+			ID:        model.NewId(),
+			ChannelID: channelID,
+			StartAt:   time.Now().UnixMilli(),
+		},
+	}
+
+	for _, tc := range []struct {
+		name      string
+		postAsBot bool
+		author    string
+	}{
+		{name: "user", postAsBot: false, author: userID},
+		{name: "bot", postAsBot: true, author: botID},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mockAPI := &pluginMocks.MockAPI{}
+			defer mockAPI.AssertExpectations(t)
+
+			p := Plugin{
+				MattermostPlugin: plugin.MattermostPlugin{
+					API: mockAPI,
+				},
+				botSession: &model.Session{UserId: botID},
+			}
+
+			postID := model.NewId()
+			mockAPI.On("GetUser", userID).Return(&model.User{Id: userID, Username: "alice"}, nil).Once()
+			mockAPI.On("GetConfig").Return(&model.Config{}).Twice()
+			mockAPI.On("CreatePost", mock.MatchedBy(func(post *model.Post) bool {
+				return post.UserId == tc.author && post.ChannelId == channelID && post.Type == callStartPostType &&
+					post.GetProp("call_id") == state.Call.ID
+			})).Return(&model.Post{Id: postID}, nil).Once()
+			mockAPI.On("GetLicense").Return(nil).Once()
+			mockAPI.On("GetChannel", channelID).Return(&model.Channel{Id: channelID, Type: model.ChannelTypeOpen}, nil).Once()
+
+			createdPostID, threadID, err := p.createCallStartedPost(state, userID, channelID, "", "", tc.postAsBot)
+			require.NoError(t, err)
+			require.Equal(t, postID, createdPostID)
+			require.Equal(t, postID, threadID)
+		})
+	}
+
+	t.Run("call push notifications disabled", func(t *testing.T) {
+		mockAPI := &pluginMocks.MockAPI{}
+		defer mockAPI.AssertExpectations(t)
+
+		p := Plugin{
+			MattermostPlugin: plugin.MattermostPlugin{
+				API: mockAPI,
+			},
+			botSession: &model.Session{UserId: botID},
+		}
+		cfg := &configuration{}
+		cfg.SetDefaults()
+		cfg.EnableCallPushNotifications = model.NewPointer(false)
+		p.configuration = cfg
+
+		postID := model.NewId()
+		mockAPI.On("GetUser", userID).Return(&model.User{Id: userID, Username: "alice"}, nil).Once()
+		mockAPI.On("GetConfig").Return(&model.Config{}).Twice()
+		mockAPI.On("CreatePost", mock.AnythingOfType("*model.Post")).Return(&model.Post{Id: postID}, nil).Once()
+
+		createdPostID, _, err := p.createCallStartedPost(state, userID, channelID, "", "", false)
+		require.NoError(t, err)
+		require.Equal(t, postID, createdPostID)
+	})
+}
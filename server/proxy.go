@@ -0,0 +1,138 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const proxyConnectTimeout = 10 * time.Second
+
+// getOutboundProxyURL returns the plugin level proxy outbound connections
+// should go through, if any.
+func (c *configuration) getOutboundProxyURL() *url.URL {
+	if c.OutboundProxyURL == "" {
+		return nil
+	}
+
+	u, err := url.Parse(c.OutboundProxyURL)
+	if err != nil {
+		return nil
+	}
+
+	return u
+}
+
+// getOutboundProxyFunc returns the proxy selection function for outbound HTTP
+// requests. The plugin level proxy takes precedence over the standard
+// HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables.
+func (c *configuration) getOutboundProxyFunc() func(*http.Request) (*url.URL, error) {
+	if proxyURL := c.getOutboundProxyURL(); proxyURL != nil {
+		return http.ProxyURL(proxyURL)
+	}
+	return http.ProxyFromEnvironment
+}
+
+// newOutboundHTTPClient returns an HTTP client honoring the configured
+// outbound proxy.
+func (c *configuration) newOutboundHTTPClient(timeout time.Duration) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = c.getOutboundProxyFunc()
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+	}
+}
+
+// getProxyDialFn returns a dialing function that tunnels connections to
+// targetAddr through the given HTTP proxy using the CONNECT method. It's
+// needed for clients that dial specific hosts (e.g. rtcd) rather than
+// letting the transport pick the proxy.
+func getProxyDialFn(proxyURL *url.URL, targetAddr string) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, network, _ string) (net.Conn, error) {
+		dialer := &net.Dialer{
+			Timeout: dialingTimeout,
+		}
+		conn, err := dialer.DialContext(ctx, network, getURLHostPort(proxyURL))
+		if err != nil {
+			return nil, fmt.Errorf("failed to dial proxy: %w", err)
+		}
+
+		if proxyURL.Scheme == "https" {
+			tlsConn := tls.Client(conn, &tls.Config{ServerName: proxyURL.Hostname()})
+			if err := tlsConn.HandshakeContext(ctx); err != nil {
+				conn.Close()
+				return nil, fmt.Errorf("failed to establish TLS connection to proxy: %w", err)
+			}
+			conn = tlsConn
+		}
+
+		if err := proxyConnect(conn, proxyURL, targetAddr); err != nil {
+			conn.Close()
+			return nil, err
+		}
+
+		return conn, nil
+	}
+}
+
+// getURLHostPort returns the host:port address of the given URL, falling
+// back to the default port for the scheme.
+func getURLHostPort(u *url.URL) string {
+	if u.Port() != "" {
+		return u.Host
+	}
+	if u.Scheme == "https" || u.Scheme == "wss" {
+		return net.JoinHostPort(u.Hostname(), "443")
+	}
+	return net.JoinHostPort(u.Hostname(), "80")
+}
+
+func proxyConnect(conn net.Conn, proxyURL *url.URL, targetAddr string) error {
+	if err := conn.SetDeadline(time.Now().Add(proxyConnectTimeout)); err != nil {
+		return fmt.Errorf("failed to set deadline: %w", err)
+	}
+	defer func() {
+		_ = conn.SetDeadline(time.Time{})
+	}()
+
+	req := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: targetAddr},
+		Host:   targetAddr,
+		Header: http.Header{},
+	}
+	if proxyURL.User != nil {
+		password, _ := proxyURL.User.Password()
+		auth := base64.StdEncoding.EncodeToString([]byte(proxyURL.User.Username() + ":" + password))
+		req.Header.Set("Proxy-Authorization", "Basic "+auth)
+	}
+
+	if err := req.Write(conn); err != nil {
+		return fmt.Errorf("failed to send CONNECT request: %w", err)
+	}
+
+	// The proxy isn't expected to send anything beyond the response headers
+	// before the tunnel is established so it's safe to discard the reader.
+	resp, err := http.ReadResponse(bufio.NewReader(conn), req)
+	if err != nil {
+		return fmt.Errorf("failed to read CONNECT response: %w", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("proxy CONNECT failed: %s", resp.Status)
+	}
+
+	return nil
+}
@@ -0,0 +1,162 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newMockProxy returns a test HTTP proxy supporting both plain (absolute-URI)
+// requests and CONNECT tunnels. All requests are recorded.
+func newMockProxy(t *testing.T) (*httptest.Server, *atomic.Value) {
+	t.Helper()
+
+	var lastReq atomic.Value
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lastReq.Store(r.Clone(context.Background()))
+
+		if r.Method != http.MethodConnect {
+			w.Header().Set("X-Proxied", "true")
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+
+		if r.Header.Get("Proxy-Authorization") == "Basic ZGVuaWVkOg==" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		targetConn, err := net.Dial("tcp", r.Host)
+		if err != nil {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+
+		hj, ok := w.(http.Hijacker)
+		require.True(t, ok)
+		conn, _, err := hj.Hijack()
+		require.NoError(t, err)
+		_, err = conn.Write([]byte("HTTP/1.1 200 Connection established\r\n\r\n"))
+		require.NoError(t, err)
+
+		go func() {
+			defer conn.Close()
+			defer targetConn.Close()
+			go func() {
+				_, _ = io.Copy(targetConn, conn)
+			}()
+			_, _ = io.Copy(conn, targetConn)
+		}()
+	}))
+	t.Cleanup(ts.Close)
+
+	return ts, &lastReq
+}
+
+func TestNewOutboundHTTPClient(t *testing.T) {
+	proxy, lastReq := newMockProxy(t)
+
+	var cfg configuration
+	cfg.SetDefaults()
+	cfg.OutboundProxyURL = proxy.URL
+
+	client := cfg.newOutboundHTTPClient(5 * time.Second)
+	resp, err := client.Post("http://webhook.example.com/summarize", "application/json", nil)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "true", resp.Header.Get("X-Proxied"))
+
+	req, ok := lastReq.Load().(*http.Request)
+	require.True(t, ok)
+	require.Equal(t, http.MethodPost, req.Method)
+	require.Equal(t, "webhook.example.com", req.URL.Host)
+	require.Equal(t, "/summarize", req.URL.Path)
+}
+
+func TestGetProxyDialFn(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer target.Close()
+	targetURL, err := url.Parse(target.URL)
+	require.NoError(t, err)
+
+	proxy, lastReq := newMockProxy(t)
+
+	newClient := func(proxyURL string) *http.Client {
+		u, err := url.Parse(proxyURL)
+		require.NoError(t, err)
+		return &http.Client{
+			Transport: &http.Transport{
+				DialContext: getProxyDialFn(u, getURLHostPort(targetURL)),
+			},
+			Timeout: 5 * time.Second,
+		}
+	}
+
+	t.Run("tunnel", func(t *testing.T) {
+		// The address passed by the transport is ignored in favour of the target one.
+		resp, err := newClient(proxy.URL).Get("http://rtcd.example.com/version")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		data, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.Equal(t, "ok", string(data))
+
+		req, ok := lastReq.Load().(*http.Request)
+		require.True(t, ok)
+		require.Equal(t, http.MethodConnect, req.Method)
+		require.Equal(t, targetURL.Host, req.Host)
+		require.Empty(t, req.Header.Get("Proxy-Authorization"))
+	})
+
+	t.Run("credentials", func(t *testing.T) {
+		u, err := url.Parse(proxy.URL)
+		require.NoError(t, err)
+		u.User = url.UserPassword("user", "pass")
+
+		resp, err := newClient(u.String()).Get(target.URL)
+		require.NoError(t, err)
+		resp.Body.Close()
+
+		req, ok := lastReq.Load().(*http.Request)
+		require.True(t, ok)
+		require.Equal(t, "Basic dXNlcjpwYXNz", req.Header.Get("Proxy-Authorization"))
+	})
+
+	t.Run("rejected", func(t *testing.T) {
+		u, err := url.Parse(proxy.URL)
+		require.NoError(t, err)
+		u.User = url.User("denied")
+
+		_, err = newClient(u.String()).Get(target.URL)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "proxy CONNECT failed: 403 Forbidden")
+	})
+}
+
+func TestGetURLHostPort(t *testing.T) {
+	for input, expected := range map[string]string{
+		"http://localhost:8045":     "localhost:8045",
+		"http://proxy.example.com":  "proxy.example.com:80",
+		"https://proxy.example.com": "proxy.example.com:443",
+		"wss://rtcd.example.com/ws": "rtcd.example.com:443",
+		"http://[::1]":              "[::1]:80",
+	} {
+		u, err := url.Parse(input)
+		require.NoError(t, err)
+		require.Equal(t, expected, getURLHostPort(u), input)
+	}
+}
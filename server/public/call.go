@@ -5,8 +5,57 @@ package public
 
 import (
 	"fmt"
+	"slices"
 )
 
+// CallEndReason describes why a call has ended.
+type CallEndReason string
+
+const (
+	// CallEndReasonHostEnded is set when the host ended the call for everyone.
+	CallEndReasonHostEnded CallEndReason = "host-ended"
+	// CallEndReasonAdminEnded is set when a system admin, other than the host,
+	// ended the call for everyone.
+	CallEndReasonAdminEnded CallEndReason = "admin-ended"
+	// CallEndReasonAllLeft is set when the last participant left the call.
+	CallEndReasonAllLeft CallEndReason = "all-left"
+	// CallEndReasonEmptyTimeout is set when the call ended after being empty
+	// for too long.
+	CallEndReasonEmptyTimeout CallEndReason = "empty-timeout"
+	// CallEndReasonIdleTimeout is set when the call ended because of no
+	// activity.
+	CallEndReasonIdleTimeout CallEndReason = "idle-timeout"
+	// CallEndReasonMaxDuration is set when the call reached the maximum
+	// allowed duration.
+	CallEndReasonMaxDuration CallEndReason = "max-duration"
+	// CallEndReasonError is set when the call ended because of an unexpected
+	// failure.
+	CallEndReasonError CallEndReason = "error"
+	// CallEndReasonNodeFailure is set when the call was cleaned up because the
+	// node hosting it went away (e.g. crash or restart).
+	CallEndReasonNodeFailure CallEndReason = "node-failure"
+	// CallEndReasonNodeDrained is set when the call was ended because the
+	// node hosting it was drained for maintenance.
+	CallEndReasonNodeDrained CallEndReason = "node-drained"
+	// CallEndReasonPrivacyChanged is set when the call was ended because
+	// its channel was converted between public and private and some
+	// participants lost access to it.
+	CallEndReasonPrivacyChanged CallEndReason = "privacy-changed"
+	// CallEndReasonMoved is sent to the channel a call was moved out of. The
+	// call itself keeps going in the new channel.
+	CallEndReasonMoved CallEndReason = "moved"
+)
+
+// The interactive features that can be disabled for a call.
+const (
+	CallFeatureReactions = "reactions"
+	CallFeatureChat      = "chat"
+	CallFeatureRaiseHand = "raise_hand"
+)
+
+// CallFeatures are all the features that can be disabled for a call.
+var CallFeatures = []string{CallFeatureReactions, CallFeatureChat, CallFeatureRaiseHand}
+
 type Call struct {
 	ID           string      `json:"id"`
 	ChannelID    string      `json:"channel_id"`
@@ -62,6 +111,12 @@ func (c Call) GetHostID() string {
 	return c.Props.Hosts[0]
 }
 
+// IsFeatureDisabled returns whether the given feature (e.g. chat) has been
+// disabled for the call.
+func (c Call) IsFeatureDisabled(feature string) bool {
+	return slices.Contains(c.Props.DisabledFeatures, feature)
+}
+
 type CallProps struct {
 	Hosts                  []string            `json:"hosts,omitempty"`
 	RTCDHost               string              `json:"rtcd_host,omitempty"`
@@ -71,6 +126,90 @@ type CallProps struct {
 	NodeID                 string              `json:"node_id,omitempty"`
 	Participants           map[string]struct{} `json:"participants,omitempty"`
 	HostLockedUserID       string              `json:"host_locked_user_id,omitempty"`
+	JoinMuted              bool                `json:"join_muted,omitempty"`
+	LastParticipantLeftAt  int64               `json:"last_participant_left_at,omitempty"`
+	SpeakerLabels          bool                `json:"speaker_labels,omitempty"`
+	// HostNodeID is the ID of the cluster node the host is connected through.
+	HostNodeID string `json:"host_node_id,omitempty"`
+	// Tag is the category (e.g. "standup") the call was started with.
+	Tag string `json:"tag,omitempty"`
+	// Metadata is the custom key-value data (e.g. an external meeting ID) the
+	// call was started with.
+	Metadata map[string]string `json:"metadata,omitempty"`
+	// HostAssignmentPolicy is the policy in effect when the call started. An
+	// empty value means the initiator becomes host.
+	HostAssignmentPolicy string `json:"host_assignment_policy,omitempty"`
+	// ChannelAdmins are the participants who are channel admins. Only tracked
+	// when the host assignment policy requires it.
+	ChannelAdmins map[string]struct{} `json:"channel_admins,omitempty"`
+	// NoiseSuppression is whether clients should apply noise suppression to
+	// their audio. There's no server-side audio processing.
+	NoiseSuppression bool `json:"noise_suppression,omitempty"`
+	// VideoSessionIDs are the sessions currently publishing camera video.
+	VideoSessionIDs []string `json:"video_session_ids,omitempty"`
+	// LiveCaptionsOff is whether live captions have been turned off for the
+	// call by the host.
+	LiveCaptionsOff bool `json:"live_captions_off,omitempty"`
+	// EndReason is why the call ended. It's set when the call ends, or when
+	// ending it has been requested (e.g. by the host).
+	EndReason CallEndReason `json:"end_reason,omitempty"`
+	// WaitingRoom is whether participants need to be admitted by the host
+	// before joining.
+	WaitingRoom bool `json:"waiting_room,omitempty"`
+	// Waiting are the sessions waiting to be admitted, keyed by session ID.
+	Waiting map[string]CallWaitingSession `json:"waiting,omitempty"`
+	// MaxParticipants is the group message participant limit in effect when
+	// the call started, zero meaning only the global limit applies.
+	MaxParticipants int `json:"max_participants,omitempty"`
+	// Preset is the name of the preset the call was started with.
+	Preset string `json:"preset,omitempty"`
+	// AudioOnly is whether camera video is disallowed in the call.
+	AudioOnly bool `json:"audio_only,omitempty"`
+	// Feedback holds the quality scores (1-5) participants gave once the
+	// call ended, keyed by user ID.
+	Feedback map[string]int `json:"feedback,omitempty"`
+	// ScreenSharingSessionIDs are the sessions sharing their screen, in the
+	// order they started. ScreenSharingSessionID is the first of them.
+	ScreenSharingSessionIDs []string `json:"screen_sharing_session_ids,omitempty"`
+	// DisabledFeatures are the interactive features (e.g. reactions) turned
+	// off for the call.
+	DisabledFeatures []string `json:"disabled_features,omitempty"`
+	// NoiseAutoMute, if set, overrides the configured automatic muting of
+	// noisy participants for the call.
+	NoiseAutoMute *CallNoiseAutoMute `json:"noise_auto_mute,omitempty"`
+}
+
+// The range of time (in seconds) voice activity can last without pauses before
+// a participant is considered noisy.
+const (
+	MinNoiseAutoMuteThresholdSeconds = 10
+	MaxNoiseAutoMuteThresholdSeconds = 300
+)
+
+// CallNoiseAutoMute controls the automatic muting of participants whose audio
+// is continuous noise rather than speech.
+type CallNoiseAutoMute struct {
+	Enabled bool `json:"enabled"`
+	// ThresholdSeconds is how long voice activity needs to last without any
+	// pause for the audio to be considered noise. Speech naturally pauses, so
+	// lower values are more sensitive but risk muting long-winded speakers.
+	ThresholdSeconds int `json:"threshold_seconds"`
+}
+
+func (n CallNoiseAutoMute) IsValid() error {
+	if n.ThresholdSeconds < MinNoiseAutoMuteThresholdSeconds || n.ThresholdSeconds > MaxNoiseAutoMuteThresholdSeconds {
+		return fmt.Errorf("invalid ThresholdSeconds: should be in the range [%d, %d]", MinNoiseAutoMuteThresholdSeconds, MaxNoiseAutoMuteThresholdSeconds)
+	}
+
+	return nil
+}
+
+// CallWaitingSession is a session waiting in a call's waiting room.
+type CallWaitingSession struct {
+	UserID string `json:"user_id"`
+	// NodeID is the ID of the cluster node holding the join request.
+	NodeID  string `json:"node_id"`
+	EnterAt int64  `json:"enter_at"`
 }
 
 type CallStats struct {
@@ -0,0 +1,51 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package public
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+)
+
+const (
+	CallDataMaxSizeBytes = 16 * 1024 // 16KB
+	CallDataMaxNameLen   = 64
+)
+
+var callDataNameRE = regexp.MustCompile(`^[a-zA-Z0-9_\-]+$`)
+
+// CallData is a named payload (e.g. an agenda, a linked document) attached to
+// a call by another plugin. Calls only stores it and relays it to the call's
+// participants, rendering it is up to the clients.
+type CallData struct {
+	PluginID string          `json:"plugin_id"`
+	Name     string          `json:"name"`
+	Data     json.RawMessage `json:"data"`
+	UpdateAt int64           `json:"update_at"`
+}
+
+func (d CallData) IsValid() error {
+	if d.PluginID == "" {
+		return fmt.Errorf("invalid PluginID: should not be empty")
+	}
+
+	if d.Name == "" || len(d.Name) > CallDataMaxNameLen || !callDataNameRE.MatchString(d.Name) {
+		return fmt.Errorf("invalid Name: should be 1 to %d alphanumeric, dash or underscore characters", CallDataMaxNameLen)
+	}
+
+	if len(d.Data) == 0 {
+		return fmt.Errorf("invalid Data: should not be empty")
+	}
+
+	if len(d.Data) > CallDataMaxSizeBytes {
+		return fmt.Errorf("invalid Data: should not be larger than %d bytes", CallDataMaxSizeBytes)
+	}
+
+	if !json.Valid(d.Data) {
+		return fmt.Errorf("invalid Data: should be valid JSON")
+	}
+
+	return nil
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package public
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCallDataIsValid(t *testing.T) {
+	tcs := []struct {
+		name string
+		data CallData
+		err  string
+	}{
+		{
+			name: "missing PluginID",
+			data: CallData{Name: "agenda", Data: json.RawMessage(`{}`)},
+			err:  "invalid PluginID: should not be empty",
+		},
+		{
+			name: "invalid Name",
+			data: CallData{PluginID: "com.example.plugin", Name: "agenda/items", Data: json.RawMessage(`{}`)},
+			err:  "invalid Name: should be 1 to 64 alphanumeric, dash or underscore characters",
+		},
+		{
+			name: "Name too long",
+			data: CallData{PluginID: "com.example.plugin", Name: strings.Repeat("a", 65), Data: json.RawMessage(`{}`)},
+			err:  "invalid Name: should be 1 to 64 alphanumeric, dash or underscore characters",
+		},
+		{
+			name: "missing Data",
+			data: CallData{PluginID: "com.example.plugin", Name: "agenda"},
+			err:  "invalid Data: should not be empty",
+		},
+		{
+			name: "Data too large",
+			data: CallData{PluginID: "com.example.plugin", Name: "agenda", Data: json.RawMessage(`"` + strings.Repeat("a", CallDataMaxSizeBytes) + `"`)},
+			err:  "invalid Data: should not be larger than 16384 bytes",
+		},
+		{
+			name: "invalid Data",
+			data: CallData{PluginID: "com.example.plugin", Name: "agenda", Data: json.RawMessage(`{"items":`)},
+			err:  "invalid Data: should be valid JSON",
+		},
+		{
+			name: "valid",
+			data: CallData{PluginID: "com.example.plugin", Name: "agenda_v-2", Data: json.RawMessage(`{"items":["intro"]}`)},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.data.IsValid()
+			if tc.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tc.err)
+			}
+		})
+	}
+}
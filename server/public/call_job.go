@@ -7,6 +7,10 @@ import (
 	"fmt"
 )
 
+// CallJobEndReasonCanceled marks a job that was aborted rather than stopped
+// normally. Jobs stopped normally have no end reason.
+const CallJobEndReasonCanceled = "canceled"
+
 type CallJob struct {
 	ID        string       `json:"id"`
 	CallID    string       `json:"call_id"`
@@ -50,4 +54,66 @@ type CallJobProps struct {
 	JobID     string `json:"job_id,omitempty"`
 	BotConnID string `json:"bot_conn_id,omitempty"`
 	Err       string `json:"err,omitempty"`
+	// PausedAt is set while the job is paused.
+	PausedAt int64 `json:"paused_at,omitempty"`
+	// Pauses holds the intervals during which the job was paused.
+	Pauses []CallJobPause `json:"pauses,omitempty"`
+	// PrimaryJobID references the main recording job of the call. It's only
+	// set on the additional recording jobs capturing the same call with a
+	// different quality profile.
+	PrimaryJobID string `json:"primary_job_id,omitempty"`
+	// Profile is the quality profile of a recording job.
+	Profile string `json:"profile,omitempty"`
+	// Markers holds the named points in time the host marked during a
+	// recording, used to split it into chapters.
+	Markers []CallJobMarker `json:"markers,omitempty"`
+	// EndReason is set when the job didn't end normally (e.g. canceled).
+	EndReason string `json:"end_reason,omitempty"`
+	// DiscardOutput is set when the job's output (e.g. recording file) should
+	// not be posted once the job ends.
+	DiscardOutput bool `json:"discard_output,omitempty"`
+	// Anonymized is set when participants should be shown with pseudonyms in
+	// the job's output (e.g. recording, transcript).
+	Anonymized bool `json:"anonymized,omitempty"`
+	// Layout is how participants are arranged in a recording (e.g. grid).
+	Layout string `json:"layout,omitempty"`
+	// WatermarkText is the text overlaid on a recording, if any.
+	WatermarkText string `json:"watermark_text,omitempty"`
+}
+
+// CallJobPause is an interval during which a job was not capturing.
+type CallJobPause struct {
+	StartAt int64 `json:"start_at"`
+	EndAt   int64 `json:"end_at"`
+}
+
+// CallJobMarker is a named point in time during a job.
+type CallJobMarker struct {
+	Name     string `json:"name"`
+	CreateAt int64  `json:"create_at"`
+}
+
+// ActiveRecordingStatus is the status of an in-progress recording.
+type ActiveRecordingStatus string
+
+const (
+	// ActiveRecordingStatusInitializing is the status of a recording whose
+	// job hasn't started capturing yet.
+	ActiveRecordingStatusInitializing ActiveRecordingStatus = "initializing"
+	ActiveRecordingStatusRecording    ActiveRecordingStatus = "recording"
+	ActiveRecordingStatusPaused       ActiveRecordingStatus = "paused"
+)
+
+// ActiveRecording is a recording in progress in an ongoing call.
+type ActiveRecording struct {
+	JobID     string `json:"job_id"`
+	CallID    string `json:"call_id"`
+	ChannelID string `json:"channel_id"`
+	CreatorID string `json:"creator_id"`
+	InitAt    int64  `json:"init_at"`
+	StartAt   int64  `json:"start_at"`
+	// PrimaryJobID is only set on additional recordings (e.g. capturing a
+	// single screen share) and references the main recording of the call.
+	PrimaryJobID string                `json:"primary_job_id,omitempty"`
+	Status       ActiveRecordingStatus `json:"status"`
 }
@@ -0,0 +1,58 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package public
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCallNoiseAutoMuteIsValid(t *testing.T) {
+	tcs := []struct {
+		name          string
+		noiseAutoMute CallNoiseAutoMute
+		err           string
+	}{
+		{
+			name:          "empty",
+			noiseAutoMute: CallNoiseAutoMute{},
+			err:           "invalid ThresholdSeconds: should be in the range [10, 300]",
+		},
+		{
+			name:          "ThresholdSeconds too high",
+			noiseAutoMute: CallNoiseAutoMute{Enabled: true, ThresholdSeconds: 301},
+			err:           "invalid ThresholdSeconds: should be in the range [10, 300]",
+		},
+		{
+			name:          "valid",
+			noiseAutoMute: CallNoiseAutoMute{Enabled: true, ThresholdSeconds: 30},
+		},
+		{
+			name:          "valid disabled",
+			noiseAutoMute: CallNoiseAutoMute{ThresholdSeconds: 10},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.noiseAutoMute.IsValid()
+			if tc.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tc.err)
+			}
+		})
+	}
+}
+
+func TestCallIsFeatureDisabled(t *testing.T) {
+	var call Call
+	require.False(t, call.IsFeatureDisabled(CallFeatureChat))
+
+	call.Props.DisabledFeatures = []string{CallFeatureReactions, CallFeatureRaiseHand}
+	require.True(t, call.IsFeatureDisabled(CallFeatureReactions))
+	require.True(t, call.IsFeatureDisabled(CallFeatureRaiseHand))
+	require.False(t, call.IsFeatureDisabled(CallFeatureChat))
+}
@@ -14,7 +14,12 @@ const (
 	MetricLiveCaptionsTranscriberBufFull  MetricName = "live_captions_transcriber_buf_full"
 	MetricLiveCaptionsPktPayloadChBufFull MetricName = "live_captions_pktPayloadCh_buf_full"
 
-	MetricClientICECandidatePair MetricName = "client_ice_candidate_pair"
+	MetricClientICECandidatePair  MetricName = "client_ice_candidate_pair"
+	MetricClientJitterBufferDelay MetricName = "client_jitter_buffer_delay"
+	MetricClientMediaKeepAlive    MetricName = "client_media_keepalive"
+	MetricClientKeyFrameRequests  MetricName = "client_key_frame_requests"
+	MetricClientICEFailure        MetricName = "client_ice_failure"
+	MetricClientConnectionQuality MetricName = "client_connection_quality"
 )
 
 type MetricMsg struct {
@@ -66,3 +71,113 @@ func (c ClientICECandidatePairMetricPayload) IsValid() error {
 
 	return nil
 }
+
+// The upper bound for a reported jitter buffer delay. Anything above this is
+// most likely a client bug.
+const maxJitterBufferDelayMs = 60000
+
+// ClientJitterBufferDelayMetricPayload holds the average delay (in
+// milliseconds) introduced by the client's audio jitter buffer.
+type ClientJitterBufferDelayMetricPayload struct {
+	DelayMs float64 `json:"delay_ms"`
+}
+
+func (c ClientJitterBufferDelayMetricPayload) IsValid() error {
+	if c.DelayMs < 0 || c.DelayMs > maxJitterBufferDelayMs {
+		return fmt.Errorf("invalid delay %v: range should be [0, %d]", c.DelayMs, maxJitterBufferDelayMs)
+	}
+
+	return nil
+}
+
+// ClientMediaKeepAliveMetricPayload is periodically sent by clients to signal
+// their media path is alive. BytesReceived is the total number of bytes
+// received over the selected ICE candidate pair. This includes STUN consent
+// checks so it keeps increasing on a live connection even if no one is
+// talking.
+type ClientMediaKeepAliveMetricPayload struct {
+	BytesReceived int64 `json:"bytes_received"`
+}
+
+func (c ClientMediaKeepAliveMetricPayload) IsValid() error {
+	if c.BytesReceived < 0 {
+		return fmt.Errorf("invalid bytes received %d: should not be negative", c.BytesReceived)
+	}
+
+	return nil
+}
+
+// The upper bound for the number of key frame requests reported at once.
+// Anything above this is most likely a client bug.
+const maxKeyFrameRequestsCount = 100000
+
+// ClientKeyFrameRequestsMetricPayload holds the number of key frame requests
+// (PLI/FIR) a client sent as a viewer and received as a publisher since its
+// previous report. Comparing the two tells how many requests were coalesced by
+// the SFU.
+type ClientKeyFrameRequestsMetricPayload struct {
+	Sent     int `json:"sent"`
+	Received int `json:"received"`
+}
+
+func (c ClientKeyFrameRequestsMetricPayload) IsValid() error {
+	if c.Sent < 0 || c.Sent > maxKeyFrameRequestsCount {
+		return fmt.Errorf("invalid sent count %d: range should be [0, %d]", c.Sent, maxKeyFrameRequestsCount)
+	}
+
+	if c.Received < 0 || c.Received > maxKeyFrameRequestsCount {
+		return fmt.Errorf("invalid received count %d: range should be [0, %d]", c.Received, maxKeyFrameRequestsCount)
+	}
+
+	return nil
+}
+
+// ClientICEFailureMetricPayload is sent by clients when their ICE connection
+// fails. CandidateType is the local type of the candidate pair that was being
+// checked or used when the connection failed, or "unknown" if none was.
+// Platform is optional.
+type ClientICEFailureMetricPayload struct {
+	CandidateType string `json:"candidate_type"`
+	Platform      string `json:"platform,omitempty"`
+}
+
+func (c ClientICEFailureMetricPayload) IsValid() error {
+	switch c.CandidateType {
+	case "host", "srflx", "prflx", "relay", "unknown":
+	default:
+		return fmt.Errorf("invalid candidate type %q", c.CandidateType)
+	}
+
+	switch c.Platform {
+	case "", "web", "desktop", "mobile":
+	default:
+		return fmt.Errorf("invalid platform %q", c.Platform)
+	}
+
+	return nil
+}
+
+// The upper bound for a reported round trip time. Anything above this is most
+// likely a client bug.
+const maxConnectionRTTMs = 60000
+
+// ClientConnectionQualityMetricPayload is periodically sent by clients to
+// report the quality of their media connection. RTTMs is the round trip time
+// (in milliseconds) of the selected ICE candidate pair while LossRate is the
+// fraction (0 to 1) of packets lost since the previous report.
+type ClientConnectionQualityMetricPayload struct {
+	RTTMs    float64 `json:"rtt_ms"`
+	LossRate float64 `json:"loss_rate"`
+}
+
+func (c ClientConnectionQualityMetricPayload) IsValid() error {
+	if c.RTTMs < 0 || c.RTTMs > maxConnectionRTTMs {
+		return fmt.Errorf("invalid rtt %v: range should be [0, %d]", c.RTTMs, maxConnectionRTTMs)
+	}
+
+	if c.LossRate < 0 || c.LossRate > 1 {
+		return fmt.Errorf("invalid loss rate %v: range should be [0, 1]", c.LossRate)
+	}
+
+	return nil
+}
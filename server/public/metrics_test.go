@@ -157,3 +157,77 @@ func TestClientICECandidatePairMetricPayloadIsValid(t *testing.T) {
 		})
 	}
 }
+
+func TestClientJitterBufferDelayMetricPayloadIsValid(t *testing.T) {
+	tcs := []struct {
+		name string
+		info ClientJitterBufferDelayMetricPayload
+		err  string
+	}{
+		{
+			name: "negative delay",
+			info: ClientJitterBufferDelayMetricPayload{
+				DelayMs: -1,
+			},
+			err: "invalid delay -1: range should be [0, 60000]",
+		},
+		{
+			name: "delay too large",
+			info: ClientJitterBufferDelayMetricPayload{
+				DelayMs: 60001,
+			},
+			err: "invalid delay 60001: range should be [0, 60000]",
+		},
+		{
+			name: "valid",
+			info: ClientJitterBufferDelayMetricPayload{
+				DelayMs: 85.5,
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.info.IsValid()
+			if tc.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tc.err)
+			}
+		})
+	}
+}
+
+func TestClientMediaKeepAliveMetricPayloadIsValid(t *testing.T) {
+	require.EqualError(t, ClientMediaKeepAliveMetricPayload{BytesReceived: -1}.IsValid(),
+		"invalid bytes received -1: should not be negative")
+	require.NoError(t, ClientMediaKeepAliveMetricPayload{}.IsValid())
+	require.NoError(t, ClientMediaKeepAliveMetricPayload{BytesReceived: 45000}.IsValid())
+}
+
+func TestClientKeyFrameRequestsMetricPayloadIsValid(t *testing.T) {
+	require.EqualError(t, ClientKeyFrameRequestsMetricPayload{Sent: -1}.IsValid(),
+		"invalid sent count -1: range should be [0, 100000]")
+	require.EqualError(t, ClientKeyFrameRequestsMetricPayload{Received: 100001}.IsValid(),
+		"invalid received count 100001: range should be [0, 100000]")
+	require.NoError(t, ClientKeyFrameRequestsMetricPayload{}.IsValid())
+	require.NoError(t, ClientKeyFrameRequestsMetricPayload{Sent: 12, Received: 3}.IsValid())
+}
+
+func TestClientICEFailureMetricPayloadIsValid(t *testing.T) {
+	require.EqualError(t, ClientICEFailureMetricPayload{}.IsValid(), `invalid candidate type ""`)
+	require.EqualError(t, ClientICEFailureMetricPayload{CandidateType: "relay", Platform: "tv"}.IsValid(), `invalid platform "tv"`)
+	require.NoError(t, ClientICEFailureMetricPayload{CandidateType: "unknown"}.IsValid())
+	require.NoError(t, ClientICEFailureMetricPayload{CandidateType: "relay", Platform: "desktop"}.IsValid())
+}
+
+func TestClientConnectionQualityMetricPayloadIsValid(t *testing.T) {
+	require.EqualError(t, ClientConnectionQualityMetricPayload{RTTMs: -1}.IsValid(),
+		"invalid rtt -1: range should be [0, 60000]")
+	require.EqualError(t, ClientConnectionQualityMetricPayload{RTTMs: 60001}.IsValid(),
+		"invalid rtt 60001: range should be [0, 60000]")
+	require.EqualError(t, ClientConnectionQualityMetricPayload{LossRate: 1.5}.IsValid(),
+		"invalid loss rate 1.5: range should be [0, 1]")
+	require.NoError(t, ClientConnectionQualityMetricPayload{}.IsValid())
+	require.NoError(t, ClientConnectionQualityMetricPayload{RTTMs: 120, LossRate: 0.02}.IsValid())
+}
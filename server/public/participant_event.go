@@ -0,0 +1,53 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package public
+
+import (
+	"fmt"
+	"strings"
+)
+
+type ParticipantEventType string
+
+const (
+	ParticipantEventTypeJoin  ParticipantEventType = "join"
+	ParticipantEventTypeLeave ParticipantEventType = "leave"
+)
+
+// ParticipantEvent is delivered to subscribed plugins whenever a participant
+// joins or leaves a call.
+//
+// Events are delivered asynchronously and there are no ordering guarantees
+// (e.g. a leave event could be received before the respective join event).
+// Events may also be dropped if a subscriber is not able to keep up, so
+// handlers should return quickly and offload any expensive processing.
+type ParticipantEvent struct {
+	Type      ParticipantEventType `json:"type"`
+	ChannelID string               `json:"channel_id"`
+	CallID    string               `json:"call_id"`
+	UserID    string               `json:"user_id"`
+	SessionID string               `json:"session_id"`
+	CreateAt  int64                `json:"create_at"`
+	// CallMetadata is the custom metadata the call was started with, if any.
+	CallMetadata map[string]string `json:"call_metadata,omitempty"`
+}
+
+// ParticipantEventsSubscription is sent by plugins interested in receiving
+// participant events. Events are POSTed to the given path of the subscribing
+// plugin.
+type ParticipantEventsSubscription struct {
+	Path string `json:"path"`
+}
+
+func (s ParticipantEventsSubscription) IsValid() error {
+	if s.Path == "" {
+		return fmt.Errorf("invalid Path: should not be empty")
+	}
+
+	if !strings.HasPrefix(s.Path, "/") {
+		return fmt.Errorf("invalid Path: should start with a slash")
+	}
+
+	return nil
+}
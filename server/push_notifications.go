@@ -29,15 +29,34 @@ func (p *Plugin) NotificationWillBePushed(notification *model.PushNotification,
 		p.LogError("store.GetActiveCallByChannelID failed", "err", err.Error())
 	}
 
+	if notification.PostType == callStartPostType && !p.getConfiguration().callPushNotificationsEnabled() {
+		msg := "calls: push notifications for calls are disabled"
+		p.LogDebug(msg, "userID", userID, "channelID", notification.ChannelId)
+		return nil, msg
+	}
+
+	if notification.PostType == callStartPostType {
+		if notify, _ := p.shouldNotifyUserForCall(userID, notification.ChannelId, notification.ChannelType); !notify {
+			msg := "calls: call notifications are disabled by user preferences"
+			p.LogDebug(msg, "userID", userID, "channelID", notification.ChannelId)
+			return nil, msg
+		}
+	}
+
 	// We will use our own notifications if:
 	// 1. This is a call start post
 	// 2. We have enabled ringing
-	// 3. The channel is a DM or GM
-	if notification.PostType != callStartPostType || !*p.getConfiguration().EnableRinging {
+	// 3. The channel is a DM or GM and ringing is enabled for its type
+	cfg := p.getConfiguration()
+	if notification.PostType != callStartPostType || !*cfg.EnableRinging {
 		return nil, ""
 	}
 
 	if notification.ChannelType == model.ChannelTypeDirect || notification.ChannelType == model.ChannelTypeGroup {
+		if !cfg.ringingEnabledForChannelType(notification.ChannelType) {
+			// The call only posts a message so the regular notification goes through.
+			return nil, ""
+		}
 		return nil, "calls plugin will handle this notification"
 	}
 
@@ -67,6 +86,10 @@ func (p *Plugin) NotificationWillBePushed(notification *model.PushNotification,
 }
 
 func (p *Plugin) sendPushNotifications(channelID, createdPostID, threadID string, sender *model.User, config *model.Config) {
+	if !p.getConfiguration().callPushNotificationsEnabled() {
+		return
+	}
+
 	if err := p.canSendPushNotifications(config, p.API.GetLicense()); err != nil {
 		return
 	}
@@ -81,6 +104,10 @@ func (p *Plugin) sendPushNotifications(channelID, createdPostID, threadID string
 		return
 	}
 
+	if !p.getConfiguration().ringOnStart(channel.Type) {
+		return
+	}
+
 	members, appErr := p.API.GetUsersInChannel(channelID, model.ChannelSortByUsername, 0, 8)
 	if appErr != nil {
 		p.LogError("failed to get channel users", "error", appErr.Error())
@@ -92,6 +119,11 @@ func (p *Plugin) sendPushNotifications(channelID, createdPostID, threadID string
 			continue
 		}
 
+		notify, silent := p.shouldNotifyUserForCall(member.Id, channelID, channel.Type)
+		if !notify {
+			continue
+		}
+
 		receiver, appErr := p.API.GetUser(member.Id)
 		if appErr != nil {
 			p.LogError("failed to get receiver user", "error", appErr.Error())
@@ -111,6 +143,12 @@ func (p *Plugin) sendPushNotifications(channelID, createdPostID, threadID string
 			Message:     buildGenericPushNotificationMessage(receiver.Locale),
 		}
 
+		// Calls notifications ring on mobile, silent ones are sent as
+		// regular messages instead.
+		if silent {
+			msg.SubType = ""
+		}
+
 		// This is ugly because it's a little complicated. We need to special case IdLoaded notifications (don't expose
 		// any details of the push notification on the wire). Otherwise, we can send more information, unless the server
 		// has set GenericNoChannel.
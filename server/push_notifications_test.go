@@ -4,6 +4,7 @@
 package main
 
 import (
+	"net/http"
 	"testing"
 	"time"
 
@@ -105,6 +106,8 @@ func TestNotificationWillBePushed(t *testing.T) {
 		cfg.SetDefaults()
 
 		mockAPI.On("GetLicense").Return(&model.License{}, nil).Times(2)
+		mockAPI.On("GetPreferenceForUser", mock.Anything, callNotificationPreferencesCategory, callNotificationPreferencesName).
+			Return(model.Preference{}, &model.AppError{StatusCode: http.StatusNotFound})
 		*cfg.EnableRinging = true
 		err := p.setConfiguration(cfg.Clone())
 		require.NoError(t, err)
@@ -150,6 +153,31 @@ func TestNotificationWillBePushed(t *testing.T) {
 			require.Equal(t, "calls plugin will handle this notification", msg)
 		})
 
+		t.Run("ringing disabled for GMs", func(t *testing.T) {
+			*cfg.EnableGMRinging = false
+			err := p.setConfiguration(cfg.Clone())
+			require.NoError(t, err)
+			defer func() {
+				*cfg.EnableGMRinging = true
+				require.NoError(t, p.setConfiguration(cfg.Clone()))
+			}()
+
+			res, msg := p.NotificationWillBePushed(&model.PushNotification{
+				PostType:    callStartPostType,
+				ChannelType: model.ChannelTypeDirect,
+			}, "userID")
+			require.Nil(t, res)
+			require.Equal(t, "calls plugin will handle this notification", msg)
+
+			// The call only posts a message, notified as usual.
+			res, msg = p.NotificationWillBePushed(&model.PushNotification{
+				PostType:    callStartPostType,
+				ChannelType: model.ChannelTypeGroup,
+			}, "userID")
+			require.Nil(t, res)
+			require.Empty(t, msg)
+		})
+
 		t.Run("regular channel", func(t *testing.T) {
 			mockAPI.On("GetUser", "receiverID").Return(&model.User{
 				FirstName: "Firstname",
@@ -197,5 +225,40 @@ func TestNotificationWillBePushed(t *testing.T) {
 				require.Empty(t, msg)
 			})
 		})
+
+		t.Run("disabled by user preferences", func(t *testing.T) {
+			mockAPI.On("GetPreferenceForUser", "mutedUserID", callNotificationPreferencesCategory, callNotificationPreferencesName).
+				Return(model.Preference{Value: `{"mode":"ring","channels":"direct","muted_channel_ids":[]}`}, nil).Once()
+			mockAPI.On("LogDebug", "calls: call notifications are disabled by user preferences",
+				"origin", mock.AnythingOfType("string"),
+				"userID", "mutedUserID", "channelID", "").Once()
+
+			res, msg := p.NotificationWillBePushed(&model.PushNotification{
+				PostType:    callStartPostType,
+				ChannelType: model.ChannelTypeOpen,
+				SenderId:    "senderID",
+			}, "mutedUserID")
+			require.Nil(t, res)
+			require.Equal(t, "calls: call notifications are disabled by user preferences", msg)
+		})
+
+		t.Run("call push notifications disabled", func(t *testing.T) {
+			p.getConfiguration().EnableCallPushNotifications = model.NewPointer(false)
+			defer func() {
+				p.getConfiguration().EnableCallPushNotifications = model.NewPointer(true)
+			}()
+
+			mockAPI.On("LogDebug", "calls: push notifications for calls are disabled",
+				"origin", mock.AnythingOfType("string"),
+				"userID", "receiverID", "channelID", "").Once()
+
+			res, msg := p.NotificationWillBePushed(&model.PushNotification{
+				PostType:    callStartPostType,
+				ChannelType: model.ChannelTypeOpen,
+				SenderId:    "senderID",
+			}, "receiverID")
+			require.Nil(t, res)
+			require.Equal(t, "calls: push notifications for calls are disabled", msg)
+		})
 	})
 }
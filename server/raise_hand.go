@@ -0,0 +1,152 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-calls/server/public"
+)
+
+const (
+	hostActivityKeyPrefix = "host_activity_"
+	hostActivityKeyTTL    = 24 * time.Hour
+)
+
+// getRaisedHandsQueue returns the sessions with a raised hand, in the order
+// they raised it.
+func getRaisedHandsQueue(sessions map[string]*public.CallSession) []*public.CallSession {
+	var queue []*public.CallSession
+	for _, session := range sessions {
+		if session.RaisedHand > 0 {
+			queue = append(queue, session)
+		}
+	}
+	slices.SortFunc(queue, func(a, b *public.CallSession) int {
+		return cmp.Compare(a.RaisedHand, b.RaisedHand)
+	})
+	return queue
+}
+
+// getRaisedHandDeadline returns the time the given raised hand should be
+// lowered at. Host activity after the hand was raised restarts the timer.
+func getRaisedHandDeadline(raisedAt, hostActivityAt int64, timeout time.Duration) time.Time {
+	return time.UnixMilli(max(raisedAt, hostActivityAt)).Add(timeout)
+}
+
+// getExpiredRaisedHands returns the sessions, in queue order, whose hand has
+// been raised for longer than the timeout.
+func getExpiredRaisedHands(sessions map[string]*public.CallSession, hostActivityAt int64, timeout time.Duration, now time.Time) []*public.CallSession {
+	var expired []*public.CallSession
+	for _, session := range getRaisedHandsQueue(sessions) {
+		if !now.Before(getRaisedHandDeadline(session.RaisedHand, hostActivityAt, timeout)) {
+			expired = append(expired, session)
+		}
+	}
+	return expired
+}
+
+// recordHostActivity restarts the auto-lower timer of the hands raised in the
+// given call.
+func (p *Plugin) recordHostActivity(callID string) {
+	if p.getConfiguration().getRaiseHandAutoLowerTimeout() == 0 {
+		return
+	}
+
+	value := []byte(strconv.FormatInt(time.Now().UnixMilli(), 10))
+	if appErr := p.API.KVSetWithExpiry(hostActivityKeyPrefix+callID, value, int64(hostActivityKeyTTL.Seconds())); appErr != nil {
+		p.LogError("failed to set host activity", "err", appErr.Error(), "callID", callID)
+	}
+}
+
+func (p *Plugin) getHostActivityAt(callID string) (int64, error) {
+	data, appErr := p.API.KVGet(hostActivityKeyPrefix + callID)
+	if appErr != nil {
+		return 0, fmt.Errorf("failed to get host activity: %w", appErr)
+	}
+	if data == nil {
+		return 0, nil
+	}
+	return strconv.ParseInt(string(data), 10, 64)
+}
+
+// autoLowerHand waits for the hand raised by the given session to expire and
+// lowers it, unless it was lowered (or raised again) in the meantime.
+func (p *Plugin) autoLowerHand(channelID, sessionID string, raisedAt int64, timeout time.Duration) {
+	deadline := getRaisedHandDeadline(raisedAt, 0, timeout)
+	for {
+		select {
+		case <-time.After(time.Until(deadline)):
+		case <-p.stopCh:
+			return
+		}
+
+		var err error
+		deadline, err = p.lowerExpiredHands(channelID, sessionID, raisedAt, timeout)
+		if err != nil {
+			p.LogError("failed to lower expired hands", "err", err.Error(), "channelID", channelID)
+			return
+		}
+		if deadline.IsZero() {
+			return
+		}
+	}
+}
+
+// lowerExpiredHands lowers all the hands in the call that have been raised
+// for longer than the timeout. It returns the time the hand raised by the
+// given session should be checked again at, or a zero time if it doesn't
+// need to be.
+func (p *Plugin) lowerExpiredHands(channelID, sessionID string, raisedAt int64, timeout time.Duration) (time.Time, error) {
+	state, err := p.lockCallReturnState(channelID)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to lock call: %w", err)
+	}
+	defer p.unlockCall(channelID)
+
+	if state == nil {
+		return time.Time{}, nil
+	}
+
+	if session := state.sessions[sessionID]; session == nil || session.RaisedHand != raisedAt {
+		return time.Time{}, nil
+	}
+
+	hostActivityAt, err := p.getHostActivityAt(state.Call.ID)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	for _, session := range getExpiredRaisedHands(state.sessions, hostActivityAt, timeout, time.Now()) {
+		session.RaisedHand = 0
+		if err := p.store.UpdateCallSession(session); err != nil {
+			return time.Time{}, fmt.Errorf("failed to update call session: %w", err)
+		}
+
+		p.LogDebug("raised hand lowered automatically", "callID", state.Call.ID, "sessionID", session.ID)
+
+		p.recordCallTimelineEvent(state.Call.ID, callTimelineEventLowerHand, session.UserID, session.ID, "")
+		p.publishWebSocketEvent(wsEventUserUnraiseHand, map[string]interface{}{
+			"userID":       session.UserID,
+			"session_id":   session.ID,
+			"call_id":      state.Call.ID,
+			"raised_hand":  session.RaisedHand,
+			"auto_lowered": true,
+		}, &WebSocketBroadcast{
+			ChannelID:           channelID,
+			ReliableClusterSend: true,
+			UserIDs:             getUserIDsFromSessions(state.sessions),
+		})
+	}
+
+	if session := state.sessions[sessionID]; session.RaisedHand == raisedAt {
+		return getRaisedHandDeadline(raisedAt, hostActivityAt, timeout), nil
+	}
+
+	return time.Time{}, nil
+}
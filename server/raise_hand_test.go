@@ -0,0 +1,62 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-calls/server/public"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetExpiredRaisedHands(t *testing.T) {
+	now := time.Now()
+	timeout := time.Minute
+
+	sessions := map[string]*public.CallSession{
+		"connA": {ID: "connA", RaisedHand: now.Add(-30 * time.Second).UnixMilli()},
+		"connB": {ID: "connB", RaisedHand: now.Add(-2 * time.Minute).UnixMilli()},
+		"connC": {ID: "connC"},
+		"connD": {ID: "connD", RaisedHand: now.Add(-90 * time.Second).UnixMilli()},
+	}
+
+	getIDs := func(sessions []*public.CallSession) []string {
+		var ids []string
+		for _, session := range sessions {
+			ids = append(ids, session.ID)
+		}
+		return ids
+	}
+
+	t.Run("queue order", func(t *testing.T) {
+		require.Equal(t, []string{"connB", "connD", "connA"}, getIDs(getRaisedHandsQueue(sessions)))
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		require.Equal(t, []string{"connB", "connD"}, getIDs(getExpiredRaisedHands(sessions, 0, timeout, now)))
+	})
+
+	t.Run("host activity", func(t *testing.T) {
+		// Host activity restarts the timer of all the raised hands.
+		hostActivityAt := now.Add(-70 * time.Second).UnixMilli()
+		require.Equal(t, []string{"connB", "connD"}, getIDs(getExpiredRaisedHands(sessions, hostActivityAt, timeout, now)))
+
+		hostActivityAt = now.Add(-10 * time.Second).UnixMilli()
+		require.Empty(t, getExpiredRaisedHands(sessions, hostActivityAt, timeout, now))
+		require.Equal(t, time.UnixMilli(hostActivityAt).Add(timeout), getRaisedHandDeadline(sessions["connB"].RaisedHand, hostActivityAt, timeout))
+	})
+
+	t.Run("remaining queue order", func(t *testing.T) {
+		for _, session := range getExpiredRaisedHands(sessions, 0, timeout, now) {
+			session.RaisedHand = 0
+		}
+		require.Equal(t, []string{"connA"}, getIDs(getRaisedHandsQueue(sessions)))
+
+		// Raising again puts the hand back at the end of the queue.
+		sessions["connB"].RaisedHand = now.UnixMilli()
+		require.Equal(t, []string{"connA", "connB"}, getIDs(getRaisedHandsQueue(sessions)))
+	})
+}
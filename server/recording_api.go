@@ -5,10 +5,16 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/mattermost/mattermost-plugin-calls/server/db"
 	"github.com/mattermost/mattermost-plugin-calls/server/public"
 
 	"github.com/mattermost/calls-offloader/public/job"
@@ -20,6 +26,48 @@ import (
 
 const recordingJobStartTimeout = time.Minute
 
+type recordingStartRequest struct {
+	// Lets the host skip the configured watermark for this recording,
+	// avoiding the additional processing cost when it's not needed.
+	DisableWatermark bool `json:"disable_watermark"`
+	// Lets the host pick the layout of this recording in place of the
+	// configured default.
+	Layout string `json:"layout"`
+}
+
+// renderRecordingWatermark fills the watermark template placeholders. Date
+// and time are rendered in UTC since participants could be in different
+// timezones.
+func renderRecordingWatermark(tmpl, channelName string, t time.Time) string {
+	t = t.UTC()
+	return strings.NewReplacer(
+		"{channel_name}", channelName,
+		"{date}", t.Format("2006-01-02"),
+		"{time}", t.Format("15:04 MST"),
+	).Replace(tmpl)
+}
+
+// getRecordingWatermarkText returns the watermark text for a new recording
+// of the given channel or an empty string if the watermark doesn't apply.
+func (p *Plugin) getRecordingWatermarkText(channelID string) (string, error) {
+	cfg := p.getConfiguration()
+	if cfg.RecordingWatermarkTemplate == "" || !p.licenseChecker.RecordingWatermarkAllowed() {
+		return "", nil
+	}
+
+	channel, appErr := p.API.GetChannel(channelID)
+	if appErr != nil {
+		return "", fmt.Errorf("failed to get channel: %w", appErr)
+	}
+
+	channelName := channel.DisplayName
+	if channelName == "" {
+		channelName = channel.Name
+	}
+
+	return renderRecordingWatermark(cfg.RecordingWatermarkTemplate, channelName, time.Now()), nil
+}
+
 func (p *Plugin) recJobTimeoutChecker(callID, jobID string) {
 	time.Sleep(recordingJobStartTimeout)
 
@@ -36,6 +84,23 @@ func (p *Plugin) recJobTimeoutChecker(callID, jobID string) {
 		return
 	}
 
+	// Additional recording jobs that failed to start are ended without
+	// affecting the main recording.
+	for _, profileJob := range state.ProfileRecordings {
+		if profileJob.StartAt != 0 || profileJob.EndAt != 0 || time.Since(time.UnixMilli(profileJob.InitAt)) < recordingJobStartTimeout {
+			continue
+		}
+		p.LogError("timed out waiting for profile recorder bot to join", "callID", callID, "jobID", profileJob.Props.JobID, "profile", profileJob.Props.Profile)
+		profileJob.Props.Err = "failed to start recording job: timed out waiting for bot to join call"
+		profileJob.EndAt = time.Now().UnixMilli()
+		if err := p.store.UpdateCallJob(profileJob); err != nil {
+			p.LogError("failed to update call job", "callID", callID, "jobID", profileJob.ID, "err", err.Error())
+		}
+		if err := p.getJobService().StopJob(callID, profileJob.ID, p.getBotID(), profileJob.Props.BotConnID); err != nil {
+			p.LogError("failed to stop profile recording job", "err", err.Error(), "jobID", profileJob.ID, "callID", callID)
+		}
+	}
+
 	// If the recording hasn't started (bot hasn't joined yet) we notify the
 	// client.
 	if recState.StartAt == 0 {
@@ -73,22 +138,85 @@ func (p *Plugin) recJobTimeoutChecker(callID, jobID string) {
 	}
 }
 
-func (p *Plugin) startRecordingJob(state *callState, callID, userID string) (rst *JobStateClient, rcode int, rerr error) {
+func (p *Plugin) startRecordingJob(state *callState, callID, userID string, req recordingStartRequest) (rst *JobStateClient, rcode int, rerr error) {
 	if state.Recording != nil && state.Recording.EndAt == 0 {
 		return nil, http.StatusForbidden, fmt.Errorf("recording already in progress")
 	}
 
+	cfg := p.getConfiguration()
+
+	if req.Layout != "" && !isValidRecordingLayout(req.Layout) {
+		return nil, http.StatusBadRequest, fmt.Errorf("invalid layout: should be either %q or %q", recordingLayoutGrid, recordingLayoutActiveSpeaker)
+	}
+
+	if err := p.checkRecordingStorageQuota(callID); err != nil {
+		var quotaErr *recordingStorageQuotaError
+		if errors.As(err, &quotaErr) {
+			return nil, http.StatusForbidden, err
+		}
+		return nil, http.StatusInternalServerError, err
+	}
+
+	opts := jobOptions{
+		RecordingLayout: cfg.getRecordingLayout(req.Layout),
+	}
+
+	// The watermark is rendered by the recording page, which gets it through
+	// the job state.
+	var watermarkText string
+	if !req.DisableWatermark {
+		text, err := p.getRecordingWatermarkText(callID)
+		if err != nil {
+			return nil, http.StatusInternalServerError, fmt.Errorf("failed to get recording watermark: %w", err)
+		}
+		watermarkText = text
+	}
+
+	additionalQualities := cfg.getRecordingAdditionalQualities()
+
 	recState := new(public.CallJob)
 	recState.ID = model.NewId()
 	recState.CallID = state.Call.ID
 	recState.Type = public.JobTypeRecording
 	recState.CreatorID = userID
 	recState.InitAt = time.Now().UnixMilli()
+	recState.Props.Anonymized = cfg.AnonymizeRecordings != nil && *cfg.AnonymizeRecordings
+	recState.Props.Layout = opts.RecordingLayout
+	recState.Props.WatermarkText = watermarkText
+	if len(additionalQualities) > 0 {
+		recState.Props.Profile = cfg.RecordingQuality
+	}
 
 	if err := p.store.CreateCallJob(recState); err != nil {
 		return nil, http.StatusInternalServerError, fmt.Errorf("failed to create call job: %w", err)
 	}
 
+	// Additional quality profiles are recorded by separate jobs tied to the
+	// main one. These are best effort: failing to start any of them doesn't
+	// affect the main recording.
+	var profileJobs []*public.CallJob
+	for _, quality := range additionalQualities {
+		profileJob := &public.CallJob{
+			ID:        model.NewId(),
+			CallID:    state.Call.ID,
+			Type:      public.JobTypeRecording,
+			CreatorID: userID,
+			InitAt:    time.Now().UnixMilli(),
+			Props: public.CallJobProps{
+				PrimaryJobID:  recState.ID,
+				Profile:       quality,
+				Anonymized:    recState.Props.Anonymized,
+				Layout:        recState.Props.Layout,
+				WatermarkText: recState.Props.WatermarkText,
+			},
+		}
+		if err := p.store.CreateCallJob(profileJob); err != nil {
+			p.LogError("failed to create profile recording job", "err", err.Error(), "callID", callID, "profile", quality)
+			continue
+		}
+		profileJobs = append(profileJobs, profileJob)
+	}
+
 	defer func() {
 		// In case of any error we relay it to the client.
 		if rerr != nil && recState != nil {
@@ -120,7 +248,16 @@ func (p *Plugin) startRecordingJob(state *callState, callID, userID string) (rst
 	// We don't want to keep the lock while making the API call to the service since it
 	// could take a while to return. We lock again as soon as this returns.
 	p.unlockCall(callID)
-	recJobID, jobErr := p.getJobService().RunJob(job.TypeRecording, callID, state.Call.PostID, recState.ID, p.botSession.Token)
+	recJobID, jobErr := p.getJobService().RunJob(job.TypeRecording, callID, state.Call.PostID, recState.ID, p.botSession.Token, opts)
+	profileJobIDs := make([]string, len(profileJobs))
+	profileJobErrs := make([]error, len(profileJobs))
+	if jobErr == nil {
+		for i, profileJob := range profileJobs {
+			profileOpts := opts
+			profileOpts.RecordingQuality = profileJob.Props.Profile
+			profileJobIDs[i], profileJobErrs[i] = p.getJobService().RunJob(job.TypeRecording, callID, state.Call.PostID, profileJob.ID, p.botSession.Token, profileOpts)
+		}
+	}
 	state, err := p.lockCallReturnState(callID)
 	if err != nil {
 		return nil, http.StatusInternalServerError, fmt.Errorf("failed to lock call: %w", err)
@@ -131,6 +268,33 @@ func (p *Plugin) startRecordingJob(state *callState, callID, userID string) (rst
 		return nil, http.StatusInternalServerError, fmt.Errorf("failed to get recording state: %w", err)
 	}
 
+	for i, profileJob := range profileJobs {
+		profileState := state.getProfileRecording(profileJob.ID)
+		if profileState == nil {
+			continue
+		}
+
+		if jobErr != nil || profileJobErrs[i] != nil {
+			profileState.EndAt = time.Now().UnixMilli()
+			if profileJobErrs[i] != nil {
+				p.LogError("failed to create profile recording job", "err", profileJobErrs[i].Error(), "callID", callID, "profile", profileState.Props.Profile)
+				profileState.Props.Err = profileJobErrs[i].Error()
+			}
+		} else {
+			profileState.Props.JobID = profileJobIDs[i]
+		}
+
+		if err := p.store.UpdateCallJob(profileState); err != nil {
+			p.LogError("failed to update call job", "err", err.Error(), "jobID", profileState.ID, "callID", callID)
+		}
+
+		if profileState.EndAt == 0 {
+			if err := p.saveRecordingMetadata(state.Call.PostID, profileState.ID, ""); err != nil {
+				p.LogError("failed to save recording metadata", "err", err.Error())
+			}
+		}
+	}
+
 	if jobErr != nil {
 		recState.EndAt = time.Now().UnixMilli()
 		recState.Props.Err = jobErr.Error()
@@ -152,10 +316,21 @@ func (p *Plugin) startRecordingJob(state *callState, callID, userID string) (rst
 	p.LogDebug("recording job started successfully", "jobID", recJobID, "callID", callID)
 
 	var trID string
-	if cfg := p.getConfiguration(); cfg.transcriptionsEnabled() {
+	if cfg.transcriptionsEnabled() {
 		trID = model.NewId()
+		// The recording doesn't wait on the transcription: if too many are
+		// running already, the transcription starts once a slot is available.
+		if ok, err := p.acquireTranscriptionSlot(callID, recState.ID, trID); err != nil {
+			p.LogError("failed to acquire transcription slot", "callID", callID, "err", err.Error())
+		} else if !ok {
+			p.LogInfo("max concurrent transcription jobs reached, queuing transcription", "callID", callID, "recID", recState.ID)
+			trID = ""
+		}
+	}
+	if trID != "" {
 		p.LogDebug("transcriptions enabled, starting job", "callID", callID)
 		if err := p.startTranscribingJob(state, callID, userID, trID); err != nil {
+			p.releaseTranscriptionSlot(trID)
 			p.LogError("failed to start transcribing job", "callID", callID, "err", err.Error())
 			return nil, http.StatusInternalServerError, fmt.Errorf("failed to start transcribing job: %w", err)
 		}
@@ -179,6 +354,32 @@ func (p *Plugin) startRecordingJob(state *callState, callID, userID string) (rst
 	return getClientStateFromCallJob(recState), http.StatusOK, nil
 }
 
+// stopProfileRecordingJobs stops the additional recording jobs tied to the
+// main recording, finalizing each of them.
+func (p *Plugin) stopProfileRecordingJobs(state *callState, callID string) {
+	for _, profileJob := range state.ProfileRecordings {
+		if profileJob.EndAt != 0 {
+			continue
+		}
+
+		profileJob.EndAt = time.Now().UnixMilli()
+		if profileJob.Props.PausedAt != 0 {
+			profileJob.Props.Pauses = append(profileJob.Props.Pauses, public.CallJobPause{
+				StartAt: profileJob.Props.PausedAt,
+				EndAt:   profileJob.EndAt,
+			})
+			profileJob.Props.PausedAt = 0
+		}
+		if err := p.store.UpdateCallJob(profileJob); err != nil {
+			p.LogError("failed to update call job", "err", err.Error(), "jobID", profileJob.ID, "callID", callID)
+		}
+
+		if err := p.getJobService().StopJob(callID, profileJob.ID, p.getBotID(), profileJob.Props.BotConnID); err != nil {
+			p.LogError("failed to stop profile recording job", "err", err.Error(), "jobID", profileJob.ID, "callID", callID)
+		}
+	}
+}
+
 func (p *Plugin) stopRecordingJob(state *callState, callID string) (rst *JobStateClient, rcode int, rerr error) {
 	if state.Recording == nil || state.Recording.EndAt != 0 {
 		return nil, http.StatusForbidden, fmt.Errorf("no recording in progress")
@@ -189,6 +390,14 @@ func (p *Plugin) stopRecordingJob(state *callState, callID string) (rst *JobStat
 		return nil, http.StatusInternalServerError, fmt.Errorf("failed to get recording state: %w", err)
 	}
 	recState.EndAt = time.Now().UnixMilli()
+	// Stopping a paused recording closes the ongoing pause interval.
+	if recState.Props.PausedAt != 0 {
+		recState.Props.Pauses = append(recState.Props.Pauses, public.CallJobPause{
+			StartAt: recState.Props.PausedAt,
+			EndAt:   recState.EndAt,
+		})
+		recState.Props.PausedAt = 0
+	}
 	if err := p.store.UpdateCallJob(recState); err != nil {
 		return nil, http.StatusInternalServerError, fmt.Errorf("failed to update call job: %w", err)
 	}
@@ -214,6 +423,8 @@ func (p *Plugin) stopRecordingJob(state *callState, callID string) (rst *JobStat
 		}
 	}
 
+	p.stopProfileRecordingJobs(state, callID)
+
 	if err := p.getJobService().StopJob(callID, recState.ID, p.getBotID(), recState.Props.BotConnID); err != nil {
 		return nil, http.StatusInternalServerError, fmt.Errorf("failed to stop recording job: %w", err)
 	}
@@ -230,6 +441,154 @@ func (p *Plugin) stopRecordingJob(state *callState, callID string) (rst *JobStat
 	return getClientStateFromCallJob(recState), http.StatusOK, nil
 }
 
+// pauseRecordingJob temporarily stops capturing for the ongoing recording
+// (and transcription, if any). The recorder keeps the same output file so that
+// on resume the segments get stitched together.
+// cancelRecordingJob aborts the ongoing recording without ending the call.
+// Unlike a normal stop, the recording is marked as canceled and, unless
+// keepFile is set, its output is discarded rather than posted.
+func (p *Plugin) cancelRecordingJob(state *callState, callID string, keepFile bool) (*JobStateClient, int, error) {
+	if state.Recording == nil || state.Recording.EndAt != 0 {
+		return nil, http.StatusForbidden, fmt.Errorf("no recording in progress")
+	}
+
+	// Props need to be set before stopping so that they are saved along with
+	// the end time.
+	jobs := append([]*public.CallJob{state.Recording}, state.ProfileRecordings...)
+	if state.Transcription != nil && state.Transcription.EndAt == 0 {
+		jobs = append(jobs, state.Transcription)
+	}
+	for _, jb := range jobs {
+		if jb.EndAt != 0 {
+			continue
+		}
+		jb.Props.EndReason = public.CallJobEndReasonCanceled
+		jb.Props.DiscardOutput = !keepFile
+	}
+
+	return p.stopRecordingJob(state, callID)
+}
+
+// pauseRecordingJob temporarily stops capturing for the ongoing recording.
+// The recording view blanks the video and silences the audio until resumed so
+// the recorder keeps writing to the same output file. Transcriptions can't be
+// paused so pausing is refused while one is in progress, as the transcript
+// would otherwise include what was said during the pause.
+func (p *Plugin) pauseRecordingJob(state *callState, callID string) (*JobStateClient, int, error) {
+	if state.Recording == nil || state.Recording.EndAt != 0 {
+		return nil, http.StatusForbidden, fmt.Errorf("no recording in progress")
+	}
+
+	if state.Transcription != nil && state.Transcription.EndAt == 0 {
+		return nil, http.StatusForbidden, fmt.Errorf("recording cannot be paused while transcribing")
+	}
+
+	recState, err := state.getRecording()
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to get recording state: %w", err)
+	}
+
+	if recState.StartAt == 0 {
+		return nil, http.StatusForbidden, fmt.Errorf("recording has not started yet")
+	}
+
+	if recState.Props.PausedAt != 0 {
+		return nil, http.StatusForbidden, fmt.Errorf("recording is already paused")
+	}
+
+	recState.Props.PausedAt = time.Now().UnixMilli()
+	if err := p.store.UpdateCallJob(recState); err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to update call job: %w", err)
+	}
+
+	if err := p.getJobService().PauseJob(recState.ID, p.getBotID()); err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to pause recording job: %w", err)
+	}
+
+	for _, profileJob := range state.ProfileRecordings {
+		if profileJob.EndAt != 0 || profileJob.Props.PausedAt != 0 {
+			continue
+		}
+		profileJob.Props.PausedAt = recState.Props.PausedAt
+		if err := p.store.UpdateCallJob(profileJob); err != nil {
+			p.LogError("failed to update call job", "err", err.Error(), "jobID", profileJob.ID, "callID", callID)
+		}
+		if err := p.getJobService().PauseJob(profileJob.ID, p.getBotID()); err != nil {
+			p.LogError("failed to pause profile recording job", "err", err.Error(), "jobID", profileJob.ID, "callID", callID)
+		}
+	}
+
+	p.publishWebSocketEvent(wsEventCallJobState, map[string]interface{}{
+		"callID":   callID,
+		"jobState": getClientStateFromCallJob(recState).toMap(),
+	}, &WebSocketBroadcast{
+		ChannelID:           callID,
+		ReliableClusterSend: true,
+		UserIDs:             getUserIDsFromSessions(state.sessions),
+	})
+
+	return getClientStateFromCallJob(recState), http.StatusOK, nil
+}
+
+// resumeRecordingJob resumes capturing for a paused recording. The paused
+// interval is saved in the job's props so that it can be marked in the
+// resulting recording post.
+func (p *Plugin) resumeRecordingJob(state *callState, callID string) (*JobStateClient, int, error) {
+	if state.Recording == nil || state.Recording.EndAt != 0 {
+		return nil, http.StatusForbidden, fmt.Errorf("no recording in progress")
+	}
+
+	recState, err := state.getRecording()
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to get recording state: %w", err)
+	}
+
+	if recState.Props.PausedAt == 0 {
+		return nil, http.StatusForbidden, fmt.Errorf("recording is not paused")
+	}
+
+	recState.Props.Pauses = append(recState.Props.Pauses, public.CallJobPause{
+		StartAt: recState.Props.PausedAt,
+		EndAt:   time.Now().UnixMilli(),
+	})
+	recState.Props.PausedAt = 0
+	if err := p.store.UpdateCallJob(recState); err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to update call job: %w", err)
+	}
+
+	if err := p.getJobService().ResumeJob(recState.ID, p.getBotID()); err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to resume recording job: %w", err)
+	}
+
+	for _, profileJob := range state.ProfileRecordings {
+		if profileJob.EndAt != 0 || profileJob.Props.PausedAt == 0 {
+			continue
+		}
+		profileJob.Props.Pauses = append(profileJob.Props.Pauses, public.CallJobPause{
+			StartAt: profileJob.Props.PausedAt,
+			EndAt:   time.Now().UnixMilli(),
+		})
+		profileJob.Props.PausedAt = 0
+		if err := p.store.UpdateCallJob(profileJob); err != nil {
+			p.LogError("failed to update call job", "err", err.Error(), "jobID", profileJob.ID, "callID", callID)
+		}
+		if err := p.getJobService().ResumeJob(profileJob.ID, p.getBotID()); err != nil {
+			p.LogError("failed to resume profile recording job", "err", err.Error(), "jobID", profileJob.ID, "callID", callID)
+		}
+	}
+
+	p.publishWebSocketEvent(wsEventCallJobState, map[string]interface{}{
+		"callID":   callID,
+		"jobState": getClientStateFromCallJob(recState).toMap(),
+	}, &WebSocketBroadcast{
+		ChannelID:           callID,
+		ReliableClusterSend: true,
+		UserIDs:             getUserIDsFromSessions(state.sessions),
+	})
+
+	return getClientStateFromCallJob(recState), http.StatusOK, nil
+}
+
 func (p *Plugin) handleRecordingAction(w http.ResponseWriter, r *http.Request) {
 	var res httpResponse
 	defer p.httpAudit("handleRecordingAction", &res, w, r)
@@ -285,9 +644,20 @@ func (p *Plugin) handleRecordingAction(w http.ResponseWriter, r *http.Request) {
 	var recState *JobStateClient
 	switch action {
 	case "start":
-		recState, code, err = p.startRecordingJob(state, callID, userID)
+		// The request body is optional.
+		var startReq recordingStartRequest
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, requestBodyMaxSizeBytes)).Decode(&startReq); err != nil && !errors.Is(err, io.EOF) {
+			res.Err = "failed to decode request body: " + err.Error()
+			res.Code = http.StatusBadRequest
+			return
+		}
+		recState, code, err = p.startRecordingJob(state, callID, userID, startReq)
 	case "stop":
 		recState, code, err = p.stopRecordingJob(state, callID)
+	case "pause":
+		recState, code, err = p.pauseRecordingJob(state, callID)
+	case "resume":
+		recState, code, err = p.resumeRecordingJob(state, callID)
 	default:
 		res.Err = "unsupported recording action"
 		res.Code = http.StatusBadRequest
@@ -305,3 +675,218 @@ func (p *Plugin) handleRecordingAction(w http.ResponseWriter, r *http.Request) {
 		p.LogError(err.Error())
 	}
 }
+
+// handleGetRecordingFile serves the recording file attached to a recording post.
+// Once access is validated, the client is redirected to the server's files API
+// which streams the content straight from the configured storage backend and
+// handles Range requests, so that large recordings never need to be loaded in
+// the plugin's memory.
+func (p *Plugin) handleGetRecordingFile(w http.ResponseWriter, r *http.Request) {
+	var res httpResponse
+	defer p.httpAudit("handleGetRecordingFile", &res, w, r)
+
+	userID := r.Header.Get("Mattermost-User-Id")
+	callID := mux.Vars(r)["call_id"]
+	fileID := mux.Vars(r)["file_id"]
+
+	if !p.API.HasPermissionToChannel(userID, callID, model.PermissionReadChannel) {
+		res.Err = "Forbidden"
+		res.Code = http.StatusForbidden
+		return
+	}
+
+	if _, _, code, err := p.getRecordingFileInfo(callID, fileID); err != nil {
+		res.Err = err.Error()
+		res.Code = code
+		return
+	}
+
+	var siteURL string
+	if cfg := p.API.GetConfig(); cfg != nil && cfg.ServiceSettings.SiteURL != nil {
+		siteURL = strings.TrimRight(*cfg.ServiceSettings.SiteURL, "/")
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("%s/api/v4/files/%s?download=1", siteURL, fileID), http.StatusFound)
+}
+
+// getRecordingFileInfo returns the file info and post of the given recording
+// file, making sure it belongs to a recording posted in the channel.
+func (p *Plugin) getRecordingFileInfo(channelID, fileID string) (*model.FileInfo, *model.Post, int, error) {
+	fi, appErr := p.API.GetFileInfo(fileID)
+	if appErr != nil {
+		return nil, nil, appErr.StatusCode, fmt.Errorf("failed to get file info: %s", appErr.Error())
+	}
+
+	if fi.ChannelId != channelID || fi.PostId == "" {
+		return nil, nil, http.StatusNotFound, fmt.Errorf("not found")
+	}
+
+	post, appErr := p.API.GetPost(fi.PostId)
+	if appErr != nil {
+		return nil, nil, appErr.StatusCode, fmt.Errorf("failed to get post: %s", appErr.Error())
+	}
+
+	if post.Type != callRecordingPostType {
+		return nil, nil, http.StatusNotFound, fmt.Errorf("not found")
+	}
+
+	return fi, post, http.StatusOK, nil
+}
+
+type recordingListEntry struct {
+	CallID      string `json:"call_id"`
+	RecordingID string `json:"recording_id"`
+	StartAt     int64  `json:"start_at"`
+	FileID      string `json:"file_id"`
+	PostID      string `json:"post_id"`
+}
+
+// handleCancelRecording aborts a recording started by mistake. The call keeps
+// going and, unless keep_file=true is passed, the partial recording is not
+// posted.
+func (p *Plugin) handleCancelRecording(w http.ResponseWriter, r *http.Request) {
+	var res httpResponse
+	defer p.httpAudit("handleCancelRecording", &res, w, r)
+
+	userID := r.Header.Get("Mattermost-User-Id")
+	jobID := mux.Vars(r)["job_id"]
+	keepFile, _ := strconv.ParseBool(r.URL.Query().Get("keep_file"))
+
+	if p.getJobService() == nil {
+		res.Err = "Job service is not initialized"
+		res.Code = http.StatusForbidden
+		return
+	}
+
+	recJob, err := p.store.GetCallJob(jobID, db.GetCallJobOpts{IncludeEnded: true})
+	if errors.Is(err, db.ErrNotFound) || (err == nil && (recJob.Type != public.JobTypeRecording || recJob.Props.PrimaryJobID != "")) {
+		res.Err = "recording not found"
+		res.Code = http.StatusNotFound
+		return
+	} else if err != nil {
+		res.Err = "failed to get recording job: " + err.Error()
+		res.Code = http.StatusInternalServerError
+		return
+	}
+
+	call, err := p.store.GetCall(recJob.CallID, db.GetCallOpts{})
+	if err != nil {
+		res.Err = "failed to get call: " + err.Error()
+		res.Code = http.StatusInternalServerError
+		return
+	}
+	channelID := call.ChannelID
+
+	if !p.API.HasPermissionToChannel(userID, channelID, model.PermissionReadChannel) {
+		res.Err = "Forbidden"
+		res.Code = http.StatusForbidden
+		return
+	}
+
+	state, err := p.lockCallReturnState(channelID)
+	if err != nil {
+		res.Err = fmt.Errorf("failed to lock call: %w", err).Error()
+		res.Code = http.StatusInternalServerError
+		return
+	}
+	defer p.unlockCall(channelID)
+
+	if state == nil || state.Recording == nil || state.Recording.ID != jobID || state.Recording.EndAt != 0 {
+		res.Err = "recording is not in progress"
+		res.Code = http.StatusBadRequest
+		return
+	}
+
+	if state.Call.GetHostID() != userID && !p.API.HasPermissionTo(userID, model.PermissionManageSystem) {
+		res.Err = "no permissions to cancel recording"
+		res.Code = http.StatusForbidden
+		return
+	}
+
+	recState, code, err := p.cancelRecordingJob(state, channelID, keepFile)
+	if err != nil {
+		res.Err = err.Error()
+		res.Code = code
+		return
+	}
+
+	p.LogInfo("recording canceled", "callID", state.Call.ID, "jobID", jobID, "userID", userID, "keepFile", keepFile)
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(recState); err != nil {
+		p.LogError(err.Error())
+	}
+}
+
+// handleGetRecordings returns the recordings of the past calls in the
+// channel, most recent first. Pagination applies to calls.
+func (p *Plugin) handleGetRecordings(w http.ResponseWriter, r *http.Request) {
+	var res httpResponse
+	defer p.httpAudit("handleGetRecordings", &res, w, r)
+
+	userID := r.Header.Get("Mattermost-User-Id")
+	callID := mux.Vars(r)["call_id"]
+
+	if !p.API.HasPermissionToChannel(userID, callID, model.PermissionReadChannel) {
+		res.Err = "Forbidden"
+		res.Code = http.StatusForbidden
+		return
+	}
+
+	opts, err := parseCallsHistoryOpts(r)
+	if err != nil {
+		res.Err = err.Error()
+		res.Code = http.StatusBadRequest
+		return
+	}
+	opts.ChannelID = callID
+
+	calls, err := p.store.GetCallsHistory(opts)
+	if err != nil {
+		res.Err = "failed to get calls: " + err.Error()
+		res.Code = http.StatusInternalServerError
+		return
+	}
+
+	entries := []recordingListEntry{}
+	for _, call := range calls {
+		if call.PostID == "" {
+			continue
+		}
+
+		post, err := p.store.GetPost(call.PostID)
+		if err != nil {
+			p.LogWarn("failed to get call post", "callID", call.ID, "err", err.Error())
+			continue
+		}
+
+		recordings, _ := post.GetProp("recordings").(map[string]any)
+		recIDs := make([]string, 0, len(recordings))
+		for recID := range recordings {
+			recIDs = append(recIDs, recID)
+		}
+		sort.Strings(recIDs)
+
+		for _, recID := range recIDs {
+			var rm jobMetadata
+			rm.fromMap(recordings[recID])
+			// Recordings that haven't been finalized yet have no file.
+			if rm.FileID == "" {
+				continue
+			}
+
+			entries = append(entries, recordingListEntry{
+				CallID:      call.ID,
+				RecordingID: recID,
+				StartAt:     call.StartAt,
+				FileID:      rm.FileID,
+				PostID:      rm.PostID,
+			})
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(entries); err != nil {
+		p.LogError(err.Error())
+	}
+}
@@ -0,0 +1,277 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin"
+
+	"github.com/mattermost/mattermost-plugin-calls/server/enterprise"
+	serverMocks "github.com/mattermost/mattermost-plugin-calls/server/mocks/github.com/mattermost/mattermost-plugin-calls/server/interfaces"
+	pluginMocks "github.com/mattermost/mattermost-plugin-calls/server/mocks/github.com/mattermost/mattermost/server/public/plugin"
+	"github.com/mattermost/mattermost-plugin-calls/server/public"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+)
+
+func TestHandleGetRecordingFile(t *testing.T) {
+	mockAPI := &pluginMocks.MockAPI{}
+	mockMetrics := &serverMocks.MockMetrics{}
+
+	p := Plugin{
+		MattermostPlugin: plugin.MattermostPlugin{
+			API: mockAPI,
+		},
+		metrics:     mockMetrics,
+		apiLimiters: map[string]*rate.Limiter{},
+	}
+
+	mockMetrics.On("Handler").Return(nil).Once()
+	apiRouter := p.newAPIRouter()
+
+	userID := model.NewId()
+	channelID := model.NewId()
+	fileID := model.NewId()
+	postID := model.NewId()
+
+	// httpAudit logs the handler name followed by the origin, request fields
+	// and result (two extra fields in case of failure).
+	for _, n := range []int{16, 18} {
+		logArgs := []any{"handleGetRecordingFile"}
+		for i := 0; i < n; i++ {
+			logArgs = append(logArgs, mock.Anything)
+		}
+		mockAPI.On("LogDebug", logArgs...)
+	}
+
+	mockAPI.On("HasPermissionToChannel", userID, channelID, model.PermissionReadChannel).Return(true)
+	mockAPI.On("GetFileInfo", fileID).Return(&model.FileInfo{
+		Id:        fileID,
+		ChannelId: channelID,
+		PostId:    postID,
+		Name:      "recording.mp4",
+		MimeType:  "video/mp4",
+	}, nil)
+	mockAPI.On("GetPost", postID).Return(&model.Post{
+		Id:        postID,
+		ChannelId: channelID,
+		Type:      callRecordingPostType,
+	}, nil)
+	siteURL := "http://localhost:8065/"
+	mockAPI.On("GetConfig").Return(&model.Config{
+		ServiceSettings: model.ServiceSettings{
+			SiteURL: &siteURL,
+		},
+	})
+
+	t.Run("redirect", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", fmt.Sprintf("/calls/%s/recordings/%s", channelID, fileID), nil)
+		r.Header.Set("Mattermost-User-Id", userID)
+
+		apiRouter.ServeHTTP(w, r)
+
+		resp := w.Result()
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusFound, resp.StatusCode)
+		require.Equal(t, fmt.Sprintf("http://localhost:8065/api/v4/files/%s?download=1", fileID), resp.Header.Get("Location"))
+	})
+
+	t.Run("not a recording", func(t *testing.T) {
+		otherFileID := model.NewId()
+		otherPostID := model.NewId()
+		mockAPI.On("GetFileInfo", otherFileID).Return(&model.FileInfo{
+			Id:        otherFileID,
+			ChannelId: channelID,
+			PostId:    otherPostID,
+		}, nil)
+		mockAPI.On("GetPost", otherPostID).Return(&model.Post{
+			Id:        otherPostID,
+			ChannelId: channelID,
+		}, nil)
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", fmt.Sprintf("/calls/%s/recordings/%s", channelID, otherFileID), nil)
+		r.Header.Set("Mattermost-User-Id", userID)
+
+		apiRouter.ServeHTTP(w, r)
+
+		resp := w.Result()
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("no permissions", func(t *testing.T) {
+		otherUserID := model.NewId()
+		mockAPI.On("HasPermissionToChannel", otherUserID, channelID, model.PermissionReadChannel).Return(false)
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", fmt.Sprintf("/calls/%s/recordings/%s", channelID, fileID), nil)
+		r.Header.Set("Mattermost-User-Id", otherUserID)
+
+		apiRouter.ServeHTTP(w, r)
+
+		resp := w.Result()
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+}
+
+func TestRenderRecordingWatermark(t *testing.T) {
+	ts := time.Date(2024, time.March, 5, 16, 30, 0, 0, time.FixedZone("CET", 3600))
+
+	require.Equal(t, "CONFIDENTIAL - Town Square - 2024-03-05 15:30 UTC",
+		renderRecordingWatermark("CONFIDENTIAL - {channel_name} - {date} {time}", "Town Square", ts))
+	require.Equal(t, "CONFIDENTIAL", renderRecordingWatermark("CONFIDENTIAL", "Town Square", ts))
+}
+
+func TestGetRecordingWatermarkText(t *testing.T) {
+	mockAPI := &pluginMocks.MockAPI{}
+
+	p := Plugin{
+		MattermostPlugin: plugin.MattermostPlugin{
+			API: mockAPI,
+		},
+		configuration: &configuration{
+			RecordingWatermarkTemplate: "CONFIDENTIAL - {channel_name}",
+		},
+	}
+	p.licenseChecker = enterprise.NewLicenseChecker(p.API)
+
+	channelID := model.NewId()
+
+	t.Run("not licensed", func(t *testing.T) {
+		defer mockAPI.AssertExpectations(t)
+
+		mockAPI.On("GetConfig").Return(&model.Config{}, nil).Once()
+		mockAPI.On("GetLicense").Return(&model.License{
+			SkuShortName: "professional",
+		}, nil).Once()
+
+		text, err := p.getRecordingWatermarkText(channelID)
+		require.NoError(t, err)
+		require.Empty(t, text)
+	})
+
+	t.Run("licensed", func(t *testing.T) {
+		defer mockAPI.AssertExpectations(t)
+
+		mockAPI.On("GetConfig").Return(&model.Config{}, nil).Once()
+		mockAPI.On("GetLicense").Return(&model.License{
+			SkuShortName: "enterprise",
+		}, nil).Once()
+		mockAPI.On("GetChannel", channelID).Return(&model.Channel{
+			Id:          channelID,
+			Name:        "town-square",
+			DisplayName: "Town Square",
+		}, nil).Once()
+
+		text, err := p.getRecordingWatermarkText(channelID)
+		require.NoError(t, err)
+		require.Equal(t, "CONFIDENTIAL - Town Square", text)
+	})
+
+	t.Run("empty template", func(t *testing.T) {
+		defer mockAPI.AssertExpectations(t)
+
+		p.configuration = &configuration{}
+
+		text, err := p.getRecordingWatermarkText(channelID)
+		require.NoError(t, err)
+		require.Empty(t, text)
+	})
+}
+
+func TestPauseResumeRecordingJob(t *testing.T) {
+	p := &Plugin{}
+
+	newState := func(rec *public.CallJob) *callState {
+		return &callState{
+			Call: public.Call{
+				ID: model.NewId(),
+			},
+			Recording: rec,
+		}
+	}
+
+	t.Run("no recording", func(t *testing.T) {
+		_, code, err := p.pauseRecordingJob(newState(nil), "channelID")
+		require.EqualError(t, err, "no recording in progress")
+		require.Equal(t, http.StatusForbidden, code)
+
+		_, code, err = p.resumeRecordingJob(newState(nil), "channelID")
+		require.EqualError(t, err, "no recording in progress")
+		require.Equal(t, http.StatusForbidden, code)
+	})
+
+	t.Run("recording ended", func(t *testing.T) {
+		rec := &public.CallJob{InitAt: 100, StartAt: 200, EndAt: 300}
+		_, code, err := p.pauseRecordingJob(newState(rec), "channelID")
+		require.EqualError(t, err, "no recording in progress")
+		require.Equal(t, http.StatusForbidden, code)
+	})
+
+	t.Run("recording not started", func(t *testing.T) {
+		rec := &public.CallJob{InitAt: 100}
+		_, code, err := p.pauseRecordingJob(newState(rec), "channelID")
+		require.EqualError(t, err, "recording has not started yet")
+		require.Equal(t, http.StatusForbidden, code)
+	})
+
+	t.Run("transcribing", func(t *testing.T) {
+		rec := &public.CallJob{InitAt: 100, StartAt: 200}
+		state := newState(rec)
+		state.Transcription = &public.CallJob{InitAt: 100, StartAt: 200}
+		_, code, err := p.pauseRecordingJob(state, "channelID")
+		require.EqualError(t, err, "recording cannot be paused while transcribing")
+		require.Equal(t, http.StatusForbidden, code)
+	})
+
+	t.Run("already paused", func(t *testing.T) {
+		rec := &public.CallJob{InitAt: 100, StartAt: 200, Props: public.CallJobProps{PausedAt: 250}}
+		_, code, err := p.pauseRecordingJob(newState(rec), "channelID")
+		require.EqualError(t, err, "recording is already paused")
+		require.Equal(t, http.StatusForbidden, code)
+	})
+
+	t.Run("not paused", func(t *testing.T) {
+		rec := &public.CallJob{InitAt: 100, StartAt: 200}
+		_, code, err := p.resumeRecordingJob(newState(rec), "channelID")
+		require.EqualError(t, err, "recording is not paused")
+		require.Equal(t, http.StatusForbidden, code)
+	})
+}
+
+func TestCancelRecordingJob(t *testing.T) {
+	p := &Plugin{}
+
+	t.Run("no recording", func(t *testing.T) {
+		_, code, err := p.cancelRecordingJob(&callState{}, "channelID", false)
+		require.EqualError(t, err, "no recording in progress")
+		require.Equal(t, http.StatusForbidden, code)
+	})
+
+	t.Run("recording ended", func(t *testing.T) {
+		rec := &public.CallJob{InitAt: 100, StartAt: 200, EndAt: 300}
+		_, code, err := p.cancelRecordingJob(&callState{Recording: rec}, "channelID", false)
+		require.EqualError(t, err, "no recording in progress")
+		require.Equal(t, http.StatusForbidden, code)
+		require.Empty(t, rec.Props.EndReason)
+	})
+
+	t.Run("end reason is relayed to clients", func(t *testing.T) {
+		rec := &public.CallJob{InitAt: 100, StartAt: 200, EndAt: 300, Props: public.CallJobProps{
+			EndReason: public.CallJobEndReasonCanceled,
+		}}
+		require.Equal(t, "canceled", getClientStateFromCallJob(rec).toMap()["end_reason"])
+	})
+}
@@ -0,0 +1,198 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/mattermost/mattermost-plugin-calls/server/public"
+)
+
+const (
+	maxRecordingMarkerNameLen = 100
+	maxRecordingMarkers       = 100
+)
+
+type recordingMarkerData struct {
+	Name string `json:"name"`
+}
+
+// recordingChapter is a section of a recording, starting at the given offset
+// (in milliseconds) from the beginning of the recorded output.
+type recordingChapter struct {
+	Name        string `json:"name"`
+	StartOffset int64  `json:"start_offset"`
+}
+
+// parseRecordingMarkerName validates the name of a recording marker and
+// returns it sanitized.
+func parseRecordingMarkerName(name string) (string, error) {
+	name = strings.TrimSpace(strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, name))
+
+	if name == "" {
+		return "", fmt.Errorf("marker name should not be empty")
+	}
+	if utf8.RuneCountInString(name) > maxRecordingMarkerNameLen {
+		return "", fmt.Errorf("marker name is too long: should be at most %d characters", maxRecordingMarkerNameLen)
+	}
+
+	return name, nil
+}
+
+// addRecordingMarker saves a named marker at the current point of the ongoing
+// recording. Markers are turned into chapters once the recording is
+// finalized. The caller is expected to hold the call lock.
+func (p *Plugin) addRecordingMarker(state *callState, userID, name string) error {
+	if state.Call.GetHostID() != userID {
+		return fmt.Errorf("no permissions to add a recording marker")
+	}
+
+	if state.Recording == nil || state.Recording.EndAt != 0 {
+		return fmt.Errorf("no recording in progress")
+	}
+	recState := state.Recording
+
+	if recState.StartAt == 0 {
+		return fmt.Errorf("recording has not started yet")
+	}
+
+	if recState.Props.PausedAt != 0 {
+		return fmt.Errorf("recording is paused")
+	}
+
+	name, err := parseRecordingMarkerName(name)
+	if err != nil {
+		return err
+	}
+
+	if len(recState.Props.Markers) >= maxRecordingMarkers {
+		return fmt.Errorf("too many markers: should be at most %d", maxRecordingMarkers)
+	}
+
+	marker := public.CallJobMarker{
+		Name:     name,
+		CreateAt: time.Now().UnixMilli(),
+	}
+
+	recState.Props.Markers = append(recState.Props.Markers, marker)
+	if err := p.store.UpdateCallJob(recState); err != nil {
+		return fmt.Errorf("failed to update call job: %w", err)
+	}
+
+	for _, profileJob := range state.ProfileRecordings {
+		if profileJob.EndAt != 0 {
+			continue
+		}
+		profileJob.Props.Markers = append(profileJob.Props.Markers, marker)
+		if err := p.store.UpdateCallJob(profileJob); err != nil {
+			p.LogError("failed to update call job", "err", err.Error(), "jobID", profileJob.ID, "callID", state.Call.ChannelID)
+		}
+	}
+
+	p.LogDebug("recording marker added", "callID", state.Call.ID, "jobID", recState.ID, "userID", userID)
+
+	return nil
+}
+
+func (p *Plugin) handleRecordingMarkerMessage(us *session, msg clientMessage) error {
+	var data recordingMarkerData
+	if err := json.Unmarshal(msg.Data, &data); err != nil {
+		return fmt.Errorf("failed to unmarshal recording marker data: %w", err)
+	}
+
+	channelID := us.getChannelID()
+	state, err := p.lockCallReturnState(channelID)
+	if err != nil {
+		return fmt.Errorf("failed to lock call: %w", err)
+	}
+	defer p.unlockCall(channelID)
+
+	if state == nil || state.Call.ID != us.callID {
+		return fmt.Errorf("no call ongoing")
+	}
+
+	return p.addRecordingMarker(state, us.userID, data.Name)
+}
+
+// getRecordingChapters converts the markers of a recording job into chapters.
+// Offsets are relative to the recorded output, meaning any time the recording
+// spent paused before a marker is not accounted for.
+func getRecordingChapters(job *public.CallJob) []recordingChapter {
+	if job == nil || job.StartAt == 0 || len(job.Props.Markers) == 0 {
+		return nil
+	}
+
+	chapters := make([]recordingChapter, 0, len(job.Props.Markers))
+	for _, marker := range job.Props.Markers {
+		chapters = append(chapters, recordingChapter{
+			Name:        marker.Name,
+			StartOffset: getRecordingOffset(job, marker.CreateAt),
+		})
+	}
+
+	return chapters
+}
+
+// getRecordingOffset returns the offset (in milliseconds) in the recorded
+// output matching the given point in time.
+func getRecordingOffset(job *public.CallJob, at int64) int64 {
+	offset := at - job.StartAt
+	for _, pause := range job.Props.Pauses {
+		if pause.StartAt >= at {
+			continue
+		}
+		offset -= min(pause.EndAt, at) - pause.StartAt
+	}
+	return max(offset, 0)
+}
+
+func formatVTTTimestamp(ms int64) string {
+	d := time.Duration(ms) * time.Millisecond
+	return fmt.Sprintf("%02d:%02d:%02d.%03d",
+		int64(d.Hours()), int64(d.Minutes())%60, int64(d.Seconds())%60, ms%1000)
+}
+
+// generateChaptersVTT returns a WebVTT chapters file for the given recording
+// job, or nil if the recording has no markers. Each chapter ends where the
+// following one starts, with the last one lasting until the end of the
+// recording.
+func generateChaptersVTT(job *public.CallJob) []byte {
+	chapters := getRecordingChapters(job)
+	if len(chapters) == 0 {
+		return nil
+	}
+
+	endAt := job.EndAt
+	if endAt == 0 {
+		endAt = time.Now().UnixMilli()
+	}
+	duration := getRecordingOffset(job, endAt)
+
+	var buf bytes.Buffer
+	buf.WriteString("WEBVTT\n")
+	for i, chapter := range chapters {
+		end := duration
+		if i < len(chapters)-1 {
+			end = chapters[i+1].StartOffset
+		}
+		end = max(end, chapter.StartOffset)
+		// The cue arrow is not allowed in the cue text.
+		name := strings.ReplaceAll(chapter.Name, "-->", "->")
+		fmt.Fprintf(&buf, "\n%d\n%s --> %s\n%s\n", i+1,
+			formatVTTTimestamp(chapter.StartOffset), formatVTTTimestamp(end), name)
+	}
+
+	return buf.Bytes()
+}
@@ -0,0 +1,134 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mattermost/mattermost-plugin-calls/server/public"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRecordingMarkerName(t *testing.T) {
+	name, err := parseRecordingMarkerName(" Q&A starts\n")
+	require.NoError(t, err)
+	require.Equal(t, "Q&A starts", name)
+
+	_, err = parseRecordingMarkerName(" \x00 ")
+	require.EqualError(t, err, "marker name should not be empty")
+
+	_, err = parseRecordingMarkerName(strings.Repeat("a", maxRecordingMarkerNameLen+1))
+	require.EqualError(t, err, "marker name is too long: should be at most 100 characters")
+
+	name, err = parseRecordingMarkerName(strings.Repeat("é", maxRecordingMarkerNameLen))
+	require.NoError(t, err)
+	require.Equal(t, strings.Repeat("é", maxRecordingMarkerNameLen), name)
+}
+
+func TestAddRecordingMarker(t *testing.T) {
+	p := &Plugin{}
+
+	hostID := model.NewId()
+
+	newState := func(rec *public.CallJob) *callState {
+		return &callState{
+			Call: public.Call{
+				ID: model.NewId(),
+				Props: public.CallProps{
+					Hosts: []string{hostID},
+				},
+			},
+			Recording: rec,
+		}
+	}
+
+	t.Run("not host", func(t *testing.T) {
+		rec := &public.CallJob{InitAt: 100, StartAt: 200}
+		err := p.addRecordingMarker(newState(rec), model.NewId(), "Q&A")
+		require.EqualError(t, err, "no permissions to add a recording marker")
+	})
+
+	t.Run("no recording", func(t *testing.T) {
+		err := p.addRecordingMarker(newState(nil), hostID, "Q&A")
+		require.EqualError(t, err, "no recording in progress")
+
+		rec := &public.CallJob{InitAt: 100, StartAt: 200, EndAt: 300}
+		err = p.addRecordingMarker(newState(rec), hostID, "Q&A")
+		require.EqualError(t, err, "no recording in progress")
+	})
+
+	t.Run("recording not started", func(t *testing.T) {
+		rec := &public.CallJob{InitAt: 100}
+		err := p.addRecordingMarker(newState(rec), hostID, "Q&A")
+		require.EqualError(t, err, "recording has not started yet")
+	})
+
+	t.Run("recording paused", func(t *testing.T) {
+		rec := &public.CallJob{InitAt: 100, StartAt: 200, Props: public.CallJobProps{PausedAt: 250}}
+		err := p.addRecordingMarker(newState(rec), hostID, "Q&A")
+		require.EqualError(t, err, "recording is paused")
+	})
+
+	t.Run("invalid name", func(t *testing.T) {
+		rec := &public.CallJob{InitAt: 100, StartAt: 200}
+		err := p.addRecordingMarker(newState(rec), hostID, "")
+		require.EqualError(t, err, "marker name should not be empty")
+	})
+
+	t.Run("too many markers", func(t *testing.T) {
+		rec := &public.CallJob{InitAt: 100, StartAt: 200}
+		rec.Props.Markers = make([]public.CallJobMarker, maxRecordingMarkers)
+		err := p.addRecordingMarker(newState(rec), hostID, "Q&A")
+		require.EqualError(t, err, "too many markers: should be at most 100")
+	})
+}
+
+func TestGetRecordingChapters(t *testing.T) {
+	t.Run("no markers", func(t *testing.T) {
+		require.Nil(t, getRecordingChapters(&public.CallJob{StartAt: 1000}))
+		require.Nil(t, generateChaptersVTT(&public.CallJob{StartAt: 1000}))
+	})
+
+	t.Run("pauses are skipped", func(t *testing.T) {
+		job := &public.CallJob{
+			StartAt: 1000,
+			EndAt:   3_700_000,
+			Props: public.CallJobProps{
+				Pauses: []public.CallJobPause{
+					{StartAt: 10_000, EndAt: 20_000},
+				},
+				Markers: []public.CallJobMarker{
+					{Name: "Intro", CreateAt: 1000},
+					{Name: "Demo", CreateAt: 5000},
+					{Name: "Q&A --> wrap up", CreateAt: 80_000},
+				},
+			},
+		}
+
+		require.Equal(t, []recordingChapter{
+			{Name: "Intro", StartOffset: 0},
+			{Name: "Demo", StartOffset: 4000},
+			{Name: "Q&A --> wrap up", StartOffset: 69_000},
+		}, getRecordingChapters(job))
+
+		require.Equal(t, `WEBVTT
+
+1
+00:00:00.000 --> 00:00:04.000
+Intro
+
+2
+00:00:04.000 --> 00:01:09.000
+Demo
+
+3
+00:01:09.000 --> 01:01:29.000
+Q&A -> wrap up
+`, string(generateChaptersVTT(job)))
+	})
+}
@@ -0,0 +1,131 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mattermost/mattermost-plugin-calls/server/db"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/i18n"
+)
+
+// shouldNotifyParticipantsOfRecordings returns whether the participants of
+// calls in the given channel should be notified when a recording is
+// available. The channel setting, if any, takes precedence over the global
+// one.
+func (p *Plugin) shouldNotifyParticipantsOfRecordings(channelID string) bool {
+	callsChannel, err := p.store.GetCallsChannel(channelID, db.GetCallsChannelOpts{})
+	if err != nil && !errors.Is(err, db.ErrNotFound) {
+		p.LogError("failed to get calls channel", "err", err.Error(), "channelID", channelID)
+	}
+	if callsChannel != nil {
+		if notify, ok := callsChannel.Props["recording_notifications"].(bool); ok {
+			return notify
+		}
+	}
+	cfg := p.getConfiguration()
+	return cfg.NotifyParticipantsOfRecordings != nil && *cfg.NotifyParticipantsOfRecordings
+}
+
+// getTranscriptionPostID returns the ID of the post holding the transcription
+// of the given recording, if it's already available.
+func getTranscriptionPostID(callPost *model.Post, recID string) string {
+	recordings, _ := callPost.GetProp("recordings").(map[string]any)
+	var rm jobMetadata
+	rm.fromMap(recordings[recID])
+	if rm.TrID == "" {
+		return ""
+	}
+
+	transcriptions, _ := callPost.GetProp("transcriptions").(map[string]any)
+	var tm jobMetadata
+	tm.fromMap(transcriptions[rm.TrID])
+	return tm.PostID
+}
+
+// newRecordingNotificationMessage returns the message sent to participants
+// when the recording of a call is available. The transcription link is only
+// included if given.
+func newRecordingNotificationMessage(T i18n.TranslateFunc, title, recLink, trLink string) string {
+	msg := T("app.call.recording_available_message", map[string]any{"Link": recLink})
+	if title != "" {
+		msg = T("app.call.recording_available_message_title", map[string]any{"Title": title, "Link": recLink})
+	}
+
+	if trLink != "" {
+		msg += "\n" + T("app.call.recording_available_transcription", map[string]any{"Link": trLink})
+	}
+
+	return msg
+}
+
+func (p *Plugin) getPermalink(postID string) string {
+	var siteURL string
+	if cfg := p.API.GetConfig(); cfg != nil && cfg.ServiceSettings.SiteURL != nil {
+		siteURL = strings.TrimRight(*cfg.ServiceSettings.SiteURL, "/")
+	}
+	return fmt.Sprintf("%s/_redirect/pl/%s", siteURL, postID)
+}
+
+// sendRecordingNotifications lets the participants of the given call know,
+// through a direct message from the bot, that its recording is available.
+// Participants who opted out through their preferences are skipped.
+func (p *Plugin) sendRecordingNotifications(callID, recPostID, trPostID string) {
+	call, err := p.store.GetCall(callID, db.GetCallOpts{})
+	if err != nil {
+		p.LogError("failed to get call", "err", err.Error(), "callID", callID)
+		return
+	}
+
+	recLink := p.getPermalink(recPostID)
+	var trLink string
+	if trPostID != "" {
+		trLink = p.getPermalink(trPostID)
+	}
+
+	botID := p.getBotID()
+
+	for _, userID := range call.Participants {
+		if userID == botID {
+			continue
+		}
+
+		user, appErr := p.API.GetUser(userID)
+		if appErr != nil {
+			p.LogError("failed to get user", "err", appErr.Error(), "userID", userID)
+			continue
+		}
+		if user.IsBot {
+			continue
+		}
+
+		prefs, err := p.getCallNotificationPreferences(userID)
+		if err != nil {
+			p.LogError("failed to get call notification preferences", "err", err.Error(), "userID", userID)
+		}
+		if prefs.MuteRecordingNotifications {
+			continue
+		}
+
+		dm, appErr := p.API.GetDirectChannel(userID, botID)
+		if appErr != nil {
+			p.LogError("failed to get dm between user and bot", "err", appErr.Error(), "userID", userID, "botID", botID)
+			continue
+		}
+
+		post := &model.Post{
+			UserId:    botID,
+			ChannelId: dm.Id,
+			Message:   newRecordingNotificationMessage(p.getTranslationFunc(user.Locale), call.Title, recLink, trLink),
+		}
+		post.AddProp("recording_post_id", recPostID)
+		if _, appErr := p.API.CreatePost(post); appErr != nil {
+			p.LogError("failed to create post", "err", appErr.Error(), "userID", userID)
+		}
+	}
+}
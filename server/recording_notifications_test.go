@@ -0,0 +1,33 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetTranscriptionPostID(t *testing.T) {
+	post := &model.Post{}
+	require.Empty(t, getTranscriptionPostID(post, "recID"))
+
+	post.AddProp("recordings", map[string]any{
+		"recID": map[string]any{"file_id": "fileID", "tr_id": "trID"},
+	})
+	require.Empty(t, getTranscriptionPostID(post, "recID"))
+
+	post.AddProp("transcriptions", map[string]any{
+		"trID": map[string]any{"rec_id": "recID"},
+	})
+	require.Empty(t, getTranscriptionPostID(post, "recID"))
+
+	post.AddProp("transcriptions", map[string]any{
+		"trID": map[string]any{"rec_id": "recID", "post_id": "trPostID"},
+	})
+	require.Equal(t, "trPostID", getTranscriptionPostID(post, "recID"))
+	require.Empty(t, getTranscriptionPostID(post, "otherRecID"))
+}
@@ -0,0 +1,111 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-calls/server/public"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/gorilla/mux"
+)
+
+// recordingProgress is the progress of an ongoing recording job.
+type recordingProgress struct {
+	JobID   string `json:"job_id"`
+	StartAt int64  `json:"start_at"`
+	Paused  bool   `json:"paused"`
+	// The amount of time (in milliseconds) recorded so far, not accounting for
+	// pauses.
+	RecordedMs int64 `json:"recorded_ms"`
+	// The amount of time (in milliseconds) the job has been running for.
+	ElapsedMs int64 `json:"elapsed_ms"`
+	// The maximum amount of time (in milliseconds) the job is allowed to run
+	// for. Zero if unlimited.
+	MaxDurationMs int64 `json:"max_duration_ms,omitempty"`
+	// The share of MaxDurationMs elapsed so far, in the [0, 100] range.
+	Percentage float64 `json:"percentage,omitempty"`
+	// The size (in bytes) of the recording file so far, estimated from the
+	// configured bitrates.
+	EstimatedSizeBytes int64 `json:"estimated_size_bytes"`
+}
+
+// getRecordingProgress returns the progress of the given recording job at
+// the given point in time. The job service caps jobs by wall-clock time so
+// the percentage accounts for pauses while the estimated size doesn't.
+func getRecordingProgress(job *public.CallJob, cfg *configuration, now time.Time) recordingProgress {
+	progress := recordingProgress{
+		JobID:   job.ID,
+		StartAt: job.StartAt,
+		Paused:  job.Props.PausedAt != 0,
+	}
+
+	if cfg.MaxRecordingDuration != nil && *cfg.MaxRecordingDuration > 0 {
+		progress.MaxDurationMs = (time.Duration(*cfg.MaxRecordingDuration) * time.Minute).Milliseconds()
+	}
+
+	// The job hasn't started capturing yet.
+	if job.StartAt == 0 {
+		return progress
+	}
+
+	nowMs := now.UnixMilli()
+	progress.ElapsedMs = max(nowMs-job.StartAt, 0)
+	progress.RecordedMs = getRecordingOffset(job, nowMs)
+	if job.Props.PausedAt != 0 {
+		progress.RecordedMs = max(progress.RecordedMs-(nowMs-job.Props.PausedAt), 0)
+	}
+
+	if progress.MaxDurationMs > 0 {
+		progress.Percentage = min(float64(progress.ElapsedMs)/float64(progress.MaxDurationMs)*100, 100)
+	}
+
+	// Rates are in Kbps.
+	recCfg := getRecorderConfig(cfg)
+	if job.Props.Profile != "" {
+		recCfg = getRecorderConfigForQuality(cfg, job.Props.Profile)
+	}
+	bytesPerSec := int64(recCfg.VideoRate+recCfg.AudioRate) * 1000 / 8
+	progress.EstimatedSizeBytes = bytesPerSec * progress.RecordedMs / 1000
+
+	return progress
+}
+
+func (p *Plugin) handleGetRecordingProgress(w http.ResponseWriter, r *http.Request) {
+	var res httpResponse
+	defer p.httpAudit("handleGetRecordingProgress", &res, w, r)
+
+	userID := r.Header.Get("Mattermost-User-Id")
+	channelID := mux.Vars(r)["call_id"]
+
+	if !p.API.HasPermissionToChannel(userID, channelID, model.PermissionReadChannel) {
+		res.Err = "Forbidden"
+		res.Code = http.StatusForbidden
+		return
+	}
+
+	state, err := p.getCallState(channelID, false)
+	if err != nil {
+		res.Err = fmt.Errorf("failed to get call state: %w", err).Error()
+		res.Code = http.StatusInternalServerError
+		return
+	}
+
+	recState, err := state.getRecording()
+	if err != nil || recState.EndAt != 0 {
+		res.Err = "no recording ongoing"
+		res.Code = http.StatusNotFound
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(getRecordingProgress(recState, p.getConfiguration(), time.Now())); err != nil {
+		p.LogError(err.Error())
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-calls/server/public"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetRecordingProgress(t *testing.T) {
+	var cfg configuration
+	cfg.SetDefaults()
+	cfg.MaxRecordingDuration = model.NewPointer(10)
+
+	now := time.UnixMilli(1_000_000_000)
+
+	t.Run("not started", func(t *testing.T) {
+		job := &public.CallJob{ID: model.NewId()}
+		require.Equal(t, recordingProgress{
+			JobID:         job.ID,
+			MaxDurationMs: 600_000,
+		}, getRecordingProgress(job, &cfg, now))
+	})
+
+	t.Run("running", func(t *testing.T) {
+		job := &public.CallJob{
+			ID:      model.NewId(),
+			StartAt: now.Add(-time.Minute).UnixMilli(),
+		}
+		progress := getRecordingProgress(job, &cfg, now)
+		require.Equal(t, int64(60_000), progress.ElapsedMs)
+		require.Equal(t, int64(60_000), progress.RecordedMs)
+		require.Equal(t, 10.0, progress.Percentage)
+		// medium quality: (1500 + 64) Kbps over a minute.
+		require.Equal(t, int64(11_730_000), progress.EstimatedSizeBytes)
+		require.False(t, progress.Paused)
+	})
+
+	t.Run("paused", func(t *testing.T) {
+		job := &public.CallJob{
+			ID:      model.NewId(),
+			StartAt: now.Add(-4 * time.Minute).UnixMilli(),
+			Props: public.CallJobProps{
+				Pauses: []public.CallJobPause{
+					{
+						StartAt: now.Add(-3 * time.Minute).UnixMilli(),
+						EndAt:   now.Add(-2 * time.Minute).UnixMilli(),
+					},
+				},
+				PausedAt: now.Add(-time.Minute).UnixMilli(),
+			},
+		}
+		progress := getRecordingProgress(job, &cfg, now)
+		require.Equal(t, int64(240_000), progress.ElapsedMs)
+		require.Equal(t, int64(120_000), progress.RecordedMs)
+		require.Equal(t, 40.0, progress.Percentage)
+		require.True(t, progress.Paused)
+	})
+
+	t.Run("past max duration", func(t *testing.T) {
+		job := &public.CallJob{
+			ID:      model.NewId(),
+			StartAt: now.Add(-11 * time.Minute).UnixMilli(),
+		}
+		require.Equal(t, 100.0, getRecordingProgress(job, &cfg, now).Percentage)
+	})
+
+	t.Run("profile", func(t *testing.T) {
+		job := &public.CallJob{
+			ID:      model.NewId(),
+			StartAt: now.Add(-time.Minute).UnixMilli(),
+			Props: public.CallJobProps{
+				Profile: "high",
+			},
+		}
+		// high quality: (2500 + 64) Kbps over a minute.
+		require.Equal(t, int64(19_230_000), getRecordingProgress(job, &cfg, now).EstimatedSizeBytes)
+	})
+}
@@ -0,0 +1,174 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+const (
+	recordingPseudonymsKeyPrefix     = "rec_pseudonyms_"
+	recordingPseudonymIDKeyPrefix    = "rec_pseudonym_id_"
+	recordingPseudonymsUpdateRetries = 5
+)
+
+// recordingPseudonym is the identity a participant is shown with in
+// anonymized recordings and transcripts.
+type recordingPseudonym struct {
+	// ID replaces the user ID so that jobs cannot resolve the real profile.
+	ID string `json:"id"`
+	// Index is the order in which the participant was first seen, starting
+	// at 1.
+	Index int `json:"index"`
+}
+
+// recordingPseudonymEntry maps a pseudonym back to the participant, as
+// returned to system admins.
+type recordingPseudonymEntry struct {
+	UserID      string `json:"user_id"`
+	PseudonymID string `json:"pseudonym_id"`
+	Pseudonym   string `json:"pseudonym"`
+}
+
+func (ps recordingPseudonym) name() string {
+	return "Participant " + strconv.Itoa(ps.Index)
+}
+
+// toUser returns the profile shown in place of the participant's.
+func (ps recordingPseudonym) toUser() *model.User {
+	return &model.User{
+		Id:        ps.ID,
+		Username:  "participant" + strconv.Itoa(ps.Index),
+		FirstName: "Participant",
+		LastName:  strconv.Itoa(ps.Index),
+	}
+}
+
+// callJobsAnonymized returns whether the ongoing jobs of the call should not
+// expose participants' identities.
+func callJobsAnonymized(state *callState) bool {
+	if state.Recording != nil && state.Recording.EndAt == 0 && state.Recording.Props.Anonymized {
+		return true
+	}
+	return state.Transcription != nil && state.Transcription.EndAt == 0 && state.Transcription.Props.Anonymized
+}
+
+func (p *Plugin) getRecordingPseudonyms(callID string) (map[string]recordingPseudonym, []byte, error) {
+	data, appErr := p.API.KVGet(recordingPseudonymsKeyPrefix + callID)
+	if appErr != nil {
+		return nil, nil, fmt.Errorf("failed to get recording pseudonyms: %w", appErr)
+	}
+
+	pseudonyms := map[string]recordingPseudonym{}
+	if data != nil {
+		if err := json.Unmarshal(data, &pseudonyms); err != nil {
+			return nil, nil, fmt.Errorf("failed to unmarshal recording pseudonyms: %w", err)
+		}
+	}
+
+	return pseudonyms, data, nil
+}
+
+// getRecordingPseudonym returns the pseudonym of the given participant,
+// assigning the next one available if they don't have one yet. Pseudonyms are
+// kept for the whole call so that participants are consistently labeled
+// across recordings and transcripts.
+func (p *Plugin) getRecordingPseudonym(callID, userID string) (recordingPseudonym, error) {
+	for i := 0; i < recordingPseudonymsUpdateRetries; i++ {
+		pseudonyms, oldData, err := p.getRecordingPseudonyms(callID)
+		if err != nil {
+			return recordingPseudonym{}, err
+		}
+
+		if ps, ok := pseudonyms[userID]; ok {
+			return ps, nil
+		}
+
+		ps := recordingPseudonym{
+			ID:    model.NewId(),
+			Index: len(pseudonyms) + 1,
+		}
+		pseudonyms[userID] = ps
+
+		data, err := json.Marshal(pseudonyms)
+		if err != nil {
+			return recordingPseudonym{}, fmt.Errorf("failed to marshal recording pseudonyms: %w", err)
+		}
+
+		ok, appErr := p.API.KVSetWithOptions(recordingPseudonymsKeyPrefix+callID, data, model.PluginKVSetOptions{
+			Atomic:   true,
+			OldValue: oldData,
+		})
+		if appErr != nil {
+			return recordingPseudonym{}, fmt.Errorf("failed to set recording pseudonyms: %w", appErr)
+		}
+		if !ok {
+			continue
+		}
+
+		// Tracked separately so that avatars can be anonymized too, as jobs
+		// fetch them by ID only.
+		if appErr := p.API.KVSet(recordingPseudonymIDKeyPrefix+ps.ID, []byte(callID)); appErr != nil {
+			return recordingPseudonym{}, fmt.Errorf("failed to set recording pseudonym ID: %w", appErr)
+		}
+
+		return ps, nil
+	}
+
+	return recordingPseudonym{}, fmt.Errorf("failed to get recording pseudonym: too many concurrent updates")
+}
+
+func (p *Plugin) isRecordingPseudonymID(id string) (bool, error) {
+	data, appErr := p.API.KVGet(recordingPseudonymIDKeyPrefix + id)
+	if appErr != nil {
+		return false, fmt.Errorf("failed to get recording pseudonym ID: %w", appErr)
+	}
+	return data != nil, nil
+}
+
+func (p *Plugin) handleGetRecordingPseudonyms(w http.ResponseWriter, r *http.Request) {
+	var res httpResponse
+	defer p.httpAudit("handleGetRecordingPseudonyms", &res, w, r)
+
+	userID := r.Header.Get("Mattermost-User-Id")
+	callID := mux.Vars(r)["call_id"]
+
+	if !p.API.HasPermissionTo(userID, model.PermissionManageSystem) {
+		res.Err = "Forbidden"
+		res.Code = http.StatusForbidden
+		return
+	}
+
+	pseudonyms, _, err := p.getRecordingPseudonyms(callID)
+	if err != nil {
+		res.Err = err.Error()
+		res.Code = http.StatusInternalServerError
+		return
+	}
+
+	entries := make([]recordingPseudonymEntry, 0, len(pseudonyms))
+	for uid, ps := range pseudonyms {
+		entries = append(entries, recordingPseudonymEntry{
+			UserID:      uid,
+			PseudonymID: ps.ID,
+			Pseudonym:   ps.name(),
+		})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return pseudonyms[entries[i].UserID].Index < pseudonyms[entries[j].UserID].Index
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(entries); err != nil {
+		p.LogError(err.Error())
+	}
+}
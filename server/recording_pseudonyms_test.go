@@ -0,0 +1,156 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mattermost/mattermost-plugin-calls/server/public"
+
+	pluginMocks "github.com/mattermost/mattermost-plugin-calls/server/mocks/github.com/mattermost/mattermost/server/public/plugin"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"golang.org/x/time/rate"
+)
+
+func TestCallJobsAnonymized(t *testing.T) {
+	state := &callState{}
+	require.False(t, callJobsAnonymized(state))
+
+	state.Recording = &public.CallJob{}
+	require.False(t, callJobsAnonymized(state))
+
+	state.Recording.Props.Anonymized = true
+	require.True(t, callJobsAnonymized(state))
+
+	// Ended jobs no longer apply.
+	state.Recording.EndAt = 100
+	require.False(t, callJobsAnonymized(state))
+
+	state.Transcription = &public.CallJob{Props: public.CallJobProps{Anonymized: true}}
+	require.True(t, callJobsAnonymized(state))
+}
+
+func TestGetRecordingPseudonym(t *testing.T) {
+	mockAPI := &pluginMocks.MockAPI{}
+	defer mockAPI.AssertExpectations(t)
+
+	p := &Plugin{
+		MattermostPlugin: plugin.MattermostPlugin{
+			API: mockAPI,
+		},
+	}
+
+	callID := model.NewId()
+	userA := model.NewId()
+	userB := model.NewId()
+	key := recordingPseudonymsKeyPrefix + callID
+
+	existing, err := json.Marshal(map[string]recordingPseudonym{
+		userA: {ID: "pseudonymA", Index: 1},
+	})
+	require.NoError(t, err)
+
+	t.Run("existing", func(t *testing.T) {
+		mockAPI.On("KVGet", key).Return(existing, nil).Once()
+
+		ps, err := p.getRecordingPseudonym(callID, userA)
+		require.NoError(t, err)
+		require.Equal(t, recordingPseudonym{ID: "pseudonymA", Index: 1}, ps)
+	})
+
+	t.Run("assigned after concurrent update", func(t *testing.T) {
+		mockAPI.On("KVGet", key).Return(nil, nil).Once()
+		mockAPI.On("KVSetWithOptions", key, mock.Anything, model.PluginKVSetOptions{Atomic: true}).
+			Return(false, nil).Once()
+		mockAPI.On("KVGet", key).Return(existing, nil).Once()
+
+		var assigned recordingPseudonym
+		mockAPI.On("KVSetWithOptions", key, mock.MatchedBy(func(data []byte) bool {
+			var pseudonyms map[string]recordingPseudonym
+			require.NoError(t, json.Unmarshal(data, &pseudonyms))
+			assigned = pseudonyms[userB]
+			return len(pseudonyms) == 2 && assigned.Index == 2
+		}), model.PluginKVSetOptions{Atomic: true, OldValue: existing}).Return(true, nil).Once()
+		mockAPI.On("KVSet", mock.AnythingOfType("string"), []byte(callID)).Return(nil).Once()
+
+		ps, err := p.getRecordingPseudonym(callID, userB)
+		require.NoError(t, err)
+		require.Equal(t, assigned, ps)
+		require.NotEqual(t, userB, ps.ID)
+
+		user := ps.toUser()
+		require.Equal(t, ps.ID, user.Id)
+		require.Equal(t, "participant2", user.Username)
+		require.Equal(t, "Participant 2", user.GetFullName())
+		require.Equal(t, "Participant 2", ps.name())
+	})
+}
+
+func TestHandleGetRecordingPseudonyms(t *testing.T) {
+	mockAPI := &pluginMocks.MockAPI{}
+	defer mockAPI.AssertExpectations(t)
+
+	p := Plugin{
+		MattermostPlugin: plugin.MattermostPlugin{
+			API: mockAPI,
+		},
+		apiLimiters: map[string]*rate.Limiter{},
+	}
+
+	apiRouter := p.newAPIRouter()
+
+	logArgs := []any{"handleGetRecordingPseudonyms"}
+	for i := 0; i < 18; i++ {
+		logArgs = append(logArgs, mock.Anything)
+	}
+	mockAPI.On("LogDebug", logArgs...)
+
+	userID := model.NewId()
+	callID := model.NewId()
+
+	get := func() *http.Response {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/calls/history/"+callID+"/pseudonyms", nil)
+		r.Header.Set("Mattermost-User-Id", userID)
+		apiRouter.ServeHTTP(w, r)
+		return w.Result()
+	}
+
+	t.Run("requires system admin", func(t *testing.T) {
+		mockAPI.On("HasPermissionTo", userID, model.PermissionManageSystem).Return(false).Once()
+		require.Equal(t, http.StatusForbidden, get().StatusCode)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		userA := model.NewId()
+		userB := model.NewId()
+		data, err := json.Marshal(map[string]recordingPseudonym{
+			userB: {ID: "pseudonymB", Index: 2},
+			userA: {ID: "pseudonymA", Index: 1},
+		})
+		require.NoError(t, err)
+
+		mockAPI.On("HasPermissionTo", userID, model.PermissionManageSystem).Return(true).Once()
+		mockAPI.On("KVGet", recordingPseudonymsKeyPrefix+callID).Return(data, nil).Once()
+
+		resp := get()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var entries []recordingPseudonymEntry
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&entries))
+		require.Equal(t, []recordingPseudonymEntry{
+			{UserID: userA, PseudonymID: "pseudonymA", Pseudonym: "Participant 1"},
+			{UserID: userB, PseudonymID: "pseudonymB", Pseudonym: "Participant 2"},
+		}, entries)
+	})
+}
@@ -0,0 +1,79 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+var (
+	// recordingsFinalizeTimeout is how long deactivating waits for recording
+	// outputs being finalized before stopping the plugin.
+	recordingsFinalizeTimeout = 30 * time.Second
+	// recordingsAbortTimeout is how long deactivating waits, once stopping,
+	// for interrupted finalizations to spool their data.
+	recordingsAbortTimeout         = 5 * time.Second
+	recordingsFinalizePollInterval = 100 * time.Millisecond
+)
+
+// startRecordingFinalization marks a recording output as being finalized
+// until the returned function is called.
+func (p *Plugin) startRecordingFinalization() func() {
+	p.recordingFinalizations.Add(1)
+	return func() {
+		p.recordingFinalizations.Add(-1)
+	}
+}
+
+// waitForRecordingFinalizations waits for the recording outputs being
+// finalized to be done, returning false if they weren't in the given time.
+func (p *Plugin) waitForRecordingFinalizations(timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for p.recordingFinalizations.Load() > 0 {
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(recordingsFinalizePollInterval)
+	}
+	return true
+}
+
+// stopRecordingFinalizations gives the recording outputs being finalized a
+// bounded window to complete before signaling the plugin is stopping, which
+// makes any pending upload spool its data for recovery on next activation.
+func (p *Plugin) stopRecordingFinalizations() {
+	if !p.waitForRecordingFinalizations(recordingsFinalizeTimeout) {
+		p.LogWarn("recordings are still being finalized, interrupting",
+			"count", fmt.Sprintf("%d", p.recordingFinalizations.Load()))
+	}
+
+	close(p.stopCh)
+
+	if !p.waitForRecordingFinalizations(recordingsAbortTimeout) {
+		p.LogError("failed to wait for interrupted recordings",
+			"count", fmt.Sprintf("%d", p.recordingFinalizations.Load()))
+	}
+}
+
+// recoverRecordingUploads retries the recording uploads that were interrupted
+// by the plugin stopping on this node.
+func (p *Plugin) recoverRecordingUploads() {
+	uploads, _, err := p.getRecordingUploads()
+	if err != nil {
+		p.LogError("failed to get recording uploads", "err", err.Error())
+		return
+	}
+
+	for _, upload := range uploads {
+		if upload.Status != recordingUploadStatusInterrupted || upload.NodeID != p.nodeID {
+			continue
+		}
+
+		p.LogInfo("recovering interrupted recording upload", "uploadID", upload.ID)
+		if _, err := p.retryRecordingUpload(upload); err != nil {
+			p.LogError("failed to recover interrupted recording upload", "err", err.Error(), "uploadID", upload.ID)
+		}
+	}
+}
@@ -0,0 +1,235 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/mattermost/mattermost-plugin-calls/server/db"
+)
+
+const (
+	recordingStorageScopeChannel = "channel"
+	recordingStorageScopeTeam    = "team"
+
+	// The maximum number of recordings deleted in a single pass when
+	// enforcing a quota.
+	maxRecordingStorageQuotaDeletions = 100
+)
+
+// recordingStorageQuotaError is returned when a recording can't be started
+// because a storage quota has been exceeded.
+type recordingStorageQuotaError struct {
+	scope string
+	used  int64
+	quota int64
+}
+
+func (e *recordingStorageQuotaError) Error() string {
+	return fmt.Sprintf("recording storage quota exceeded for this %s: %d MB used out of %d MB allowed, delete some recordings to start new ones",
+		e.scope, bytesToMB(e.used), bytesToMB(e.quota))
+}
+
+// recordingStorageUsage is the storage used by the recordings in a channel or
+// team, along with the configured quota (zero meaning no limit).
+type recordingStorageUsage struct {
+	Scope      string `json:"scope"`
+	ID         string `json:"id"`
+	UsedBytes  int64  `json:"used_bytes"`
+	QuotaBytes int64  `json:"quota_bytes"`
+}
+
+func (u recordingStorageUsage) exceeded() bool {
+	return u.QuotaBytes > 0 && u.UsedBytes >= u.QuotaBytes
+}
+
+func bytesToMB(bytes int64) int64 {
+	return bytes / (1024 * 1024)
+}
+
+// getRecordingStorageUsages returns the storage usage of the recordings for
+// every quota that applies to the given channel.
+func (p *Plugin) getRecordingStorageUsages(channelID string) ([]recordingStorageUsage, error) {
+	channelQuota, teamQuota := p.getConfiguration().getRecordingStorageQuotas()
+
+	var usages []recordingStorageUsage
+	if channelQuota > 0 {
+		used, err := p.store.GetRecordingsStorageSize(db.RecordingsStorageOpts{ChannelID: channelID})
+		if err != nil {
+			return nil, err
+		}
+		usages = append(usages, recordingStorageUsage{
+			Scope:      recordingStorageScopeChannel,
+			ID:         channelID,
+			UsedBytes:  used,
+			QuotaBytes: channelQuota,
+		})
+	}
+
+	if teamQuota > 0 {
+		channel, appErr := p.API.GetChannel(channelID)
+		if appErr != nil {
+			return nil, fmt.Errorf("failed to get channel: %w", appErr)
+		}
+		// Direct and group messages don't belong to any team.
+		if channel.TeamId != "" {
+			used, err := p.store.GetRecordingsStorageSize(db.RecordingsStorageOpts{TeamID: channel.TeamId})
+			if err != nil {
+				return nil, err
+			}
+			usages = append(usages, recordingStorageUsage{
+				Scope:      recordingStorageScopeTeam,
+				ID:         channel.TeamId,
+				UsedBytes:  used,
+				QuotaBytes: teamQuota,
+			})
+		}
+	}
+
+	return usages, nil
+}
+
+// checkRecordingStorageQuota returns an error if a new recording can't be
+// started in the given channel because a storage quota has been exceeded.
+// Quotas only block recordings when the policy is set to do so.
+func (p *Plugin) checkRecordingStorageQuota(channelID string) error {
+	policy := p.getConfiguration().RecordingStorageQuotaPolicy
+	if policy != recordingStorageQuotaPolicyBlock {
+		return nil
+	}
+
+	usages, err := p.getRecordingStorageUsages(channelID)
+	if err != nil {
+		return fmt.Errorf("failed to get recording storage usage: %w", err)
+	}
+
+	for _, usage := range usages {
+		if usage.exceeded() {
+			p.metrics.IncRecordingStorageQuotaExceeded(usage.Scope, policy)
+			return &recordingStorageQuotaError{
+				scope: usage.Scope,
+				used:  usage.UsedBytes,
+				quota: usage.QuotaBytes,
+			}
+		}
+	}
+
+	return nil
+}
+
+// enforceRecordingStorageQuota deletes the oldest recordings in the given
+// channel, or in its team, until their total size fits within the configured
+// quotas. The recording attached to the given post is never deleted.
+func (p *Plugin) enforceRecordingStorageQuota(channelID, excludePostID string) {
+	defer p.updateRecordingStorageMetric()
+
+	policy := p.getConfiguration().RecordingStorageQuotaPolicy
+	if policy != recordingStorageQuotaPolicyDeleteOldest {
+		return
+	}
+
+	usages, err := p.getRecordingStorageUsages(channelID)
+	if err != nil {
+		p.LogError("failed to get recording storage usage", "err", err.Error(), "channelID", channelID)
+		return
+	}
+
+	for _, usage := range usages {
+		if !usage.exceeded() {
+			continue
+		}
+
+		p.metrics.IncRecordingStorageQuotaExceeded(usage.Scope, policy)
+
+		opts := db.RecordingsStorageOpts{Limit: maxRecordingStorageQuotaDeletions}
+		if usage.Scope == recordingStorageScopeChannel {
+			opts.ChannelID = usage.ID
+		} else {
+			opts.TeamID = usage.ID
+		}
+
+		recordings, err := p.store.GetOldestRecordings(opts)
+		if err != nil {
+			p.LogError("failed to get oldest recordings", "err", err.Error(), "scope", usage.Scope, "id", usage.ID)
+			continue
+		}
+
+		for _, rec := range getRecordingsToDelete(recordings, usage.UsedBytes-usage.QuotaBytes, excludePostID) {
+			if appErr := p.API.DeletePost(rec.PostID); appErr != nil {
+				p.LogError("failed to delete recording post", "err", appErr.Error(), "postID", rec.PostID)
+				continue
+			}
+			p.LogInfo("recording deleted to fit storage quota", "postID", rec.PostID, "fileID", rec.FileID,
+				"channelID", rec.ChannelID, "scope", usage.Scope, "id", usage.ID)
+		}
+	}
+}
+
+// getRecordingsToDelete returns the oldest recordings to delete so that more
+// than the given amount of bytes is freed.
+func getRecordingsToDelete(recordings []db.RecordingFile, toFree int64, excludePostID string) []db.RecordingFile {
+	var toDelete []db.RecordingFile
+	for _, rec := range recordings {
+		if toFree < 0 {
+			break
+		}
+		if rec.PostID == excludePostID {
+			continue
+		}
+		toDelete = append(toDelete, rec)
+		toFree -= rec.Size
+	}
+	return toDelete
+}
+
+// updateRecordingStorageMetric refreshes the metric tracking the total size
+// of the stored recordings.
+func (p *Plugin) updateRecordingStorageMetric() {
+	size, err := p.store.GetRecordingsStorageSize(db.RecordingsStorageOpts{})
+	if err != nil {
+		p.LogError("failed to get recordings storage size", "err", err.Error())
+		return
+	}
+	p.metrics.SetRecordingStorageBytes(size)
+}
+
+// handleGetRecordingsStorage returns the storage used by the call recordings
+// in a channel or team, along with the configured quota.
+func (p *Plugin) handleGetRecordingsStorage(w http.ResponseWriter, r *http.Request) error {
+	channelID := r.URL.Query().Get("channel_id")
+	teamID := r.URL.Query().Get("team_id")
+	if (channelID == "") == (teamID == "") {
+		http.Error(w, "either channel_id or team_id should be provided", http.StatusBadRequest)
+		return nil
+	}
+
+	channelQuota, teamQuota := p.getConfiguration().getRecordingStorageQuotas()
+	usage := recordingStorageUsage{
+		Scope:      recordingStorageScopeChannel,
+		ID:         channelID,
+		QuotaBytes: channelQuota,
+	}
+	opts := db.RecordingsStorageOpts{ChannelID: channelID}
+	if teamID != "" {
+		usage.Scope = recordingStorageScopeTeam
+		usage.ID = teamID
+		usage.QuotaBytes = teamQuota
+		opts = db.RecordingsStorageOpts{TeamID: teamID}
+	}
+
+	used, err := p.store.GetRecordingsStorageSize(opts)
+	if err != nil {
+		return fmt.Errorf("failed to get recordings storage size: %w", err)
+	}
+	usage.UsedBytes = used
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(usage); err != nil {
+		return fmt.Errorf("error encoding recordings storage: %w", err)
+	}
+
+	return nil
+}
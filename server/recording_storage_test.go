@@ -0,0 +1,68 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost-plugin-calls/server/db"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetRecordingsToDelete(t *testing.T) {
+	recordings := []db.RecordingFile{
+		{FileID: "fileA", PostID: "postA", Size: 100},
+		{FileID: "fileB", PostID: "postB", Size: 200},
+		{FileID: "fileC", PostID: "postC", Size: 300},
+	}
+
+	getIDs := func(recordings []db.RecordingFile) []string {
+		var ids []string
+		for _, rec := range recordings {
+			ids = append(ids, rec.FileID)
+		}
+		return ids
+	}
+
+	t.Run("oldest first", func(t *testing.T) {
+		require.Equal(t, []string{"fileA"}, getIDs(getRecordingsToDelete(recordings, 50, "")))
+		require.Equal(t, []string{"fileA", "fileB"}, getIDs(getRecordingsToDelete(recordings, 100, "")))
+		require.Equal(t, []string{"fileA", "fileB", "fileC"}, getIDs(getRecordingsToDelete(recordings, 1000, "")))
+	})
+
+	t.Run("excluded post", func(t *testing.T) {
+		require.Equal(t, []string{"fileB"}, getIDs(getRecordingsToDelete(recordings, 50, "postA")))
+	})
+
+	t.Run("nothing to free", func(t *testing.T) {
+		require.Empty(t, getRecordingsToDelete(recordings, -1, ""))
+	})
+}
+
+func TestGetRecordingStorageQuotas(t *testing.T) {
+	var cfg configuration
+	cfg.SetDefaults()
+
+	channelQuota, teamQuota := cfg.getRecordingStorageQuotas()
+	require.Zero(t, channelQuota)
+	require.Zero(t, teamQuota)
+
+	cfg.MaxRecordingStoragePerChannelMB = model.NewPointer(10)
+	cfg.MaxRecordingStoragePerTeamMB = model.NewPointer(1024)
+	channelQuota, teamQuota = cfg.getRecordingStorageQuotas()
+	require.Equal(t, int64(10*1024*1024), channelQuota)
+	require.Equal(t, int64(1024*1024*1024), teamQuota)
+}
+
+func TestRecordingStorageQuotaError(t *testing.T) {
+	err := &recordingStorageQuotaError{
+		scope: recordingStorageScopeChannel,
+		used:  150 * 1024 * 1024,
+		quota: 100 * 1024 * 1024,
+	}
+	require.EqualError(t, err, "recording storage quota exceeded for this channel: 150 MB used out of 100 MB allowed, delete some recordings to start new ones")
+}
@@ -0,0 +1,132 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+// matchRecordingKeyword returns the first of the given (lower cased) keywords
+// found as a whole word in message, or an empty string if none matches.
+func matchRecordingKeyword(message string, keywords []string) string {
+	if len(keywords) == 0 {
+		return ""
+	}
+
+	for _, field := range strings.Fields(strings.ToLower(message)) {
+		// Trailing punctuation (e.g. "/sev1!") shouldn't prevent a match
+		// while leading characters such as "/" or "#" can be part of the keyword.
+		field = strings.TrimRightFunc(field, unicode.IsPunct)
+		if slices.Contains(keywords, field) {
+			return field
+		}
+	}
+
+	return ""
+}
+
+// isAutomatedPost returns whether the post was made on behalf of an
+// integration, such as a bot or an incoming webhook.
+func isAutomatedPost(post *model.Post) bool {
+	return post.GetProp(model.PostPropsFromBot) == "true" || post.GetProp(model.PostPropsFromWebhook) == "true"
+}
+
+// handleRecordingKeywordPost starts a recording of the ongoing call when a
+// configured keyword is posted in one of the channels keyword triggers apply
+// to.
+func (p *Plugin) handleRecordingKeywordPost(post *model.Post) {
+	// Integrations are not allowed to start recordings.
+	if post.UserId == p.getBotID() || post.IsSystemMessage() || isAutomatedPost(post) {
+		return
+	}
+
+	cfg := p.getConfiguration()
+	if !slices.Contains(cfg.getRecordingKeywordTriggerChannels(), post.ChannelId) {
+		return
+	}
+
+	keyword := matchRecordingKeyword(post.Message, cfg.getRecordingKeywordTriggers())
+	if keyword == "" {
+		return
+	}
+
+	// Starting the job can take a while so we avoid blocking the hook.
+	go p.startRecordingFromKeyword(post, keyword)
+}
+
+func (p *Plugin) startRecordingFromKeyword(post *model.Post, keyword string) {
+	channelID := post.ChannelId
+
+	if !p.licenseChecker.RecordingsAllowed() {
+		p.LogDebug("recording keyword trigger ignored: recordings are not allowed by the license", "channelID", channelID)
+		return
+	}
+
+	if cfg := p.getConfiguration(); !cfg.recordingsEnabled() {
+		p.LogDebug("recording keyword trigger ignored: recordings are not enabled", "channelID", channelID)
+		return
+	}
+
+	if p.getJobService() == nil {
+		p.LogWarn("recording keyword trigger ignored: job service is not initialized", "channelID", channelID)
+		return
+	}
+
+	poster, appErr := p.API.GetUser(post.UserId)
+	if appErr != nil {
+		p.LogError("failed to get user", "err", appErr.Error(), "userID", post.UserId)
+		return
+	}
+	if poster.IsBot {
+		p.LogDebug("recording keyword trigger ignored: posted by a bot", "channelID", channelID, "userID", post.UserId)
+		return
+	}
+
+	state, err := p.lockCallReturnState(channelID)
+	if err != nil {
+		p.LogError("failed to lock call", "err", err.Error(), "channelID", channelID)
+		return
+	}
+	defer p.unlockCall(channelID)
+
+	if state == nil {
+		return
+	}
+
+	if state.Recording != nil && state.Recording.EndAt == 0 {
+		p.LogDebug("recording keyword trigger ignored: recording already in progress", "callID", state.Call.ID)
+		return
+	}
+
+	// Only those who could otherwise start or stop the recording are
+	// allowed to trigger it, so that any channel member can't start a
+	// recording on the host's behalf.
+	if post.UserId != state.Call.GetHostID() && !p.API.HasPermissionTo(post.UserId, model.PermissionManageSystem) {
+		p.LogDebug("recording keyword trigger ignored: poster is neither the host nor an admin", "callID", state.Call.ID, "userID", post.UserId)
+		return
+	}
+
+	p.LogInfo("starting recording from keyword trigger",
+		"callID", state.Call.ID, "channelID", channelID, "postID", post.Id, "userID", post.UserId, "keyword", keyword)
+
+	if _, _, err := p.startRecordingJob(state, channelID, post.UserId, recordingStartRequest{}); err != nil {
+		p.LogError("failed to start recording from keyword trigger", "err", err.Error(), "callID", state.Call.ID)
+		return
+	}
+
+	T := p.getTranslationFunc("")
+	threadPost := &model.Post{
+		UserId:    p.getBotID(),
+		ChannelId: channelID,
+		RootId:    state.Call.ThreadID,
+		Message:   T("app.call.recording_keyword_trigger_message", map[string]any{"Keyword": keyword}),
+	}
+	if _, appErr := p.API.CreatePost(threadPost); appErr != nil {
+		p.LogError("failed to create post", "err", appErr.Error(), "channelID", channelID)
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchRecordingKeyword(t *testing.T) {
+	cfg := configuration{RecordingKeywordTriggers: " /SEV1, #incident,,/sev1"}
+	keywords := cfg.getRecordingKeywordTriggers()
+	require.Equal(t, []string{"/sev1", "#incident"}, keywords)
+
+	tcs := []struct {
+		message  string
+		expected string
+	}{
+		{"", ""},
+		{"sev1", ""},
+		{"this is a /sev1x", ""},
+		{"/sev1", "/sev1"},
+		{"Declaring a /Sev1!", "/sev1"},
+		{"we have an #incident, joining the call", "#incident"},
+	}
+
+	for _, tc := range tcs {
+		require.Equal(t, tc.expected, matchRecordingKeyword(tc.message, keywords), tc.message)
+	}
+
+	require.Empty(t, matchRecordingKeyword("/sev1", nil))
+}
+
+func TestIsAutomatedPost(t *testing.T) {
+	post := &model.Post{}
+	require.False(t, isAutomatedPost(post))
+
+	post.AddProp(model.PostPropsFromWebhook, "true")
+	require.True(t, isAutomatedPost(post))
+
+	post = &model.Post{}
+	post.AddProp(model.PostPropsFromBot, "true")
+	require.True(t, isAutomatedPost(post))
+}
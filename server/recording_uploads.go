@@ -0,0 +1,510 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+const (
+	recordingUploadsKey           = "recording_uploads"
+	recordingUploadsUpdateRetries = 5
+
+	recordingUploadStatusDelayed = "delayed"
+	recordingUploadStatusFailed  = "failed"
+	// recordingUploadStatusInterrupted is set when the plugin stopped before
+	// the upload could be stored. It's retried on the next activation.
+	recordingUploadStatusInterrupted = "interrupted"
+)
+
+var recUploadRetryBaseDelay = 2 * time.Second
+
+// recordingUpload tracks a recording upload that could not be stored in the
+// file store on the first attempt.
+type recordingUpload struct {
+	ID        string `json:"id"`
+	ChannelID string `json:"channel_id"`
+	Filename  string `json:"filename"`
+	FileSize  int64  `json:"file_size"`
+	// NodeID is the cluster node holding the spooled data.
+	NodeID   string `json:"node_id"`
+	Status   string `json:"status"`
+	Attempts int    `json:"attempts"`
+	Err      string `json:"err,omitempty"`
+	CreateAt int64  `json:"create_at"`
+	UpdateAt int64  `json:"update_at"`
+}
+
+// spooledChunk is a chunk of upload data saved to local disk, starting at the
+// given offset of the uploaded file.
+type spooledChunk struct {
+	path   string
+	offset int64
+}
+
+func (p *Plugin) getRecordingUploads() (map[string]recordingUpload, []byte, error) {
+	data, appErr := p.API.KVGet(recordingUploadsKey)
+	if appErr != nil {
+		return nil, nil, fmt.Errorf("failed to get recording uploads: %w", appErr)
+	}
+
+	uploads := map[string]recordingUpload{}
+	if data == nil {
+		return uploads, nil, nil
+	}
+
+	if err := json.Unmarshal(data, &uploads); err != nil {
+		return nil, nil, fmt.Errorf("failed to unmarshal recording uploads: %w", err)
+	}
+
+	return uploads, data, nil
+}
+
+// updateRecordingUploads applies the given update to the tracked recording
+// uploads. Concurrent updates from different nodes are detected and retried
+// so that no change is lost.
+func (p *Plugin) updateRecordingUploads(update func(uploads map[string]recordingUpload) error) error {
+	for i := 0; i < recordingUploadsUpdateRetries; i++ {
+		uploads, oldData, err := p.getRecordingUploads()
+		if err != nil {
+			return err
+		}
+
+		if err := update(uploads); err != nil {
+			return err
+		}
+
+		data, err := json.Marshal(uploads)
+		if err != nil {
+			return fmt.Errorf("failed to marshal recording uploads: %w", err)
+		}
+
+		ok, appErr := p.API.KVSetWithOptions(recordingUploadsKey, data, model.PluginKVSetOptions{
+			Atomic:   true,
+			OldValue: oldData,
+		})
+		if appErr != nil {
+			return fmt.Errorf("failed to set recording uploads: %w", appErr)
+		}
+		if ok {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("failed to update recording uploads: too many concurrent updates")
+}
+
+// spoolUploadChunk saves the given upload data to the spool directory so that
+// it can be replayed if storing it fails.
+func (p *Plugin) spoolUploadChunk(us *model.UploadSession, r io.Reader) (spooledChunk, error) {
+	dir := p.getConfiguration().recordingUploadSpoolDirectory()
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return spooledChunk{}, fmt.Errorf("failed to create spool directory: %w", err)
+	}
+
+	chunk := spooledChunk{
+		path:   filepath.Join(dir, fmt.Sprintf("%s_%d", us.Id, us.FileOffset)),
+		offset: us.FileOffset,
+	}
+
+	f, err := os.OpenFile(chunk.path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return spooledChunk{}, fmt.Errorf("failed to create spool file: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := io.Copy(f, r); err != nil {
+		os.Remove(chunk.path)
+		return spooledChunk{}, fmt.Errorf("failed to write spool file: %w", err)
+	}
+
+	return chunk, nil
+}
+
+// getSpooledChunks returns the chunks spooled for the given upload, sorted by
+// offset.
+func (p *Plugin) getSpooledChunks(uploadID string) ([]spooledChunk, error) {
+	paths, err := filepath.Glob(filepath.Join(p.getConfiguration().recordingUploadSpoolDirectory(), uploadID+"_*"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list spool files: %w", err)
+	}
+
+	chunks := make([]spooledChunk, 0, len(paths))
+	for _, path := range paths {
+		offset, err := strconv.ParseInt(strings.TrimPrefix(filepath.Base(path), uploadID+"_"), 10, 64)
+		if err != nil {
+			continue
+		}
+		chunks = append(chunks, spooledChunk{path: path, offset: offset})
+	}
+
+	sort.Slice(chunks, func(i, j int) bool {
+		return chunks[i].offset < chunks[j].offset
+	})
+
+	return chunks, nil
+}
+
+func (p *Plugin) removeSpooledChunks(uploadID string) {
+	chunks, err := p.getSpooledChunks(uploadID)
+	if err != nil {
+		p.LogError("failed to get spooled chunks", "err", err.Error(), "uploadID", uploadID)
+		return
+	}
+	for _, chunk := range chunks {
+		if err := os.Remove(chunk.path); err != nil {
+			p.LogError("failed to remove spool file", "err", err.Error(), "path", chunk.path)
+		}
+	}
+}
+
+// storeSpooledChunk pushes a spooled chunk to the file store. Since a failed
+// attempt may have partially stored the chunk, the upload is resumed from the
+// current offset of the upload session.
+func (p *Plugin) storeSpooledChunk(uploadID string, chunk spooledChunk) (*model.FileInfo, error) {
+	us, appErr := p.API.GetUploadSession(uploadID)
+	if appErr != nil {
+		return nil, fmt.Errorf("failed to get upload session: %w", appErr)
+	}
+
+	f, err := os.Open(chunk.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open spool file: %w", err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat spool file: %w", err)
+	}
+
+	skip := us.FileOffset - chunk.offset
+	if skip < 0 || skip > info.Size() {
+		return nil, fmt.Errorf("spooled data doesn't match the upload offset")
+	}
+
+	if _, err := f.Seek(skip, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("failed to seek spool file: %w", err)
+	}
+
+	fi, appErr := p.API.UploadData(us, f)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	return fi, nil
+}
+
+// storeRecordingUploadData stores a chunk of a recording upload sent by the
+// recorder. The data is spooled to local disk first so that storing it can be
+// retried with backoff in case the file store is unavailable. If all attempts
+// fail the spooled data is kept so that the upload can be retried manually
+// by a system admin.
+func (p *Plugin) storeRecordingUploadData(us *model.UploadSession, r io.Reader) (*model.FileInfo, error) {
+	chunk, err := p.spoolUploadChunk(us, r)
+	if err != nil {
+		return nil, err
+	}
+
+	maxRetries := *p.getConfiguration().RecordingUploadMaxRetries
+
+	var fi *model.FileInfo
+	var attempts int
+	var interrupted bool
+retryLoop:
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		if attempt > 0 {
+			if attempt == 1 {
+				p.trackRecordingUpload(us, recordingUploadStatusDelayed, attempt, err)
+			}
+			select {
+			case <-time.After(time.Duration(attempt) * recUploadRetryBaseDelay):
+			case <-p.stopCh:
+				err = fmt.Errorf("plugin is stopping")
+				interrupted = true
+				break retryLoop
+			}
+		}
+
+		attempts++
+		fi, err = p.storeSpooledChunk(us.Id, chunk)
+		if err == nil {
+			break
+		}
+
+		p.LogWarn("failed to store recording upload data",
+			"uploadID", us.Id, "attempt", fmt.Sprintf("%d", attempt+1), "err", err.Error())
+	}
+
+	if interrupted {
+		p.LogWarn("plugin is stopping, keeping recording upload data spooled for recovery", "uploadID", us.Id)
+		p.trackRecordingUpload(us, recordingUploadStatusInterrupted, attempts, err)
+		return nil, err
+	} else if err != nil {
+		p.LogError("failed to store recording upload data, keeping it spooled", "uploadID", us.Id, "err", err.Error())
+		p.trackRecordingUpload(us, recordingUploadStatusFailed, attempts, err)
+		return nil, err
+	}
+
+	if err := os.Remove(chunk.path); err != nil {
+		p.LogError("failed to remove spool file", "err", err.Error(), "path", chunk.path)
+	}
+
+	if fi != nil {
+		p.untrackRecordingUpload(us)
+	}
+
+	return fi, nil
+}
+
+// trackRecordingUpload saves the status of a recording upload that couldn't
+// be stored and notifies the call channel about it. Notifications are sent once
+// per recording since the recorder re-uploads the whole file on failure.
+func (p *Plugin) trackRecordingUpload(us *model.UploadSession, status string, attempts int, uploadErr error) {
+	var notify bool
+	err := p.updateRecordingUploads(func(uploads map[string]recordingUpload) error {
+		notify = true
+		for _, upload := range uploads {
+			if upload.ID != us.Id && upload.ChannelID == us.ChannelId && upload.Filename == us.Filename &&
+				(upload.Status == status || upload.Status == recordingUploadStatusFailed) {
+				notify = false
+			}
+		}
+
+		upload, ok := uploads[us.Id]
+		if !ok {
+			upload = recordingUpload{
+				ID:        us.Id,
+				ChannelID: us.ChannelId,
+				Filename:  us.Filename,
+				FileSize:  us.FileSize,
+				NodeID:    p.nodeID,
+				CreateAt:  time.Now().UnixMilli(),
+			}
+		}
+		// Interrupted uploads are notified as delayed since they get retried.
+		if upload.Status == status || (status == recordingUploadStatusInterrupted && upload.Status == recordingUploadStatusDelayed) {
+			notify = false
+		}
+		upload.Status = status
+		upload.Attempts = attempts
+		upload.Err = uploadErr.Error()
+		upload.UpdateAt = time.Now().UnixMilli()
+		uploads[us.Id] = upload
+
+		return nil
+	})
+	if err != nil {
+		p.LogError("failed to track recording upload", "err", err.Error(), "uploadID", us.Id)
+		return
+	}
+
+	if !notify {
+		return
+	}
+
+	T := p.getTranslationFunc("")
+	msgID := "app.call.recording_upload_delayed_message"
+	if status == recordingUploadStatusFailed {
+		msgID = "app.call.recording_upload_failed_message"
+	}
+	p.createRecordingUploadPost(us.ChannelId, T(msgID), nil)
+}
+
+// untrackRecordingUpload stops tracking a recording upload once stored,
+// along with any earlier failed upload of the same recording this node is
+// still holding.
+func (p *Plugin) untrackRecordingUpload(us *model.UploadSession) {
+	isTracked := func(id string, upload recordingUpload) bool {
+		return id == us.Id || (upload.ChannelID == us.ChannelId && upload.Filename == us.Filename)
+	}
+
+	// Most uploads succeed right away so we avoid the write when there's
+	// nothing to untrack.
+	uploads, _, err := p.getRecordingUploads()
+	if err != nil {
+		p.LogError("failed to get recording uploads", "err", err.Error(), "uploadID", us.Id)
+		return
+	}
+	var found bool
+	for id, upload := range uploads {
+		if isTracked(id, upload) {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return
+	}
+
+	var removed []string
+	err = p.updateRecordingUploads(func(uploads map[string]recordingUpload) error {
+		removed = removed[:0]
+		for id, upload := range uploads {
+			if isTracked(id, upload) {
+				delete(uploads, id)
+				if upload.NodeID == p.nodeID {
+					removed = append(removed, id)
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		p.LogError("failed to untrack recording upload", "err", err.Error(), "uploadID", us.Id)
+		return
+	}
+
+	for _, id := range removed {
+		p.removeSpooledChunks(id)
+	}
+}
+
+func (p *Plugin) createRecordingUploadPost(channelID, message string, fileIDs []string) {
+	post := &model.Post{
+		UserId:    p.getBotID(),
+		ChannelId: channelID,
+		Message:   message,
+		FileIds:   fileIDs,
+	}
+	if _, appErr := p.API.CreatePost(post); appErr != nil {
+		p.LogError("failed to create recording upload post", "err", appErr.Error(), "channelID", channelID)
+	}
+}
+
+// retryRecordingUpload replays the spooled data of a failed recording upload.
+// Once stored, the recording is posted in the call channel.
+func (p *Plugin) retryRecordingUpload(upload recordingUpload) (*model.FileInfo, error) {
+	chunks, err := p.getSpooledChunks(upload.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(chunks) == 0 {
+		return nil, fmt.Errorf("no spooled data found")
+	}
+
+	us, appErr := p.API.GetUploadSession(upload.ID)
+	if appErr != nil {
+		return nil, fmt.Errorf("failed to get upload session: %w", appErr)
+	}
+
+	var fi *model.FileInfo
+	for _, chunk := range chunks {
+		fi, err = p.storeSpooledChunk(upload.ID, chunk)
+		if err != nil {
+			p.trackRecordingUpload(us, recordingUploadStatusFailed, upload.Attempts+1, err)
+			return nil, err
+		}
+		if err := os.Remove(chunk.path); err != nil {
+			p.LogError("failed to remove spool file", "err", err.Error(), "path", chunk.path)
+		}
+	}
+
+	if fi == nil {
+		err := fmt.Errorf("spooled data doesn't cover the whole recording")
+		p.trackRecordingUpload(us, recordingUploadStatusFailed, upload.Attempts+1, err)
+		return nil, err
+	}
+
+	p.untrackRecordingUpload(us)
+
+	T := p.getTranslationFunc("")
+	p.createRecordingUploadPost(upload.ChannelID, T("app.call.recording_upload_recovered_message"), []string{fi.Id})
+
+	return fi, nil
+}
+
+func (p *Plugin) handleGetRecordingUploads(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("Mattermost-User-Id")
+
+	if !p.API.HasPermissionTo(userID, model.PermissionManageSystem) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	uploads, _, err := p.getRecordingUploads()
+	if err != nil {
+		p.LogError(err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	list := make([]recordingUpload, 0, len(uploads))
+	for _, upload := range uploads {
+		list = append(list, upload)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].CreateAt < list[j].CreateAt
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(list); err != nil {
+		p.LogError(err.Error())
+	}
+}
+
+func (p *Plugin) handleRetryRecordingUpload(w http.ResponseWriter, r *http.Request) {
+	var res httpResponse
+	defer p.httpAudit("handleRetryRecordingUpload", &res, w, r)
+
+	userID := r.Header.Get("Mattermost-User-Id")
+	uploadID := mux.Vars(r)["upload_id"]
+
+	if !p.API.HasPermissionTo(userID, model.PermissionManageSystem) {
+		res.Err = "Forbidden"
+		res.Code = http.StatusForbidden
+		return
+	}
+
+	uploads, _, err := p.getRecordingUploads()
+	if err != nil {
+		res.Err = err.Error()
+		res.Code = http.StatusInternalServerError
+		return
+	}
+
+	upload, ok := uploads[uploadID]
+	if !ok {
+		res.Err = "upload not found"
+		res.Code = http.StatusNotFound
+		return
+	}
+
+	// The spooled data is only available on the node that received it.
+	if upload.NodeID != p.nodeID {
+		res.Err = fmt.Sprintf("upload is spooled on a different node (%s)", upload.NodeID)
+		res.Code = http.StatusConflict
+		return
+	}
+
+	fi, err := p.retryRecordingUpload(upload)
+	if err != nil {
+		res.Err = err.Error()
+		res.Code = http.StatusInternalServerError
+		var appErr *model.AppError
+		if errors.As(err, &appErr) {
+			res.Code = appErr.StatusCode
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(fi); err != nil {
+		p.LogError(err.Error())
+	}
+}
@@ -0,0 +1,344 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin"
+
+	pluginMocks "github.com/mattermost/mattermost-plugin-calls/server/mocks/github.com/mattermost/mattermost/server/public/plugin"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"golang.org/x/time/rate"
+)
+
+func TestStoreRecordingUploadData(t *testing.T) {
+	defaultDelay := recUploadRetryBaseDelay
+	recUploadRetryBaseDelay = time.Millisecond
+	defer func() {
+		recUploadRetryBaseDelay = defaultDelay
+	}()
+
+	botID := model.NewId()
+	channelID := model.NewId()
+
+	setup := func(t *testing.T) (*Plugin, *pluginMocks.MockAPI, string) {
+		t.Helper()
+
+		mockAPI := &pluginMocks.MockAPI{}
+		t.Cleanup(func() { mockAPI.AssertExpectations(t) })
+
+		p := &Plugin{
+			MattermostPlugin: plugin.MattermostPlugin{
+				API: mockAPI,
+			},
+			botSession: &model.Session{UserId: botID},
+			stopCh:     make(chan struct{}),
+			nodeID:     "nodeA",
+		}
+
+		spoolDir := t.TempDir()
+		cfg := &configuration{}
+		cfg.SetDefaults()
+		cfg.RecordingUploadMaxRetries = model.NewPointer(2)
+		cfg.RecordingUploadSpoolDirectory = spoolDir
+		p.configuration = cfg
+
+		return p, mockAPI, spoolDir
+	}
+
+	newUploadSession := func() *model.UploadSession {
+		return &model.UploadSession{
+			Id:        model.NewId(),
+			ChannelId: channelID,
+			Filename:  "recording.mp4",
+			FileSize:  4,
+		}
+	}
+
+	spoolFiles := func(t *testing.T, dir string) []string {
+		t.Helper()
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		var names []string
+		for _, e := range entries {
+			names = append(names, e.Name())
+		}
+		return names
+	}
+
+	t.Run("stored", func(t *testing.T) {
+		p, mockAPI, spoolDir := setup(t)
+		us := newUploadSession()
+
+		mockAPI.On("GetUploadSession", us.Id).Return(us, nil).Once()
+		mockAPI.On("UploadData", us, mock.MatchedBy(func(r io.Reader) bool {
+			data, err := io.ReadAll(r)
+			return err == nil && string(data) == "data"
+		})).Return(&model.FileInfo{Id: "fileID"}, nil).Once()
+		mockAPI.On("KVGet", recordingUploadsKey).Return(nil, nil).Once()
+
+		fi, err := p.storeRecordingUploadData(us, strings.NewReader("data"))
+		require.NoError(t, err)
+		require.Equal(t, "fileID", fi.Id)
+		require.Empty(t, spoolFiles(t, spoolDir))
+	})
+
+	t.Run("stored after retry", func(t *testing.T) {
+		p, mockAPI, spoolDir := setup(t)
+		us := newUploadSession()
+
+		mockAPI.On("GetUploadSession", us.Id).Return(us, nil).Twice()
+		mockAPI.On("UploadData", us, mock.Anything).Return(nil, &model.AppError{Message: "store unavailable"}).Once()
+		mockAPI.On("UploadData", us, mock.Anything).Return(&model.FileInfo{Id: "fileID"}, nil).Once()
+		mockAPI.On("LogWarn", "failed to store recording upload data",
+			"origin", mock.Anything, "uploadID", us.Id, "attempt", "1", "err", "store unavailable").Once()
+
+		// Delayed upload gets tracked and notified.
+		mockAPI.On("KVGet", recordingUploadsKey).Return(nil, nil).Once()
+		mockAPI.On("KVSetWithOptions", recordingUploadsKey, mock.MatchedBy(func(data []byte) bool {
+			var uploads map[string]recordingUpload
+			require.NoError(t, json.Unmarshal(data, &uploads))
+			return uploads[us.Id].Status == recordingUploadStatusDelayed && uploads[us.Id].NodeID == "nodeA"
+		}), model.PluginKVSetOptions{Atomic: true}).Return(true, nil).Once()
+		mockAPI.On("GetConfig").Return(&model.Config{}).Once()
+		mockAPI.On("CreatePost", mock.MatchedBy(func(post *model.Post) bool {
+			return post.ChannelId == channelID && post.UserId == botID &&
+				post.Message == "app.call.recording_upload_delayed_message"
+		})).Return(&model.Post{}, nil).Once()
+
+		// Once stored it's untracked.
+		tracked, err := json.Marshal(map[string]recordingUpload{us.Id: {ID: us.Id, ChannelID: channelID, Status: recordingUploadStatusDelayed}})
+		require.NoError(t, err)
+		mockAPI.On("KVGet", recordingUploadsKey).Return(tracked, nil).Twice()
+		mockAPI.On("KVSetWithOptions", recordingUploadsKey, []byte("{}"), model.PluginKVSetOptions{Atomic: true, OldValue: tracked}).
+			Return(true, nil).Once()
+
+		fi, err := p.storeRecordingUploadData(us, strings.NewReader("data"))
+		require.NoError(t, err)
+		require.Equal(t, "fileID", fi.Id)
+		require.Empty(t, spoolFiles(t, spoolDir))
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		p, mockAPI, spoolDir := setup(t)
+		us := newUploadSession()
+
+		mockAPI.On("GetUploadSession", us.Id).Return(us, nil).Times(3)
+		mockAPI.On("UploadData", us, mock.Anything).Return(nil, &model.AppError{Message: "store unavailable"}).Times(3)
+		mockAPI.On("LogWarn", "failed to store recording upload data",
+			"origin", mock.Anything, "uploadID", us.Id, "attempt", mock.Anything, "err", "store unavailable").Times(3)
+		mockAPI.On("LogError", "failed to store recording upload data, keeping it spooled",
+			"origin", mock.Anything, "uploadID", us.Id, "err", "store unavailable").Once()
+
+		tracked, err := json.Marshal(map[string]recordingUpload{us.Id: {ID: us.Id, ChannelID: channelID, Status: recordingUploadStatusDelayed}})
+		require.NoError(t, err)
+		mockAPI.On("KVGet", recordingUploadsKey).Return(nil, nil).Once()
+		mockAPI.On("KVSetWithOptions", recordingUploadsKey, mock.Anything, model.PluginKVSetOptions{Atomic: true}).Return(true, nil).Once()
+		mockAPI.On("KVGet", recordingUploadsKey).Return(tracked, nil).Once()
+		mockAPI.On("KVSetWithOptions", recordingUploadsKey, mock.MatchedBy(func(data []byte) bool {
+			var uploads map[string]recordingUpload
+			require.NoError(t, json.Unmarshal(data, &uploads))
+			return uploads[us.Id].Status == recordingUploadStatusFailed && uploads[us.Id].Attempts == 3
+		}), model.PluginKVSetOptions{Atomic: true, OldValue: tracked}).Return(true, nil).Once()
+		mockAPI.On("GetConfig").Return(&model.Config{}).Twice()
+		mockAPI.On("CreatePost", mock.AnythingOfType("*model.Post")).Return(&model.Post{}, nil).Twice()
+
+		_, err = p.storeRecordingUploadData(us, strings.NewReader("data"))
+		require.EqualError(t, err, "store unavailable")
+
+		// The data is kept for a manual retry.
+		require.Equal(t, []string{us.Id + "_0"}, spoolFiles(t, spoolDir))
+		data, err := os.ReadFile(filepath.Join(spoolDir, us.Id+"_0"))
+		require.NoError(t, err)
+		require.Equal(t, "data", string(data))
+	})
+
+	t.Run("interrupted", func(t *testing.T) {
+		recUploadRetryBaseDelay = time.Hour
+		defer func() {
+			recUploadRetryBaseDelay = time.Millisecond
+		}()
+
+		p, mockAPI, spoolDir := setup(t)
+		us := newUploadSession()
+		close(p.stopCh)
+
+		mockAPI.On("GetUploadSession", us.Id).Return(us, nil).Once()
+		mockAPI.On("UploadData", us, mock.Anything).Return(nil, &model.AppError{Message: "store unavailable"}).Once()
+		mockAPI.On("LogWarn", "failed to store recording upload data",
+			"origin", mock.Anything, "uploadID", us.Id, "attempt", "1", "err", "store unavailable").Once()
+		mockAPI.On("LogWarn", "plugin is stopping, keeping recording upload data spooled for recovery",
+			"origin", mock.Anything, "uploadID", us.Id).Once()
+
+		// Only notified once as delayed.
+		tracked, err := json.Marshal(map[string]recordingUpload{us.Id: {ID: us.Id, ChannelID: channelID, Status: recordingUploadStatusDelayed}})
+		require.NoError(t, err)
+		mockAPI.On("KVGet", recordingUploadsKey).Return(nil, nil).Once()
+		mockAPI.On("KVSetWithOptions", recordingUploadsKey, mock.Anything, model.PluginKVSetOptions{Atomic: true}).Return(true, nil).Once()
+		mockAPI.On("KVGet", recordingUploadsKey).Return(tracked, nil).Once()
+		mockAPI.On("KVSetWithOptions", recordingUploadsKey, mock.MatchedBy(func(data []byte) bool {
+			var uploads map[string]recordingUpload
+			require.NoError(t, json.Unmarshal(data, &uploads))
+			return uploads[us.Id].Status == recordingUploadStatusInterrupted && uploads[us.Id].Attempts == 1
+		}), model.PluginKVSetOptions{Atomic: true, OldValue: tracked}).Return(true, nil).Once()
+		mockAPI.On("GetConfig").Return(&model.Config{}).Once()
+		mockAPI.On("CreatePost", mock.MatchedBy(func(post *model.Post) bool {
+			return post.Message == "app.call.recording_upload_delayed_message"
+		})).Return(&model.Post{}, nil).Once()
+
+		_, err = p.storeRecordingUploadData(us, strings.NewReader("data"))
+		require.EqualError(t, err, "plugin is stopping")
+
+		// The data is kept for recovery.
+		require.Equal(t, []string{us.Id + "_0"}, spoolFiles(t, spoolDir))
+	})
+}
+
+func TestWaitForRecordingFinalizations(t *testing.T) {
+	defaultInterval := recordingsFinalizePollInterval
+	recordingsFinalizePollInterval = time.Millisecond
+	defer func() {
+		recordingsFinalizePollInterval = defaultInterval
+	}()
+
+	p := &Plugin{}
+
+	require.True(t, p.waitForRecordingFinalizations(0))
+
+	done := p.startRecordingFinalization()
+	require.False(t, p.waitForRecordingFinalizations(10*time.Millisecond))
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		done()
+	}()
+	require.True(t, p.waitForRecordingFinalizations(time.Second))
+}
+
+func TestRecoverRecordingUploads(t *testing.T) {
+	mockAPI := &pluginMocks.MockAPI{}
+	defer mockAPI.AssertExpectations(t)
+
+	botID := model.NewId()
+	p := &Plugin{
+		MattermostPlugin: plugin.MattermostPlugin{
+			API: mockAPI,
+		},
+		botSession: &model.Session{UserId: botID},
+		nodeID:     "nodeA",
+	}
+
+	spoolDir := t.TempDir()
+	cfg := &configuration{}
+	cfg.SetDefaults()
+	cfg.RecordingUploadSpoolDirectory = spoolDir
+	p.configuration = cfg
+
+	channelID := model.NewId()
+	us := &model.UploadSession{
+		Id:        model.NewId(),
+		ChannelId: channelID,
+		Filename:  "recording.mp4",
+		FileSize:  4,
+	}
+	err := os.WriteFile(filepath.Join(spoolDir, us.Id+"_0"), []byte("data"), 0600)
+	require.NoError(t, err)
+
+	// Failed uploads and the ones spooled on other nodes are left alone.
+	tracked, err := json.Marshal(map[string]recordingUpload{
+		us.Id:         {ID: us.Id, ChannelID: channelID, Filename: us.Filename, NodeID: "nodeA", Status: recordingUploadStatusInterrupted},
+		model.NewId(): {ID: model.NewId(), ChannelID: channelID, NodeID: "nodeA", Status: recordingUploadStatusFailed},
+		model.NewId(): {ID: model.NewId(), ChannelID: channelID, NodeID: "nodeB", Status: recordingUploadStatusInterrupted},
+	})
+	require.NoError(t, err)
+	mockAPI.On("KVGet", recordingUploadsKey).Return(tracked, nil).Once()
+
+	mockAPI.On("LogInfo", "recovering interrupted recording upload", "origin", mock.Anything, "uploadID", us.Id).Once()
+	mockAPI.On("GetUploadSession", us.Id).Return(us, nil).Twice()
+	mockAPI.On("UploadData", us, mock.Anything).Return(&model.FileInfo{Id: "fileID"}, nil).Once()
+
+	// Once stored it's untracked and posted.
+	mockAPI.On("KVGet", recordingUploadsKey).Return(tracked, nil).Twice()
+	mockAPI.On("KVSetWithOptions", recordingUploadsKey, mock.MatchedBy(func(data []byte) bool {
+		var uploads map[string]recordingUpload
+		require.NoError(t, json.Unmarshal(data, &uploads))
+		_, ok := uploads[us.Id]
+		return !ok && len(uploads) == 2
+	}), model.PluginKVSetOptions{Atomic: true, OldValue: tracked}).Return(true, nil).Once()
+	mockAPI.On("GetConfig").Return(&model.Config{}).Once()
+	mockAPI.On("CreatePost", mock.MatchedBy(func(post *model.Post) bool {
+		return post.ChannelId == channelID && post.Message == "app.call.recording_upload_recovered_message" &&
+			len(post.FileIds) == 1 && post.FileIds[0] == "fileID"
+	})).Return(&model.Post{}, nil).Once()
+
+	p.recoverRecordingUploads()
+
+	entries, err := os.ReadDir(spoolDir)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
+
+func TestHandleRetryRecordingUpload(t *testing.T) {
+	mockAPI := &pluginMocks.MockAPI{}
+	defer mockAPI.AssertExpectations(t)
+
+	p := Plugin{
+		MattermostPlugin: plugin.MattermostPlugin{
+			API: mockAPI,
+		},
+		apiLimiters: map[string]*rate.Limiter{},
+		nodeID:      "nodeA",
+	}
+
+	apiRouter := p.newAPIRouter()
+
+	logArgs := []any{"handleRetryRecordingUpload"}
+	for i := 0; i < 18; i++ {
+		logArgs = append(logArgs, mock.Anything)
+	}
+	mockAPI.On("LogDebug", logArgs...)
+
+	userID := model.NewId()
+	uploadID := model.NewId()
+
+	retry := func() int {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("POST", "/recordings/uploads/"+uploadID+"/retry", nil)
+		r.Header.Set("Mattermost-User-Id", userID)
+		apiRouter.ServeHTTP(w, r)
+		return w.Result().StatusCode
+	}
+
+	t.Run("requires system admin", func(t *testing.T) {
+		mockAPI.On("HasPermissionTo", userID, model.PermissionManageSystem).Return(false).Once()
+		require.Equal(t, http.StatusForbidden, retry())
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mockAPI.On("HasPermissionTo", userID, model.PermissionManageSystem).Return(true).Once()
+		mockAPI.On("KVGet", recordingUploadsKey).Return(nil, nil).Once()
+		require.Equal(t, http.StatusNotFound, retry())
+	})
+
+	t.Run("spooled on another node", func(t *testing.T) {
+		tracked, err := json.Marshal(map[string]recordingUpload{uploadID: {ID: uploadID, NodeID: "nodeB", Status: recordingUploadStatusFailed}})
+		require.NoError(t, err)
+		mockAPI.On("HasPermissionTo", userID, model.PermissionManageSystem).Return(true).Once()
+		mockAPI.On("KVGet", recordingUploadsKey).Return(tracked, nil).Once()
+		require.Equal(t, http.StatusConflict, retry())
+	})
+}
@@ -0,0 +1,162 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+const (
+	recWebhookResponseMaxSizeBytes = 1024 * 1024 // 1MB
+)
+
+// The base delay between recording webhook attempts. Subsequent attempts
+// back off linearly.
+var recWebhookRetryBaseDelay = 5 * time.Second
+
+type recordingWebhookPayload struct {
+	ChannelID   string `json:"channel_id"`
+	CallID      string `json:"call_id"`
+	CallPostID  string `json:"call_post_id"`
+	RecordingID string `json:"recording_id"`
+	PostID      string `json:"post_id"`
+	FileID      string `json:"file_id"`
+	// FileURL is a public link to the recording file when public links are
+	// enabled on the server. Otherwise it points to the plugin's recording
+	// endpoint, which requires a Mattermost access token with read
+	// permissions on the channel.
+	FileURL string `json:"file_url"`
+	// CallMetadata is the custom metadata the call was started with, if any.
+	CallMetadata map[string]string `json:"call_metadata,omitempty"`
+	// Chapters are the sections of the recording marked by the host, if any.
+	Chapters []recordingChapter `json:"chapters,omitempty"`
+}
+
+type recordingWebhookResponse struct {
+	Summary string `json:"summary"`
+}
+
+// sendRecordingWebhook notifies the configured external service that a new
+// recording is available. If the service replies with a summary, this gets
+// posted in the call thread. It's meant to be run in a dedicated goroutine so
+// that it never delays the availability of the recording itself.
+func (p *Plugin) sendRecordingWebhook(payload recordingWebhookPayload, threadID string) {
+	cfg := p.getConfiguration()
+	if !cfg.recordingWebhookEnabled() {
+		return
+	}
+
+	if link, appErr := p.API.GetFileLink(payload.FileID); appErr == nil {
+		payload.FileURL = link
+	} else if siteURL := p.API.GetConfig().ServiceSettings.SiteURL; siteURL != nil && *siteURL != "" {
+		payload.FileURL = fmt.Sprintf("%s/plugins/%s/calls/%s/recordings/%s",
+			strings.TrimRight(*siteURL, "/"), manifest.Id, payload.ChannelID, payload.FileID)
+	}
+
+	client := cfg.newOutboundHTTPClient(time.Duration(*cfg.RecordingWebhookTimeoutSeconds) * time.Second)
+
+	var summary string
+	var retry bool
+	var err error
+	for attempt := 0; attempt <= *cfg.RecordingWebhookMaxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-time.After(time.Duration(attempt) * recWebhookRetryBaseDelay):
+			case <-p.stopCh:
+				p.LogWarn("plugin stopping, aborting recording webhook", "recID", payload.RecordingID)
+				return
+			}
+		}
+
+		summary, retry, err = p.postRecordingWebhook(client, cfg.RecordingWebhookURL, cfg.RecordingWebhookAuthToken, payload)
+		if err == nil || !retry {
+			break
+		}
+
+		p.LogWarn("recording webhook request failed",
+			"recID", payload.RecordingID, "attempt", fmt.Sprintf("%d", attempt+1), "err", err.Error())
+	}
+
+	if err != nil {
+		p.LogError("failed to send recording webhook", "recID", payload.RecordingID, "err", err.Error())
+		return
+	}
+
+	if summary == "" {
+		return
+	}
+
+	T := p.getTranslationFunc("")
+	summaryPost := &model.Post{
+		UserId:    p.getBotID(),
+		ChannelId: payload.ChannelID,
+		Message:   fmt.Sprintf("%s\n\n%s", T("app.call.recording_summary_message"), summary),
+		RootId:    threadID,
+	}
+	summaryPost.AddProp("recording_id", payload.RecordingID)
+	summaryPost.AddProp("call_post_id", payload.CallPostID)
+
+	if _, appErr := p.API.CreatePost(summaryPost); appErr != nil {
+		p.LogError("failed to create recording summary post", "recID", payload.RecordingID, "err", appErr.Error())
+	}
+}
+
+// postRecordingWebhook sends the payload to the webhook and returns the summary
+// found in the response, if any. In case of failure, the returned bool tells
+// whether the request is worth retrying: client errors and malformed responses
+// are not expected to go away on their own.
+func (p *Plugin) postRecordingWebhook(client *http.Client, webhookURL, authToken string, payload recordingWebhookPayload) (string, bool, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, webhookURL, bytes.NewReader(data))
+	if err != nil {
+		return "", false, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if authToken != "" {
+		req.Header.Set("Authorization", "Bearer "+authToken)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", true, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		// Timeouts and rate limiting are the only client errors worth retrying.
+		retry := resp.StatusCode >= 500 || resp.StatusCode == http.StatusRequestTimeout ||
+			resp.StatusCode == http.StatusTooManyRequests
+		return "", retry, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, recWebhookResponseMaxSizeBytes))
+	if err != nil {
+		return "", true, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	// An empty response means the service accepted the recording but has
+	// nothing to post back.
+	if len(bytes.TrimSpace(body)) == 0 {
+		return "", false, nil
+	}
+
+	var whResp recordingWebhookResponse
+	if err := json.Unmarshal(body, &whResp); err != nil {
+		return "", false, fmt.Errorf("failed to decode response body: %w", err)
+	}
+
+	return strings.TrimSpace(whResp.Summary), false, nil
+}
@@ -0,0 +1,203 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin"
+
+	pluginMocks "github.com/mattermost/mattermost-plugin-calls/server/mocks/github.com/mattermost/mattermost/server/public/plugin"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSendRecordingWebhook(t *testing.T) {
+	defaultDelay := recWebhookRetryBaseDelay
+	recWebhookRetryBaseDelay = time.Millisecond
+	defer func() {
+		recWebhookRetryBaseDelay = defaultDelay
+	}()
+
+	botID := model.NewId()
+	channelID := model.NewId()
+	threadID := model.NewId()
+	payload := recordingWebhookPayload{
+		ChannelID:   channelID,
+		CallID:      model.NewId(),
+		CallPostID:  threadID,
+		RecordingID: model.NewId(),
+		PostID:      model.NewId(),
+		FileID:      model.NewId(),
+	}
+
+	setup := func(t *testing.T, webhookURL, fileLink string) (*Plugin, *pluginMocks.MockAPI) {
+		t.Helper()
+
+		mockAPI := &pluginMocks.MockAPI{}
+		t.Cleanup(func() { mockAPI.AssertExpectations(t) })
+
+		p := &Plugin{
+			MattermostPlugin: plugin.MattermostPlugin{
+				API: mockAPI,
+			},
+			botSession: &model.Session{UserId: botID},
+			stopCh:     make(chan struct{}),
+		}
+
+		cfg := &configuration{}
+		cfg.SetDefaults()
+		cfg.EnableRecordings = model.NewPointer(true)
+		cfg.RecordingWebhookURL = webhookURL
+		cfg.RecordingWebhookAuthToken = "token"
+		cfg.RecordingWebhookMaxRetries = model.NewPointer(2)
+		p.configuration = cfg
+
+		if fileLink != "" {
+			mockAPI.On("GetFileLink", payload.FileID).Return(fileLink, nil).Once()
+		} else {
+			mockAPI.On("GetFileLink", payload.FileID).Return("", model.NewAppError("GetFileLink", "api.file.get_public_link.disabled.app_error",
+				nil, "", http.StatusNotImplemented)).Once()
+			mockAPI.On("GetConfig").Return(&model.Config{
+				ServiceSettings: model.ServiceSettings{
+					SiteURL: model.NewPointer("http://localhost:8065/"),
+				},
+			})
+		}
+
+		return p, mockAPI
+	}
+
+	t.Run("summary", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, http.MethodPost, r.Method)
+			require.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+
+			var data recordingWebhookPayload
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&data))
+			require.Equal(t, payload.RecordingID, data.RecordingID)
+			require.Equal(t, "http://localhost:8065/plugins/com.mattermost.calls/calls/"+channelID+"/recordings/"+payload.FileID, data.FileURL)
+
+			_, _ = w.Write([]byte(`{"summary": "A short summary"}`))
+		}))
+		defer ts.Close()
+
+		p, mockAPI := setup(t, ts.URL, "")
+
+		mockAPI.On("CreatePost", mock.MatchedBy(func(post *model.Post) bool {
+			return post.UserId == botID && post.ChannelId == channelID && post.RootId == threadID &&
+				post.GetProp("recording_id") == payload.RecordingID
+		})).Return(&model.Post{}, nil).Once()
+
+		p.sendRecordingWebhook(payload, threadID)
+	})
+
+	t.Run("public link", func(t *testing.T) {
+		fileLink := "http://localhost:8065/files/" + payload.FileID + "/public?h=hash"
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var data recordingWebhookPayload
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&data))
+			require.Equal(t, fileLink, data.FileURL)
+
+			w.WriteHeader(http.StatusAccepted)
+		}))
+		defer ts.Close()
+
+		p, _ := setup(t, ts.URL, fileLink)
+
+		p.sendRecordingWebhook(payload, threadID)
+	})
+
+	t.Run("no summary", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusAccepted)
+		}))
+		defer ts.Close()
+
+		p, _ := setup(t, ts.URL, "")
+
+		p.sendRecordingWebhook(payload, threadID)
+	})
+
+	t.Run("retry", func(t *testing.T) {
+		var calls int32
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			if atomic.AddInt32(&calls, 1) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			_, _ = w.Write([]byte(`{"summary": "A short summary"}`))
+		}))
+		defer ts.Close()
+
+		p, mockAPI := setup(t, ts.URL, "")
+
+		mockAPI.On("LogWarn", "recording webhook request failed",
+			"origin", mock.Anything, "recID", payload.RecordingID, "attempt", "1", "err", "unexpected status code 503").Once()
+		mockAPI.On("CreatePost", mock.AnythingOfType("*model.Post")).Return(&model.Post{}, nil).Once()
+
+		p.sendRecordingWebhook(payload, threadID)
+		require.Equal(t, int32(2), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		var calls int32
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer ts.Close()
+
+		p, mockAPI := setup(t, ts.URL, "")
+
+		mockAPI.On("LogWarn", "recording webhook request failed",
+			"origin", mock.Anything, "recID", payload.RecordingID, "attempt", mock.Anything, "err", "unexpected status code 500").Times(3)
+		mockAPI.On("LogError", "failed to send recording webhook",
+			"origin", mock.Anything, "recID", payload.RecordingID, "err", "unexpected status code 500").Once()
+
+		p.sendRecordingWebhook(payload, threadID)
+		require.Equal(t, int32(3), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("client error", func(t *testing.T) {
+		var calls int32
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			w.WriteHeader(http.StatusBadRequest)
+		}))
+		defer ts.Close()
+
+		p, mockAPI := setup(t, ts.URL, "")
+
+		mockAPI.On("LogError", "failed to send recording webhook",
+			"origin", mock.Anything, "recID", payload.RecordingID, "err", "unexpected status code 400").Once()
+
+		p.sendRecordingWebhook(payload, threadID)
+		require.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("invalid response", func(t *testing.T) {
+		var calls int32
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			_, _ = w.Write([]byte("<html>OK</html>"))
+		}))
+		defer ts.Close()
+
+		p, mockAPI := setup(t, ts.URL, "")
+
+		mockAPI.On("LogError", "failed to send recording webhook",
+			"origin", mock.Anything, "recID", payload.RecordingID, "err", mock.Anything).Once()
+
+		p.sendRecordingWebhook(payload, threadID)
+		require.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	})
+}
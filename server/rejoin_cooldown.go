@@ -0,0 +1,121 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+const rejoinCooldownKeyPrefix = "rejoin_cooldown_"
+
+// rejoinCooldownReason is why a participant left a call without choosing to,
+// which determines how long they have to wait before joining it again.
+type rejoinCooldownReason string
+
+const (
+	// Removed by the host.
+	rejoinCooldownReasonKicked rejoinCooldownReason = "kicked"
+	// Disconnected because of an error (e.g. lost connection).
+	rejoinCooldownReasonDropped rejoinCooldownReason = "dropped"
+)
+
+type rejoinCooldown struct {
+	Reason rejoinCooldownReason `json:"reason"`
+	Until  int64                `json:"until"`
+}
+
+type rejoinCooldownError struct {
+	reason    rejoinCooldownReason
+	remaining time.Duration
+}
+
+func (e *rejoinCooldownError) Error() string {
+	// Rounding up so that we never tell users they can rejoin in 0s.
+	remaining := (e.remaining + time.Second - 1).Truncate(time.Second)
+	if e.reason == rejoinCooldownReasonKicked {
+		return fmt.Sprintf("you were removed from this call by the host, you can rejoin in %s", remaining)
+	}
+	return fmt.Sprintf("you were disconnected from this call, you can rejoin in %s", remaining)
+}
+
+func rejoinCooldownKey(channelID, userID string) string {
+	return rejoinCooldownKeyPrefix + channelID + "_" + userID
+}
+
+func (p *Plugin) getRejoinCooldown(channelID, userID string) (*rejoinCooldown, error) {
+	data, appErr := p.API.KVGet(rejoinCooldownKey(channelID, userID))
+	if appErr != nil {
+		return nil, fmt.Errorf("failed to get rejoin cooldown: %w", appErr)
+	}
+	if data == nil {
+		return nil, nil
+	}
+
+	var cooldown rejoinCooldown
+	if err := json.Unmarshal(data, &cooldown); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal rejoin cooldown: %w", err)
+	}
+
+	return &cooldown, nil
+}
+
+// setRejoinCooldown prevents the user from joining the call in the given
+// channel for the time configured for the reason. An ongoing cooldown that
+// ends later is left untouched so that, for example, the connection
+// dropping as a consequence of a kick can't shorten it.
+func (p *Plugin) setRejoinCooldown(channelID, userID string, reason rejoinCooldownReason) {
+	if userID == p.getBotID() {
+		return
+	}
+
+	duration := p.getConfiguration().getRejoinCooldown(reason)
+	if duration == 0 {
+		return
+	}
+
+	until := time.Now().Add(duration)
+
+	if cooldown, err := p.getRejoinCooldown(channelID, userID); err != nil {
+		p.LogError(err.Error(), "channelID", channelID, "userID", userID)
+	} else if cooldown != nil && cooldown.Until >= until.UnixMilli() {
+		return
+	}
+
+	data, err := json.Marshal(rejoinCooldown{Reason: reason, Until: until.UnixMilli()})
+	if err != nil {
+		p.LogError("failed to marshal rejoin cooldown", "err", err.Error())
+		return
+	}
+
+	// The key expires along with the cooldown, rounding up to the next second.
+	ttl := int64((duration + time.Second - 1) / time.Second)
+	if appErr := p.API.KVSetWithExpiry(rejoinCooldownKey(channelID, userID), data, ttl); appErr != nil {
+		p.LogError("failed to set rejoin cooldown", "err", appErr.Error(), "channelID", channelID, "userID", userID)
+	}
+}
+
+// checkRejoinCooldown returns a *rejoinCooldownError if the user has to wait
+// before joining the call in the given channel.
+func (p *Plugin) checkRejoinCooldown(userID, channelID string) error {
+	if userID == p.getBotID() {
+		return nil
+	}
+
+	cooldown, err := p.getRejoinCooldown(channelID, userID)
+	if err != nil {
+		return err
+	}
+
+	if cooldown == nil {
+		return nil
+	}
+
+	if remaining := time.Until(time.UnixMilli(cooldown.Until)); remaining > 0 {
+		return &rejoinCooldownError{reason: cooldown.Reason, remaining: remaining}
+	}
+
+	return nil
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	pluginMocks "github.com/mattermost/mattermost-plugin-calls/server/mocks/github.com/mattermost/mattermost/server/public/plugin"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRejoinCooldown(t *testing.T) {
+	mockAPI := &pluginMocks.MockAPI{}
+	defer mockAPI.AssertExpectations(t)
+
+	cfg := &configuration{}
+	cfg.SetDefaults()
+	cfg.DroppedRejoinCooldownSeconds = model.NewPointer(5)
+
+	p := &Plugin{
+		MattermostPlugin: plugin.MattermostPlugin{
+			API: mockAPI,
+		},
+		configuration: cfg,
+	}
+
+	key := rejoinCooldownKey("channelID", "userID")
+
+	t.Run("no cooldown", func(t *testing.T) {
+		mockAPI.On("KVGet", key).Return(nil, nil).Once()
+		require.NoError(t, p.checkRejoinCooldown("userID", "channelID"))
+	})
+
+	t.Run("kicked", func(t *testing.T) {
+		var data []byte
+		mockAPI.On("KVGet", key).Return(nil, nil).Once()
+		mockAPI.On("KVSetWithExpiry", key, mock.Anything, int64(60)).Run(func(args mock.Arguments) {
+			data = args.Get(1).([]byte)
+		}).Return(nil).Once()
+		p.setRejoinCooldown("channelID", "userID", rejoinCooldownReasonKicked)
+
+		mockAPI.On("KVGet", key).Return(data, nil).Once()
+		err := p.checkRejoinCooldown("userID", "channelID")
+		var cooldownErr *rejoinCooldownError
+		require.ErrorAs(t, err, &cooldownErr)
+		require.Equal(t, rejoinCooldownReasonKicked, cooldownErr.reason)
+		require.EqualError(t, err, "you were removed from this call by the host, you can rejoin in 1m0s")
+
+		// Dropping right after being kicked doesn't shorten the cooldown.
+		mockAPI.On("KVGet", key).Return(data, nil).Once()
+		p.setRejoinCooldown("channelID", "userID", rejoinCooldownReasonDropped)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		data, err := json.Marshal(rejoinCooldown{Reason: rejoinCooldownReasonDropped, Until: time.Now().Add(-time.Second).UnixMilli()})
+		require.NoError(t, err)
+		mockAPI.On("KVGet", key).Return(data, nil).Once()
+		require.NoError(t, p.checkRejoinCooldown("userID", "channelID"))
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		cfg.DroppedRejoinCooldownSeconds = model.NewPointer(0)
+		p.setRejoinCooldown("channelID", "userID", rejoinCooldownReasonDropped)
+	})
+}
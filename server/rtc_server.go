@@ -0,0 +1,205 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-calls/server/db"
+
+	"github.com/mattermost/rtcd/service/rtc"
+)
+
+var errRTCServerBusy = errors.New("calls are ongoing on this node")
+
+// Types of RTC server hosting the calls, used as metric labels.
+const (
+	rtcServerTypeEmbedded = "embedded"
+	rtcServerTypeRTCD     = "rtcd"
+)
+
+// getRTCServerType returns the type of RTC server hosting the calls.
+func (p *Plugin) getRTCServerType() string {
+	if p.rtcdManager != nil {
+		return rtcServerTypeRTCD
+	}
+	return rtcServerTypeEmbedded
+}
+
+// How often a pending reload of the embedded RTC server checks whether the
+// node has become idle.
+var rtcServerReloadCheckInterval = 30 * time.Second
+
+// newRTCServerConfig builds the embedded RTC server configuration from the
+// plugin settings. All the settings it reads (addresses, ports, ICE host
+// overrides, ICE servers, TURN and IPv6) can be changed without a plugin
+// restart: they are applied by reloading the server once no calls are hosted
+// on the node. Switching between the embedded server and RTCD (RTCDServiceURL)
+// still requires a restart.
+func (p *Plugin) newRTCServerConfig(cfg *configuration) (rtc.ServerConfig, error) {
+	rtcServerConfig := rtc.ServerConfig{
+		ICEAddressUDP:   rtc.ICEAddress(cfg.UDPServerAddress),
+		ICEAddressTCP:   rtc.ICEAddress(cfg.TCPServerAddress),
+		ICEPortUDP:      *cfg.UDPServerPort,
+		ICEPortTCP:      *cfg.TCPServerPort,
+		ICEHostOverride: cfg.ICEHostOverride,
+		ICEServers:      rtc.ICEServers(cfg.getICEServers(false)),
+		TURNConfig: rtc.TURNConfig{
+			CredentialsExpirationMinutes: *cfg.TURNCredentialsExpirationMinutes,
+		},
+		EnableIPv6:      *cfg.EnableIPv6,
+		UDPSocketsCount: runtime.NumCPU(),
+	}
+	if *cfg.ServerSideTURN {
+		rtcServerConfig.TURNConfig.StaticAuthSecret = cfg.TURNStaticAuthSecret
+	}
+	if cfg.ICEInterface != "" {
+		// The interface is resolved when the server is created so that a
+		// missing or misconfigured interface fails loudly instead of silently
+		// advertising candidates on the wrong network.
+		ips, err := getInterfaceIPs(cfg.ICEInterface, *cfg.EnableIPv6)
+		if err != nil {
+			return rtc.ServerConfig{}, fmt.Errorf("failed to resolve ICEInterface: %w", err)
+		}
+		addrs := strings.Join(ips, ",")
+		p.LogDebug("binding RTC server to interface", "iface", cfg.ICEInterface, "addrs", addrs)
+		rtcServerConfig.ICEAddressUDP = rtc.ICEAddress(addrs)
+		rtcServerConfig.ICEAddressTCP = rtc.ICEAddress(addrs)
+	}
+	if cfg.ICEHostPortOverride != nil {
+		rtcServerConfig.ICEHostPortOverride = rtc.ICEHostPortOverride(fmt.Sprintf("%d", *cfg.ICEHostPortOverride))
+	}
+
+	return rtcServerConfig, nil
+}
+
+func (p *Plugin) startRTCServer(rtcServerConfig rtc.ServerConfig) (*rtc.Server, error) {
+	rtcServer, err := rtc.NewServer(rtcServerConfig, newLogger(p), p.metrics.RTCMetrics())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := rtcServer.Start(); err != nil {
+		return nil, err
+	}
+
+	return rtcServer, nil
+}
+
+func (p *Plugin) getRTCServer() *rtc.Server {
+	p.rtcServerMut.RLock()
+	defer p.rtcServerMut.RUnlock()
+	return p.rtcServer
+}
+
+// reloadRTCServer replaces the embedded RTC server with one built from the
+// current configuration. To avoid dropping calls this only happens if no calls
+// are hosted on this node, errRTCServerBusy is returned otherwise.
+func (p *Plugin) reloadRTCServer() error {
+	if p.getRTCServer() == nil {
+		return fmt.Errorf("embedded RTC server is not in use")
+	}
+
+	rtcServerConfig, err := p.newRTCServerConfig(p.getConfiguration())
+	if err != nil {
+		return err
+	}
+
+	p.rtcServerMut.Lock()
+	oldServer := p.rtcServer
+	oldConfig := p.rtcServerConfig
+	if reflect.DeepEqual(oldConfig, rtcServerConfig) {
+		p.rtcServerMut.Unlock()
+		return nil
+	}
+
+	// Calls record the node hosting them before any RTC session is
+	// initialized so, while holding the lock, this is enough to guarantee
+	// that no session is using the server.
+	calls, err := p.store.GetAllActiveCalls(db.GetCallOpts{})
+	if err != nil {
+		p.rtcServerMut.Unlock()
+		return fmt.Errorf("failed to get active calls: %w", err)
+	}
+	for _, call := range calls {
+		if call.Props.NodeID == p.nodeID {
+			p.rtcServerMut.Unlock()
+			return errRTCServerBusy
+		}
+	}
+
+	// The old server needs to be stopped first as the new one may be binding
+	// the same addresses.
+	if err := oldServer.Stop(); err != nil {
+		p.LogError("failed to stop RTC server", "err", err.Error())
+	}
+
+	p.LogInfo("reloading RTC server")
+
+	rtcServer, err := p.startRTCServer(rtcServerConfig)
+	if err != nil {
+		p.LogError("failed to start RTC server with new configuration, restoring previous one", "err", err.Error())
+		rtcServerConfig = oldConfig
+		rtcServer, err = p.startRTCServer(oldConfig)
+		if err != nil {
+			p.rtcServerMut.Unlock()
+			return fmt.Errorf("failed to start RTC server: %w", err)
+		}
+	}
+	p.rtcServer = rtcServer
+	p.rtcServerConfig = rtcServerConfig
+	p.rtcServerMut.Unlock()
+
+	// The previous wsWriter routine returns once its server is stopped.
+	go p.wsWriter(rtcServer)
+
+	return nil
+}
+
+// scheduleRTCServerReload applies RTC settings changes to the embedded server.
+// If calls are ongoing the reload waits for them to end.
+func (p *Plugin) scheduleRTCServerReload() {
+	if p.getRTCServer() == nil {
+		return
+	}
+
+	// A pending reload always applies the latest configuration.
+	if !p.rtcServerReloadPending.CompareAndSwap(false, true) {
+		return
+	}
+
+	go func() {
+		defer p.rtcServerReloadPending.Store(false)
+
+		ticker := time.NewTicker(rtcServerReloadCheckInterval)
+		defer ticker.Stop()
+
+		logged := false
+		for {
+			err := p.reloadRTCServer()
+			if err == nil {
+				return
+			} else if !errors.Is(err, errRTCServerBusy) {
+				p.LogError("failed to reload RTC server", "err", err.Error())
+				return
+			}
+
+			if !logged {
+				p.LogInfo("RTC settings have changed, they will be applied once no calls are ongoing on this node")
+				logged = true
+			}
+
+			select {
+			case <-ticker.C:
+			case <-p.stopCh:
+				return
+			}
+		}
+	}()
+}
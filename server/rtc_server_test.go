@@ -0,0 +1,81 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mattermost/rtcd/service/rtc"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRTCServerConfig(t *testing.T) {
+	p := &Plugin{}
+
+	t.Run("defaults", func(t *testing.T) {
+		cfg := &configuration{}
+		cfg.SetDefaults()
+
+		rtcCfg, err := p.newRTCServerConfig(cfg)
+		require.NoError(t, err)
+		require.Equal(t, *cfg.UDPServerPort, rtcCfg.ICEPortUDP)
+		require.Equal(t, *cfg.TCPServerPort, rtcCfg.ICEPortTCP)
+		require.Empty(t, rtcCfg.ICEAddressUDP)
+		require.Empty(t, rtcCfg.ICEAddressTCP)
+		require.Empty(t, rtcCfg.TURNConfig.StaticAuthSecret)
+		require.Empty(t, rtcCfg.ICEHostPortOverride)
+	})
+
+	t.Run("TURN and ICE", func(t *testing.T) {
+		cfg := &configuration{}
+		cfg.SetDefaults()
+		cfg.ICEServersConfigs = ICEServersConfigs{
+			{
+				URLs: []string{"turn:turn.example.com:3478"},
+			},
+		}
+		cfg.TURNStaticAuthSecret = "secret"
+		cfg.ICEHostOverride = "10.0.0.1"
+		cfg.ICEHostPortOverride = model.NewPointer(30443)
+
+		rtcCfg, err := p.newRTCServerConfig(cfg)
+		require.NoError(t, err)
+		require.Equal(t, rtc.ICEServers(cfg.getICEServers(false)), rtcCfg.ICEServers)
+		require.Empty(t, rtcCfg.TURNConfig.StaticAuthSecret)
+		require.Equal(t, "10.0.0.1", rtcCfg.ICEHostOverride)
+		require.Equal(t, rtc.ICEHostPortOverride("30443"), rtcCfg.ICEHostPortOverride)
+
+		cfg.ServerSideTURN = model.NewPointer(true)
+		rtcCfg, err = p.newRTCServerConfig(cfg)
+		require.NoError(t, err)
+		require.Equal(t, "secret", rtcCfg.TURNConfig.StaticAuthSecret)
+	})
+
+	t.Run("missing interface", func(t *testing.T) {
+		cfg := &configuration{}
+		cfg.SetDefaults()
+		cfg.ICEInterface = "notaniface0"
+
+		_, err := p.newRTCServerConfig(cfg)
+		require.ErrorContains(t, err, "failed to resolve ICEInterface")
+	})
+}
+
+func TestReloadRTCServer(t *testing.T) {
+	p := &Plugin{}
+	cfg := &configuration{}
+	cfg.SetDefaults()
+	p.configuration = cfg
+
+	t.Run("embedded server not in use", func(t *testing.T) {
+		err := p.reloadRTCServer()
+		require.EqualError(t, err, "embedded RTC server is not in use")
+
+		p.scheduleRTCServerReload()
+		require.False(t, p.rtcServerReloadPending.Load())
+	})
+}
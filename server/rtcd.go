@@ -40,11 +40,24 @@ const (
 
 var errClientReplaced = errors.New("client replaced")
 
+// The delay before the first retry when sending a message to rtcd fails. It
+// doubles on every subsequent attempt up to rtcdSendRetryMaxDelay.
+var (
+	rtcdSendRetryBaseDelay = 100 * time.Millisecond
+	rtcdSendRetryMaxDelay  = 2 * time.Second
+)
+
 type rtcdHost struct {
 	ip      string
 	client  interfaces.RTCDClient
 	flagged bool
-	mut     sync.RWMutex
+	// draining is set when an admin requested the host to be drained.
+	draining bool
+	// connectAt is the time the client for this host was connected.
+	connectAt time.Time
+	// lastPingAt is the time of the last successful keepalive ping.
+	lastPingAt time.Time
+	mut        sync.RWMutex
 }
 
 type rtcdClientManager struct {
@@ -52,6 +65,9 @@ type rtcdClientManager struct {
 
 	rtcdURL  string
 	rtcdPort string
+	// proxyURL is the optional plugin level proxy connections to rtcd
+	// are tunneled through.
+	proxyURL *url.URL
 
 	hosts map[string]*rtcdHost
 
@@ -61,10 +77,11 @@ type rtcdClientManager struct {
 
 func (p *Plugin) newRTCDClientManager(rtcdURL string) (m *rtcdClientManager, err error) {
 	m = &rtcdClientManager{
-		ctx:     p,
-		rtcdURL: rtcdURL,
-		closeCh: make(chan struct{}),
-		hosts:   map[string]*rtcdHost{},
+		ctx:      p,
+		rtcdURL:  rtcdURL,
+		proxyURL: p.getConfiguration().getOutboundProxyURL(),
+		closeCh:  make(chan struct{}),
+		hosts:    map[string]*rtcdHost{},
 	}
 
 	ips, port, err := resolveURL(rtcdURL, resolveTimeout)
@@ -85,7 +102,7 @@ func (p *Plugin) newRTCDClientManager(rtcdURL string) (m *rtcdClientManager, err
 	}()
 
 	for _, ip := range ips {
-		client, err := m.newRTCDClient(rtcdURL, ip.String(), getDialFn(ip.String(), port))
+		client, err := m.newRTCDClient(rtcdURL, ip.String(), m.getDialFn(ip.String(), port))
 		if err != nil {
 			return nil, err
 		}
@@ -97,10 +114,69 @@ func (p *Plugin) newRTCDClientManager(rtcdURL string) (m *rtcdClientManager, err
 	}
 
 	go m.hostsChecker()
+	go m.keepalive()
 
 	return m, nil
 }
 
+// keepalive runs in a dedicated goroutine that routinely pings all the
+// connected hosts. Besides surfacing unhealthy hosts early, this keeps the
+// underlying connections warm so that the first call after an idle period
+// doesn't pay the cost of re-establishing them.
+func (m *rtcdClientManager) keepalive() {
+	for {
+		// The interval is read on every iteration so that configuration
+		// changes apply without a restart.
+		interval := m.ctx.getConfiguration().getRTCDKeepaliveInterval()
+		enabled := interval > 0
+		if !enabled {
+			interval = hostCheckInterval
+		}
+
+		select {
+		case <-time.After(interval):
+			if enabled {
+				m.pingHosts()
+			}
+		case <-m.closeCh:
+			return
+		}
+	}
+}
+
+// pingHosts checks the connected hosts, tracking when each was last
+// successfully reached.
+func (m *rtcdClientManager) pingHosts() {
+	m.mut.RLock()
+	hosts := make([]*rtcdHost, 0, len(m.hosts))
+	for _, host := range m.hosts {
+		hosts = append(hosts, host)
+	}
+	m.mut.RUnlock()
+
+	for _, host := range hosts {
+		// Disconnected clients are already being taken care of by the
+		// reconnect handler.
+		if !host.client.Connected() {
+			continue
+		}
+
+		if _, err := host.client.GetSystemInfo(); err != nil {
+			m.ctx.LogWarn("failed to ping rtcd host", "host", host.ip, "err", err.Error())
+			continue
+		}
+
+		now := time.Now()
+		host.mut.Lock()
+		host.lastPingAt = now
+		connectAt := host.connectAt
+		host.mut.Unlock()
+
+		m.ctx.metrics.SetRTCDLastPingTime(host.ip, float64(now.Unix()))
+		m.ctx.metrics.SetRTCDConnectionAge(host.ip, now.Sub(connectAt).Seconds())
+	}
+}
+
 // hostsChecker runs in a dedicated goroutine that routinely resolves all
 // the available hosts (ip addresses) pointed by the rtcd URL that are advertised through DNS.
 // When new hosts are found a client for them is created. Hosts that are missing
@@ -139,6 +215,8 @@ func (m *rtcdClientManager) hostsChecker() {
 			}
 			m.mut.RUnlock()
 
+			m.updateDrainingHosts()
+
 			// we look for newly advertised hosts we may not have a client for yet.
 			for ip := range ipsMap {
 				if h := m.getHost(ip); h == nil {
@@ -149,7 +227,7 @@ func (m *rtcdClientManager) hostsChecker() {
 					time.Sleep(time.Duration(rand.Intn(baseReconnectIntervalMs)) * time.Millisecond)
 
 					m.ctx.LogDebug("creating client for missing host", "host", ip)
-					client, err := m.newRTCDClient(m.rtcdURL, ip, getDialFn(ip, m.rtcdPort))
+					client, err := m.newRTCDClient(m.rtcdURL, ip, m.getDialFn(ip, m.rtcdPort))
 					if err != nil {
 						m.ctx.LogError(fmt.Sprintf("failed to create new client: %s", err.Error()), "host", ip)
 						continue
@@ -180,6 +258,7 @@ func (m *rtcdClientManager) removeHost(host string) error {
 
 	_ = h.client.Close()
 	delete(m.hosts, host)
+	m.ctx.metrics.DeleteRTCDHostMetrics(host)
 
 	return nil
 }
@@ -201,8 +280,9 @@ func (m *rtcdClientManager) addHost(host string, client *rtcd.Client) (err error
 	}
 
 	m.hosts[host] = &rtcdHost{
-		ip:     host,
-		client: client,
+		ip:        host,
+		client:    client,
+		connectAt: time.Now(),
 	}
 
 	go m.clientReader(client)
@@ -226,7 +306,7 @@ func (m *rtcdClientManager) GetHostForNewCall() (string, error) {
 	var hostsAvailable []*rtcdHost
 	for ip, host := range m.hosts {
 		host.mut.RLock()
-		flagged := host.flagged
+		flagged := host.flagged || host.draining
 		host.mut.RUnlock()
 
 		offline := !host.client.Connected()
@@ -278,27 +358,94 @@ func (m *rtcdClientManager) GetHostForNewCall() (string, error) {
 
 // Send routes the message to the appropriate host that's handling the given
 // call. If this is missing a new client is created and added to the mapping.
+//
+// Failed sends are retried with an exponential backoff, up to the configured
+// number of retries and within the configured timeout. Messages needed to set
+// up calls are also held while the client is reconnecting so that a brief
+// disconnection doesn't make the negotiation fail.
 func (m *rtcdClientManager) Send(msg rtcd.ClientMessage, host string) error {
-	var client interfaces.RTCDClient
-
 	if host == "" {
 		return fmt.Errorf("host should not be empty")
 	}
 
-	if h := m.getHost(host); h == nil {
+	cfg := m.ctx.getConfiguration()
+	maxRetries := cfg.getRTCDSendMaxRetries()
+	deadline := time.Now().Add(cfg.getRTCDSendTimeout())
+	critical := isCriticalRTCDMessage(msg)
+	delay := rtcdSendRetryBaseDelay
+
+	for retries := 0; ; {
+		err := m.send(msg, host)
+		if err == nil {
+			return nil
+		}
+
+		// Waiting for a reconnection doesn't count towards the retries.
+		buffering := critical && m.isReconnecting(host)
+		if !buffering && retries >= maxRetries {
+			m.ctx.metrics.IncRTCDMessagesDropped(msg.Type)
+			return err
+		}
+
+		wait := min(delay, time.Until(deadline))
+		if wait <= 0 {
+			m.ctx.metrics.IncRTCDMessagesDropped(msg.Type)
+			return fmt.Errorf("timed out sending message: %w", err)
+		}
+
+		m.ctx.LogDebug("failed to send message to rtcd, retrying",
+			"host", host, "type", msg.Type, "buffering", fmt.Sprintf("%t", buffering), "err", err.Error())
+
+		select {
+		case <-time.After(wait):
+		case <-m.closeCh:
+			m.ctx.metrics.IncRTCDMessagesDropped(msg.Type)
+			return err
+		}
+
+		if !buffering {
+			retries++
+		}
+		m.ctx.metrics.IncRTCDMessageRetries(msg.Type)
+		delay = min(2*delay, rtcdSendRetryMaxDelay)
+	}
+}
+
+func (m *rtcdClientManager) send(msg rtcd.ClientMessage, host string) error {
+	// The host is looked up on every attempt as its client may get replaced
+	// in the meantime (see reconnectCb).
+	h := m.getHost(host)
+	if h == nil {
 		m.ctx.LogDebug("creating client for missing host on send", "host", host)
-		client, err := m.newRTCDClient(m.rtcdURL, host, getDialFn(host, m.rtcdPort))
+		client, err := m.newRTCDClient(m.rtcdURL, host, m.getDialFn(host, m.rtcdPort))
 		if err != nil {
 			return fmt.Errorf("failed to create new client: %w", err)
 		}
 		if err := m.addHost(host, client); err != nil {
 			return fmt.Errorf("failed to add host: %w", err)
 		}
-	} else {
-		client = h.client
+		return client.Send(msg)
 	}
 
-	return client.Send(msg)
+	return h.client.Send(msg)
+}
+
+// isReconnecting returns whether the client for the given host is known but
+// currently disconnected.
+func (m *rtcdClientManager) isReconnecting(host string) bool {
+	h := m.getHost(host)
+	return h != nil && !h.client.Connected()
+}
+
+// isCriticalRTCDMessage returns whether the message is needed to set up or
+// resume a call, in which case it's worth holding it during brief reconnects.
+func isCriticalRTCDMessage(msg rtcd.ClientMessage) bool {
+	switch msg.Type {
+	case rtcd.ClientMessageJoin, rtcd.ClientMessageReconnect, rtcd.ClientMessageRTC:
+		return true
+	default:
+		return false
+	}
 }
 
 func (m *rtcdClientManager) Close() error {
@@ -653,10 +800,25 @@ func (m *rtcdClientManager) handleClientMsg(msg rtcd.ClientMessage) error {
 			return fmt.Errorf("failed to get call sessions: %w", err)
 		}
 
+		// Voice activity from a muted session means audio is being sent without
+		// the client having unmuted through the server first.
+		if session := sessions[rtcMsg.SessionID]; rtcMsg.Type == rtc.VoiceOnMessage && call.Props.JoinMuted && session != nil && !session.Unmuted {
+			go m.ctx.enforceSessionMuted(call.ChannelID, rtcMsg.SessionID)
+			return nil
+		}
+
+		if rtcMsg.Type == rtc.VoiceOnMessage {
+			m.ctx.startNoiseDetection(call.ChannelID, call.ID, rtcMsg.SessionID, m.ctx.getCallNoiseAutoMute(call))
+		} else {
+			m.ctx.stopNoiseDetection(rtcMsg.SessionID)
+		}
+
 		m.ctx.publishWebSocketEvent(evType, map[string]interface{}{
 			"userID":     rtcMsg.UserID,
 			"session_id": rtcMsg.SessionID,
+			"call_id":    rtcMsg.CallID,
 		}, &WebSocketBroadcast{ChannelID: call.ChannelID, UserIDs: getUserIDsFromSessions(sessions)})
+		m.ctx.metrics.ObserveWebSocketWriterMessage(wsWriterMsgTypeVoiceActivity, len(rtcMsg.Data))
 
 		return nil
 	}
@@ -668,15 +830,29 @@ func (m *rtcdClientManager) handleClientMsg(msg rtcd.ClientMessage) error {
 		return fmt.Errorf("failed to find session by originalConnID: %s", rtcMsg.SessionID)
 	}
 
+	data, err := m.ctx.getConfiguration().filterSignalCandidates(us.iceLimiter, rtcMsg.Type, rtcMsg.Data)
+	if err != nil {
+		return fmt.Errorf("failed to filter signal candidates: %w", err)
+	}
+	if data == nil {
+		m.ctx.LogDebug("dropping ICE candidate over the per session limit", "sessionID", rtcMsg.SessionID)
+		return nil
+	}
+
 	m.ctx.publishWebSocketEvent(wsEventSignal, map[string]interface{}{
-		"data":   string(rtcMsg.Data),
+		"data":   string(data),
 		"connID": rtcMsg.SessionID,
 	}, &WebSocketBroadcast{ConnectionID: us.connID, ReliableClusterSend: true})
+	m.ctx.metrics.ObserveWebSocketWriterMessage(wsWriterMsgTypeSignaling, len(data))
 
 	return nil
 }
 
-func getDialFn(host, port string) rtcd.DialContextFn {
+func (m *rtcdClientManager) getDialFn(host, port string) rtcd.DialContextFn {
+	if m.proxyURL != nil {
+		return getProxyDialFn(m.proxyURL, net.JoinHostPort(host, port))
+	}
+
 	return func(ctx context.Context, network, _ string) (net.Conn, error) {
 		dialer := &net.Dialer{
 			Timeout: dialingTimeout,
@@ -711,6 +887,40 @@ func (m *rtcdClientManager) clientReader(client *rtcd.Client) {
 	}
 }
 
+// updateDrainingHosts syncs the draining state of the hosts with the drains
+// requested through the API, possibly on other nodes.
+func (m *rtcdClientManager) updateDrainingHosts() {
+	m.mut.RLock()
+	ips := make([]string, 0, len(m.hosts))
+	for ip := range m.hosts {
+		ips = append(ips, ip)
+	}
+	m.mut.RUnlock()
+
+	for _, ip := range ips {
+		drain, err := m.ctx.getNodeDrain(ip)
+		if err != nil {
+			m.ctx.LogError(err.Error(), "host", ip)
+			continue
+		}
+		m.setHostDraining(ip, drain != nil)
+	}
+}
+
+func (m *rtcdClientManager) setHostDraining(ip string, draining bool) {
+	host := m.getHost(ip)
+	if host == nil {
+		return
+	}
+
+	host.mut.Lock()
+	defer host.mut.Unlock()
+	if host.draining != draining {
+		m.ctx.LogDebug("updating host draining state", "host", ip, "draining", fmt.Sprintf("%t", draining))
+		host.draining = draining
+	}
+}
+
 func (h *rtcdHost) isFlagged() bool {
 	h.mut.RLock()
 	defer h.mut.RUnlock()
@@ -8,6 +8,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/mattermost/mattermost/server/public/model"
 	"github.com/mattermost/mattermost/server/public/plugin"
 
 	rtcd "github.com/mattermost/rtcd/service"
@@ -331,6 +332,176 @@ func TestGetHostForNewCall(t *testing.T) {
 	})
 }
 
+func TestRTCDClientManagerSend(t *testing.T) {
+	defaultBaseDelay := rtcdSendRetryBaseDelay
+	rtcdSendRetryBaseDelay = time.Millisecond
+	defer func() {
+		rtcdSendRetryBaseDelay = defaultBaseDelay
+	}()
+
+	mockAPI := &pluginMocks.MockAPI{}
+	mockMetrics := &rtcdMocks.MockMetrics{}
+	mockClient := &rtcdMocks.MockRTCDClient{}
+
+	defer mockAPI.AssertExpectations(t)
+	defer mockMetrics.AssertExpectations(t)
+	defer mockClient.AssertExpectations(t)
+
+	cfg := &configuration{}
+	cfg.SetDefaults()
+	cfg.RTCDSendMaxRetries = model.NewPointer(2)
+	cfg.RTCDSendTimeoutMs = model.NewPointer(1000)
+
+	m := &rtcdClientManager{
+		ctx: &Plugin{
+			MattermostPlugin: plugin.MattermostPlugin{
+				API: mockAPI,
+			},
+			configuration: cfg,
+			metrics:       mockMetrics,
+		},
+		hosts: map[string]*rtcdHost{
+			"127.0.0.1": {
+				ip:     "127.0.0.1",
+				client: mockClient,
+			},
+		},
+		closeCh: make(chan struct{}),
+	}
+
+	mockAPI.On("LogDebug", "failed to send message to rtcd, retrying", "origin", mock.AnythingOfType("string"),
+		"host", "127.0.0.1", "type", mock.AnythingOfType("string"), "buffering", mock.AnythingOfType("string"),
+		"err", "ws client is not initialized")
+
+	t.Run("empty host", func(t *testing.T) {
+		err := m.Send(rtcd.ClientMessage{Type: rtcd.ClientMessageRTC}, "")
+		require.EqualError(t, err, "host should not be empty")
+	})
+
+	t.Run("success", func(t *testing.T) {
+		msg := rtcd.ClientMessage{Type: rtcd.ClientMessageRTC}
+		mockClient.On("Send", msg).Return(nil).Once()
+
+		err := m.Send(msg, "127.0.0.1")
+		require.NoError(t, err)
+	})
+
+	t.Run("retried", func(t *testing.T) {
+		msg := rtcd.ClientMessage{Type: rtcd.ClientMessageLeave}
+		mockClient.On("Send", msg).Return(fmt.Errorf("ws client is not initialized")).Once()
+		mockClient.On("Send", msg).Return(nil).Once()
+		mockMetrics.On("IncRTCDMessageRetries", rtcd.ClientMessageLeave).Once()
+
+		err := m.Send(msg, "127.0.0.1")
+		require.NoError(t, err)
+	})
+
+	t.Run("dropped after max retries", func(t *testing.T) {
+		msg := rtcd.ClientMessage{Type: rtcd.ClientMessageLeave}
+		mockClient.On("Send", msg).Return(fmt.Errorf("ws client is not initialized")).Times(3)
+		mockMetrics.On("IncRTCDMessageRetries", rtcd.ClientMessageLeave).Twice()
+		mockMetrics.On("IncRTCDMessagesDropped", rtcd.ClientMessageLeave).Once()
+
+		err := m.Send(msg, "127.0.0.1")
+		require.EqualError(t, err, "ws client is not initialized")
+	})
+
+	t.Run("critical message buffered while reconnecting", func(t *testing.T) {
+		msg := rtcd.ClientMessage{Type: rtcd.ClientMessageJoin}
+		// More failures than the allowed retries while the client is disconnected.
+		mockClient.On("Send", msg).Return(fmt.Errorf("ws client is not initialized")).Times(4)
+		mockClient.On("Connected").Return(false).Times(4)
+		mockClient.On("Send", msg).Return(nil).Once()
+		mockMetrics.On("IncRTCDMessageRetries", rtcd.ClientMessageJoin).Times(4)
+
+		err := m.Send(msg, "127.0.0.1")
+		require.NoError(t, err)
+	})
+
+	t.Run("critical message dropped on timeout", func(t *testing.T) {
+		cfg.RTCDSendTimeoutMs = model.NewPointer(100)
+		defer func() {
+			cfg.RTCDSendTimeoutMs = model.NewPointer(1000)
+		}()
+
+		msg := rtcd.ClientMessage{Type: rtcd.ClientMessageJoin}
+		mockClient.On("Send", msg).Return(fmt.Errorf("ws client is not initialized"))
+		mockClient.On("Connected").Return(false)
+		mockMetrics.On("IncRTCDMessageRetries", rtcd.ClientMessageJoin)
+		mockMetrics.On("IncRTCDMessagesDropped", rtcd.ClientMessageJoin).Once()
+
+		start := time.Now()
+		err := m.Send(msg, "127.0.0.1")
+		require.EqualError(t, err, "timed out sending message: ws client is not initialized")
+		require.Less(t, time.Since(start), time.Second)
+	})
+}
+
+func TestRTCDClientManagerPingHosts(t *testing.T) {
+	mockAPI := &pluginMocks.MockAPI{}
+	mockMetrics := &rtcdMocks.MockMetrics{}
+	mockClientA := &rtcdMocks.MockRTCDClient{}
+	mockClientB := &rtcdMocks.MockRTCDClient{}
+	mockClientC := &rtcdMocks.MockRTCDClient{}
+
+	defer mockAPI.AssertExpectations(t)
+	defer mockMetrics.AssertExpectations(t)
+	defer mockClientA.AssertExpectations(t)
+	defer mockClientB.AssertExpectations(t)
+	defer mockClientC.AssertExpectations(t)
+
+	connectAt := time.Now().Add(-time.Minute)
+
+	m := &rtcdClientManager{
+		ctx: &Plugin{
+			MattermostPlugin: plugin.MattermostPlugin{
+				API: mockAPI,
+			},
+			metrics: mockMetrics,
+		},
+		hosts: map[string]*rtcdHost{
+			"127.0.0.1": {
+				ip:        "127.0.0.1",
+				client:    mockClientA,
+				connectAt: connectAt,
+			},
+			"127.0.0.2": {
+				ip:        "127.0.0.2",
+				client:    mockClientB,
+				connectAt: connectAt,
+			},
+			"127.0.0.3": {
+				ip:        "127.0.0.3",
+				client:    mockClientC,
+				connectAt: connectAt,
+			},
+		},
+	}
+
+	// Healthy host.
+	mockClientA.On("Connected").Return(true).Once()
+	mockClientA.On("GetSystemInfo").Return(rtcd.SystemInfo{}, nil).Once()
+	mockMetrics.On("SetRTCDLastPingTime", "127.0.0.1", mock.AnythingOfType("float64")).Once()
+	mockMetrics.On("SetRTCDConnectionAge", "127.0.0.1", mock.MatchedBy(func(age float64) bool {
+		return age >= 60
+	})).Once()
+
+	// Unreachable host.
+	mockClientB.On("Connected").Return(true).Once()
+	mockClientB.On("GetSystemInfo").Return(rtcd.SystemInfo{}, fmt.Errorf("connection refused")).Once()
+	mockAPI.On("LogWarn", "failed to ping rtcd host", "origin", mock.AnythingOfType("string"),
+		"host", "127.0.0.2", "err", "connection refused").Once()
+
+	// Reconnecting host is skipped.
+	mockClientC.On("Connected").Return(false).Once()
+
+	m.pingHosts()
+
+	require.False(t, m.hosts["127.0.0.1"].lastPingAt.IsZero())
+	require.True(t, m.hosts["127.0.0.2"].lastPingAt.IsZero())
+	require.True(t, m.hosts["127.0.0.3"].lastPingAt.IsZero())
+}
+
 func TestResolveURL(t *testing.T) {
 	ips, port, err := resolveURL("https://localhost:8045", time.Second)
 	require.NoError(t, err)
@@ -0,0 +1,54 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"errors"
+	"slices"
+
+	"github.com/mattermost/mattermost-plugin-calls/server/public"
+)
+
+var errScreenSlotsFull = errors.New("screen sharing slots are full")
+
+// getScreenSharingSessionIDs returns the sessions sharing their screen, in
+// the order they started.
+func getScreenSharingSessionIDs(props public.CallProps) []string {
+	if len(props.ScreenSharingSessionIDs) > 0 {
+		return props.ScreenSharingSessionIDs
+	}
+	if props.ScreenSharingSessionID != "" {
+		return []string{props.ScreenSharingSessionID}
+	}
+	return nil
+}
+
+// addScreenSession gives a screen sharing slot to the given session.
+// ScreenSharingSessionID is kept pointing to the first sharer so that clients
+// which only support a single share keep working.
+func addScreenSession(props *public.CallProps, sessionID string) {
+	props.ScreenSharingSessionIDs = append(getScreenSharingSessionIDs(*props), sessionID)
+	props.ScreenSharingSessionID = props.ScreenSharingSessionIDs[0]
+}
+
+// removeScreenSession frees the screen sharing slot of the given session. It
+// returns false if the session wasn't sharing.
+func removeScreenSession(props *public.CallProps, sessionID string) bool {
+	sessionIDs := getScreenSharingSessionIDs(*props)
+	if !slices.Contains(sessionIDs, sessionID) {
+		return false
+	}
+
+	props.ScreenSharingSessionIDs = slices.DeleteFunc(slices.Clone(sessionIDs), func(id string) bool {
+		return id == sessionID
+	})
+	props.ScreenSharingSessionID = ""
+	if len(props.ScreenSharingSessionIDs) > 0 {
+		props.ScreenSharingSessionID = props.ScreenSharingSessionIDs[0]
+	} else {
+		props.ScreenSharingSessionIDs = nil
+	}
+
+	return true
+}
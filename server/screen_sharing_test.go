@@ -0,0 +1,43 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost-plugin-calls/server/public"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScreenSharingSessions(t *testing.T) {
+	t.Run("single share", func(t *testing.T) {
+		// Calls started before multiple shares were supported only have
+		// ScreenSharingSessionID set.
+		props := public.CallProps{ScreenSharingSessionID: "sessionA"}
+		require.Equal(t, []string{"sessionA"}, getScreenSharingSessionIDs(props))
+
+		require.False(t, removeScreenSession(&props, "sessionB"))
+		require.True(t, removeScreenSession(&props, "sessionA"))
+		require.Empty(t, props.ScreenSharingSessionID)
+		require.Empty(t, getScreenSharingSessionIDs(props))
+	})
+
+	t.Run("multiple shares", func(t *testing.T) {
+		var props public.CallProps
+		addScreenSession(&props, "sessionA")
+		addScreenSession(&props, "sessionB")
+		require.Equal(t, "sessionA", props.ScreenSharingSessionID)
+		require.Equal(t, []string{"sessionA", "sessionB"}, getScreenSharingSessionIDs(props))
+
+		// The next sharer takes over the first slot.
+		require.True(t, removeScreenSession(&props, "sessionA"))
+		require.Equal(t, "sessionB", props.ScreenSharingSessionID)
+		require.Equal(t, []string{"sessionB"}, getScreenSharingSessionIDs(props))
+
+		require.True(t, removeScreenSession(&props, "sessionB"))
+		require.Empty(t, props.ScreenSharingSessionID)
+		require.Nil(t, props.ScreenSharingSessionIDs)
+	})
+}
@@ -0,0 +1,91 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Reasons for rejecting a client SDP message, used as metric labels.
+const (
+	sdpRejectReasonTooLarge  = "too_large"
+	sdpRejectReasonMalformed = "malformed"
+)
+
+var errSDPTooLarge = errors.New("sdp exceeds the maximum allowed size")
+
+// sdpRequiredFields are the session level fields every session description
+// must include (RFC 8866).
+var sdpRequiredFields = []byte{'o', 's', 't'}
+
+// validateSDPMessage performs basic structural validation of a client SDP
+// signaling message so that malformed session descriptions are rejected
+// before reaching the RTC server.
+func validateSDPMessage(data []byte) error {
+	var msg struct {
+		Type string `json:"type"`
+		SDP  string `json:"sdp"`
+	}
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return fmt.Errorf("failed to unmarshal SDP message: %w", err)
+	}
+
+	if msg.Type != "offer" && msg.Type != "answer" {
+		return fmt.Errorf("invalid SDP type %q", msg.Type)
+	}
+
+	if !strings.HasPrefix(msg.SDP, "v=0") {
+		return fmt.Errorf("invalid SDP: missing version")
+	}
+
+	found := map[byte]bool{}
+	var hasMedia bool
+	for i, line := range strings.Split(strings.TrimRight(msg.SDP, "\r\n"), "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		if len(line) < 2 || line[1] != '=' || line[0] < 'a' || line[0] > 'z' {
+			return fmt.Errorf("invalid SDP: malformed line %d", i+1)
+		}
+		if line[0] == 'm' {
+			hasMedia = true
+		} else if !hasMedia {
+			found[line[0]] = true
+		}
+	}
+
+	for _, field := range sdpRequiredFields {
+		if !found[field] {
+			return fmt.Errorf("invalid SDP: missing %c= field", field)
+		}
+	}
+
+	if !hasMedia {
+		return fmt.Errorf("invalid SDP: no media description")
+	}
+
+	return nil
+}
+
+// getSDPOfferICEUfrag returns the ICE username fragment of the given SDP
+// offer, or an empty string if the message isn't an offer or doesn't carry
+// one. A client changing it between offers is restarting ICE (RFC 8445).
+func getSDPOfferICEUfrag(data []byte) string {
+	var msg struct {
+		Type string `json:"type"`
+		SDP  string `json:"sdp"`
+	}
+	if err := json.Unmarshal(data, &msg); err != nil || msg.Type != "offer" {
+		return ""
+	}
+
+	for _, line := range strings.Split(msg.SDP, "\n") {
+		if ufrag, ok := strings.CutPrefix(strings.TrimSuffix(line, "\r"), "a=ice-ufrag:"); ok {
+			return ufrag
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,137 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateSDPMessage(t *testing.T) {
+	validSDP := "v=0\r\n" +
+		"o=- 4611731400430051336 2 IN IP4 127.0.0.1\r\n" +
+		"s=-\r\n" +
+		"t=0 0\r\n" +
+		"a=group:BUNDLE 0\r\n" +
+		"m=audio 9 UDP/TLS/RTP/SAVPF 111\r\n" +
+		"c=IN IP4 0.0.0.0\r\n" +
+		"a=rtpmap:111 opus/48000/2\r\n"
+
+	newMsg := func(sdpType, sdp string) []byte {
+		data, err := json.Marshal(map[string]string{"type": sdpType, "sdp": sdp})
+		require.NoError(t, err)
+		return data
+	}
+
+	tcs := []struct {
+		name string
+		data []byte
+		err  string
+	}{
+		{
+			name: "valid offer",
+			data: newMsg("offer", validSDP),
+		},
+		{
+			name: "valid answer without trailing CRLF",
+			data: newMsg("answer", strings.TrimSuffix(validSDP, "\r\n")),
+		},
+		{
+			name: "invalid JSON",
+			data: []byte("{"),
+			err:  "failed to unmarshal SDP message: unexpected end of JSON input",
+		},
+		{
+			name: "invalid type",
+			data: newMsg("rollback", validSDP),
+			err:  `invalid SDP type "rollback"`,
+		},
+		{
+			name: "empty",
+			data: newMsg("offer", ""),
+			err:  "invalid SDP: missing version",
+		},
+		{
+			name: "malformed line",
+			data: newMsg("offer", strings.Replace(validSDP, "s=-", "garbage", 1)),
+			err:  "invalid SDP: malformed line 3",
+		},
+		{
+			name: "missing origin",
+			data: newMsg("offer", strings.Replace(validSDP, "o=- 4611731400430051336 2 IN IP4 127.0.0.1\r\n", "", 1)),
+			err:  "invalid SDP: missing o= field",
+		},
+		{
+			name: "no media",
+			data: newMsg("offer", strings.Split(validSDP, "m=")[0]),
+			err:  "invalid SDP: no media description",
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateSDPMessage(tc.data)
+			if tc.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tc.err)
+			}
+		})
+	}
+}
+
+func TestUnpackSDPData(t *testing.T) {
+	pack := func(data []byte) []byte {
+		var buf bytes.Buffer
+		w := zlib.NewWriter(&buf)
+		_, err := w.Write(data)
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+		return buf.Bytes()
+	}
+
+	t.Run("within limit", func(t *testing.T) {
+		data := bytes.Repeat([]byte("a"), 1024)
+		unpacked, err := unpackSDPData(pack(data), 1024)
+		require.NoError(t, err)
+		require.Equal(t, data, unpacked)
+	})
+
+	t.Run("too large", func(t *testing.T) {
+		data := bytes.Repeat([]byte("a"), 1025)
+		_, err := unpackSDPData(pack(data), 1024)
+		require.ErrorIs(t, err, errSDPTooLarge)
+	})
+
+	t.Run("invalid data", func(t *testing.T) {
+		_, err := unpackSDPData([]byte("not compressed"), 1024)
+		require.Error(t, err)
+	})
+}
+
+func TestGetSDPOfferICEUfrag(t *testing.T) {
+	sdp := "v=0\r\n" +
+		"o=- 4611731400430051336 2 IN IP4 127.0.0.1\r\n" +
+		"s=-\r\n" +
+		"t=0 0\r\n" +
+		"m=audio 9 UDP/TLS/RTP/SAVPF 111\r\n" +
+		"a=ice-ufrag:Yq3t\r\n" +
+		"a=ice-pwd:Rk2CDUsC2NU4f4b7L5qLnTHd\r\n"
+
+	newMsg := func(sdpType, sdp string) []byte {
+		data, err := json.Marshal(map[string]string{"type": sdpType, "sdp": sdp})
+		require.NoError(t, err)
+		return data
+	}
+
+	require.Equal(t, "Yq3t", getSDPOfferICEUfrag(newMsg("offer", sdp)))
+	require.Empty(t, getSDPOfferICEUfrag(newMsg("answer", sdp)))
+	require.Empty(t, getSDPOfferICEUfrag(newMsg("offer", strings.Split(sdp, "a=ice-ufrag")[0])))
+	require.Empty(t, getSDPOfferICEUfrag([]byte("invalid")))
+}
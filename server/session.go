@@ -6,6 +6,8 @@ package main
 import (
 	"errors"
 	"fmt"
+	"slices"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -22,11 +24,22 @@ const (
 	msgChSize = 50
 )
 
-var errGroupCallsNotAllowed = fmt.Errorf("unlicensed servers only allow calls in DMs")
+var (
+	errGroupCallsNotAllowed      = fmt.Errorf("unlicensed servers only allow calls in DMs")
+	errMaxParticipantsReached    = fmt.Errorf("the maximum number of participants for this call has been reached: upgrade your plan or contact your system admin to increase the limit")
+	errMaxConcurrentCallsReached = fmt.Errorf("the maximum number of concurrent calls has been reached: upgrade your plan or contact your system admin to increase the limit")
+	errRTCDNotAllowed            = fmt.Errorf("new calls cannot be started as the license no longer allows the RTCD service: contact your system admin")
+)
+
+// The time a client has to leave the call on its own, after being removed or
+// replaced, before its session is forcibly closed.
+var sessionCloseGracePeriod = 3 * time.Second
 
 type session struct {
-	userID         string
-	channelID      string
+	userID string
+	// channelID can change while the session is ongoing (i.e. the call
+	// getting moved) so it needs to be accessed through getChannelID.
+	channelID      atomic.Pointer[string]
 	connID         string
 	originalConnID string
 	callID         string
@@ -58,14 +71,90 @@ type session struct {
 	// removed tracks whether the session was removed from state.
 	removed int32
 
+	// to notify that the client has established its ICE connection.
+	iceConnectedCh chan struct{}
+	iceConnected   int32
+	// the ICE username fragment of the last offer received from the client.
+	iceUfrag atomic.Pointer[string]
+
+	// the total bytes last reported through a media keepalive. Metric
+	// messages can be handled concurrently (i.e. from the plugin hook) so it
+	// must only be updated through recordMediaActivity.
+	mediaBytesReceived atomic.Int64
+	// the time (unix ms) media activity was last reported by the client. Zero
+	// if the client doesn't report media keepalives. Read by the media
+	// inactivity watcher.
+	mediaActivityAt atomic.Int64
+
+	// the connection quality last reported by the client. Metric messages
+	// can be handled concurrently (i.e. from the plugin hook) so it must be
+	// accessed while holding connQualityMut.
+	connQuality    connectionQualityState
+	connQualityMut sync.Mutex
+
 	// rate limiter for incoming WebSocket messages.
 	wsMsgLimiter *rate.Limiter
+	// rate limiter for in-call chat messages.
+	chatMsgLimiter *rate.Limiter
+	// rate limiter for the combined budget of chatty messages
+	// (i.e. reactions, chat, recording markers).
+	msgLimiter *rate.Limiter
+	// throttled tracks whether the session is currently exceeding its
+	// chatty messages budget.
+	throttled int32
+
+	// tracks the ICE candidates advertised to the session.
+	iceLimiter *iceCandidateLimiter
+
+	// The call props needed to process voice activity and captions, kept in
+	// memory to avoid hitting the store for every event. joinMuted doesn't
+	// change for the whole duration of the call while noiseAutoMute and
+	// liveCaptionsOff follow the host's changes.
+	joinMuted       atomic.Bool
+	noiseAutoMute   atomic.Pointer[public.CallNoiseAutoMute]
+	liveCaptionsOff atomic.Bool
+}
+
+// getChannelID returns the channel the session's call is in. Callers needing
+// it multiple times (e.g. to lock and later unlock the call) are expected to
+// read it once.
+func (us *session) getChannelID() string {
+	if channelID := us.channelID.Load(); channelID != nil {
+		return *channelID
+	}
+	return ""
+}
+
+func (us *session) setChannelID(channelID string) {
+	us.channelID.Store(&channelID)
+}
+
+// recordMediaActivity tracks the total bytes received as reported by the
+// client. Activity is only recorded if the received bytes keep growing. The
+// first report starts tracking the session.
+func (us *session) recordMediaActivity(bytesReceived int64) {
+	for {
+		prev := us.mediaBytesReceived.Load()
+		if bytesReceived <= prev && us.mediaActivityAt.Load() != 0 {
+			return
+		}
+		if us.mediaBytesReceived.CompareAndSwap(prev, max(prev, bytesReceived)) {
+			us.mediaActivityAt.Store(time.Now().UnixMilli())
+			return
+		}
+	}
+}
+
+// setCallProps keeps in memory the call props the session needs.
+func (us *session) setCallProps(props public.CallProps) {
+	us.joinMuted.Store(props.JoinMuted)
+	us.noiseAutoMute.Store(props.NoiseAutoMute)
+	us.liveCaptionsOff.Store(props.LiveCaptionsOff)
 }
 
 func newUserSession(userID, channelID, connID, callID string, rtc bool) *session {
-	return &session{
+	us := &session{
 		userID:         userID,
-		channelID:      channelID,
 		connID:         connID,
 		originalConnID: connID,
 		callID:         callID,
@@ -75,9 +164,155 @@ func newUserSession(userID, channelID, connID, callID string, rtc bool) *session
 		wsReconnectCh:  make(chan struct{}),
 		leaveCh:        make(chan struct{}),
 		rtcCloseCh:     make(chan struct{}),
+		iceConnectedCh: make(chan struct{}),
 		wsMsgLimiter:   rate.NewLimiter(10, 100),
+		chatMsgLimiter: rate.NewLimiter(chatMsgRateLimit, chatMsgBurst),
+		msgLimiter:     rate.NewLimiter(defaultSessionMessageRateLimit, defaultSessionMessageBurst),
+		iceLimiter:     newICECandidateLimiter(),
 		rtc:            rtc,
 	}
+	us.setChannelID(channelID)
+
+	return us
+}
+
+// allowSessionMessage enforces the combined budget of chatty messages for
+// the given session. Sessions exceeding it get their messages dropped until
+// the budget replenishes.
+func (p *Plugin) allowSessionMessage(us *session, msgType string) bool {
+	if us.msgLimiter.Allow() {
+		atomic.StoreInt32(&us.throttled, 0)
+		return true
+	}
+
+	// We only log and count once per throttling period to avoid flooding
+	// logs with the very messages we are trying to limit.
+	if atomic.CompareAndSwapInt32(&us.throttled, 0, 1) {
+		p.metrics.IncThrottledSessions()
+		p.LogWarn("session exceeded its messages budget, throttling", "msgType", msgType,
+			"userID", us.userID, "connID", us.connID, "callID", us.callID)
+	}
+
+	return false
+}
+
+// ICE connection states tracked through metrics. Comparing the two across ICE
+// modes gives the connection success rate of each.
+const (
+	iceConnectionStateNew       = "new"
+	iceConnectionStateConnected = "connected"
+)
+
+// getICEConnectionTimeout returns how long the joining session has to
+// establish its ICE connection, zero meaning it's not enforced. The bot and
+// clients that don't report when they are connected are exempt.
+func (p *Plugin) getICEConnectionTimeout(userID string, joinData CallsClientJoinData) time.Duration {
+	cfg := p.getConfiguration()
+	if userID == p.getBotID() || !joinData.ICEConnectionReports || cfg.ICEConnectionTimeoutSeconds == nil {
+		return 0
+	}
+	return time.Duration(*cfg.ICEConnectionTimeoutSeconds) * time.Second
+}
+
+// iceConnectionTimeoutWatcher aborts the join if the session fails to establish
+// its ICE connection within the given timeout. This prevents clients on
+// misconfigured networks from hanging indefinitely while connecting.
+func (p *Plugin) iceConnectionTimeoutWatcher(us *session, timeout time.Duration) {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+	case <-us.iceConnectedCh:
+		return
+	case <-us.leaveCh:
+		return
+	case <-us.wsCloseCh:
+		return
+	case <-us.wsReconnectCh:
+		return
+	case <-us.rtcCloseCh:
+		return
+	case <-p.stopCh:
+		return
+	}
+
+	p.LogWarn("ICE connection timed out, aborting join",
+		"userID", us.userID, "connID", us.connID, "channelID", us.getChannelID(), "callID", us.callID, "timeout", timeout.String())
+	p.metrics.IncICEConnectionTimeouts()
+
+	p.publishWebSocketEvent(wsEventError, map[string]interface{}{
+		"data":   "ICE connection timed out: unable to establish a media connection with the server",
+		"connID": us.connID,
+	}, &WebSocketBroadcast{ConnectionID: us.connID, ReliableClusterSend: true})
+
+	if atomic.CompareAndSwapInt32(&us.left, 0, 1) {
+		close(us.leaveCh)
+	}
+}
+
+// How often the media inactivity watcher checks for activity.
+var mediaInactivityCheckInterval = 5 * time.Second
+
+// mediaInactivityWatcher disconnects the session if the client stops reporting
+// media activity for longer than the given timeout while its WebSocket
+// connection is still alive. Without this, a session whose media path is dead
+// would linger in the call indefinitely, appearing present to others.
+// If iceRestartTimeout is set, the client is first asked to restart ICE every
+// time that long passes without activity, so that connections broken by a
+// network change can recover in place. A zero timeout only does the latter.
+func (p *Plugin) mediaInactivityWatcher(us *session, timeout, iceRestartTimeout time.Duration) {
+	ticker := time.NewTicker(mediaInactivityCheckInterval)
+	defer ticker.Stop()
+
+	var iceRestartAt time.Time
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-us.leaveCh:
+			return
+		case <-us.wsCloseCh:
+			return
+		case <-us.wsReconnectCh:
+			return
+		case <-us.rtcCloseCh:
+			return
+		case <-p.stopCh:
+			return
+		}
+
+		// Clients not reporting media keepalives are never flagged.
+		activityAt := us.mediaActivityAt.Load()
+		if activityAt == 0 {
+			continue
+		}
+
+		inactiveFor := time.Since(time.UnixMilli(activityAt))
+		if timeout == 0 || inactiveFor < timeout {
+			if iceRestartTimeout > 0 && inactiveFor >= iceRestartTimeout && time.Since(iceRestartAt) >= iceRestartTimeout {
+				iceRestartAt = time.Now()
+				p.requestICERestart(us)
+			}
+			continue
+		}
+
+		p.LogWarn("no media activity from session, disconnecting",
+			"userID", us.userID, "connID", us.connID, "channelID", us.getChannelID(), "callID", us.callID, "timeout", timeout.String())
+		p.metrics.IncZombieSessions()
+		p.setRejoinCooldown(us.getChannelID(), us.userID, rejoinCooldownReasonDropped)
+
+		p.publishWebSocketEvent(wsEventError, map[string]interface{}{
+			"data":   "media connection lost: no activity received from the client",
+			"connID": us.connID,
+		}, &WebSocketBroadcast{ConnectionID: us.connID, ReliableClusterSend: true})
+
+		if atomic.CompareAndSwapInt32(&us.left, 0, 1) {
+			close(us.leaveCh)
+		}
+
+		return
+	}
 }
 
 func (p *Plugin) addUserSession(state *callState, callsEnabled *bool, userID, connID, channelID, jobID string, ct model.ChannelType) (retState *callState, retErr error) {
@@ -114,6 +349,16 @@ func (p *Plugin) addUserSession(state *callState, callsEnabled *bool, userID, co
 			}
 			return nil, err
 		}
+
+		if err := p.newCallAllowed(); err != nil {
+			return nil, err
+		}
+
+		// With the embedded RTC server calls are hosted by the node the
+		// first participant is connected to.
+		if p.rtcdManager == nil && p.isNodeDraining(p.nodeID) {
+			return nil, errNodeDraining
+		}
 	}
 
 	if state == nil {
@@ -125,12 +370,17 @@ func (p *Plugin) addUserSession(state *callState, callsEnabled *bool, userID, co
 				OwnerID:   userID,
 				ChannelID: channelID,
 				Props: public.CallProps{
-					NodeID: p.nodeID,
+					NodeID:               p.nodeID,
+					HostAssignmentPolicy: p.getConfiguration().HostAssignmentPolicy,
 				},
 			},
 			sessions: map[string]*public.CallSession{},
 		}
 
+		if ct == model.ChannelTypeGroup {
+			state.Call.Props.MaxParticipants = p.getConfiguration().maxParticipantsForChannelType(ct)
+		}
+
 		if p.rtcdManager != nil {
 			host, err := p.rtcdManager.GetHostForNewCall()
 			if err != nil {
@@ -150,11 +400,8 @@ func (p *Plugin) addUserSession(state *callState, callsEnabled *bool, userID, co
 	}
 
 	// Check for license limits -- needs to be done here to prevent a race condition
-	if allowed, err := p.joinAllowed(state); !allowed {
-		if err != nil {
-			p.LogError("joinAllowed failed", "error", err.Error())
-		}
-		return nil, fmt.Errorf("user cannot join because of limits")
+	if err := p.joinAllowed(state); err != nil {
+		return nil, err
 	}
 
 	// When the bot joins the call it means a job (recording, transcription) is
@@ -172,6 +419,14 @@ func (p *Plugin) addUserSession(state *callState, callsEnabled *bool, userID, co
 				state.Recording.Props.BotConnID = ""
 				return nil, fmt.Errorf("failed to update call job: %w", err)
 			}
+		} else if profileJob := state.getProfileRecording(jobID); profileJob != nil && profileJob.StartAt == 0 && profileJob.EndAt == 0 {
+			p.LogDebug("bot joined, profile recording job is starting", "jobID", jobID, "profile", profileJob.Props.Profile)
+			profileJob.Props.BotConnID = connID
+
+			if err := p.store.UpdateCallJob(profileJob); err != nil {
+				profileJob.Props.BotConnID = ""
+				return nil, fmt.Errorf("failed to update call job: %w", err)
+			}
 		} else if state.Transcription != nil && state.Transcription.ID == jobID && state.Transcription.StartAt == 0 {
 			p.LogDebug("bot joined, transcribing job is starting", "jobID", jobID)
 			state.Transcription.Props.BotConnID = connID
@@ -196,6 +451,44 @@ func (p *Plugin) addUserSession(state *callState, callsEnabled *bool, userID, co
 		}
 	}
 
+	// Depending on the configured policy, a user joining from another device
+	// replaces their existing sessions. Host role and screen sharing are
+	// handled as part of the replaced sessions leaving, at which point the
+	// user is still in the call through the new session.
+	if userID != p.getBotID() && p.getConfiguration().replaceSessionsOnJoin() {
+		if replacedSessionIDs := state.getUserSessionIDs(userID); len(replacedSessionIDs) > 0 {
+			defer func() {
+				if retErr == nil {
+					for _, sessionID := range replacedSessionIDs {
+						p.replaceUserSession(userID, channelID, state.Call.ID, sessionID)
+					}
+				}
+			}()
+		}
+	}
+
+	if userID != p.getBotID() && state.Call.Props.HostAssignmentPolicy == hostAssignmentPolicyChannelAdmin {
+		if cm, appErr := p.API.GetChannelMember(channelID, userID); appErr != nil {
+			p.LogError("failed to get channel member", "err", appErr.Error(), "userID", userID, "channelID", channelID)
+		} else if cm.SchemeAdmin {
+			if state.Call.Props.ChannelAdmins == nil {
+				state.Call.Props.ChannelAdmins = map[string]struct{}{}
+			}
+			state.Call.Props.ChannelAdmins[userID] = struct{}{}
+		}
+	}
+
+	// Someone joining an empty call keeps it going, so any warning about it
+	// ending no longer applies.
+	if userID != p.getBotID() && state.Call.Props.LastParticipantLeftAt > 0 &&
+		state.recordingGracePeriodActive(state.Call.ID, state.Call.Props.LastParticipantLeftAt, p.getBotID()) {
+		defer func() {
+			if retErr == nil {
+				p.publishCallEndWarningCanceled(state.Call.ID, channelID)
+			}
+		}()
+	}
+
 	state.sessions[connID] = &public.CallSession{
 		ID:     connID,
 		CallID: state.Call.ID,
@@ -215,6 +508,7 @@ func (p *Plugin) addUserSession(state *callState, callsEnabled *bool, userID, co
 					ReliableClusterSend: true,
 					UserIDs:             getUserIDsFromSessions(state.sessions),
 				})
+				p.recordCallTimelineEvent(state.Call.ID, callTimelineEventHostChange, newHostID, "", "")
 			}
 		}()
 	}
@@ -227,6 +521,20 @@ func (p *Plugin) addUserSession(state *callState, callsEnabled *bool, userID, co
 		state.Call.Props.Participants[userID] = struct{}{}
 	}
 
+	defer func() {
+		if retErr == nil {
+			p.publishParticipantEvent(public.ParticipantEventTypeJoin, channelID, state.Call.ID, userID, connID, state.Call.Props.Metadata)
+			p.recordCallTimelineEvent(state.Call.ID, callTimelineEventJoin, userID, connID, "")
+			if userID != p.getBotID() {
+				go func(callID string) {
+					if err := p.enableFocusMode(userID, callID); err != nil {
+						p.LogError("failed to enable focus mode", "err", err.Error(), "userID", userID)
+					}
+				}(state.Call.ID)
+			}
+		}
+	}()
+
 	if len(state.sessions) == 1 {
 		if err := p.store.CreateCall(&state.Call); err != nil {
 			return nil, fmt.Errorf("failed to create call: %w", err)
@@ -246,7 +554,7 @@ func (p *Plugin) addUserSession(state *callState, callsEnabled *bool, userID, co
 func (p *Plugin) userCanStartOrJoin(userID string, enabled *bool, channelType model.ChannelType) error {
 	// (since v1) Calls can only be started/joined in DMs in unlicensed servers.
 	// If calls are disabled, no-one can start or join.
-	// If explicitly enabled, everyone can start or join.
+	// If explicitly enabled (channel setting or, lacking one, team setting), everyone can start or join.
 	// If not explicitly enabled and default enabled, everyone can join or start
 	// otherwise (not explicitly enabled and not default enabled), only sysadmins can start
 	// TODO: look to see what logic we should lift to the joinCall fn
@@ -260,6 +568,9 @@ func (p *Plugin) userCanStartOrJoin(userID string, enabled *bool, channelType mo
 	explicitlyEnabled := enabled != nil && *enabled
 	explicitlyDisabled := enabled != nil && !*enabled
 	defaultEnabled := cfg.DefaultEnabled != nil && *cfg.DefaultEnabled
+	if typeEnabled := cfg.getChannelTypeCallsEnabled(channelType); typeEnabled != nil {
+		defaultEnabled = *typeEnabled
+	}
 
 	if explicitlyDisabled {
 		return fmt.Errorf("calls are disabled in the channel")
@@ -279,6 +590,63 @@ func (p *Plugin) userCanStartOrJoin(userID string, enabled *bool, channelType mo
 	return fmt.Errorf("insufficient permissions")
 }
 
+// shouldJoinMuted returns whether participants of a call started in the given
+// channel should join muted. The channel setting, if any, takes precedence over
+// the global one.
+func (p *Plugin) shouldJoinMuted(callsChannel *public.CallsChannel) bool {
+	if callsChannel != nil {
+		if joinMuted, ok := callsChannel.Props["join_muted"].(bool); ok {
+			return joinMuted
+		}
+	}
+	cfg := p.getConfiguration()
+	return cfg.JoinMuted != nil && *cfg.JoinMuted
+}
+
+// replaceUserSession notifies the given session that the user has joined the
+// call from another device so that the client can leave. The session is
+// forcibly closed if it's still connected once the grace period has elapsed.
+func (p *Plugin) replaceUserSession(userID, channelID, callID, sessionID string) {
+	p.LogDebug("replacing session", "userID", userID, "sessionID", sessionID, "callID", callID)
+
+	p.publishWebSocketEvent(wsEventSessionReplaced, map[string]interface{}{
+		"call_id":    callID,
+		"channel_id": channelID,
+		"session_id": sessionID,
+	}, &WebSocketBroadcast{UserID: userID, ReliableClusterSend: true})
+
+	go p.closeSessionAfterGracePeriod(channelID, sessionID)
+}
+
+// closeSessionAfterGracePeriod waits for the client to end the session
+// cleanly. If it doesn't (like for an older mobile client) the session is
+// forcibly ended.
+func (p *Plugin) closeSessionAfterGracePeriod(channelID, sessionID string) {
+	select {
+	case <-time.After(sessionCloseGracePeriod):
+	case <-p.stopCh:
+		return
+	}
+
+	state, err := p.getCallState(channelID, false)
+	if err != nil {
+		p.LogError("closeSessionAfterGracePeriod: failed to get call state", "err", err.Error())
+	}
+
+	if state == nil {
+		return
+	}
+
+	ust, ok := state.sessions[sessionID]
+	if !ok {
+		return
+	}
+
+	if err := p.closeRTCSession(ust.UserID, sessionID, channelID, state.Call.Props.NodeID, state.Call.ID); err != nil {
+		p.LogError("closeSessionAfterGracePeriod: failed to close RTC session", "err", err.Error())
+	}
+}
+
 func (p *Plugin) removeUserSession(state *callState, userID, originalConnID, connID, channelID string) error {
 	defer func(start time.Time) {
 		p.metrics.ObserveAppHandlersTime("removeUserSession", time.Since(start).Seconds())
@@ -296,17 +664,45 @@ func (p *Plugin) removeUserSession(state *callState, userID, originalConnID, con
 		return fmt.Errorf("failed to delete call session: %w", err)
 	}
 	delete(state.sessions, originalConnID)
-	p.LogDebug("session was removed from state", "userID", userID, "connID", connID, "originalConnID", originalConnID)
+	p.publishParticipantEvent(public.ParticipantEventTypeLeave, channelID, state.Call.ID, userID, originalConnID, state.Call.Props.Metadata)
+	p.recordCallTimelineEvent(state.Call.ID, callTimelineEventLeave, userID, originalConnID, "")
+	if userID != p.getBotID() {
+		go func(callID string) {
+			if err := p.disableFocusMode(userID, callID); err != nil {
+				p.LogError("failed to disable focus mode", "err", err.Error(), "userID", userID)
+			}
+		}(state.Call.ID)
+	}
+	p.LogDebug("session was removed from state", "userID", userID, "connID", connID, "originalConnID", originalConnID, "callID", state.Call.ID)
 
 	// Check if leaving session was screen sharing.
-	if state.Call.Props.ScreenSharingSessionID == originalConnID {
-		state.Call.Props.ScreenSharingSessionID = ""
-		if state.Call.Props.ScreenStartAt > 0 {
+	if removeScreenSession(&state.Call.Props, originalConnID) {
+		if state.Call.Props.ScreenSharingSessionID == "" && state.Call.Props.ScreenStartAt > 0 {
 			state.Call.Stats.ScreenDuration += secondsSinceTimestamp(state.Call.Props.ScreenStartAt)
 			state.Call.Props.ScreenStartAt = 0
 		}
-		p.LogDebug("removed session was sharing, sending screen off event", "userID", userID, "connID", connID, "originalConnID", originalConnID)
-		p.publishWebSocketEvent(wsEventUserScreenOff, map[string]interface{}{}, &WebSocketBroadcast{
+		p.LogDebug("removed session was sharing, sending screen off event", "userID", userID, "connID", connID, "originalConnID", originalConnID, "callID", state.Call.ID)
+		p.publishWebSocketEvent(wsEventUserScreenOff, map[string]interface{}{
+			"call_id":                    state.Call.ID,
+			"session_id":                 originalConnID,
+			"screen_sharing_session_ids": getScreenSharingSessionIDs(state.Call.Props),
+		}, &WebSocketBroadcast{
+			ChannelID:           channelID,
+			ReliableClusterSend: true,
+			UserIDs:             getUserIDsFromSessions(state.sessions),
+		})
+	}
+
+	// Check if leaving session was publishing video.
+	if slices.Contains(state.Call.Props.VideoSessionIDs, originalConnID) {
+		state.Call.Props.VideoSessionIDs = removeVideoSession(state.Call.Props.VideoSessionIDs, originalConnID)
+		p.publishWebSocketEvent(wsEventUserVideoOff, map[string]interface{}{
+			"userID":               userID,
+			"session_id":           originalConnID,
+			"call_id":              state.Call.ID,
+			"video_publishers":     len(state.Call.Props.VideoSessionIDs),
+			"max_video_publishers": p.getConfiguration().getMaxVideoPublishers(),
+		}, &WebSocketBroadcast{
 			ChannelID:           channelID,
 			ReliableClusterSend: true,
 			UserIDs:             getUserIDsFromSessions(state.sessions),
@@ -314,28 +710,16 @@ func (p *Plugin) removeUserSession(state *callState, userID, originalConnID, con
 	}
 
 	// If the bot is the only user left in the call we automatically stop any
-	// ongoing jobs.
+	// ongoing jobs, unless a grace period for the recording is configured.
 	if state.onlyUserLeft(p.getBotID()) {
-		p.LogDebug("all users left call with job(s) in progress, stopping", "channelID", channelID)
-
-		if state.Recording != nil {
-			p.LogDebug("stopping ongoing recording", "jobID", state.Recording.Props.JobID, "botConnID", state.Recording.Props.BotConnID)
-			if err := p.getJobService().StopJob(channelID, state.Recording.ID, p.getBotID(), state.Recording.Props.BotConnID); err != nil {
-				p.LogError("failed to stop recording job", "error", err.Error(),
-					"channelID", channelID,
-					"jobID", state.Recording.Props.JobID,
-					"botConnID", state.Recording.Props.BotConnID)
-			}
-		}
-
-		if state.Transcription != nil {
-			p.LogDebug("stopping ongoing transcription", "jobID", state.Transcription.Props.JobID, "botConnID", state.Transcription.Props.BotConnID)
-			if err := p.getJobService().StopJob(channelID, state.Transcription.ID, p.getBotID(), state.Transcription.Props.BotConnID); err != nil {
-				p.LogError("failed to stop transcribing job", "error", err.Error(),
-					"channelID", channelID,
-					"jobID", state.Transcription.Props.JobID,
-					"botConnID", state.Transcription.Props.BotConnID)
-			}
+		gracePeriod := p.getConfiguration().recordingEmptyCallGracePeriod()
+		if gracePeriod > 0 && state.Recording != nil && state.Recording.EndAt == 0 {
+			p.LogDebug("all users left call with recording in progress, waiting for grace period",
+				"channelID", channelID, "callID", state.Call.ID, "gracePeriod", gracePeriod.String())
+			state.Call.Props.LastParticipantLeftAt = time.Now().UnixMilli()
+			go p.emptyCallJobsStopper(channelID, state.Call.ID, state.Call.Props.LastParticipantLeftAt, gracePeriod)
+		} else {
+			p.stopEmptyCallJobs(state, channelID)
 		}
 	}
 
@@ -345,12 +729,14 @@ func (p *Plugin) removeUserSession(state *callState, userID, originalConnID, con
 		p.LogDebug("recording bot left the call", "channelID", channelID, "jobID", state.Recording.Props.JobID, "botConnID", originalConnID)
 
 		state.Recording.EndAt = time.Now().UnixMilli()
-		if err := p.store.UpdateCallJob(state.Recording); err != nil {
+		if err := p.updateCallJobBuffered(state.Recording); err != nil {
 			return fmt.Errorf("failed to update call job: %w", err)
 		}
 
 		// Since MM-52346 we don't need to explicitly stop the recording here as
 		// the bot leaving the call will implicitly terminate the recording process.
+		p.stopProfileRecordingJobs(state, channelID)
+
 		if state.Transcription != nil && state.Transcription.EndAt == 0 {
 			p.LogDebug("attempting to stop transcribing job", "channelID", channelID, "jobID", state.Transcription.Props.JobID)
 			if err := p.stopTranscribingJob(state, channelID); err != nil {
@@ -368,11 +754,24 @@ func (p *Plugin) removeUserSession(state *callState, userID, originalConnID, con
 		})
 	}
 
+	// A bot recording an additional quality profile leaving only affects its
+	// own job.
+	for _, profileJob := range state.ProfileRecordings {
+		if profileJob.EndAt != 0 || originalConnID != profileJob.Props.BotConnID {
+			continue
+		}
+		p.LogDebug("profile recording bot left the call", "channelID", channelID, "jobID", profileJob.Props.JobID, "botConnID", originalConnID)
+		profileJob.EndAt = time.Now().UnixMilli()
+		if err := p.updateCallJobBuffered(profileJob); err != nil {
+			return fmt.Errorf("failed to update call job: %w", err)
+		}
+	}
+
 	if state.Transcription != nil && state.Transcription.EndAt == 0 && originalConnID == state.Transcription.Props.BotConnID {
 		p.LogDebug("transcribing bot left the call", "channelID", channelID, "jobID", state.Transcription.Props.JobID, "botConnID", originalConnID)
 
 		state.Transcription.EndAt = time.Now().UnixMilli()
-		if err := p.store.UpdateCallJob(state.Transcription); err != nil {
+		if err := p.updateCallJobBuffered(state.Transcription); err != nil {
 			return fmt.Errorf("failed to update call job: %w", err)
 		}
 
@@ -406,32 +805,23 @@ func (p *Plugin) removeUserSession(state *callState, userID, originalConnID, con
 
 	if state.LiveCaptions != nil && state.LiveCaptions.EndAt == 0 && connID == state.LiveCaptions.Props.BotConnID {
 		state.LiveCaptions.EndAt = time.Now().UnixMilli()
-		if err := p.store.UpdateCallJob(state.LiveCaptions); err != nil {
+		if err := p.updateCallJobBuffered(state.LiveCaptions); err != nil {
 			return fmt.Errorf("failed to update call job: %w", err)
 		}
 	}
 
+	_, atCapacity := p.getCallCapacity(state)
 	p.publishWebSocketEvent(wsEventUserLeft, map[string]interface{}{
-		"user_id":    userID,
-		"session_id": originalConnID,
+		"user_id":     userID,
+		"session_id":  originalConnID,
+		"call_id":     state.Call.ID,
+		"at_capacity": atCapacity,
 	}, &WebSocketBroadcast{ChannelID: channelID, ReliableClusterSend: true})
 
 	// Change host if needed
 	if state.Call.GetHostID() == userID && len(state.sessions) > 0 {
 		if newHostID := state.getHostID(p.getBotID()); newHostID != userID {
-			if newHostID == "" {
-				state.Call.Props.Hosts = nil
-			} else {
-				state.Call.Props.Hosts = []string{newHostID}
-			}
-			p.publishWebSocketEvent(wsEventCallHostChanged, map[string]interface{}{
-				"hostID":  newHostID,
-				"call_id": state.Call.ID,
-			}, &WebSocketBroadcast{
-				ChannelID:           channelID,
-				ReliableClusterSend: true,
-				UserIDs:             getUserIDsFromSessions(state.sessions),
-			})
+			p.updateCallHost(state, channelID, newHostID)
 		}
 	}
 
@@ -440,7 +830,7 @@ func (p *Plugin) removeUserSession(state *callState, userID, originalConnID, con
 		if state.Call.Props.ScreenStartAt > 0 {
 			state.Call.Stats.ScreenDuration += secondsSinceTimestamp(state.Call.Props.ScreenStartAt)
 		}
-		setCallEnded(&state.Call)
+		setCallEnded(&state.Call, public.CallEndReasonAllLeft)
 
 		defer func() {
 			_, err := p.updateCallPostEnded(state.Call.PostID, mapKeys(state.Call.Props.Participants))
@@ -454,22 +844,72 @@ func (p *Plugin) removeUserSession(state *callState, userID, originalConnID, con
 		return fmt.Errorf("failed to update call: %w", err)
 	}
 
+	if state.Call.EndAt > 0 {
+		p.publishCallEnd(&state.Call)
+	}
+
 	return nil
 }
 
-// JoinAllowed returns true if the user is allowed to join the call, taking into
-// account license and configuration limits
-func (p *Plugin) joinAllowed(state *callState) (bool, error) {
-	// Rules are:
-	// Cloud Starter: channels, dm/gm: limited to cfg.cloudStarterMaxParticipantsDefault
-	// On-prem, Cloud Professional & Cloud Enterprise (incl. trial): DMs 1-1, GMs and Channel calls
-	// limited to cfg.cloudPaidMaxParticipantsDefault people.
-	// This is set in the override defaults, so MaxCallParticipants will be accurate for the current license.
-	if cfg := p.getConfiguration(); cfg != nil && cfg.MaxCallParticipants != nil &&
-		*cfg.MaxCallParticipants != 0 && len(state.sessions) >= *cfg.MaxCallParticipants {
-		return false, nil
-	}
-	return true, nil
+// stopEmptyCallJobs stops any ongoing job for a call that has no participants
+// left. The recording gets finalized as usual as the bot leaving the call
+// terminates the recording process.
+func (p *Plugin) stopEmptyCallJobs(state *callState, channelID string) {
+	p.LogDebug("all users left call with job(s) in progress, stopping", "channelID", channelID)
+
+	if state.Recording != nil {
+		p.LogDebug("stopping ongoing recording", "jobID", state.Recording.Props.JobID, "botConnID", state.Recording.Props.BotConnID)
+		if err := p.getJobService().StopJob(channelID, state.Recording.ID, p.getBotID(), state.Recording.Props.BotConnID); err != nil {
+			p.LogError("failed to stop recording job", "error", err.Error(),
+				"channelID", channelID,
+				"jobID", state.Recording.Props.JobID,
+				"botConnID", state.Recording.Props.BotConnID)
+		}
+	}
+
+	p.stopProfileRecordingJobs(state, channelID)
+
+	if state.Transcription != nil {
+		p.LogDebug("stopping ongoing transcription", "jobID", state.Transcription.Props.JobID, "botConnID", state.Transcription.Props.BotConnID)
+		if err := p.getJobService().StopJob(channelID, state.Transcription.ID, p.getBotID(), state.Transcription.Props.BotConnID); err != nil {
+			p.LogError("failed to stop transcribing job", "error", err.Error(),
+				"channelID", channelID,
+				"jobID", state.Transcription.Props.JobID,
+				"botConnID", state.Transcription.Props.BotConnID)
+		}
+	}
+}
+
+// emptyCallJobsStopper stops any ongoing jobs once the recording grace period
+// has elapsed, provided nobody rejoined the call in the meantime. Users are
+// warned ahead of time, if configured.
+func (p *Plugin) emptyCallJobsStopper(channelID, callID string, leftAt int64, gracePeriod time.Duration) {
+	if !p.warnEmptyCallEnd(channelID, callID, leftAt, gracePeriod) {
+		return
+	}
+
+	select {
+	case <-time.After(time.Until(time.UnixMilli(leftAt).Add(gracePeriod))):
+	case <-p.stopCh:
+		return
+	}
+
+	state, err := p.lockCallReturnState(channelID)
+	if err != nil {
+		p.LogError("failed to lock call", "err", err.Error(), "channelID", channelID, "callID", callID)
+		return
+	}
+	defer p.unlockCall(channelID)
+
+	// The call could have ended already or someone could have rejoined (and
+	// possibly left again, restarting the grace period).
+	if !state.recordingGracePeriodActive(callID, leftAt, p.getBotID()) {
+		return
+	}
+
+	p.LogDebug("recording grace period elapsed", "channelID", channelID, "callID", callID)
+
+	p.stopEmptyCallJobs(state, channelID)
 }
 
 func (p *Plugin) removeSession(us *session) error {
@@ -490,13 +930,16 @@ func (p *Plugin) removeSession(us *session) error {
 		p.LogError("failed to get call sessions count", "callID", us.callID, "err", err.Error())
 	}
 
+	// The session's channel can change if the call is moved so we read it
+	// once for locking and unlocking the same call.
+	channelID := us.getChannelID()
+
 	removeSessionFromCall := func(state *callState) {
 		p.LogDebug("removing session from state", "userID", us.userID, "connID", us.connID, "originalConnID", us.originalConnID)
 
 		p.mut.Lock()
 		delete(p.sessions, us.connID)
 
-		channelID := us.channelID
 		callID := us.callID
 
 		// If all locally stored sessions for the call have been removed, we should stop any associated batcher.
@@ -527,32 +970,32 @@ func (p *Plugin) removeSession(us *session) error {
 		}
 		p.mut.Unlock()
 
-		if err := p.removeUserSession(state, us.userID, us.originalConnID, us.connID, us.channelID); err != nil {
+		if err := p.removeUserSession(state, us.userID, us.originalConnID, us.connID, channelID); err != nil {
 			p.LogError("failed to remove user session ", "originalConnID", us.originalConnID, "err", err.Error())
 		}
 	}
 
 	p.mut.Lock()
-	batcher := p.removeSessionsBatchers[us.channelID]
+	batcher := p.removeSessionsBatchers[channelID]
 	shouldBatch := batcher != nil || sessionsCount >= minMembersCountForBatching
 	if shouldBatch {
 		defer p.mut.Unlock()
 		p.LogDebug("will batch sessions leaving operations",
-			"channelID", us.channelID,
+			"channelID", channelID,
 			"sessionsCount", sessionsCount,
 			"threshold", minMembersCountForBatching,
 		)
 		var err error
 		if batcher == nil {
-			p.LogDebug("creating new removeSessionsBatcher for call", "channelID", us.channelID, "batchMaxSize", sessionsCount)
+			p.LogDebug("creating new removeSessionsBatcher for call", "channelID", channelID, "batchMaxSize", sessionsCount)
 
 			batcher, err = newBatcher(batching.Config{
 				Interval: joinLeaveBatchingInterval,
 				Size:     sessionsCount,
 				PreRunCb: func(ctx batching.Context) error {
-					p.LogDebug("performing removeSessionFromCall batch", "channelID", us.channelID, "batchSize", ctx[batching.ContextBatchSizeKey])
+					p.LogDebug("performing removeSessionFromCall batch", "channelID", channelID, "batchSize", ctx[batching.ContextBatchSizeKey])
 
-					state, err := p.lockCallReturnState(us.channelID)
+					state, err := p.lockCallReturnState(channelID)
 					if err != nil {
 						return fmt.Errorf("failed to lock call: %w", err)
 					}
@@ -561,14 +1004,14 @@ func (p *Plugin) removeSession(us *session) error {
 					return nil
 				},
 				PostRunCb: func(_ batching.Context) error {
-					p.unlockCall(us.channelID)
+					p.unlockCall(channelID)
 					return nil
 				},
 			})
 			if err != nil {
 				return fmt.Errorf("failed to create batcher: %w", err)
 			}
-			p.removeSessionsBatchers[us.channelID] = batcher
+			p.removeSessionsBatchers[channelID] = batcher
 			batcher.Start()
 		}
 
@@ -586,17 +1029,17 @@ func (p *Plugin) removeSession(us *session) error {
 	p.mut.Unlock()
 
 	p.LogDebug("no need to batch sessions leaving operations",
-		"channelID", us.channelID,
+		"channelID", channelID,
 		"sessionsCount", sessionsCount,
 		"threshold", minMembersCountForBatching,
 	)
 
-	state, err := p.lockCallReturnState(us.channelID)
+	state, err := p.lockCallReturnState(channelID)
 	if err != nil {
 		return fmt.Errorf("failed to lock call: %w", err)
 	}
 	removeSessionFromCall(state)
-	p.unlockCall(us.channelID)
+	p.unlockCall(channelID)
 
 	return nil
 }
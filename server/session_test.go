@@ -4,6 +4,8 @@
 package main
 
 import (
+	"net/http"
+	"sync"
 	"testing"
 	"time"
 
@@ -37,7 +39,9 @@ func TestAddUserSession(t *testing.T) {
 			},
 		},
 		sessions: map[string]*session{},
+		stopCh:   make(chan struct{}),
 	}
+	t.Cleanup(func() { close(p.stopCh) })
 
 	p.licenseChecker = enterprise.NewLicenseChecker(p.API)
 
@@ -45,6 +49,11 @@ func TestAddUserSession(t *testing.T) {
 	t.Cleanup(tearDown)
 	p.store = store
 
+	// Focus mode is checked asynchronously as users join and leave.
+	mockAPI.On("GetPreferenceForUser", mock.Anything, callNotificationPreferencesCategory, callNotificationPreferencesName).
+		Return(model.Preference{}, &model.AppError{StatusCode: http.StatusNotFound}).Maybe()
+	mockAPI.On("KVGet", mock.Anything).Return(nil, nil).Maybe()
+
 	mockMetrics.On("ObserveAppHandlersTime", mock.AnythingOfType("string"), mock.AnythingOfType("float64"))
 
 	t.Run("not enabled", func(t *testing.T) {
@@ -160,4 +169,424 @@ func TestAddUserSession(t *testing.T) {
 			require.NotNil(t, retState.sessions["connA"])
 		})
 	})
+
+	t.Run("multi-device join policy", func(t *testing.T) {
+		defaultGracePeriod := sessionCloseGracePeriod
+		sessionCloseGracePeriod = time.Hour
+		defer func() {
+			sessionCloseGracePeriod = defaultGracePeriod
+			p.configuration.MultiDeviceJoinPolicy = ""
+		}()
+
+		t.Run("allow", func(t *testing.T) {
+			defer mockAPI.AssertExpectations(t)
+			defer mockMetrics.AssertExpectations(t)
+			defer ResetTestStore(t, p.store)
+
+			p.configuration.MultiDeviceJoinPolicy = multiDeviceJoinPolicyAllow
+
+			mockMetrics.On("IncWebSocketEvent", "out", wsEventCallHostChanged).Once()
+			mockAPI.On("PublishWebSocketEvent", wsEventCallHostChanged, mock.Anything,
+				&model.WebsocketBroadcast{UserId: "userA", ChannelId: "channelID", ReliableClusterSend: true}).Once()
+
+			retState, err := p.addUserSession(nil, model.NewPointer(true), "userA", "connA", "channelID", "", model.ChannelTypeDirect)
+			require.NoError(t, err)
+
+			retState, err = p.addUserSession(retState, model.NewPointer(true), "userA", "connA2", "channelID", "", model.ChannelTypeDirect)
+			require.NoError(t, err)
+			require.Len(t, retState.sessions, 2)
+			require.Equal(t, []string{"connA", "connA2"}, retState.getUserSessionIDs("userA"))
+			require.Equal(t, "userA", retState.Call.GetHostID())
+		})
+
+		t.Run("replace", func(t *testing.T) {
+			defer mockAPI.AssertExpectations(t)
+			defer mockMetrics.AssertExpectations(t)
+			defer ResetTestStore(t, p.store)
+
+			p.configuration.MultiDeviceJoinPolicy = multiDeviceJoinPolicyReplace
+
+			mockMetrics.On("IncWebSocketEvent", "out", wsEventCallHostChanged).Once()
+			mockAPI.On("PublishWebSocketEvent", wsEventCallHostChanged, mock.Anything,
+				&model.WebsocketBroadcast{UserId: "userA", ChannelId: "channelID", ReliableClusterSend: true}).Once()
+
+			retState, err := p.addUserSession(nil, model.NewPointer(true), "userA", "connA", "channelID", "", model.ChannelTypeDirect)
+			require.NoError(t, err)
+
+			// The older session is sharing its screen.
+			retState.Call.Props.ScreenSharingSessionID = "connA"
+
+			retState, err = p.addUserSession(retState, model.NewPointer(true), "userB", "connB", "channelID", "", model.ChannelTypeDirect)
+			require.NoError(t, err)
+
+			mockAPI.On("LogDebug", "replacing session", "origin", mock.Anything,
+				"userID", "userA", "sessionID", "connA", "callID", retState.Call.ID).Once()
+			mockMetrics.On("IncWebSocketEvent", "out", wsEventSessionReplaced).Once()
+			mockAPI.On("PublishWebSocketEvent", wsEventSessionReplaced, map[string]interface{}{
+				"call_id":    retState.Call.ID,
+				"channel_id": "channelID",
+				"session_id": "connA",
+			}, &model.WebsocketBroadcast{UserId: "userA", ReliableClusterSend: true}).Once()
+
+			retState, err = p.addUserSession(retState, model.NewPointer(true), "userA", "connA2", "channelID", "", model.ChannelTypeDirect)
+			require.NoError(t, err)
+
+			// The replaced session is only removed once it leaves, so until
+			// then the user keeps the host role and the screen share.
+			require.Len(t, retState.sessions, 3)
+			require.Equal(t, "userA", retState.Call.GetHostID())
+			require.Equal(t, "connA", retState.Call.Props.ScreenSharingSessionID)
+
+			// Once the replaced session leaves, the host role stays with the
+			// user's new session while the screen share ends.
+			mockMetrics.On("IncWebSocketEvent", "out", wsEventUserScreenOff).Once()
+			mockAPI.On("PublishWebSocketEvent", wsEventUserScreenOff, mock.Anything, mock.Anything).Twice()
+			mockAPI.On("LogDebug", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+				mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			require.NoError(t, p.removeUserSession(retState, "userA", "connA", "connA", "channelID"))
+			require.Equal(t, []string{"connA2"}, retState.getUserSessionIDs("userA"))
+			require.Equal(t, "userA", retState.getHostID(p.getBotID()))
+			require.Empty(t, retState.Call.Props.ScreenSharingSessionID)
+		})
+	})
+}
+
+func TestShouldJoinMuted(t *testing.T) {
+	p := Plugin{
+		configuration: &configuration{
+			ClientConfig: ClientConfig{
+				JoinMuted: model.NewPointer(true),
+			},
+		},
+	}
+
+	t.Run("global setting", func(t *testing.T) {
+		require.True(t, p.shouldJoinMuted(nil))
+		require.True(t, p.shouldJoinMuted(&public.CallsChannel{ChannelID: "channelID"}))
+	})
+
+	t.Run("channel override", func(t *testing.T) {
+		require.False(t, p.shouldJoinMuted(&public.CallsChannel{
+			ChannelID: "channelID",
+			Props:     public.StringMap{"join_muted": false},
+		}))
+	})
+
+	t.Run("invalid channel override", func(t *testing.T) {
+		require.True(t, p.shouldJoinMuted(&public.CallsChannel{
+			ChannelID: "channelID",
+			Props:     public.StringMap{"join_muted": "false"},
+		}))
+	})
+}
+
+func TestICEConnectionTimeoutWatcher(t *testing.T) {
+	mockAPI := &pluginMocks.MockAPI{}
+	mockMetrics := &serverMocks.MockMetrics{}
+
+	p := Plugin{
+		MattermostPlugin: plugin.MattermostPlugin{
+			API: mockAPI,
+		},
+		metrics: mockMetrics,
+		stopCh:  make(chan struct{}),
+	}
+
+	t.Run("connected", func(t *testing.T) {
+		defer mockAPI.AssertExpectations(t)
+		defer mockMetrics.AssertExpectations(t)
+
+		us := newUserSession("userID", "channelID", "connID", "callID", true)
+		close(us.iceConnectedCh)
+
+		p.iceConnectionTimeoutWatcher(us, time.Second)
+		require.Zero(t, us.left)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		defer mockAPI.AssertExpectations(t)
+		defer mockMetrics.AssertExpectations(t)
+
+		us := newUserSession("userID", "channelID", "connID", "callID", true)
+
+		mockAPI.On("LogWarn", "ICE connection timed out, aborting join",
+			"origin", mock.Anything, "userID", "userID", "connID", "connID", "channelID", "channelID",
+			"callID", "callID", "timeout", "10ms").Once()
+		mockMetrics.On("IncICEConnectionTimeouts").Once()
+		mockMetrics.On("IncWebSocketEvent", "out", wsEventError).Once()
+		mockAPI.On("PublishWebSocketEvent", wsEventError, mock.Anything,
+			&model.WebsocketBroadcast{ConnectionId: "connID", ReliableClusterSend: true}).Once()
+
+		p.iceConnectionTimeoutWatcher(us, 10*time.Millisecond)
+
+		select {
+		case <-us.leaveCh:
+		default:
+			require.Fail(t, "leaveCh should be closed")
+		}
+	})
+}
+
+func TestGetICEConnectionTimeout(t *testing.T) {
+	var cfg configuration
+	cfg.SetDefaults()
+	cfg.ICEConnectionTimeoutSeconds = model.NewPointer(30)
+
+	botID := model.NewId()
+	p := Plugin{
+		configuration: &cfg,
+		botSession:    &model.Session{UserId: botID},
+	}
+
+	userID := model.NewId()
+
+	t.Run("reporting client", func(t *testing.T) {
+		require.Equal(t, 30*time.Second, p.getICEConnectionTimeout(userID, CallsClientJoinData{ICEConnectionReports: true}))
+	})
+
+	t.Run("client not reporting", func(t *testing.T) {
+		require.Zero(t, p.getICEConnectionTimeout(userID, CallsClientJoinData{}))
+	})
+
+	t.Run("bot", func(t *testing.T) {
+		require.Zero(t, p.getICEConnectionTimeout(botID, CallsClientJoinData{ICEConnectionReports: true}))
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		cfg.ICEConnectionTimeoutSeconds = model.NewPointer(0)
+		require.Zero(t, p.getICEConnectionTimeout(userID, CallsClientJoinData{ICEConnectionReports: true}))
+	})
+}
+
+func TestMediaInactivityWatcher(t *testing.T) {
+	defaultInterval := mediaInactivityCheckInterval
+	mediaInactivityCheckInterval = 10 * time.Millisecond
+	defer func() {
+		mediaInactivityCheckInterval = defaultInterval
+	}()
+
+	mockAPI := &pluginMocks.MockAPI{}
+	mockMetrics := &serverMocks.MockMetrics{}
+
+	p := Plugin{
+		MattermostPlugin: plugin.MattermostPlugin{
+			API: mockAPI,
+		},
+		metrics: mockMetrics,
+		stopCh:  make(chan struct{}),
+	}
+
+	t.Run("no keepalives", func(t *testing.T) {
+		defer mockAPI.AssertExpectations(t)
+		defer mockMetrics.AssertExpectations(t)
+
+		us := newUserSession("userID", "channelID", "connID", "callID", true)
+
+		go func() {
+			time.Sleep(100 * time.Millisecond)
+			close(us.wsCloseCh)
+		}()
+
+		p.mediaInactivityWatcher(us, 20*time.Millisecond, 0)
+		require.Zero(t, us.left)
+	})
+
+	t.Run("inactive", func(t *testing.T) {
+		defer mockAPI.AssertExpectations(t)
+		defer mockMetrics.AssertExpectations(t)
+
+		us := newUserSession("userID", "channelID", "connID", "callID", true)
+		us.mediaActivityAt.Store(time.Now().UnixMilli())
+
+		mockAPI.On("LogWarn", "no media activity from session, disconnecting",
+			"origin", mock.Anything, "userID", "userID", "connID", "connID", "channelID", "channelID",
+			"callID", "callID", "timeout", "20ms").Once()
+		mockMetrics.On("IncZombieSessions").Once()
+		mockMetrics.On("IncWebSocketEvent", "out", wsEventError).Once()
+		mockAPI.On("PublishWebSocketEvent", wsEventError, mock.Anything,
+			&model.WebsocketBroadcast{ConnectionId: "connID", ReliableClusterSend: true}).Once()
+
+		p.mediaInactivityWatcher(us, 20*time.Millisecond, 0)
+
+		select {
+		case <-us.leaveCh:
+		default:
+			require.Fail(t, "leaveCh should be closed")
+		}
+	})
+
+	t.Run("ice restart", func(t *testing.T) {
+		defer mockAPI.AssertExpectations(t)
+		defer mockMetrics.AssertExpectations(t)
+
+		us := newUserSession("userID", "channelID", "connID", "callID", true)
+		us.mediaActivityAt.Store(time.Now().UnixMilli())
+
+		mockAPI.On("LogDebug", "requesting ICE restart",
+			"origin", mock.Anything, "userID", "userID", "connID", "connID", "channelID", "channelID",
+			"callID", "callID")
+		mockMetrics.On("IncICERestarts", iceRestartInitiatorServer)
+		mockMetrics.On("IncWebSocketEvent", "out", wsEventICERestart)
+		mockAPI.On("PublishWebSocketEvent", wsEventICERestart, map[string]any{"call_id": "callID"},
+			&model.WebsocketBroadcast{ConnectionId: "connID", ReliableClusterSend: true})
+
+		go func() {
+			time.Sleep(100 * time.Millisecond)
+			close(us.wsCloseCh)
+		}()
+
+		// Without a media inactivity timeout the session is never disconnected.
+		p.mediaInactivityWatcher(us, 0, 20*time.Millisecond)
+		require.Zero(t, us.left)
+	})
+}
+
+func TestRecordMediaActivity(t *testing.T) {
+	us := newUserSession("userID", "channelID", "connID", "callID", true)
+
+	t.Run("first report", func(t *testing.T) {
+		us.recordMediaActivity(0)
+		require.NotZero(t, us.mediaActivityAt.Load())
+		require.Zero(t, us.mediaBytesReceived.Load())
+	})
+
+	t.Run("no progress", func(t *testing.T) {
+		us.mediaActivityAt.Store(1)
+		us.recordMediaActivity(0)
+		require.Equal(t, int64(1), us.mediaActivityAt.Load())
+	})
+
+	t.Run("concurrent reports", func(t *testing.T) {
+		var wg sync.WaitGroup
+		for i := 1; i <= 10; i++ {
+			wg.Add(1)
+			go func(bytesReceived int64) {
+				defer wg.Done()
+				us.recordMediaActivity(bytesReceived)
+			}(int64(i * 100))
+		}
+		wg.Wait()
+
+		require.Equal(t, int64(1000), us.mediaBytesReceived.Load())
+		require.Greater(t, us.mediaActivityAt.Load(), int64(1))
+	})
+}
+
+func TestAllowSessionMessage(t *testing.T) {
+	mockAPI := &pluginMocks.MockAPI{}
+	mockMetrics := &serverMocks.MockMetrics{}
+
+	defer mockAPI.AssertExpectations(t)
+	defer mockMetrics.AssertExpectations(t)
+
+	p := Plugin{
+		MattermostPlugin: plugin.MattermostPlugin{
+			API: mockAPI,
+		},
+		metrics: mockMetrics,
+	}
+
+	cfg := &configuration{}
+	cfg.SetDefaults()
+	cfg.SessionMessageRateLimit = model.NewPointer(1)
+	cfg.SessionMessageBurst = model.NewPointer(2)
+
+	us := newUserSession("userID", "channelID", "connID", "callID", true)
+	us.msgLimiter = cfg.newSessionMessageLimiter()
+
+	require.True(t, p.allowSessionMessage(us, clientMessageTypeReact))
+	require.True(t, p.allowSessionMessage(us, clientMessageTypeChat))
+
+	// Throttling is only logged and counted once.
+	mockAPI.On("LogWarn", "session exceeded its messages budget, throttling",
+		"origin", mock.Anything, "msgType", clientMessageTypeRecMarker, "userID", "userID",
+		"connID", "connID", "callID", "callID").Once()
+	mockMetrics.On("IncThrottledSessions").Once()
+	require.False(t, p.allowSessionMessage(us, clientMessageTypeRecMarker))
+	require.False(t, p.allowSessionMessage(us, clientMessageTypeReact))
+	require.Equal(t, int32(1), us.throttled)
+
+	t.Run("unlimited", func(t *testing.T) {
+		cfg.SessionMessageRateLimit = model.NewPointer(0)
+		us := newUserSession("userID", "channelID", "connID", "callID", true)
+		us.msgLimiter = cfg.newSessionMessageLimiter()
+		for i := 0; i < 100; i++ {
+			require.True(t, p.allowSessionMessage(us, clientMessageTypeReact))
+		}
+	})
+}
+
+func TestIsThrottledClientMessage(t *testing.T) {
+	require.True(t, isThrottledClientMessage(clientMessageTypeReact))
+	require.True(t, isThrottledClientMessage(clientMessageTypeChat))
+	require.True(t, isThrottledClientMessage(clientMessageTypeRecMarker))
+	require.False(t, isThrottledClientMessage(clientMessageTypeRaiseHand))
+	require.False(t, isThrottledClientMessage(clientMessageTypeUnraiseHand))
+	require.False(t, isThrottledClientMessage(clientMessageTypeMute))
+	require.False(t, isThrottledClientMessage(clientMessageTypeUnmute))
+	require.False(t, isThrottledClientMessage(clientMessageTypeScreenOff))
+	require.False(t, isThrottledClientMessage(clientMessageTypeVideoOff))
+	require.False(t, isThrottledClientMessage(clientMessageTypeSDP))
+	require.False(t, isThrottledClientMessage(clientMessageTypeICE))
+	require.False(t, isThrottledClientMessage(clientMessageTypeJoin))
+	require.False(t, isThrottledClientMessage(clientMessageTypeLeave))
+}
+
+func TestStopEmptyCallJobs(t *testing.T) {
+	mockAPI := &pluginMocks.MockAPI{}
+	mockMetrics := &serverMocks.MockMetrics{}
+
+	botID := model.NewId()
+	p := &Plugin{
+		MattermostPlugin: plugin.MattermostPlugin{
+			API: mockAPI,
+		},
+		metrics:    mockMetrics,
+		botSession: &model.Session{UserId: botID},
+	}
+	p.jobService = &jobService{
+		ctx: p,
+	}
+
+	defer mockAPI.AssertExpectations(t)
+	defer mockMetrics.AssertExpectations(t)
+
+	state := &callState{
+		Call: public.Call{
+			ID: "callID",
+		},
+		Recording: &public.CallJob{
+			ID: "recordingID",
+			Props: public.CallJobProps{
+				JobID:     "recJobID",
+				BotConnID: "recBotConnID",
+			},
+		},
+		Transcription: &public.CallJob{
+			ID: "transcriptionID",
+			Props: public.CallJobProps{
+				JobID:     "trJobID",
+				BotConnID: "trBotConnID",
+			},
+		},
+	}
+
+	mockAPI.On("LogDebug", "all users left call with job(s) in progress, stopping",
+		"origin", mock.Anything, "channelID", "channelID").Once()
+	mockAPI.On("LogDebug", "stopping ongoing recording",
+		"origin", mock.Anything, "jobID", "recJobID", "botConnID", "recBotConnID").Once()
+	mockAPI.On("LogDebug", "stopping ongoing transcription",
+		"origin", mock.Anything, "jobID", "trJobID", "botConnID", "trBotConnID").Once()
+
+	mockMetrics.On("IncWebSocketEvent", "out", wsEventJobStop).Twice()
+	for _, jobID := range []string{"recordingID", "transcriptionID"} {
+		mockAPI.On("PublishWebSocketEvent", wsEventJobStop, map[string]any{
+			"job_id": jobID,
+		}, &model.WebsocketBroadcast{
+			UserId:              botID,
+			ReliableClusterSend: true,
+		}).Once()
+	}
+
+	p.stopEmptyCallJobs(state, "channelID")
 }
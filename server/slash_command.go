@@ -7,8 +7,13 @@ import (
 	"bytes"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"sort"
 	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-calls/server/public"
 
 	"github.com/mattermost/mattermost/server/public/model"
 	"github.com/mattermost/mattermost/server/public/plugin"
@@ -25,8 +30,16 @@ const (
 	recordingCommandTrigger = "recording"
 	hostCommandTrigger      = "host"
 	logsCommandTrigger      = "logs"
+	whoCommandTrigger       = "who"
+	pingCommandTrigger      = "ping"
+	markerCommandTrigger    = "marker"
+	infoCommandTrigger      = "info"
+	moveCommandTrigger      = "move"
 )
 
+// The maximum number of users that can be pinged at once.
+const maxPingUsers = 10
+
 var subCommands = []string{
 	startCommandTrigger,
 	joinCommandTrigger,
@@ -36,6 +49,10 @@ var subCommands = []string{
 	statsCommandTrigger,
 	recordingCommandTrigger,
 	logsCommandTrigger,
+	whoCommandTrigger,
+	pingCommandTrigger,
+	markerCommandTrigger,
+	infoCommandTrigger,
 }
 
 func (p *Plugin) getAutocompleteData() *model.AutocompleteData {
@@ -43,6 +60,9 @@ func (p *Plugin) getAutocompleteData() *model.AutocompleteData {
 		"Available commands: "+strings.Join(subCommands, ","))
 	startCmdData := model.NewAutocompleteData(startCommandTrigger, "", "Starts a call in the current channel")
 	startCmdData.AddTextArgument("[message]", "Root message for the call", "")
+	startCmdData.AddNamedTextArgument("tag", "Category of the call (e.g. standup)", "[tag]", "", false)
+	startCmdData.AddNamedTextArgument("preset", "Preset to start the call with (e.g. webinar)", "[preset]", "", false)
+	startCmdData.AddNamedTextArgument("thread", "Post (ID or permalink) whose thread the call should be posted in", "[post]", "", false)
 	data.AddCommand(startCmdData)
 	data.AddCommand(model.NewAutocompleteData(joinCommandTrigger, "", "Joins a call in the current channel"))
 	data.AddCommand(model.NewAutocompleteData(leaveCommandTrigger, "", "Leave a call in the current channel."))
@@ -50,16 +70,31 @@ func (p *Plugin) getAutocompleteData() *model.AutocompleteData {
 	data.AddCommand(model.NewAutocompleteData(statsCommandTrigger, "", "Show client-generated statistics about the call."))
 	data.AddCommand(model.NewAutocompleteData(endCommandTrigger, "", "End the call for everyone. All the participants will drop immediately."))
 	data.AddCommand(model.NewAutocompleteData(logsCommandTrigger, "", "Show client logs."))
+	data.AddCommand(model.NewAutocompleteData(whoCommandTrigger, "", "List the participants of the call in the current channel."))
+	data.AddCommand(model.NewAutocompleteData(infoCommandTrigger, "", "Show connection details about the call in the current channel."))
+	pingCmdData := model.NewAutocompleteData(pingCommandTrigger, "", "Start a call in the current channel and send a direct message with the link to the given users.")
+	pingCmdData.AddTextArgument("@username1 @username2 [message]", "", "")
+	data.AddCommand(pingCmdData)
 
 	recordingCmdData := model.NewAutocompleteData(recordingCommandTrigger, "", "Manage calls recordings")
-	recordingCmdData.AddTextArgument("Available options: start, stop", "", "start|stop")
+	recordingCmdData.AddTextArgument("Available options: start, stop, pause, resume", "", "start|stop|pause|resume")
+	recordingCmdData.AddNamedTextArgument("layout", "Layout of the recording when starting it (grid or active_speaker)", "[layout]", "", false)
 	data.AddCommand(recordingCmdData)
 
+	markerCmdData := model.NewAutocompleteData(markerCommandTrigger, "", "Mark the start of a new chapter in the ongoing recording (host only).")
+	markerCmdData.AddTextArgument("\"Chapter name\"", "", "")
+	data.AddCommand(markerCmdData)
+
 	if p.licenseChecker.HostControlsAllowed() {
 		subCommands = append(subCommands, hostCommandTrigger)
 		hostCmdData := model.NewAutocompleteData(hostCommandTrigger, "", "Change the host (system admins only).")
 		hostCmdData.AddTextArgument("@username", "", "@*")
 		data.AddCommand(hostCmdData)
+
+		subCommands = append(subCommands, moveCommandTrigger)
+		moveCmdData := model.NewAutocompleteData(moveCommandTrigger, "", "Move the call to another channel (host only). Add force to remove the participants who can't access it.")
+		moveCmdData.AddTextArgument("~channel [force]", "", "~*")
+		data.AddCommand(moveCmdData)
 	}
 
 	return data
@@ -87,22 +122,140 @@ func (p *Plugin) unregisterCommands() error {
 	return nil
 }
 
+// getCallLink returns a deep link that joins the call in the given channel
+// when opened.
+func (p *Plugin) getCallLink(siteURL, teamID, channelID string) (string, error) {
+	team, appErr := p.API.GetTeam(teamID)
+	if appErr != nil {
+		return "", appErr
+	}
+
+	return fmt.Sprintf("%s/%s/channels/%s?join_call=true", siteURL, team.Name, channelID), nil
+}
+
 func (p *Plugin) handleLinkCommand(args *model.CommandArgs) (*model.CommandResponse, error) {
 	channel, appErr := p.API.GetChannel(args.ChannelId)
 	if appErr != nil {
 		return nil, appErr
 	}
 
-	team, appErr := p.API.GetTeam(args.TeamId)
+	link, err := p.getCallLink(args.SiteURL, args.TeamId, channel.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.CommandResponse{
+		ResponseType: model.CommandResponseTypeEphemeral,
+		Text:         fmt.Sprintf("Call link: %s", link),
+	}, nil
+}
+
+// handlePingCommand sends a direct message, through the bot, with a link to
+// join the call in the current channel to each of the given users. Starting the
+// call is taken care of by the client.
+func (p *Plugin) handlePingCommand(args *model.CommandArgs, fields []string) (*model.CommandResponse, error) {
+	var usernames []string
+	var note string
+	for i, field := range fields[2:] {
+		if !strings.HasPrefix(field, "@") {
+			note = strings.Join(fields[2+i:], " ")
+			break
+		}
+		usernames = append(usernames, strings.TrimPrefix(field, "@"))
+	}
+
+	if len(usernames) == 0 {
+		return nil, fmt.Errorf("No users provided")
+	}
+
+	if len(usernames) > maxPingUsers {
+		return nil, fmt.Errorf("Too many users provided: at most %d users can be pinged at once", maxPingUsers)
+	}
+
+	if !p.API.HasPermissionToChannel(args.UserId, args.ChannelId, model.PermissionReadChannel) {
+		return nil, fmt.Errorf("You don't have permissions to start a call in this channel")
+	}
+
+	sender, appErr := p.API.GetUser(args.UserId)
 	if appErr != nil {
-		return nil, appErr
+		return nil, fmt.Errorf("Failed to get user: %w", appErr)
+	}
+
+	channel, appErr := p.API.GetChannel(args.ChannelId)
+	if appErr != nil {
+		return nil, fmt.Errorf("Failed to get channel: %w", appErr)
+	}
+
+	channelName := channel.DisplayName
+	if channelName == "" {
+		channelName = channel.Name
+	}
+
+	link, err := p.getCallLink(args.SiteURL, args.TeamId, channel.Id)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get call link: %w", err)
+	}
+
+	botID := p.getBotID()
+	var pinged, failed []string
+	for _, username := range usernames {
+		user, appErr := p.API.GetUserByUsername(username)
+		if appErr != nil || user.Id == sender.Id {
+			failed = append(failed, "@"+username)
+			continue
+		}
+
+		dm, appErr := p.API.GetDirectChannel(user.Id, botID)
+		if appErr != nil {
+			p.LogError("failed to get dm between user and bot", "userID", user.Id, "botID", botID, "err", appErr.Error())
+			failed = append(failed, "@"+username)
+			continue
+		}
+
+		T := p.getTranslationFunc(user.Locale)
+		msg := T("app.call.ping_message", map[string]any{
+			"Username":    sender.Username,
+			"ChannelName": channelName,
+			"Link":        link,
+		})
+
+		// Users who can't access the channel still get the message so they
+		// can ask to be added.
+		if !p.API.HasPermissionToChannel(user.Id, channel.Id, model.PermissionReadChannel) {
+			msg += "\n\n" + T("app.call.ping_no_access_note", map[string]any{"Username": sender.Username})
+		}
+
+		if note != "" {
+			msg += "\n\n> " + note
+		}
+
+		if _, appErr := p.API.CreatePost(&model.Post{
+			UserId:    botID,
+			ChannelId: dm.Id,
+			Message:   msg,
+		}); appErr != nil {
+			p.LogError("failed to create ping post", "userID", user.Id, "err", appErr.Error())
+			failed = append(failed, "@"+username)
+			continue
+		}
+
+		pinged = append(pinged, "@"+username)
 	}
 
-	link := fmt.Sprintf("%s/%s/channels/%s?join_call=true", args.SiteURL, team.Name, channel.Id)
+	var text string
+	if len(pinged) > 0 {
+		text = fmt.Sprintf("Sent a link to join the call to %s.", strings.Join(pinged, ", "))
+	}
+	if len(failed) > 0 {
+		if text != "" {
+			text += "\n"
+		}
+		text += fmt.Sprintf("Could not send a link to %s.", strings.Join(failed, ", "))
+	}
 
 	return &model.CommandResponse{
 		ResponseType: model.CommandResponseTypeEphemeral,
-		Text:         fmt.Sprintf("Call link: %s", link),
+		Text:         text,
 	}, nil
 }
 
@@ -160,13 +313,44 @@ func (p *Plugin) handleRecordingCommand(fields []string) (*model.CommandResponse
 		return nil, fmt.Errorf("Invalid number of arguments provided")
 	}
 
-	if subCmd := fields[2]; subCmd != "start" && subCmd != "stop" {
+	switch subCmd := fields[2]; subCmd {
+	case "start", "stop", "pause", "resume":
+	default:
 		return nil, fmt.Errorf("Invalid subcommand %q", subCmd)
 	}
 
 	return &model.CommandResponse{}, nil
 }
 
+func (p *Plugin) handleMarkerCommand(args *model.CommandArgs, fields []string) (*model.CommandResponse, error) {
+	if len(fields) < 3 {
+		return nil, fmt.Errorf("Invalid number of arguments provided")
+	}
+
+	// The name can contain spaces and optionally be quoted.
+	_, name, _ := strings.Cut(args.Command, markerCommandTrigger)
+	name = strings.Trim(strings.TrimSpace(name), `"`)
+
+	state, err := p.lockCallReturnState(args.ChannelId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock call: %w", err)
+	}
+	defer p.unlockCall(args.ChannelId)
+
+	if state == nil {
+		return nil, fmt.Errorf("No call is ongoing in this channel")
+	}
+
+	if err := p.addRecordingMarker(state, args.UserId, name); err != nil {
+		return nil, err
+	}
+
+	return &model.CommandResponse{
+		ResponseType: model.CommandResponseTypeEphemeral,
+		Text:         "Recording marker added.",
+	}, nil
+}
+
 func (p *Plugin) handleHostCommand(args *model.CommandArgs, fields []string) (*model.CommandResponse, error) {
 	if len(fields) != 3 {
 		return nil, fmt.Errorf("Invalid number of arguments provided")
@@ -186,6 +370,211 @@ func (p *Plugin) handleHostCommand(args *model.CommandArgs, fields []string) (*m
 	return &model.CommandResponse{}, nil
 }
 
+func (p *Plugin) handleMoveCommand(args *model.CommandArgs, fields []string) (*model.CommandResponse, error) {
+	if len(fields) < 3 || len(fields) > 4 {
+		return nil, fmt.Errorf("Invalid number of arguments provided")
+	}
+
+	removeWithoutAccess := len(fields) == 4 && fields[3] == "force"
+	if len(fields) == 4 && !removeWithoutAccess {
+		return nil, fmt.Errorf("Invalid argument %q", fields[3])
+	}
+
+	channelName := strings.TrimPrefix(fields[2], "~")
+	channel, appErr := p.API.GetChannelByName(args.TeamId, channelName, false)
+	if appErr != nil {
+		return nil, fmt.Errorf("Could not find channel `%s`", channelName)
+	}
+
+	if err := p.moveCall(args.UserId, args.ChannelId, channel.Id, removeWithoutAccess); err != nil {
+		if errors.Is(err, errMoveParticipantsWithoutAccess) {
+			return nil, fmt.Errorf("Some participants can't access ~%s. Run the command again with force to remove them from the call", channelName)
+		}
+		return nil, err
+	}
+
+	return &model.CommandResponse{
+		ResponseType: model.CommandResponseTypeEphemeral,
+		Text:         fmt.Sprintf("Call moved to ~%s.", channelName),
+	}, nil
+}
+
+func (p *Plugin) handleWhoCommand(args *model.CommandArgs) (*model.CommandResponse, error) {
+	if !p.API.HasPermissionToChannel(args.UserId, args.ChannelId, model.PermissionReadChannel) {
+		return nil, fmt.Errorf("You don't have permissions to view the participants of this call")
+	}
+
+	state, err := p.getCallState(args.ChannelId, false)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get call state: %w", err)
+	}
+
+	text := p.getCallWho(state)
+	if text == "" {
+		text = "There's no call ongoing in this channel."
+	}
+
+	return &model.CommandResponse{
+		ResponseType: model.CommandResponseTypeEphemeral,
+		Text:         text,
+	}, nil
+}
+
+// getCallWho returns a table of the given call's participants, sorted by join
+// time, or an empty string if there are none.
+func (p *Plugin) getCallWho(state *callState) string {
+	botID := p.getBotID()
+	var sessions []*public.CallSession
+	if state != nil {
+		for _, session := range state.sessions {
+			// The bot is not exposed as a participant.
+			if session.UserID == botID {
+				continue
+			}
+			sessions = append(sessions, session)
+		}
+	}
+
+	if len(sessions) == 0 {
+		return ""
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].JoinAt < sessions[j].JoinAt
+	})
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Call participants (%d):\n\n", len(sessions))
+	sb.WriteString("| Participant | Role | Audio | Hand | Joined |\n")
+	sb.WriteString("|---|---|---|---|---|\n")
+
+	hostID := state.GetHostID()
+	usernames := make(map[string]string, len(sessions))
+	for _, session := range sessions {
+		username, ok := usernames[session.UserID]
+		if !ok {
+			if user, appErr := p.API.GetUser(session.UserID); appErr != nil {
+				p.LogError("failed to get user", "err", appErr.Error(), "userID", session.UserID)
+				username = session.UserID
+			} else {
+				username = "@" + user.Username
+			}
+			usernames[session.UserID] = username
+		}
+
+		// Being muted is already covered by the audio column, so the role
+		// is only about hosting the call.
+		role := "Participant"
+		if session.UserID == hostID {
+			role = "Host"
+		}
+
+		audio := "Muted"
+		if session.Unmuted {
+			audio = "Unmuted"
+		}
+
+		hand := "-"
+		if session.RaisedHand > 0 {
+			hand = "Raised"
+		}
+
+		fmt.Fprintf(&sb, "| %s | %s | %s | %s | %s UTC |\n", username, role, audio, hand,
+			time.UnixMilli(session.JoinAt).UTC().Format("3:04PM"))
+	}
+
+	return sb.String()
+}
+
+func (p *Plugin) handleInfoCommand(args *model.CommandArgs) (*model.CommandResponse, error) {
+	if !p.API.HasPermissionToChannel(args.UserId, args.ChannelId, model.PermissionReadChannel) {
+		return nil, fmt.Errorf("You don't have permissions to view this call")
+	}
+
+	state, err := p.getCallState(args.ChannelId, false)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get call state: %w", err)
+	}
+
+	if state == nil {
+		return &model.CommandResponse{
+			ResponseType: model.CommandResponseTypeEphemeral,
+			Text:         "There's no call ongoing in this channel.",
+		}, nil
+	}
+
+	isAdmin := p.API.HasPermissionTo(args.UserId, model.PermissionManageSystem)
+
+	return &model.CommandResponse{
+		ResponseType: model.CommandResponseTypeEphemeral,
+		Text:         getCallInfo(p.getConfiguration(), state, isAdmin),
+	}, nil
+}
+
+// getCallInfo returns a summary of how the call is being served. Details about
+// the infrastructure are only included for system admins.
+func getCallInfo(cfg *configuration, state *callState, isAdmin bool) string {
+	var sb strings.Builder
+	sb.WriteString("Call info:\n\n")
+	sb.WriteString("| | |\n")
+	sb.WriteString("|---|---|\n")
+	fmt.Fprintf(&sb, "| Call ID | `%s` |\n", state.Call.ID)
+
+	if state.Call.Props.RTCDHost != "" {
+		sb.WriteString("| Server | RTCD |\n")
+	} else {
+		sb.WriteString("| Server | Embedded RTC server |\n")
+	}
+
+	if !isAdmin {
+		return sb.String()
+	}
+
+	if state.Call.Props.RTCDHost != "" {
+		fmt.Fprintf(&sb, "| RTCD instance | `%s` |\n", state.Call.Props.RTCDHost)
+	}
+	if state.Call.Props.NodeID != "" {
+		fmt.Fprintf(&sb, "| Node | `%s` |\n", state.Call.Props.NodeID)
+	}
+
+	iceServers, _ := cfg.getChannelICEServers(state.Call.ChannelID, false)
+	var urls []string
+	for _, iceCfg := range iceServers {
+		urls = append(urls, iceCfg.URLs...)
+	}
+	if len(urls) > 0 {
+		fmt.Fprintf(&sb, "| ICE servers | %s |\n", strings.Join(urls, ", "))
+	} else {
+		sb.WriteString("| ICE servers | None |\n")
+	}
+
+	candidateTypes := cfg.getAllowedICECandidateTypes()
+	if len(candidateTypes) > 0 {
+		fmt.Fprintf(&sb, "| ICE candidate types | %s |\n", strings.Join(candidateTypes, ", "))
+	} else {
+		sb.WriteString("| ICE candidate types | All |\n")
+	}
+	relayOnly := len(candidateTypes) == 1 && candidateTypes[0] == iceCandidateTypeRelay
+	fmt.Fprintf(&sb, "| Relay only | %s |\n", formatBool(relayOnly))
+	fmt.Fprintf(&sb, "| Server side TURN | %s |\n", formatBool(cfg.ServerSideTURN != nil && *cfg.ServerSideTURN))
+
+	codecs := "Opus (audio), VP8 (video)"
+	if cfg.EnableAV1 != nil && *cfg.EnableAV1 {
+		codecs += ", AV1 (screen sharing)"
+	}
+	fmt.Fprintf(&sb, "| Codecs | %s |\n", codecs)
+	fmt.Fprintf(&sb, "| Simulcast | %s |\n", formatBool(cfg.EnableSimulcast != nil && *cfg.EnableSimulcast))
+
+	return sb.String()
+}
+
+func formatBool(b bool) string {
+	if b {
+		return "Yes"
+	}
+	return "No"
+}
+
 func (p *Plugin) ExecuteCommand(_ *plugin.Context, args *model.CommandArgs) (*model.CommandResponse, *model.AppError) {
 	fields := strings.Fields(args.Command)
 
@@ -236,10 +625,30 @@ func (p *Plugin) ExecuteCommand(_ *plugin.Context, args *model.CommandArgs) (*mo
 		return buildCommandResponse(p.handleRecordingCommand(fields))
 	}
 
+	if subCmd == whoCommandTrigger {
+		return buildCommandResponse(p.handleWhoCommand(args))
+	}
+
+	if subCmd == infoCommandTrigger {
+		return buildCommandResponse(p.handleInfoCommand(args))
+	}
+
+	if subCmd == pingCommandTrigger {
+		return buildCommandResponse(p.handlePingCommand(args, fields))
+	}
+
+	if subCmd == markerCommandTrigger {
+		return buildCommandResponse(p.handleMarkerCommand(args, fields))
+	}
+
 	if subCmd == hostCommandTrigger && p.licenseChecker.HostControlsAllowed() {
 		return buildCommandResponse(p.handleHostCommand(args, fields))
 	}
 
+	if subCmd == moveCommandTrigger && p.licenseChecker.HostControlsAllowed() {
+		return buildCommandResponse(p.handleMoveCommand(args, fields))
+	}
+
 	for _, cmd := range subCommands {
 		if cmd == subCmd {
 			return &model.CommandResponse{}, nil
@@ -0,0 +1,124 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-calls/server/public"
+
+	pluginMocks "github.com/mattermost/mattermost-plugin-calls/server/mocks/github.com/mattermost/mattermost/server/public/plugin"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetCallInfo(t *testing.T) {
+	cfg := &configuration{}
+	cfg.SetDefaults()
+	cfg.ICEServersConfigs = ICEServersConfigs{{URLs: []string{"stun:stun.example.com:3478"}}}
+	cfg.AllowedICECandidateTypes = "relay"
+
+	state := &callState{
+		Call: public.Call{
+			ID:        model.NewId(),
+			ChannelID: model.NewId(),
+			Props: public.CallProps{
+				NodeID: "nodeA",
+			},
+		},
+	}
+
+	t.Run("participant", func(t *testing.T) {
+		info := getCallInfo(cfg, state, false)
+		require.Contains(t, info, "| Call ID | `"+state.Call.ID+"` |")
+		require.Contains(t, info, "| Server | Embedded RTC server |")
+		require.NotContains(t, info, "nodeA")
+		require.NotContains(t, info, "stun.example.com")
+	})
+
+	t.Run("admin", func(t *testing.T) {
+		info := getCallInfo(cfg, state, true)
+		require.Contains(t, info, "| Node | `nodeA` |")
+		require.Contains(t, info, "| ICE servers | stun:stun.example.com:3478 |")
+		require.Contains(t, info, "| Relay only | Yes |")
+		require.Contains(t, info, "| Codecs | Opus (audio), VP8 (video) |")
+	})
+
+	t.Run("rtcd", func(t *testing.T) {
+		state.Call.Props.RTCDHost = "10.0.0.1"
+		defer func() {
+			state.Call.Props.RTCDHost = ""
+		}()
+
+		info := getCallInfo(cfg, state, false)
+		require.Contains(t, info, "| Server | RTCD |")
+		require.NotContains(t, info, "10.0.0.1")
+
+		info = getCallInfo(cfg, state, true)
+		require.Contains(t, info, "| RTCD instance | `10.0.0.1` |")
+	})
+}
+
+func TestGetCallWho(t *testing.T) {
+	mockAPI := &pluginMocks.MockAPI{}
+	defer mockAPI.AssertExpectations(t)
+
+	botID := model.NewId()
+	p := &Plugin{
+		MattermostPlugin: plugin.MattermostPlugin{
+			API: mockAPI,
+		},
+		botSession: &model.Session{UserId: botID},
+	}
+
+	joinAt := time.Date(2024, time.March, 5, 16, 30, 0, 0, time.UTC).UnixMilli()
+
+	t.Run("no call", func(t *testing.T) {
+		require.Empty(t, p.getCallWho(nil))
+	})
+
+	t.Run("only bot", func(t *testing.T) {
+		state := &callState{
+			sessions: map[string]*public.CallSession{
+				"botSessionID": {ID: "botSessionID", UserID: botID, JoinAt: joinAt},
+			},
+		}
+		require.Empty(t, p.getCallWho(state))
+	})
+
+	t.Run("participants", func(t *testing.T) {
+		state := &callState{
+			Call: public.Call{
+				Props: public.CallProps{
+					Hosts: []string{"userA"},
+				},
+			},
+			sessions: map[string]*public.CallSession{
+				"botSessionID": {ID: "botSessionID", UserID: botID, JoinAt: joinAt},
+				"sessionA":     {ID: "sessionA", UserID: "userA", JoinAt: joinAt},
+				"sessionB":     {ID: "sessionB", UserID: "userB", JoinAt: joinAt + 60000, Unmuted: true},
+				"sessionC":     {ID: "sessionC", UserID: "userC", JoinAt: joinAt + 120000, RaisedHand: joinAt + 180000},
+			},
+		}
+
+		mockAPI.On("GetUser", "userA").Return(&model.User{Id: "userA", Username: "alice"}, nil).Once()
+		mockAPI.On("GetUser", "userB").Return(&model.User{Id: "userB", Username: "bob"}, nil).Once()
+		mockAPI.On("GetUser", "userC").Return(nil, model.NewAppError("GetUser", "app.user.missing_account.const", nil, "", http.StatusNotFound)).Once()
+		mockAPI.On("LogError", "failed to get user", "origin", mock.Anything,
+			"err", mock.Anything, "userID", "userC").Once()
+
+		require.Equal(t, "Call participants (3):\n\n"+
+			"| Participant | Role | Audio | Hand | Joined |\n"+
+			"|---|---|---|---|---|\n"+
+			"| @alice | Host | Muted | - | 4:30PM UTC |\n"+
+			"| @bob | Participant | Unmuted | - | 4:31PM UTC |\n"+
+			"| userC | Participant | Muted | Raised | 4:32PM UTC |\n", p.getCallWho(state))
+	})
+}
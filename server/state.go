@@ -6,6 +6,10 @@ package main
 import (
 	"errors"
 	"fmt"
+	"slices"
+	"sort"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/mattermost/mattermost-plugin-calls/server/db"
@@ -18,6 +22,9 @@ type callState struct {
 	Recording     *public.CallJob
 	Transcription *public.CallJob
 	LiveCaptions  *public.CallJob
+	// The additional recording jobs capturing the call with different
+	// quality profiles alongside Recording.
+	ProfileRecordings []*public.CallJob
 }
 
 // Clone performs a deep copy of the call state.
@@ -52,6 +59,12 @@ func (cs *callState) Clone() *callState {
 			csCopy.Props.Participants[k] = v
 		}
 	}
+	if cs.Props.ChannelAdmins != nil {
+		csCopy.Props.ChannelAdmins = make(map[string]struct{}, len(cs.Call.Props.ChannelAdmins))
+		for k, v := range cs.Call.Props.ChannelAdmins {
+			csCopy.Props.ChannelAdmins[k] = v
+		}
+	}
 
 	// Sessions
 	if cs.sessions != nil {
@@ -75,6 +88,13 @@ func (cs *callState) Clone() *callState {
 		csCopy.LiveCaptions = new(public.CallJob)
 		*csCopy.LiveCaptions = *cs.LiveCaptions
 	}
+	if cs.ProfileRecordings != nil {
+		csCopy.ProfileRecordings = make([]*public.CallJob, len(cs.ProfileRecordings))
+		for i, job := range cs.ProfileRecordings {
+			csCopy.ProfileRecordings[i] = new(public.CallJob)
+			*csCopy.ProfileRecordings[i] = *job
+		}
+	}
 
 	return csCopy
 }
@@ -84,6 +104,10 @@ type UserStateClient struct {
 	UserID     string `json:"user_id"`
 	Unmuted    bool   `json:"unmuted"`
 	RaisedHand int64  `json:"raised_hand"`
+	// Video and ScreenSharing are set if the session is currently sending
+	// video or sharing its screen.
+	Video         bool `json:"video,omitempty"`
+	ScreenSharing bool `json:"screen_sharing,omitempty"`
 }
 
 type CallStateClient struct {
@@ -95,21 +119,44 @@ type CallStateClient struct {
 	ThreadID string `json:"thread_id"`
 	PostID   string `json:"post_id"`
 
-	ScreenSharingSessionID string          `json:"screen_sharing_session_id"`
-	OwnerID                string          `json:"owner_id"`
-	HostID                 string          `json:"host_id"`
-	Recording              *JobStateClient `json:"recording,omitempty"`
-	Transcription          *JobStateClient `json:"transcription,omitempty"`
-	LiveCaptions           *JobStateClient `json:"live_captions,omitempty"`
-	DismissedNotification  map[string]bool `json:"dismissed_notification,omitempty"`
+	ScreenSharingSessionID  string            `json:"screen_sharing_session_id"`
+	OwnerID                 string            `json:"owner_id"`
+	HostID                  string            `json:"host_id"`
+	Recording               *JobStateClient   `json:"recording,omitempty"`
+	Transcription           *JobStateClient   `json:"transcription,omitempty"`
+	LiveCaptions            *JobStateClient   `json:"live_captions,omitempty"`
+	DismissedNotification   map[string]bool   `json:"dismissed_notification,omitempty"`
+	JoinMuted               bool              `json:"join_muted,omitempty"`
+	SpeakerLabels           bool              `json:"speaker_labels,omitempty"`
+	VideoSessionIDs         []string          `json:"video_session_ids,omitempty"`
+	NoiseSuppression        bool              `json:"noise_suppression,omitempty"`
+	LiveCaptionsOff         bool              `json:"live_captions_off,omitempty"`
+	MaxParticipants         int               `json:"max_participants,omitempty"`
+	AtCapacity              bool              `json:"at_capacity,omitempty"`
+	Metadata                map[string]string `json:"metadata,omitempty"`
+	WaitingRoom             bool              `json:"waiting_room,omitempty"`
+	Preset                  string            `json:"preset,omitempty"`
+	AudioOnly               bool              `json:"audio_only,omitempty"`
+	ScreenSharingSessionIDs []string          `json:"screen_sharing_session_ids,omitempty"`
+	// DisabledFeatures are the interactive features clients should hide the
+	// controls of.
+	DisabledFeatures []string `json:"disabled_features,omitempty"`
 }
 
 type JobStateClient struct {
-	Type    public.JobType `json:"type"`
-	InitAt  int64          `json:"init_at"`
-	StartAt int64          `json:"start_at"`
-	EndAt   int64          `json:"end_at"`
-	Err     string         `json:"err,omitempty"`
+	Type     public.JobType `json:"type"`
+	InitAt   int64          `json:"init_at"`
+	StartAt  int64          `json:"start_at"`
+	EndAt    int64          `json:"end_at"`
+	PausedAt int64          `json:"paused_at"`
+	Err      string         `json:"err,omitempty"`
+	// EndReason is set when the job didn't end normally (e.g. canceled).
+	EndReason string `json:"end_reason,omitempty"`
+	// Layout is the recording layout. Only applies to recording jobs.
+	Layout string `json:"layout,omitempty"`
+	// WatermarkText is the text overlaid on the recording. Only applies to
+	// recording jobs.
+	WatermarkText string `json:"watermark_text,omitempty"`
 }
 
 func (js *JobStateClient) toMap() map[string]interface{} {
@@ -117,11 +164,15 @@ func (js *JobStateClient) toMap() map[string]interface{} {
 		return nil
 	}
 	return map[string]interface{}{
-		"type":     string(js.Type),
-		"init_at":  js.InitAt,
-		"start_at": js.StartAt,
-		"end_at":   js.EndAt,
-		"err":      js.Err,
+		"type":           string(js.Type),
+		"init_at":        js.InitAt,
+		"start_at":       js.StartAt,
+		"end_at":         js.EndAt,
+		"paused_at":      js.PausedAt,
+		"err":            js.Err,
+		"end_reason":     js.EndReason,
+		"layout":         js.Layout,
+		"watermark_text": js.WatermarkText,
 	}
 }
 
@@ -130,11 +181,15 @@ func getClientStateFromCallJob(job *public.CallJob) *JobStateClient {
 		return nil
 	}
 	return &JobStateClient{
-		Type:    job.Type,
-		InitAt:  job.InitAt,
-		StartAt: job.StartAt,
-		EndAt:   job.EndAt,
-		Err:     job.Props.Err,
+		Type:          job.Type,
+		InitAt:        job.InitAt,
+		StartAt:       job.StartAt,
+		EndAt:         job.EndAt,
+		PausedAt:      job.Props.PausedAt,
+		Err:           job.Props.Err,
+		EndReason:     job.Props.EndReason,
+		Layout:        job.Props.Layout,
+		WatermarkText: job.Props.WatermarkText,
 	}
 }
 
@@ -148,6 +203,20 @@ func (cs *callState) getRecording() (*public.CallJob, error) {
 	return cs.Recording, nil
 }
 
+// getProfileRecording returns the additional recording job with the given ID,
+// if any.
+func (cs *callState) getProfileRecording(jobID string) *public.CallJob {
+	if cs == nil {
+		return nil
+	}
+	for _, job := range cs.ProfileRecordings {
+		if job.ID == jobID {
+			return job
+		}
+	}
+	return nil
+}
+
 func (cs *callState) getTranscription() (*public.CallJob, error) {
 	if cs == nil {
 		return nil, fmt.Errorf("no call ongoing")
@@ -168,37 +237,106 @@ func (cs *callState) getLiveCaptions() (*public.CallJob, error) {
 	return cs.LiveCaptions, nil
 }
 
+// hasRunningJobs returns whether any job (e.g. recording, transcription) is
+// running for the call.
+func (cs *callState) hasRunningJobs() bool {
+	if cs == nil {
+		return false
+	}
+	for _, job := range []*public.CallJob{cs.Recording, cs.Transcription, cs.LiveCaptions} {
+		if job != nil && job.EndAt == 0 {
+			return true
+		}
+	}
+	for _, job := range cs.ProfileRecordings {
+		if job.EndAt == 0 {
+			return true
+		}
+	}
+	return false
+}
+
 func (cs *callState) getHostID(botID string) string {
 	if cs.Call.Props.HostLockedUserID != "" && cs.isUserIDInCall(cs.Call.Props.HostLockedUserID) {
 		return cs.Call.Props.HostLockedUserID
 	}
 
-	var host public.CallSession
-	for _, session := range cs.sessions {
-		// if current host is still in the call, keep them as the host
-		if session.UserID == cs.Call.GetHostID() {
-			return cs.Call.GetHostID()
+	// if current host is still in the call, keep them as the host
+	if hostID := cs.Call.GetHostID(); hostID != "" && cs.isUserIDInCall(hostID) {
+		if cs.Call.Props.HostAssignmentPolicy != hostAssignmentPolicyChannelAdmin || cs.isChannelAdmin(hostID) {
+			return hostID
 		}
 
+		// A channel admin joining takes over from a host who isn't one.
+		if newHostID := cs.getNewHostID(botID, ""); cs.isChannelAdmin(newHostID) {
+			return newHostID
+		}
+
+		return hostID
+	}
+
+	return cs.getNewHostID(botID, "")
+}
+
+// getNewHostID returns the longest connected participant, excluding the bot
+// and the given user, following the call's host assignment policy. Ties are
+// broken by session ID so that the selection is deterministic across nodes.
+func (cs *callState) getNewHostID(botID, excludeUserID string) string {
+	if cs.Call.Props.HostAssignmentPolicy == hostAssignmentPolicyNone {
+		return ""
+	}
+
+	isLonger := func(session, host *public.CallSession) bool {
+		return host == nil || session.JoinAt < host.JoinAt ||
+			(session.JoinAt == host.JoinAt && session.ID < host.ID)
+	}
+
+	var host, adminHost *public.CallSession
+	for _, session := range cs.sessions {
 		// bot can't be host
-		if session.UserID == botID {
+		if session.UserID == botID || session.UserID == excludeUserID {
 			continue
 		}
 
-		if host.UserID == "" {
-			host = *session
-			continue
+		if isLonger(session, host) {
+			host = session
 		}
 
-		// the participant who joined earliest should be host
-		if session.JoinAt < host.JoinAt {
-			host = *session
+		if cs.isChannelAdmin(session.UserID) && isLonger(session, adminHost) {
+			adminHost = session
 		}
 	}
 
+	// Channel admins are only tracked under the channel admin policy.
+	if adminHost != nil {
+		return adminHost.UserID
+	}
+
+	if host == nil {
+		return ""
+	}
+
 	return host.UserID
 }
 
+// getNewHostIDAmongSessions works like getNewHostID but only considers the
+// given sessions as candidates.
+func (cs *callState) getNewHostIDAmongSessions(botID, excludeUserID string, sessionIDs []string) string {
+	candidates := *cs
+	candidates.sessions = make(map[string]*public.CallSession, len(sessionIDs))
+	for _, sessionID := range sessionIDs {
+		if session := cs.sessions[sessionID]; session != nil {
+			candidates.sessions[sessionID] = session
+		}
+	}
+	return candidates.getNewHostID(botID, excludeUserID)
+}
+
+func (cs *callState) isChannelAdmin(userID string) bool {
+	_, ok := cs.Call.Props.ChannelAdmins[userID]
+	return ok
+}
+
 func (cs *callState) isUserIDInCall(userID string) bool {
 	for _, session := range cs.sessions {
 		if session.UserID == userID {
@@ -208,6 +346,31 @@ func (cs *callState) isUserIDInCall(userID string) bool {
 	return false
 }
 
+// getUserSessionIDs returns the IDs of the sessions the given user is
+// connected with, sorted by join time.
+func (cs *callState) getUserSessionIDs(userID string) []string {
+	var sessions []*public.CallSession
+	for _, session := range cs.sessions {
+		if session.UserID == userID {
+			sessions = append(sessions, session)
+		}
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		if sessions[i].JoinAt == sessions[j].JoinAt {
+			return sessions[i].ID < sessions[j].ID
+		}
+		return sessions[i].JoinAt < sessions[j].JoinAt
+	})
+
+	sessionIDs := make([]string, 0, len(sessions))
+	for _, session := range sessions {
+		sessionIDs = append(sessionIDs, session.ID)
+	}
+
+	return sessionIDs
+}
+
 func (cs *callState) getClientState(botID, userID string) *CallStateClient {
 	states := cs.getStates(botID)
 
@@ -223,21 +386,33 @@ func (cs *callState) getClientState(botID, userID string) *CallStateClient {
 		ID:      cs.ID,
 		StartAt: cs.StartAt,
 
-		Sessions:               states,
-		ThreadID:               cs.ThreadID,
-		PostID:                 cs.PostID,
-		ScreenSharingSessionID: cs.Props.ScreenSharingSessionID,
-		OwnerID:                cs.OwnerID,
-		HostID:                 cs.GetHostID(),
-		Recording:              getClientStateFromCallJob(cs.Recording),
-		Transcription:          getClientStateFromCallJob(cs.Transcription),
-		LiveCaptions:           getClientStateFromCallJob(cs.LiveCaptions),
-		DismissedNotification:  dismissed,
+		Sessions:                states,
+		ThreadID:                cs.ThreadID,
+		PostID:                  cs.PostID,
+		ScreenSharingSessionID:  cs.Props.ScreenSharingSessionID,
+		OwnerID:                 cs.OwnerID,
+		HostID:                  cs.GetHostID(),
+		Recording:               getClientStateFromCallJob(cs.Recording),
+		Transcription:           getClientStateFromCallJob(cs.Transcription),
+		LiveCaptions:            getClientStateFromCallJob(cs.LiveCaptions),
+		DismissedNotification:   dismissed,
+		JoinMuted:               cs.Props.JoinMuted,
+		SpeakerLabels:           cs.Props.SpeakerLabels,
+		VideoSessionIDs:         cs.Props.VideoSessionIDs,
+		NoiseSuppression:        cs.Props.NoiseSuppression,
+		LiveCaptionsOff:         cs.Props.LiveCaptionsOff,
+		WaitingRoom:             cs.Props.WaitingRoom,
+		Metadata:                cs.Props.Metadata,
+		Preset:                  cs.Props.Preset,
+		AudioOnly:               cs.Props.AudioOnly,
+		ScreenSharingSessionIDs: getScreenSharingSessionIDs(cs.Props),
+		DisabledFeatures:        cs.Props.DisabledFeatures,
 	}
 }
 
 func (cs *callState) getStates(botID string) []UserStateClient {
 	states := make([]UserStateClient, 0, len(cs.sessions))
+	screenSharingSessionIDs := getScreenSharingSessionIDs(cs.Props)
 	for _, session := range cs.sessions {
 		// We don't want to expose to the client that the bot is in a call.
 		if session.UserID == botID {
@@ -248,6 +423,9 @@ func (cs *callState) getStates(botID string) []UserStateClient {
 			UserID:     session.UserID,
 			Unmuted:    session.Unmuted,
 			RaisedHand: session.RaisedHand,
+
+			Video:         slices.Contains(cs.Props.VideoSessionIDs, session.ID),
+			ScreenSharing: slices.Contains(screenSharingSessionIDs, session.ID),
 		})
 	}
 	return states
@@ -264,6 +442,16 @@ func (cs *callState) onlyUserLeft(userID string) bool {
 	return found
 }
 
+// recordingGracePeriodActive returns true if the call is still waiting
+// for the recording grace period that started at leftAt to elapse, meaning
+// nobody rejoined the call since the last participant left.
+func (cs *callState) recordingGracePeriodActive(callID string, leftAt int64, botID string) bool {
+	if cs == nil || cs.Call.ID != callID || cs.Call.Props.LastParticipantLeftAt != leftAt {
+		return false
+	}
+	return cs.onlyUserLeft(botID)
+}
+
 func (p *Plugin) getCallStateFromCall(call *public.Call, fromWriter bool) (*callState, error) {
 	if call == nil {
 		return nil, fmt.Errorf("call should not be nil")
@@ -286,15 +474,23 @@ func (p *Plugin) getCallStateFromCall(call *public.Call, fromWriter bool) (*call
 	}
 	state.sessions = sessions
 
-	jobs, err := p.store.GetActiveCallJobs(call.ID, db.GetCallJobOpts{
+	allJobs, err := p.store.GetAllActiveCallJobs(call.ID, db.GetCallJobOpts{
 		FromWriter: fromWriter,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get call jobs: %w", err)
 	}
+	jobs := db.GetCallJobsByType(allJobs)
 	state.Recording = jobs[public.JobTypeRecording]
 	state.Transcription = jobs[public.JobTypeTranscribing]
 	state.LiveCaptions = jobs[public.JobTypeCaptioning]
+	if state.Recording != nil {
+		for _, job := range allJobs {
+			if job.Props.PrimaryJobID == state.Recording.ID {
+				state.ProfileRecordings = append(state.ProfileRecordings, job)
+			}
+		}
+	}
 
 	return state, nil
 }
@@ -318,41 +514,109 @@ func (p *Plugin) getCallState(channelID string, fromWriter bool) (*callState, er
 	return p.getCallStateFromCall(call, fromWriter)
 }
 
+// cleanUpState ends the calls left over from a previous run. Calls are
+// processed concurrently by up to CleanUpStateWorkers workers, each call
+// being locked through its cluster mutex so that nodes don't step on each
+// other.
 func (p *Plugin) cleanUpState() error {
 	p.LogDebug("cleaning up calls state")
+	start := time.Now()
 
 	calls, err := p.store.GetAllActiveCalls(db.GetCallOpts{FromWriter: true})
 	if err != nil {
 		return fmt.Errorf("failed to get all active calls: %w", err)
 	}
 
+	var reaped atomic.Int64
+	var failed atomic.Bool
+	var errOnce sync.Once
+	var cleanUpErr error
+
+	callsCh := make(chan *public.Call)
+	var wg sync.WaitGroup
+	for i := 0; i < min(p.getConfiguration().getCleanUpStateWorkers(), len(calls)); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for call := range callsCh {
+				ok, err := p.cleanUpCallState(call)
+				if err != nil {
+					errOnce.Do(func() {
+						cleanUpErr = err
+						failed.Store(true)
+					})
+					continue
+				}
+				if ok {
+					reaped.Add(1)
+				}
+			}
+		}()
+	}
+
 	for _, call := range calls {
-		if err := p.lockCall(call.ChannelID); err != nil {
-			p.LogError("failed to lock call", "err", err.Error())
-			continue
+		// We stop at the first failure, as when cleaning up serially.
+		if failed.Load() {
+			break
 		}
+		callsCh <- call
+	}
+	close(callsCh)
+	wg.Wait()
 
-		// If a call has a RTCD host assigned, we want to check with the RTCD side whether the call is still ongoing or not before
-		// cleaning up the state.
-		if p.rtcdManager != nil && call.Props.RTCDHost != "" && !p.rtcdManager.hasCallEnded(call) {
-			p.unlockCall(call.ChannelID)
-			continue
+	if cleanUpErr != nil {
+		return fmt.Errorf("failed to clean up state: %w", cleanUpErr)
+	}
+
+	elapsed := time.Since(start)
+	p.metrics.ObserveCleanUpStateTime(elapsed.Seconds())
+	p.metrics.AddCleanUpStateReapedCalls(int(reaped.Load()))
+	p.LogInfo("calls state cleaned up", "reaped", fmt.Sprintf("%d", reaped.Load()), "elapsed", elapsed.String())
+
+	return nil
+}
+
+// cleanUpCallState ends the given call unless it's still ongoing on RTCD. It
+// returns whether the call was ended.
+func (p *Plugin) cleanUpCallState(call *public.Call) (bool, error) {
+	if err := p.lockCall(call.ChannelID); err != nil {
+		p.LogError("failed to lock call", "err", err.Error())
+		return false, nil
+	}
+	defer p.unlockCall(call.ChannelID)
+
+	// A buffered write could have ended the call already.
+	if p.hasCallDBWrites(call.ID) {
+		if err := p.flushCallDBWrites(call.ID); err != nil {
+			return false, fmt.Errorf("failed to save buffered writes: %w", err)
 		}
 
-		if err := p.cleanCallState(call); err != nil {
-			p.unlockCall(call.ChannelID)
-			return fmt.Errorf("failed to clean up state: %w", err)
+		var err error
+		call, err = p.store.GetCall(call.ID, db.GetCallOpts{FromWriter: true})
+		if err != nil {
+			return false, fmt.Errorf("failed to get call: %w", err)
+		}
+		if call.EndAt > 0 {
+			return false, nil
 		}
+	}
 
-		p.unlockCall(call.ChannelID)
+	// If a call has a RTCD host assigned, we want to check with the RTCD side whether the call is still ongoing or not before
+	// cleaning up the state.
+	if p.rtcdManager != nil && call.Props.RTCDHost != "" && !p.rtcdManager.hasCallEnded(call) {
+		return false, nil
 	}
 
-	return nil
+	if err := p.cleanCallState(call, public.CallEndReasonNodeFailure); err != nil {
+		return false, err
+	}
+
+	return true, nil
 }
 
 // NOTE: cleanCallState is meant to be called under lock (on channelID) so that
 // the operation can be performed atomically.
-func (p *Plugin) cleanCallState(call *public.Call) error {
+func (p *Plugin) cleanCallState(call *public.Call, reason public.CallEndReason) error {
 	if call == nil {
 		return nil
 	}
@@ -361,15 +625,16 @@ func (p *Plugin) cleanCallState(call *public.Call) error {
 		p.LogError("failed to update call post", "err", err.Error())
 	}
 
-	if call.EndAt == 0 {
-		setCallEnded(call)
+	ongoing := call.EndAt == 0
+	if ongoing {
+		setCallEnded(call, reason)
 	}
 
 	if err := p.store.DeleteCallsSessions(call.ID); err != nil {
 		p.LogError("failed to delete calls sessions", "err", err.Error())
 	}
 
-	jobs, err := p.store.GetActiveCallJobs(call.ID, db.GetCallJobOpts{
+	jobs, err := p.store.GetAllActiveCallJobs(call.ID, db.GetCallJobOpts{
 		FromWriter: true,
 	})
 	if err != nil {
@@ -378,11 +643,12 @@ func (p *Plugin) cleanCallState(call *public.Call) error {
 	for _, job := range jobs {
 		if job.EndAt == 0 {
 			job.EndAt = time.Now().UnixMilli()
-			if err := p.store.UpdateCallJob(job); err != nil {
+			if err := p.updateCallJobBuffered(job); err != nil {
 				p.LogError("failed to update call job", "err", err.Error())
 			}
 
-			if job.Type == public.JobTypeRecording {
+			// Clients only track the main recording job.
+			if job.Type == public.JobTypeRecording && job.Props.PrimaryJobID == "" {
 				p.publishWebSocketEvent(wsEventCallJobState, map[string]interface{}{
 					"callID":   call.ChannelID,
 					"jobState": getClientStateFromCallJob(job).toMap(),
@@ -391,15 +657,64 @@ func (p *Plugin) cleanCallState(call *public.Call) error {
 		}
 	}
 
-	return p.store.UpdateCall(call)
+	if err := p.updateCallBuffered(call); err != nil {
+		return err
+	}
+
+	if ongoing {
+		p.publishCallEnd(call)
+
+		// A call with a single participant has no quality worth rating.
+		if len(call.Participants) > 1 && p.getConfiguration().callFeedbackSampled() {
+			go p.sendCallFeedbackPrompts(call)
+		}
+	}
+
+	return nil
 }
 
-func setCallEnded(call *public.Call) {
+// setCallEnded marks the call as ended. The given reason only applies if
+// none was set already, as when ending the call was requested by the host.
+func setCallEnded(call *public.Call, reason public.CallEndReason) {
 	call.EndAt = time.Now().UnixMilli()
+	if call.Props.EndReason == "" {
+		call.Props.EndReason = reason
+	}
 	call.Participants = mapKeys(call.Props.Participants)
 	call.Props.RTCDHost = ""
 	call.Props.DismissedNotification = nil
 	call.Props.NodeID = ""
 	call.Props.Hosts = nil
+	call.Props.HostNodeID = ""
 	call.Props.Participants = nil
+	call.Props.ChannelAdmins = nil
+}
+
+// publishCallStart lets clients in the channel know a call is ongoing.
+func (p *Plugin) publishCallStart(call *public.Call) {
+	// TODO: send all the info attached to a call.
+	p.publishWebSocketEvent(wsEventCallStart, map[string]interface{}{
+		"id":                call.ID,
+		"channelID":         call.ChannelID,
+		"start_at":          call.StartAt,
+		"thread_id":         call.ThreadID,
+		"post_id":           call.PostID,
+		"owner_id":          call.OwnerID,
+		"host_id":           call.GetHostID(),
+		"join_muted":        call.Props.JoinMuted,
+		"noise_suppression": call.Props.NoiseSuppression,
+		"metadata":          call.Props.Metadata,
+		"preset":            call.Props.Preset,
+		"disabled_features": getDisabledCallFeatures(*call),
+	}, &WebSocketBroadcast{ChannelID: call.ChannelID, ReliableClusterSend: true})
+}
+
+// publishCallEnd lets clients in the channel know the call has ended, or is
+// about to, so they can disconnect and tell users why.
+func (p *Plugin) publishCallEnd(call *public.Call) {
+	p.publishWebSocketEvent(wsEventCallEnd, map[string]interface{}{
+		"call_id":  call.ID,
+		"reason":   string(call.Props.EndReason),
+		"metadata": call.Props.Metadata,
+	}, &WebSocketBroadcast{ChannelID: call.ChannelID, ReliableClusterSend: true})
 }
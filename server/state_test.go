@@ -44,6 +44,9 @@ func TestCallStateGetClientState(t *testing.T) {
 				Props: public.CallProps{
 					Hosts:                  []string{"hostID"},
 					ScreenSharingSessionID: "sessionA",
+					SpeakerLabels:          true,
+					NoiseSuppression:       true,
+					LiveCaptionsOff:        true,
 				},
 			},
 			sessions: map[string]*public.CallSession{
@@ -60,15 +63,22 @@ func TestCallStateGetClientState(t *testing.T) {
 			StartAt: cs.StartAt,
 			Sessions: []UserStateClient{
 				{
-					SessionID:  "sessionA",
-					UserID:     "userA",
-					RaisedHand: 1100,
+					SessionID:     "sessionA",
+					UserID:        "userA",
+					RaisedHand:    1100,
+					ScreenSharing: true,
 				},
 			},
 			ThreadID:               cs.ThreadID,
 			ScreenSharingSessionID: cs.Props.ScreenSharingSessionID,
 			OwnerID:                cs.OwnerID,
 			HostID:                 cs.Props.Hosts[0],
+			SpeakerLabels:          true,
+			NoiseSuppression:       true,
+			LiveCaptionsOff:        true,
+			// Derived from ScreenSharingSessionID for calls started before
+			// multiple shares were supported.
+			ScreenSharingSessionIDs: []string{cs.Props.ScreenSharingSessionID},
 		}
 
 		require.Equal(t, &ccs, cs.getClientState("botID", "userID"))
@@ -159,6 +169,57 @@ func TestCallStateGetClientState(t *testing.T) {
 
 		require.ElementsMatch(t, ccs.Sessions, actualCS.Sessions)
 	})
+
+	t.Run("video and screen sharing", func(t *testing.T) {
+		cs := &callState{
+			Call: public.Call{
+				ID:      "test",
+				StartAt: 100,
+				Props: public.CallProps{
+					VideoSessionIDs:         []string{"sessionA", "sessionB"},
+					ScreenSharingSessionIDs: []string{"sessionB"},
+				},
+			},
+			sessions: map[string]*public.CallSession{
+				"sessionA": {
+					ID:      "sessionA",
+					UserID:  "userA",
+					Unmuted: true,
+				},
+				"sessionB": {
+					ID:     "sessionB",
+					UserID: "userB",
+				},
+				"sessionC": {
+					ID:         "sessionC",
+					UserID:     "userC",
+					RaisedHand: 1100,
+				},
+			},
+		}
+
+		expected := []UserStateClient{
+			{
+				SessionID: "sessionA",
+				UserID:    "userA",
+				Unmuted:   true,
+				Video:     true,
+			},
+			{
+				SessionID:     "sessionB",
+				UserID:        "userB",
+				Video:         true,
+				ScreenSharing: true,
+			},
+			{
+				SessionID:  "sessionC",
+				UserID:     "userC",
+				RaisedHand: 1100,
+			},
+		}
+
+		require.ElementsMatch(t, expected, cs.getClientState("botID", "").Sessions)
+	})
 }
 
 func TestCallStateGetHostID(t *testing.T) {
@@ -288,6 +349,388 @@ func TestCallStateGetHostID(t *testing.T) {
 
 		require.Equal(t, "userE", cs.getHostID("botID"))
 	})
+
+	t.Run("all but one leave simultaneously", func(t *testing.T) {
+		cs := &callState{
+			Call: public.Call{
+				ID:      "test",
+				StartAt: 100,
+				Props: public.CallProps{
+					Hosts: []string{"userA"},
+				},
+			},
+			sessions: map[string]*public.CallSession{
+				"botSessionID": {
+					ID:     "botSessionID",
+					UserID: "botID",
+					JoinAt: 500,
+				},
+				"sessionA": {
+					ID:     "sessionA",
+					UserID: "userA",
+					JoinAt: 800,
+				},
+				"sessionB": {
+					ID:     "sessionB",
+					UserID: "userB",
+					JoinAt: 900,
+				},
+				"sessionC": {
+					ID:     "sessionC",
+					UserID: "userC",
+					JoinAt: 1000,
+				},
+			},
+		}
+
+		delete(cs.sessions, "sessionA")
+		delete(cs.sessions, "sessionB")
+
+		require.Equal(t, "userC", cs.getHostID("botID"))
+	})
+}
+
+func TestCallStateGetNewHostID(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		var cs callState
+		require.Empty(t, cs.getNewHostID("botID", ""))
+	})
+
+	t.Run("only bot and excluded user", func(t *testing.T) {
+		cs := &callState{
+			sessions: map[string]*public.CallSession{
+				"botSessionID": {
+					ID:     "botSessionID",
+					UserID: "botID",
+					JoinAt: 500,
+				},
+				"sessionA": {
+					ID:     "sessionA",
+					UserID: "userA",
+					JoinAt: 800,
+				},
+			},
+		}
+
+		require.Empty(t, cs.getNewHostID("botID", "userA"))
+	})
+
+	t.Run("excludes current host", func(t *testing.T) {
+		cs := &callState{
+			Call: public.Call{
+				Props: public.CallProps{
+					Hosts: []string{"userA"},
+				},
+			},
+			sessions: map[string]*public.CallSession{
+				"sessionA": {
+					ID:     "sessionA",
+					UserID: "userA",
+					JoinAt: 800,
+				},
+				"sessionB": {
+					ID:     "sessionB",
+					UserID: "userB",
+					JoinAt: 1000,
+				},
+				"sessionC": {
+					ID:     "sessionC",
+					UserID: "userC",
+					JoinAt: 900,
+				},
+			},
+		}
+
+		require.Equal(t, "userC", cs.getNewHostID("botID", "userA"))
+	})
+
+	t.Run("deterministic on ties", func(t *testing.T) {
+		cs := &callState{
+			sessions: map[string]*public.CallSession{
+				"sessionC": {
+					ID:     "sessionC",
+					UserID: "userC",
+					JoinAt: 1000,
+				},
+				"sessionB": {
+					ID:     "sessionB",
+					UserID: "userB",
+					JoinAt: 1000,
+				},
+				"sessionD": {
+					ID:     "sessionD",
+					UserID: "userD",
+					JoinAt: 1000,
+				},
+			},
+		}
+
+		for i := 0; i < 10; i++ {
+			require.Equal(t, "userB", cs.getNewHostID("botID", ""))
+		}
+	})
+}
+
+func TestCallStateGetNewHostIDAmongSessions(t *testing.T) {
+	cs := &callState{
+		sessions: map[string]*public.CallSession{
+			"sessionA": {
+				ID:     "sessionA",
+				UserID: "userA",
+				JoinAt: 800,
+			},
+			"sessionB": {
+				ID:     "sessionB",
+				UserID: "userB",
+				JoinAt: 900,
+			},
+			"sessionC": {
+				ID:     "sessionC",
+				UserID: "userC",
+				JoinAt: 1000,
+			},
+		},
+	}
+
+	t.Run("no sessions", func(t *testing.T) {
+		require.Empty(t, cs.getNewHostIDAmongSessions("botID", "", nil))
+	})
+
+	t.Run("skips sessions not given", func(t *testing.T) {
+		require.Equal(t, "userC", cs.getNewHostIDAmongSessions("botID", "userA", []string{"sessionA", "sessionC"}))
+	})
+
+	t.Run("unknown sessions", func(t *testing.T) {
+		require.Equal(t, "userB", cs.getNewHostIDAmongSessions("botID", "", []string{"sessionX", "sessionB", "sessionC"}))
+	})
+
+	t.Run("state is untouched", func(t *testing.T) {
+		require.Len(t, cs.sessions, 3)
+		require.Equal(t, "userA", cs.getNewHostID("botID", ""))
+	})
+}
+
+func TestCallStateHostAssignmentPolicy(t *testing.T) {
+	newState := func(policy string, hostID string, admins ...string) *callState {
+		cs := &callState{
+			Call: public.Call{
+				Props: public.CallProps{
+					HostAssignmentPolicy: policy,
+				},
+			},
+			sessions: map[string]*public.CallSession{
+				"sessionA": {
+					ID:     "sessionA",
+					UserID: "userA",
+					JoinAt: 800,
+				},
+				"sessionB": {
+					ID:     "sessionB",
+					UserID: "userB",
+					JoinAt: 900,
+				},
+				"sessionC": {
+					ID:     "sessionC",
+					UserID: "userC",
+					JoinAt: 1000,
+				},
+			},
+		}
+		if hostID != "" {
+			cs.Props.Hosts = []string{hostID}
+		}
+		for _, adminID := range admins {
+			if cs.Props.ChannelAdmins == nil {
+				cs.Props.ChannelAdmins = map[string]struct{}{}
+			}
+			cs.Props.ChannelAdmins[adminID] = struct{}{}
+		}
+		return cs
+	}
+
+	t.Run("initiator", func(t *testing.T) {
+		cs := newState(hostAssignmentPolicyInitiator, "")
+		require.Equal(t, "userA", cs.getHostID("botID"))
+
+		cs = newState(hostAssignmentPolicyInitiator, "userB")
+		require.Equal(t, "userB", cs.getHostID("botID"))
+	})
+
+	t.Run("unset defaults to initiator", func(t *testing.T) {
+		cs := newState("", "")
+		require.Equal(t, "userA", cs.getHostID("botID"))
+	})
+
+	t.Run("channel admin", func(t *testing.T) {
+		cs := newState(hostAssignmentPolicyChannelAdmin, "", "userC", "userB")
+		require.Equal(t, "userB", cs.getHostID("botID"))
+	})
+
+	t.Run("channel admin not present", func(t *testing.T) {
+		cs := newState(hostAssignmentPolicyChannelAdmin, "")
+		require.Equal(t, "userA", cs.getHostID("botID"))
+
+		cs = newState(hostAssignmentPolicyChannelAdmin, "userA")
+		require.Equal(t, "userA", cs.getHostID("botID"))
+	})
+
+	t.Run("channel admin joining takes over", func(t *testing.T) {
+		cs := newState(hostAssignmentPolicyChannelAdmin, "userA", "userC")
+		require.Equal(t, "userC", cs.getHostID("botID"))
+	})
+
+	t.Run("channel admin host is kept", func(t *testing.T) {
+		cs := newState(hostAssignmentPolicyChannelAdmin, "userC", "userB", "userC")
+		require.Equal(t, "userC", cs.getHostID("botID"))
+	})
+
+	t.Run("channel admin doesn't take over a transferred host", func(t *testing.T) {
+		cs := newState(hostAssignmentPolicyChannelAdmin, "userA", "userC")
+		cs.Props.HostLockedUserID = "userA"
+		require.Equal(t, "userA", cs.getHostID("botID"))
+	})
+
+	t.Run("channel admin host leaving", func(t *testing.T) {
+		cs := newState(hostAssignmentPolicyChannelAdmin, "userB", "userB", "userC")
+		require.Equal(t, "userC", cs.getNewHostID("botID", "userB"))
+
+		cs = newState(hostAssignmentPolicyChannelAdmin, "userB", "userB")
+		require.Equal(t, "userA", cs.getNewHostID("botID", "userB"))
+	})
+
+	t.Run("none", func(t *testing.T) {
+		cs := newState(hostAssignmentPolicyNone, "")
+		require.Empty(t, cs.getHostID("botID"))
+		require.Empty(t, cs.getNewHostID("botID", ""))
+	})
+
+	t.Run("none with assigned host", func(t *testing.T) {
+		cs := newState(hostAssignmentPolicyNone, "userB")
+		cs.Props.HostLockedUserID = "userB"
+		require.Equal(t, "userB", cs.getHostID("botID"))
+
+		// The role is not passed on once the assigned host leaves.
+		delete(cs.sessions, "sessionB")
+		require.Empty(t, cs.getHostID("botID"))
+	})
+}
+
+func TestCallStateGetUserSessionIDs(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		var cs callState
+		require.Empty(t, cs.getUserSessionIDs("userA"))
+	})
+
+	t.Run("multiple devices", func(t *testing.T) {
+		cs := &callState{
+			sessions: map[string]*public.CallSession{
+				"sessionA3": {
+					ID:     "sessionA3",
+					UserID: "userA",
+					JoinAt: 1000,
+				},
+				"sessionB": {
+					ID:     "sessionB",
+					UserID: "userB",
+					JoinAt: 500,
+				},
+				"sessionA2": {
+					ID:     "sessionA2",
+					UserID: "userA",
+					JoinAt: 800,
+				},
+				"sessionA1": {
+					ID:     "sessionA1",
+					UserID: "userA",
+					JoinAt: 800,
+				},
+			},
+		}
+
+		require.Equal(t, []string{"sessionA1", "sessionA2", "sessionA3"}, cs.getUserSessionIDs("userA"))
+		require.Equal(t, []string{"sessionB"}, cs.getUserSessionIDs("userB"))
+		require.Empty(t, cs.getUserSessionIDs("userC"))
+	})
+}
+
+func TestCallStateRecordingGracePeriodActive(t *testing.T) {
+	newState := func() *callState {
+		return &callState{
+			Call: public.Call{
+				ID: "callID",
+				Props: public.CallProps{
+					LastParticipantLeftAt: 1000,
+				},
+			},
+			sessions: map[string]*public.CallSession{
+				"botSessionID": {
+					ID:     "botSessionID",
+					UserID: "botID",
+				},
+			},
+		}
+	}
+
+	t.Run("nil state", func(t *testing.T) {
+		var cs *callState
+		require.False(t, cs.recordingGracePeriodActive("callID", 1000, "botID"))
+	})
+
+	t.Run("active", func(t *testing.T) {
+		require.True(t, newState().recordingGracePeriodActive("callID", 1000, "botID"))
+	})
+
+	t.Run("different call", func(t *testing.T) {
+		require.False(t, newState().recordingGracePeriodActive("otherCallID", 1000, "botID"))
+	})
+
+	t.Run("participant rejoined", func(t *testing.T) {
+		cs := newState()
+		cs.sessions["sessionA"] = &public.CallSession{
+			ID:     "sessionA",
+			UserID: "userA",
+		}
+		require.False(t, cs.recordingGracePeriodActive("callID", 1000, "botID"))
+	})
+
+	t.Run("grace period restarted", func(t *testing.T) {
+		cs := newState()
+		cs.Call.Props.LastParticipantLeftAt = 2000
+		require.False(t, cs.recordingGracePeriodActive("callID", 1000, "botID"))
+	})
+}
+
+func TestCallStateHasRunningJobs(t *testing.T) {
+	t.Run("nil state", func(t *testing.T) {
+		var cs *callState
+		require.False(t, cs.hasRunningJobs())
+	})
+
+	t.Run("no jobs", func(t *testing.T) {
+		require.False(t, (&callState{}).hasRunningJobs())
+	})
+
+	t.Run("ended jobs", func(t *testing.T) {
+		cs := &callState{
+			Recording:         &public.CallJob{ID: "recordingID", EndAt: 1000},
+			ProfileRecordings: []*public.CallJob{{ID: "profileID", EndAt: 1000}},
+		}
+		require.False(t, cs.hasRunningJobs())
+	})
+
+	t.Run("live captions", func(t *testing.T) {
+		cs := &callState{
+			LiveCaptions: &public.CallJob{ID: "captionsID"},
+		}
+		require.True(t, cs.hasRunningJobs())
+	})
+
+	t.Run("profile recording", func(t *testing.T) {
+		cs := &callState{
+			ProfileRecordings: []*public.CallJob{{ID: "profileID"}},
+		}
+		require.True(t, cs.hasRunningJobs())
+	})
 }
 
 func TestGetClientStateFromCallJob(t *testing.T) {
@@ -313,6 +756,45 @@ func TestGetClientStateFromCallJob(t *testing.T) {
 
 		require.Equal(t, recState, getClientStateFromCallJob(job))
 	})
+
+	t.Run("paused", func(t *testing.T) {
+		job := &public.CallJob{
+			ID:      "recID",
+			InitAt:  100,
+			StartAt: 200,
+			Props: public.CallJobProps{
+				PausedAt: 250,
+			},
+		}
+
+		recState := &JobStateClient{
+			InitAt:   100,
+			StartAt:  200,
+			PausedAt: 250,
+		}
+
+		require.Equal(t, recState, getClientStateFromCallJob(job))
+	})
+
+	t.Run("watermark", func(t *testing.T) {
+		job := &public.CallJob{
+			ID:     "recID",
+			InitAt: 100,
+			Props: public.CallJobProps{
+				Layout:        "grid",
+				WatermarkText: "CONFIDENTIAL",
+			},
+		}
+
+		recState := &JobStateClient{
+			InitAt:        100,
+			Layout:        "grid",
+			WatermarkText: "CONFIDENTIAL",
+		}
+
+		require.Equal(t, recState, getClientStateFromCallJob(job))
+		require.Equal(t, "CONFIDENTIAL", recState.toMap()["watermark_text"])
+	})
 }
 
 func samePointer(t testing.TB, a, b interface{}) bool {
@@ -360,6 +842,9 @@ func TestCallStateClone(t *testing.T) {
 						model.NewId(): {},
 						model.NewId(): {},
 					},
+					ChannelAdmins: map[string]struct{}{
+						model.NewId(): {},
+					},
 				},
 			},
 			sessions: map[string]*public.CallSession{
@@ -517,6 +1002,37 @@ func BenchmarkCallStateClone(b *testing.B) {
 	}
 }
 
+func TestSetCallEnded(t *testing.T) {
+	t.Run("reason", func(t *testing.T) {
+		call := &public.Call{
+			Props: public.CallProps{
+				Hosts:        []string{"userA"},
+				NodeID:       "nodeID",
+				Participants: map[string]struct{}{"userA": {}},
+			},
+		}
+
+		setCallEnded(call, public.CallEndReasonAllLeft)
+		require.NotZero(t, call.EndAt)
+		require.Equal(t, public.CallEndReasonAllLeft, call.Props.EndReason)
+		require.Equal(t, public.StringArray{"userA"}, call.Participants)
+		require.Empty(t, call.Props.Hosts)
+		require.Empty(t, call.Props.NodeID)
+		require.Empty(t, call.Props.Participants)
+	})
+
+	t.Run("requested reason is kept", func(t *testing.T) {
+		call := &public.Call{
+			Props: public.CallProps{
+				EndReason: public.CallEndReasonHostEnded,
+			},
+		}
+
+		setCallEnded(call, public.CallEndReasonAllLeft)
+		require.Equal(t, public.CallEndReasonHostEnded, call.Props.EndReason)
+	})
+}
+
 func TestCleanUpState(t *testing.T) {
 	mockAPI := &pluginMocks.MockAPI{}
 	mockMetrics := &serverMocks.MockMetrics{}
@@ -540,6 +1056,10 @@ func TestCleanUpState(t *testing.T) {
 		t.Run("no calls", func(t *testing.T) {
 			mockAPI.On("LogDebug", "cleaning up calls state",
 				"origin", mock.AnythingOfType("string")).Once()
+			mockAPI.On("LogInfo", "calls state cleaned up",
+				"origin", mock.AnythingOfType("string"), "reaped", mock.AnythingOfType("string"), "elapsed", mock.AnythingOfType("string")).Once()
+			mockMetrics.On("ObserveCleanUpStateTime", mock.AnythingOfType("float64")).Once()
+			mockMetrics.On("AddCleanUpStateReapedCalls", mock.AnythingOfType("int")).Once()
 
 			err := p.cleanUpState()
 			require.NoError(t, err)
@@ -577,6 +1097,10 @@ func TestCleanUpState(t *testing.T) {
 
 			mockAPI.On("LogDebug", "cleaning up calls state",
 				"origin", mock.AnythingOfType("string")).Once()
+			mockAPI.On("LogInfo", "calls state cleaned up",
+				"origin", mock.AnythingOfType("string"), "reaped", mock.AnythingOfType("string"), "elapsed", mock.AnythingOfType("string")).Once()
+			mockMetrics.On("ObserveCleanUpStateTime", mock.AnythingOfType("float64")).Once()
+			mockMetrics.On("AddCleanUpStateReapedCalls", mock.AnythingOfType("int")).Once()
 
 			mockAPI.On("LogDebug", "creating cluster mutex for call",
 				"origin", mock.AnythingOfType("string"), "channelID", channelID).Once()
@@ -587,6 +1111,12 @@ func TestCleanUpState(t *testing.T) {
 			mockMetrics.On("ObserveClusterMutexLockedTime", "mutex_call", mock.AnythingOfType("float64"))
 
 			mockAPI.On("UpdatePost", mock.AnythingOfType("*model.Post")).Return(&model.Post{Id: postID}, nil).Once()
+
+			mockMetrics.On("IncWebSocketEvent", "out", wsEventCallEnd).Once()
+			mockAPI.On("PublishWebSocketEvent", wsEventCallEnd, map[string]any{
+				"call_id": callID,
+				"reason":  string(public.CallEndReasonNodeFailure),
+			}, &model.WebsocketBroadcast{ChannelId: channelID, ReliableClusterSend: true}).Once()
 			mockAPI.On("GetConfig").Return(&model.Config{}, nil).Once()
 			mockAPI.On("KVDelete", "mutex_call_"+channelID).Return(nil)
 
@@ -601,6 +1131,54 @@ func TestCleanUpState(t *testing.T) {
 			require.NoError(t, err)
 			require.Empty(t, sessions)
 		})
+
+		t.Run("many calls", func(t *testing.T) {
+			defer ResetTestStore(t, p.store)
+
+			numCalls := 10
+			userID := model.NewId()
+			for i := 0; i < numCalls; i++ {
+				channelID := model.NewId()
+				postID := model.NewId()
+				err := p.store.CreateCall(&public.Call{
+					ID:        model.NewId(),
+					CreateAt:  time.Now().UnixMilli(),
+					ChannelID: channelID,
+					StartAt:   time.Now().UnixMilli(),
+					PostID:    postID,
+					ThreadID:  model.NewId(),
+					OwnerID:   userID,
+				})
+				require.NoError(t, err)
+				createPost(t, store, postID, userID, channelID)
+
+				mockAPI.On("LogDebug", "creating cluster mutex for call",
+					"origin", mock.AnythingOfType("string"), "channelID", channelID).Once()
+				mockAPI.On("KVDelete", "mutex_call_"+channelID).Return(nil)
+			}
+
+			mockAPI.On("LogDebug", "cleaning up calls state",
+				"origin", mock.AnythingOfType("string")).Once()
+			mockAPI.On("LogInfo", "calls state cleaned up",
+				"origin", mock.AnythingOfType("string"), "reaped", "10", "elapsed", mock.AnythingOfType("string")).Once()
+			mockMetrics.On("ObserveCleanUpStateTime", mock.AnythingOfType("float64")).Once()
+			mockMetrics.On("AddCleanUpStateReapedCalls", numCalls).Once()
+
+			mockAPI.On("KVSetWithOptions", mock.Anything, mock.Anything, mock.Anything).Return(true, nil)
+			mockMetrics.On("ObserveClusterMutexGrabTime", "mutex_call", mock.AnythingOfType("float64"))
+			mockMetrics.On("ObserveClusterMutexLockedTime", "mutex_call", mock.AnythingOfType("float64"))
+			mockAPI.On("UpdatePost", mock.AnythingOfType("*model.Post")).Return(&model.Post{}, nil).Times(numCalls)
+			mockMetrics.On("IncWebSocketEvent", "out", wsEventCallEnd).Times(numCalls)
+			mockAPI.On("PublishWebSocketEvent", wsEventCallEnd, mock.Anything, mock.Anything).Times(numCalls)
+			mockAPI.On("GetConfig").Return(&model.Config{}, nil).Times(numCalls)
+
+			err := p.cleanUpState()
+			require.NoError(t, err)
+
+			calls, err := p.store.GetAllActiveCalls(db.GetCallOpts{})
+			require.NoError(t, err)
+			require.Empty(t, calls)
+		})
 	})
 
 	t.Run("rtcd", func(t *testing.T) {
@@ -619,6 +1197,10 @@ func TestCleanUpState(t *testing.T) {
 
 			mockAPI.On("LogDebug", "cleaning up calls state",
 				"origin", mock.AnythingOfType("string")).Once()
+			mockAPI.On("LogInfo", "calls state cleaned up",
+				"origin", mock.AnythingOfType("string"), "reaped", mock.AnythingOfType("string"), "elapsed", mock.AnythingOfType("string")).Once()
+			mockMetrics.On("ObserveCleanUpStateTime", mock.AnythingOfType("float64")).Once()
+			mockMetrics.On("AddCleanUpStateReapedCalls", mock.AnythingOfType("int")).Once()
 
 			err := p.cleanUpState()
 			require.NoError(t, err)
@@ -668,6 +1250,10 @@ func TestCleanUpState(t *testing.T) {
 
 			mockAPI.On("LogDebug", "cleaning up calls state",
 				"origin", mock.AnythingOfType("string")).Once()
+			mockAPI.On("LogInfo", "calls state cleaned up",
+				"origin", mock.AnythingOfType("string"), "reaped", mock.AnythingOfType("string"), "elapsed", mock.AnythingOfType("string")).Once()
+			mockMetrics.On("ObserveCleanUpStateTime", mock.AnythingOfType("float64")).Once()
+			mockMetrics.On("AddCleanUpStateReapedCalls", mock.AnythingOfType("int")).Once()
 
 			mockAPI.On("LogDebug", "creating cluster mutex for call",
 				"origin", mock.AnythingOfType("string"), "channelID", channelID).Once()
@@ -678,6 +1264,12 @@ func TestCleanUpState(t *testing.T) {
 			mockMetrics.On("ObserveClusterMutexLockedTime", "mutex_call", mock.AnythingOfType("float64"))
 
 			mockAPI.On("UpdatePost", mock.AnythingOfType("*model.Post")).Return(&model.Post{Id: postID}, nil).Once()
+
+			mockMetrics.On("IncWebSocketEvent", "out", wsEventCallEnd).Once()
+			mockAPI.On("PublishWebSocketEvent", wsEventCallEnd, map[string]any{
+				"call_id": callID,
+				"reason":  string(public.CallEndReasonNodeFailure),
+			}, &model.WebsocketBroadcast{ChannelId: channelID, ReliableClusterSend: true}).Once()
 			mockAPI.On("GetConfig").Return(&model.Config{}, nil).Once()
 			mockAPI.On("KVDelete", "mutex_call_"+channelID).Return(nil)
 
@@ -750,6 +1342,10 @@ func TestCleanUpState(t *testing.T) {
 
 			mockAPI.On("LogDebug", "cleaning up calls state",
 				"origin", mock.AnythingOfType("string")).Once()
+			mockAPI.On("LogInfo", "calls state cleaned up",
+				"origin", mock.AnythingOfType("string"), "reaped", mock.AnythingOfType("string"), "elapsed", mock.AnythingOfType("string")).Once()
+			mockMetrics.On("ObserveCleanUpStateTime", mock.AnythingOfType("float64")).Once()
+			mockMetrics.On("AddCleanUpStateReapedCalls", mock.AnythingOfType("int")).Once()
 
 			mockAPI.On("LogDebug", "creating cluster mutex for call",
 				"origin", mock.AnythingOfType("string"), "channelID", channelID).Once()
@@ -760,6 +1356,12 @@ func TestCleanUpState(t *testing.T) {
 			mockMetrics.On("ObserveClusterMutexLockedTime", "mutex_call", mock.AnythingOfType("float64"))
 
 			mockAPI.On("UpdatePost", mock.AnythingOfType("*model.Post")).Return(&model.Post{Id: postID}, nil).Once()
+
+			mockMetrics.On("IncWebSocketEvent", "out", wsEventCallEnd).Once()
+			mockAPI.On("PublishWebSocketEvent", wsEventCallEnd, map[string]any{
+				"call_id": callID,
+				"reason":  string(public.CallEndReasonNodeFailure),
+			}, &model.WebsocketBroadcast{ChannelId: channelID, ReliableClusterSend: true}).Once()
 			mockAPI.On("GetConfig").Return(&model.Config{}, nil).Once()
 			mockAPI.On("KVDelete", "mutex_call_"+channelID).Return(nil)
 
@@ -845,6 +1447,10 @@ func TestCleanUpState(t *testing.T) {
 
 			mockAPI.On("LogDebug", "cleaning up calls state",
 				"origin", mock.AnythingOfType("string")).Once()
+			mockAPI.On("LogInfo", "calls state cleaned up",
+				"origin", mock.AnythingOfType("string"), "reaped", mock.AnythingOfType("string"), "elapsed", mock.AnythingOfType("string")).Once()
+			mockMetrics.On("ObserveCleanUpStateTime", mock.AnythingOfType("float64")).Once()
+			mockMetrics.On("AddCleanUpStateReapedCalls", mock.AnythingOfType("int")).Once()
 
 			mockAPI.On("LogDebug", "creating cluster mutex for call",
 				"origin", mock.AnythingOfType("string"), "channelID", channelID).Once()
@@ -940,6 +1546,10 @@ func TestCleanUpState(t *testing.T) {
 
 			mockAPI.On("LogDebug", "cleaning up calls state",
 				"origin", mock.AnythingOfType("string")).Once()
+			mockAPI.On("LogInfo", "calls state cleaned up",
+				"origin", mock.AnythingOfType("string"), "reaped", mock.AnythingOfType("string"), "elapsed", mock.AnythingOfType("string")).Once()
+			mockMetrics.On("ObserveCleanUpStateTime", mock.AnythingOfType("float64")).Once()
+			mockMetrics.On("AddCleanUpStateReapedCalls", mock.AnythingOfType("int")).Once()
 
 			mockAPI.On("LogDebug", "creating cluster mutex for call",
 				"origin", mock.AnythingOfType("string"), "channelID", channelID).Once()
@@ -1033,6 +1643,10 @@ func TestCleanUpState(t *testing.T) {
 
 			mockAPI.On("LogDebug", "cleaning up calls state",
 				"origin", mock.AnythingOfType("string")).Once()
+			mockAPI.On("LogInfo", "calls state cleaned up",
+				"origin", mock.AnythingOfType("string"), "reaped", mock.AnythingOfType("string"), "elapsed", mock.AnythingOfType("string")).Once()
+			mockMetrics.On("ObserveCleanUpStateTime", mock.AnythingOfType("float64")).Once()
+			mockMetrics.On("AddCleanUpStateReapedCalls", mock.AnythingOfType("int")).Once()
 
 			mockAPI.On("LogDebug", "creating cluster mutex for call",
 				"origin", mock.AnythingOfType("string"), "channelID", channelID).Once()
@@ -53,9 +53,35 @@ func (p *Plugin) lockCallReturnState(channelID string) (*callState, error) {
 		return nil, fmt.Errorf("failed to create call lock: %w", err)
 	}
 
-	state, err := p.getCallState(channelID, true)
+	state, err := p.getLockedCallState(channelID)
 	if err != nil {
 		p.unlockCall(channelID)
+		return nil, err
+	}
+
+	return state, nil
+}
+
+// getLockedCallState returns the state of the call in the given channel,
+// saving first any write still buffered for it. Otherwise such write would
+// later replace the ones made based on the returned state.
+// NOTE: this is meant to be called under lock (on channelID).
+func (p *Plugin) getLockedCallState(channelID string) (*callState, error) {
+	state, err := p.getCallState(channelID, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get call state: %w", err)
+	}
+
+	if state == nil || !p.hasCallDBWrites(state.Call.ID) {
+		return state, nil
+	}
+
+	if err := p.flushCallDBWrites(state.Call.ID); err != nil {
+		return nil, fmt.Errorf("failed to save buffered writes: %w", err)
+	}
+
+	state, err = p.getCallState(channelID, true)
+	if err != nil {
 		return nil, fmt.Errorf("failed to get call state: %w", err)
 	}
 
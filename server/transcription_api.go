@@ -51,6 +51,8 @@ func (p *Plugin) transcriptionJobTimeoutChecker(callID, jobID string) {
 
 		p.LogError("timed out waiting for transcriber bot to join", "callID", callID, "jobID", jobID)
 
+		p.releaseTranscriptionSlot(trState.ID)
+
 		trState.EndAt = time.Now().UnixMilli()
 		trState.Props.Err = "failed to start transcriber job: timed out waiting for bot to join call"
 		if err := p.store.UpdateCallJob(trState); err != nil {
@@ -114,6 +116,8 @@ func (p *Plugin) startTranscribingJob(state *callState, callID, userID, trID str
 	trState.Type = public.JobTypeTranscribing
 	trState.CreatorID = userID
 	trState.InitAt = time.Now().UnixMilli()
+	// Transcripts follow the recording they are produced alongside.
+	trState.Props.Anonymized = state.Recording != nil && state.Recording.Props.Anonymized
 
 	if err := p.store.CreateCallJob(trState); err != nil {
 		return fmt.Errorf("failed to create call job: %w", err)
@@ -167,7 +171,7 @@ func (p *Plugin) startTranscribingJob(state *callState, callID, userID, trID str
 	// We don't want to keep the lock while making the API call to the service since it
 	// could take a while to return. We lock again as soon as this returns.
 	p.unlockCall(callID)
-	trJobID, jobErr := p.getJobService().RunJob(job.TypeTranscribing, callID, state.Call.PostID, trState.ID, p.botSession.Token)
+	trJobID, jobErr := p.getJobService().RunJob(job.TypeTranscribing, callID, state.Call.PostID, trState.ID, p.botSession.Token, jobOptions{})
 	state, err := p.lockCallReturnState(callID)
 	if err != nil {
 		return fmt.Errorf("failed to lock call: %w", err)
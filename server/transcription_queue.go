@@ -0,0 +1,244 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+const (
+	transcriptionJobsKey           = "transcription_jobs"
+	transcriptionJobsUpdateRetries = 5
+)
+
+// queuedTranscription is a transcription of an ongoing recording waiting for
+// a slot to start.
+type queuedTranscription struct {
+	ChannelID   string `json:"channel_id"`
+	RecordingID string `json:"recording_id"`
+	EnqueueAt   int64  `json:"enqueue_at"`
+}
+
+// transcriptionJobs keeps track of the transcription jobs running across the
+// cluster so that their number can be capped.
+type transcriptionJobs struct {
+	// Running maps the IDs of the running transcription jobs to the time they
+	// were started at.
+	Running map[string]int64 `json:"running"`
+	// Queue holds the transcriptions waiting to start, in FIFO order.
+	Queue []queuedTranscription `json:"queue"`
+}
+
+func (p *Plugin) getTranscriptionJobs() (*transcriptionJobs, []byte, error) {
+	data, appErr := p.API.KVGet(transcriptionJobsKey)
+	if appErr != nil {
+		return nil, nil, fmt.Errorf("failed to get transcription jobs: %w", appErr)
+	}
+
+	jobs := &transcriptionJobs{}
+	if data != nil {
+		if err := json.Unmarshal(data, jobs); err != nil {
+			return nil, nil, fmt.Errorf("failed to unmarshal transcription jobs: %w", err)
+		}
+	}
+	if jobs.Running == nil {
+		jobs.Running = map[string]int64{}
+	}
+
+	return jobs, data, nil
+}
+
+// pruneTranscriptionJobs drops the entries that outlived the maximum duration
+// of their job. This avoids leaking slots because of jobs that never reported
+// back (e.g. crashed).
+func pruneTranscriptionJobs(jobs *transcriptionJobs, maxRecDurationMinutes int, now time.Time) {
+	maxRecDuration := time.Duration(maxRecDurationMinutes) * time.Minute
+
+	// Transcription jobs can run for up to twice the recording duration.
+	for id, startAt := range jobs.Running {
+		if now.Sub(time.UnixMilli(startAt)) > 2*maxRecDuration+transcriptionJobStartTimeout {
+			delete(jobs.Running, id)
+		}
+	}
+
+	queue := jobs.Queue[:0]
+	for _, qt := range jobs.Queue {
+		if now.Sub(time.UnixMilli(qt.EnqueueAt)) <= maxRecDuration {
+			queue = append(queue, qt)
+		}
+	}
+	jobs.Queue = queue
+}
+
+// updateTranscriptionJobs applies the given update to the tracked
+// transcription jobs. The update should return false if nothing changed.
+// Concurrent updates from different nodes are detected and retried.
+func (p *Plugin) updateTranscriptionJobs(update func(jobs *transcriptionJobs) bool) error {
+	for i := 0; i < transcriptionJobsUpdateRetries; i++ {
+		jobs, oldData, err := p.getTranscriptionJobs()
+		if err != nil {
+			return err
+		}
+
+		pruneTranscriptionJobs(jobs, *p.getConfiguration().MaxRecordingDuration, time.Now())
+
+		if !update(jobs) {
+			return nil
+		}
+
+		data, err := json.Marshal(jobs)
+		if err != nil {
+			return fmt.Errorf("failed to marshal transcription jobs: %w", err)
+		}
+
+		ok, appErr := p.API.KVSetWithOptions(transcriptionJobsKey, data, model.PluginKVSetOptions{
+			Atomic:   true,
+			OldValue: oldData,
+		})
+		if appErr != nil {
+			return fmt.Errorf("failed to set transcription jobs: %w", appErr)
+		}
+		if ok {
+			p.metrics.SetTranscriptionQueueDepth(len(jobs.Queue))
+			return nil
+		}
+	}
+
+	return fmt.Errorf("failed to update transcription jobs: too many concurrent updates")
+}
+
+// acquireTranscriptionSlot reserves a slot for the transcription job with the
+// given ID. If none is available, the transcription of the recording is
+// queued and false is returned.
+func (p *Plugin) acquireTranscriptionSlot(channelID, recID, trID string) (bool, error) {
+	limit := *p.getConfiguration().MaxConcurrentTranscriptionJobs
+	if limit == 0 {
+		return true, nil
+	}
+
+	var acquired bool
+	err := p.updateTranscriptionJobs(func(jobs *transcriptionJobs) bool {
+		// Transcriptions already waiting go first.
+		acquired = len(jobs.Queue) == 0 && len(jobs.Running) < limit
+		if acquired {
+			jobs.Running[trID] = time.Now().UnixMilli()
+		} else {
+			jobs.Queue = append(jobs.Queue, queuedTranscription{
+				ChannelID:   channelID,
+				RecordingID: recID,
+				EnqueueAt:   time.Now().UnixMilli(),
+			})
+		}
+		return true
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return acquired, nil
+}
+
+// releaseTranscriptionSlot frees the slot held by the given transcription job
+// and starts the next queued transcription, if any.
+func (p *Plugin) releaseTranscriptionSlot(trID string) {
+	if *p.getConfiguration().MaxConcurrentTranscriptionJobs == 0 {
+		return
+	}
+
+	err := p.updateTranscriptionJobs(func(jobs *transcriptionJobs) bool {
+		if _, ok := jobs.Running[trID]; !ok {
+			return false
+		}
+		delete(jobs.Running, trID)
+		return true
+	})
+	if err != nil {
+		p.LogError("failed to release transcription slot", "err", err.Error(), "trID", trID)
+	}
+
+	// Starting the next transcription requires locking its call, which we may
+	// be holding already.
+	go p.processTranscriptionQueue()
+}
+
+// processTranscriptionQueue starts queued transcriptions while slots are
+// available.
+func (p *Plugin) processTranscriptionQueue() {
+	for {
+		limit := *p.getConfiguration().MaxConcurrentTranscriptionJobs
+		trID := model.NewId()
+		var next *queuedTranscription
+		err := p.updateTranscriptionJobs(func(jobs *transcriptionJobs) bool {
+			next = nil
+			if len(jobs.Queue) == 0 || (limit > 0 && len(jobs.Running) >= limit) {
+				return false
+			}
+			next = &queuedTranscription{}
+			*next = jobs.Queue[0]
+			jobs.Queue = jobs.Queue[1:]
+			jobs.Running[trID] = time.Now().UnixMilli()
+			return true
+		})
+		if err != nil {
+			p.LogError("failed to dequeue transcription", "err", err.Error())
+			return
+		}
+		if next == nil {
+			return
+		}
+
+		p.metrics.ObserveTranscriptionQueueWaitTime(time.Since(time.UnixMilli(next.EnqueueAt)).Seconds())
+
+		if err := p.startQueuedTranscription(*next, trID); err != nil {
+			p.LogWarn("failed to start queued transcription", "err", err.Error(),
+				"channelID", next.ChannelID, "recID", next.RecordingID)
+			if err := p.updateTranscriptionJobs(func(jobs *transcriptionJobs) bool {
+				delete(jobs.Running, trID)
+				return true
+			}); err != nil {
+				p.LogError("failed to release transcription slot", "err", err.Error(), "trID", trID)
+			}
+		}
+	}
+}
+
+// startQueuedTranscription starts the transcription job for a queued
+// recording. The transcriber captures audio live so a recording that ended
+// while waiting can no longer be transcribed.
+func (p *Plugin) startQueuedTranscription(qt queuedTranscription, trID string) error {
+	if !p.getConfiguration().transcriptionsEnabled() {
+		return fmt.Errorf("transcriptions are not enabled")
+	}
+
+	if p.getJobService() == nil {
+		return fmt.Errorf("job service is not initialized")
+	}
+
+	state, err := p.lockCallReturnState(qt.ChannelID)
+	if err != nil {
+		return fmt.Errorf("failed to lock call: %w", err)
+	}
+	defer p.unlockCall(qt.ChannelID)
+
+	if state == nil || state.Recording == nil || state.Recording.ID != qt.RecordingID || state.Recording.EndAt != 0 {
+		return fmt.Errorf("recording has ended")
+	}
+
+	postID := state.Call.PostID
+
+	p.LogDebug("starting queued transcription job", "callID", qt.ChannelID, "recID", qt.RecordingID, "trID", trID)
+	if err := p.startTranscribingJob(state, qt.ChannelID, state.Recording.CreatorID, trID); err != nil {
+		return fmt.Errorf("failed to start transcribing job: %w", err)
+	}
+
+	if err := p.saveRecordingMetadata(postID, qt.RecordingID, trID); err != nil {
+		p.LogError("failed to save recording metadata", "err", err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,101 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	serverMocks "github.com/mattermost/mattermost-plugin-calls/server/mocks/github.com/mattermost/mattermost-plugin-calls/server/interfaces"
+	pluginMocks "github.com/mattermost/mattermost-plugin-calls/server/mocks/github.com/mattermost/mattermost/server/public/plugin"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPruneTranscriptionJobs(t *testing.T) {
+	now := time.Now()
+	jobs := &transcriptionJobs{
+		Running: map[string]int64{
+			"trA": now.Add(-time.Hour).UnixMilli(),
+			"trB": now.Add(-3 * time.Hour).UnixMilli(),
+		},
+		Queue: []queuedTranscription{
+			{RecordingID: "recA", EnqueueAt: now.Add(-2 * time.Hour).UnixMilli()},
+			{RecordingID: "recB", EnqueueAt: now.Add(-time.Minute).UnixMilli()},
+		},
+	}
+
+	pruneTranscriptionJobs(jobs, 60, now)
+
+	require.Equal(t, map[string]int64{"trA": now.Add(-time.Hour).UnixMilli()}, jobs.Running)
+	require.Equal(t, []queuedTranscription{
+		{RecordingID: "recB", EnqueueAt: now.Add(-time.Minute).UnixMilli()},
+	}, jobs.Queue)
+}
+
+func TestAcquireTranscriptionSlot(t *testing.T) {
+	mockAPI := &pluginMocks.MockAPI{}
+	mockMetrics := &serverMocks.MockMetrics{}
+	defer mockAPI.AssertExpectations(t)
+	defer mockMetrics.AssertExpectations(t)
+
+	p := &Plugin{
+		MattermostPlugin: plugin.MattermostPlugin{
+			API: mockAPI,
+		},
+		metrics: mockMetrics,
+	}
+
+	cfg := &configuration{}
+	cfg.SetDefaults()
+	p.configuration = cfg
+
+	channelID := model.NewId()
+
+	t.Run("unlimited", func(t *testing.T) {
+		ok, err := p.acquireTranscriptionSlot(channelID, "recA", "trA")
+		require.NoError(t, err)
+		require.True(t, ok)
+	})
+
+	cfg.MaxConcurrentTranscriptionJobs = model.NewPointer(1)
+
+	t.Run("slot available", func(t *testing.T) {
+		mockAPI.On("KVGet", transcriptionJobsKey).Return(nil, nil).Once()
+		mockAPI.On("KVSetWithOptions", transcriptionJobsKey, mock.MatchedBy(func(data []byte) bool {
+			var jobs transcriptionJobs
+			require.NoError(t, json.Unmarshal(data, &jobs))
+			_, ok := jobs.Running["trA"]
+			return ok && len(jobs.Queue) == 0
+		}), model.PluginKVSetOptions{Atomic: true}).Return(true, nil).Once()
+		mockMetrics.On("SetTranscriptionQueueDepth", 0).Once()
+
+		ok, err := p.acquireTranscriptionSlot(channelID, "recA", "trA")
+		require.NoError(t, err)
+		require.True(t, ok)
+	})
+
+	t.Run("queued", func(t *testing.T) {
+		running, err := json.Marshal(transcriptionJobs{Running: map[string]int64{"trA": time.Now().UnixMilli()}})
+		require.NoError(t, err)
+
+		mockAPI.On("KVGet", transcriptionJobsKey).Return(running, nil).Once()
+		mockAPI.On("KVSetWithOptions", transcriptionJobsKey, mock.MatchedBy(func(data []byte) bool {
+			var jobs transcriptionJobs
+			require.NoError(t, json.Unmarshal(data, &jobs))
+			_, ok := jobs.Running["trB"]
+			return !ok && len(jobs.Queue) == 1 && jobs.Queue[0].ChannelID == channelID && jobs.Queue[0].RecordingID == "recB"
+		}), model.PluginKVSetOptions{Atomic: true, OldValue: running}).Return(true, nil).Once()
+		mockMetrics.On("SetTranscriptionQueueDepth", 1).Once()
+
+		ok, err := p.acquireTranscriptionSlot(channelID, "recB", "trB")
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+}
@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"io"
 	"math"
+	"net"
 	"net/url"
 	"regexp"
 	"sort"
@@ -91,16 +92,21 @@ func getChannelNameForNotification(channel *model.Channel, sender *model.User, u
 	}
 }
 
-func unpackSDPData(data []byte) ([]byte, error) {
+// unpackSDPData decompresses the given SDP data. errSDPTooLarge is returned if
+// the decompressed data exceeds maxSize bytes.
+func unpackSDPData(data []byte, maxSize int) ([]byte, error) {
 	buf := bytes.NewBuffer(data)
 	rd, err := zlib.NewReader(buf)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create reader: %w", err)
 	}
-	unpacked, err := io.ReadAll(rd)
+	unpacked, err := io.ReadAll(io.LimitReader(rd, int64(maxSize)+1))
 	if err != nil {
 		return nil, fmt.Errorf("failed to read data: %w", err)
 	}
+	if len(unpacked) > maxSize {
+		return nil, errSDPTooLarge
+	}
 	return unpacked, nil
 }
 
@@ -139,6 +145,56 @@ func checkMinVersion(minVersion, currVersion string) error {
 	return nil
 }
 
+// getInterfaceIPs resolves the given network interface name or local IP
+// address to the list of IPs the RTC server should listen on. IPv6 addresses
+// are only included if enabled. Link-local addresses are always skipped.
+func getInterfaceIPs(iface string, enableIPv6 bool) ([]string, error) {
+	if ip := net.ParseIP(iface); ip != nil {
+		addrs, err := net.InterfaceAddrs()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get interface addresses: %w", err)
+		}
+		for _, addr := range addrs {
+			if ipNet, ok := addr.(*net.IPNet); ok && ipNet.IP.Equal(ip) {
+				return []string{ip.String()}, nil
+			}
+		}
+		return nil, fmt.Errorf("address %s is not assigned to any local interface", iface)
+	}
+
+	netIface, err := net.InterfaceByName(iface)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find interface %q: %w", iface, err)
+	}
+
+	if netIface.Flags&net.FlagUp == 0 {
+		return nil, fmt.Errorf("interface %q is down", iface)
+	}
+
+	addrs, err := netIface.Addrs()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get addresses for interface %q: %w", iface, err)
+	}
+
+	var ips []string
+	for _, addr := range addrs {
+		ipNet, ok := addr.(*net.IPNet)
+		if !ok || ipNet.IP.IsLinkLocalUnicast() {
+			continue
+		}
+		if ipNet.IP.To4() == nil && !enableIPv6 {
+			continue
+		}
+		ips = append(ips, ipNet.IP.String())
+	}
+
+	if len(ips) == 0 {
+		return nil, fmt.Errorf("no usable address found on interface %q", iface)
+	}
+
+	return ips, nil
+}
+
 func mapKeys[K comparable, V any](m map[K]V) []K {
 	keys := make([]K, 0, len(m))
 	for k := range m {
@@ -5,6 +5,7 @@ package main
 
 import (
 	"errors"
+	"net"
 	"testing"
 
 	"github.com/mattermost/mattermost-plugin-calls/server/public"
@@ -307,3 +308,46 @@ func TestGetUserIDsFromSessions(t *testing.T) {
 		}, userIDs)
 	})
 }
+
+func TestGetInterfaceIPs(t *testing.T) {
+	ifaces, err := net.Interfaces()
+	require.NoError(t, err)
+
+	var loopback *net.Interface
+	for i := range ifaces {
+		if ifaces[i].Flags&net.FlagLoopback != 0 && ifaces[i].Flags&net.FlagUp != 0 {
+			loopback = &ifaces[i]
+			break
+		}
+	}
+	if loopback == nil {
+		t.Skip("no loopback interface available")
+	}
+
+	t.Run("by name", func(t *testing.T) {
+		ips, err := getInterfaceIPs(loopback.Name, false)
+		require.NoError(t, err)
+		require.Contains(t, ips, "127.0.0.1")
+		for _, ip := range ips {
+			require.NotNil(t, net.ParseIP(ip).To4())
+		}
+	})
+
+	t.Run("by address", func(t *testing.T) {
+		ips, err := getInterfaceIPs("127.0.0.1", false)
+		require.NoError(t, err)
+		require.Equal(t, []string{"127.0.0.1"}, ips)
+	})
+
+	t.Run("missing interface", func(t *testing.T) {
+		ips, err := getInterfaceIPs("notaniface0", false)
+		require.ErrorContains(t, err, `failed to find interface "notaniface0"`)
+		require.Empty(t, ips)
+	})
+
+	t.Run("unassigned address", func(t *testing.T) {
+		ips, err := getInterfaceIPs("192.0.2.1", false)
+		require.EqualError(t, err, "address 192.0.2.1 is not assigned to any local interface")
+		require.Empty(t, ips)
+	})
+}
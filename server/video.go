@@ -0,0 +1,97 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+var errVideoSlotsFull = errors.New("video slots are full")
+
+// handleClientMessageTypeVideo keeps track of which sessions are publishing
+// camera video so that the configured maximum number of video publishers can
+// be enforced. Turning on the camera is refused altogether when video is
+// disabled, either globally or for an audio only call.
+func (p *Plugin) handleClientMessageTypeVideo(us *session, msg clientMessage) error {
+	cfg := p.getConfiguration()
+	if msg.Type == clientMessageTypeVideoOn && cfg.videoDisabled() {
+		return fmt.Errorf("video is not allowed")
+	}
+
+	channelID := us.getChannelID()
+	state, err := p.lockCallReturnState(channelID)
+	if err != nil {
+		return fmt.Errorf("failed to lock call: %w", err)
+	}
+	defer p.unlockCall(channelID)
+	if state == nil {
+		return fmt.Errorf("no call ongoing")
+	}
+
+	if state.sessions[us.originalConnID] == nil {
+		return fmt.Errorf("user session is missing from call state")
+	}
+
+	if msg.Type == clientMessageTypeVideoOn && state.Call.Props.AudioOnly {
+		return fmt.Errorf("video is not allowed")
+	}
+
+	maxPublishers := cfg.getMaxVideoPublishers()
+	publishing := slices.Contains(state.Call.Props.VideoSessionIDs, us.originalConnID)
+
+	if msg.Type == clientMessageTypeVideoOn {
+		if publishing {
+			return nil
+		}
+
+		if maxPublishers > 0 && len(state.Call.Props.VideoSessionIDs) >= maxPublishers {
+			p.LogDebug("video publishing rejected, slots are full", "userID", us.userID, "connID", us.connID, "callID", us.callID)
+			p.publishWebSocketEvent(wsEventUserVideoRejected, map[string]interface{}{
+				"connID":               us.connID,
+				"call_id":              us.callID,
+				"reason":               errVideoSlotsFull.Error(),
+				"video_publishers":     len(state.Call.Props.VideoSessionIDs),
+				"max_video_publishers": maxPublishers,
+			}, &WebSocketBroadcast{ConnectionID: us.connID})
+			return nil
+		}
+
+		state.Call.Props.VideoSessionIDs = append(state.Call.Props.VideoSessionIDs, us.originalConnID)
+	} else {
+		if !publishing {
+			return nil
+		}
+		state.Call.Props.VideoSessionIDs = removeVideoSession(state.Call.Props.VideoSessionIDs, us.originalConnID)
+	}
+
+	if err := p.store.UpdateCall(&state.Call); err != nil {
+		return fmt.Errorf("failed to update call: %w", err)
+	}
+
+	evType := wsEventUserVideoOn
+	if msg.Type == clientMessageTypeVideoOff {
+		evType = wsEventUserVideoOff
+	}
+	p.publishWebSocketEvent(evType, map[string]interface{}{
+		"userID":               us.userID,
+		"session_id":           us.originalConnID,
+		"call_id":              us.callID,
+		"video_publishers":     len(state.Call.Props.VideoSessionIDs),
+		"max_video_publishers": maxPublishers,
+	}, &WebSocketBroadcast{
+		ChannelID:           us.getChannelID(),
+		ReliableClusterSend: true,
+		UserIDs:             getUserIDsFromSessions(state.sessions),
+	})
+
+	return nil
+}
+
+func removeVideoSession(sessionIDs []string, sessionID string) []string {
+	return slices.DeleteFunc(sessionIDs, func(id string) bool {
+		return id == sessionID
+	})
+}
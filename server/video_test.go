@@ -0,0 +1,157 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-calls/server/cluster"
+	"github.com/mattermost/mattermost-plugin-calls/server/public"
+
+	serverMocks "github.com/mattermost/mattermost-plugin-calls/server/mocks/github.com/mattermost/mattermost-plugin-calls/server/interfaces"
+	pluginMocks "github.com/mattermost/mattermost-plugin-calls/server/mocks/github.com/mattermost/mattermost/server/public/plugin"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemoveVideoSession(t *testing.T) {
+	require.Empty(t, removeVideoSession(nil, "sessionA"))
+	require.Equal(t, []string{"sessionA", "sessionC"}, removeVideoSession([]string{"sessionA", "sessionB", "sessionC"}, "sessionB"))
+	require.Equal(t, []string{"sessionA"}, removeVideoSession([]string{"sessionA"}, "sessionB"))
+}
+
+func TestHandleClientMessageTypeVideo(t *testing.T) {
+	mockAPI := &pluginMocks.MockAPI{}
+	mockMetrics := &serverMocks.MockMetrics{}
+
+	defer mockAPI.AssertExpectations(t)
+	defer mockMetrics.AssertExpectations(t)
+
+	botID := model.NewId()
+	p := Plugin{
+		MattermostPlugin: plugin.MattermostPlugin{
+			API: mockAPI,
+		},
+		callsClusterLocks: map[string]*cluster.Mutex{},
+		metrics:           mockMetrics,
+		botSession:        &model.Session{UserId: botID},
+	}
+
+	cfg := &configuration{}
+	cfg.SetDefaults()
+	cfg.MaxVideoPublishers = model.NewPointer(1)
+	p.configuration = cfg
+
+	t.Run("video disabled", func(t *testing.T) {
+		cfg.DisableVideo = model.NewPointer(true)
+		defer func() { cfg.DisableVideo = model.NewPointer(false) }()
+
+		us := newUserSession(model.NewId(), model.NewId(), model.NewId(), model.NewId(), true)
+		err := p.handleClientMessageTypeVideo(us, clientMessage{Type: clientMessageTypeVideoOn})
+		require.EqualError(t, err, "video is not allowed")
+	})
+
+	store, tearDown := NewTestStore(t)
+	t.Cleanup(tearDown)
+	p.store = store
+
+	mockAPI.On("LogDebug", mock.Anything, mock.Anything, mock.Anything,
+		mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+		mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mockAPI.On("LogDebug", mock.Anything, mock.Anything, mock.Anything,
+		mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+		mock.Anything, mock.Anything)
+	mockAPI.On("KVSetWithOptions", mock.Anything, mock.Anything, mock.Anything).Return(true, nil)
+	mockAPI.On("KVDelete", mock.Anything).Return(nil)
+	mockMetrics.On("ObserveClusterMutexGrabTime", "mutex_call", mock.AnythingOfType("float64"))
+	mockMetrics.On("ObserveClusterMutexLockedTime", "mutex_call", mock.AnythingOfType("float64"))
+	mockMetrics.On("ObserveAppHandlersTime", mock.AnythingOfType("string"), mock.AnythingOfType("float64"))
+
+	channelID := model.NewId()
+	userA := model.NewId()
+	userB := model.NewId()
+	connA := model.NewId()
+	connB := model.NewId()
+
+	call := &public.Call{
+		ID:        model.NewId(),
+		CreateAt:  time.Now().UnixMilli(),
+		ChannelID: channelID,
+		StartAt:   time.Now().UnixMilli(),
+		PostID:    model.NewId(),
+		ThreadID:  model.NewId(),
+		OwnerID:   userA,
+	}
+	err := p.store.CreateCall(call)
+	require.NoError(t, err)
+
+	for connID, userID := range map[string]string{connA: userA, connB: userB} {
+		err = p.store.CreateCallSession(&public.CallSession{
+			ID:     connID,
+			CallID: call.ID,
+			UserID: userID,
+			JoinAt: time.Now().UnixMilli(),
+		})
+		require.NoError(t, err)
+	}
+
+	usA := newUserSession(userA, channelID, connA, call.ID, true)
+	usB := newUserSession(userB, channelID, connB, call.ID, true)
+
+	getVideoSessionIDs := func(t *testing.T) []string {
+		t.Helper()
+		state, err := p.getCallState(channelID, false)
+		require.NoError(t, err)
+		return state.Call.Props.VideoSessionIDs
+	}
+
+	t.Run("video on", func(t *testing.T) {
+		mockMetrics.On("IncWebSocketEvent", "out", wsEventUserVideoOn).Twice()
+		mockAPI.On("PublishWebSocketEvent", wsEventUserVideoOn, mock.Anything, &model.WebsocketBroadcast{UserId: botID}).Once()
+		mockAPI.On("PublishWebSocketEvent", wsEventUserVideoOn, mock.MatchedBy(func(data map[string]any) bool {
+			return data["session_id"] == connA && data["video_publishers"] == 1 && data["max_video_publishers"] == 1
+		}), mock.Anything).Twice()
+
+		err := p.handleClientMessageTypeVideo(usA, clientMessage{Type: clientMessageTypeVideoOn})
+		require.NoError(t, err)
+		require.Equal(t, []string{connA}, getVideoSessionIDs(t))
+
+		// Idempotent
+		err = p.handleClientMessageTypeVideo(usA, clientMessage{Type: clientMessageTypeVideoOn})
+		require.NoError(t, err)
+		require.Equal(t, []string{connA}, getVideoSessionIDs(t))
+	})
+
+	t.Run("slots full", func(t *testing.T) {
+		mockMetrics.On("IncWebSocketEvent", "out", wsEventUserVideoRejected).Once()
+		mockAPI.On("PublishWebSocketEvent", wsEventUserVideoRejected, map[string]any{
+			"connID":               connB,
+			"call_id":              call.ID,
+			"reason":               "video slots are full",
+			"video_publishers":     1,
+			"max_video_publishers": 1,
+		}, &model.WebsocketBroadcast{ConnectionId: connB}).Once()
+
+		err := p.handleClientMessageTypeVideo(usB, clientMessage{Type: clientMessageTypeVideoOn})
+		require.NoError(t, err)
+		require.Equal(t, []string{connA}, getVideoSessionIDs(t))
+	})
+
+	t.Run("video off", func(t *testing.T) {
+		mockMetrics.On("IncWebSocketEvent", "out", wsEventUserVideoOff).Twice()
+		mockAPI.On("PublishWebSocketEvent", wsEventUserVideoOff, mock.Anything, &model.WebsocketBroadcast{UserId: botID}).Once()
+		mockAPI.On("PublishWebSocketEvent", wsEventUserVideoOff, mock.MatchedBy(func(data map[string]any) bool {
+			return data["session_id"] == connA && data["video_publishers"] == 0
+		}), mock.Anything).Twice()
+
+		err := p.handleClientMessageTypeVideo(usA, clientMessage{Type: clientMessageTypeVideoOff})
+		require.NoError(t, err)
+		require.Empty(t, getVideoSessionIDs(t))
+	})
+}
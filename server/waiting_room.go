@@ -0,0 +1,432 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-calls/server/public"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/i18n"
+)
+
+// pendingAdmission holds a join request for a call with the waiting room
+// enabled until the host admits it.
+type pendingAdmission struct {
+	userID        string
+	authSessionID string
+	joinData      callsJoinData
+}
+
+// waitingSessionClient is a session waiting to be admitted, as shown to the
+// host.
+type waitingSessionClient struct {
+	SessionID   string `json:"session_id"`
+	UserID      string `json:"user_id"`
+	DisplayName string `json:"display_name"`
+	EnterAt     int64  `json:"enter_at"`
+	WaitMs      int64  `json:"wait_ms"`
+}
+
+// shouldEnableWaitingRoom returns whether calls started in the given channel
+// should have the waiting room enabled.
+func (p *Plugin) shouldEnableWaitingRoom(callsChannel *public.CallsChannel) bool {
+	if callsChannel != nil {
+		if waitingRoom, ok := callsChannel.Props["waiting_room"].(bool); ok {
+			return waitingRoom
+		}
+	}
+	cfg := p.getConfiguration()
+	return cfg.WaitingRoom != nil && *cfg.WaitingRoom
+}
+
+// waitingRoomHostID returns the user expected to admit participants. A host
+// assigned ahead of time (e.g. the meeting organizer) is returned even if
+// they haven't joined yet.
+func waitingRoomHostID(state *callState) string {
+	if state.Call.Props.HostLockedUserID != "" {
+		return state.Call.Props.HostLockedUserID
+	}
+	return state.Call.GetHostID()
+}
+
+// bypassesWaitingRoom returns whether the given user can join the call
+// without being admitted.
+func bypassesWaitingRoom(state *callState, userID string) bool {
+	if userID == state.Call.Props.HostLockedUserID {
+		return true
+	}
+
+	for _, hostID := range state.Call.Props.Hosts {
+		if userID == hostID {
+			return true
+		}
+	}
+
+	// Users already admitted can join from other devices.
+	return state.isUserIDInCall(userID)
+}
+
+// requestAdmission holds the join request if the call has the waiting room
+// enabled, notifying the host. It returns whether the user is waiting.
+func (p *Plugin) requestAdmission(userID, connID, authSessionID string, joinData callsJoinData) (bool, error) {
+	channelID := joinData.ChannelID
+
+	state, err := p.lockCallReturnState(channelID)
+	if err != nil {
+		return false, fmt.Errorf("failed to lock call: %w", err)
+	}
+	defer p.unlockCall(channelID)
+
+	if state == nil || !state.Call.Props.WaitingRoom || bypassesWaitingRoom(state, userID) {
+		return false, nil
+	}
+
+	pa := &pendingAdmission{
+		userID:        userID,
+		authSessionID: authSessionID,
+		joinData:      joinData,
+	}
+
+	if err := addConnEntry(p, p.pendingAdmissions, connID, pa, "in the waiting room"); err != nil {
+		return false, err
+	}
+
+	if state.Call.Props.Waiting == nil {
+		state.Call.Props.Waiting = map[string]public.CallWaitingSession{}
+	}
+	state.Call.Props.Waiting[connID] = public.CallWaitingSession{
+		UserID:  userID,
+		NodeID:  p.nodeID,
+		EnterAt: time.Now().UnixMilli(),
+	}
+	if err := p.store.UpdateCall(&state.Call); err != nil {
+		p.takePendingAdmission(connID)
+		return false, fmt.Errorf("failed to update call: %w", err)
+	}
+
+	p.LogDebug("user is waiting to be admitted", "userID", userID, "connID", connID, "channelID", channelID, "callID", state.Call.ID)
+
+	p.publishWebSocketEvent(wsEventCallWaiting, map[string]interface{}{
+		"connID":     connID,
+		"channel_id": channelID,
+		"call_id":    state.Call.ID,
+	}, &WebSocketBroadcast{ConnectionID: connID})
+
+	p.notifyWaitingRoomHost(state, userID)
+
+	return true, nil
+}
+
+// takePendingAdmission removes and returns the pending admission for the
+// given connection, if any.
+func (p *Plugin) takePendingAdmission(connID string) *pendingAdmission {
+	return takeConnEntry(p, p.pendingAdmissions, connID)
+}
+
+// leaveWaitingRoom drops the pending admission for the given connection,
+// letting the host know the user is no longer waiting.
+func (p *Plugin) leaveWaitingRoom(connID string) {
+	pa := p.takePendingAdmission(connID)
+	if pa == nil {
+		return
+	}
+
+	channelID := pa.joinData.ChannelID
+
+	p.LogDebug("user left the waiting room", "userID", pa.userID, "connID", connID, "channelID", channelID)
+
+	state, err := p.lockCallReturnState(channelID)
+	if err != nil {
+		p.LogError("failed to lock call", "err", err.Error(), "channelID", channelID)
+		return
+	}
+	defer p.unlockCall(channelID)
+
+	if state == nil {
+		return
+	}
+
+	if _, ok := state.Call.Props.Waiting[connID]; !ok {
+		return
+	}
+
+	delete(state.Call.Props.Waiting, connID)
+	if err := p.store.UpdateCall(&state.Call); err != nil {
+		p.LogError("failed to update call", "err", err.Error(), "callID", state.Call.ID)
+		return
+	}
+
+	p.notifyWaitingRoomHost(state, "")
+}
+
+// resolveAdmission resumes or drops the join request held for the given
+// connection once the host has made a decision.
+func (p *Plugin) resolveAdmission(connID string, admitted bool) {
+	pa := p.takePendingAdmission(connID)
+	if pa == nil {
+		return
+	}
+
+	if !admitted {
+		p.publishWebSocketEvent(wsEventCallWaitingDenied, map[string]interface{}{
+			"connID":     connID,
+			"channel_id": pa.joinData.ChannelID,
+		}, &WebSocketBroadcast{ConnectionID: connID})
+		return
+	}
+
+	pa.joinData.admitted = true
+
+	if err := p.handleJoin(pa.userID, connID, pa.authSessionID, pa.joinData); err != nil {
+		p.LogWarn(err.Error(), "userID", pa.userID, "connID", connID)
+		p.publishWebSocketEvent(wsEventError, map[string]interface{}{
+			"data":   err.Error(),
+			"connID": connID,
+		}, &WebSocketBroadcast{ConnectionID: connID, ReliableClusterSend: true})
+	}
+}
+
+// sendAdmissionResult forwards the host's decision to the node holding the
+// join request.
+func (p *Plugin) sendAdmissionResult(connID string, ws public.CallWaitingSession, admitted bool) error {
+	if ws.NodeID == p.nodeID {
+		// Joining requires locking the call, which the caller is holding.
+		go p.resolveAdmission(connID, admitted)
+		return nil
+	}
+
+	msgType := clusterMessageTypeAdmit
+	if !admitted {
+		msgType = clusterMessageTypeDeny
+	}
+
+	return p.sendClusterMessage(clusterMessage{
+		ConnID:   connID,
+		UserID:   ws.UserID,
+		SenderID: p.nodeID,
+	}, msgType, ws.NodeID)
+}
+
+// admitWaitingSessions lets the given waiting session, or all of them, join
+// the call. Denied sessions are dropped from the waiting room instead.
+func (p *Plugin) admitWaitingSessions(requesterID, channelID, sessionID string, all, admitted bool) error {
+	state, err := p.lockCallReturnState(channelID)
+	if err != nil {
+		return fmt.Errorf("failed to lock call: %w", err)
+	}
+	defer p.unlockCall(channelID)
+
+	if state == nil {
+		return ErrNoCallOngoing
+	}
+
+	if requesterID != state.Call.GetHostID() {
+		if isAdmin := p.API.HasPermissionTo(requesterID, model.PermissionManageSystem); !isAdmin {
+			return ErrNoPermissions
+		}
+	}
+
+	resolved := map[string]public.CallWaitingSession{}
+	if all {
+		resolved = state.Call.Props.Waiting
+		state.Call.Props.Waiting = nil
+	} else {
+		ws, ok := state.Call.Props.Waiting[sessionID]
+		if !ok {
+			return ErrNotInCall
+		}
+		resolved[sessionID] = ws
+		delete(state.Call.Props.Waiting, sessionID)
+	}
+
+	if len(resolved) == 0 {
+		return nil
+	}
+
+	if err := p.store.UpdateCall(&state.Call); err != nil {
+		return fmt.Errorf("failed to update call: %w", err)
+	}
+
+	for connID, ws := range resolved {
+		if err := p.sendAdmissionResult(connID, ws, admitted); err != nil {
+			p.LogError("failed to send admission result", "err", err.Error(), "connID", connID, "callID", state.Call.ID)
+		}
+	}
+
+	p.notifyWaitingRoomHost(state, "")
+
+	return nil
+}
+
+// setWaitingRoom turns the waiting room on or off for the given call. Turning
+// it off admits everyone waiting.
+func (p *Plugin) setWaitingRoom(requesterID, channelID string, enabled bool) error {
+	state, err := p.lockCallReturnState(channelID)
+	if err != nil {
+		return fmt.Errorf("failed to lock call: %w", err)
+	}
+	defer p.unlockCall(channelID)
+
+	if state == nil {
+		return ErrNoCallOngoing
+	}
+
+	if requesterID != state.Call.GetHostID() {
+		if isAdmin := p.API.HasPermissionTo(requesterID, model.PermissionManageSystem); !isAdmin {
+			return ErrNoPermissions
+		}
+	}
+
+	if state.Call.Props.WaitingRoom == enabled {
+		return nil
+	}
+
+	waiting := state.Call.Props.Waiting
+	state.Call.Props.WaitingRoom = enabled
+	state.Call.Props.Waiting = nil
+	if err := p.store.UpdateCall(&state.Call); err != nil {
+		return fmt.Errorf("failed to update call: %w", err)
+	}
+
+	for connID, ws := range waiting {
+		if err := p.sendAdmissionResult(connID, ws, true); err != nil {
+			p.LogError("failed to send admission result", "err", err.Error(), "connID", connID, "callID", state.Call.ID)
+		}
+	}
+
+	p.publishWebSocketEvent(wsEventCallWaitingRoom, map[string]interface{}{
+		"call_id": state.Call.ID,
+		"enabled": enabled,
+	}, &WebSocketBroadcast{
+		ChannelID:           channelID,
+		ReliableClusterSend: true,
+		UserIDs:             getUserIDsFromSessions(state.sessions),
+	})
+
+	if len(waiting) > 0 {
+		p.notifyWaitingRoomHost(state, "")
+	}
+
+	return nil
+}
+
+// getWaitingSessions returns the sessions waiting to be admitted, longest
+// waiting first, with names formatted for the given viewer.
+func (p *Plugin) getWaitingSessions(state *callState, viewerID string) []waitingSessionClient {
+	now := time.Now().UnixMilli()
+	nameFormat := p.getNotificationNameFormat(viewerID)
+
+	sessions := make([]waitingSessionClient, 0, len(state.Call.Props.Waiting))
+	for connID, ws := range state.Call.Props.Waiting {
+		wsc := waitingSessionClient{
+			SessionID: connID,
+			UserID:    ws.UserID,
+			EnterAt:   ws.EnterAt,
+			WaitMs:    now - ws.EnterAt,
+		}
+		if user, appErr := p.API.GetUser(ws.UserID); appErr != nil {
+			p.LogWarn("failed to get user", "err", appErr.Error(), "userID", ws.UserID)
+		} else {
+			wsc.DisplayName = user.GetDisplayName(nameFormat)
+		}
+		sessions = append(sessions, wsc)
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		if sessions[i].EnterAt == sessions[j].EnterAt {
+			return sessions[i].SessionID < sessions[j].SessionID
+		}
+		return sessions[i].EnterAt < sessions[j].EnterAt
+	})
+
+	return sessions
+}
+
+// notifyWaitingRoomHost sends the current waiting list to the host. When
+// newUserID is set, someone just started waiting and the host is optionally
+// notified through a push notification as well.
+func (p *Plugin) notifyWaitingRoomHost(state *callState, newUserID string) {
+	hostID := waitingRoomHostID(state)
+	if hostID == "" {
+		return
+	}
+
+	hostInCall := state.isUserIDInCall(hostID)
+
+	p.publishWebSocketEvent(wsEventCallWaitingRoomUpdate, map[string]interface{}{
+		"call_id":      state.Call.ID,
+		"channel_id":   state.Call.ChannelID,
+		"waiting":      p.getWaitingSessions(state, hostID),
+		"host_in_call": hostInCall,
+	}, &WebSocketBroadcast{UserID: hostID, ReliableClusterSend: true})
+
+	if newUserID != "" {
+		if cfg := p.getConfiguration(); cfg.WaitingRoomPushNotifications != nil && *cfg.WaitingRoomPushNotifications {
+			p.sendWaitingRoomPushNotification(state, hostID, newUserID, hostInCall)
+		}
+	}
+}
+
+func (p *Plugin) sendWaitingRoomPushNotification(state *callState, hostID, userID string, hostInCall bool) {
+	config := p.API.GetConfig()
+	if err := p.canSendPushNotifications(config, p.API.GetLicense()); err != nil {
+		return
+	}
+
+	channel, appErr := p.API.GetChannel(state.Call.ChannelID)
+	if appErr != nil {
+		p.LogError("failed to get channel", "error", appErr.Error())
+		return
+	}
+
+	receiver, appErr := p.API.GetUser(hostID)
+	if appErr != nil {
+		p.LogError("failed to get receiver user", "error", appErr.Error())
+		return
+	}
+
+	msg := &model.PushNotification{
+		Version:     model.PushMessageV2,
+		Type:        model.PushTypeMessage,
+		TeamId:      channel.TeamId,
+		ChannelId:   channel.Id,
+		PostId:      state.Call.PostID,
+		SenderId:    userID,
+		ChannelType: channel.Type,
+		Message:     buildGenericWaitingRoomPushNotificationMessage(receiver.Locale),
+	}
+
+	// Names are only exposed if the server allows full notification contents.
+	if config.EmailSettings.PushNotificationContents != nil && *config.EmailSettings.PushNotificationContents == model.FullNotification {
+		sender, appErr := p.API.GetUser(userID)
+		if appErr != nil {
+			p.LogError("failed to get sender user", "error", appErr.Error())
+			return
+		}
+		senderName := sender.GetDisplayName(p.getNotificationNameFormat(hostID))
+		msg.SenderName = senderName
+		msg.Message = buildWaitingRoomPushNotificationMessage(senderName, receiver.Locale, hostInCall)
+	}
+
+	if err := p.API.SendPushNotification(msg, hostID); err != nil {
+		p.LogError(fmt.Sprintf("failed to send push notification for userID: %s", hostID), "error", err.Error())
+	}
+}
+
+func buildWaitingRoomPushNotificationMessage(senderName, locale string, hostInCall bool) string {
+	T := i18n.GetUserTranslations(locale)
+	if !hostInCall {
+		return T("app.push_notification.waiting_room_join_message", map[string]any{"SenderName": senderName})
+	}
+	return T("app.push_notification.waiting_room_message", map[string]any{"SenderName": senderName})
+}
+
+func buildGenericWaitingRoomPushNotificationMessage(locale string) string {
+	T := i18n.GetUserTranslations(locale)
+	return T("app.push_notification.waiting_room_generic_message")
+}
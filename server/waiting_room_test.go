@@ -0,0 +1,168 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-calls/server/public"
+
+	serverMocks "github.com/mattermost/mattermost-plugin-calls/server/mocks/github.com/mattermost/mattermost-plugin-calls/server/interfaces"
+	pluginMocks "github.com/mattermost/mattermost-plugin-calls/server/mocks/github.com/mattermost/mattermost/server/public/plugin"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestShouldEnableWaitingRoom(t *testing.T) {
+	cfg := &configuration{}
+	cfg.SetDefaults()
+	p := &Plugin{configuration: cfg}
+
+	require.False(t, p.shouldEnableWaitingRoom(nil))
+
+	cfg.WaitingRoom = model.NewPointer(true)
+	require.True(t, p.shouldEnableWaitingRoom(nil))
+	require.True(t, p.shouldEnableWaitingRoom(&public.CallsChannel{}))
+
+	// Channel setting takes precedence.
+	require.False(t, p.shouldEnableWaitingRoom(&public.CallsChannel{
+		Props: map[string]any{"waiting_room": false},
+	}))
+
+	cfg.WaitingRoom = model.NewPointer(false)
+	require.True(t, p.shouldEnableWaitingRoom(&public.CallsChannel{
+		Props: map[string]any{"waiting_room": true},
+	}))
+}
+
+func TestBypassesWaitingRoom(t *testing.T) {
+	hostID := model.NewId()
+	organizerID := model.NewId()
+	participantID := model.NewId()
+
+	state := &callState{
+		Call: public.Call{
+			Props: public.CallProps{
+				Hosts:            []string{hostID},
+				HostLockedUserID: organizerID,
+			},
+		},
+		sessions: map[string]*public.CallSession{
+			"sessionA": {UserID: participantID},
+		},
+	}
+
+	require.True(t, bypassesWaitingRoom(state, hostID))
+	require.True(t, bypassesWaitingRoom(state, organizerID))
+	require.True(t, bypassesWaitingRoom(state, participantID))
+	require.False(t, bypassesWaitingRoom(state, model.NewId()))
+
+	require.Equal(t, organizerID, waitingRoomHostID(state))
+	state.Call.Props.HostLockedUserID = ""
+	require.Equal(t, hostID, waitingRoomHostID(state))
+}
+
+func TestGetWaitingSessions(t *testing.T) {
+	mockAPI := &pluginMocks.MockAPI{}
+	defer mockAPI.AssertExpectations(t)
+
+	p := &Plugin{
+		MattermostPlugin: plugin.MattermostPlugin{
+			API: mockAPI,
+		},
+	}
+
+	hostID := model.NewId()
+	userA := &model.User{Id: model.NewId(), Username: "alice"}
+	userB := &model.User{Id: model.NewId(), Username: "bob"}
+
+	now := time.Now().UnixMilli()
+	state := &callState{
+		Call: public.Call{
+			Props: public.CallProps{
+				Waiting: map[string]public.CallWaitingSession{
+					"sessionB": {UserID: userB.Id, EnterAt: now - 1000},
+					"sessionA": {UserID: userA.Id, EnterAt: now - 60000},
+				},
+			},
+		},
+	}
+
+	mockAPI.On("GetConfig").Return(&model.Config{}).Once()
+	mockAPI.On("GetUser", userA.Id).Return(userA, nil).Once()
+	mockAPI.On("GetUser", userB.Id).Return(userB, nil).Once()
+
+	sessions := p.getWaitingSessions(state, hostID)
+	require.Len(t, sessions, 2)
+
+	// Longest waiting first.
+	require.Equal(t, "sessionA", sessions[0].SessionID)
+	require.Equal(t, userA.Id, sessions[0].UserID)
+	require.Equal(t, "alice", sessions[0].DisplayName)
+	require.GreaterOrEqual(t, sessions[0].WaitMs, int64(60000))
+	require.Equal(t, "sessionB", sessions[1].SessionID)
+	require.Equal(t, "bob", sessions[1].DisplayName)
+}
+
+func TestResolveAdmission(t *testing.T) {
+	mockAPI := &pluginMocks.MockAPI{}
+	mockMetrics := &serverMocks.MockMetrics{}
+
+	defer mockAPI.AssertExpectations(t)
+	defer mockMetrics.AssertExpectations(t)
+
+	p := &Plugin{
+		MattermostPlugin: plugin.MattermostPlugin{
+			API: mockAPI,
+		},
+		metrics:           mockMetrics,
+		pendingAdmissions: map[string]*pendingAdmission{},
+	}
+
+	userID := model.NewId()
+	channelID := model.NewId()
+	joinData := callsJoinData{
+		CallsClientJoinData: CallsClientJoinData{
+			ChannelID: channelID,
+		},
+	}
+
+	t.Run("no pending admission", func(t *testing.T) {
+		p.resolveAdmission(model.NewId(), true)
+		p.resolveAdmission(model.NewId(), false)
+	})
+
+	t.Run("denied", func(t *testing.T) {
+		connID := model.NewId()
+		p.pendingAdmissions[connID] = &pendingAdmission{
+			userID:   userID,
+			joinData: joinData,
+		}
+
+		mockMetrics.On("IncWebSocketEvent", "out", wsEventCallWaitingDenied).Once()
+		mockAPI.On("PublishWebSocketEvent", wsEventCallWaitingDenied, map[string]any{
+			"connID":     connID,
+			"channel_id": channelID,
+		}, &model.WebsocketBroadcast{ConnectionId: connID}).Once()
+
+		p.resolveAdmission(connID, false)
+		require.Empty(t, p.pendingAdmissions)
+
+		// Resolving twice is a no-op.
+		p.resolveAdmission(connID, false)
+	})
+}
+
+func TestBuildWaitingRoomPushNotificationMessage(t *testing.T) {
+	require.Equal(t, "app.push_notification.waiting_room_message",
+		buildWaitingRoomPushNotificationMessage("alice", "en", true))
+	require.Equal(t, "app.push_notification.waiting_room_join_message",
+		buildWaitingRoomPushNotificationMessage("alice", "en", false))
+	require.Equal(t, "app.push_notification.waiting_room_generic_message",
+		buildGenericWaitingRoomPushNotificationMessage("en"))
+}
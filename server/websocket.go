@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"sync/atomic"
 	"time"
@@ -25,31 +26,65 @@ import (
 const (
 	wsEventSignal = "signal"
 
-	wsEventUserJoined                = "user_joined"
-	wsEventUserLeft                  = "user_left"
-	wsEventUserMuted                 = "user_muted"
-	wsEventUserUnmuted               = "user_unmuted"
-	wsEventUserVoiceOn               = "user_voice_on"
-	wsEventUserVoiceOff              = "user_voice_off"
-	wsEventUserScreenOn              = "user_screen_on"
-	wsEventUserScreenOff             = "user_screen_off"
-	wsEventCallStart                 = "call_start"
-	wsEventCallState                 = "call_state"
-	wsEventCallEnd                   = "call_end"
-	wsEventUserRaiseHand             = "user_raise_hand"
-	wsEventUserUnraiseHand           = "user_unraise_hand"
-	wsEventUserReacted               = "user_reacted"
-	wsEventJoin                      = "join"
-	wsEventError                     = "error"
-	wsEventCallHostChanged           = "call_host_changed"
-	wsEventCallJobState              = "call_job_state"
-	wsEventUserDismissedNotification = "user_dismissed_notification"
-	wsEventJobStop                   = "job_stop"
-	wsEventCaption                   = "caption"
-	wsEventHostMute                  = "host_mute"
-	wsEventHostScreenOff             = "host_screen_off"
-	wsEventHostLowerHand             = "host_lower_hand"
-	wsEventHostRemoved               = "host_removed"
+	wsEventUserJoined                  = "user_joined"
+	wsEventUserLeft                    = "user_left"
+	wsEventUserMuted                   = "user_muted"
+	wsEventUserUnmuted                 = "user_unmuted"
+	wsEventUserVoiceOn                 = "user_voice_on"
+	wsEventUserVoiceOff                = "user_voice_off"
+	wsEventUserScreenOn                = "user_screen_on"
+	wsEventUserScreenOff               = "user_screen_off"
+	wsEventUserVideoOn                 = "user_video_on"
+	wsEventUserVideoOff                = "user_video_off"
+	wsEventUserVideoRejected           = "user_video_rejected"
+	wsEventUserScreenRejected          = "user_screen_rejected"
+	wsEventCallStart                   = "call_start"
+	wsEventCallState                   = "call_state"
+	wsEventCallEnd                     = "call_end"
+	wsEventUserRaiseHand               = "user_raise_hand"
+	wsEventUserUnraiseHand             = "user_unraise_hand"
+	wsEventUserReacted                 = "user_reacted"
+	wsEventJoin                        = "join"
+	wsEventError                       = "error"
+	wsEventCallHostChanged             = "call_host_changed"
+	wsEventCallJobState                = "call_job_state"
+	wsEventUserDismissedNotification   = "user_dismissed_notification"
+	wsEventJobStop                     = "job_stop"
+	wsEventJobPause                    = "job_pause"
+	wsEventJobResume                   = "job_resume"
+	wsEventCaption                     = "caption"
+	wsEventHostMute                    = "host_mute"
+	wsEventHostScreenOff               = "host_screen_off"
+	wsEventHostLowerHand               = "host_lower_hand"
+	wsEventHostRemoved                 = "host_removed"
+	wsEventSessionReplaced             = "session_replaced"
+	wsEventCallSpeakerLabels           = "call_speaker_labels"
+	wsEventCallNoiseSuppression        = "call_noise_suppression"
+	wsEventCallLiveCaptions            = "call_live_captions"
+	wsEventCallChatMessage             = "call_chat_message"
+	wsEventLobbyReady                  = "lobby_ready"
+	wsEventLobbyPong                   = "lobby_pong"
+	wsEventLobbyLeft                   = "lobby_left"
+	wsEventCallStartConfirm            = "call_start_confirm"
+	wsEventCallStartExpired            = "call_start_expired"
+	wsEventCallNotificationPreferences = "call_notification_preferences"
+	wsEventCallWaiting                 = "call_waiting"
+	wsEventCallWaitingDenied           = "call_waiting_denied"
+	wsEventCallWaitingRoom             = "call_waiting_room"
+	wsEventCallWaitingRoomUpdate       = "call_waiting_room_update"
+	wsEventCallQualityDegraded         = "call_quality_degraded"
+	wsEventCallPoorConnections         = "call_poor_connections"
+	wsEventCallNodeDraining            = "call_node_draining"
+	wsEventCallData                    = "call_data"
+	wsEventCallDataRemoved             = "call_data_removed"
+	wsEventCallEndWarning              = "call_end_warning"
+	wsEventCallEndWarningCanceled      = "call_end_warning_canceled"
+	wsEventCallMoved                   = "call_moved"
+	wsEventICEServers                  = "ice_servers"
+	wsEventCallFeatures                = "call_features"
+	wsEventICERestart                  = "ice_restart"
+	wsEventCallNoiseAutoMute           = "call_noise_auto_mute"
+	wsEventNoiseMute                   = "noise_mute"
 
 	wsReconnectionTimeout = 10 * time.Second
 )
@@ -76,9 +111,23 @@ type CallsClientJoinData struct {
 	ChannelID string
 	Title     string
 	ThreadID  string
+	// Tag is optional and only applies when starting a call.
+	Tag string
+	// Metadata is optional and only applies when starting a call.
+	Metadata map[string]any
+	// Preset is optional and only applies when starting a call.
+	Preset string
+	// DisabledFeatures is optional and only applies when starting a call. It
+	// overrides the configured defaults when set.
+	DisabledFeatures []string
 
 	AV1Support  bool
 	DCSignaling bool
+	// ICEConnectionReports is set by clients reporting the selected ICE
+	// candidate pair (client_ice_candidate_pair metric) once connected. Only
+	// those are subject to ICEConnectionTimeoutSeconds, as there would be no
+	// way to tell the others have connected.
+	ICEConnectionReports bool
 
 	// JobID is the id of the job tight to the bot connection to
 	// a call (e.g. recording, transcription). It's a parameter reserved to the
@@ -90,6 +139,11 @@ type callsJoinData struct {
 	CallsClientJoinData
 	remoteAddr string
 	xff        string
+	// startConfirmed is set once the client has confirmed starting the call.
+	startConfirmed bool
+	// admitted is set once the host has admitted the user from the waiting
+	// room.
+	admitted bool
 }
 
 type WebSocketBroadcast struct {
@@ -173,27 +227,43 @@ func (p *Plugin) handleClientMessageTypeScreen(us *session, msg clientMessage, h
 		}
 	}
 
-	state, err := p.lockCallReturnState(us.channelID)
+	channelID := us.getChannelID()
+	state, err := p.lockCallReturnState(channelID)
 	if err != nil {
 		return fmt.Errorf("failed to lock call: %w", err)
 	}
-	defer p.unlockCall(us.channelID)
+	defer p.unlockCall(channelID)
 	if state == nil {
 		return fmt.Errorf("no call ongoing")
 	}
 
+	maxScreenShares := p.getConfiguration().getMaxScreenShares()
 	if msg.Type == clientMessageTypeScreenOn {
-		if state.Call.Props.ScreenSharingSessionID != "" {
-			return fmt.Errorf("cannot start screen sharing, someone else is sharing already: connID=%s", state.Call.Props.ScreenSharingSessionID)
+		sharing := getScreenSharingSessionIDs(state.Call.Props)
+		if slices.Contains(sharing, us.originalConnID) {
+			return fmt.Errorf("cannot start screen sharing, session is sharing already: connID=%s", us.originalConnID)
+		}
+		if len(sharing) >= maxScreenShares {
+			p.LogDebug("screen sharing rejected, slots are full", "userID", us.userID, "connID", us.connID, "callID", us.callID)
+			p.publishWebSocketEvent(wsEventUserScreenRejected, map[string]interface{}{
+				"connID":                     us.connID,
+				"call_id":                    us.callID,
+				"reason":                     errScreenSlotsFull.Error(),
+				"screen_sharing_session_ids": sharing,
+				"max_screen_shares":          maxScreenShares,
+			}, &WebSocketBroadcast{ConnectionID: us.connID})
+			return nil
+		}
+		addScreenSession(&state.Call.Props, us.originalConnID)
+		if state.Call.Props.ScreenStartAt == 0 {
+			state.Call.Props.ScreenStartAt = time.Now().Unix()
 		}
-		state.Call.Props.ScreenSharingSessionID = us.originalConnID
-		state.Call.Props.ScreenStartAt = time.Now().Unix()
 	} else {
-		if state.Call.Props.ScreenSharingSessionID != us.originalConnID {
-			return fmt.Errorf("cannot stop screen sharing, someone else is sharing already: connID=%s", state.Call.Props.ScreenSharingSessionID)
+		if !removeScreenSession(&state.Call.Props, us.originalConnID) {
+			return fmt.Errorf("cannot stop screen sharing, session is not sharing: connID=%s", us.originalConnID)
 		}
-		state.Call.Props.ScreenSharingSessionID = ""
-		if state.Call.Props.ScreenStartAt > 0 {
+		// Screen duration accounts for the time anyone was sharing.
+		if state.Call.Props.ScreenSharingSessionID == "" && state.Call.Props.ScreenStartAt > 0 {
 			state.Call.Stats.ScreenDuration = secondsSinceTimestamp(state.Call.Props.ScreenStartAt)
 			state.Call.Props.ScreenStartAt = 0
 		}
@@ -214,7 +284,7 @@ func (p *Plugin) handleClientMessageTypeScreen(us *session, msg clientMessage, h
 		if err := p.sendClusterMessage(clusterMessage{
 			ConnID:        us.originalConnID,
 			UserID:        us.userID,
-			ChannelID:     us.channelID,
+			ChannelID:     us.getChannelID(),
 			CallID:        us.callID,
 			SenderID:      p.nodeID,
 			ClientMessage: msg,
@@ -234,9 +304,12 @@ func (p *Plugin) handleClientMessageTypeScreen(us *session, msg clientMessage, h
 	}
 
 	p.publishWebSocketEvent(wsMsgType, map[string]interface{}{
-		"userID":     us.userID,
-		"session_id": us.originalConnID,
-	}, &WebSocketBroadcast{ChannelID: us.channelID, ReliableClusterSend: true, UserIDs: getUserIDsFromSessions(state.sessions)})
+		"userID":                     us.userID,
+		"session_id":                 us.originalConnID,
+		"call_id":                    us.callID,
+		"screen_sharing_session_ids": getScreenSharingSessionIDs(state.Call.Props),
+		"max_screen_shares":          maxScreenShares,
+	}, &WebSocketBroadcast{ChannelID: us.getChannelID(), ReliableClusterSend: true, UserIDs: getUserIDsFromSessions(state.sessions)})
 
 	return nil
 }
@@ -261,14 +334,15 @@ func (p *Plugin) handleClientMsg(us *session, msg clientMessage, handlerID strin
 	p.metrics.IncWebSocketEvent("in", msg.Type)
 	switch msg.Type {
 	case clientMessageTypeSDP:
-		p.LogDebug("received sdp", "connID", us.connID, "originalConnID", us.originalConnID, "userID", us.userID)
+		p.LogDebug("received sdp", "connID", us.connID, "originalConnID", us.originalConnID, "userID", us.userID, "callID", us.callID)
+		p.handleClientICERestart(us, msg.Data)
 		// if I am not the handler for this we relay the signaling message.
 		if handlerID != p.nodeID {
 			// need to relay signaling.
 			if err := p.sendClusterMessage(clusterMessage{
 				ConnID:        us.originalConnID,
 				UserID:        us.userID,
-				ChannelID:     us.channelID,
+				ChannelID:     us.getChannelID(),
 				CallID:        us.callID,
 				SenderID:      p.nodeID,
 				ClientMessage: msg,
@@ -287,7 +361,7 @@ func (p *Plugin) handleClientMsg(us *session, msg clientMessage, handlerID strin
 			}
 		}
 	case clientMessageTypeICE:
-		p.LogDebug("received ice candidate", "connID", us.connID, "originalConnID", us.originalConnID, "userID", us.userID)
+		p.LogDebug("received ice candidate", "connID", us.connID, "originalConnID", us.originalConnID, "userID", us.userID, "callID", us.callID)
 		if handlerID == p.nodeID {
 			rtcMsg := rtc.Message{
 				SessionID: us.originalConnID,
@@ -303,7 +377,7 @@ func (p *Plugin) handleClientMsg(us *session, msg clientMessage, handlerID strin
 			if err := p.sendClusterMessage(clusterMessage{
 				ConnID:        us.originalConnID,
 				UserID:        us.userID,
-				ChannelID:     us.channelID,
+				ChannelID:     us.getChannelID(),
 				CallID:        us.callID,
 				SenderID:      p.nodeID,
 				ClientMessage: msg,
@@ -312,12 +386,25 @@ func (p *Plugin) handleClientMsg(us *session, msg clientMessage, handlerID strin
 			}
 		}
 	case clientMessageTypeMute, clientMessageTypeUnmute:
+		// We hold the call lock while forwarding the track event so that it
+		// can't race with the server muting sessions in calls that require
+		// participants to explicitly unmute (see enforceSessionMuted).
+		channelID := us.getChannelID()
+		state, err := p.lockCallReturnState(channelID)
+		if err != nil {
+			return fmt.Errorf("failed to lock call: %w", err)
+		}
+		defer p.unlockCall(channelID)
+		if state == nil {
+			return fmt.Errorf("no call ongoing")
+		}
+
 		if handlerID != p.nodeID {
 			// need to relay track event.
 			if err := p.sendClusterMessage(clusterMessage{
 				ConnID:        us.originalConnID,
 				UserID:        us.userID,
-				ChannelID:     us.channelID,
+				ChannelID:     us.getChannelID(),
 				CallID:        us.callID,
 				SenderID:      p.nodeID,
 				ClientMessage: msg,
@@ -341,14 +428,6 @@ func (p *Plugin) handleClientMsg(us *session, msg clientMessage, handlerID strin
 			}
 		}
 
-		state, err := p.lockCallReturnState(us.channelID)
-		if err != nil {
-			return fmt.Errorf("failed to lock call: %w", err)
-		}
-		defer p.unlockCall(us.channelID)
-		if state == nil {
-			return fmt.Errorf("no call ongoing")
-		}
 		session := state.sessions[us.originalConnID]
 		if session == nil {
 			return fmt.Errorf("user state is missing from call state")
@@ -360,14 +439,18 @@ func (p *Plugin) handleClientMsg(us *session, msg clientMessage, handlerID strin
 		}
 
 		evType := wsEventUserUnmuted
+		timelineEvType := callTimelineEventUnmute
 		if msg.Type == clientMessageTypeMute {
 			evType = wsEventUserMuted
+			timelineEvType = callTimelineEventMute
 		}
+		p.recordCallTimelineEvent(us.callID, timelineEvType, us.userID, us.originalConnID, "")
 		p.publishWebSocketEvent(evType, map[string]interface{}{
 			"userID":     us.userID,
 			"session_id": us.originalConnID,
+			"call_id":    us.callID,
 		}, &WebSocketBroadcast{
-			ChannelID:           us.channelID,
+			ChannelID:           us.getChannelID(),
 			ReliableClusterSend: true,
 			UserIDs:             getUserIDsFromSessions(state.sessions),
 		})
@@ -375,17 +458,22 @@ func (p *Plugin) handleClientMsg(us *session, msg clientMessage, handlerID strin
 		if err := p.handleClientMessageTypeScreen(us, msg, handlerID); err != nil {
 			return err
 		}
+	case clientMessageTypeVideoOn, clientMessageTypeVideoOff:
+		if err := p.handleClientMessageTypeVideo(us, msg); err != nil {
+			return err
+		}
 	case clientMessageTypeRaiseHand, clientMessageTypeUnraiseHand:
 		evType := wsEventUserUnraiseHand
 		if msg.Type == clientMessageTypeRaiseHand {
 			evType = wsEventUserRaiseHand
 		}
 
-		state, err := p.lockCallReturnState(us.channelID)
+		channelID := us.getChannelID()
+		state, err := p.lockCallReturnState(channelID)
 		if err != nil {
 			return fmt.Errorf("failed to lock call: %w", err)
 		}
-		defer p.unlockCall(us.channelID)
+		defer p.unlockCall(channelID)
 		if state == nil {
 			return fmt.Errorf("no call ongoing")
 		}
@@ -395,6 +483,12 @@ func (p *Plugin) handleClientMsg(us *session, msg clientMessage, handlerID strin
 			return fmt.Errorf("user session is missing from call state")
 		}
 
+		// Lowering a hand is still allowed so that hands raised before the
+		// feature got disabled can be cleared.
+		if msg.Type == clientMessageTypeRaiseHand && state.Call.IsFeatureDisabled(public.CallFeatureRaiseHand) {
+			return fmt.Errorf("raise hand is disabled for this call")
+		}
+
 		if msg.Type == clientMessageTypeRaiseHand {
 			session.RaisedHand = time.Now().UnixMilli()
 		} else {
@@ -405,12 +499,22 @@ func (p *Plugin) handleClientMsg(us *session, msg clientMessage, handlerID strin
 			return fmt.Errorf("failed to update call session: %w", err)
 		}
 
+		if msg.Type == clientMessageTypeRaiseHand {
+			p.recordCallTimelineEvent(us.callID, callTimelineEventRaiseHand, us.userID, us.originalConnID, "")
+			if timeout := p.getConfiguration().getRaiseHandAutoLowerTimeout(); timeout > 0 {
+				go p.autoLowerHand(us.getChannelID(), us.originalConnID, session.RaisedHand, timeout)
+			}
+		} else {
+			p.recordCallTimelineEvent(us.callID, callTimelineEventLowerHand, us.userID, us.originalConnID, "")
+		}
+
 		p.publishWebSocketEvent(evType, map[string]interface{}{
 			"userID":      us.userID,
 			"session_id":  us.originalConnID,
+			"call_id":     us.callID,
 			"raised_hand": session.RaisedHand,
 		}, &WebSocketBroadcast{
-			ChannelID:           us.channelID,
+			ChannelID:           us.getChannelID(),
 			ReliableClusterSend: true,
 			UserIDs:             getUserIDsFromSessions(state.sessions),
 		})
@@ -422,20 +526,36 @@ func (p *Plugin) handleClientMsg(us *session, msg clientMessage, handlerID strin
 			return fmt.Errorf("failed to unmarshal emoji data: %w", err)
 		}
 
-		sessions, err := p.store.GetCallSessions(us.callID, db.GetCallSessionOpts{})
+		state, err := p.getCallState(us.getChannelID(), false)
 		if err != nil {
-			return fmt.Errorf("failed to get call sessions: %w", err)
+			return fmt.Errorf("failed to get call state: %w", err)
+		}
+		if state == nil {
+			return fmt.Errorf("no call ongoing")
+		}
+
+		if state.Call.IsFeatureDisabled(public.CallFeatureReactions) {
+			return fmt.Errorf("reactions are disabled for this call")
 		}
 
 		p.publishWebSocketEvent(evType, map[string]interface{}{
 			"user_id":    us.userID,
 			"session_id": us.originalConnID,
+			"call_id":    us.callID,
 			"emoji":      emoji.toMap(),
 			"timestamp":  time.Now().UnixMilli(),
 		}, &WebSocketBroadcast{
-			ChannelID: us.channelID,
-			UserIDs:   getUserIDsFromSessions(sessions),
+			ChannelID: us.getChannelID(),
+			UserIDs:   getUserIDsFromSessions(state.sessions),
 		})
+	case clientMessageTypeChat:
+		if err := p.handleCallChatMessage(us, msg); err != nil {
+			return fmt.Errorf("failed to handle chat message: %w", err)
+		}
+	case clientMessageTypeRecMarker:
+		if err := p.handleRecordingMarkerMessage(us, msg); err != nil {
+			return fmt.Errorf("failed to handle recording marker message: %w", err)
+		}
 	default:
 		return fmt.Errorf("invalid client message type %q", msg.Type)
 	}
@@ -443,20 +563,38 @@ func (p *Plugin) handleClientMsg(us *session, msg clientMessage, handlerID strin
 	return nil
 }
 
+// OnWebSocketConnect is used as a signal of user activity to reconcile any
+// focus mode state left behind by a missed leave (e.g. a node crashing).
+func (p *Plugin) OnWebSocketConnect(_, userID string) {
+	if userID == "" || p.isBot(userID) {
+		return
+	}
+
+	go func() {
+		if err := p.disableFocusMode(userID, ""); err != nil {
+			p.LogError("failed to reconcile focus mode", "err", err.Error(), "userID", userID)
+		}
+	}()
+}
+
 func (p *Plugin) OnWebSocketDisconnect(connID, userID string) {
 	if userID == "" {
 		return
 	}
 
+	p.leaveLobby(connID)
+	p.takePendingCallStart(connID)
+	p.leaveWaitingRoom(connID)
+
 	p.mut.RLock()
 	us := p.sessions[connID]
 	p.mut.RUnlock()
 	if us != nil {
 		if atomic.CompareAndSwapInt32(&us.wsClosed, 0, 1) {
-			p.LogDebug("closing ws channel for session", "userID", userID, "connID", connID, "channelID", us.channelID)
+			p.LogDebug("closing ws channel for session", "userID", userID, "connID", connID, "channelID", us.getChannelID())
 			close(us.wsCloseCh)
 		} else {
-			p.LogError("ws channel already closed", "userID", userID, "connID", connID, "channelID", us.channelID)
+			p.LogError("ws channel already closed", "userID", userID, "connID", connID, "channelID", us.getChannelID())
 		}
 	} else {
 		// If we don't find the session it's usually an expected case as this hook tracks all MM connections, not just Calls ones.
@@ -468,7 +606,7 @@ func (p *Plugin) OnWebSocketDisconnect(connID, userID string) {
 			us := p.sessions[connID]
 			p.mut.RUnlock()
 			if us != nil && atomic.CompareAndSwapInt32(&us.wsClosed, 0, 1) {
-				p.LogDebug("race: closing ws channel for session", "userID", userID, "connID", connID, "channelID", us.channelID)
+				p.LogDebug("race: closing ws channel for session", "userID", userID, "connID", connID, "channelID", us.getChannelID())
 				close(us.wsCloseCh)
 			}
 		}()
@@ -507,7 +645,7 @@ func (p *Plugin) wsReader(us *session, authSessionID, handlerID string) {
 			if s, appErr := p.API.GetSession(authSessionID); appErr != nil || (s.ExpiresAt != 0 && time.Now().UnixMilli() >= s.ExpiresAt) {
 				fields := []any{
 					"channelID",
-					us.channelID,
+					us.getChannelID(),
 					"userID",
 					us.userID,
 					"connID",
@@ -523,7 +661,7 @@ func (p *Plugin) wsReader(us *session, authSessionID, handlerID string) {
 				p.LogInfo("invalid or expired session, closing RTC session", fields...)
 
 				// We forcefully disconnect any session that has been revoked or expired.
-				if err := p.closeRTCSession(us.userID, us.connID, us.channelID, handlerID, us.callID); err != nil {
+				if err := p.closeRTCSession(us.userID, us.connID, us.getChannelID(), handlerID, us.callID); err != nil {
 					p.LogError("failed to close RTC session", append(fields[:5], "err", err.Error()))
 				}
 
@@ -546,17 +684,56 @@ func (p *Plugin) sendRTCMessage(msg rtc.Message, callID string) error {
 		return p.rtcdManager.Send(cm, host)
 	}
 
-	return p.rtcServer.Send(msg)
+	return p.getRTCServer().Send(msg)
 }
 
-func (p *Plugin) wsWriter() {
+// enforceSessionMuted disables the voice track of a session that is sending
+// audio while still muted in a call that requires participants to explicitly
+// unmute (e.g. a client unmuting locally without notifying the server).
+func (p *Plugin) enforceSessionMuted(channelID, sessionID string) {
+	state, err := p.lockCallReturnState(channelID)
+	if err != nil {
+		p.LogError("failed to lock call", "err", err.Error(), "channelID", channelID)
+		return
+	}
+	defer p.unlockCall(channelID)
+
+	if state == nil || !state.Call.Props.JoinMuted {
+		return
+	}
+
+	// The session could have legitimately unmuted in the meantime.
+	session := state.sessions[sessionID]
+	if session == nil || session.Unmuted {
+		return
+	}
+
+	p.LogDebug("muting session sending audio while muted", "sessionID", sessionID, "callID", state.Call.ID)
+
+	if err := p.sendRTCMessage(rtc.Message{
+		SessionID: sessionID,
+		Type:      rtc.MuteMessage,
+	}, state.Call.ID); err != nil {
+		p.LogError("failed to send RTC message", "err", err.Error(), "sessionID", sessionID, "callID", state.Call.ID)
+	}
+}
+
+// Types of RTC messages forwarded to WebSocket clients, used to label metrics.
+const (
+	wsWriterMsgTypeSignaling     = "signaling"
+	wsWriterMsgTypeVoiceActivity = "voice_activity"
+)
+
+func (p *Plugin) wsWriter(rtcServer *rtc.Server) {
 	for {
 		select {
-		case msg, ok := <-p.rtcServer.ReceiveCh():
+		case msg, ok := <-rtcServer.ReceiveCh():
 			if !ok {
 				return
 			}
 
+			p.metrics.SetWebSocketWriterQueueDepth(len(rtcServer.ReceiveCh()))
+
 			us := p.getSessionByOriginalID(msg.SessionID)
 			if us == nil {
 				p.LogError("failed to get session by originalConnID", "originalConnID", msg.SessionID)
@@ -575,18 +752,41 @@ func (p *Plugin) wsWriter() {
 					continue
 				}
 
+				if msg.Type == rtc.VoiceOffMessage {
+					p.stopNoiseDetection(us.originalConnID)
+				} else {
+					if session := sessions[us.originalConnID]; us.joinMuted.Load() && session != nil && !session.Unmuted {
+						go p.enforceSessionMuted(us.getChannelID(), us.originalConnID)
+						continue
+					}
+					p.startNoiseDetection(us.getChannelID(), us.callID, us.originalConnID, p.getSessionNoiseAutoMute(us))
+				}
+
 				p.publishWebSocketEvent(evType, map[string]interface{}{
 					"userID":     us.userID,
 					"session_id": us.originalConnID,
-				}, &WebSocketBroadcast{ChannelID: us.channelID, UserIDs: getUserIDsFromSessions(sessions)})
+					"call_id":    us.callID,
+				}, &WebSocketBroadcast{ChannelID: us.getChannelID(), UserIDs: getUserIDsFromSessions(sessions)})
+				p.metrics.ObserveWebSocketWriterMessage(wsWriterMsgTypeVoiceActivity, len(msg.Data))
+
+				continue
+			}
 
+			data, err := p.getConfiguration().filterSignalCandidates(us.iceLimiter, msg.Type, msg.Data)
+			if err != nil {
+				p.LogError("failed to filter signal candidates", "err", err.Error(), "sessionID", msg.SessionID)
+				continue
+			}
+			if data == nil {
+				p.LogDebug("dropping ICE candidate over the per session limit", "sessionID", msg.SessionID)
 				continue
 			}
 
 			p.publishWebSocketEvent(wsEventSignal, map[string]interface{}{
-				"data":   string(msg.Data),
+				"data":   string(data),
 				"connID": msg.SessionID,
 			}, &WebSocketBroadcast{ConnectionID: us.connID, ReliableClusterSend: true})
+			p.metrics.ObserveWebSocketWriterMessage(wsWriterMsgTypeSignaling, len(data))
 		case <-p.stopCh:
 			return
 		}
@@ -594,49 +794,76 @@ func (p *Plugin) wsWriter() {
 }
 
 func (p *Plugin) handleLeave(us *session, userID, connID, channelID, handlerID string) error {
-	p.LogDebug("handleLeave", "userID", userID, "connID", connID, "channelID", channelID)
+	p.LogDebug("handleLeave", "userID", userID, "connID", connID, "channelID", channelID, "callID", us.callID)
 
 	select {
 	case <-us.wsReconnectCh:
-		p.LogDebug("reconnected, returning", "userID", userID, "connID", connID, "channelID", channelID)
+		p.LogDebug("reconnected, returning", "userID", userID, "connID", connID, "channelID", channelID, "callID", us.callID)
 
 		// Clearing the previous session since it gets copied over after
 		// successful reconnect.
 		p.mut.Lock()
 		if p.sessions[connID] == us && !us.rtc {
-			p.LogDebug("clearing non-RTC session after reconnect", "userID", userID, "connID", connID, "channelID", channelID)
+			p.LogDebug("clearing non-RTC session after reconnect", "userID", userID, "connID", connID, "channelID", channelID, "callID", us.callID)
 			delete(p.sessions, connID)
 		}
 		p.mut.Unlock()
 		return nil
 	case <-us.leaveCh:
-		p.LogDebug("user left call", "userID", userID, "connID", connID, "channelID", us.channelID)
+		p.LogDebug("user left call", "userID", userID, "connID", connID, "channelID", us.getChannelID(), "callID", us.callID)
 	case <-us.rtcCloseCh:
-		p.LogDebug("rtc connection was closed", "userID", userID, "connID", connID, "channelID", us.channelID)
+		p.LogDebug("rtc connection was closed", "userID", userID, "connID", connID, "channelID", us.getChannelID(), "callID", us.callID)
 		return nil
 	case <-time.After(wsReconnectionTimeout):
-		p.LogDebug("timeout waiting for reconnection", "userID", userID, "connID", connID, "channelID", channelID)
+		p.LogDebug("timeout waiting for reconnection", "userID", userID, "connID", connID, "channelID", channelID, "callID", us.callID)
+		p.setRejoinCooldown(channelID, userID, rejoinCooldownReasonDropped)
 	}
 
 	if err := p.closeRTCSession(userID, us.originalConnID, channelID, handlerID, us.callID); err != nil {
-		p.LogError(err.Error())
+		p.LogError(err.Error(), "callID", us.callID)
 	}
 
 	if err := p.removeSession(us); err != nil {
-		p.LogError(err.Error())
+		p.LogError(err.Error(), "callID", us.callID)
 	}
 
 	return nil
 }
 
+var (
+	errArchivedChannel = errors.New("calls are not available in archived channels")
+	errReadOnlyChannel = errors.New("calls are not allowed in read-only channels")
+)
+
+// checkCallChannelAccess returns an error if the user is not allowed to join
+// calls in the given channel. Users who can read but not post in the channel
+// (read-only channels) are only allowed if AllowCallsInReadOnlyChannels is set.
+// It also returns whether the user can post in the channel.
+func (p *Plugin) checkCallChannelAccess(userID, channelID string) (bool, error) {
+	if p.isBot(userID) || p.API.HasPermissionToChannel(userID, channelID, model.PermissionCreatePost) {
+		return true, nil
+	}
+
+	if !p.API.HasPermissionToChannel(userID, channelID, model.PermissionReadChannel) {
+		return false, fmt.Errorf("forbidden")
+	}
+
+	if !p.getConfiguration().callsInReadOnlyChannelsAllowed() {
+		return false, errReadOnlyChannel
+	}
+
+	return false, nil
+}
+
 func (p *Plugin) handleJoin(userID, connID, authSessionID string, joinData callsJoinData) (retErr error) {
 	channelID := joinData.ChannelID
 	p.LogDebug("handleJoin", "userID", userID, "connID", connID, "channelID", channelID)
 
 	// We should go through only if the user has permissions to the requested channel
 	// or if the user is the Calls bot.
-	if !(p.isBot(userID) || p.API.HasPermissionToChannel(userID, channelID, model.PermissionCreatePost)) {
-		return fmt.Errorf("forbidden")
+	canPost, err := p.checkCallChannelAccess(userID, channelID)
+	if err != nil {
+		return err
 	}
 
 	if userID == p.getBotID() && joinData.JobID == "" {
@@ -648,7 +875,7 @@ func (p *Plugin) handleJoin(userID, connID, authSessionID string, joinData calls
 		return appErr
 	}
 	if channel.DeleteAt > 0 {
-		return fmt.Errorf("cannot join call in archived channel")
+		return errArchivedChannel
 	}
 	channelStats, appErr := p.API.GetChannelStats(channelID)
 	if appErr != nil {
@@ -669,18 +896,87 @@ func (p *Plugin) handleJoin(userID, connID, authSessionID string, joinData calls
 			return fmt.Errorf("cannot attach call to deleted thread")
 		}
 
+		// Any post in the thread can be given, the call is attached to the
+		// thread's root.
 		if post.RootId != "" {
-			return fmt.Errorf("thread is not a root post")
+			joinData.ThreadID = post.RootId
+		}
+	}
+
+	callTag := normalizeCallTag(joinData.Tag)
+	if callTag != "" && !p.getConfiguration().isAllowedCallTag(callTag) {
+		return fmt.Errorf("call tag is not allowed")
+	}
+
+	callMetadata, err := parseCallMetadata(joinData.Metadata)
+	if err != nil {
+		return fmt.Errorf("invalid call metadata: %w", err)
+	}
+
+	disabledFeatures := p.getConfiguration().getDefaultDisabledCallFeatures()
+	if joinData.DisabledFeatures != nil {
+		disabledFeatures = joinData.DisabledFeatures
+	}
+	disabledFeatures, err = parseCallFeatures(disabledFeatures)
+	if err != nil {
+		return fmt.Errorf("invalid disabled features: %w", err)
+	}
+
+	var preset callPreset
+	if joinData.Preset != "" {
+		var ok bool
+		if preset, ok = p.getConfiguration().getCallPreset(joinData.Preset); !ok {
+			return fmt.Errorf("call preset not found")
 		}
 	}
 
+	if err := p.checkUserBlockedFromCalls(userID, channelID); err != nil {
+		return err
+	}
+
+	if err := p.checkRejoinCooldown(userID, channelID); err != nil {
+		return err
+	}
+
 	callsChannel, err := p.store.GetCallsChannel(channelID, db.GetCallsChannelOpts{})
 	if err != nil && !errors.Is(err, db.ErrNotFound) {
 		return fmt.Errorf("failed to get call channel: %w", err)
 	}
-	var callsEnabled *bool
-	if callsChannel != nil {
-		callsEnabled = model.NewPointer(callsChannel.Enabled)
+	callsEnabled, _ := p.getConfiguration().resolveCallsEnabled(callsChannel, channel.TeamId)
+
+	// Starting a new call may require an explicit confirmation. Joining an
+	// ongoing call never does, and the check is skipped entirely when
+	// confirmation isn't required so that regular joins are not delayed.
+	if !joinData.startConfirmed && userID != p.getBotID() && p.shouldConfirmCallStart(callsChannel) {
+		active, err := p.store.GetCallActive(channelID, db.GetCallOpts{})
+		if err != nil {
+			return fmt.Errorf("failed to get call active: %w", err)
+		}
+		if !active {
+			return p.requestCallStartConfirmation(userID, connID, authSessionID, joinData)
+		}
+	}
+
+	if !joinData.admitted && userID != p.getBotID() {
+		waiting, err := p.requestAdmission(userID, connID, authSessionID, joinData)
+		if err != nil {
+			return err
+		} else if waiting {
+			return nil
+		}
+	}
+
+	joinMuted := p.shouldJoinMuted(callsChannel)
+	waitingRoom := p.shouldEnableWaitingRoom(callsChannel)
+	noiseSuppression := p.getConfiguration().noiseSuppressionRecommended()
+
+	// The preset picked when starting the call takes precedence over the
+	// channel and global settings.
+	if preset.JoinMuted != nil {
+		joinMuted = *preset.JoinMuted
+	}
+	if preset.WaitingRoom != nil {
+		waitingRoom = *preset.WaitingRoom
 	}
 
 	addSessionToCall := func(state *callState) *callState {
@@ -688,7 +984,12 @@ func (p *Plugin) handleJoin(userID, connID, authSessionID string, joinData calls
 
 		state, err = p.addUserSession(state, callsEnabled, userID, connID, channelID, joinData.JobID, channel.Type)
 		if err != nil {
-			p.LogError("failed to add user session", "err", err.Error())
+			// The state is nil if the session was meant to start a new call.
+			var callID string
+			if state != nil {
+				callID = state.Call.ID
+			}
+			p.LogError("failed to add user session", "err", err.Error(), "userID", userID, "connID", connID, "channelID", channelID, "callID", callID)
 			p.publishWebSocketEvent(wsEventError, map[string]interface{}{
 				"data":   err.Error(),
 				"connID": connID,
@@ -698,7 +999,7 @@ func (p *Plugin) handleJoin(userID, connID, authSessionID string, joinData calls
 			// new call has started
 
 			// If this is TestMode (DefaultEnabled=false) and sysadmin, send an ephemeral message
-			if cfg := p.getConfiguration(); cfg.DefaultEnabled != nil && !*cfg.DefaultEnabled &&
+			if cfg := p.getConfiguration(); !cfg.defaultEnabledForChannel(channel.TeamId, channel.Type) &&
 				p.API.HasPermissionTo(userID, model.PermissionManageSystem) {
 				p.API.SendEphemeralPost(
 					userID,
@@ -710,27 +1011,35 @@ func (p *Plugin) handleJoin(userID, connID, authSessionID string, joinData calls
 				)
 			}
 
-			postID, threadID, err := p.createCallStartedPost(state, userID, channelID, joinData.Title, joinData.ThreadID)
+			// If the user cannot post in the channel, the call post is created by
+			// the bot so that it doesn't appear as a user message.
+			postID, threadID, err := p.createCallStartedPost(state, userID, channelID, joinData.Title, joinData.ThreadID, !canPost)
 			if err != nil {
-				p.LogError(err.Error())
+				p.LogError(err.Error(), "callID", state.Call.ID)
 			}
 
 			state.Call.PostID = postID
 			state.Call.ThreadID = threadID
-			if err := p.store.UpdateCall(&state.Call); err != nil {
-				p.LogError(err.Error())
+			state.Call.Props.JoinMuted = joinMuted
+			state.Call.Props.NoiseSuppression = noiseSuppression
+			state.Call.Props.Tag = callTag
+			state.Call.Props.Metadata = callMetadata
+			state.Call.Props.WaitingRoom = waitingRoom
+			state.Call.Props.Preset = preset.Name
+			state.Call.Props.AudioOnly = preset.AudioOnly
+			if len(disabledFeatures) > 0 {
+				state.Call.Props.DisabledFeatures = disabledFeatures
+			}
+			if err := p.updateCallBuffered(&state.Call); err != nil {
+				p.LogError(err.Error(), "callID", state.Call.ID)
+			}
+
+			if preset.Record {
+				// Starting the job requires the call lock we are holding.
+				go p.startRecordingFromPreset(channelID, preset.Name)
 			}
 
-			// TODO: send all the info attached to a call.
-			p.publishWebSocketEvent(wsEventCallStart, map[string]interface{}{
-				"id":        state.Call.ID,
-				"channelID": channelID,
-				"start_at":  state.Call.StartAt,
-				"thread_id": threadID,
-				"post_id":   postID,
-				"owner_id":  state.Call.OwnerID,
-				"host_id":   state.Call.GetHostID(),
-			}, &WebSocketBroadcast{ChannelID: channelID, ReliableClusterSend: true})
+			p.publishCallStart(&state.Call)
 		}
 
 		p.LogDebug("session has joined call",
@@ -739,9 +1048,11 @@ func (p *Plugin) handleJoin(userID, connID, authSessionID string, joinData calls
 		)
 
 		handlerID := state.Call.Props.NodeID
-		p.LogDebug("got handlerID", "handlerID", handlerID)
+		p.LogDebug("got handlerID", "handlerID", handlerID, "callID", state.Call.ID)
 
 		us := newUserSession(userID, channelID, connID, state.Call.ID, p.rtcdManager == nil && handlerID == p.nodeID)
+		us.msgLimiter = p.getConfiguration().newSessionMessageLimiter()
+		us.setCallProps(state.Call.Props)
 		p.mut.Lock()
 		p.sessions[connID] = us
 		p.mut.Unlock()
@@ -759,10 +1070,10 @@ func (p *Plugin) handleJoin(userID, connID, authSessionID string, joinData calls
 				},
 			}
 			if err := p.rtcdManager.Send(msg, state.Call.Props.RTCDHost); err != nil {
-				p.LogError("failed to send client join message", "err", err.Error())
+				p.LogError("failed to send client join message", "err", err.Error(), "callID", us.callID)
 				go func() {
 					if err := p.handleLeave(us, userID, connID, channelID, handlerID); err != nil {
-						p.LogError(err.Error())
+						p.LogError(err.Error(), "callID", us.callID)
 					}
 				}()
 				return state
@@ -790,17 +1101,17 @@ func (p *Plugin) handleJoin(userID, connID, authSessionID string, joinData calls
 					},
 				}
 				p.LogDebug("initializing RTC session", "userID", userID, "connID", connID, "channelID", channelID, "callID", us.callID)
-				if err = p.rtcServer.InitSession(cfg, func() error {
+				if err = p.getRTCServer().InitSession(cfg, func() error {
 					if atomic.CompareAndSwapInt32(&us.rtcClosed, 0, 1) {
 						close(us.rtcCloseCh)
 						return p.removeSession(us)
 					}
 					return nil
 				}); err != nil {
-					p.LogError("failed to init session", "err", err.Error())
+					p.LogError("failed to init session", "err", err.Error(), "callID", us.callID)
 					go func() {
 						if err := p.handleLeave(us, userID, connID, channelID, handlerID); err != nil {
-							p.LogError(err.Error())
+							p.LogError(err.Error(), "callID", us.callID)
 						}
 					}()
 					return state
@@ -818,10 +1129,10 @@ func (p *Plugin) handleJoin(userID, connID, authSessionID string, joinData calls
 						"dcSignaling": joinData.DCSignaling,
 					},
 				}, clusterMessageTypeConnect, handlerID); err != nil {
-					p.LogError("failed to send connect message", "err", err.Error())
+					p.LogError("failed to send connect message", "err", err.Error(), "callID", us.callID)
 					go func() {
 						if err := p.handleLeave(us, userID, connID, channelID, handlerID); err != nil {
-							p.LogError(err.Error())
+							p.LogError(err.Error(), "callID", us.callID)
 						}
 					}()
 					return state
@@ -831,12 +1142,27 @@ func (p *Plugin) handleJoin(userID, connID, authSessionID string, joinData calls
 
 		// send successful join response
 		p.publishWebSocketEvent(wsEventJoin, map[string]interface{}{
-			"connID": connID,
+			"connID":            connID,
+			"call_id":           state.Call.ID,
+			"join_muted":        state.Call.Props.JoinMuted,
+			"noise_suppression": state.Call.Props.NoiseSuppression,
 		}, &WebSocketBroadcast{ConnectionID: connID, ReliableClusterSend: true})
 
+		if p.callsQualityDegraded.Load() && state.Call.Props.NodeID == p.nodeID && state.Call.Props.RTCDHost == "" {
+			p.publishWebSocketEvent(wsEventCallQualityDegraded, map[string]interface{}{
+				"call_id":    state.Call.ID,
+				"channel_id": channelID,
+				"degraded":   true,
+			}, &WebSocketBroadcast{ConnectionID: connID, ReliableClusterSend: true})
+		}
+
+		_, atCapacity := p.getCallCapacity(state)
 		p.publishWebSocketEvent(wsEventUserJoined, map[string]interface{}{
-			"user_id":    userID,
-			"session_id": connID,
+			"user_id":     userID,
+			"session_id":  connID,
+			"call_id":     state.Call.ID,
+			"unmuted":     false,
+			"at_capacity": atCapacity,
 		}, &WebSocketBroadcast{ChannelID: channelID, ReliableClusterSend: true})
 
 		if userID == p.getBotID() && state.Recording != nil {
@@ -850,9 +1176,9 @@ func (p *Plugin) handleJoin(userID, connID, authSessionID string, joinData calls
 			})
 		}
 
-		clientStateData, err := json.Marshal(state.getClientState(p.getBotID(), userID))
+		clientStateData, err := json.Marshal(p.getCallClientState(state, userID))
 		if err != nil {
-			p.LogError("failed to marshal client state", "err", err.Error())
+			p.LogError("failed to marshal client state", "err", err.Error(), "callID", state.Call.ID)
 		} else {
 			p.publishWebSocketEvent(wsEventCallState, map[string]interface{}{
 				"channel_id": channelID,
@@ -860,8 +1186,25 @@ func (p *Plugin) handleJoin(userID, connID, authSessionID string, joinData calls
 			}, &WebSocketBroadcast{UserID: userID, ReliableClusterSend: true})
 		}
 
+		// A host joining late gets to see who's been waiting.
+		if len(state.Call.Props.Waiting) > 0 && userID == waitingRoomHostID(state) {
+			p.notifyWaitingRoomHost(state, "")
+		}
+
 		p.metrics.IncWebSocketConn()
 
+		if userID != p.getBotID() {
+			p.metrics.IncICEConnections(iceConnectionStateNew)
+		}
+
+		if timeout := p.getICEConnectionTimeout(userID, joinData.CallsClientJoinData); timeout > 0 {
+			go p.iceConnectionTimeoutWatcher(us, timeout)
+		}
+
+		if cfg := p.getConfiguration(); userID != p.getBotID() && (cfg.getMediaInactivityTimeout() > 0 || cfg.getICERestartTimeout() > 0) {
+			go p.mediaInactivityWatcher(us, cfg.getMediaInactivityTimeout(), cfg.getICERestartTimeout())
+		}
+
 		go func() {
 			defer p.metrics.DecWebSocketConn()
 			p.wsReader(us, authSessionID, handlerID)
@@ -952,8 +1295,8 @@ func (p *Plugin) handleReconnect(userID, connID, channelID, originalConnID, prev
 	p.LogDebug("handleReconnect", "userID", userID, "connID", connID, "channelID", channelID,
 		"originalConnID", originalConnID, "prevConnID", prevConnID)
 
-	if !p.isBot(userID) && !p.API.HasPermissionToChannel(userID, channelID, model.PermissionCreatePost) {
-		return fmt.Errorf("forbidden")
+	if _, err := p.checkCallChannelAccess(userID, channelID); err != nil {
+		return err
 	}
 
 	state, err := p.getCallState(channelID, false)
@@ -966,6 +1309,7 @@ func (p *Plugin) handleReconnect(userID, connID, channelID, originalConnID, prev
 	}
 
 	var rtc bool
+	var iceUfrag *string
 	p.mut.Lock()
 	us := p.sessions[connID]
 
@@ -978,6 +1322,7 @@ func (p *Plugin) handleReconnect(userID, connID, channelID, originalConnID, prev
 
 	if us != nil {
 		rtc = us.rtc
+		iceUfrag = us.iceUfrag.Load()
 		if atomic.CompareAndSwapInt32(&us.wsReconnected, 0, 1) {
 			p.LogDebug("closing reconnectCh", "userID", userID, "connID", connID, "channelID", channelID,
 				"originalConnID", originalConnID)
@@ -1010,7 +1355,12 @@ func (p *Plugin) handleReconnect(userID, connID, channelID, originalConnID, prev
 	}
 
 	us = newUserSession(userID, channelID, connID, state.Call.ID, rtc)
+	us.msgLimiter = p.getConfiguration().newSessionMessageLimiter()
+	us.setCallProps(state.Call.Props)
 	us.originalConnID = originalConnID
+	// Keeping track of the ICE credentials in use so that a restart following
+	// the reconnection (e.g. on network change) is detected.
+	us.iceUfrag.Store(iceUfrag)
 	if p.sessions[originalConnID] != nil {
 		// We need to ensure to clear the original session to avoid potentially tracking it twice in case the ID has changed
 		// and we are the node handling it's RTC counterpart.
@@ -1042,6 +1392,14 @@ func (p *Plugin) handleReconnect(userID, connID, channelID, originalConnID, prev
 		}
 	}
 
+	if cfg := p.getConfiguration(); userID != p.getBotID() && (cfg.getMediaInactivityTimeout() > 0 || cfg.getICERestartTimeout() > 0) {
+		go p.mediaInactivityWatcher(us, cfg.getMediaInactivityTimeout(), cfg.getICERestartTimeout())
+	}
+
+	if !p.isBot(userID) {
+		go p.publishICEServers(us)
+	}
+
 	p.wsReader(us, authSessionID, state.Call.Props.NodeID)
 
 	if err := p.handleLeave(us, userID, connID, channelID, state.Call.Props.NodeID); err != nil {
@@ -1058,21 +1416,16 @@ func (p *Plugin) handleCallStateRequest(channelID, userID, connID string) error
 		return fmt.Errorf("forbidden")
 	}
 
-	// Locking is not ideal but it's the only way to guarantee a race free
-	// sequence and a consistent state.
-	// On the client we should make sure to make this request only when strictly
-	// necessary (i.e first load, joining call, reconnecting).
-	state, err := p.lockCallReturnState(channelID)
+	clientState, err := p.getCallSnapshot(channelID, userID)
 	if err != nil {
-		return fmt.Errorf("failed to lock call: %w", err)
+		return err
 	}
-	defer p.unlockCall(channelID)
 
-	if state == nil {
+	if clientState == nil {
 		return fmt.Errorf("no call ongoing")
 	}
 
-	clientStateData, err := json.Marshal(state.getClientState(p.getBotID(), userID))
+	clientStateData, err := json.Marshal(clientState)
 	if err != nil {
 		return fmt.Errorf("failed to marshal client state: %w", err)
 	}
@@ -1102,7 +1455,9 @@ func (p *Plugin) WebSocketMessageHasBeenPosted(connID, userID string, req *model
 		// Only a few events don't require a user session to exist. For anything else
 		// we should return.
 		switch msg.Type {
-		case clientMessageTypeJoin, clientMessageTypeLeave, clientMessageTypeReconnect, clientMessageTypeCallState:
+		case clientMessageTypeJoin, clientMessageTypeLeave, clientMessageTypeReconnect, clientMessageTypeCallState,
+			clientMessageTypeLobbyEnter, clientMessageTypeLobbyLeave, clientMessageTypeLobbyPing,
+			clientMessageTypeStartConfirm, clientMessageTypeStartCancel:
 		default:
 			return
 		}
@@ -1113,6 +1468,10 @@ func (p *Plugin) WebSocketMessageHasBeenPosted(connID, userID string, req *model
 		return
 	}
 
+	if us != nil && isThrottledClientMessage(msg.Type) && !p.allowSessionMessage(us, msg.Type) {
+		return
+	}
+
 	switch msg.Type {
 	case clientMessageTypeJoin:
 		channelID, ok := req.Data["channelID"].(string)
@@ -1129,6 +1488,30 @@ func (p *Plugin) WebSocketMessageHasBeenPosted(connID, userID string, req *model
 		// it will be an empty string.
 		threadID, _ := req.Data["threadID"].(string)
 
+		// Tag is optional, so if it's not present,
+		// it will be an empty string.
+		tag, _ := req.Data["tag"].(string)
+
+		// Metadata is optional, so if it's not present,
+		// it will be nil.
+		metadata, _ := req.Data["metadata"].(map[string]any)
+
+		// Preset is optional, so if it's not present,
+		// it will be an empty string.
+		preset, _ := req.Data["preset"].(string)
+
+		// DisabledFeatures is optional, so if it's not present,
+		// it will be nil.
+		var disabledFeatures []string
+		if features, ok := req.Data["disabledFeatures"].([]any); ok {
+			disabledFeatures = make([]string, 0, len(features))
+			for _, feature := range features {
+				if f, ok := feature.(string); ok {
+					disabledFeatures = append(disabledFeatures, f)
+				}
+			}
+		}
+
 		// JobID is optional, so if it's not present,
 		// it will be an empty string.
 		jobID, _ := req.Data["jobID"].(string)
@@ -1140,18 +1523,25 @@ func (p *Plugin) WebSocketMessageHasBeenPosted(connID, userID string, req *model
 		xff, _ := req.Data[model.WebSocketXForwardedFor].(string)
 
 		joinData := callsJoinData{
-			CallsClientJoinData{
-				ChannelID:   channelID,
-				Title:       title,
-				ThreadID:    threadID,
-				AV1Support:  av1Support,
-				DCSignaling: dcSignaling,
-				JobID:       jobID,
+			CallsClientJoinData: CallsClientJoinData{
+				ChannelID:        channelID,
+				Title:            title,
+				ThreadID:         threadID,
+				Tag:              tag,
+				Metadata:         metadata,
+				Preset:           preset,
+				DisabledFeatures: disabledFeatures,
+				AV1Support:       av1Support,
+				DCSignaling:      dcSignaling,
+				JobID:            jobID,
 			},
-			remoteAddr,
-			xff,
+			remoteAddr: remoteAddr,
+			xff:        xff,
 		}
 
+		// Users join the call straight from the lobby when done testing.
+		p.leaveLobby(connID)
+
 		go func() {
 			if err := p.handleJoin(userID, connID, req.Session.Id, joinData); err != nil {
 				p.LogWarn(err.Error(), "userID", userID, "connID", connID, "channelID", channelID)
@@ -1195,6 +1585,10 @@ func (p *Plugin) WebSocketMessageHasBeenPosted(connID, userID string, req *model
 			close(us.leaveCh)
 		}
 
+		if us == nil {
+			go p.leaveWaitingRoom(connID)
+		}
+
 		if err := p.sendClusterMessage(clusterMessage{
 			ConnID:   connID,
 			UserID:   userID,
@@ -1203,6 +1597,55 @@ func (p *Plugin) WebSocketMessageHasBeenPosted(connID, userID string, req *model
 			p.LogError(err.Error())
 		}
 
+		return
+	case clientMessageTypeStartConfirm:
+		p.metrics.IncWebSocketEvent("in", msg.Type)
+
+		go func() {
+			if err := p.handleCallStartConfirm(userID, connID); err != nil {
+				p.LogWarn(err.Error(), "userID", userID, "connID", connID)
+				p.publishWebSocketEvent(wsEventError, map[string]interface{}{
+					"data":   err.Error(),
+					"connID": connID,
+				}, &WebSocketBroadcast{ConnectionID: connID, ReliableClusterSend: true})
+			}
+		}()
+		return
+	case clientMessageTypeStartCancel:
+		p.metrics.IncWebSocketEvent("in", msg.Type)
+		if pcs := p.takePendingCallStart(connID); pcs != nil {
+			p.LogDebug("call start cancelled", "userID", userID, "connID", connID, "channelID", pcs.joinData.ChannelID)
+		}
+		return
+	case clientMessageTypeLobbyEnter:
+		p.metrics.IncWebSocketEvent("in", msg.Type)
+
+		channelID, _ := req.Data["channelID"].(string)
+		if channelID == "" {
+			p.LogError("missing channelID")
+			return
+		}
+
+		if err := p.handleLobbyEnter(userID, connID, channelID); err != nil {
+			p.LogWarn(err.Error(), "userID", userID, "connID", connID, "channelID", channelID)
+			p.publishWebSocketEvent(wsEventError, map[string]interface{}{
+				"data":   err.Error(),
+				"connID": connID,
+			}, &WebSocketBroadcast{ConnectionID: connID})
+		}
+		return
+	case clientMessageTypeLobbyLeave:
+		p.metrics.IncWebSocketEvent("in", msg.Type)
+		p.leaveLobby(connID)
+		return
+	case clientMessageTypeLobbyPing:
+		p.metrics.IncWebSocketEvent("in", msg.Type)
+
+		seq, _ := req.Data["seq"].(float64)
+		ts, _ := req.Data["ts"].(float64)
+		if err := p.handleLobbyPing(connID, seq, ts); err != nil {
+			p.LogDebug("handleLobbyPing failed", "err", err.Error(), "userID", userID, "connID", connID)
+		}
 		return
 	case clientMessageTypeCallState:
 		p.metrics.IncWebSocketEvent("in", "call_state")
@@ -1223,13 +1666,47 @@ func (p *Plugin) WebSocketMessageHasBeenPosted(connID, userID string, req *model
 			p.LogError("invalid or missing sdp data")
 			return
 		}
-		data, err := unpackSDPData(msgData)
+		cfg := p.getConfiguration()
+		data, err := unpackSDPData(msgData, cfg.getMaxSDPSize())
+		if err == nil {
+			err = validateSDPMessage(data)
+		}
+		if err != nil {
+			reason := sdpRejectReasonMalformed
+			if errors.Is(err, errSDPTooLarge) {
+				reason = sdpRejectReasonTooLarge
+			}
+			p.LogWarn("rejecting client sdp", "err", err.Error(), "reason", reason, "userID", userID, "connID", connID)
+			p.metrics.IncRejectedSDPs(reason)
+			p.publishWebSocketEvent(wsEventError, map[string]interface{}{
+				"data":   "invalid session description: " + err.Error(),
+				"connID": connID,
+			}, &WebSocketBroadcast{ConnectionID: connID})
+			return
+		}
+		data, err = cfg.filterSDPMessageCandidates(data)
 		if err != nil {
 			p.LogError(err.Error())
 			return
 		}
 		msg.Data = data
-	case clientMessageTypeICE, clientMessageTypeScreenOn:
+	case clientMessageTypeICE:
+		msgData, ok := req.Data["data"].(string)
+		if !ok {
+			p.LogError("invalid or missing data")
+			return
+		}
+		allowed, err := p.getConfiguration().isICEMessageAllowed([]byte(msgData))
+		if err != nil {
+			p.LogError(err.Error())
+			return
+		}
+		if !allowed {
+			p.LogDebug("dropping ICE candidate of disallowed type", "userID", userID, "connID", connID)
+			return
+		}
+		msg.Data = []byte(msgData)
+	case clientMessageTypeScreenOn:
 		msgData, ok := req.Data["data"].(string)
 		if !ok {
 			p.LogError("invalid or missing data")
@@ -1243,6 +1720,20 @@ func (p *Plugin) WebSocketMessageHasBeenPosted(connID, userID string, req *model
 			return
 		}
 		msg.Data = []byte(msgData)
+	case clientMessageTypeChat:
+		msgData, ok := req.Data["data"].(string)
+		if !ok {
+			p.LogError("invalid or missing chat data")
+			return
+		}
+		msg.Data = []byte(msgData)
+	case clientMessageTypeRecMarker:
+		msgData, ok := req.Data["data"].(string)
+		if !ok {
+			p.LogError("invalid or missing recording marker data")
+			return
+		}
+		msg.Data = []byte(msgData)
 	case clientMessageTypeCaption:
 		// Sent from the transcriber.
 		p.metrics.IncWebSocketEvent("in", msg.Type)
@@ -1265,7 +1756,18 @@ func (p *Plugin) WebSocketMessageHasBeenPosted(connID, userID string, req *model
 			p.LogError("invalid or missing new_audio_len_ms in caption ws message")
 			return
 		}
-		if err := p.handleCaptionMessage(us.callID, us.channelID, sessionID, text, newAudioLenMs); err != nil {
+		// Partial (interim) captions are optional. Transcribers not sending
+		// them only produce final captions.
+		caption := liveCaption{
+			SessionID: sessionID,
+			Text:      text,
+			IsFinal:   true,
+		}
+		if isFinal, ok := req.Data["is_final"].(bool); ok {
+			caption.IsFinal = isFinal
+		}
+		caption.CaptionID, _ = req.Data["caption_id"].(string)
+		if err := p.handleCaptionMessage(us, caption, newAudioLenMs); err != nil {
 			p.LogError("handleCaptionMessage failed", "err", err.Error(), "userID", userID, "connID", connID)
 			return
 		}
@@ -1278,7 +1780,7 @@ func (p *Plugin) WebSocketMessageHasBeenPosted(connID, userID string, req *model
 			p.LogError("invalid or missing metric_name in metric ws message")
 			return
 		}
-		if err := p.handleMetricMessage(public.MetricName(metricName), userID, req.Data["data"]); err != nil {
+		if err := p.handleMetricMessage(us, public.MetricName(metricName), req.Data["data"]); err != nil {
 			p.LogError("handleMetricMessage failed", "err", err.Error())
 			return
 		}
@@ -1295,9 +1797,9 @@ func (p *Plugin) WebSocketMessageHasBeenPosted(connID, userID string, req *model
 
 func (p *Plugin) closeRTCSession(userID, connID, channelID, handlerID, callID string) error {
 	p.LogDebug("closeRTCSession", "userID", userID, "connID", connID, "channelID", channelID)
-	if p.rtcServer != nil {
+	if rtcServer := p.getRTCServer(); rtcServer != nil {
 		if handlerID == p.nodeID {
-			if err := p.rtcServer.CloseSession(connID); err != nil {
+			if err := rtcServer.CloseSession(connID); err != nil {
 				return err
 			}
 		} else {
@@ -1375,36 +1877,56 @@ func (p *Plugin) handleBotWSReconnect(connID, prevConnID, originalConnID, channe
 	return nil
 }
 
-func (p *Plugin) handleCaptionMessage(callID, channelID, captionFromSessionID, text string, newAudioLenMs float64) error {
+// liveCaption is a caption produced by the transcriber for a given session.
+// Partial captions are later replaced by the final caption with the same ID.
+type liveCaption struct {
+	SessionID string
+	CaptionID string
+	Text      string
+	IsFinal   bool
+}
+
+func (p *Plugin) handleCaptionMessage(us *session, caption liveCaption, newAudioLenMs float64) error {
+	// The audio has been transcribed regardless of whether captions are
+	// forwarded.
+	p.metrics.ObserveLiveCaptionsAudioLen(newAudioLenMs)
+
+	if us.liveCaptionsOff.Load() {
+		return nil
+	}
+
+	callID, channelID := us.callID, us.getChannelID()
+
 	sessions, err := p.store.GetCallSessions(callID, db.GetCallSessionOpts{})
 	if err != nil {
 		return fmt.Errorf("failed to get call sessions: %w", err)
 	}
 
-	captionSession, ok := sessions[captionFromSessionID]
+	captionSession, ok := sessions[caption.SessionID]
 	if !ok {
 		return fmt.Errorf("user session for caption missing from call")
 	}
 
 	p.publishWebSocketEvent(wsEventCaption, map[string]interface{}{
 		"channel_id": channelID,
+		"call_id":    callID,
 		"user_id":    captionSession.UserID,
 		"session_id": captionSession.ID,
-		"text":       text,
+		"text":       caption.Text,
+		"caption_id": caption.CaptionID,
+		"is_final":   caption.IsFinal,
 	}, &WebSocketBroadcast{
 		ChannelID:           channelID,
 		ReliableClusterSend: true,
 		UserIDs:             getUserIDsFromSessions(sessions),
 	})
 
-	p.metrics.ObserveLiveCaptionsAudioLen(newAudioLenMs)
-
 	return nil
 }
 
-func (p *Plugin) handleMetricMessage(metricName public.MetricName, userID string, payload any) error {
+func (p *Plugin) handleMetricMessage(us *session, metricName public.MetricName, payload any) error {
 	// Bot only metrics
-	if userID == p.getBotID() {
+	if us.userID == p.getBotID() {
 		switch metricName {
 		case public.MetricLiveCaptionsWindowDropped:
 			p.metrics.IncLiveCaptionsWindowDropped()
@@ -1436,6 +1958,115 @@ func (p *Plugin) handleMetricMessage(metricName public.MetricName, userID string
 		}
 
 		p.metrics.IncClientICECandidatePairs(payload)
+
+		// Clients report the selected candidate pair once the ICE connection
+		// has been established.
+		if payload.State == "succeeded" && atomic.CompareAndSwapInt32(&us.iceConnected, 0, 1) {
+			close(us.iceConnectedCh)
+			p.metrics.IncICEConnections(iceConnectionStateConnected)
+		}
+	case public.MetricClientJitterBufferDelay:
+		data, ok := payload.(string)
+		if !ok {
+			return fmt.Errorf("invalid payload found in metric message")
+		}
+
+		var payload public.ClientJitterBufferDelayMetricPayload
+
+		if err := json.Unmarshal([]byte(data), &payload); err != nil {
+			return fmt.Errorf("failed to unmarshal payload: %w", err)
+		}
+
+		if err := payload.IsValid(); err != nil {
+			return fmt.Errorf("failed to validate payload: %w", err)
+		}
+
+		p.metrics.ObserveClientJitterBufferDelay(payload.DelayMs)
+	case public.MetricClientMediaKeepAlive:
+		data, ok := payload.(string)
+		if !ok {
+			return fmt.Errorf("invalid payload found in metric message")
+		}
+
+		var payload public.ClientMediaKeepAliveMetricPayload
+
+		if err := json.Unmarshal([]byte(data), &payload); err != nil {
+			return fmt.Errorf("failed to unmarshal payload: %w", err)
+		}
+
+		if err := payload.IsValid(); err != nil {
+			return fmt.Errorf("failed to validate payload: %w", err)
+		}
+
+		us.recordMediaActivity(payload.BytesReceived)
+	case public.MetricClientKeyFrameRequests:
+		data, ok := payload.(string)
+		if !ok {
+			return fmt.Errorf("invalid payload found in metric message")
+		}
+
+		var payload public.ClientKeyFrameRequestsMetricPayload
+
+		if err := json.Unmarshal([]byte(data), &payload); err != nil {
+			return fmt.Errorf("failed to unmarshal payload: %w", err)
+		}
+
+		if err := payload.IsValid(); err != nil {
+			return fmt.Errorf("failed to validate payload: %w", err)
+		}
+
+		// Requests sent by viewers are what the SFU receives, requests
+		// received by publishers are what it forwards.
+		if payload.Sent > 0 {
+			p.metrics.AddKeyFrameRequests("requested", payload.Sent)
+		}
+		if payload.Received > 0 {
+			p.metrics.AddKeyFrameRequests("forwarded", payload.Received)
+		}
+	case public.MetricClientICEFailure:
+		data, ok := payload.(string)
+		if !ok {
+			return fmt.Errorf("invalid payload found in metric message")
+		}
+
+		var payload public.ClientICEFailureMetricPayload
+
+		if err := json.Unmarshal([]byte(data), &payload); err != nil {
+			return fmt.Errorf("failed to unmarshal payload: %w", err)
+		}
+
+		if err := payload.IsValid(); err != nil {
+			return fmt.Errorf("failed to validate payload: %w", err)
+		}
+
+		platform := payload.Platform
+		if platform == "" {
+			platform = "unknown"
+		}
+
+		p.LogWarn("client ICE connection failed",
+			"userID", us.userID, "connID", us.connID, "channelID", us.getChannelID(), "callID", us.callID,
+			"candidateType", payload.CandidateType, "platform", platform)
+		p.metrics.IncICEConnectionFailures(payload.CandidateType, platform)
+		p.recordCallTimelineEvent(us.callID, callTimelineEventICEFailure, us.userID, us.originalConnID, payload.CandidateType)
+	case public.MetricClientConnectionQuality:
+		data, ok := payload.(string)
+		if !ok {
+			return fmt.Errorf("invalid payload found in metric message")
+		}
+
+		var payload public.ClientConnectionQualityMetricPayload
+
+		if err := json.Unmarshal([]byte(data), &payload); err != nil {
+			return fmt.Errorf("failed to unmarshal payload: %w", err)
+		}
+
+		if err := payload.IsValid(); err != nil {
+			return fmt.Errorf("failed to validate payload: %w", err)
+		}
+
+		p.metrics.ObserveClientPacketLoss(p.getRTCServerType(), payload.LossRate)
+		p.handleConnectionQualityReport(us, payload)
 	}
 
 	return nil
@@ -326,12 +326,12 @@ func TestWSReader(t *testing.T) {
 
 			mockAPI.On("LogInfo", "invalid or expired session, closing RTC session",
 				"origin", mock.AnythingOfType("string"),
-				"channelID", us.channelID, "userID", us.userID, "connID", us.connID,
+				"channelID", us.getChannelID(), "userID", us.userID, "connID", us.connID,
 				"sessionID", "authSessionID", "expiresAt", fmt.Sprintf("%d", expiresAt)).Once()
 
 			mockAPI.On("LogDebug", "closeRTCSession",
 				"origin", mock.AnythingOfType("string"),
-				"userID", us.userID, "connID", us.connID, "channelID", us.channelID).Once()
+				"userID", us.userID, "connID", us.connID, "channelID", us.getChannelID()).Once()
 
 			var wg sync.WaitGroup
 			wg.Add(1)
@@ -356,12 +356,12 @@ func TestWSReader(t *testing.T) {
 
 			mockAPI.On("LogInfo", "invalid or expired session, closing RTC session",
 				"origin", mock.AnythingOfType("string"),
-				"channelID", us.channelID, "userID", us.userID, "connID", us.connID,
+				"channelID", us.getChannelID(), "userID", us.userID, "connID", us.connID,
 				"err", "GetSessionById: We encountered an error finding the session.").Once()
 
 			mockAPI.On("LogDebug", "closeRTCSession",
 				"origin", mock.AnythingOfType("string"),
-				"userID", us.userID, "connID", us.connID, "channelID", us.channelID).Once()
+				"userID", us.userID, "connID", us.connID, "channelID", us.getChannelID()).Once()
 
 			var wg sync.WaitGroup
 			wg.Add(1)
@@ -444,6 +444,35 @@ func TestHandleCallStateRequest(t *testing.T) {
 	})
 }
 
+func TestHandleCaptionMessageLiveCaptionsOff(t *testing.T) {
+	mockMetrics := &serverMocks.MockMetrics{}
+	defer mockMetrics.AssertExpectations(t)
+
+	us := newUserSession("botID", "channelID", "connID", "callID", false)
+	p := &Plugin{
+		metrics: mockMetrics,
+		sessions: map[string]*session{
+			"connID": us,
+		},
+	}
+
+	us.setCallProps(public.CallProps{LiveCaptionsOff: true})
+	require.True(t, us.liveCaptionsOff.Load())
+
+	// Captions are dropped without hitting the store.
+	mockMetrics.On("ObserveLiveCaptionsAudioLen", float64(1000)).Once()
+	err := p.handleCaptionMessage(us, liveCaption{SessionID: "sessionID", Text: "text"}, 1000)
+	require.NoError(t, err)
+
+	t.Run("toggled by the host", func(t *testing.T) {
+		p.setSessionsLiveCaptionsOff("otherCallID", false)
+		require.True(t, us.liveCaptionsOff.Load())
+
+		p.setSessionsLiveCaptionsOff("callID", false)
+		require.False(t, us.liveCaptionsOff.Load())
+	})
+}
+
 func TestWebSocketBroadcastToModel(t *testing.T) {
 	t.Run("nil/empty", func(t *testing.T) {
 		var wsb *WebSocketBroadcast
@@ -698,6 +727,11 @@ func TestHandleJoin(t *testing.T) {
 	t.Cleanup(tearDown)
 	p.store = store
 
+	// Focus mode is checked asynchronously as users join and leave.
+	mockAPI.On("GetPreferenceForUser", mock.Anything, callNotificationPreferencesCategory, callNotificationPreferencesName).
+		Return(model.Preference{}, &model.AppError{StatusCode: http.StatusNotFound}).Maybe()
+	mockAPI.On("KVGet", mock.Anything).Return(nil, nil).Maybe()
+
 	mockMetrics.On("ObserveClusterMutexGrabTime", "mutex_call", mock.AnythingOfType("float64"))
 	mockMetrics.On("ObserveClusterMutexLockedTime", "mutex_call", mock.AnythingOfType("float64"))
 	mockMetrics.On("ObserveAppHandlersTime", mock.AnythingOfType("string"), mock.AnythingOfType("float64"))
@@ -809,6 +843,11 @@ func TestHandleJoin(t *testing.T) {
 
 		mockAPI.On("UpdatePost", mock.AnythingOfType("*model.Post")).Return(&model.Post{Id: postID}, nil).Once()
 
+		mockMetrics.On("IncWebSocketEvent", "out", wsEventCallEnd).Once()
+		mockAPI.On("PublishWebSocketEvent", wsEventCallEnd, mock.MatchedBy(func(data map[string]any) bool {
+			return data["reason"] == string(public.CallEndReasonAllLeft)
+		}), &model.WebsocketBroadcast{ChannelId: channelID, ReliableClusterSend: true}).Once()
+
 		// Call unlock
 		mockAPI.On("KVDelete", "mutex_call_"+channelID).Return(nil).Once()
 
@@ -948,6 +987,11 @@ func TestHandleJoin(t *testing.T) {
 
 		mockAPI.On("UpdatePost", mock.AnythingOfType("*model.Post")).Return(&model.Post{Id: postID}, nil).Once()
 
+		mockMetrics.On("IncWebSocketEvent", "out", wsEventCallEnd).Once()
+		mockAPI.On("PublishWebSocketEvent", wsEventCallEnd, mock.MatchedBy(func(data map[string]any) bool {
+			return data["reason"] == string(public.CallEndReasonAllLeft)
+		}), &model.WebsocketBroadcast{ChannelId: channelID, ReliableClusterSend: true}).Once()
+
 		// Call unlock
 		mockAPI.On("KVDelete", "mutex_call_"+channelID).Return(nil).Once()
 
@@ -1276,6 +1320,11 @@ func TestHandleJoin(t *testing.T) {
 
 		mockAPI.On("UpdatePost", mock.AnythingOfType("*model.Post")).Return(&model.Post{Id: postID}, nil).Once()
 
+		mockMetrics.On("IncWebSocketEvent", "out", wsEventCallEnd).Once()
+		mockAPI.On("PublishWebSocketEvent", wsEventCallEnd, mock.MatchedBy(func(data map[string]any) bool {
+			return data["reason"] == string(public.CallEndReasonAllLeft)
+		}), &model.WebsocketBroadcast{ChannelId: channelID, ReliableClusterSend: true}).Once()
+
 		mockAPI.On("LogWarn", "The number of active call sessions is high. Consider deploying a dedicated RTCD service.", mock.Anything, mock.Anything)
 
 		err := p.handleJoin(userID, connID, authSessionID, callsJoinData{
@@ -1305,3 +1354,134 @@ func TestHandleJoin(t *testing.T) {
 		time.Sleep(2 * time.Second)
 	})
 }
+
+func TestCheckCallChannelAccess(t *testing.T) {
+	mockAPI := &pluginMocks.MockAPI{}
+	defer mockAPI.AssertExpectations(t)
+
+	botID := model.NewId()
+	p := Plugin{
+		MattermostPlugin: plugin.MattermostPlugin{
+			API: mockAPI,
+		},
+		botSession: &model.Session{UserId: botID},
+	}
+
+	cfg := &configuration{}
+	cfg.SetDefaults()
+	p.configuration = cfg
+
+	channelID := model.NewId()
+	userID := model.NewId()
+
+	t.Run("bot", func(t *testing.T) {
+		canPost, err := p.checkCallChannelAccess(botID, channelID)
+		require.NoError(t, err)
+		require.True(t, canPost)
+	})
+
+	t.Run("can post", func(t *testing.T) {
+		mockAPI.On("HasPermissionToChannel", userID, channelID, model.PermissionCreatePost).Return(true).Once()
+		canPost, err := p.checkCallChannelAccess(userID, channelID)
+		require.NoError(t, err)
+		require.True(t, canPost)
+	})
+
+	t.Run("no access", func(t *testing.T) {
+		mockAPI.On("HasPermissionToChannel", userID, channelID, model.PermissionCreatePost).Return(false).Once()
+		mockAPI.On("HasPermissionToChannel", userID, channelID, model.PermissionReadChannel).Return(false).Once()
+		_, err := p.checkCallChannelAccess(userID, channelID)
+		require.EqualError(t, err, "forbidden")
+	})
+
+	t.Run("read-only channel", func(t *testing.T) {
+		mockAPI.On("HasPermissionToChannel", userID, channelID, model.PermissionCreatePost).Return(false).Once()
+		mockAPI.On("HasPermissionToChannel", userID, channelID, model.PermissionReadChannel).Return(true).Once()
+		_, err := p.checkCallChannelAccess(userID, channelID)
+		require.Equal(t, errReadOnlyChannel, err)
+	})
+
+	t.Run("read-only channel allowed", func(t *testing.T) {
+		cfg.AllowCallsInReadOnlyChannels = model.NewPointer(true)
+		defer func() { cfg.AllowCallsInReadOnlyChannels = model.NewPointer(false) }()
+
+		mockAPI.On("HasPermissionToChannel", userID, channelID, model.PermissionCreatePost).Return(false).Once()
+		mockAPI.On("HasPermissionToChannel", userID, channelID, model.PermissionReadChannel).Return(true).Once()
+		canPost, err := p.checkCallChannelAccess(userID, channelID)
+		require.NoError(t, err)
+		require.False(t, canPost)
+	})
+}
+
+func TestHandleJoinChannelRestrictions(t *testing.T) {
+	mockAPI := &pluginMocks.MockAPI{}
+	defer mockAPI.AssertExpectations(t)
+
+	p := Plugin{
+		MattermostPlugin: plugin.MattermostPlugin{
+			API: mockAPI,
+		},
+	}
+
+	cfg := &configuration{}
+	cfg.SetDefaults()
+	p.configuration = cfg
+
+	mockAPI.On("LogDebug", mock.Anything, mock.Anything, mock.Anything,
+		mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+		mock.Anything, mock.Anything)
+
+	userID := model.NewId()
+	connID := model.NewId()
+
+	t.Run("archived channel", func(t *testing.T) {
+		channelID := model.NewId()
+		mockAPI.On("HasPermissionToChannel", userID, channelID, model.PermissionCreatePost).Return(true).Once()
+		mockAPI.On("GetChannel", channelID).Return(&model.Channel{
+			Id:       channelID,
+			Type:     model.ChannelTypeOpen,
+			DeleteAt: time.Now().UnixMilli(),
+		}, nil).Once()
+
+		err := p.handleJoin(userID, connID, "", callsJoinData{
+			CallsClientJoinData: CallsClientJoinData{
+				ChannelID: channelID,
+			},
+		})
+		require.EqualError(t, err, "calls are not available in archived channels")
+	})
+
+	t.Run("read-only channel", func(t *testing.T) {
+		channelID := model.NewId()
+		mockAPI.On("HasPermissionToChannel", userID, channelID, model.PermissionCreatePost).Return(false).Once()
+		mockAPI.On("HasPermissionToChannel", userID, channelID, model.PermissionReadChannel).Return(true).Once()
+
+		err := p.handleJoin(userID, connID, "", callsJoinData{
+			CallsClientJoinData: CallsClientJoinData{
+				ChannelID: channelID,
+			},
+		})
+		require.EqualError(t, err, "calls are not allowed in read-only channels")
+	})
+
+	t.Run("archived read-only channel", func(t *testing.T) {
+		cfg.AllowCallsInReadOnlyChannels = model.NewPointer(true)
+		defer func() { cfg.AllowCallsInReadOnlyChannels = model.NewPointer(false) }()
+
+		channelID := model.NewId()
+		mockAPI.On("HasPermissionToChannel", userID, channelID, model.PermissionCreatePost).Return(false).Once()
+		mockAPI.On("HasPermissionToChannel", userID, channelID, model.PermissionReadChannel).Return(true).Once()
+		mockAPI.On("GetChannel", channelID).Return(&model.Channel{
+			Id:       channelID,
+			Type:     model.ChannelTypeOpen,
+			DeleteAt: time.Now().UnixMilli(),
+		}, nil).Once()
+
+		err := p.handleJoin(userID, connID, "", callsJoinData{
+			CallsClientJoinData: CallsClientJoinData{
+				ChannelID: channelID,
+			},
+		})
+		require.EqualError(t, err, "calls are not available in archived channels")
+	})
+}
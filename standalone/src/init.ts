@@ -50,7 +50,7 @@ import {
 import {pluginId} from 'plugin/manifest';
 import reducer from 'plugin/reducers';
 import RestClient from 'plugin/rest_client';
-import {callsConfig, callsVersionInfo, iceServers, needsTURNCredentials} from 'plugin/selectors';
+import {callsConfig, callsVersionInfo, iceServers, iceTransportPolicy, needsTURNCredentials} from 'plugin/selectors';
 import {DesktopNotificationArgs, Store, WebAppUtils} from 'plugin/types/mattermost-webapp';
 import {
     getPluginPath,
@@ -60,12 +60,15 @@ import {
     handleCallEnd,
     handleCallHostChanged,
     handleCallJobState,
+    handleCallMoved,
+    handleCallSpeakerLabels,
     handleCallStart,
     handleCallState,
     handleHostLowerHand,
     handleHostMute,
     handleHostRemoved,
     handleHostScreenOff,
+    handleNoiseMute,
     handleUserDismissedNotification,
     handleUserJoined,
     handleUserLeft,
@@ -81,7 +84,15 @@ import {
     handleUserVoiceOn,
 } from 'plugin/websocket_handlers';
 import {Reducer} from 'redux';
-import {CallActions, CallsClientConfig, CurrentCallData, CurrentCallDataDefault} from 'src/types/types';
+import {
+    CallActions,
+    CallMovedData,
+    CallsClientConfig,
+    CallSpeakerLabelsData,
+    CurrentCallData,
+    CurrentCallDataDefault,
+    NoiseMuteData,
+} from 'src/types/types';
 
 import {
     getCallID,
@@ -234,6 +245,7 @@ export default async function init(cfg: InitConfig) {
     const clientConfig = {
         wsURL: getWSConnectionURL(getConfig(store.getState())),
         iceServers: iceConfigs,
+        iceTransportPolicy: iceTransportPolicy(store.getState()),
         authToken: getToken(),
         simulcast: callsConfig(store.getState()).EnableSimulcast,
         enableAV1: callsConfig(store.getState()).EnableAV1,
@@ -252,6 +264,9 @@ export default async function init(cfg: InitConfig) {
         case `custom_${pluginId}_call_end`:
             handleCallEnd(store, ev as WebSocketMessage<EmptyData>);
             break;
+        case `custom_${pluginId}_call_moved`:
+            handleCallMoved(store, ev as WebSocketMessage<CallMovedData>);
+            break;
         case `custom_${pluginId}_user_joined`:
             handleUserJoined(store, ev as WebSocketMessage<UserJoinedData>);
             break;
@@ -282,6 +297,9 @@ export default async function init(cfg: InitConfig) {
         case `custom_${pluginId}_user_unraise_hand`:
             handleUserUnraisedHand(store, ev as WebSocketMessage<UserRaiseUnraiseHandData>);
             break;
+        case `custom_${pluginId}_call_speaker_labels`:
+            handleCallSpeakerLabels(store, ev as WebSocketMessage<CallSpeakerLabelsData>);
+            break;
         case `custom_${pluginId}_call_host_changed`:
             handleCallHostChanged(store, ev as WebSocketMessage<CallHostChangedData>);
             break;
@@ -300,6 +318,9 @@ export default async function init(cfg: InitConfig) {
         case `custom_${pluginId}_host_mute`:
             handleHostMute(store, ev as WebSocketMessage<HostControlMsg>);
             break;
+        case `custom_${pluginId}_noise_mute`:
+            handleNoiseMute(store, ev as WebSocketMessage<NoiseMuteData>);
+            break;
         case `custom_${pluginId}_host_screen_off`:
             handleHostScreenOff(store, ev as WebSocketMessage<HostControlMsg>);
             break;
@@ -6,3 +6,5 @@ import {pluginId} from 'plugin/manifest';
 export const storeKey = `plugins-${pluginId}-recording`;
 
 export const RECEIVED_CALL_PROFILE_IMAGES = pluginId + '_received_call_profile_images';
+export const RECORDING_PAUSED = pluginId + '_recording_paused';
+export const RECORDING_RESUMED = pluginId + '_recording_resumed';
@@ -7,20 +7,26 @@ import Avatar from 'plugin/components/avatar/avatar';
 import ParticipantsGrid from 'plugin/components/expanded_view/participants_grid';
 import {logErr} from 'plugin/log';
 import {alphaSortSessions, getUserDisplayName, stateSortSessions, untranslatable} from 'plugin/utils';
-import React, {useCallback, useEffect, useState} from 'react';
+import React, {useCallback, useEffect, useRef, useState} from 'react';
 import {useIntl} from 'react-intl';
 import {useSelector} from 'react-redux';
 import ScreenIcon from 'src/components/icons/screen_icon';
 import {ReactionStream} from 'src/components/reaction_stream/reaction_stream';
 import Timestamp from 'src/components/timestamp';
-import {callProfileImages} from 'src/recording/selectors';
+import {callProfileImages, isRecordingPaused} from 'src/recording/selectors';
 import {
     hostIDForCurrentCall,
     profilesInCurrentCallMap,
+    recordingForCurrentCall,
     screenSharingSessionForCurrentCall,
     sessionsInCurrentCall,
+    speakerLabelsForCurrentCall,
 } from 'src/selectors';
 
+// How long someone needs to keep talking before the speaker label switches to
+// them, so that short interjections and crosstalk don't make it flicker.
+const SPEAKER_LABEL_DEBOUNCE_MS = 1000;
+
 const RecordingView = () => {
     const {formatMessage} = useIntl();
     const [screenPlayerNode, setScreenPlayerNode] = useState<HTMLVideoElement | null>(null);
@@ -35,6 +41,45 @@ const RecordingView = () => {
     const profileImages = useSelector((state: GlobalState) => callProfileImages(state, callsClient?.channelID || ''));
 
     const hostID = useSelector((state: GlobalState) => hostIDForCurrentCall(state));
+    const recording = useSelector(recordingForCurrentCall);
+    const layout = recording?.layout;
+    const watermarkText = recording?.watermark_text;
+    const [activeSpeakerID, setActiveSpeakerID] = useState('');
+    const speakerLabels = useSelector(speakerLabelsForCurrentCall);
+    const [labelSpeakerID, setLabelSpeakerID] = useState('');
+
+    // While paused nothing from the call should end up in the recording so
+    // the view is blanked and all the voice tracks are silenced.
+    const paused = useSelector(isRecordingPaused);
+    const pausedRef = useRef(paused);
+    useEffect(() => {
+        pausedRef.current = paused;
+        document.querySelectorAll('audio').forEach((el) => {
+            el.muted = paused;
+        });
+    }, [paused]);
+
+    // In the active speaker layout we keep showing the last person who talked
+    // until someone else starts talking.
+    const speakingSessionID = sessions.find((session) => session.voice)?.session_id || '';
+    useEffect(() => {
+        if (speakingSessionID) {
+            setActiveSpeakerID(speakingSessionID);
+        }
+    }, [speakingSessionID]);
+
+    useEffect(() => {
+        if (!speakerLabels) {
+            setLabelSpeakerID('');
+            return undefined;
+        }
+
+        const timeoutID = setTimeout(() => {
+            setLabelSpeakerID(speakingSessionID);
+        }, SPEAKER_LABEL_DEBOUNCE_MS);
+
+        return () => clearTimeout(timeoutID);
+    }, [speakerLabels, speakingSessionID]);
 
     const attachVoiceTracks = (tracks: MediaStreamTrack[]) => {
         for (const track of tracks) {
@@ -42,6 +87,7 @@ const RecordingView = () => {
             audioEl.srcObject = new MediaStream([track]);
             audioEl.controls = false;
             audioEl.autoplay = true;
+            audioEl.muted = pausedRef.current;
             audioEl.style.display = 'none';
             audioEl.onerror = (err) => logErr(err);
             document.body.appendChild(audioEl);
@@ -169,14 +215,76 @@ const RecordingView = () => {
         );
     };
 
+    const renderActiveSpeaker = () => {
+        const session = sessions.find((s) => s.session_id === activeSpeakerID) || sessions[0];
+        const profile = session ? profiles[session.user_id] : null;
+        if (!profile) {
+            return null;
+        }
+
+        return (
+            <div style={style.activeSpeakerContainer}>
+                <Avatar
+                    size={192}
+                    fontSize={96}
+                    border={false}
+                    borderGlowWidth={session.voice ? 6 : 0}
+                    url={profileImages[profile.id]}
+                />
+                <span style={style.activeSpeakerName}>{getUserDisplayName(profile)}</span>
+            </div>
+        );
+    };
+
+    const renderSpeakerLabel = () => {
+        const session = sessions.find((s) => s.session_id === labelSpeakerID);
+        const profile = session ? profiles[session.user_id] : null;
+        if (!profile) {
+            return null;
+        }
+
+        return (
+            <div style={style.speakerLabel}>
+                {untranslatable(getUserDisplayName(profile))}
+            </div>
+        );
+    };
+
+    // The watermark is burned onto every frame, including the paused ones.
+    const renderWatermark = () => {
+        if (!watermarkText) {
+            return null;
+        }
+
+        return (
+            <div style={style.watermark}>
+                {untranslatable(watermarkText)}
+            </div>
+        );
+    };
+
+    if (paused) {
+        return (
+            <div
+                id='calls-recording-view'
+                style={{...style.root, ...style.paused}}
+            >
+                <span>{formatMessage({defaultMessage: 'Recording paused'})}</span>
+                {renderWatermark()}
+            </div>
+        );
+    }
+
     const hasScreenShare = Boolean(screenSharingSession);
+    const isActiveSpeakerLayout = layout === 'active_speaker';
 
     return (
         <div
             id='calls-recording-view'
             style={style.root}
         >
-            {!hasScreenShare &&
+            {!hasScreenShare && isActiveSpeakerLayout && renderActiveSpeaker()}
+            {!hasScreenShare && !isActiveSpeakerLayout &&
             <ParticipantsGrid
                 callID={callsClient.channelID}
                 callHostID={hostID}
@@ -200,6 +308,11 @@ const RecordingView = () => {
             <div style={style.reactionsContainer}>
                 <ReactionStream/>
             </div>
+
+            {/* The active speaker layout already shows the name of who's talking. */}
+            {speakerLabels && (hasScreenShare || !isActiveSpeakerLayout) && renderSpeakerLabel()}
+
+            {renderWatermark()}
         </div>
     );
 };
@@ -231,10 +344,59 @@ const style = {
         width: '100%',
         minHeight: '100%',
     },
+    activeSpeakerContainer: {
+        display: 'flex',
+        flexDirection: 'column',
+        justifyContent: 'center',
+        alignItems: 'center',
+        width: '100%',
+        height: 'calc(100vh - 32px)',
+    },
+    activeSpeakerName: {
+        marginTop: '24px',
+        fontWeight: 600,
+        fontSize: '24px',
+        lineHeight: '32px',
+    },
+    paused: {
+        justifyContent: 'center',
+        alignItems: 'center',
+        fontWeight: 600,
+        fontSize: '24px',
+        lineHeight: '32px',
+    },
     reactionsContainer: {
         position: 'absolute',
         bottom: '48px',
     },
+    speakerLabel: {
+        position: 'absolute',
+        bottom: '48px',
+        left: '16px',
+        padding: '4px 8px',
+        borderRadius: '4px',
+        background: 'rgba(9, 10, 11, 0.72)',
+        color: 'white',
+        fontWeight: 600,
+        fontSize: '16px',
+        lineHeight: '24px',
+        pointerEvents: 'none',
+        zIndex: 1,
+    },
+    watermark: {
+        position: 'absolute',
+        top: '16px',
+        right: '16px',
+        padding: '4px 8px',
+        borderRadius: '4px',
+        background: 'rgba(9, 10, 11, 0.48)',
+        color: 'rgba(255, 255, 255, 0.72)',
+        fontWeight: 600,
+        fontSize: '14px',
+        lineHeight: '20px',
+        pointerEvents: 'none',
+        zIndex: 1,
+    },
     footer: {
         display: 'flex',
         background: '#000000',
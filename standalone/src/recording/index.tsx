@@ -1,17 +1,18 @@
 // Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
 // See LICENSE.txt for license information.
 
-import {CallState, CallStateData, JobStopData, UserJoinedData, WebsocketEventData} from '@mattermost/calls-common/lib/types';
+import {JobStopData, WebsocketEventData} from '@mattermost/calls-common/lib/types';
 import {WebSocketMessage} from '@mattermost/client/websocket';
+import {UserProfile} from '@mattermost/types/users';
 import {ChannelTypes} from 'mattermost-redux/action_types';
 import {getCurrentUserLocale} from 'mattermost-redux/selectors/entities/i18n';
+import {setProfilesFetcher} from 'plugin/actions';
 import {logErr, logInfo} from 'plugin/log';
 import {pluginId} from 'plugin/manifest';
 import {Store} from 'plugin/types/mattermost-webapp';
 import {
     fetchTranslationsFile,
     getPluginPath,
-    getUserIDsForSessions,
     runWithRetry,
     setCallsGlobalCSSVars,
 } from 'plugin/utils';
@@ -26,22 +27,24 @@ import RestClient from 'src/rest_client';
 import init, {InitCbProps} from '../init';
 import {
     RECEIVED_CALL_PROFILE_IMAGES,
+    RECORDING_PAUSED,
+    RECORDING_RESUMED,
 } from './action_types';
 import RecordingView from './components/recording_view';
 
-async function fetchProfileImages(ids: string[]) {
+async function fetchProfileImages(imageIDs: {[userID: string]: string}) {
     const profileImages: {[userID: string]: string} = {};
     const promises = [];
-    for (const id of ids) {
+    for (const [userID, imageID] of Object.entries(imageIDs)) {
         promises.push(
             runWithRetry(() => {
-                return fetch(`${getPluginPath()}/bot/users/${id}/image`, RestClient.getOptions({method: 'get'})).then((res) => {
+                return fetch(`${getPluginPath()}/bot/users/${imageID}/image`, RestClient.getOptions({method: 'get'})).then((res) => {
                     if (!res.ok) {
                         throw new Error('image fetch failed');
                     }
                     return res.blob();
                 }).then((data) => {
-                    profileImages[id] = URL.createObjectURL(data);
+                    profileImages[userID] = URL.createObjectURL(data);
                 });
             }),
         );
@@ -56,7 +59,42 @@ async function fetchProfileImages(ids: string[]) {
     return profileImages;
 }
 
+// fetchCallProfiles resolves the profiles of call participants through the
+// bot API, which returns pseudonyms in place of the real profiles when the call
+// is recorded anonymously. Profiles are still keyed by user ID as that's what
+// sessions refer to, while images are fetched by the ID the server returned so
+// that anonymized participants get the placeholder avatar.
+async function fetchCallProfiles(store: Store, channelID: string, ids: string[]) {
+    const data: {[userID: string]: UserProfile} = await runWithRetry(() => {
+        return RestClient.fetch(`${getPluginPath()}/bot/calls/${channelID}/profiles`, {
+            method: 'post',
+            body: JSON.stringify(ids),
+        });
+    });
+
+    const profiles: UserProfile[] = [];
+    const imageIDs: {[userID: string]: string} = {};
+    for (const [userID, profile] of Object.entries(data)) {
+        profiles.push({...profile, id: userID});
+        imageIDs[userID] = profile.id;
+    }
+
+    fetchProfileImages(imageIDs).then((images) => {
+        store.dispatch({
+            type: RECEIVED_CALL_PROFILE_IMAGES,
+            data: {
+                channelID,
+                profileImages: images,
+            },
+        });
+    });
+
+    return profiles;
+}
+
 async function initRecordingStore(store: Store, channelID: string) {
+    setProfilesFetcher((ids) => fetchCallProfiles(store, channelID, ids));
+
     try {
         const channel = await runWithRetry(() => {
             return RestClient.fetch(`${getPluginPath()}/bot/channels/${channelID}`, {method: 'get'});
@@ -103,53 +141,32 @@ async function initRecording({store, theme}: InitCbProps) {
 
 function wsHandlerRecording(store: Store, ev: WebSocketMessage<WebsocketEventData>) {
     switch (ev.event) {
-    case `custom_${pluginId}_user_joined`: {
-        const data = ev.data as UserJoinedData;
-
-        runWithRetry(() => {
-            return fetchProfileImages([data.user_id]);
-        }).then((images) => {
-            store.dispatch({
-                type: RECEIVED_CALL_PROFILE_IMAGES,
-                data: {
-                    channelID: data.channelID,
-                    profileImages: images,
-                },
-            });
-        }).catch((err) => {
-            logErr('failed to fetch user profiles', err);
-        });
+    case `custom_${pluginId}_job_stop`: {
+        const data = ev.data as JobStopData;
+
+        if (getJobID() === data.job_id) {
+            logInfo('received job stop event, disconnecting');
+            window.callsClient?.disconnect();
+        }
 
         break;
     }
-    case `custom_${pluginId}_call_state`: {
-        const data = ev.data as CallStateData;
-        const call: CallState = JSON.parse(data.call);
+    case `custom_${pluginId}_job_pause`: {
+        const data = ev.data as JobStopData;
 
-        if (call.sessions?.length > 0) {
-            runWithRetry(() => {
-                return fetchProfileImages(getUserIDsForSessions(call.sessions));
-            }).then((images) => {
-                store.dispatch({
-                    type: RECEIVED_CALL_PROFILE_IMAGES,
-                    data: {
-                        channelID: data.channel_id,
-                        profileImages: images,
-                    },
-                });
-            }).catch((err) => {
-                logErr('failed to fetch profile images', err);
-            });
+        if (getJobID() === data.job_id) {
+            logInfo('received job pause event, blanking output');
+            store.dispatch({type: RECORDING_PAUSED});
         }
 
         break;
     }
-    case `custom_${pluginId}_job_stop`: {
+    case `custom_${pluginId}_job_resume`: {
         const data = ev.data as JobStopData;
 
         if (getJobID() === data.job_id) {
-            logInfo('received job stop event, disconnecting');
-            window.callsClient?.disconnect();
+            logInfo('received job resume event, resuming output');
+            store.dispatch({type: RECORDING_RESUMED});
         }
 
         break;
@@ -5,6 +5,8 @@ import {combineReducers, Reducer} from 'redux';
 
 import {
     RECEIVED_CALL_PROFILE_IMAGES,
+    RECORDING_PAUSED,
+    RECORDING_RESUMED,
 } from './action_types';
 
 interface callProfileImagesState {
@@ -48,6 +50,18 @@ const callProfileImages = (state: callProfileImagesState = {}, action: callProfi
     }
 };
 
+const paused = (state = false, action: {type: string}) => {
+    switch (action.type) {
+    case RECORDING_PAUSED:
+        return true;
+    case RECORDING_RESUMED:
+        return false;
+    default:
+        return state;
+    }
+};
+
 export default combineReducers({
     callProfileImages,
+    paused,
 }) as Reducer;
@@ -14,3 +14,5 @@ export const callProfileImages = (state: GlobalState, channelID: string) => {
     }
     return getState(state).callProfileImages[channelID];
 };
+
+export const isRecordingPaused = (state: GlobalState): boolean => Boolean(getState(state).paused);
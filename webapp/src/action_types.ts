@@ -19,12 +19,16 @@ export const USER_LOWER_HAND = pluginId + '_user_unraise_hand';
 export const USER_REACTED = pluginId + '_user_reacted';
 export const USER_REACTED_TIMEOUT = pluginId + '_user_reacted_timeout';
 export const CALL_HOST = pluginId + '_call_host';
+export const CALL_CAPACITY = pluginId + '_call_capacity';
+export const CALL_QUALITY_DEGRADED = pluginId + '_call_quality_degraded';
+export const CALL_SPEAKER_LABELS = pluginId + '_call_speaker_labels';
 export const CALL_RECORDING_STATE = pluginId + '_call_recording_state';
 export const CALL_LIVE_CAPTIONS_STATE = pluginId + '_call_live_captions_state';
 export const CALL_REC_PROMPT_DISMISSED = pluginId + '_call_rec_prompt_dismissed';
 export const USER_JOINED_TIMEOUT = pluginId + '_user_joined_timeout';
 export const LIVE_CAPTION = pluginId + '_live_caption';
 export const LIVE_CAPTION_TIMEOUT_EVENT = pluginId + '_live_caption_timeout_event';
+export const CALL_CHAT_MESSAGE = pluginId + '_call_chat_message';
 export const HOST_CONTROL_NOTICE = pluginId + '_host_control_notice';
 export const HOST_CONTROL_NOTICE_TIMEOUT_EVENT = pluginId + '_host_control_notice_timeout_event';
 
@@ -45,6 +49,10 @@ export const DID_NOTIFY_FOR_CALL = pluginId + '_did_notify_for_call';
 //  and when the last session in the call leaves the call
 export const CALL_END = pluginId + '_call_end';
 
+// CALL_MOVED is sent when the call the current user is connected to
+//  gets moved to another channel
+export const CALL_MOVED = pluginId + '_call_moved';
+
 export const RECEIVED_CALLS_CONFIG = pluginId + '_received_calls_config';
 export const RECEIVED_CALLS_CONFIG_ENV_OVERRIDES = pluginId + '_received_calls_config_env_overrides';
 export const RECEIVED_CALLS_VERSION_INFO = pluginId + '_received_calls_version_info';
@@ -55,6 +63,7 @@ export const RTCD_ENABLED = pluginId + '_rtcd_enabled';
 export const TRANSCRIBE_API = pluginId + '_transcribe_api';
 export const RECEIVED_CHANNEL_STATE = pluginId + 'received_channel_state';
 export const RECEIVED_CALLS_USER_PREFERENCES = pluginId + '_received_calls_user_preferences';
+export const RECEIVED_CALL_NOTIFICATION_PREFERENCES = pluginId + '_received_call_notification_preferences';
 
 export const DESKTOP_WIDGET_CONNECTED = pluginId + '_desktop_widget_connected';
 
@@ -5,6 +5,8 @@
 import {CallsConfig, CallState, CallsVersionInfo} from '@mattermost/calls-common/lib/types';
 import {ClientError} from '@mattermost/client';
 import {Channel} from '@mattermost/types/channels';
+import {Options} from '@mattermost/types/client4';
+import {UserProfile} from '@mattermost/types/users';
 import {UserTypes} from 'mattermost-redux/action_types';
 import {getChannel as loadChannel} from 'mattermost-redux/actions/channels';
 import {bindClientFunc} from 'mattermost-redux/actions/helpers';
@@ -32,9 +34,10 @@ import {
     idForCurrentCall,
     incomingCalls,
     numSessionsInCallInChannel,
+    ringingEnabledInChannel,
     ringingForCall,
 } from 'src/selectors';
-import {CallsStats, ChannelType} from 'src/types/types';
+import {CallCapacityData, CallEndReason, CallNotificationPreferences, CallsStats, ChannelType} from 'src/types/types';
 import {
     getPluginPath,
     getSessionsMapFromSessions,
@@ -47,11 +50,13 @@ import {modals, notificationSounds, openPricingModal} from 'src/webapp_globals';
 
 import {
     ADD_INCOMING_CALL,
+    CALL_CAPACITY,
     CALL_END,
     CALL_HOST,
     CALL_LIVE_CAPTIONS_STATE,
     CALL_REC_PROMPT_DISMISSED,
     CALL_RECORDING_STATE,
+    CALL_SPEAKER_LABELS,
     CALL_STATE,
     CLIENT_CONNECTING,
     DID_RING_FOR_CALL,
@@ -61,6 +66,7 @@ import {
     HIDE_SWITCH_CALL_MODAL,
     LIVE_CAPTIONS_ENABLED,
     LOCAL_SESSION_CLOSE,
+    RECEIVED_CALL_NOTIFICATION_PREFERENCES,
     RECEIVED_CALLS_CONFIG,
     RECEIVED_CALLS_CONFIG_ENV_OVERRIDES,
     RECEIVED_CALLS_VERSION_INFO,
@@ -147,6 +153,37 @@ export const getCallsVersionInfo = (): ActionFuncAsync<CallsVersionInfo> => {
     });
 };
 
+export const getCallNotificationPreferences = (): ActionFuncAsync<CallNotificationPreferences> => {
+    return bindClientFunc({
+        clientFunc: () => RestClient.fetch<CallNotificationPreferences>(
+            `${getPluginPath()}/notification-preferences`,
+            {method: 'get'},
+        ),
+        onSuccess: [RECEIVED_CALL_NOTIFICATION_PREFERENCES],
+    });
+};
+
+export const setCallNotificationPreferences = (prefs: CallNotificationPreferences): ActionFuncAsync => {
+    return async (dispatch: DispatchFunc) => {
+        try {
+            await RestClient.fetch(
+                `${getPluginPath()}/notification-preferences`,
+                {method: 'post', body: JSON.stringify(prefs)},
+            );
+        } catch (err) {
+            logErr(err);
+            return {error: err};
+        }
+
+        dispatch({
+            type: RECEIVED_CALL_NOTIFICATION_PREFERENCES,
+            data: prefs,
+        });
+
+        return {data: true};
+    };
+};
+
 export const getCallActive = async (channelID: string) => {
     try {
         const res = await RestClient.fetch<{ active: boolean }>(
@@ -284,10 +321,15 @@ export function prefetchThread(postId: string) {
     };
 }
 
-export const startCallRecording = (callID: string) => (dispatch: Dispatch) => {
+export const startCallRecording = (callID: string, layout?: string) => (dispatch: Dispatch) => {
+    const opts: Options = {method: 'post'};
+    if (layout) {
+        opts.body = JSON.stringify({layout});
+    }
+
     RestClient.fetch(
         `${getPluginPath()}/calls/${callID}/recording/start`,
-        {method: 'post'},
+        opts,
     ).catch((err) => {
         dispatch({
             type: CALL_RECORDING_STATE,
@@ -312,6 +354,20 @@ export const stopCallRecording = async (callID: string) => {
     );
 };
 
+export const pauseCallRecording = async (callID: string) => {
+    return RestClient.fetch(
+        `${getPluginPath()}/calls/${callID}/recording/pause`,
+        {method: 'post'},
+    );
+};
+
+export const resumeCallRecording = async (callID: string) => {
+    return RestClient.fetch(
+        `${getPluginPath()}/calls/${callID}/recording/resume`,
+        {method: 'post'},
+    );
+};
+
 export const recordingPromptDismissedAt = (callID: string, dismissedAt: number) => (dispatch: Dispatch) => {
     dispatch({
         type: CALL_REC_PROMPT_DISMISSED,
@@ -365,6 +421,10 @@ export function incomingCallOnChannel(channelID: string, callID: string, callerI
             return;
         }
 
+        if (!ringingEnabledInChannel(getState(), channel)) {
+            return;
+        }
+
         if (callDismissedNotification(getState(), channelID)) {
             return;
         }
@@ -415,7 +475,7 @@ export const userLeft = (channelID: string, userID: string, sessionID: string) =
     };
 };
 
-export const callEnd = (channelID: string) => {
+export const callEnd = (channelID: string, reason?: CallEndReason) => {
     return (dispatch: DispatchFunc, getState: GetStateFunc) => {
         if (channelIDForCurrentCall(getState()) === channelID) {
             window.callsClient?.disconnect();
@@ -428,6 +488,7 @@ export const callEnd = (channelID: string) => {
             data: {
                 channelID,
                 callID,
+                reason,
             },
         });
 
@@ -502,6 +563,17 @@ export const stopRingingForCall = (callID: string): ActionFunc => {
     };
 };
 
+type ProfilesFetcher = (ids: string[]) => Promise<UserProfile[]>;
+
+let fetchProfilesByIds: ProfilesFetcher = (ids) => RestClient.getProfilesByIds(ids);
+
+// setProfilesFetcher overrides how the profiles of call participants are
+// fetched. The recording view uses it so that anonymized participants are
+// never resolved to their real profiles.
+export function setProfilesFetcher(fetcher: ProfilesFetcher) {
+    fetchProfilesByIds = fetcher;
+}
+
 export const loadProfilesByIdsIfMissing = (ids: string[]) => {
     return async (dispatch: DispatchFunc, getState: GetStateFunc) => {
         const missingIds = [];
@@ -511,12 +583,12 @@ export const loadProfilesByIdsIfMissing = (ids: string[]) => {
             }
         }
         if (missingIds.length > 0) {
-            dispatch({type: UserTypes.RECEIVED_PROFILES, data: await RestClient.getProfilesByIds(missingIds)});
+            dispatch({type: UserTypes.RECEIVED_PROFILES, data: await fetchProfilesByIds(missingIds)});
         }
     };
 };
 
-export const loadCallState = (channelID: string, call: CallState) => (dispatch: DispatchFunc, getState: GetStateFunc) => {
+export const loadCallState = (channelID: string, call: CallState & CallCapacityData) => (dispatch: DispatchFunc, getState: GetStateFunc) => {
     const actions: AnyAction[] = [];
 
     actions.push({
@@ -551,6 +623,7 @@ export const loadCallState = (channelID: string, call: CallState) => (dispatch:
         data: {
             channelID,
             session_id: call.screen_sharing_session_id,
+            screen_sharing_session_ids: (call as typeof call & {screen_sharing_session_ids?: string[]}).screen_sharing_session_ids,
         },
     });
 
@@ -563,6 +636,22 @@ export const loadCallState = (channelID: string, call: CallState) => (dispatch:
         },
     });
 
+    actions.push({
+        type: CALL_SPEAKER_LABELS,
+        data: {
+            channelID,
+            enabled: Boolean((call as typeof call & {speaker_labels?: boolean}).speaker_labels),
+        },
+    });
+
+    actions.push({
+        type: CALL_CAPACITY,
+        data: {
+            channelID,
+            atCapacity: Boolean(call.at_capacity),
+        },
+    });
+
     const dismissed = call.dismissed_notification;
     if (dismissed) {
         const currentUserID = getCurrentUserId(getState());
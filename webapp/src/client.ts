@@ -12,9 +12,11 @@ import {zlibSync, strToU8} from 'fflate';
 import {AudioDevices, CallsClientConfig, CallsClientStats, TrackInfo} from 'src/types/types';
 
 import {logDebug, logErr, logInfo, logWarn, persistClientLogs} from './log';
-import {getScreenStream, getPersistentStorage} from './utils';
+import {getScreenStream, getPersistentStorage, isDesktopApp} from './utils';
 import {WebSocketClient, WebSocketError, WebSocketErrorType} from './websocket';
 import {
+    SERVER_LOAD_SCREEN_MAX_FRAME_RATE,
+    SERVER_LOAD_SCREEN_MAX_HEIGHT,
     STORAGE_CALLS_CLIENT_STATS_KEY,
     STORAGE_CALLS_DEFAULT_AUDIO_INPUT_KEY,
     STORAGE_CALLS_DEFAULT_AUDIO_OUTPUT_KEY,
@@ -30,6 +32,7 @@ export const userRemovedFromChannelErr = new Error('user was removed from channe
 export const userLeftChannelErr = new Error('user has left channel');
 
 const rtcMonitorInterval = 10000;
+const mediaKeepAliveInterval = 10000;
 
 export default class CallsClient extends EventEmitter {
     public channelID: string;
@@ -52,7 +55,11 @@ export default class CallsClient extends EventEmitter {
     private connected = false;
     public initTime = Date.now();
     private rtcMonitor: RTCMonitor | null = null;
+    private mediaKeepAliveTimeout: ReturnType<typeof setTimeout> | null = null;
+    private keyFrameRequestsSent = 0;
+    private keyFrameRequestsReceived = 0;
     private av1Codec: RTCRtpCodecCapability | null = null;
+    private qualityDegraded = false;
 
     constructor(config: CallsClientConfig) {
         logDebug('creating new calls client', JSON.stringify(config));
@@ -314,7 +321,96 @@ export default class CallsClient extends EventEmitter {
         gatherStats();
     }
 
-    public async init(joinData: CallsClientJoinData) {
+    // reportICEFailure sends the type of the local candidate that was being
+    // checked when the ICE connection failed.
+    private async reportICEFailure() {
+        if (!this.ws || !this.peer) {
+            return;
+        }
+
+        let candidateType = 'unknown';
+        try {
+            const stats = parseRTCStats(await this.peer.getStats()).iceStats;
+
+            // The pair that got the furthest in the checks is the most relevant.
+            for (const state of ['succeeded', 'in-progress', 'failed', 'waiting']) {
+                const pair = stats[state]?.find((p) => p.local);
+                if (pair?.local) {
+                    candidateType = pair.local.candidateType;
+                    break;
+                }
+            }
+        } catch (err) {
+            logErr('failed to parse ICE stats', err);
+        }
+
+        logWarn('ICE connection failed', candidateType);
+
+        this.ws?.send('metric', {
+            metric_name: 'client_ice_failure',
+            data: JSON.stringify({
+                candidate_type: candidateType,
+                platform: isDesktopApp() ? 'desktop' : 'web',
+            }),
+        });
+    }
+
+    // sendMediaKeepAlives periodically reports the bytes received over the
+    // selected ICE candidate pair so the server can detect a dead media path
+    // while the WebSocket connection is still alive.
+    private sendMediaKeepAlives() {
+        const sendKeepAlive = async () => {
+            if (!this.ws || !this.peer) {
+                return;
+            }
+
+            try {
+                let bytesReceived = 0;
+                let keyFrameRequestsSent = 0;
+                let keyFrameRequestsReceived = 0;
+                (await this.peer.getStats()).forEach((report) => {
+                    if (report.type === 'candidate-pair' && report.nominated && report.state === 'succeeded') {
+                        bytesReceived += report.bytesReceived || 0;
+                    } else if (report.type === 'inbound-rtp' && report.kind === 'video') {
+                        keyFrameRequestsSent += (report.pliCount || 0) + (report.firCount || 0);
+                    } else if (report.type === 'outbound-rtp' && report.kind === 'video') {
+                        keyFrameRequestsReceived += (report.pliCount || 0) + (report.firCount || 0);
+                    }
+                });
+
+                this.ws.send('metric', {
+                    metric_name: 'client_media_keepalive',
+                    data: JSON.stringify({
+                        bytes_received: bytesReceived,
+                    }),
+                });
+
+                // Key frame requests are reported as deltas since the last
+                // report. Counters reset when tracks are renegotiated.
+                const sent = Math.max(keyFrameRequestsSent - this.keyFrameRequestsSent, 0);
+                const received = Math.max(keyFrameRequestsReceived - this.keyFrameRequestsReceived, 0);
+                this.keyFrameRequestsSent = keyFrameRequestsSent;
+                this.keyFrameRequestsReceived = keyFrameRequestsReceived;
+                if (sent > 0 || received > 0) {
+                    this.ws.send('metric', {
+                        metric_name: 'client_key_frame_requests',
+                        data: JSON.stringify({
+                            sent,
+                            received,
+                        }),
+                    });
+                }
+            } catch (err) {
+                logErr('failed to send media keepalive', err);
+            }
+
+            this.mediaKeepAliveTimeout = setTimeout(sendKeepAlive, mediaKeepAliveInterval);
+        };
+
+        this.mediaKeepAliveTimeout = setTimeout(sendKeepAlive, mediaKeepAliveInterval);
+    }
+
+    public async init(joinData: CallsClientJoinData & {tag?: string, preset?: string, metadata?: Record<string, string>, iceConnectionReports?: boolean}) {
         this.channelID = joinData.channelID;
 
         if (this.config.enableAV1 && !this.config.simulcast) {
@@ -332,6 +428,9 @@ export default class CallsClient extends EventEmitter {
             joinData.dcSignaling = true;
         }
 
+        // We report the selected candidate pair once connected (see collectICEStats).
+        joinData.iceConnectionReports = true;
+
         if (!window.isSecureContext) {
             throw insecureContextErr;
         }
@@ -389,8 +488,10 @@ export default class CallsClient extends EventEmitter {
         ws.on('join', async () => {
             logDebug('join ack received, initializing connection');
 
-            const peer = new RTCPeer({
+            // The config is passed through to the underlying RTCPeerConnection.
+            const peerConfig: ConstructorParameters<typeof RTCPeer>[0] & Pick<RTCConfiguration, 'iceTransportPolicy'> = {
                 iceServers: this.config.iceServers || [],
+                iceTransportPolicy: this.config.iceTransportPolicy,
                 logger: {
                     logDebug,
                     logErr,
@@ -400,11 +501,13 @@ export default class CallsClient extends EventEmitter {
                 simulcast: this.config.simulcast,
                 dcSignaling: this.config.dcSignaling,
                 dcLocking: this.config.dcLocking,
-            });
+            };
+            const peer = new RTCPeer(peerConfig);
 
             this.peer = peer;
 
             this.collectICEStats();
+            this.sendMediaKeepAlives();
 
             this.rtcMonitor = new RTCMonitor({
                 peer,
@@ -437,8 +540,15 @@ export default class CallsClient extends EventEmitter {
                 });
             });
 
-            peer.on('error', (err) => {
+            peer.on('error', async (err) => {
                 logErr('peer error', err);
+
+                // Failing before ever connecting is most likely caused by the
+                // network, so we let the server know to help diagnosing it.
+                if (!this.connected && !this.closed) {
+                    await this.reportICEFailure();
+                }
+
                 if (!this.closed) {
                     this.disconnect(err === rtcPeerTimeoutErr.message ? rtcPeerTimeoutErr : rtcPeerErr);
                 }
@@ -588,6 +698,11 @@ export default class CallsClient extends EventEmitter {
 
         this.rtcMonitor?.stop();
 
+        if (this.mediaKeepAliveTimeout) {
+            clearTimeout(this.mediaKeepAliveTimeout);
+            this.mediaKeepAliveTimeout = null;
+        }
+
         this.closed = true;
         if (this.peer) {
             this.getStats().then((stats) => {
@@ -679,6 +794,37 @@ export default class CallsClient extends EventEmitter {
         }
     }
 
+    // setQualityDegraded reduces the quality of the shared screen, lowering the
+    // load on the server while it's under pressure.
+    public async setQualityDegraded(degraded: boolean) {
+        if (this.qualityDegraded === degraded) {
+            return;
+        }
+
+        logInfo(`${degraded ? 'reducing' : 'restoring'} quality due to server load`);
+
+        this.qualityDegraded = degraded;
+
+        await this.applyScreenConstraints();
+    }
+
+    private async applyScreenConstraints() {
+        if (!this.localScreenTrack) {
+            return;
+        }
+
+        const constraints: MediaTrackConstraints = this.qualityDegraded ? {
+            frameRate: {max: SERVER_LOAD_SCREEN_MAX_FRAME_RATE},
+            height: {max: SERVER_LOAD_SCREEN_MAX_HEIGHT},
+        } : {};
+
+        try {
+            await this.localScreenTrack.applyConstraints(constraints);
+        } catch (err) {
+            logErr('failed to apply screen constraints', err);
+        }
+    }
+
     public getLocalScreenStream(): MediaStream|null {
         if (!this.localScreenTrack) {
             return null;
@@ -710,6 +856,9 @@ export default class CallsClient extends EventEmitter {
 
         const screenTrack = screenStream.getVideoTracks()[0];
         this.localScreenTrack = screenTrack;
+        if (this.qualityDegraded) {
+            await this.applyScreenConstraints();
+        }
 
         const screenAudioTrack = screenStream.getAudioTracks()[0];
 
@@ -804,6 +953,18 @@ export default class CallsClient extends EventEmitter {
         });
     }
 
+    public sendChatMessage(message: string) {
+        this.ws?.send('chat', {
+            data: JSON.stringify({message}),
+        });
+    }
+
+    public addRecordingMarker(name: string) {
+        this.ws?.send('recording_marker', {
+            data: JSON.stringify({name}),
+        });
+    }
+
     public async getStats(): Promise<CallsClientStats | null> {
         if (!this.peer) {
             throw new Error('not connected');
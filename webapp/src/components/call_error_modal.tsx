@@ -36,6 +36,14 @@ export const removedMsgTitle = defineMessage({defaultMessage: 'You were removed
 export const removedMsg = defineMessage({defaultMessage: 'The host removed you from the call.'});
 export const removedDismiss = defineMessage({defaultMessage: 'Dismiss'});
 
+export const sessionReplacedMsg = 'session-replaced';
+
+export const channelAccessLostMsg = 'channel-access-lost';
+
+// Matching the errors returned by the server on join.
+export const archivedChannelErrMsg = 'calls are not available in archived channels';
+export const readOnlyChannelErrMsg = 'calls are not allowed in read-only channels';
+
 export const CallErrorModal = (props: Props) => {
     const {formatMessage} = useIntl();
 
@@ -194,6 +202,44 @@ export const CallErrorModal = (props: Props) => {
         );
         confirmMsg = formatMessage(removedDismiss);
         break;
+    case channelAccessLostMsg:
+        headerMsg = (
+            <>{formatMessage(removedMsgTitle)}</>
+        );
+        msg = (
+            <span>
+                {formatMessage({defaultMessage: 'The channel was made private and you no longer have access to it.'})}
+            </span>
+        );
+        confirmMsg = formatMessage(removedDismiss);
+        break;
+    case sessionReplacedMsg:
+        headerMsg = (
+            <span>{formatMessage({defaultMessage: 'You joined from another device'})}</span>
+        );
+        msg = (
+            <span>
+                {formatMessage({defaultMessage: 'You\'ve joined this call from another device, and have been disconnected here.'})}
+            </span>
+        );
+        confirmMsg = formatMessage(removedDismiss);
+        break;
+    case archivedChannelErrMsg:
+        headerMsg = (
+            <span>{formatMessage({defaultMessage: 'Unable to join the call'})}</span>
+        );
+        msg = (
+            <span>{formatMessage({defaultMessage: 'Calls are not available in archived channels.'})}</span>
+        );
+        break;
+    case readOnlyChannelErrMsg:
+        headerMsg = (
+            <span>{formatMessage({defaultMessage: 'Unable to join the call'})}</span>
+        );
+        msg = (
+            <span>{formatMessage({defaultMessage: 'Calls are not allowed in read-only channels.'})}</span>
+        );
+        break;
     }
 
     return (
@@ -111,6 +111,7 @@ interface Props {
     callHostChangeAt: number,
     callRecording?: CallJobReduxState,
     isRecording: boolean,
+    qualityDegraded: boolean,
     screenSharingSession?: UserSessionState,
     show: boolean,
     showExpandedView: () => void,
@@ -639,6 +640,18 @@ export default class CallWidget extends React.PureComponent<Props, State> {
         if (this.screenPlayer && this.state.screenStream !== prevState.screenStream) {
             this.screenPlayer.srcObject = this.state.screenStream;
         }
+
+        if (this.props.qualityDegraded !== prevProps.qualityDegraded) {
+            this.setState({
+                alerts: {
+                    ...this.state.alerts,
+                    serverLoad: {
+                        active: this.props.qualityDegraded,
+                        show: this.props.qualityDegraded,
+                    },
+                },
+            });
+        }
     }
 
     private getGlobalWidgetBounds = () => {
@@ -1779,15 +1792,17 @@ export default class CallWidget extends React.PureComponent<Props, State> {
             return null;
         }
 
+        const isPaused = Boolean(this.props.callRecording?.paused_at);
+
         return (
             <React.Fragment>
                 <Badge
                     id={'calls-recording-badge'}
-                    text={'REC'}
+                    text={isPaused ? 'PAUSED' : 'REC'}
                     textSize={11}
                     gap={2}
                     icon={(<RecordCircleIcon style={{width: '11px', height: '11px'}}/>)}
-                    color={hasRecStarted ? '#D24B4E' : 'rgb(var(--center-channel-color-rgb))'}
+                    color={hasRecStarted && !isPaused ? '#D24B4E' : 'rgb(var(--center-channel-color-rgb))'}
                     loading={!hasRecStarted}
                 />
                 <div style={{margin: '0 2px 0 4px'}}>{untranslatable('•')}</div>
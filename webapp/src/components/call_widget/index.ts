@@ -26,6 +26,7 @@ import {
     hostChangeAtForCurrentCall,
     hostControlNoticesForCurrentCall,
     hostIDForCurrentCall,
+    isCallQualityDegradedForCurrentCall,
     isRecordingInCurrentCall,
     profilesInCurrentCallMap,
     recentlyJoinedUsersInCurrentCall,
@@ -78,6 +79,7 @@ const mapStateToProps = (state: GlobalState) => {
         callHostChangeAt: hostChangeAtForCurrentCall(state),
         callRecording: recordingForCurrentCall(state),
         isRecording: isRecordingInCurrentCall(state),
+        qualityDegraded: isCallQualityDegradedForCurrentCall(state),
         screenSharingSession,
         allowScreenSharing: allowScreenSharing(state),
         show: !expandedView(state),
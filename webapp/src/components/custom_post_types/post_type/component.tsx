@@ -37,6 +37,7 @@ interface Props {
     profiles: UserProfile[],
     isCloudPaid: boolean,
     maxParticipants: number,
+    atCapacity?: boolean,
     militaryTime: boolean,
     compactDisplay: boolean,
     isRHS: boolean,
@@ -49,6 +50,7 @@ const PostType = ({
     profiles,
     isCloudPaid,
     maxParticipants,
+    atCapacity,
     militaryTime,
     compactDisplay,
     isRHS,
@@ -143,7 +145,7 @@ const PostType = ({
     );
 
     // Note: don't use isLimitRestricted because that uses current channel, and this post could be in RHS
-    if (maxParticipants > 0 && profiles.length >= maxParticipants) {
+    if (atCapacity ?? (maxParticipants > 0 && profiles.length >= maxParticipants)) {
         joinButton = (
             <OverlayTrigger
                 placement='top'
@@ -12,6 +12,7 @@ import {MESSAGE_DISPLAY, MESSAGE_DISPLAY_COMPACT, MESSAGE_DISPLAY_DEFAULT} from
 import {
     channelIDForCurrentCall,
     hostIDForCallInChannel,
+    isCallAtCapacity,
     isCloudProfessionalOrEnterpriseorEnterpriseAdvanceOrTrial,
     maxParticipants,
     profilesInCallInChannel,
@@ -29,6 +30,7 @@ const mapStateToProps = (state: GlobalState, ownProps: OwnProps) => {
         profiles: profilesInCallInChannel(state, ownProps.post.channel_id),
         isCloudPaid: isCloudProfessionalOrEnterpriseorEnterpriseAdvanceOrTrial(state),
         maxParticipants: maxParticipants(state),
+        atCapacity: isCallAtCapacity(state, ownProps.post.channel_id),
         militaryTime: getBool(state, Preferences.CATEGORY_DISPLAY_SETTINGS, Preferences.USE_MILITARY_TIME, false),
         compactDisplay: get(state, Preferences.CATEGORY_DISPLAY_SETTINGS, MESSAGE_DISPLAY, MESSAGE_DISPLAY_DEFAULT) === MESSAGE_DISPLAY_COMPACT,
         isHost: hostIDForCallInChannel(state, ownProps.post.channel_id) === getCurrentUserId(state),
@@ -98,6 +98,7 @@ interface Props extends RouteComponentProps {
     callHostChangeAt: number,
     callRecording?: CallJobReduxState,
     isRecording: boolean,
+    qualityDegraded: boolean,
     hideExpandedView: () => void,
     showScreenSourceModal: () => void,
     selectRhsPost?: (postID: string) => void,
@@ -105,6 +106,8 @@ interface Props extends RouteComponentProps {
     closeRhs?: () => void,
     isRhsOpen?: boolean,
     screenSharingSession?: UserSessionState,
+    screenSharingSessionIDs: string[],
+    maxScreenShares: number,
     channel?: Channel,
     channelTeam?: Team,
     channelDisplayName: string;
@@ -480,12 +483,13 @@ export default class ExpandedView extends React.PureComponent<Props, State> {
             return;
         }
         const callsClient = getCallsClient();
-        if (this.props.screenSharingSession && this.props.screenSharingSession?.session_id === this.props.currentSession?.session_id) {
+        const isSharing = this.props.screenSharingSessionIDs.includes(this.props.currentSession?.session_id || '');
+        if (isSharing) {
             callsClient?.unshareScreen();
             this.setState({
                 screenStream: null,
             });
-        } else if (!this.props.screenSharingSession) {
+        } else if (this.props.screenSharingSessionIDs.length < this.props.maxScreenShares) {
             if (window.desktopAPI?.openScreenShareModal) {
                 logDebug('desktopAPI.openScreenShareModal');
                 window.desktopAPI.openScreenShareModal();
@@ -541,6 +545,18 @@ export default class ExpandedView extends React.PureComponent<Props, State> {
             this.style = this.genStyle();
         }
 
+        if (this.props.qualityDegraded !== prevProps.qualityDegraded) {
+            this.setState({
+                alerts: {
+                    ...this.state.alerts,
+                    serverLoad: {
+                        active: this.props.qualityDegraded,
+                        show: this.props.qualityDegraded,
+                    },
+                },
+            });
+        }
+
         if (window.opener) {
             if (document.title.indexOf('Call') === -1 && this.props.channel) {
                 if (isDMChannel(this.props.channel) && this.props.connectedDMUser) {
@@ -924,10 +940,12 @@ export default class ExpandedView extends React.PureComponent<Props, State> {
             return null;
         }
 
+        const isPaused = Boolean(this.props.callRecording?.paused_at);
+
         const badge = (
             <Badge
                 id={'calls-recording-badge'}
-                text={'REC'}
+                text={isPaused ? 'PAUSED' : 'REC'}
                 textSize={12}
                 lineHeight={16}
                 gap={4}
@@ -935,7 +953,7 @@ export default class ExpandedView extends React.PureComponent<Props, State> {
                 padding={'6px 8px'}
                 icon={(<RecordCircleIcon style={{width: '12px', height: '12px'}}/>)}
                 hoverIcon={(<RecordSquareIcon style={{width: '12px', height: '12px'}}/>)}
-                bgColor={hasRecStarted ? '#D24B4E' : 'rgba(221, 223, 228, 0.04)'}
+                bgColor={hasRecStarted && !isPaused ? '#D24B4E' : 'rgba(221, 223, 228, 0.04)'}
                 loading={!hasRecStarted}
             />
         );
@@ -997,8 +1015,7 @@ export default class ExpandedView extends React.PureComponent<Props, State> {
             muteTooltipSubtext = formatMessage(CallAlertConfigs.missingAudioInputPermissions.tooltipSubtext!);
         }
 
-        const sharingID = this.props.screenSharingSession?.session_id;
-        const isSharing = sharingID === this.props.currentSession?.session_id;
+        const isSharing = this.props.screenSharingSessionIDs.includes(this.props.currentSession?.session_id || '');
 
         let shareScreenTooltipText = isSharing ? formatMessage({defaultMessage: 'Stop presenting'}) : formatMessage({defaultMessage: 'Start presenting'});
         if (noScreenPermissions) {
@@ -1183,7 +1200,7 @@ export default class ExpandedView extends React.PureComponent<Props, State> {
                                         />
                                     }
                                     unavailable={noScreenPermissions}
-                                    disabled={Boolean(sharingID) && !isSharing}
+                                    disabled={!isSharing && this.props.screenSharingSessionIDs.length >= this.props.maxScreenShares}
                                 />
                             }
 
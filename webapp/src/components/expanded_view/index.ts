@@ -23,12 +23,15 @@ import {
     getChannelUrlAndDisplayName,
     hostChangeAtForCurrentCall,
     hostIDForCurrentCall,
+    isCallQualityDegradedForCurrentCall,
     isRecordingInCurrentCall,
+    maxScreenShares,
     profilesInCurrentCallMap,
     recordingForCurrentCall,
     recordingMaxDuration,
     recordingsEnabled,
     screenSharingSessionForCurrentCall,
+    screenSharingSessionIDsForCurrentCall,
     sessionForCurrentCall,
     sessionsInCurrentCall,
     sessionsInCurrentCallMap,
@@ -76,7 +79,10 @@ const mapStateToProps = (state: GlobalState) => {
         callHostChangeAt: hostChangeAtForCurrentCall(state),
         callRecording: recordingForCurrentCall(state),
         isRecording: isRecordingInCurrentCall(state),
+        qualityDegraded: isCallQualityDegradedForCurrentCall(state),
         screenSharingSession,
+        screenSharingSessionIDs: screenSharingSessionIDsForCurrentCall(state),
+        maxScreenShares: maxScreenShares(state),
         channel,
         channelTeam,
         channelDisplayName,
@@ -91,6 +91,22 @@ export const HostNotices = ({onWidget = false}: Props) => {
                             </Text>
                         </Notice>
                     );
+                case HostControlNoticeType.NoiseMuted:
+                    return (
+                        <Notice
+                            key={n.noticeID}
+                            data-testid={'notice-noise-muted'}
+                            $onWidget={onWidget}
+                        >
+                            <StyledCompassIcon
+                                icon={'microphone-off'}
+                                $onWidget={onWidget}
+                            />
+                            <Text $onWidget={onWidget}>
+                                <FormattedMessage defaultMessage={'You were muted due to continuous background noise. Unmute when ready.'}/>
+                            </Text>
+                        </Notice>
+                    );
                 default:
                     return null;
                 }
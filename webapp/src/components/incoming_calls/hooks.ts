@@ -1,7 +1,7 @@
 // Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
 // See LICENSE.txt for license information.
 
-import {ChannelMembership} from '@mattermost/types/channels';
+import {Channel, ChannelMembership} from '@mattermost/types/channels';
 import {GlobalState} from '@mattermost/types/store';
 import {UserProfile} from '@mattermost/types/users';
 import {getProfilesInChannel} from 'mattermost-redux/actions/users';
@@ -22,6 +22,7 @@ import {navigateToURL} from 'src/browser_routing';
 import {DEFAULT_RING_SOUND} from 'src/constants';
 import {logDebug, logWarn} from 'src/log';
 import {
+    callNotificationPreferences,
     channelIDForCurrentCall,
     currentlyRinging,
     didNotifyForCall,
@@ -30,12 +31,14 @@ import {
     ringingForCall,
     teamForCurrentCall,
 } from 'src/selectors';
-import {ChannelType, IncomingCallNotification, UserStatuses} from 'src/types/types';
+import {CallNotificationPreferences, ChannelType, IncomingCallNotification, UserStatuses} from 'src/types/types';
 import {
     desktopGTE,
     getCallsClient,
     getChannelURL,
     isDesktopApp,
+    isDMChannel,
+    isGMChannel,
     notificationsStopRinging,
     sendDesktopEvent,
     shouldRenderDesktopWidget,
@@ -133,6 +136,23 @@ const getDesktopNotificationFromUser = (user: UserProfile) => {
     return !user.notify_props || user.notify_props.desktop !== NotificationLevel.NONE;
 };
 
+// shouldNotifyForCall mirrors the server side logic deciding whether a user
+// should be notified of a call starting in the given channel.
+const shouldNotifyForCall = (prefs: CallNotificationPreferences, channelID: string, channel?: Channel) => {
+    if (prefs.muted_channel_ids.includes(channelID)) {
+        return false;
+    }
+
+    switch (prefs.channels) {
+    case 'none':
+        return false;
+    case 'direct':
+        return isDMChannel(channel) || isGMChannel(channel);
+    default:
+        return true;
+    }
+};
+
 const getDesktopNotificationFromChannel = (member: ChannelMembership | null | undefined) => {
     return !member?.notify_props?.desktop || member.notify_props.desktop !== NotificationLevel.NONE;
 };
@@ -141,8 +161,11 @@ const getDesktopNotificationFromChannel = (member: ChannelMembership | null | un
 const useNotificationSettings = (channelID: string, user: UserProfile) => {
     const status = useSelector(getStatusForCurrentUser);
     const member = useSelector((state: GlobalState) => getMyChannelMember(state, channelID));
-    const muted = !member || isChannelMuted(member) || status === UserStatuses.DND || status === UserStatuses.OUT_OF_OFFICE;
-    const ring = !muted && getRingingFromUser(user);
+    const channel = useSelector((state: GlobalState) => getChannel(state, channelID));
+    const prefs = useSelector(callNotificationPreferences);
+    const muted = !member || isChannelMuted(member) || status === UserStatuses.DND || status === UserStatuses.OUT_OF_OFFICE ||
+        !shouldNotifyForCall(prefs, channelID, channel);
+    const ring = !muted && prefs.mode === 'ring' && getRingingFromUser(user);
     const desktopSoundEnabled = getDesktopSoundFromChannelMemberAndUser(member, user);
     const desktopNotificationEnabledInChannel = getDesktopNotificationFromChannel(member);
     const desktopNotificationEnabledGlobally = getDesktopNotificationFromUser(user);
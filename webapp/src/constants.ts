@@ -17,6 +17,10 @@ export const LIVE_CAPTION_TIMEOUT = 5000;
 export const HOST_CONTROL_NOTICE_TIMEOUT = 5000;
 export const DEGRADED_CALL_QUALITY_ALERT_WAIT = 20000;
 
+// Limits applied to the shared screen while the server is under load.
+export const SERVER_LOAD_SCREEN_MAX_FRAME_RATE = 5;
+export const SERVER_LOAD_SCREEN_MAX_HEIGHT = 720;
+
 // From mattermost-webapp/webapp/channels/src/utils/constants.tsx, importing causes tsc to throw fits.
 export const MESSAGE_DISPLAY = 'message_display';
 export const MESSAGE_DISPLAY_COMPACT = 'compact';
@@ -70,6 +74,12 @@ export const CallAlertConfigs: { [key: string]: CallAlertConfig } = {
         bannerText: defineMessage({defaultMessage: 'The audio output device has changed to <i>{deviceLabel}</i>.'}),
         dismissable: true,
     },
+    serverLoad: {
+        type: CallAlertType.Info,
+        icon: 'information-outline',
+        bannerText: defineMessage({defaultMessage: 'Call quality is reduced due to server load.'}),
+        dismissable: true,
+    },
 };
 
 export const CallRecordingDisclaimerStrings: {[key: string]: {[key: string]: MessageDescriptor}} = {
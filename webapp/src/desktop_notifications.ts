@@ -7,7 +7,7 @@ import {getCurrentUserId} from 'mattermost-redux/selectors/entities/users';
 import {CALL_START_POST_TYPE} from 'src/constants';
 import {
     channelIDForCurrentCall,
-    ringingEnabled,
+    ringingEnabledInChannel,
     threadIDForCurrentCall,
 } from 'src/selectors';
 import {DesktopNotificationArgs, Store} from 'src/types/mattermost-webapp';
@@ -32,7 +32,7 @@ export function desktopNotificationHandler(
         // @ts-ignore our imported webapp types are old
         if (post.type === CALL_START_POST_TYPE &&
             isDmGmChannel(channel) &&
-            ringingEnabled(store.getState())) {
+            ringingEnabledInChannel(store.getState(), channel)) {
             // e2eNotificationsRejected is added when running the e2e tests
             if (window.e2eDesktopNotificationsRejected) {
                 window.e2eDesktopNotificationsRejected.push(args);
@@ -24,6 +24,7 @@ import {
     displayCallErrorModal,
     displayCallsTestModeUser,
     displayFreeTrial,
+    getCallNotificationPreferences,
     getCallsConfig,
     getCallsConfigEnvOverrides,
     getCallsStats,
@@ -99,10 +100,11 @@ import {CALL_RECORDING_POST_TYPE, CALL_START_POST_TYPE, CALL_TRANSCRIPTION_POST_
 import {desktopNotificationHandler} from 'src/desktop_notifications';
 import RestClient from 'src/rest_client';
 import slashCommandsHandler from 'src/slash_commands';
-import {CallActions, CurrentCallData, CurrentCallDataDefault, DesktopMessageType} from 'src/types/types';
+import {CallActions, CallCapacityData, CurrentCallData, CurrentCallDataDefault, DesktopMessageType} from 'src/types/types';
 import {modals} from 'src/webapp_globals';
 
 import {
+    CALL_CAPACITY,
     CALL_STATE,
     DISMISS_CALL,
     RECEIVED_CHANNEL_STATE,
@@ -140,9 +142,10 @@ import {
     callsVersionInfo,
     channelHasCall,
     channelIDForCurrentCall,
-    defaultEnabled,
+    defaultEnabledInChannel,
     hasPermissionsToEnableCalls,
     iceServers,
+    iceTransportPolicy,
     isCloudStarter,
     isLimitRestricted,
     needsTURNCredentials,
@@ -166,9 +169,14 @@ import {
     shouldRenderDesktopWidget,
 } from './utils';
 import {
+    handleCallChatMessage,
     handleCallEnd,
     handleCallHostChanged,
     handleCallJobState,
+    handleCallMoved,
+    handleCallNotificationPreferences,
+    handleCallQualityDegraded,
+    handleCallSpeakerLabels,
     handleCallStart,
     handleCallState,
     handleCaption,
@@ -176,6 +184,8 @@ import {
     handleHostMute,
     handleHostRemoved,
     handleHostScreenOff,
+    handleNoiseMute,
+    handleSessionReplaced,
     handleUserDismissedNotification,
     handleUserJoined,
     handleUserLeft,
@@ -252,6 +262,10 @@ export default class Plugin {
             handleCallEnd(store, ev);
         });
 
+        registry.registerWebSocketEventHandler(`custom_${pluginId}_call_moved`, (ev) => {
+            handleCallMoved(store, ev);
+        });
+
         registry.registerWebSocketEventHandler(`custom_${pluginId}_user_screen_on`, (ev) => {
             handleUserScreenOn(store, ev);
         });
@@ -296,10 +310,18 @@ export default class Plugin {
             handleCaption(store, ev);
         });
 
+        registry.registerWebSocketEventHandler(`custom_${pluginId}_call_chat_message`, (ev) => {
+            handleCallChatMessage(store, ev);
+        });
+
         registry.registerWebSocketEventHandler(`custom_${pluginId}_host_mute`, (ev) => {
             handleHostMute(store, ev);
         });
 
+        registry.registerWebSocketEventHandler(`custom_${pluginId}_noise_mute`, (ev) => {
+            handleNoiseMute(store, ev);
+        });
+
         registry.registerWebSocketEventHandler(`custom_${pluginId}_host_screen_off`, (ev) => {
             handleHostScreenOff(store, ev);
         });
@@ -311,6 +333,22 @@ export default class Plugin {
         registry.registerWebSocketEventHandler(`custom_${pluginId}_host_removed`, (ev) => {
             handleHostRemoved(store, ev);
         });
+
+        registry.registerWebSocketEventHandler(`custom_${pluginId}_session_replaced`, (ev) => {
+            handleSessionReplaced(ev);
+        });
+
+        registry.registerWebSocketEventHandler(`custom_${pluginId}_call_notification_preferences`, (ev) => {
+            handleCallNotificationPreferences(store, ev);
+        });
+
+        registry.registerWebSocketEventHandler(`custom_${pluginId}_call_quality_degraded`, (ev) => {
+            handleCallQualityDegraded(store, ev);
+        });
+
+        registry.registerWebSocketEventHandler(`custom_${pluginId}_call_speaker_labels`, (ev) => {
+            handleCallSpeakerLabels(store, ev);
+        });
     }
 
     private initialize(registry: PluginRegistry, store: Store) {
@@ -405,9 +443,9 @@ export default class Plugin {
             return desktopNotificationHandler(store, post, msgProps, channel, args);
         });
 
-        const connectToCall = async (channelId: string, teamId?: string, title?: string, rootId?: string) => {
+        const connectToCall = async (channelId: string, teamId?: string, title?: string, rootId?: string, tag?: string, preset?: string) => {
             if (!channelIDForCurrentCall(store.getState())) {
-                connectCall(channelId, title, rootId);
+                connectCall(channelId, title, rootId, tag, preset);
 
                 // following the thread only on join. On call start
                 // this is done in the call_start ws event handler.
@@ -419,7 +457,7 @@ export default class Plugin {
             }
         };
 
-        const joinCall = async (channelId: string, teamId?: string, title?: string, rootId?: string) => {
+        const joinCall = async (channelId: string, teamId?: string, title?: string, rootId?: string, tag?: string, preset?: string) => {
             // Anyone can join a call already in progress.
             // If explicitly enabled, everyone can start calls.
             // In LiveMode (DefaultEnabled=true):
@@ -433,7 +471,7 @@ export default class Plugin {
             const explicitlyDisabled = callsExplicitlyDisabled(store.getState(), channelId);
 
             // Note: not super happy with using explicitlyDisabled both here and below, but wanted to keep the "able to start" logic confined to one place.
-            if (channelHasCall(store.getState(), channelId) || explicitlyEnabled || (!explicitlyDisabled && defaultEnabled(store.getState()))) {
+            if (channelHasCall(store.getState(), channelId) || explicitlyEnabled || (!explicitlyDisabled && defaultEnabledInChannel(store.getState(), channelId))) {
                 if (isLimitRestricted(store.getState())) {
                     if (isCloudStarter(store.getState())) {
                         store.dispatch(displayFreeTrial());
@@ -444,7 +482,7 @@ export default class Plugin {
                     return;
                 }
 
-                await connectToCall(channelId, teamId, title, rootId);
+                await connectToCall(channelId, teamId, title, rootId, tag, preset);
                 return;
             }
 
@@ -456,7 +494,7 @@ export default class Plugin {
             // We are in TestMode (DefaultEnabled=false)
             if (isCurrentUserSystemAdmin(store.getState())) {
                 // Rely on server side to send ephemeral message.
-                await connectToCall(channelId, teamId, title, rootId);
+                await connectToCall(channelId, teamId, title, rootId, tag, preset);
             } else {
                 store.dispatch(displayCallsTestModeUser());
             }
@@ -637,7 +675,7 @@ export default class Plugin {
             }));
         }
 
-        const connectCall = async (channelID: string, title?: string, rootId?: string) => {
+        const connectCall = async (channelID: string, title?: string, rootId?: string, tag?: string, preset?: string) => {
             // Desktop handler
             const payload = {
                 callID: channelID,
@@ -668,19 +706,27 @@ export default class Plugin {
                 }
 
                 const state = store.getState();
-                const iceConfigs = [...iceServers(state)];
-                if (needsTURNCredentials(state)) {
-                    logDebug('turn credentials needed');
-                    try {
-                        iceConfigs.push(...await RestClient.fetch<RTCIceServer[]>(`${getPluginPath()}/turn-credentials`, {method: 'get'}));
-                    } catch (err) {
-                        logErr(err);
+                let iceConfigs: RTCIceServer[];
+                try {
+                    // ICE servers can be overridden per channel, this also includes any needed TURN credentials.
+                    iceConfigs = await RestClient.fetch<RTCIceServer[]>(`${getPluginPath()}/calls/${channelID}/ice-servers`, {method: 'get'});
+                } catch (err) {
+                    logErr(err);
+                    iceConfigs = [...iceServers(state)];
+                    if (needsTURNCredentials(state)) {
+                        logDebug('turn credentials needed');
+                        try {
+                            iceConfigs.push(...await RestClient.fetch<RTCIceServer[]>(`${getPluginPath()}/turn-credentials`, {method: 'get'}));
+                        } catch (turnErr) {
+                            logErr(turnErr);
+                        }
                     }
                 }
 
                 window.callsClient = new CallsClient({
                     wsURL: getWSConnectionURL(getConfig(state)),
                     iceServers: iceConfigs,
+                    iceTransportPolicy: iceTransportPolicy(state),
                     simulcast: callsConfig(state).EnableSimulcast,
                     enableAV1: callsConfig(state).EnableAV1,
                     dcSignaling: callsConfig(state).EnableDCSignaling,
@@ -790,6 +836,8 @@ export default class Plugin {
                     channelID,
                     title,
                     threadID: rootId,
+                    tag,
+                    preset,
                 }).catch((err: Error) => {
                     store.dispatch(setClientConnecting(false));
 
@@ -883,7 +931,7 @@ export default class Plugin {
                         },
                     });
 
-                    const call = data[i].call;
+                    const call = data[i].call as (NonNullable<CallChannelState['call']> & CallCapacityData) | undefined;
 
                     if (!call || !call.sessions?.length) {
                         continue;
@@ -911,6 +959,14 @@ export default class Plugin {
                             },
                         });
 
+                        actions.push({
+                            type: CALL_CAPACITY,
+                            data: {
+                                channelID: data[i].channel_id,
+                                atCapacity: Boolean(call.at_capacity),
+                            },
+                        });
+
                         if (ringingEnabled(store.getState()) && data[i].call) {
                             // dismissedNotification is populated after the actions array has been batched, so manually check:
                             const dismissed = call.dismissed_notification;
@@ -964,7 +1020,11 @@ export default class Plugin {
 
             unsubscribeActivateListener();
 
-            const requests = [store.dispatch(getCallsConfig()), store.dispatch(getCallsVersionInfo())];
+            const requests = [
+                store.dispatch(getCallsConfig()),
+                store.dispatch(getCallsVersionInfo()),
+                store.dispatch(getCallNotificationPreferences()),
+            ];
             if (isCurrentUserSystemAdmin(store.getState())) {
                 requests.push(store.dispatch(getCallsConfigEnvOverrides()));
             }
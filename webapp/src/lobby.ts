@@ -0,0 +1,143 @@
+// Copyright (c) 2020-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+import {EventEmitter} from 'events';
+
+import {logDebug, logErr} from './log';
+import {pluginId} from './manifest';
+import {WebSocketClient, WebSocketError} from './websocket';
+
+const lobbyPingIntervalMs = 1000;
+
+export type LobbyConnectionStats = {
+    rtt: number,
+    jitter: number,
+    lossRate: number,
+};
+
+// CallsLobby lets a user test their connection and devices before joining a
+// call. The server replies to connection probes so that round trip time and
+// jitter can be measured. Media is looped back locally since the lobby is never
+// part of the call.
+export default class CallsLobby extends EventEmitter {
+    private readonly channelID: string;
+    private ws: WebSocketClient | null = null;
+    private eventPrefix: string = 'custom_' + pluginId;
+    private pingInterval: ReturnType<typeof setInterval> | null = null;
+    private pingSeq = 0;
+    private pongs = 0;
+    private rtt = 0;
+    private jitter = 0;
+    private audioCtx: AudioContext | null = null;
+    private stream: MediaStream | null = null;
+
+    constructor(channelID: string) {
+        super();
+        this.channelID = channelID;
+    }
+
+    public enter(wsURL: string, authToken?: string) {
+        const ws = new WebSocketClient(wsURL, authToken);
+        this.ws = ws;
+
+        ws.on('open', (_: string, __: string, isReconnect: boolean) => {
+            if (!isReconnect) {
+                ws.send('lobby_enter', {channelID: this.channelID});
+            }
+        });
+
+        ws.on('error', (err: WebSocketError) => {
+            logErr('lobby: ws error', err);
+            this.emit('error', err);
+        });
+
+        ws.on('event', (msg) => {
+            switch (msg.event) {
+            case `${this.eventPrefix}_lobby_ready`:
+                logDebug('lobby: ready', msg.data);
+                this.startPinging();
+                this.emit('ready', msg.data.max_duration_ms);
+                break;
+            case `${this.eventPrefix}_lobby_pong`:
+                this.handlePong(msg.data.ts);
+                break;
+            case `${this.eventPrefix}_lobby_left`:
+                logDebug('lobby: left', msg.data.reason);
+                this.emit('left', msg.data.reason);
+                this.leave();
+                break;
+            }
+        });
+    }
+
+    // testDevices plays back the audio captured from the given input device so
+    // that the user can verify both their microphone and speakers.
+    public async testDevices(deviceId?: string) {
+        this.stopDevicesTest();
+
+        this.stream = await navigator.mediaDevices.getUserMedia({
+            audio: deviceId ? {deviceId: {exact: deviceId}} : true,
+        });
+
+        this.audioCtx = new AudioContext();
+        const source = this.audioCtx.createMediaStreamSource(this.stream);
+        const analyser = this.audioCtx.createAnalyser();
+        source.connect(analyser);
+        analyser.connect(this.audioCtx.destination);
+
+        return analyser;
+    }
+
+    public stopDevicesTest() {
+        this.stream?.getTracks().forEach((track) => track.stop());
+        this.stream = null;
+        this.audioCtx?.close();
+        this.audioCtx = null;
+    }
+
+    public leave() {
+        this.stopDevicesTest();
+
+        if (this.pingInterval) {
+            clearInterval(this.pingInterval);
+            this.pingInterval = null;
+        }
+
+        if (this.ws) {
+            this.ws.send('lobby_leave');
+            this.ws.close();
+            this.ws = null;
+        }
+
+        this.removeAllListeners();
+    }
+
+    private startPinging() {
+        if (this.pingInterval) {
+            return;
+        }
+
+        this.pingInterval = setInterval(() => {
+            this.pingSeq++;
+            this.ws?.send('lobby_ping', {seq: this.pingSeq, ts: performance.now()});
+        }, lobbyPingIntervalMs);
+    }
+
+    private handlePong(ts: number) {
+        const rtt = performance.now() - ts;
+        this.pongs++;
+
+        // Interarrival jitter estimate as per RFC 3550.
+        if (this.pongs > 1) {
+            this.jitter += (Math.abs(rtt - this.rtt) - this.jitter) / 16;
+        }
+        this.rtt = rtt;
+
+        const stats: LobbyConnectionStats = {
+            rtt: this.rtt,
+            jitter: this.jitter,
+            lossRate: this.pingSeq > 0 ? Math.max(0, 1 - (this.pongs / this.pingSeq)) : 0,
+        };
+        this.emit('stats', stats);
+    }
+}
@@ -6,6 +6,10 @@ import {CallJobState, CallsConfig, CallsVersionInfo, LiveCaption, Reaction, User
 import {combineReducers} from 'redux';
 import {MAX_NUM_REACTIONS_IN_REACTION_STREAM} from 'src/constants';
 import {
+    CallChatMessage,
+    CallEndReason,
+    CallNotificationPreferences,
+    CallNotificationPreferencesDefault,
     CallsConfigDefault,
     CallsUserPreferences,
     CallsUserPreferencesDefault,
@@ -19,8 +23,13 @@ import {
 
 import {
     ADD_INCOMING_CALL,
+    CALL_CHAT_MESSAGE,
+    CALL_CAPACITY,
+    CALL_QUALITY_DEGRADED,
+    CALL_SPEAKER_LABELS,
     CALL_END,
     CALL_HOST,
+    CALL_MOVED,
     CALL_LIVE_CAPTIONS_STATE,
     CALL_REC_PROMPT_DISMISSED,
     CALL_RECORDING_STATE,
@@ -39,6 +48,7 @@ import {
     LIVE_CAPTION_TIMEOUT_EVENT,
     LIVE_CAPTIONS_ENABLED,
     LOCAL_SESSION_CLOSE,
+    RECEIVED_CALL_NOTIFICATION_PREFERENCES,
     RECEIVED_CALLS_CONFIG,
     RECEIVED_CALLS_CONFIG_ENV_OVERRIDES,
     RECEIVED_CALLS_USER_PREFERENCES,
@@ -110,12 +120,21 @@ type callEndAction = {
 type callEndData = {
     channelID: string;
     callID: string;
+    reason?: CallEndReason;
+}
+type callMovedAction = {
+    type: string;
+    data: callMovedData;
+}
+type callMovedData = {
+    channelID: string;
+    newChannelID: string;
 }
 
 // clientStateReducer holds the channel and session ID for the call the current user is connected to.
 // This reducer is only needed by the Desktop app client to be aware that the user is
 // connected through the global widget.
-const clientStateReducer = (state: clientState = null, action: clientStateAction | callEndAction) => {
+const clientStateReducer = (state: clientState = null, action: clientStateAction | callEndAction | callMovedAction) => {
     switch (action.type) {
     case UNINIT:
         return null;
@@ -140,6 +159,16 @@ const clientStateReducer = (state: clientState = null, action: clientStateAction
         }
         return state;
     }
+    case CALL_MOVED: {
+        const data = action.data as callMovedData;
+        if (state && data.channelID === state.channelID) {
+            return {
+                ...state,
+                channelID: data.newChannelID,
+            };
+        }
+        return state;
+    }
     default:
         return state;
     }
@@ -532,6 +561,46 @@ const liveCaptions = (state: liveCaptionState = {}, action: liveCaptionAction |
     }
 };
 
+export type callChatMessagesState = {
+    [channelID: string]: CallChatMessage[];
+}
+
+type callChatMessageAction = {
+    type: string;
+    data: CallChatMessage & {
+        channelID: string;
+    };
+}
+
+// Messages are kept sorted by creation time as they can be received out of
+// order when coming from different cluster nodes.
+const addChatMessage = (messages: CallChatMessage[] = [], msg: CallChatMessage) => {
+    const idx = messages.findIndex((m) => m.create_at > msg.create_at);
+    if (idx === -1) {
+        return [...messages, msg];
+    }
+    return [...messages.slice(0, idx), msg, ...messages.slice(idx)];
+};
+
+const callChatMessages = (state: callChatMessagesState = {}, action: callChatMessageAction) => {
+    switch (action.type) {
+    case UNINIT:
+        return {};
+    case CALL_CHAT_MESSAGE:
+        return {
+            ...state,
+            [action.data.channel_id]: addChatMessage(state[action.data.channel_id], action.data),
+        };
+    case CALL_END: {
+        const nextState = {...state};
+        delete nextState[action.data.channelID];
+        return nextState;
+    }
+    default:
+        return state;
+    }
+};
+
 export type callsJobState = {
     [callID: string]: CallJobState;
 }
@@ -683,6 +752,99 @@ const hosts = (state: hostsState = {}, action: hostsStateAction) => {
     }
 };
 
+export type callsCapacityState = {
+    [channelID: string]: boolean;
+}
+
+type callsCapacityAction = {
+    type: string;
+    data: {
+        channelID: string;
+        atCapacity: boolean;
+    };
+}
+
+const callsCapacity = (state: callsCapacityState = {}, action: callsCapacityAction) => {
+    switch (action.type) {
+    case UNINIT:
+        return {};
+    case CALL_CAPACITY:
+        return {
+            ...state,
+            [action.data.channelID]: action.data.atCapacity,
+        };
+    case CALL_END: {
+        const nextState = {...state};
+        delete nextState[action.data.channelID];
+        return nextState;
+    }
+    default:
+        return state;
+    }
+};
+
+export type callsQualityDegradedState = {
+    [channelID: string]: boolean;
+}
+
+type callsQualityDegradedAction = {
+    type: string;
+    data: {
+        channelID: string;
+        degraded: boolean;
+    };
+}
+
+const callsQualityDegraded = (state: callsQualityDegradedState = {}, action: callsQualityDegradedAction) => {
+    switch (action.type) {
+    case UNINIT:
+        return {};
+    case CALL_QUALITY_DEGRADED:
+        return {
+            ...state,
+            [action.data.channelID]: action.data.degraded,
+        };
+    case CALL_END: {
+        const nextState = {...state};
+        delete nextState[action.data.channelID];
+        return nextState;
+    }
+    default:
+        return state;
+    }
+};
+
+export type callsSpeakerLabelsState = {
+    [channelID: string]: boolean;
+}
+
+type callsSpeakerLabelsAction = {
+    type: string;
+    data: {
+        channelID: string;
+        enabled: boolean;
+    };
+}
+
+const callsSpeakerLabels = (state: callsSpeakerLabelsState = {}, action: callsSpeakerLabelsAction) => {
+    switch (action.type) {
+    case UNINIT:
+        return {};
+    case CALL_SPEAKER_LABELS:
+        return {
+            ...state,
+            [action.data.channelID]: action.data.enabled,
+        };
+    case CALL_END: {
+        const nextState = {...state};
+        delete nextState[action.data.channelID];
+        return nextState;
+    }
+    default:
+        return state;
+    }
+};
+
 export type screenSharingIDsState = {
     [channelID: string]: string;
 }
@@ -692,6 +854,7 @@ type screenSharingIDAction = {
     data: {
         channelID: string;
         session_id: string;
+        screen_sharing_session_ids?: string[];
     }
 }
 
@@ -700,6 +863,14 @@ const screenSharingIDs = (state: screenSharingIDsState = {}, action: screenShari
     case UNINIT:
         return {};
     case USER_SCREEN_ON:
+        // When multiple sessions are sharing, the first one to start is
+        // the one presented by default.
+        if (action.data.screen_sharing_session_ids?.length) {
+            return {
+                ...state,
+                [action.data.channelID]: action.data.screen_sharing_session_ids[0],
+            };
+        }
         return {
             ...state,
             [action.data.channelID]: action.data.session_id,
@@ -719,8 +890,50 @@ const screenSharingIDs = (state: screenSharingIDsState = {}, action: screenShari
         }
         return {
             ...state,
-            [action.data.channelID]: '',
+            [action.data.channelID]: action.data.screen_sharing_session_ids?.[0] || '',
+        };
+    default:
+        return state;
+    }
+};
+
+export type screenSharingSessionIDsState = {
+    [channelID: string]: string[];
+}
+
+const screenSharingSessionIDs = (state: screenSharingSessionIDsState = {}, action: screenSharingIDAction) => {
+    switch (action.type) {
+    case UNINIT:
+        return {};
+    case USER_SCREEN_ON:
+    case USER_SCREEN_OFF:
+        if (action.data.screen_sharing_session_ids) {
+            return {
+                ...state,
+                [action.data.channelID]: action.data.screen_sharing_session_ids,
+            };
+        }
+
+        // Older servers only support a single share.
+        return {
+            ...state,
+            [action.data.channelID]: action.type === USER_SCREEN_ON && action.data.session_id ? [action.data.session_id] : [],
+        };
+    case USER_LEFT: {
+        const ids = state[action.data.channelID];
+        if (!ids?.includes(action.data.session_id)) {
+            return state;
+        }
+        return {
+            ...state,
+            [action.data.channelID]: ids.filter((id) => id !== action.data.session_id),
         };
+    }
+    case CALL_END: {
+        const nextState = {...state};
+        delete nextState[action.data.channelID];
+        return nextState;
+    }
     default:
         return state;
     }
@@ -821,6 +1034,15 @@ const callsUserPreferences = (state = CallsUserPreferencesDefault, action: { typ
     }
 };
 
+const callNotificationPreferences = (state = CallNotificationPreferencesDefault, action: { type: string, data: CallNotificationPreferences }) => {
+    switch (action.type) {
+    case RECEIVED_CALL_NOTIFICATION_PREFERENCES:
+        return action.data;
+    default:
+        return state;
+    }
+};
+
 export type recentlyJoinedUsersState = {
     [channelID: string]: string[];
 }
@@ -1019,7 +1241,11 @@ export default combineReducers({
     sessions,
     calls,
     hosts,
+    callsCapacity,
+    callsQualityDegraded,
+    callsSpeakerLabels,
     screenSharingIDs,
+    screenSharingSessionIDs,
     expandedView,
     switchCallModal,
     screenSourceModal,
@@ -1028,6 +1254,7 @@ export default combineReducers({
     callsVersionInfo,
     rtcdEnabled,
     callsUserPreferences,
+    callNotificationPreferences,
     recordings,
     callLiveCaptionsState,
     recentlyJoinedUsers,
@@ -1037,6 +1264,7 @@ export default combineReducers({
     didNotifyForCalls,
     dismissedCalls,
     liveCaptions,
+    callChatMessages,
     clientConnecting,
     hostControlNotices,
 });
@@ -28,6 +28,7 @@ import {
 import {displayUsername} from 'mattermost-redux/utils/user_utils';
 import {createSelector} from 'reselect';
 import {
+    callChatMessagesState,
     callsJobState,
     callState,
     hostControlNoticeState,
@@ -35,11 +36,14 @@ import {
     liveCaptionState,
     recentlyJoinedUsersState,
     screenSharingIDsState,
+    screenSharingSessionIDsState,
     sessionsState,
     usersReactionsState,
 } from 'src/reducers';
 import {
+    CallChatMessage,
     CallJobReduxState,
+    CallNotificationPreferences,
     CallsUserPreferences,
     ChannelState,
     HostControlNotice,
@@ -225,6 +229,18 @@ export const liveCaptionsInCurrentCall: (state: GlobalState) => LiveCaptions =
         (liveCaptions, channelID) => liveCaptions[channelID] || {},
     );
 
+const callChatMessagesInCalls = (state: GlobalState): callChatMessagesState => {
+    return pluginState(state).callChatMessages;
+};
+
+export const callChatMessagesInCurrentCall: (state: GlobalState) => CallChatMessage[] =
+    createSelector(
+        'callChatMessagesInCurrentCall',
+        callChatMessagesInCalls,
+        channelIDForCurrentCall,
+        (messages, channelID) => messages[channelID] || [],
+    );
+
 export const callStartAtForCallInChannel = (state: GlobalState, channelID: string): number => {
     return pluginState(state).calls[channelID]?.startAt || 0;
 };
@@ -295,6 +311,16 @@ export const screenSharingSessionForCurrentCall: (state: GlobalState) => UserSes
         (ids, channelID, sessions) => sessions[channelID]?.[ids[channelID]],
     );
 
+const screenSharingSessionIDsForCalls = (state: GlobalState): screenSharingSessionIDsState => {
+    return pluginState(state).screenSharingSessionIDs;
+};
+
+const emptyScreenSharingSessionIDs: string[] = [];
+
+export const screenSharingSessionIDsForCurrentCall = (state: GlobalState): string[] => {
+    return screenSharingSessionIDsForCalls(state)[channelIDForCurrentCall(state)] || emptyScreenSharingSessionIDs;
+};
+
 export const threadIDForCallInChannel = (state: GlobalState, channelID: string) => {
     return pluginState(state).calls[channelID]?.threadID || '';
 };
@@ -377,6 +403,21 @@ export const isRecordingInCurrentCall: (state: GlobalState) => boolean =
         },
     );
 
+export const isRecordingPausedInCurrentCall: (state: GlobalState) => boolean =
+    createSelector(
+        'isRecordingPausedInCurrentCall',
+        recordingsForCalls,
+        channelIDForCurrentCall,
+        (recordings, channelID) => {
+            const recording = recordings[channelID];
+            if (!recording) {
+                return false;
+            }
+
+            return recording.init_at > recording.end_at && Boolean(recording.paused_at);
+        },
+    );
+
 export const incomingCalls = (state: GlobalState): IncomingCallNotification[] =>
     pluginState(state).incomingCalls;
 
@@ -425,16 +466,85 @@ export const callsConfig = (state: GlobalState): CallsConfig =>
 export const iceServers = (state: GlobalState): RTCIceServer[] =>
     callsConfig(state).ICEServersConfigs || [];
 
+// iceTransportPolicy returns the policy clients should gather ICE candidates
+// with. It's set to relay when the server only accepts relay candidates.
+export const iceTransportPolicy = (state: GlobalState): RTCIceTransportPolicy =>
+    (callsConfig(state) as CallsConfig & {ICETransportPolicy?: RTCIceTransportPolicy}).ICETransportPolicy || 'all';
+
 export const defaultEnabled = (state: GlobalState) =>
     callsConfig(state).DefaultEnabled;
 
+const parseTeamIDs = (ids?: string) => (ids || '').split(',').map((id) => id.trim()).filter(Boolean);
+
+type DMGMCallsConfig = {
+    DMCallsDefault?: string;
+    GMCallsDefault?: string;
+    EnableDMRinging?: boolean;
+    EnableGMRinging?: boolean;
+};
+
+const dmgmConfig = (state: GlobalState) => callsConfig(state) as CallsConfig & DMGMCallsConfig;
+
+// defaultEnabledInChannel returns whether calls are enabled in a channel with no
+// explicit setting. The setting of the channel's team, if any, takes precedence
+// over DefaultEnabled. DM/GM channels have their own default instead.
+export const defaultEnabledInChannel = (state: GlobalState, channelId: string) => {
+    const channel = getChannel(state, channelId);
+    let channelTypeDefault;
+    if (channel?.type === 'D') {
+        channelTypeDefault = dmgmConfig(state).DMCallsDefault;
+    } else if (channel?.type === 'G') {
+        channelTypeDefault = dmgmConfig(state).GMCallsDefault;
+    }
+    if (channelTypeDefault === 'enabled') {
+        return true;
+    } else if (channelTypeDefault === 'disabled') {
+        return false;
+    }
+
+    const teamId = channel?.team_id;
+    if (teamId) {
+        const config = callsConfig(state) as CallsConfig & {EnabledTeams?: string; DisabledTeams?: string};
+        if (parseTeamIDs(config.EnabledTeams).includes(teamId)) {
+            return true;
+        }
+        if (parseTeamIDs(config.DisabledTeams).includes(teamId)) {
+            return false;
+        }
+    }
+    return defaultEnabled(state);
+};
+
 export const maxParticipants = (state: GlobalState) =>
     callsConfig(state).MaxCallParticipants;
 
 export const needsTURNCredentials = (state: GlobalState) =>
     callsConfig(state).NeedsTURNCredentials;
 
+// isCallAtCapacity returns whether the call in the given channel is at
+// capacity, as resolved by the server, or undefined if not known.
+export const isCallAtCapacity = (state: GlobalState, channelID: string): boolean | undefined => {
+    return pluginState(state).callsCapacity?.[channelID];
+};
+
+// isCallQualityDegradedForCurrentCall returns whether the quality of the
+// current call is reduced due to server load.
+export const isCallQualityDegradedForCurrentCall = (state: GlobalState): boolean => {
+    return Boolean(pluginState(state).callsQualityDegraded?.[channelIDForCurrentCall(state)]);
+};
+
+// speakerLabelsForCurrentCall returns whether the host enabled the name labels
+// of the active speaker in the recording of the current call.
+export const speakerLabelsForCurrentCall = (state: GlobalState): boolean => {
+    return Boolean(pluginState(state).callsSpeakerLabels?.[channelIDForCurrentCall(state)]);
+};
+
 export const isLimitRestricted = (state: GlobalState): boolean => {
+    const atCapacity = isCallAtCapacity(state, getCurrentChannelId(state));
+    if (atCapacity !== undefined) {
+        return atCapacity;
+    }
+
     const numCurrentUsers = profilesInCallInCurrentChannel(state).length;
     const max = maxParticipants(state);
     return max > 0 && numCurrentUsers >= max;
@@ -461,6 +571,22 @@ export const rtcdEnabled = (state: GlobalState) =>
 export const ringingEnabled = (state: GlobalState) =>
     callsConfig(state).EnableRinging;
 
+// ringingEnabledInChannel returns whether starting a call in the given DM/GM
+// channel rings its members. Older servers don't send the per type settings,
+// in which case ringing applies to both.
+export const ringingEnabledInChannel = (state: GlobalState, channel?: Channel) => {
+    if (!ringingEnabled(state) || !channel) {
+        return false;
+    }
+    const config = dmgmConfig(state);
+    if (channel.type === 'D') {
+        return config.EnableDMRinging !== false;
+    } else if (channel.type === 'G') {
+        return config.EnableGMRinging !== false;
+    }
+    return false;
+};
+
 export const transcribeAPI = (state: GlobalState) =>
     callsConfig(state).TranscribeAPI;
 
@@ -486,7 +612,7 @@ export const callsEnabledInCurrentChannel = (state: GlobalState): boolean => {
     if (callsExplicitlyDisabled(state, channelId)) {
         return false;
     }
-    return callsExplicitlyEnabled(state, channelId) || defaultEnabled(state) || isCurrentUserSystemAdmin(state);
+    return callsExplicitlyEnabled(state, channelId) || defaultEnabledInChannel(state, channelId) || isCurrentUserSystemAdmin(state);
 };
 
 export const endCallModal = (state: GlobalState) => {
@@ -500,7 +626,7 @@ export const hasPermissionsToEnableCalls = (state: GlobalState, channelId: strin
     if (isCurrentUserSystemAdmin(state)) {
         return true;
     }
-    if (!defaultEnabled(state)) {
+    if (!defaultEnabledInChannel(state, channelId)) {
         return false;
     }
 
@@ -567,6 +693,9 @@ export const isCloudTrialNeverStarted = (state: GlobalState): boolean =>
 export const callsUserPreferences = (state: GlobalState): CallsUserPreferences =>
     pluginState(state).callsUserPreferences;
 
+export const callNotificationPreferences = (state: GlobalState): CallNotificationPreferences =>
+    pluginState(state).callNotificationPreferences;
+
 export const shouldPlayJoinUserSound = (state: GlobalState): boolean =>
     profilesInCurrentCall(state).length < callsUserPreferences(state).joinSoundParticipantsThreshold;
 
@@ -585,6 +714,9 @@ export const isAtLeastProfessional = (state: GlobalState): boolean => {
     return enterpriseAdvanced || enterprise || professional || isCloudProfessionalOrEnterpriseorEnterpriseAdvanceOrTrial(state);
 };
 
+export const maxScreenShares = (state: GlobalState): number =>
+    (callsConfig(state) as CallsConfig & {MaxScreenShares?: number}).MaxScreenShares || 1;
+
 export const areHostControlsAllowed = (state: GlobalState): boolean => callsConfig(state).HostControlsAllowed;
 
 export const areGroupCallsAllowed = (state: GlobalState): boolean => callsConfig(state).GroupCallsAllowed;
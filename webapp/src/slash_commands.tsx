@@ -9,6 +9,8 @@ import {ActionResult} from 'mattermost-redux/types/actions';
 import {defineMessage} from 'react-intl';
 import {
     displayGenericErrorModal,
+    pauseCallRecording,
+    resumeCallRecording,
     startCallRecording,
     stopCallRecording,
 } from 'src/actions';
@@ -30,11 +32,12 @@ import {
     hostIDForCallInChannel,
     hostIDForCurrentCall,
     isRecordingInCurrentCall,
+    isRecordingPausedInCurrentCall,
 } from './selectors';
 import {Store} from './types/mattermost-webapp';
 import {getCallsClient, getCallsWindow, getPersistentStorage, isDMChannel, sendDesktopEvent, shouldRenderDesktopWidget} from './utils';
 
-type joinCallFn = (channelId: string, teamId?: string, title?: string, rootId?: string) => void;
+type joinCallFn = (channelId: string, teamId?: string, title?: string, rootId?: string, tag?: string, preset?: string) => void;
 
 export default async function slashCommandsHandler(store: Store, joinCall: joinCallFn, message: string, args: CommandArgs) {
     const fullCmd = message.trim();
@@ -80,8 +83,42 @@ export default async function slashCommandsHandler(store: Store, joinCall: joinC
         }
         if (!connectedID) {
             let title = '';
+            let tag = '';
+            let preset = '';
+            let rootID = args.root_id;
             if (fields.length > 2) {
-                title = fields.slice(2).join(' ');
+                const titleFields = fields.slice(2);
+
+                // The tag is only used when starting a call.
+                const tagIdx = titleFields.indexOf('--tag');
+                if (tagIdx !== -1) {
+                    tag = titleFields[tagIdx + 1] || '';
+                    titleFields.splice(tagIdx, 2);
+                }
+
+                // The preset is only used when starting a call.
+                const presetIdx = titleFields.indexOf('--preset');
+                if (presetIdx !== -1) {
+                    preset = titleFields[presetIdx + 1] || '';
+                    titleFields.splice(presetIdx, 2);
+                }
+
+                // Posts the call in the thread of the given post, either
+                // through its ID or permalink.
+                const threadIdx = titleFields.indexOf('--thread');
+                if (threadIdx !== -1) {
+                    const match = (titleFields[threadIdx + 1] || '').match(/([a-z0-9]{26})\/?$/);
+                    if (!match) {
+                        store.dispatch(displayGenericErrorModal(
+                            defineMessage({defaultMessage: 'Unable to start call'}),
+                            defineMessage({defaultMessage: 'The thread should be given as a post ID or permalink.'}),
+                        ));
+                        return {};
+                    }
+                    rootID = match[1];
+                    titleFields.splice(threadIdx, 2);
+                }
+                title = titleFields.join(' ');
             }
 
             let team_id = args?.team_id;
@@ -90,7 +127,7 @@ export default async function slashCommandsHandler(store: Store, joinCall: joinC
             }
 
             try {
-                await joinCall(args.channel_id, team_id, title, args.root_id);
+                await joinCall(args.channel_id, team_id, title, rootID, tag, preset);
                 return {};
             } catch (e) {
                 let msg = defineMessage({defaultMessage: 'An internal error occurred and prevented you from joining the call. Please try again.'});
@@ -160,6 +197,44 @@ export default async function slashCommandsHandler(store: Store, joinCall: joinC
         }));
 
         return {};
+    case 'ping': {
+        if (fields.length < 3) {
+            break;
+        }
+
+        // The server takes care of messaging the users, here we only start
+        // the call if there isn't one already.
+        if (!connectedID && !channelHasCall(store.getState(), args.channel_id)) {
+            let channel = getChannel(store.getState(), args.channel_id);
+            if (!channel) {
+                const res = await store.dispatch(getChannelAction(args.channel_id)) as ActionResult;
+                channel = res.data;
+            }
+
+            if (!isDMChannel(channel) && !areGroupCallsAllowed(store.getState())) {
+                store.dispatch(displayGenericErrorModal(
+                    defineMessage({defaultMessage: 'Unable to start call'}),
+                    defineMessage({defaultMessage: 'Calls are only available in DM channels.'}),
+                ));
+                return {};
+            }
+
+            try {
+                await joinCall(args.channel_id, args?.team_id || channel?.team_id, '', args.root_id);
+            } catch (e) {
+                let msg = defineMessage({defaultMessage: 'An internal error occurred and prevented you from joining the call. Please try again.'});
+                if (e === DisabledCallsErr) {
+                    msg = defineMessage({defaultMessage: 'Calls are disabled in this channel.'});
+                }
+                store.dispatch(displayGenericErrorModal(
+                    defineMessage({defaultMessage: 'Unable to start call'}),
+                    msg,
+                ));
+                return {};
+            }
+        }
+        break;
+    }
     case 'link':
         break;
     case 'stats': {
@@ -178,16 +253,24 @@ export default async function slashCommandsHandler(store: Store, joinCall: joinC
         return {message: `/call logs ${btoa(getClientLogs())}`, args};
     }
     case 'recording': {
-        if (fields.length < 3 || (fields[2] !== 'start' && fields[2] !== 'stop')) {
+        if (fields.length < 3 || !['start', 'stop', 'pause', 'resume'].includes(fields[2])) {
             break;
         }
 
         const startErrorTitle = defineMessage({defaultMessage: 'Unable to start recording'});
         const stopErrorTitle = defineMessage({defaultMessage: 'Unable to stop recording'});
+        const pauseErrorTitle = defineMessage({defaultMessage: 'Unable to pause recording'});
+        const resumeErrorTitle = defineMessage({defaultMessage: 'Unable to resume recording'});
+        const errorTitles: Record<string, typeof startErrorTitle> = {
+            start: startErrorTitle,
+            stop: stopErrorTitle,
+            pause: pauseErrorTitle,
+            resume: resumeErrorTitle,
+        };
 
         if (args.channel_id !== connectedID) {
             store.dispatch(displayGenericErrorModal(
-                fields[2] === 'start' ? startErrorTitle : stopErrorTitle,
+                errorTitles[fields[2]],
                 defineMessage({defaultMessage: 'You\'re not connected to a call in the current channel.'}),
             ));
             return {};
@@ -213,7 +296,11 @@ export default async function slashCommandsHandler(store: Store, joinCall: joinC
                 return {};
             }
 
-            await store.dispatch(startCallRecording(connectedID));
+            // The layout defaults to the one configured by the admin.
+            const layoutIdx = fields.indexOf('--layout');
+            const layout = layoutIdx === -1 ? '' : fields[layoutIdx + 1];
+
+            await store.dispatch(startCallRecording(connectedID, layout));
         }
 
         if (fields[2] === 'stop') {
@@ -235,6 +322,50 @@ export default async function slashCommandsHandler(store: Store, joinCall: joinC
 
             await stopCallRecording(connectedID);
         }
+
+        if (fields[2] === 'pause' || fields[2] === 'resume') {
+            const errorTitle = errorTitles[fields[2]];
+
+            if (!isHost) {
+                store.dispatch(displayGenericErrorModal(
+                    errorTitle,
+                    defineMessage({defaultMessage: 'You don\'t have permission to pause or resume the recording. Please ask the call host to do it.'}),
+                ));
+                return {};
+            }
+
+            if (!isRecordingInCurrentCall(state)) {
+                store.dispatch(displayGenericErrorModal(
+                    errorTitle,
+                    defineMessage({defaultMessage: 'No recording is in progress.'}),
+                ));
+                return {};
+            }
+
+            const isPaused = isRecordingPausedInCurrentCall(state);
+
+            if (fields[2] === 'pause' && isPaused) {
+                store.dispatch(displayGenericErrorModal(
+                    errorTitle,
+                    defineMessage({defaultMessage: 'The recording is already paused.'}),
+                ));
+                return {};
+            }
+
+            if (fields[2] === 'resume' && !isPaused) {
+                store.dispatch(displayGenericErrorModal(
+                    errorTitle,
+                    defineMessage({defaultMessage: 'The recording is not paused.'}),
+                ));
+                return {};
+            }
+
+            if (fields[2] === 'pause') {
+                await pauseCallRecording(connectedID);
+            } else {
+                await resumeCallRecording(connectedID);
+            }
+        }
         break;
     }
     }
@@ -25,6 +25,13 @@ export const CallsConfigDefault: CallsConfig = {
     EnableDCSignaling: false,
 };
 
+// Capacity of a call as resolved by the server. Included in the call state
+// and in user joined/left events.
+export type CallCapacityData = {
+    max_participants?: number;
+    at_capacity?: boolean;
+}
+
 export type ChannelState = {
     id: string;
     enabled?: boolean;
@@ -34,6 +41,7 @@ export type CallsClientConfig = {
     wsURL: string;
     authToken?: string;
     iceServers: RTCIceServer[];
+    iceTransportPolicy?: RTCIceTransportPolicy;
     simulcast?: boolean;
     enableAV1: boolean;
     dcSignaling: boolean;
@@ -69,6 +77,45 @@ export const CallsUserPreferencesDefault = {
     joinSoundParticipantsThreshold: 8,
 };
 
+// Sent when the quality of the call needs to be reduced (or can be restored)
+// because the node hosting it is under CPU pressure.
+export type CallQualityDegradedData = {
+    call_id: string;
+    channel_id: string;
+    degraded: boolean;
+}
+
+// Sent when the host toggles the name labels of the active speaker in the
+// call recording.
+export type CallSpeakerLabelsData = {
+    call_id: string;
+    channel_id: string;
+    enabled: boolean;
+}
+
+// Sent to a participant muted for being noisy.
+export type NoiseMuteData = {
+    call_id: string;
+    channel_id: string;
+    session_id: string;
+}
+
+export type CallNotificationPreferences = {
+    mode: 'ring' | 'silent';
+    channels: 'all' | 'direct' | 'none';
+    muted_channel_ids: string[];
+    focus_mode: boolean;
+    mute_recording_notifications: boolean;
+}
+
+export const CallNotificationPreferencesDefault: CallNotificationPreferences = {
+    mode: 'ring',
+    channels: 'all',
+    muted_channel_ids: [],
+    focus_mode: false,
+    mute_recording_notifications: false,
+};
+
 export enum CallAlertType {
     Error = 'error',
     Warning = 'warning',
@@ -119,14 +166,23 @@ export const CallAlertStatesDefault = {
         active: false,
         show: false,
     },
+    serverLoad: {
+        active: false,
+        show: false,
+    },
 };
 
+export type RecordingLayout = 'grid' | 'active_speaker';
+
 export type CallJobReduxState = {
     init_at: number;
     start_at: number;
     end_at: number;
+    paused_at?: number;
     err?: string;
     error_at?: number;
+    layout?: RecordingLayout;
+    watermark_text?: string;
     prompt_dismissed_at?: number;
 }
 
@@ -176,6 +232,7 @@ export enum HostControlNoticeType {
     LowerHand,
     HostChanged,
     HostRemoved,
+    NoiseMuted,
 }
 
 export type HostControlNotice = {
@@ -191,6 +248,52 @@ export type HostControlNoticeTimeout = {
     noticeID: string;
 }
 
+export type CallEndReason =
+    | 'host-ended'
+    | 'admin-ended'
+    | 'all-left'
+    | 'empty-timeout'
+    | 'idle-timeout'
+    | 'max-duration'
+    | 'error'
+    | 'node-failure'
+    | 'moved';
+
+export type CallEndData = {
+    channelID?: string;
+    call_id: string;
+    reason?: CallEndReason;
+}
+
+export type CallMovedData = {
+    call_id: string;
+    channel_id: string;
+    new_channel_id: string;
+    new_post_id: string;
+    new_thread_id: string;
+    removed_session_ids: string[];
+}
+
+export type SessionReplacedData = {
+    call_id: string;
+    channel_id: string;
+    session_id: string;
+}
+
+export type CallChatMessageData = {
+    call_id: string;
+    channel_id: string;
+    user_id: string;
+    session_id: string;
+    message: string;
+    create_at: number;
+    post_id: string;
+}
+
+export type CallChatMessage = CallChatMessageData & {
+    display_name: string;
+}
+
 export type RemoveConfirmationData = {
     sessionID: string;
     userID: string;
@@ -8,7 +8,6 @@ import {
     CallStartData,
     CallState,
     CallStateData,
-    EmptyData,
     HostControlLowerHand,
     HostControlMsg,
     HostControlRemoved,
@@ -38,7 +37,7 @@ import {
     userLeft,
 } from 'src/actions';
 import {userLeftChannelErr, userRemovedFromChannelErr} from 'src/client';
-import {hostRemovedMsg} from 'src/components/call_error_modal';
+import {channelAccessLostMsg, hostRemovedMsg, sessionReplacedMsg} from 'src/components/call_error_modal';
 import {
     HOST_CONTROL_NOTICE_TIMEOUT,
     JOB_TYPE_CAPTIONING,
@@ -48,20 +47,35 @@ import {
     REACTION_TIMEOUT_IN_REACTION_STREAM,
 } from 'src/constants';
 import {
+    CallCapacityData,
+    CallChatMessageData,
+    CallEndData,
+    CallMovedData,
+    CallNotificationPreferences,
+    CallQualityDegradedData,
+    CallSpeakerLabelsData,
     HostControlNotice,
     HostControlNoticeType,
+    NoiseMuteData,
+    SessionReplacedData,
 } from 'src/types/types';
 
 import {
+    CALL_CAPACITY,
+    CALL_CHAT_MESSAGE,
     CALL_HOST,
     CALL_LIVE_CAPTIONS_STATE,
+    CALL_MOVED,
+    CALL_QUALITY_DEGRADED,
     CALL_RECORDING_STATE,
+    CALL_SPEAKER_LABELS,
     CALL_STATE,
     DISMISS_CALL,
     HOST_CONTROL_NOTICE,
     HOST_CONTROL_NOTICE_TIMEOUT_EVENT,
     LIVE_CAPTION,
     LIVE_CAPTION_TIMEOUT_EVENT,
+    RECEIVED_CALL_NOTIFICATION_PREFERENCES,
     USER_JOINED,
     USER_JOINED_TIMEOUT,
     USER_LOWER_HAND,
@@ -95,16 +109,16 @@ import {
 
 // NOTE: it's important this function is kept synchronous in order to guarantee the order of
 // state mutating operations.
-export function handleCallEnd(store: Store, ev: WebSocketMessage<EmptyData>) {
+export function handleCallEnd(store: Store, ev: WebSocketMessage<CallEndData>) {
     const channelID = ev.data.channelID || ev.broadcast.channel_id;
-    store.dispatch(callEnd(channelID));
+    store.dispatch(callEnd(channelID, ev.data.reason));
 }
 
 // NOTE: it's important this function is kept synchronous in order to guarantee the order of
 // state mutating operations.
 export function handleCallState(store: Store, ev: WebSocketMessage<CallStateData>) {
     try {
-        const call: CallState = JSON.parse(ev.data.call);
+        const call: CallState & CallCapacityData = JSON.parse(ev.data.call);
         store.dispatch(loadCallState(ev.data.channel_id, call));
     } catch (err) {
         logErr(err);
@@ -163,17 +177,35 @@ export function handleCallStart(store: Store, ev: WebSocketMessage<CallStartData
     }
 }
 
+// Events from older servers don't include the capacity, in which case the
+// client falls back to checking the participant limit itself.
+function handleCallCapacity(store: Store, channelID: string, data: CallCapacityData) {
+    if (data.at_capacity === undefined) {
+        return;
+    }
+
+    store.dispatch({
+        type: CALL_CAPACITY,
+        data: {
+            channelID,
+            atCapacity: data.at_capacity,
+        },
+    });
+}
+
 // NOTE: it's important this function is kept synchronous in order to guarantee the order of
 // state mutating operations.
-export function handleUserLeft(store: Store, ev: WebSocketMessage<UserLeftData>) {
+export function handleUserLeft(store: Store, ev: WebSocketMessage<UserLeftData & CallCapacityData>) {
     const channelID = ev.data.channelID || ev.broadcast.channel_id;
 
     store.dispatch(userLeft(channelID, ev.data.user_id, ev.data.session_id));
+
+    handleCallCapacity(store, channelID, ev.data);
 }
 
 // NOTE: it's important this function is kept synchronous in order to guarantee the order of
 // state mutating operations.
-export function handleUserJoined(store: Store, ev: WebSocketMessage<UserJoinedData>) {
+export function handleUserJoined(store: Store, ev: WebSocketMessage<UserJoinedData & CallCapacityData>) {
     const userID = ev.data.user_id;
     const channelID = ev.data.channelID || ev.broadcast.channel_id;
     const currentUserID = getCurrentUserId(store.getState());
@@ -207,6 +239,8 @@ export function handleUserJoined(store: Store, ev: WebSocketMessage<UserJoinedDa
         },
     });
 
+    handleCallCapacity(store, channelID, ev.data);
+
     setTimeout(() => {
         store.dispatch({
             type: USER_JOINED_TIMEOUT,
@@ -272,7 +306,7 @@ export function handleUserVoiceOff(store: Store, ev: WebSocketMessage<UserVoiceO
 
 // NOTE: it's important this function is kept synchronous in order to guarantee the order of
 // state mutating operations.
-export function handleUserScreenOn(store: Store, ev: WebSocketMessage<UserScreenOnOffData>) {
+export function handleUserScreenOn(store: Store, ev: WebSocketMessage<UserScreenOnOffData & {screen_sharing_session_ids?: string[]}>) {
     const channelID = ev.data.channelID || ev.broadcast.channel_id;
     store.dispatch({
         type: USER_SCREEN_ON,
@@ -280,13 +314,14 @@ export function handleUserScreenOn(store: Store, ev: WebSocketMessage<UserScreen
             channelID,
             userID: ev.data.userID,
             session_id: ev.data.session_id,
+            screen_sharing_session_ids: ev.data.screen_sharing_session_ids,
         },
     });
 }
 
 // NOTE: it's important this function is kept synchronous in order to guarantee the order of
 // state mutating operations.
-export function handleUserScreenOff(store: Store, ev: WebSocketMessage<UserScreenOnOffData>) {
+export function handleUserScreenOff(store: Store, ev: WebSocketMessage<UserScreenOnOffData & {screen_sharing_session_ids?: string[]}>) {
     const channelID = ev.data.channelID || ev.broadcast.channel_id;
     store.dispatch({
         type: USER_SCREEN_OFF,
@@ -294,6 +329,7 @@ export function handleUserScreenOff(store: Store, ev: WebSocketMessage<UserScree
             channelID,
             userID: ev.data.userID,
             session_id: ev.data.session_id,
+            screen_sharing_session_ids: ev.data.screen_sharing_session_ids,
         },
     });
 }
@@ -506,6 +542,39 @@ export function handleHostMute(store: Store, ev: WebSocketMessage<HostControlMsg
     client.mute();
 }
 
+// handleNoiseMute mutes the client, as detected as noisy by the server, and
+// lets the user know why.
+export function handleNoiseMute(store: Store, ev: WebSocketMessage<NoiseMuteData>) {
+    const client = getCallsClient();
+    if (!client || client.channelID !== ev.data.channel_id || ev.data.session_id !== client.getSessionID()) {
+        return;
+    }
+
+    handleHostMute(store, ev);
+
+    const notice: HostControlNotice = {
+        type: HostControlNoticeType.NoiseMuted,
+        callID: ev.data.call_id,
+        noticeID: generateId(),
+        displayName: '',
+    };
+
+    store.dispatch({
+        type: HOST_CONTROL_NOTICE,
+        data: notice,
+    });
+
+    setTimeout(() => {
+        store.dispatch({
+            type: HOST_CONTROL_NOTICE_TIMEOUT_EVENT,
+            data: {
+                callID: ev.data.call_id,
+                noticeID: notice.noticeID,
+            },
+        });
+    }, HOST_CONTROL_NOTICE_TIMEOUT);
+}
+
 export function handleHostScreenOff(store: Store, ev: WebSocketMessage<HostControlMsg>) {
     const channelID = ev.data.channel_id;
     const client = getCallsClient();
@@ -566,7 +635,99 @@ export function handleHostLowerHand(store: Store, ev: WebSocketMessage<HostContr
     }, HOST_CONTROL_NOTICE_TIMEOUT);
 }
 
-export function handleHostRemoved(store: Store, ev: WebSocketMessage<HostControlRemoved>) {
+export function handleCallChatMessage(store: Store, ev: WebSocketMessage<CallChatMessageData>) {
+    const channel_id = ev.data.channel_id;
+
+    if (channelIDForCurrentCall(store.getState()) !== channel_id) {
+        return;
+    }
+
+    const profiles = profilesInCurrentCallMap(store.getState());
+    store.dispatch({
+        type: CALL_CHAT_MESSAGE,
+        data: {
+            ...ev.data,
+            display_name: getUserDisplayName(profiles[ev.data.user_id]),
+        },
+    });
+}
+
+export function handleCallNotificationPreferences(store: Store, ev: WebSocketMessage<CallNotificationPreferences>) {
+    store.dispatch({
+        type: RECEIVED_CALL_NOTIFICATION_PREFERENCES,
+        data: ev.data,
+    });
+}
+
+export function handleCallQualityDegraded(store: Store, ev: WebSocketMessage<CallQualityDegradedData>) {
+    store.dispatch({
+        type: CALL_QUALITY_DEGRADED,
+        data: {
+            channelID: ev.data.channel_id,
+            degraded: ev.data.degraded,
+        },
+    });
+
+    const client = getCallsClient();
+    if (client?.channelID === ev.data.channel_id) {
+        client.setQualityDegraded(ev.data.degraded);
+    }
+}
+
+export function handleCallSpeakerLabels(store: Store, ev: WebSocketMessage<CallSpeakerLabelsData>) {
+    store.dispatch({
+        type: CALL_SPEAKER_LABELS,
+        data: {
+            channelID: ev.data.channel_id,
+            enabled: ev.data.enabled,
+        },
+    });
+}
+
+// NOTE: it's important this function is kept synchronous in order to guarantee the order of
+// state mutating operations.
+export function handleCallMoved(store: Store, ev: WebSocketMessage<CallMovedData>) {
+    const client = getCallsClient();
+    if (!client || client.channelID !== ev.data.channel_id) {
+        return;
+    }
+
+    // Removed participants get disconnected through the host_removed event.
+    if (ev.data.removed_session_ids?.includes(client.getSessionID())) {
+        return;
+    }
+
+    // The media connection is tied to the call rather than the channel so we only need
+    // to follow it. This needs to happen before the call_end event for the previous
+    // channel comes in, so that we don't disconnect.
+    client.channelID = ev.data.new_channel_id;
+    store.dispatch({
+        type: CALL_MOVED,
+        data: {
+            channelID: ev.data.channel_id,
+            newChannelID: ev.data.new_channel_id,
+        },
+    });
+
+    const channel = getChannel(store.getState(), ev.data.new_channel_id);
+    if (channel) {
+        followThread(store, channel.id, channel.team_id);
+    }
+}
+
+export function handleSessionReplaced(ev: WebSocketMessage<SessionReplacedData>) {
+    const client = getCallsClient();
+    if (!client || client?.channelID !== ev.data.channel_id) {
+        return;
+    }
+
+    // The user joined the call from another device.
+    if (ev.data.session_id === client.getSessionID()) {
+        client.disconnect(new Error(sessionReplacedMsg));
+    }
+}
+
+export function handleHostRemoved(store: Store, ev: WebSocketMessage<HostControlRemoved & {reason?: string}>) {
     const channelID = ev.data.channel_id;
     const client = getCallsClient();
     if (!client || client?.channelID !== channelID) {
@@ -575,7 +736,9 @@ export function handleHostRemoved(store: Store, ev: WebSocketMessage<HostControl
 
     const sessionID = client.getSessionID();
     if (ev.data.session_id === sessionID) {
-        getCallsClient()?.disconnect(new Error(hostRemovedMsg));
+        // Participants are also removed when they lose access to the channel.
+        const errMsg = ev.data.reason === 'channel_access_lost' ? channelAccessLostMsg : hostRemovedMsg;
+        getCallsClient()?.disconnect(new Error(errMsg));
         return;
     }
 